@@ -0,0 +1,6 @@
+// Package pomodorov1 is the generated Go code for the gRPC control API
+// in pomodoro.proto. Clients in other languages generate theirs from the
+// same file.
+package pomodorov1
+
+//go:generate buf generate ../.. --template ../../buf.gen.yaml -o ../..
@@ -0,0 +1,1166 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        (unknown)
+// source: gopomodoro/v1/pomodoro.proto
+
+// The GoPomodoro control API, served by "gopomodoro daemon -grpc". It
+// mirrors the HTTP API: every call returns the state after it, and Watch
+// streams engine events for live displays.
+
+package pomodorov1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Phase int32
+
+const (
+	Phase_PHASE_UNSPECIFIED Phase = 0
+	Phase_PHASE_WORK        Phase = 1
+	Phase_PHASE_SHORT_BREAK Phase = 2
+	Phase_PHASE_LONG_BREAK  Phase = 3
+)
+
+// Enum value maps for Phase.
+var (
+	Phase_name = map[int32]string{
+		0: "PHASE_UNSPECIFIED",
+		1: "PHASE_WORK",
+		2: "PHASE_SHORT_BREAK",
+		3: "PHASE_LONG_BREAK",
+	}
+	Phase_value = map[string]int32{
+		"PHASE_UNSPECIFIED": 0,
+		"PHASE_WORK":        1,
+		"PHASE_SHORT_BREAK": 2,
+		"PHASE_LONG_BREAK":  3,
+	}
+)
+
+func (x Phase) Enum() *Phase {
+	p := new(Phase)
+	*p = x
+	return p
+}
+
+func (x Phase) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Phase) Descriptor() protoreflect.EnumDescriptor {
+	return file_gopomodoro_v1_pomodoro_proto_enumTypes[0].Descriptor()
+}
+
+func (Phase) Type() protoreflect.EnumType {
+	return &file_gopomodoro_v1_pomodoro_proto_enumTypes[0]
+}
+
+func (x Phase) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Phase.Descriptor instead.
+func (Phase) EnumDescriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{0}
+}
+
+type PauseReason int32
+
+const (
+	PauseReason_PAUSE_REASON_UNSPECIFIED  PauseReason = 0
+	PauseReason_PAUSE_REASON_MEETING      PauseReason = 1
+	PauseReason_PAUSE_REASON_BIO          PauseReason = 2
+	PauseReason_PAUSE_REASON_INTERRUPTION PauseReason = 3
+	PauseReason_PAUSE_REASON_OTHER        PauseReason = 4
+	// PAUSE_REASON_IDLE is set by automatic pauses; Pause rejects it.
+	PauseReason_PAUSE_REASON_IDLE PauseReason = 5
+)
+
+// Enum value maps for PauseReason.
+var (
+	PauseReason_name = map[int32]string{
+		0: "PAUSE_REASON_UNSPECIFIED",
+		1: "PAUSE_REASON_MEETING",
+		2: "PAUSE_REASON_BIO",
+		3: "PAUSE_REASON_INTERRUPTION",
+		4: "PAUSE_REASON_OTHER",
+		5: "PAUSE_REASON_IDLE",
+	}
+	PauseReason_value = map[string]int32{
+		"PAUSE_REASON_UNSPECIFIED":  0,
+		"PAUSE_REASON_MEETING":      1,
+		"PAUSE_REASON_BIO":          2,
+		"PAUSE_REASON_INTERRUPTION": 3,
+		"PAUSE_REASON_OTHER":        4,
+		"PAUSE_REASON_IDLE":         5,
+	}
+)
+
+func (x PauseReason) Enum() *PauseReason {
+	p := new(PauseReason)
+	*p = x
+	return p
+}
+
+func (x PauseReason) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PauseReason) Descriptor() protoreflect.EnumDescriptor {
+	return file_gopomodoro_v1_pomodoro_proto_enumTypes[1].Descriptor()
+}
+
+func (PauseReason) Type() protoreflect.EnumType {
+	return &file_gopomodoro_v1_pomodoro_proto_enumTypes[1]
+}
+
+func (x PauseReason) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PauseReason.Descriptor instead.
+func (PauseReason) EnumDescriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{1}
+}
+
+type EventKind int32
+
+const (
+	EventKind_EVENT_KIND_UNSPECIFIED EventKind = 0
+	// EVENT_KIND_STATE is the first message of a Watch stream.
+	EventKind_EVENT_KIND_STATE           EventKind = 1
+	EventKind_EVENT_KIND_TICK            EventKind = 2
+	EventKind_EVENT_KIND_START           EventKind = 3
+	EventKind_EVENT_KIND_ADVANCE         EventKind = 4
+	EventKind_EVENT_KIND_PAUSE           EventKind = 5
+	EventKind_EVENT_KIND_RESUME          EventKind = 6
+	EventKind_EVENT_KIND_STOP            EventKind = 7
+	EventKind_EVENT_KIND_UPDATE          EventKind = 8
+	EventKind_EVENT_KIND_INTERRUPT       EventKind = 9
+	EventKind_EVENT_KIND_OVERTIME        EventKind = 10
+	EventKind_EVENT_KIND_SKIP            EventKind = 11
+	EventKind_EVENT_KIND_WARNING         EventKind = 12
+	EventKind_EVENT_KIND_EXTEND          EventKind = 13
+	EventKind_EVENT_KIND_ABANDON         EventKind = 14
+	EventKind_EVENT_KIND_TASK            EventKind = 15
+	EventKind_EVENT_KIND_REFUSED         EventKind = 16
+	EventKind_EVENT_KIND_CONFIG_RELOADED EventKind = 17
+	EventKind_EVENT_KIND_UNDO            EventKind = 18
+)
+
+// Enum value maps for EventKind.
+var (
+	EventKind_name = map[int32]string{
+		0:  "EVENT_KIND_UNSPECIFIED",
+		1:  "EVENT_KIND_STATE",
+		2:  "EVENT_KIND_TICK",
+		3:  "EVENT_KIND_START",
+		4:  "EVENT_KIND_ADVANCE",
+		5:  "EVENT_KIND_PAUSE",
+		6:  "EVENT_KIND_RESUME",
+		7:  "EVENT_KIND_STOP",
+		8:  "EVENT_KIND_UPDATE",
+		9:  "EVENT_KIND_INTERRUPT",
+		10: "EVENT_KIND_OVERTIME",
+		11: "EVENT_KIND_SKIP",
+		12: "EVENT_KIND_WARNING",
+		13: "EVENT_KIND_EXTEND",
+		14: "EVENT_KIND_ABANDON",
+		15: "EVENT_KIND_TASK",
+		16: "EVENT_KIND_REFUSED",
+		17: "EVENT_KIND_CONFIG_RELOADED",
+		18: "EVENT_KIND_UNDO",
+	}
+	EventKind_value = map[string]int32{
+		"EVENT_KIND_UNSPECIFIED":     0,
+		"EVENT_KIND_STATE":           1,
+		"EVENT_KIND_TICK":            2,
+		"EVENT_KIND_START":           3,
+		"EVENT_KIND_ADVANCE":         4,
+		"EVENT_KIND_PAUSE":           5,
+		"EVENT_KIND_RESUME":          6,
+		"EVENT_KIND_STOP":            7,
+		"EVENT_KIND_UPDATE":          8,
+		"EVENT_KIND_INTERRUPT":       9,
+		"EVENT_KIND_OVERTIME":        10,
+		"EVENT_KIND_SKIP":            11,
+		"EVENT_KIND_WARNING":         12,
+		"EVENT_KIND_EXTEND":          13,
+		"EVENT_KIND_ABANDON":         14,
+		"EVENT_KIND_TASK":            15,
+		"EVENT_KIND_REFUSED":         16,
+		"EVENT_KIND_CONFIG_RELOADED": 17,
+		"EVENT_KIND_UNDO":            18,
+	}
+)
+
+func (x EventKind) Enum() *EventKind {
+	p := new(EventKind)
+	*p = x
+	return p
+}
+
+func (x EventKind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (EventKind) Descriptor() protoreflect.EnumDescriptor {
+	return file_gopomodoro_v1_pomodoro_proto_enumTypes[2].Descriptor()
+}
+
+func (EventKind) Type() protoreflect.EnumType {
+	return &file_gopomodoro_v1_pomodoro_proto_enumTypes[2]
+}
+
+func (x EventKind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use EventKind.Descriptor instead.
+func (EventKind) EnumDescriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{2}
+}
+
+type GetStateRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStateRequest) Reset() {
+	*x = GetStateRequest{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateRequest) ProtoMessage() {}
+
+func (x *GetStateRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateRequest.ProtoReflect.Descriptor instead.
+func (*GetStateRequest) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{0}
+}
+
+type GetStateResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *State                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetStateResponse) Reset() {
+	*x = GetStateResponse{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetStateResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetStateResponse) ProtoMessage() {}
+
+func (x *GetStateResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetStateResponse.ProtoReflect.Descriptor instead.
+func (*GetStateResponse) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetStateResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type StartRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartRequest) Reset() {
+	*x = StartRequest{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartRequest) ProtoMessage() {}
+
+func (x *StartRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartRequest.ProtoReflect.Descriptor instead.
+func (*StartRequest) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{2}
+}
+
+type StartResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *State                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StartResponse) Reset() {
+	*x = StartResponse{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StartResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StartResponse) ProtoMessage() {}
+
+func (x *StartResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StartResponse.ProtoReflect.Descriptor instead.
+func (*StartResponse) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *StartResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type PauseRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Reason        PauseReason            `protobuf:"varint,1,opt,name=reason,proto3,enum=gopomodoro.v1.PauseReason" json:"reason,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseRequest) Reset() {
+	*x = PauseRequest{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseRequest) ProtoMessage() {}
+
+func (x *PauseRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseRequest.ProtoReflect.Descriptor instead.
+func (*PauseRequest) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *PauseRequest) GetReason() PauseReason {
+	if x != nil {
+		return x.Reason
+	}
+	return PauseReason_PAUSE_REASON_UNSPECIFIED
+}
+
+type PauseResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *State                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PauseResponse) Reset() {
+	*x = PauseResponse{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PauseResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PauseResponse) ProtoMessage() {}
+
+func (x *PauseResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PauseResponse.ProtoReflect.Descriptor instead.
+func (*PauseResponse) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *PauseResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type ResumeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeRequest) Reset() {
+	*x = ResumeRequest{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeRequest) ProtoMessage() {}
+
+func (x *ResumeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeRequest.ProtoReflect.Descriptor instead.
+func (*ResumeRequest) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{6}
+}
+
+type ResumeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *State                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResumeResponse) Reset() {
+	*x = ResumeResponse{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResumeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResumeResponse) ProtoMessage() {}
+
+func (x *ResumeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResumeResponse.ProtoReflect.Descriptor instead.
+func (*ResumeResponse) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ResumeResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type StopRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopRequest) Reset() {
+	*x = StopRequest{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopRequest) ProtoMessage() {}
+
+func (x *StopRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopRequest.ProtoReflect.Descriptor instead.
+func (*StopRequest) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{8}
+}
+
+type StopResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *State                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StopResponse) Reset() {
+	*x = StopResponse{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StopResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StopResponse) ProtoMessage() {}
+
+func (x *StopResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StopResponse.ProtoReflect.Descriptor instead.
+func (*StopResponse) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *StopResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type SkipRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SkipRequest) Reset() {
+	*x = SkipRequest{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkipRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkipRequest) ProtoMessage() {}
+
+func (x *SkipRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkipRequest.ProtoReflect.Descriptor instead.
+func (*SkipRequest) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{10}
+}
+
+type SkipResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	State         *State                 `protobuf:"bytes,1,opt,name=state,proto3" json:"state,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SkipResponse) Reset() {
+	*x = SkipResponse{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SkipResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SkipResponse) ProtoMessage() {}
+
+func (x *SkipResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SkipResponse.ProtoReflect.Descriptor instead.
+func (*SkipResponse) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *SkipResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// tick_interval adds EVENT_KIND_TICK messages this often while a phase
+	// runs; unset or zero sends none.
+	TickInterval  *durationpb.Duration `protobuf:"bytes,1,opt,name=tick_interval,json=tickInterval,proto3" json:"tick_interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *WatchRequest) GetTickInterval() *durationpb.Duration {
+	if x != nil {
+		return x.TickInterval
+	}
+	return nil
+}
+
+// WatchResponse is one engine event.
+type WatchResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Kind  EventKind              `protobuf:"varint,1,opt,name=kind,proto3,enum=gopomodoro.v1.EventKind" json:"kind,omitempty"`
+	At    *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=at,proto3" json:"at,omitempty"`
+	State *State                 `protobuf:"bytes,3,opt,name=state,proto3" json:"state,omitempty"`
+	// message explains EVENT_KIND_REFUSED, and names the action
+	// EVENT_KIND_UNDO took back: skip, stop or extend.
+	Message       string `protobuf:"bytes,4,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchResponse) Reset() {
+	*x = WatchResponse{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchResponse) ProtoMessage() {}
+
+func (x *WatchResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchResponse.ProtoReflect.Descriptor instead.
+func (*WatchResponse) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchResponse) GetKind() EventKind {
+	if x != nil {
+		return x.Kind
+	}
+	return EventKind_EVENT_KIND_UNSPECIFIED
+}
+
+func (x *WatchResponse) GetAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.At
+	}
+	return nil
+}
+
+func (x *WatchResponse) GetState() *State {
+	if x != nil {
+		return x.State
+	}
+	return nil
+}
+
+func (x *WatchResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// State is an engine snapshot.
+type State struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Phase Phase                  `protobuf:"varint,1,opt,name=phase,proto3,enum=gopomodoro.v1.Phase" json:"phase,omitempty"`
+	// name is the phase or custom cycle step name, e.g. "WORK".
+	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	// started_at and ends_at are unset while idle.
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	EndsAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=ends_at,json=endsAt,proto3" json:"ends_at,omitempty"`
+	Remaining     *durationpb.Duration   `protobuf:"bytes,5,opt,name=remaining,proto3" json:"remaining,omitempty"`
+	PomodoroDone  int32                  `protobuf:"varint,6,opt,name=pomodoro_done,json=pomodoroDone,proto3" json:"pomodoro_done,omitempty"`
+	Paused        bool                   `protobuf:"varint,7,opt,name=paused,proto3" json:"paused,omitempty"`
+	PauseReason   PauseReason            `protobuf:"varint,8,opt,name=pause_reason,json=pauseReason,proto3,enum=gopomodoro.v1.PauseReason" json:"pause_reason,omitempty"`
+	Interruptions int32                  `protobuf:"varint,9,opt,name=interruptions,proto3" json:"interruptions,omitempty"`
+	Overtime      bool                   `protobuf:"varint,10,opt,name=overtime,proto3" json:"overtime,omitempty"`
+	Idle          bool                   `protobuf:"varint,11,opt,name=idle,proto3" json:"idle,omitempty"`
+	// task is the title of the attached task, if any.
+	Task          string `protobuf:"bytes,12,opt,name=task,proto3" json:"task,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *State) Reset() {
+	*x = State{}
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *State) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*State) ProtoMessage() {}
+
+func (x *State) ProtoReflect() protoreflect.Message {
+	mi := &file_gopomodoro_v1_pomodoro_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use State.ProtoReflect.Descriptor instead.
+func (*State) Descriptor() ([]byte, []int) {
+	return file_gopomodoro_v1_pomodoro_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *State) GetPhase() Phase {
+	if x != nil {
+		return x.Phase
+	}
+	return Phase_PHASE_UNSPECIFIED
+}
+
+func (x *State) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *State) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *State) GetEndsAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndsAt
+	}
+	return nil
+}
+
+func (x *State) GetRemaining() *durationpb.Duration {
+	if x != nil {
+		return x.Remaining
+	}
+	return nil
+}
+
+func (x *State) GetPomodoroDone() int32 {
+	if x != nil {
+		return x.PomodoroDone
+	}
+	return 0
+}
+
+func (x *State) GetPaused() bool {
+	if x != nil {
+		return x.Paused
+	}
+	return false
+}
+
+func (x *State) GetPauseReason() PauseReason {
+	if x != nil {
+		return x.PauseReason
+	}
+	return PauseReason_PAUSE_REASON_UNSPECIFIED
+}
+
+func (x *State) GetInterruptions() int32 {
+	if x != nil {
+		return x.Interruptions
+	}
+	return 0
+}
+
+func (x *State) GetOvertime() bool {
+	if x != nil {
+		return x.Overtime
+	}
+	return false
+}
+
+func (x *State) GetIdle() bool {
+	if x != nil {
+		return x.Idle
+	}
+	return false
+}
+
+func (x *State) GetTask() string {
+	if x != nil {
+		return x.Task
+	}
+	return ""
+}
+
+var File_gopomodoro_v1_pomodoro_proto protoreflect.FileDescriptor
+
+const file_gopomodoro_v1_pomodoro_proto_rawDesc = "" +
+	"\n" +
+	"\x1cgopomodoro/v1/pomodoro.proto\x12\rgopomodoro.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x11\n" +
+	"\x0fGetStateRequest\">\n" +
+	"\x10GetStateResponse\x12*\n" +
+	"\x05state\x18\x01 \x01(\v2\x14.gopomodoro.v1.StateR\x05state\"\x0e\n" +
+	"\fStartRequest\";\n" +
+	"\rStartResponse\x12*\n" +
+	"\x05state\x18\x01 \x01(\v2\x14.gopomodoro.v1.StateR\x05state\"B\n" +
+	"\fPauseRequest\x122\n" +
+	"\x06reason\x18\x01 \x01(\x0e2\x1a.gopomodoro.v1.PauseReasonR\x06reason\";\n" +
+	"\rPauseResponse\x12*\n" +
+	"\x05state\x18\x01 \x01(\v2\x14.gopomodoro.v1.StateR\x05state\"\x0f\n" +
+	"\rResumeRequest\"<\n" +
+	"\x0eResumeResponse\x12*\n" +
+	"\x05state\x18\x01 \x01(\v2\x14.gopomodoro.v1.StateR\x05state\"\r\n" +
+	"\vStopRequest\":\n" +
+	"\fStopResponse\x12*\n" +
+	"\x05state\x18\x01 \x01(\v2\x14.gopomodoro.v1.StateR\x05state\"\r\n" +
+	"\vSkipRequest\":\n" +
+	"\fSkipResponse\x12*\n" +
+	"\x05state\x18\x01 \x01(\v2\x14.gopomodoro.v1.StateR\x05state\"N\n" +
+	"\fWatchRequest\x12>\n" +
+	"\rtick_interval\x18\x01 \x01(\v2\x19.google.protobuf.DurationR\ftickInterval\"\xaf\x01\n" +
+	"\rWatchResponse\x12,\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x18.gopomodoro.v1.EventKindR\x04kind\x12*\n" +
+	"\x02at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x02at\x12*\n" +
+	"\x05state\x18\x03 \x01(\v2\x14.gopomodoro.v1.StateR\x05state\x12\x18\n" +
+	"\amessage\x18\x04 \x01(\tR\amessage\"\xd6\x03\n" +
+	"\x05State\x12*\n" +
+	"\x05phase\x18\x01 \x01(\x0e2\x14.gopomodoro.v1.PhaseR\x05phase\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x129\n" +
+	"\n" +
+	"started_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x123\n" +
+	"\aends_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06endsAt\x127\n" +
+	"\tremaining\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\tremaining\x12#\n" +
+	"\rpomodoro_done\x18\x06 \x01(\x05R\fpomodoroDone\x12\x16\n" +
+	"\x06paused\x18\a \x01(\bR\x06paused\x12=\n" +
+	"\fpause_reason\x18\b \x01(\x0e2\x1a.gopomodoro.v1.PauseReasonR\vpauseReason\x12$\n" +
+	"\rinterruptions\x18\t \x01(\x05R\rinterruptions\x12\x1a\n" +
+	"\bovertime\x18\n" +
+	" \x01(\bR\bovertime\x12\x12\n" +
+	"\x04idle\x18\v \x01(\bR\x04idle\x12\x12\n" +
+	"\x04task\x18\f \x01(\tR\x04task*[\n" +
+	"\x05Phase\x12\x15\n" +
+	"\x11PHASE_UNSPECIFIED\x10\x00\x12\x0e\n" +
+	"\n" +
+	"PHASE_WORK\x10\x01\x12\x15\n" +
+	"\x11PHASE_SHORT_BREAK\x10\x02\x12\x14\n" +
+	"\x10PHASE_LONG_BREAK\x10\x03*\xa9\x01\n" +
+	"\vPauseReason\x12\x1c\n" +
+	"\x18PAUSE_REASON_UNSPECIFIED\x10\x00\x12\x18\n" +
+	"\x14PAUSE_REASON_MEETING\x10\x01\x12\x14\n" +
+	"\x10PAUSE_REASON_BIO\x10\x02\x12\x1d\n" +
+	"\x19PAUSE_REASON_INTERRUPTION\x10\x03\x12\x16\n" +
+	"\x12PAUSE_REASON_OTHER\x10\x04\x12\x15\n" +
+	"\x11PAUSE_REASON_IDLE\x10\x05*\xca\x03\n" +
+	"\tEventKind\x12\x1a\n" +
+	"\x16EVENT_KIND_UNSPECIFIED\x10\x00\x12\x14\n" +
+	"\x10EVENT_KIND_STATE\x10\x01\x12\x13\n" +
+	"\x0fEVENT_KIND_TICK\x10\x02\x12\x14\n" +
+	"\x10EVENT_KIND_START\x10\x03\x12\x16\n" +
+	"\x12EVENT_KIND_ADVANCE\x10\x04\x12\x14\n" +
+	"\x10EVENT_KIND_PAUSE\x10\x05\x12\x15\n" +
+	"\x11EVENT_KIND_RESUME\x10\x06\x12\x13\n" +
+	"\x0fEVENT_KIND_STOP\x10\a\x12\x15\n" +
+	"\x11EVENT_KIND_UPDATE\x10\b\x12\x18\n" +
+	"\x14EVENT_KIND_INTERRUPT\x10\t\x12\x17\n" +
+	"\x13EVENT_KIND_OVERTIME\x10\n" +
+	"\x12\x13\n" +
+	"\x0fEVENT_KIND_SKIP\x10\v\x12\x16\n" +
+	"\x12EVENT_KIND_WARNING\x10\f\x12\x15\n" +
+	"\x11EVENT_KIND_EXTEND\x10\r\x12\x16\n" +
+	"\x12EVENT_KIND_ABANDON\x10\x0e\x12\x13\n" +
+	"\x0fEVENT_KIND_TASK\x10\x0f\x12\x16\n" +
+	"\x12EVENT_KIND_REFUSED\x10\x10\x12\x1e\n" +
+	"\x1aEVENT_KIND_CONFIG_RELOADED\x10\x11\x12\x13\n" +
+	"\x0fEVENT_KIND_UNDO\x10\x122\xf5\x03\n" +
+	"\x0fPomodoroService\x12K\n" +
+	"\bGetState\x12\x1e.gopomodoro.v1.GetStateRequest\x1a\x1f.gopomodoro.v1.GetStateResponse\x12B\n" +
+	"\x05Start\x12\x1b.gopomodoro.v1.StartRequest\x1a\x1c.gopomodoro.v1.StartResponse\x12B\n" +
+	"\x05Pause\x12\x1b.gopomodoro.v1.PauseRequest\x1a\x1c.gopomodoro.v1.PauseResponse\x12E\n" +
+	"\x06Resume\x12\x1c.gopomodoro.v1.ResumeRequest\x1a\x1d.gopomodoro.v1.ResumeResponse\x12?\n" +
+	"\x04Stop\x12\x1a.gopomodoro.v1.StopRequest\x1a\x1b.gopomodoro.v1.StopResponse\x12?\n" +
+	"\x04Skip\x12\x1a.gopomodoro.v1.SkipRequest\x1a\x1b.gopomodoro.v1.SkipResponse\x12D\n" +
+	"\x05Watch\x12\x1b.gopomodoro.v1.WatchRequest\x1a\x1c.gopomodoro.v1.WatchResponse0\x01B=Z;github.com/ezchuang/GoPomodoro/api/gopomodoro/v1;pomodorov1b\x06proto3"
+
+var (
+	file_gopomodoro_v1_pomodoro_proto_rawDescOnce sync.Once
+	file_gopomodoro_v1_pomodoro_proto_rawDescData []byte
+)
+
+func file_gopomodoro_v1_pomodoro_proto_rawDescGZIP() []byte {
+	file_gopomodoro_v1_pomodoro_proto_rawDescOnce.Do(func() {
+		file_gopomodoro_v1_pomodoro_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_gopomodoro_v1_pomodoro_proto_rawDesc), len(file_gopomodoro_v1_pomodoro_proto_rawDesc)))
+	})
+	return file_gopomodoro_v1_pomodoro_proto_rawDescData
+}
+
+var file_gopomodoro_v1_pomodoro_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_gopomodoro_v1_pomodoro_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_gopomodoro_v1_pomodoro_proto_goTypes = []any{
+	(Phase)(0),                    // 0: gopomodoro.v1.Phase
+	(PauseReason)(0),              // 1: gopomodoro.v1.PauseReason
+	(EventKind)(0),                // 2: gopomodoro.v1.EventKind
+	(*GetStateRequest)(nil),       // 3: gopomodoro.v1.GetStateRequest
+	(*GetStateResponse)(nil),      // 4: gopomodoro.v1.GetStateResponse
+	(*StartRequest)(nil),          // 5: gopomodoro.v1.StartRequest
+	(*StartResponse)(nil),         // 6: gopomodoro.v1.StartResponse
+	(*PauseRequest)(nil),          // 7: gopomodoro.v1.PauseRequest
+	(*PauseResponse)(nil),         // 8: gopomodoro.v1.PauseResponse
+	(*ResumeRequest)(nil),         // 9: gopomodoro.v1.ResumeRequest
+	(*ResumeResponse)(nil),        // 10: gopomodoro.v1.ResumeResponse
+	(*StopRequest)(nil),           // 11: gopomodoro.v1.StopRequest
+	(*StopResponse)(nil),          // 12: gopomodoro.v1.StopResponse
+	(*SkipRequest)(nil),           // 13: gopomodoro.v1.SkipRequest
+	(*SkipResponse)(nil),          // 14: gopomodoro.v1.SkipResponse
+	(*WatchRequest)(nil),          // 15: gopomodoro.v1.WatchRequest
+	(*WatchResponse)(nil),         // 16: gopomodoro.v1.WatchResponse
+	(*State)(nil),                 // 17: gopomodoro.v1.State
+	(*durationpb.Duration)(nil),   // 18: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 19: google.protobuf.Timestamp
+}
+var file_gopomodoro_v1_pomodoro_proto_depIdxs = []int32{
+	17, // 0: gopomodoro.v1.GetStateResponse.state:type_name -> gopomodoro.v1.State
+	17, // 1: gopomodoro.v1.StartResponse.state:type_name -> gopomodoro.v1.State
+	1,  // 2: gopomodoro.v1.PauseRequest.reason:type_name -> gopomodoro.v1.PauseReason
+	17, // 3: gopomodoro.v1.PauseResponse.state:type_name -> gopomodoro.v1.State
+	17, // 4: gopomodoro.v1.ResumeResponse.state:type_name -> gopomodoro.v1.State
+	17, // 5: gopomodoro.v1.StopResponse.state:type_name -> gopomodoro.v1.State
+	17, // 6: gopomodoro.v1.SkipResponse.state:type_name -> gopomodoro.v1.State
+	18, // 7: gopomodoro.v1.WatchRequest.tick_interval:type_name -> google.protobuf.Duration
+	2,  // 8: gopomodoro.v1.WatchResponse.kind:type_name -> gopomodoro.v1.EventKind
+	19, // 9: gopomodoro.v1.WatchResponse.at:type_name -> google.protobuf.Timestamp
+	17, // 10: gopomodoro.v1.WatchResponse.state:type_name -> gopomodoro.v1.State
+	0,  // 11: gopomodoro.v1.State.phase:type_name -> gopomodoro.v1.Phase
+	19, // 12: gopomodoro.v1.State.started_at:type_name -> google.protobuf.Timestamp
+	19, // 13: gopomodoro.v1.State.ends_at:type_name -> google.protobuf.Timestamp
+	18, // 14: gopomodoro.v1.State.remaining:type_name -> google.protobuf.Duration
+	1,  // 15: gopomodoro.v1.State.pause_reason:type_name -> gopomodoro.v1.PauseReason
+	3,  // 16: gopomodoro.v1.PomodoroService.GetState:input_type -> gopomodoro.v1.GetStateRequest
+	5,  // 17: gopomodoro.v1.PomodoroService.Start:input_type -> gopomodoro.v1.StartRequest
+	7,  // 18: gopomodoro.v1.PomodoroService.Pause:input_type -> gopomodoro.v1.PauseRequest
+	9,  // 19: gopomodoro.v1.PomodoroService.Resume:input_type -> gopomodoro.v1.ResumeRequest
+	11, // 20: gopomodoro.v1.PomodoroService.Stop:input_type -> gopomodoro.v1.StopRequest
+	13, // 21: gopomodoro.v1.PomodoroService.Skip:input_type -> gopomodoro.v1.SkipRequest
+	15, // 22: gopomodoro.v1.PomodoroService.Watch:input_type -> gopomodoro.v1.WatchRequest
+	4,  // 23: gopomodoro.v1.PomodoroService.GetState:output_type -> gopomodoro.v1.GetStateResponse
+	6,  // 24: gopomodoro.v1.PomodoroService.Start:output_type -> gopomodoro.v1.StartResponse
+	8,  // 25: gopomodoro.v1.PomodoroService.Pause:output_type -> gopomodoro.v1.PauseResponse
+	10, // 26: gopomodoro.v1.PomodoroService.Resume:output_type -> gopomodoro.v1.ResumeResponse
+	12, // 27: gopomodoro.v1.PomodoroService.Stop:output_type -> gopomodoro.v1.StopResponse
+	14, // 28: gopomodoro.v1.PomodoroService.Skip:output_type -> gopomodoro.v1.SkipResponse
+	16, // 29: gopomodoro.v1.PomodoroService.Watch:output_type -> gopomodoro.v1.WatchResponse
+	23, // [23:30] is the sub-list for method output_type
+	16, // [16:23] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
+}
+
+func init() { file_gopomodoro_v1_pomodoro_proto_init() }
+func file_gopomodoro_v1_pomodoro_proto_init() {
+	if File_gopomodoro_v1_pomodoro_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_gopomodoro_v1_pomodoro_proto_rawDesc), len(file_gopomodoro_v1_pomodoro_proto_rawDesc)),
+			NumEnums:      3,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_gopomodoro_v1_pomodoro_proto_goTypes,
+		DependencyIndexes: file_gopomodoro_v1_pomodoro_proto_depIdxs,
+		EnumInfos:         file_gopomodoro_v1_pomodoro_proto_enumTypes,
+		MessageInfos:      file_gopomodoro_v1_pomodoro_proto_msgTypes,
+	}.Build()
+	File_gopomodoro_v1_pomodoro_proto = out.File
+	file_gopomodoro_v1_pomodoro_proto_goTypes = nil
+	file_gopomodoro_v1_pomodoro_proto_depIdxs = nil
+}
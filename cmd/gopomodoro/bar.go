@@ -0,0 +1,147 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/client"
+	"github.com/ezchuang/GoPomodoro/internal/server"
+)
+
+// barColors are the i3blocks colors per barStatus class.
+var barColors = map[string]string{
+	"idle":        "#928374",
+	"work":        "#fb4934",
+	"short_break": "#b8bb26",
+	"long_break":  "#83a598",
+	"paused":      "#fabd2f",
+	"overtime":    "#d3869b",
+}
+
+// barClicks maps click names to daemon calls.
+var barClicks = map[string]func(c *client.Client, ctx context.Context) error{
+	"toggle":  (*client.Client).Toggle,
+	"skip":    func(c *client.Client, ctx context.Context) error { return c.Post(ctx, "/skip") },
+	"stop":    func(c *client.Client, ctx context.Context) error { return c.Post(ctx, "/stop") },
+	"extend":  func(c *client.Client, ctx context.Context) error { return c.Post(ctx, "/extend?by=1m") },
+	"shorten": func(c *client.Client, ctx context.Context) error { return c.Post(ctx, "/extend?by=-1m") },
+}
+
+// i3blocksButtons maps $BLOCK_BUTTON to a click: left toggles, middle
+// stops, right skips and the wheel extends or shortens.
+var i3blocksButtons = map[string]string{"1": "toggle", "2": "stop", "3": "skip", "4": "extend", "5": "shorten"}
+
+// barCommand prints the daemon's timer for waybar or i3blocks, optionally
+// handling a click first.
+func barCommand(fs *flag.FlagSet) func(args []string) error {
+	addr := fs.String("addr", "", "daemon address (default $GOPOMODORO_ADDR or 127.0.0.1:7767)")
+	timer := fs.String("timer", "", "show a named timer instead of the default one")
+	format := fs.String("format", "waybar", "output format: waybar, i3blocks or plain")
+	click := fs.String("click", "", "send an action first: toggle, skip, stop, extend or shorten")
+	follow := fs.Bool("follow", false, "print a new line every second instead of once")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+
+		render, ok := map[string]func(barStatus, server.StateJSON, bool) string{
+			"waybar":   waybarLine,
+			"i3blocks": i3blocksLine,
+			"plain":    plainLine,
+		}[*format]
+		if !ok {
+			return fmt.Errorf("unknown format %q (want waybar, i3blocks or plain)", *format)
+		}
+		if *click == "" && *format == "i3blocks" {
+			*click = i3blocksButtons[os.Getenv("BLOCK_BUTTON")]
+		}
+		c := client.New(*addr)
+		c.Timer = *timer
+		if *click != "" {
+			do, ok := barClicks[*click]
+			if !ok {
+				return fmt.Errorf("unknown click action %q", *click)
+			}
+			ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+			err := do(c, ctx)
+			cancel()
+			if err != nil && !*follow {
+				fmt.Fprintln(os.Stderr, err)
+			}
+		}
+
+		show := func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+			defer cancel()
+			st, err := c.State(ctx)
+			fmt.Println(render(summarize(st, time.Now()), st, err == nil))
+		}
+		show()
+		if *follow {
+			for range time.Tick(time.Second) {
+				show()
+			}
+		}
+		return nil
+	}
+}
+
+// waybarLine is a JSON object for a custom module with
+// "return-type": "json". Without a daemon the text is empty, which
+// hides the module.
+func waybarLine(b barStatus, st server.StateJSON, up bool) string {
+	out := struct {
+		Text    string `json:"text"`
+		Tooltip string `json:"tooltip,omitempty"`
+		Class   string `json:"class"`
+		Alt     string `json:"alt"`
+	}{Class: "offline", Alt: "offline"}
+	if up {
+		out.Text, out.Tooltip, out.Class, out.Alt = b.String(), barTooltip(b, st), b.Class, b.Class
+	}
+	line, _ := json.Marshal(out)
+	return string(line)
+}
+
+// plainLine is the bare status, empty without a daemon.
+func plainLine(b barStatus, _ server.StateJSON, up bool) string {
+	if !up {
+		return ""
+	}
+	return b.String()
+}
+
+// i3blocksLine is full_text, short_text and color on three lines.
+func i3blocksLine(b barStatus, _ server.StateJSON, up bool) string {
+	if !up {
+		return ""
+	}
+	return b.String() + "\n" + b.Text + "\n" + barColors[b.Class]
+}
+
+// barTooltip describes the state in a few lines.
+func barTooltip(b barStatus, st server.StateJSON) string {
+	var lines []string
+	switch b.Class {
+	case "idle":
+		lines = append(lines, "Idle")
+	case "overtime":
+		lines = append(lines, st.Name+" overtime "+b.Text)
+	case "paused":
+		line := st.Name + " paused, " + b.Text + " left"
+		if st.PauseReason != "" {
+			line += " (" + st.PauseReason + ")"
+		}
+		lines = append(lines, line)
+	default:
+		lines = append(lines, st.Name+", "+b.Text+" left")
+	}
+	lines = append(lines, fmt.Sprintf("Completed: %d", st.PomodoroDone))
+	if st.Task != "" {
+		lines = append(lines, "Task: "+st.Task)
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"os/user"
+	"path/filepath"
+	"syscall"
+
+	"github.com/ezchuang/GoPomodoro/internal/blocker"
+)
+
+// blockerCommand is the site blocker's helper: "hold" blocks until stdin
+// closes, "restore" removes a leftover block and "setup" prints the sudo
+// rule that lets the timer run the other two, for a binary only root
+// can change.
+func blockerCommand(fs *flag.FlagSet) func(args []string) error {
+	hosts := fs.String("hosts", blocker.HostsPath(), "hosts file")
+	return func(args []string) error {
+		if len(args) == 0 {
+			return errors.New("usage: gopomodoro blocker hold|restore|setup")
+		}
+		_ = fs.Parse(args[1:])
+		// under sudo, only ever touch the real hosts file
+		if os.Getenv("SUDO_UID") != "" && *hosts != blocker.HostsPath() {
+			return fmt.Errorf("blocker: refusing to edit %s as root", *hosts)
+		}
+
+		switch args[0] {
+		case "hold":
+			stop := make(chan struct{})
+			go func() {
+				// EOF on stdin: the timer unblocked, exited or crashed
+				_, _ = io.Copy(io.Discard, os.Stdin)
+				close(stop)
+			}()
+			sigs := make(chan os.Signal, 1)
+			signal.Notify(sigs, os.Interrupt, syscall.SIGTERM, syscall.SIGHUP)
+			go func() {
+				<-sigs
+				_ = os.Stdin.Close()
+			}()
+			return blocker.Hold(*hosts, fs.Args(), func() { fmt.Println("blocked") }, stop)
+		case "restore":
+			return blocker.Restore(*hosts)
+		case "setup":
+			exe, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			if exe, err = filepath.EvalSymlinks(exe); err != nil {
+				return err
+			}
+			// the rule runs exe as root, so whoever can replace it must
+			// be root already
+			if err := blocker.RootOnly(exe); err != nil {
+				return fmt.Errorf(`blocker: won't make a sudo rule for %s: %w
+Install gopomodoro where only root can change it first, e.g.
+	sudo install -o root -g root -m 0755 %s /usr/local/bin/gopomodoro
+then run "/usr/local/bin/gopomodoro blocker setup"`, exe, err, exe)
+			}
+			name := "ALL"
+			if u, err := user.Current(); err == nil {
+				name = u.Username
+			}
+			path := blocker.HostsPath()
+			fmt.Printf(`GoPomodoro edits %s through "sudo -n" when it can't write it itself.
+	Allow that without a password by running "sudo visudo -f /etc/sudoers.d/gopomodoro"
+	and adding:
+
+	%s ALL=(root) NOPASSWD: %s blocker hold -hosts %s -- *, %s blocker restore -hosts %s
+	`, path, name, exe, path, exe, path)
+			return nil
+		}
+		return fmt.Errorf("blocker: unknown command %q", args[0])
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+// command is a subcommand. setup registers its flags on fs and returns
+// the function running it; that function parses args with fs itself,
+// since some commands take an action first or pass flags on.
+type command struct {
+	setup func(fs *flag.FlagSet) func(args []string) error
+	// summary is the one-line description in the command list; usage
+	// shows the arguments, e.g. "[flags] duration [message]".
+	summary string
+	usage   string
+	// actions are the command's own subcommands, e.g. history's
+	// "edit", for shell completion.
+	actions []string
+	// hidden flags work but are left out of the help.
+	hidden []string
+}
+
+// commands are the subcommands by name. Without one, tui runs. help
+// and completion are added by init, since they list the others.
+var commands = map[string]command{
+	"tui": {setup: tuiCommand, summary: "run the timer in the terminal UI (the default)",
+		usage: "[flags]"},
+	"daemon": {setup: daemonCommand, summary: "run the timer headless, controlled through the HTTP API",
+		usage: "[flags]", hidden: []string{"fault-inject", "fault-seed"}},
+	"tray": {setup: trayCommand, summary: "run the timer behind a system tray icon",
+		usage: "[flags]"},
+	"start": {setup: startCommand, summary: "start the daemon's timer, or resume it when paused",
+		usage: "[flags]"},
+	"status": {setup: statusCommand, summary: "print the daemon's timer",
+		usage: "[flags]"},
+	"wait": {setup: waitCommand, summary: "wait for the daemon's phase to end; exit 0 if it completed",
+		usage: "[flags]"},
+	"countdown": {setup: countdownCommand, summary: "just a timer: count down once, notify and exit",
+		usage: "[flags] duration [message]  (e.g. countdown 4m Tea is ready)"},
+	"timers": {setup: timersCommand, summary: "list or control the daemon's named timers",
+		usage:   "[flags] [list | add NAME | rm NAME | start|pause|resume|stop|skip|toggle NAME]",
+		actions: []string{"list", "add", "rm", "start", "pause", "resume", "stop", "skip", "toggle"}},
+	"stats": {setup: statsCommand, summary: "print statistics from the history",
+		usage: "[flags]"},
+	"export": {setup: exportCommand, summary: "export the history as CSV, JSON or iCalendar",
+		usage: "[flags]"},
+	"import": {setup: importCommand, summary: "import sessions from other apps' exports",
+		usage: "[flags] file... (- for stdin)"},
+	"key": {setup: keyCommand, summary: "encrypt the history at rest with a key file, or rotate or remove it",
+		usage: "[flags] [status | init | rotate | decrypt]", actions: []string{"status", "init", "rotate", "decrypt"}},
+	"sync": {setup: syncCommand, summary: "sync the history with your other machines through S3, WebDAV or git",
+		usage: "[flags]"},
+	"history": {setup: historyCommand, summary: "list, edit and delete stored sessions",
+		usage:   "[list [-since date] | edit <id> [-task t] [-tags t] [-status s] [-start t] [-end t] | delete <id> | audit]",
+		actions: []string{"list", "edit", "delete", "audit"}},
+	"config": {setup: configCommand, summary: "show where the config is, check it or print a profile",
+		usage:   "[flags] path | check | show [profile]",
+		actions: []string{"path", "check", "show"}},
+	"service": {setup: serviceCommand, summary: "install the daemon as a login service",
+		usage:   "install [daemon flags] | uninstall | status",
+		actions: []string{"install", "uninstall", "status"}},
+	"bar": {setup: barCommand, summary: "print the daemon's timer for waybar or i3blocks",
+		usage: "[flags]"},
+	"tmux": {setup: tmuxCommand, summary: "print the daemon's timer for tmux's status line",
+		usage: "[flags]"},
+	"git": {setup: gitCommand, summary: "count pomodoros per commit with git hooks",
+		usage:   "install [-trailer=false] [-record=false] [dir] | uninstall [dir] | stats [-since date]",
+		actions: []string{"install", "uninstall", "stats"}},
+	"jira": {setup: jiraCommand, summary: "submit Jira worklogs for past sessions",
+		usage:   "worklog [-since date] [-dry-run]  (-since defaults to today)",
+		actions: []string{"worklog"}},
+	"gcal": {setup: gcalCommand, summary: "connect a Google Calendar account",
+		usage: "login [flags]", actions: []string{"login"}},
+	"spotify": {setup: spotifyCommand, summary: "connect Spotify and list its devices",
+		usage: "login|devices [flags]", actions: []string{"login", "devices"}},
+	"leaderboard": {setup: leaderboardCommand, summary: "run the team leaderboard server members report to",
+		usage: "[flags]"},
+	"blocker": {setup: blockerCommand, summary: "the site blocker's privileged helper",
+		usage: "hold|restore|setup [flags]", actions: []string{"hold", "restore", "setup"}},
+}
+
+func init() {
+	commands["help"] = command{setup: helpCommand, summary: "list the commands, or show one's flags",
+		usage: "[command]"}
+	commands["completion"] = command{setup: completionCommand, summary: "print a shell completion script",
+		usage: "bash|zsh|fish|powershell", actions: slices.Sorted(maps.Keys(completionScripts))}
+	commands[completeName] = command{setup: completeCommand}
+}
+
+// defaultCommand runs when the first argument names no command.
+const defaultCommand = "tui"
+
+// lookup returns the named command, or the default one for "".
+func lookup(name string) (command, bool) {
+	c, ok := commands[commandName(name)]
+	return c, ok
+}
+
+// commandName resolves "" to the default command.
+func commandName(name string) string {
+	if name == "" {
+		return defaultCommand
+	}
+	return name
+}
+
+// flagSet is the flag set of the named command, with its flags
+// registered and its help as Usage, and the function running it.
+func flagSet(name string) (*flag.FlagSet, func(args []string) error) {
+	name = commandName(name)
+	c := commands[name]
+	fs := flag.NewFlagSet(name, flag.ExitOnError)
+	fs.Usage = func() { printUsage(fs, name, c) }
+	return fs, c.setup(fs)
+}
+
+// runCommand runs the named command, or the TUI for "", with args.
+func runCommand(name string, args []string) error {
+	_, run := flagSet(name)
+	return run(args)
+}
+
+// printUsage is every command's -h: how to call it, what it does and
+// its visible flags. The default command's also lists the others.
+func printUsage(fs *flag.FlagSet, name string, c command) {
+	out := fs.Output()
+	if name == defaultCommand {
+		fmt.Fprintf(out, "Usage: gopomodoro [command] [flags]\n\n")
+		printCommands(out)
+		fmt.Fprintf(out, "\nRun \"gopomodoro help <command>\" for a command's flags. Without a command, %s runs:\n\n", defaultCommand)
+	}
+	fmt.Fprintf(out, "Usage: gopomodoro %s %s\n\n%s.\n", name, c.usage, upperFirst(c.summary))
+	visible := flag.NewFlagSet(name, flag.ContinueOnError)
+	visible.SetOutput(out)
+	fs.VisitAll(func(f *flag.Flag) {
+		if !slices.Contains(c.hidden, f.Name) {
+			visible.Var(f.Value, f.Name, f.Usage)
+		}
+	})
+	// Var keeps the current values, so DefValue comes from fs
+	visible.VisitAll(func(f *flag.Flag) {
+		f.DefValue = fs.Lookup(f.Name).DefValue
+	})
+	hasFlags := false
+	visible.VisitAll(func(*flag.Flag) { hasFlags = true })
+	if hasFlags {
+		fmt.Fprintf(out, "\nFlags:\n")
+		visible.PrintDefaults()
+	}
+}
+
+// printCommands lists the commands with their summaries.
+func printCommands(out io.Writer) {
+	var names []string
+	width := 0
+	for n := range commands {
+		if !strings.HasPrefix(n, "_") {
+			names = append(names, n)
+			width = max(width, len(n))
+		}
+	}
+	slices.Sort(names)
+	fmt.Fprintf(out, "Commands:\n")
+	for _, n := range names {
+		fmt.Fprintf(out, "  %-*s  %s\n", width, n, commands[n].summary)
+	}
+}
+
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+// helpCommand prints the command list, or a command's help.
+func helpCommand(*flag.FlagSet) func(args []string) error {
+	return func(args []string) error {
+		if len(args) == 0 {
+			fmt.Fprintf(os.Stderr, "Usage: gopomodoro [command] [flags]\n\n")
+			printCommands(os.Stderr)
+			fmt.Fprintf(os.Stderr, "\nRun \"gopomodoro help <command>\" for a command's flags.\n")
+			return nil
+		}
+		if _, ok := lookup(args[0]); !ok || strings.HasPrefix(args[0], "_") {
+			return fmt.Errorf("help: unknown command %q", args[0])
+		}
+		fs, _ := flagSet(args[0])
+		fs.Usage()
+		return nil
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/ui"
+)
+
+// completeName is the hidden command the completion scripts call back
+// into with the words typed so far.
+const completeName = "__complete"
+
+// completionScripts hook each shell's completion up to __complete, so
+// candidates such as profile names are always current.
+var completionScripts = map[string]string{
+	"bash": `# gopomodoro completion bash > /etc/bash_completion.d/gopomodoro
+_gopomodoro() {
+	local IFS=$'\n'
+	COMPREPLY=($(gopomodoro __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+}
+complete -o default -F _gopomodoro gopomodoro
+`,
+	"zsh": `#compdef gopomodoro
+# gopomodoro completion zsh > "${fpath[1]}/_gopomodoro"
+_gopomodoro() {
+	local -a candidates
+	candidates=(${(f)"$(gopomodoro __complete "${(@)words[2,CURRENT]}" 2>/dev/null)"})
+	if (( ${#candidates} )); then
+		compadd -- $candidates
+	else
+		_files
+	fi
+}
+compdef _gopomodoro gopomodoro
+`,
+	"fish": `# gopomodoro completion fish > ~/.config/fish/completions/gopomodoro.fish
+complete -c gopomodoro -a '(gopomodoro __complete (commandline -opc)[2..-1] (commandline -ct) 2>/dev/null)'
+`,
+	"powershell": `# gopomodoro completion powershell | Out-String | Invoke-Expression
+Register-ArgumentCompleter -Native -CommandName gopomodoro -ScriptBlock {
+	param($wordToComplete, $commandAst, $cursorPosition)
+	$words = @($commandAst.CommandElements | Select-Object -Skip 1 | ForEach-Object { $_.ToString() })
+	if ($wordToComplete -eq '') { $words += '""' }
+	gopomodoro __complete @words 2>$null | ForEach-Object {
+		[System.Management.Automation.CompletionResult]::new($_, $_, 'ParameterValue', $_)
+	}
+}
+`,
+}
+
+// completionCommand prints the completion script for a shell.
+func completionCommand(fs *flag.FlagSet) func(args []string) error {
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		script, ok := completionScripts[fs.Arg(0)]
+		if !ok {
+			fs.Usage()
+			os.Exit(2)
+		}
+		fmt.Print(script)
+		return nil
+	}
+}
+
+// completeCommand prints the candidates for the last of args, one per
+// line; the others are the words before it.
+func completeCommand(*flag.FlagSet) func(args []string) error {
+	return func(args []string) error {
+		if len(args) == 0 {
+			args = []string{""}
+		}
+		for _, c := range completions(args[:len(args)-1], args[len(args)-1]) {
+			fmt.Println(c)
+		}
+		return nil
+	}
+}
+
+// completions are the candidates for cur after words: subcommands,
+// their actions and flags, and the values of some flags.
+func completions(words []string, cur string) []string {
+	// bash splits "-profile=x" into "-profile", "=" and "x"
+	if cur == "=" {
+		cur = ""
+	} else if n := len(words); n > 0 && words[n-1] == "=" {
+		words = words[:n-1]
+	}
+	name := ""
+	if len(words) > 0 && !strings.HasPrefix(words[0], "_") {
+		if _, ok := commands[words[0]]; ok {
+			name, words = words[0], words[1:]
+		}
+	}
+	c, _ := lookup(name)
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	c.setup(fs)
+
+	// the value of a flag, after it or its "="
+	if f, ok := valueFlag(fs, words); ok {
+		return matching(flagValues(name, f, words), cur)
+	}
+	if dash, rest, ok := strings.Cut(cur, "="); ok && strings.HasPrefix(dash, "-") {
+		if f := fs.Lookup(strings.TrimLeft(dash, "-")); f != nil {
+			var out []string
+			for _, v := range matching(flagValues(name, f.Name, words), rest) {
+				out = append(out, dash+"="+v)
+			}
+			return out
+		}
+	}
+
+	if strings.HasPrefix(cur, "-") {
+		dashes := "-"
+		if strings.HasPrefix(cur, "--") {
+			dashes = "--"
+		}
+		var names []string
+		fs.VisitAll(func(f *flag.Flag) {
+			names = append(names, dashes+f.Name)
+		})
+		return matching(names, cur)
+	}
+	if positionals(fs, words) > 0 {
+		return nil
+	}
+	if name == "" {
+		var names []string
+		for n := range commands {
+			if !strings.HasPrefix(n, "_") {
+				names = append(names, n)
+			}
+		}
+		return matching(names, cur)
+	}
+	return matching(c.actions, cur)
+}
+
+// valueFlag reports the flag whose value is the next word: the last of
+// words when it's a flag without "=" that isn't boolean.
+func valueFlag(fs *flag.FlagSet, words []string) (string, bool) {
+	if len(words) == 0 {
+		return "", false
+	}
+	last := words[len(words)-1]
+	if !strings.HasPrefix(last, "-") || strings.Contains(last, "=") {
+		return "", false
+	}
+	f := fs.Lookup(strings.TrimLeft(last, "-"))
+	if f == nil || isBoolFlag(f) {
+		return "", false
+	}
+	return f.Name, true
+}
+
+func isBoolFlag(f *flag.Flag) bool {
+	b, ok := f.Value.(interface{ IsBoolFlag() bool })
+	return ok && b.IsBoolFlag()
+}
+
+// positionals counts the words that are neither flags nor their values.
+func positionals(fs *flag.FlagSet, words []string) int {
+	n := 0
+	for i := 0; i < len(words); i++ {
+		w := words[i]
+		if !strings.HasPrefix(w, "-") || w == "-" {
+			n++
+			continue
+		}
+		if _, ok := valueFlag(fs, words[:i+1]); ok {
+			i++
+		}
+	}
+	return n
+}
+
+// flagValues are the known values of a flag of the named command; the
+// config for profiles, timers and themes is the one -config in words
+// names, or the default.
+func flagValues(name, flagName string, words []string) []string {
+	switch flagName {
+	case "format":
+		switch name {
+		case "bar":
+			return []string{"waybar", "i3blocks", "plain"}
+		case "export":
+			return []string{"csv", "json", "ics"}
+		case "import":
+			return history.ImportFormats
+		}
+		return nil
+	case "profile", "timer", "theme":
+	default:
+		return nil
+	}
+	path := ""
+	for i, w := range words {
+		if v, ok := strings.CutPrefix(strings.TrimLeft(w, "-"), "config="); ok && strings.HasPrefix(w, "-") {
+			path = v
+		} else if strings.TrimLeft(w, "-") == "config" && strings.HasPrefix(w, "-") && i+1 < len(words) {
+			path = words[i+1]
+		}
+	}
+	f, err := loadConfig(path)
+	if err != nil {
+		return nil
+	}
+	switch flagName {
+	case "profile":
+		return slices.Sorted(maps.Keys(f.Profiles))
+	case "timer":
+		return slices.Sorted(maps.Keys(f.Timers))
+	}
+	return ui.ThemeNames(f)
+}
+
+// matching are the candidates starting with prefix, sorted.
+func matching(candidates []string, prefix string) []string {
+	var out []string
+	for _, c := range candidates {
+		if strings.HasPrefix(c, prefix) {
+			out = append(out, c)
+		}
+	}
+	slices.Sort(out)
+	return out
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/BurntSushi/toml"
+)
+
+// configCommand prints the config file's path, checks it, or prints a
+// profile with the defaults and inheritance resolved.
+func configCommand(fs *flag.FlagSet) func(args []string) error {
+	path := configFlag(fs)
+	return func(args []string) error {
+		rest := parseInterspersed(fs, args)
+		if len(rest) == 0 {
+			fs.Usage()
+			os.Exit(2)
+		}
+		switch rest[0] {
+		case "path":
+			fmt.Println(configPath(*path))
+			return nil
+		case "check":
+			f, err := loadConfig(*path)
+			if err != nil {
+				return err
+			}
+			names := f.Names()
+			for _, name := range names {
+				if _, err := f.Resolve(name); err != nil {
+					return fmt.Errorf("profile %s: %w", name, err)
+				}
+			}
+			fmt.Printf("%s: ok, %d profiles\n", configPath(*path), len(names))
+			return nil
+		case "show":
+			f, err := loadConfig(*path)
+			if err != nil {
+				return err
+			}
+			name := f.Profile
+			if len(rest) > 1 {
+				name = rest[1]
+			}
+			prof, err := f.Resolve(name)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("# profile %s\n", name)
+			return toml.NewEncoder(os.Stdout).Encode(prof)
+		}
+		fs.Usage()
+		return fmt.Errorf("config: unknown action %q", rest[0])
+	}
+}
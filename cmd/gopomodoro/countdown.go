@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+)
+
+// countdownCommand is "just a timer": a single countdown, shown on the
+// terminal, that notifies when it's up and exits. It keeps no history.
+func countdownCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := configFlag(fs)
+	quiet := fs.Bool("quiet", false, "don't show the time left")
+	return func(args []string) error {
+		pos := parseInterspersed(fs, args)
+		if len(pos) == 0 {
+			fs.Usage()
+			os.Exit(2)
+		}
+		d, err := time.ParseDuration(pos[0])
+		if err != nil || d <= 0 {
+			return fmt.Errorf("countdown: %q is not a positive duration like 10m or 90s", pos[0])
+		}
+		message := strings.Join(pos[1:], " ")
+		if message == "" {
+			message = "Time's up"
+		}
+
+		file, err := loadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		notifier, err := buildNotifier(file, nil)
+		if err != nil {
+			return err
+		}
+
+		// a one-step cycle: the engine's timing, without the rotation
+		engine := core.New(core.Config{Cycle: []core.Step{{Name: "countdown", Kind: core.PhaseShortBreak, Duration: d}}})
+		done := make(chan core.Event, 1)
+		defer engine.Subscribe(func(ev core.Event) {
+			if ev.Kind == core.EventAdvance {
+				select {
+				case done <- ev:
+				default:
+				}
+			}
+		})()
+		// the time left is redrawn in place, so only on a terminal
+		if fi, err := os.Stdout.Stat(); err == nil && fi.Mode()&os.ModeCharDevice != 0 && !*quiet {
+			defer engine.SubscribeTicks(func(ev core.Event) {
+				fmt.Printf("\r⏳ %s ", clock(ev.Remaining))
+			})()
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		engine.Start()
+		select {
+		case <-ctx.Done():
+			engine.Stop()
+			fmt.Println()
+			return nil
+		case ev := <-done:
+			engine.Stop()
+			if !*quiet {
+				fmt.Printf("\r⏰ %s\n", message)
+			}
+			return notify.Send(notifier, notify.Message{Title: "GoPomodoro", Body: message, Event: ev})
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/chaos"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+	"github.com/ezchuang/GoPomodoro/internal/rpc"
+	"github.com/ezchuang/GoPomodoro/internal/server"
+)
+
+// daemonCommand runs the engine headless, controlled through the HTTP API.
+func daemonCommand(fs *flag.FlagSet) func(args []string) error {
+	ef := registerEngineFlags(fs)
+	openHistory := historyFlag(fs)
+	openLog := logFlags(fs)
+	listen := fs.String("listen", server.DefaultAddr, "address of the HTTP/WebSocket API")
+	tf := registerTeamFlags(fs)
+	grpcAddr := fs.String("grpc", "", "also serve the gRPC API on this address (e.g. 127.0.0.1:7768)")
+	faultInject := fs.Bool("fault-inject", false, "randomly delay timers, drop notifications and restart the scheduler")
+	faultSeed := fs.Uint64("fault-seed", 0, "seed for -fault-inject (0 picks one from the clock)")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		logger, closeLog, err := openLog(false)
+		if err != nil {
+			return err
+		}
+		defer closeLog()
+
+		res, err := ef.resolve()
+		if err != nil {
+			return err
+		}
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		var engine *core.PomodoroEngine
+		registry, err := buildNotifier(res.file, logger)
+		if err != nil {
+			return err
+		}
+		defer tf.shareRooms(registry, func(err error) {
+			log.Printf("notify: %v", err)
+		})()
+		var notifier notify.Notifier = registry
+
+		if *faultInject {
+			seed := *faultSeed
+			if seed == 0 {
+				seed = uint64(time.Now().UnixNano())
+			}
+			log.Printf("fault injection enabled, seed %d", seed)
+			opts := chaos.DefaultOptions(seed)
+			opts.Logf = log.Printf
+			in := chaos.New(opts)
+
+			engine = core.NewWithClock(res.engine, in.Clock())
+			notifier = in.Notifier(notifier)
+			_, cancel := chaos.Watch(engine, func(msg string) {
+				log.Printf("INVARIANT VIOLATED: %s", msg)
+			})
+			defer cancel()
+			go in.Run(ctx, engine)
+		} else {
+			engine = core.New(res.engine)
+		}
+		res.attach(engine)
+
+		cleanup, err := runHeadless(ctx, "daemon", engine, res, store, notifier)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		timers, err := openTimers(engine, res)
+		if err != nil {
+			return err
+		}
+		defer notifyTimers(timers, notifier, locale(res.file), func(err error) {
+			log.Printf("notify: %v", err)
+		})()
+
+		api, err := newAPI(timers, res, store, registry.Health)
+		if err != nil {
+			return err
+		}
+		_, cancelTeam, err := tf.start(ctx, engine, api, func(err error) {
+			log.Printf("team: %v", err)
+		})
+		if err != nil {
+			return err
+		}
+		defer cancelTeam()
+
+		srv := &http.Server{Addr: *listen, Handler: api}
+		errc := make(chan error, 1)
+		go func() { errc <- srv.ListenAndServe() }()
+		log.Printf("daemon listening on %s", *listen)
+		if *grpcAddr != "" {
+			lis, err := net.Listen("tcp", *grpcAddr)
+			if err != nil {
+				return err
+			}
+			gs := rpc.NewGRPC(engine, api.Token)
+			go func() { errc <- gs.Serve(lis) }()
+			// Watch streams never end on their own, so don't wait for them
+			defer gs.Stop()
+			log.Printf("gRPC API on %s", *grpcAddr)
+		}
+
+		// with -cycles, the daemon exits once the run is over
+		finished := make(chan struct{}, 1)
+		defer engine.Subscribe(func(ev core.Event) {
+			if ev.Kind == core.EventStop && ev.Finished {
+				select {
+				case finished <- struct{}{}:
+				default:
+				}
+			}
+		})()
+
+		var cut error
+		select {
+		case <-ctx.Done():
+			if n := res.engine.Cycles; n > 0 {
+				cut = fmt.Errorf("stopped before completing %d pomodoros", n)
+			}
+		case <-finished:
+			log.Printf("all -cycles %d done, exiting", res.engine.Cycles)
+		case err := <-errc:
+			return err
+		}
+		engine.Stop()
+		shutdown, done := context.WithTimeout(context.Background(), 2*time.Second)
+		defer done()
+		return errors.Join(cut, srv.Shutdown(shutdown))
+	}
+}
+
+// newAPI is the HTTP API for the default timer and the others of
+// timers, with the calendar feed backed by store and the notification
+// backends' health reported by notifiers. Its token is
+// $GOPOMODORO_API_TOKEN, else the one in api.token, made on first use.
+func newAPI(timers *core.Manager, res resolved, store *history.Store, notifiers func() []notify.Health) (*server.Server, error) {
+	token := os.Getenv(server.TokenEnv)
+	if token == "" {
+		path, err := server.DefaultTokenPath()
+		if err != nil {
+			return nil, err
+		}
+		if token, err = server.LoadToken(path); err != nil {
+			return nil, fmt.Errorf("api token: %w", err)
+		}
+	}
+	srv := server.New(timers.Get(core.DefaultTimer))
+	srv.Token = token
+	srv.Origins = res.file.API.Origins
+	srv.ServeCalendar(store.List)
+	srv.ServeNotifiers(notifiers)
+	srv.ServeTimers(timers, func(profile string) (*core.PomodoroEngine, error) {
+		return newTimer(res, profile)
+	})
+	return srv, nil
+}
@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"os"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// exportCommand dumps session history as CSV or JSON on stdout.
+func exportCommand(fs *flag.FlagSet) func(args []string) error {
+	openHistory := historyFlag(fs)
+	format := fs.String("format", "csv", "output format: csv, json or ics")
+	since := sinceFlag(fs)
+	return func(args []string) error {
+		_ = fs.Parse(args)
+
+		from, err := since()
+		if err != nil {
+			return err
+		}
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		sessions, err := store.List()
+		if err != nil {
+			return err
+		}
+		w := bufio.NewWriter(os.Stdout)
+		if err := history.Export(w, *format, stats.Filter(sessions, from)); err != nil {
+			return err
+		}
+		return w.Flush()
+	}
+}
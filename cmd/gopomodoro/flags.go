@@ -0,0 +1,291 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/i18n"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/github"
+	"github.com/ezchuang/GoPomodoro/internal/logging"
+)
+
+// engineFlags are the config/profile/timing flags shared by every
+// command that runs an engine.
+type engineFlags struct {
+	fs         *flag.FlagSet
+	configPath *string
+	profile    *string
+	work       *time.Duration
+	short      *time.Duration
+	long       *time.Duration
+	longEvery  *int
+	longAfter  *time.Duration
+	overtime   *bool
+	strict     *bool
+	flow       *bool
+	tags       *string
+	issue      *string
+	cycles     *int
+}
+
+func configFlag(fs *flag.FlagSet) *string {
+	return fs.String("config", "", "config file (default $XDG_CONFIG_HOME/gopomodoro/config.toml)")
+}
+
+func registerEngineFlags(fs *flag.FlagSet) *engineFlags {
+	return &engineFlags{
+		fs:         fs,
+		configPath: configFlag(fs),
+		profile:    fs.String("profile", "", "named duration profile from the config file"),
+		work:       fs.Duration("work", 25*time.Minute, "work duration"),
+		short:      fs.Duration("short", 5*time.Minute, "short break duration"),
+		long:       fs.Duration("long", 15*time.Minute, "long break duration"),
+		longEvery:  fs.Int("long-every", 4, "take a long break every N pomodoros (0 for never)"),
+		longAfter:  fs.Duration("long-after", 0, "take a long break after this much work instead of every N pomodoros (e.g. 2h)"),
+		overtime:   fs.Bool("overtime", false, "count up after a work phase until acknowledged instead of starting the break"),
+		strict:     fs.Bool("strict", false, "refuse pause, skip and extend during work; stopping voids the pomodoro"),
+		flow:       fs.Bool("flow", false, "open-ended work that counts up until acknowledged, with breaks sized to the time worked"),
+		tags:       fs.String("tags", "", `tags for the work sessions, e.g. "#client-a #coding"`),
+		issue:      fs.String("issue", "", "GitHub issue to work on, as a URL or owner/repo#number"),
+		cycles:     fs.Int("cycles", 0, "stop after this many completed pomodoros and exit, 1 if quit before (0 for no limit)"),
+	}
+}
+
+// resolved is what engineFlags produce after parsing.
+type resolved struct {
+	flags       *engineFlags
+	path        string // of the config file, "" if unknown
+	file        *config.File
+	profileName string
+	profile     config.Profile
+	engine      core.Config
+	// scheduled is set when the config's schedule picked the profile and
+	// no flag pins the timings, so it may switch later.
+	scheduled bool
+	// task and tags are what the engine starts with: the project's,
+	// unless -issue replaces its task or -tags its tags.
+	task core.Task
+	tags []string
+}
+
+// attach gives engine the task and tags to start with.
+func (r resolved) attach(engine *core.PomodoroEngine) {
+	engine.SetTask(r.task)
+	engine.SetTags(r.tags)
+}
+
+// configPath is path, or the default location when path is empty.
+func configPath(path string) string {
+	if path == "" {
+		if p, err := config.DefaultPath(); err == nil {
+			path = p
+		}
+	}
+	return path
+}
+
+// loadConfig reads the config at path, or at the default location when
+// path is empty, with the working directory's project config applied.
+func loadConfig(path string) (*config.File, error) {
+	f, err := config.Load(configPath(path))
+	if err != nil {
+		return nil, err
+	}
+	if err := useProject(f); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// locale is the language f sets, or else the environment's.
+func locale(f *config.File) *i18n.Locale {
+	if l, err := i18n.New(f.Locale); err == nil {
+		return l
+	}
+	return i18n.Detect(os.Getenv)
+}
+
+// useProject applies the project config of the working directory, if
+// there is one, to f.
+func useProject(f *config.File) error {
+	p, err := config.FindProject(".")
+	if err != nil || p == nil {
+		return err
+	}
+	return f.UseProject(p)
+}
+
+// resolve loads the config file and selected profile.
+func (f *engineFlags) resolve() (resolved, error) {
+	file, err := loadConfig(*f.configPath)
+	if err != nil {
+		return resolved{}, err
+	}
+	res, err := f.resolveFile(file)
+	if err != nil {
+		return resolved{}, err
+	}
+	if res.task.Source == github.Source {
+		res.task = issueTask(file, res.task)
+	}
+	return res, nil
+}
+
+// resolveFile selects the profile from file, by default the one the
+// config's schedule picks for now; timing flags given explicitly on the
+// command line override the profile.
+func (f *engineFlags) resolveFile(file *config.File) (resolved, error) {
+	name := *f.profile
+	scheduled := name == "" && len(file.Schedule) > 0
+	if name == "" {
+		name = file.Scheduled(time.Now())
+	}
+	prof, err := file.Resolve(name)
+	if err != nil {
+		return resolved{}, err
+	}
+	cfg := prof.Core()
+	cfg.Cycles = *f.cycles
+	f.fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "work":
+			cfg.Work = *f.work
+		case "short":
+			cfg.ShortBrk = *f.short
+		case "long":
+			cfg.LongBrk = *f.long
+		case "long-every":
+			cfg.LongEvery = *f.longEvery
+			cfg.Cadence = core.CadenceCount
+		case "long-after":
+			cfg.LongAfter = *f.longAfter
+			cfg.Cadence = core.CadenceTime
+		case "overtime":
+			cfg.Overtime = *f.overtime
+		case "strict":
+			cfg.Strict = *f.strict
+		case "flow":
+			cfg.Flow = *f.flow
+		default:
+			return
+		}
+		scheduled = false
+	})
+	res := resolved{
+		flags:       f,
+		path:        configPath(*f.configPath),
+		file:        file,
+		profileName: name,
+		profile:     prof,
+		engine:      cfg,
+		scheduled:   scheduled,
+		tags:        core.ParseTags(*f.tags),
+	}
+	if p := file.Project; p != nil {
+		res.task = core.Task{Title: p.Task}
+		if res.tags == nil {
+			res.tags = core.ParseTags(strings.Join(p.Tags, " "))
+		}
+	}
+	if *f.issue != "" {
+		ref, err := github.ParseRef(*f.issue)
+		if err != nil {
+			return resolved{}, err
+		}
+		res.task = github.Task(ref, "")
+	}
+	return res, nil
+}
+
+// logFlags registers -log-file and -log-level and returns a function
+// that, after parsing, sets up the log: engine events, notifications and
+// HTTP calls. It becomes slog's and log's default, except for the TUI
+// without -log-file, which gets a logger that discards everything
+// rather than one drawing over the screen.
+func logFlags(fs *flag.FlagSet) func(tui bool) (*slog.Logger, func(), error) {
+	path := fs.String("log-file", "", "write a structured (JSON) log to this file (default stderr, none for the TUI)")
+	level := fs.String("log-level", "info", "log level: debug, info, warn or error")
+	return func(tui bool) (*slog.Logger, func(), error) {
+		lvl, err := logging.ParseLevel(*level)
+		if err != nil {
+			return nil, nil, err
+		}
+		if *path == "" && tui {
+			return slog.New(slog.DiscardHandler), func() {}, nil
+		}
+		logger, closer, err := logging.Open(*path, lvl)
+		if err != nil {
+			return nil, nil, err
+		}
+		slog.SetDefault(logger)
+		http.DefaultTransport = logging.Transport(http.DefaultTransport, logger)
+		return logger, func() { _ = closer.Close() }, nil
+	}
+}
+
+// historyFlag registers -history and -key-file and returns a function
+// opening the selected store after parsing, encrypted when the key file
+// exists.
+func historyFlag(fs *flag.FlagSet) func() (*history.Store, error) {
+	open := historyKeyFlags(fs)
+	return func() (*history.Store, error) {
+		store, _, err := open()
+		return store, err
+	}
+}
+
+// historyKeyFlags is historyFlag, its function returning the key file's
+// path too.
+func historyKeyFlags(fs *flag.FlagSet) func() (*history.Store, string, error) {
+	path := fs.String("history", "", "history file (default $XDG_DATA_HOME/gopomodoro/history.jsonl)")
+	keyFile := fs.String("key-file", "", "key file encrypting the history, used if it exists (default $GOPOMODORO_KEY_FILE, else history.key beside the config)")
+	return func() (*history.Store, string, error) {
+		p := *path
+		if p == "" {
+			var err error
+			if p, err = history.DefaultPath(); err != nil {
+				return nil, "", err
+			}
+		}
+		kp := cmp.Or(*keyFile, os.Getenv("GOPOMODORO_KEY_FILE"))
+		if kp == "" {
+			cfg, err := config.DefaultPath()
+			if err != nil {
+				return nil, "", err
+			}
+			kp = filepath.Join(filepath.Dir(cfg), "history.key")
+		}
+		key, err := history.LoadKey(kp)
+		if err != nil {
+			return nil, "", err
+		}
+		store, err := history.OpenEncrypted(p, key)
+		return store, kp, err
+	}
+}
+
+// sinceFlag registers -since and returns a function parsing it after
+// flag parsing; no date gives the zero time.
+func sinceFlag(fs *flag.FlagSet) func() (time.Time, error) {
+	since := fs.String("since", "", "only include sessions from this date on (YYYY-MM-DD)")
+	return func() (time.Time, error) {
+		if *since == "" {
+			return time.Time{}, nil
+		}
+		t, err := time.ParseInLocation(time.DateOnly, *since, time.Local)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("-since: %w", err)
+		}
+		return t, nil
+	}
+}
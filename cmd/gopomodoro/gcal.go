@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/ezchuang/GoPomodoro/internal/integrations/gcal"
+)
+
+// gcalCommand handles "gcal login", which connects a Google account for
+// [integrations.google_calendar] with the device flow.
+func gcalCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := configFlag(fs)
+	return func(args []string) error {
+		if len(args) == 0 || args[0] != "login" {
+			fs.Usage()
+			os.Exit(2)
+		}
+		_ = fs.Parse(args[1:])
+
+		f, err := loadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		cc := f.Integrations.Calendar
+		if cc == nil {
+			return errors.New("google calendar: add an [integrations.google_calendar] section first")
+		}
+		client, err := calendarClient(cc.ClientID, cc.ClientSecret)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		dc, err := client.Auth.Start(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Visit %s and enter the code %s\n", dc.VerificationURL, dc.UserCode)
+		_ = openBrowser(dc.VerificationURL)
+		token, err := client.Auth.Poll(ctx, dc)
+		if err != nil {
+			return err
+		}
+		if err := gcal.SaveToken(client.TokenPath, token); err != nil {
+			return err
+		}
+		fmt.Println("Logged in; token saved to", client.TokenPath)
+		return nil
+	}
+}
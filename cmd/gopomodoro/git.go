@@ -0,0 +1,162 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/git"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// gitCommand handles "git install", which adds commit hooks to a
+// repository, "git uninstall", "git stats" and "git hook", which the
+// hooks run.
+func gitCommand(fs *flag.FlagSet) func(args []string) error {
+	openHistory := historyFlag(fs)
+	since := sinceFlag(fs)
+	trailer := fs.Bool("trailer", true, "install: add a \"Pomodoros: N\" trailer with the pomodoros since the last commit")
+	record := fs.Bool("record", true, "install: record each commit for git stats")
+	return func(args []string) error {
+		rest := parseInterspersed(fs, args)
+		if len(rest) == 0 {
+			fs.Usage()
+			os.Exit(2)
+		}
+		action, rest := rest[0], rest[1:]
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		journal := git.JournalPath(store.Path())
+		ctx := context.Background()
+
+		switch action {
+		case "install", "uninstall":
+			repo := &git.Repo{}
+			if len(rest) > 0 {
+				repo.Dir = rest[0]
+			}
+			dir, err := repo.HooksDir(ctx)
+			if err != nil {
+				return err
+			}
+			if action == "uninstall" {
+				removed, err := git.Uninstall(dir)
+				if len(removed) > 0 {
+					fmt.Printf("removed %s from %s\n", strings.Join(removed, ", "), dir)
+				}
+				return err
+			}
+			var hooks []string
+			if *trailer {
+				hooks = append(hooks, git.PrepareHook)
+			}
+			if *record {
+				hooks = append(hooks, git.CommitHook)
+			}
+			if len(hooks) == 0 {
+				return fmt.Errorf("git install: nothing to install with -trailer=false and -record=false")
+			}
+			exe, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			command := []string{exe, "git"}
+			fs.Visit(func(f *flag.Flag) {
+				// the hooks must find the same history
+				if f.Name == "history" {
+					path, _ := filepath.Abs(store.Path())
+					command = append(command, "-history", path)
+				}
+			})
+			if err := git.Install(dir, append(command, "hook"), hooks...); err != nil {
+				return err
+			}
+			fmt.Printf("installed %s in %s\n", strings.Join(hooks, ", "), dir)
+			return nil
+		case "hook":
+			if len(rest) == 0 {
+				return fmt.Errorf("git hook: no hook name")
+			}
+			return runGitHook(ctx, rest[0], rest[1:], store.List, journal)
+		case "stats":
+			from, err := since()
+			if err != nil {
+				return err
+			}
+			sessions, err := store.List()
+			if err != nil {
+				return err
+			}
+			commits, err := git.Commits(journal)
+			if err != nil {
+				return err
+			}
+			var recent []git.Commit
+			for _, c := range commits {
+				if !c.At.Before(from) {
+					recent = append(recent, c)
+				}
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			fmt.Fprintln(w, "Repository\tPomodoros\tFocus\tCommits")
+			for _, rs := range git.ByRepo(stats.Filter(sessions, from), recent) {
+				fmt.Fprintf(w, "%s\t%d\t%s\t%d\n", rs.Repo, rs.Pomodoros, rs.Focus.Round(time.Second), rs.Commits)
+			}
+			return w.Flush()
+		}
+		fs.Usage()
+		return fmt.Errorf("git: unknown action %q", action)
+	}
+}
+
+// runGitHook is what the installed hooks run, with git's arguments.
+func runGitHook(ctx context.Context, name string, args []string, list func() ([]history.Session, error), journal string) error {
+	repo := &git.Repo{}
+	switch name {
+	case git.PrepareHook:
+		// args are the message file, its source and for amends a commit
+		if len(args) == 0 {
+			return fmt.Errorf("%s: no message file", name)
+		}
+		if len(args) > 1 && (args[1] == "merge" || args[1] == "squash" || args[1] == "commit") {
+			return nil
+		}
+		_, last, err := repo.Head(ctx)
+		if err != nil {
+			return err
+		}
+		if last.IsZero() {
+			// the first commit counts today's pomodoros
+			y, m, d := time.Now().Date()
+			last = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		}
+		sessions, err := list()
+		if err != nil {
+			return err
+		}
+		n := git.Pomodoros(sessions, last)
+		if n == 0 {
+			return nil
+		}
+		return repo.AddTrailer(ctx, args[0], n)
+	case git.CommitHook:
+		hash, at, err := repo.Head(ctx)
+		if err != nil || hash == "" {
+			return err
+		}
+		root, err := repo.Root(ctx)
+		if err != nil {
+			return err
+		}
+		return git.Record(journal, git.Commit{Hash: hash, Repo: root, At: at})
+	}
+	return fmt.Errorf("git hook: unknown hook %q", name)
+}
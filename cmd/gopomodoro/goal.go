@@ -0,0 +1,112 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/i18n"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+	"github.com/ezchuang/GoPomodoro/internal/summary"
+)
+
+// dailyGoal seeds a goal tracker from today's history and celebrates
+// through notifier once the target is reached.
+func dailyGoal(target int, store *history.Store, notifier notify.Notifier, loc *i18n.Locale) (*stats.Goal, error) {
+	sessions, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	return stats.NewGoal(target, sessions, func(done, target int) {
+		body := loc.Sprintf("🎉 Daily goal reached: %d/%d pomodoros today!", done, target)
+		_ = notifier.Notify("GoPomodoro", body)
+	}), nil
+}
+
+// watchSummary delivers the [summary] end-of-day report daily until ctx
+// is done. The returned function delivers it once more when on_shutdown
+// is set; call it after the last session has been recorded.
+func watchSummary(ctx context.Context, f *config.File, store *history.Store, notifier notify.Notifier, onErr func(error)) (func(), error) {
+	sc := f.Summary
+	if sc == nil {
+		return func() {}, nil
+	}
+	if onErr == nil {
+		onErr = func(error) {}
+	}
+	opts := summary.Options{
+		At:      sc.At,
+		Path:    sc.Path,
+		Goal:    f.Goal,
+		Top:     sc.Top,
+		List:    store.List,
+		OnError: onErr,
+	}
+	if sc.NotifyEnabled() {
+		opts.Notify = func(body string) error {
+			return notify.Send(notifier, notify.Message{Title: "GoPomodoro · End of day", Body: body})
+		}
+	}
+	s, err := summary.New(opts)
+	if err != nil {
+		return nil, err
+	}
+	go s.Run(ctx)
+	if !sc.OnShutdown {
+		return func() {}, nil
+	}
+	return func() {
+		if err := s.Deliver(time.Now()); err != nil {
+			onErr(err)
+		}
+	}, nil
+}
+
+// weeklyDigest builds the [weekly_email] digest; without the section
+// it reports an error, for "stats -email".
+func weeklyDigest(f *config.File, store *history.Store, onErr func(error)) (*summary.Weekly, error) {
+	wc := f.WeeklyEmail
+	if wc == nil {
+		return nil, errors.New("no [weekly_email] in the config")
+	}
+	if wc.SMTP == "" {
+		return nil, errors.New("weekly email: smtp is required")
+	}
+	if len(wc.To) == 0 {
+		return nil, errors.New("weekly email: to is required")
+	}
+	mailer := summary.Mailer{
+		Addr:     wc.SMTP,
+		Username: wc.Username,
+		Password: cmp.Or(wc.Password, os.Getenv("SMTP_PASSWORD")),
+		From:     cmp.Or(wc.From, wc.To[0]),
+		To:       wc.To,
+	}
+	return summary.NewWeekly(summary.WeeklyOptions{
+		At:      wc.At,
+		Send:    mailer.Send,
+		Goal:    f.Goal,
+		Top:     wc.Top,
+		List:    store.List,
+		OnError: onErr,
+	})
+}
+
+// watchWeekly mails the [weekly_email] digest every week until ctx is
+// done.
+func watchWeekly(ctx context.Context, f *config.File, store *history.Store, onErr func(error)) error {
+	if f.WeeklyEmail == nil {
+		return nil
+	}
+	w, err := weeklyDigest(f, store, onErr)
+	if err != nil {
+		return err
+	}
+	go w.Run(ctx)
+	return nil
+}
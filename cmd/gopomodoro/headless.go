@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"log"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/logging"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+	"github.com/ezchuang/GoPomodoro/internal/suggest"
+)
+
+// runHeadless wires history, integrations, idle detection and
+// notifications to an engine that runs without the TUI, logging errors.
+// name is the command's, for its journal. cleanup unsubscribes
+// everything; stop the engine before calling it so the last phase is
+// recorded.
+func runHeadless(ctx context.Context, name string, engine *core.PomodoroEngine, res resolved, store *history.Store, notifier notify.Notifier) (cleanup func(), err error) {
+	var cancels []func()
+	cleanup = func() {
+		for i := len(cancels) - 1; i >= 0; i-- {
+			cancels[i]()
+		}
+	}
+
+	// the command made its logger slog's default
+	cancels = append(cancels, engine.Subscribe(logging.Events(slog.Default())))
+
+	recorder := history.NewRecorder(store, func(err error) {
+		log.Printf("history: %v", err)
+	})
+	recovery := recoverJournal(recorder, name, log.Printf)
+	sinks, closeSinks, err := sessionSinks(res.file, store, func(line string) {
+		log.Print(line)
+	}, func(err error) {
+		log.Printf("log: %v", err)
+	})
+	if err != nil {
+		return nil, err
+	}
+	for _, sink := range sinks {
+		recorder.OnSession(sink)
+	}
+	cancels = append(cancels, closeSinks, engine.Subscribe(recorder.Handle))
+
+	loc := locale(res.file)
+	// the notifications below feed the goal, so they see its count
+	goal, err := dailyGoal(res.file.Goal, store, notifier, loc)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	templates, err := notifyTemplates(res.file, engine)
+	if err != nil {
+		cleanup()
+		return nil, err
+	}
+	cancels = append(cancels, subscribeIntegrations(engine, res.file, store, func(err error) {
+		log.Printf("integration: %v", err)
+	}))
+	if err := watchIdle(ctx, engine, res.file, func(err error) {
+		log.Printf("idle: %v", err)
+	}); err != nil {
+		log.Printf("idle detection disabled: %v", err)
+	}
+	if cancel, err := watchDND(engine, res.file, func(err error) {
+		log.Printf("dnd: %v", err)
+	}); err != nil {
+		log.Printf("do not disturb disabled: %v", err)
+	} else {
+		cancels = append(cancels, cancel)
+	}
+	if cancel, err := watchEnforce(engine, res.file, false, func(err error) {
+		log.Printf("enforce: %v", err)
+	}); err != nil {
+		log.Printf("break enforcement disabled: %v", err)
+	} else {
+		cancels = append(cancels, cancel)
+	}
+	if cancel, err := watchBlock(engine, res.file, func(err error) {
+		log.Printf("blocker: %v", err)
+	}); err != nil {
+		log.Printf("site blocking disabled: %v", err)
+	} else {
+		cancels = append(cancels, cancel)
+	}
+	if cancel, err := watchMeetings(ctx, engine, res.file, notifier, func(err error) {
+		log.Printf("meetings: %v", err)
+	}); err != nil {
+		log.Printf("meeting checks disabled: %v", err)
+	} else {
+		cancels = append(cancels, cancel)
+	}
+	if final, err := watchSummary(ctx, res.file, store, notifier, func(err error) {
+		log.Printf("summary: %v", err)
+	}); err != nil {
+		log.Printf("end-of-day summary disabled: %v", err)
+	} else {
+		// first in, so it runs last: after the recorder saved the final phase
+		cancels = append([]func(){final}, cancels...)
+	}
+	if err := watchWeekly(ctx, res.file, store, func(err error) {
+		log.Printf("weekly email: %v", err)
+	}); err != nil {
+		log.Printf("weekly email disabled: %v", err)
+	}
+	if err := watchSync(ctx, res.file, store, func(err error) {
+		log.Printf("sync: %v", err)
+	}); err != nil {
+		log.Printf("history sync disabled: %v", err)
+	}
+	if cancel, err := watchMQTT(ctx, engine, res.file, func(err error) {
+		log.Printf("mqtt: %v", err)
+	}); err != nil {
+		log.Printf("mqtt disabled: %v", err)
+	} else {
+		cancels = append(cancels, cancel)
+	}
+	if cancel, err := watchTelegram(ctx, engine, res.file, func(err error) {
+		log.Printf("telegram: %v", err)
+	}); err != nil {
+		log.Printf("telegram commands disabled: %v", err)
+	} else {
+		cancels = append(cancels, cancel)
+	}
+	if _, cancel, err := watchLeaderboard(ctx, engine, res.file, store, false, func(err error) {
+		log.Printf("leaderboard: %v", err)
+	}); err != nil {
+		log.Printf("leaderboard disabled: %v", err)
+	} else {
+		cancels = append(cancels, cancel)
+	}
+
+	// a reload or the schedule may switch profiles, and with them the
+	// notification settings
+	var profile atomic.Pointer[config.Profile]
+	profile.Store(&res.profile)
+	if err := followConfig(ctx, engine, res, func(res resolved) {
+		log.Printf("config: profile %s from the next phase", res.profileName)
+		profile.Store(&res.profile)
+	}, func(err error) {
+		log.Printf("config: %v", err)
+	}); err != nil {
+		log.Printf("config reload disabled: %v", err)
+	}
+
+	var tips *suggest.Suggester
+	if sc := res.file.Suggestions; sc.On() {
+		tips = suggest.New(sc.Short, sc.Long)
+	}
+	// like the notifier's, the quiet hours are the ones at startup
+	quiet := res.file.Notify.Quiet
+	cancels = append(cancels, engine.Subscribe(func(ev core.Event) {
+		goal.Handle(ev)
+		prof := profile.Load()
+		if !prof.NotificationsEnabled() {
+			slog.Debug("notifications are off for the profile", "event", ev.Kind.String())
+			return
+		}
+		var body string
+		switch ev.Kind {
+		case core.EventAdvance:
+			body = loc.Sprintf("Phase: %s", loc.Name(ev.State))
+			if tip := tips.For(ev.State); tip != "" {
+				body += "\n" + tip
+			}
+		case core.EventWarning:
+			body = notify.WarningBody(loc, ev)
+			if prof.WarningSound && !quiet.Covers(ev.At) {
+				_ = notify.Beep()
+			}
+		case core.EventOvertime:
+			body = loc.T("Work done, overtime running")
+		case core.EventRefused:
+			body = ev.Refusal.Error()
+		case core.EventStop:
+			switch {
+			case ev.QuittingTime:
+				body = loc.T("Quitting time, done for today")
+			case ev.Finished:
+				body = notify.FinishedBody(loc, engine.Config().Cycles)
+			default:
+				return
+			}
+		default:
+			return
+		}
+		msg := notify.Message{
+			Title:   "GoPomodoro",
+			Body:    body,
+			Event:   ev,
+			Actions: notify.PhaseActions(engine, ev),
+		}
+		done, target := goal.Progress()
+		msg, err := templates.Apply(msg, done, target)
+		if err != nil {
+			log.Printf("notify: %v", err)
+		}
+		if err := notify.Send(notifier, msg); err != nil {
+			log.Printf("notify: %v", err)
+		}
+	}))
+	recovery.Restore(engine)
+	return cleanup, nil
+}
+
+// recoverJournal turns on recorder's journal for the named command,
+// picking up the phase a killed process left there, and logs what
+// became of it with logf. Restore the result into the engine once
+// everything is subscribed to it.
+func recoverJournal(recorder *history.Recorder, name string, logf func(format string, v ...any)) history.Recovery {
+	rec, err := recorder.Recover(name, time.Now())
+	switch {
+	case err != nil:
+		logf("journal: %v", err)
+	case rec.Resume:
+		logf("journal: resuming the %s phase the last run left", rec.State.Name())
+	case rec.Session != nil:
+		status := "abandoned"
+		if rec.Session.Completed {
+			status = "completed"
+		}
+		logf("journal: recorded the %s phase the last run left as %s", rec.Session.Phase, status)
+	}
+	return rec
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"cmp"
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// historyCommand lists, edits and deletes stored sessions. Edits and
+// deletions are journaled beside the history file.
+func historyCommand(fs *flag.FlagSet) func(args []string) error {
+	openHistory := historyFlag(fs)
+	since := sinceFlag(fs)
+	task := fs.String("task", "", `edit: the session's task title; "-" detaches it`)
+	tags := fs.String("tags", "", `edit: the session's tags, e.g. "#client-a #coding"; "-" clears them`)
+	status := fs.String("status", "", "edit: completed, abandoned or incomplete")
+	start := fs.String("start", "", `edit: start time, e.g. "2025-05-01 09:00" or RFC 3339`)
+	end := fs.String("end", "", "edit: end time, like -start")
+	return func(args []string) error {
+		// flags may come before or after the action and id
+		var action string
+		rest := parseInterspersed(fs, args)
+		if len(rest) > 0 {
+			action, rest = rest[0], rest[1:]
+		}
+
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		switch action {
+		case "", "list":
+			from, err := since()
+			if err != nil {
+				return err
+			}
+			sessions, err := store.List()
+			if err != nil {
+				return err
+			}
+			return printSessions(stats.Filter(sessions, from))
+		case "audit":
+			changes, err := store.Changes()
+			if err != nil {
+				return err
+			}
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, c := range changes {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.At.Local().Format(time.DateTime), c.Action, c.Before.ID, describeSession(c.Before))
+				if c.After != nil {
+					fmt.Fprintf(w, "\t\t\t→ %s\n", describeSession(*c.After))
+				}
+			}
+			return w.Flush()
+		case "edit", "delete":
+			if len(rest) != 1 {
+				fs.Usage()
+				return fmt.Errorf("history %s: need one session id", action)
+			}
+		default:
+			fs.Usage()
+			return fmt.Errorf("history: unknown action %q", action)
+		}
+
+		id := rest[0]
+		if action == "delete" {
+			if err := store.Delete(id); err != nil {
+				return err
+			}
+			fmt.Println("deleted", id)
+			return nil
+		}
+		sessions, err := store.List()
+		if err != nil {
+			return err
+		}
+		var sess *history.Session
+		for i := range sessions {
+			if sessions[i].ID == id {
+				sess = &sessions[i]
+			}
+		}
+		if sess == nil {
+			return fmt.Errorf("%w: %q", history.ErrNotFound, id)
+		}
+		if err := editSession(sess, *task, *tags, *status, *start, *end); err != nil {
+			return err
+		}
+		if err := store.Update(*sess); err != nil {
+			return err
+		}
+		fmt.Println(describeSession(*sess))
+		return nil
+	}
+}
+
+// parseInterspersed parses args allowing flags between the positional
+// arguments, which it returns.
+func parseInterspersed(fs *flag.FlagSet, args []string) []string {
+	var pos []string
+	for {
+		_ = fs.Parse(args)
+		if fs.NArg() == 0 {
+			return pos
+		}
+		pos = append(pos, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// editSession applies the history edit flags to s; empty ones leave
+// their field alone.
+func editSession(s *history.Session, task, tags, status, start, end string) error {
+	switch task {
+	case "":
+	case "-":
+		s.Task = nil
+	default:
+		t := history.Task{Title: task}
+		if s.Task != nil {
+			t = *s.Task
+			t.Title = task
+		}
+		s.Task = &t
+	}
+	switch tags {
+	case "":
+	case "-":
+		s.Tags = nil
+	default:
+		s.Tags = core.ParseTags(tags)
+	}
+	switch status {
+	case "":
+	case "completed", "abandoned", "incomplete":
+		s.Completed, s.Abandoned = status == "completed", status == "abandoned"
+	default:
+		return fmt.Errorf("history edit: unknown status %q", status)
+	}
+	for _, f := range []struct {
+		s   string
+		dst *time.Time
+	}{{start, &s.Start}, {end, &s.End}} {
+		if f.s == "" {
+			continue
+		}
+		t, err := parseTime(f.s)
+		if err != nil {
+			return fmt.Errorf("history edit: %w", err)
+		}
+		*f.dst = t
+	}
+	if s.End.Before(s.Start) {
+		return fmt.Errorf("history edit: session would end before it starts")
+	}
+	return nil
+}
+
+// parseTime reads an RFC 3339 time or a local "2006-01-02 15:04[:05]".
+func parseTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{time.DateTime, "2006-01-02 15:04"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", s)
+}
+
+// printSessions lists sessions one per line with their IDs.
+func printSessions(sessions []history.Session) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tStart\tPhase\tActive\tStatus\tTask\tTags")
+	for _, s := range sessions {
+		task := ""
+		if s.Task != nil {
+			task = s.Task.Title
+		}
+		status := s.Status()
+		if s.AbandonReason != "" {
+			status += fmt.Sprintf(" (%s)", s.AbandonReason)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", s.ID, s.Start.Local().Format("2006-01-02 15:04"),
+			cmp.Or(s.Name, s.Phase), s.Active().Round(time.Second), status, task, core.FormatTags(s.Tags))
+	}
+	return w.Flush()
+}
+
+// describeSession is a one-line summary of s for the audit log.
+func describeSession(s history.Session) string {
+	line := fmt.Sprintf("%s %s %s %s", cmp.Or(s.Name, s.Phase), s.Start.Local().Format("2006-01-02 15:04"),
+		s.Active().Round(time.Second), s.Status())
+	if s.Task != nil {
+		line += fmt.Sprintf(" %q", s.Task.Title)
+	}
+	if len(s.Tags) > 0 {
+		line += " " + core.FormatTags(s.Tags)
+	}
+	return line
+}
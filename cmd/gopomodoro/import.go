@@ -0,0 +1,67 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// importCommand adds sessions exported by another Pomodoro app to the
+// history file.
+func importCommand(fs *flag.FlagSet) func(args []string) error {
+	openHistory := historyFlag(fs)
+	format := fs.String("format", "csv", "input format: "+strings.Join(history.ImportFormats, ", "))
+	mapFlag := fs.String("map", "", "CSV column mapping, e.g. start=Begin,end=Finish,phase=Type")
+	dryRun := fs.Bool("dry-run", false, "parse and count sessions without writing them")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		if fs.NArg() == 0 {
+			fs.Usage()
+			os.Exit(2)
+		}
+
+		mapping, err := history.ParseMapping(*mapFlag)
+		if err != nil {
+			return err
+		}
+		var sessions []history.Session
+		for _, name := range fs.Args() {
+			got, err := importFile(name, *format, mapping)
+			if err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+			sessions = append(sessions, got...)
+		}
+		if *dryRun {
+			fmt.Printf("Read %d sessions.\n", len(sessions))
+			return nil
+		}
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		added, err := store.Import(sessions)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Imported %d sessions (%d already in history).\n", added, len(sessions)-added)
+		return nil
+	}
+}
+
+func importFile(name, format string, mapping history.Mapping) ([]history.Session, error) {
+	var r io.Reader = os.Stdin
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		r = f
+	}
+	return history.Import(r, format, mapping)
+}
@@ -0,0 +1,582 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/blocker"
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/dailylog"
+	"github.com/ezchuang/GoPomodoro/internal/dnd"
+	"github.com/ezchuang/GoPomodoro/internal/enforce"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/idle"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/gcal"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/github"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/jira"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/media"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/mqtt"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/slack"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/spotify"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/taskfile"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/taskwarrior"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/telegram"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/todoist"
+	"github.com/ezchuang/GoPomodoro/internal/meetings"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+	"github.com/ezchuang/GoPomodoro/internal/ui"
+)
+
+// subscribeIntegrations hooks the integrations enabled in f up to
+// engine; store provides past pomodoros per task. The returned func
+// unsubscribes them all.
+func subscribeIntegrations(engine *core.PomodoroEngine, f *config.File, store *history.Store, onErr func(error)) func() {
+	var cancels []func()
+	if sc := f.Integrations.Slack; sc != nil {
+		if token := cmp.Or(sc.Token, os.Getenv("SLACK_TOKEN")); token != "" {
+			ss := slack.NewStatusSync(slack.NewClient(token), slack.Options{
+				Emoji:   sc.Emoji,
+				Text:    sc.Text,
+				DND:     sc.DND == nil || *sc.DND,
+				OnError: onErr,
+			})
+			cancels = append(cancels, engine.Subscribe(ss.Handle))
+		}
+	}
+	if tw := f.Integrations.Taskwarrior; tw != nil && (tw.Track == nil || *tw.Track) {
+		tr := taskwarrior.NewTracker(taskwarriorClient(tw), taskwarrior.Options{
+			UDA:     tw.UDA,
+			OnError: onErr,
+		})
+		cancels = append(cancels, engine.Subscribe(tr.Handle))
+	}
+	if td := f.Integrations.Todoist; td != nil && (td.Comment || td.CompleteAfter > 0) {
+		if client := todoistClient(td); client != nil {
+			tr := todoist.NewTracker(client, todoist.Options{
+				Comment:       td.Comment,
+				CompleteAfter: td.CompleteAfter,
+				Done:          pomodorosPerTask(store, todoist.Source, onErr),
+				OnComplete: func(t core.Task) {
+					// a finished task shouldn't collect more pomodoros
+					if engine.State().Task == t {
+						engine.SetTask(core.Task{})
+					}
+				},
+				OnError: onErr,
+			})
+			cancels = append(cancels, engine.Subscribe(tr.Handle))
+		}
+	}
+	if tf := f.Integrations.TaskFile; tf != nil {
+		if file, err := taskfile.New(tf.Path); err != nil {
+			if onErr != nil {
+				onErr(err)
+			}
+		} else {
+			cancels = append(cancels, engine.Subscribe(taskfile.NewTracker(file, onErr).Handle))
+		}
+	}
+	if cc := f.Integrations.Calendar; cc != nil {
+		client, err := calendarClient(cc.ClientID, cc.ClientSecret)
+		if err != nil {
+			if onErr != nil {
+				onErr(err)
+			}
+		} else {
+			client.Calendar = cc.Calendar
+			tr := gcal.NewTracker(client, gcal.Options{
+				Summary:  cc.Summary,
+				ShowTask: cc.ShowTask,
+				OnError:  onErr,
+			})
+			cancels = append(cancels, engine.Subscribe(tr.Handle))
+		}
+	}
+	var players []func() (media.Player, *config.MediaActions, error)
+	if mc := f.Integrations.Media; mc != nil {
+		players = append(players, func() (media.Player, *config.MediaActions, error) {
+			p, err := media.New(mc.Player)
+			return p, &mc.MediaActions, err
+		})
+	}
+	if sc := f.Integrations.Spotify; sc != nil {
+		players = append(players, func() (media.Player, *config.MediaActions, error) {
+			p, err := spotifyPlayer(sc)
+			return p, &sc.MediaActions, err
+		})
+	}
+	for _, open := range players {
+		player, actions, err := open()
+		var s *media.Sync
+		if err == nil {
+			s, err = mediaSync(player, actions, onErr)
+		}
+		if err != nil {
+			if onErr != nil {
+				onErr(err)
+			}
+			continue
+		}
+		cancels = append(cancels, engine.Subscribe(s.Handle))
+	}
+	return func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+	}
+}
+
+// mediaSync drives player as configured by mc.
+func mediaSync(player media.Player, mc *config.MediaActions, onErr func(error)) (*media.Sync, error) {
+	opts := media.Options{Playlist: mc.Playlist, OnError: onErr}
+	for _, a := range []struct {
+		dst *media.Action
+		s   string
+		def media.Action
+	}{
+		{&opts.Work, mc.Work, media.Play},
+		{&opts.ShortBreak, mc.ShortBreak, media.Pause},
+		{&opts.LongBreak, mc.LongBreak, media.Pause},
+		{&opts.Stop, mc.Stop, media.None},
+	} {
+		var err error
+		if *a.dst, err = media.ParseAction(a.s, a.def); err != nil {
+			return nil, err
+		}
+	}
+	return media.NewSync(player, opts), nil
+}
+
+// spotifyPlayer returns the [integrations.spotify] player.
+func spotifyPlayer(sc *config.Spotify) (*spotify.Player, error) {
+	client, err := spotifyClient(sc.ClientID)
+	if err != nil {
+		return nil, err
+	}
+	return &spotify.Player{Client: client, Device: sc.Device}, nil
+}
+
+// spotifyClient returns a client using the token saved by "spotify
+// login".
+func spotifyClient(clientID string) (*spotify.Client, error) {
+	clientID = cmp.Or(clientID, os.Getenv("SPOTIFY_CLIENT_ID"))
+	if clientID == "" {
+		return nil, errors.New("spotify: client_id is required")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return spotify.NewClient(clientID, filepath.Join(dir, "gopomodoro", "spotify-token.json")), nil
+}
+
+// calendarClient returns a client using the token saved by "gcal
+// login".
+func calendarClient(clientID, clientSecret string) (*gcal.Client, error) {
+	clientID = cmp.Or(clientID, os.Getenv("GOOGLE_CLIENT_ID"))
+	clientSecret = cmp.Or(clientSecret, os.Getenv("GOOGLE_CLIENT_SECRET"))
+	if clientID == "" {
+		return nil, errors.New("google calendar: client_id is required")
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return nil, err
+	}
+	return gcal.NewClient(clientID, clientSecret, filepath.Join(dir, "gopomodoro", "google-token.json")), nil
+}
+
+// sessionSinks returns the consumers of finished sessions enabled in f,
+// for history.Recorder.OnSession; closeSinks flushes those that batch
+// or report in the background and must run after the recorder is
+// unsubscribed. logf receives what the sinks report, such as Jira dry
+// runs, and may be nil.
+func sessionSinks(f *config.File, store *history.Store, logf func(string), onErr func(error)) (sinks []func(history.Session), closeSinks func(), err error) {
+	var closers []func()
+	closeSinks = func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+	if lc := f.Log; lc != nil {
+		w, err := dailylog.New(dailylog.Options{
+			Path:      lc.Path,
+			Line:      lc.Template,
+			Abandoned: lc.Abandoned,
+			OnError:   onErr,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, w.Handle)
+	}
+	if oc := f.Integrations.Obsidian; oc != nil {
+		n, err := dailylog.NewNote(dailylog.NoteOptions{
+			Vault:      oc.Vault,
+			Folder:     oc.Folder,
+			DateFormat: oc.DateFormat,
+			Heading:    oc.Heading,
+			Line:       oc.Template,
+			List:       store.List,
+			OnError:    onErr,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, n.Handle)
+	}
+	if jc := f.Integrations.Jira; jc != nil {
+		l, err := jiraLogger(jc, store, logf, onErr)
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, l.Handle)
+		closers = append(closers, l.Close)
+	}
+	if gc := f.Integrations.GitHub; gc != nil && (gc.Comment || gc.Field != "") {
+		r, err := github.NewReporter(githubClient(gc), github.Options{
+			Comment: gc.Comment,
+			Field:   gc.Field,
+			Unit:    gc.Unit,
+			Done:    pomodorosPerTask(store, github.Source, onErr),
+			OnError: onErr,
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+		sinks = append(sinks, r.Handle)
+		closers = append(closers, r.Close)
+	}
+	return sinks, closeSinks, nil
+}
+
+// jiraLogger returns the [integrations.jira] worklog logger.
+func jiraLogger(jc *config.Jira, store *history.Store, logf func(string), onErr func(error)) (*jira.Logger, error) {
+	token := cmp.Or(jc.Token, os.Getenv("JIRA_TOKEN"))
+	if jc.URL == "" || token == "" {
+		return nil, errors.New("jira: url and token are required")
+	}
+	return jira.NewLogger(&jira.Client{BaseURL: jc.URL, Email: jc.Email, Token: token}, jira.Options{
+		Journal: jira.JournalPath(store.Path()),
+		Comment: jc.Comment,
+		DryRun:  jc.DryRun,
+		Batch:   jc.Batch.Duration,
+		Log:     logf,
+		OnError: onErr,
+	}), nil
+}
+
+// taskSources returns the task managers enabled in f for the TUI's
+// task picker and panel.
+func taskSources(f *config.File) []ui.TaskSource {
+	var sources []ui.TaskSource
+	if tf := f.Integrations.TaskFile; tf != nil {
+		if file, err := taskfile.New(tf.Path); err == nil {
+			sources = append(sources, file)
+		}
+	}
+	if tw := f.Integrations.Taskwarrior; tw != nil {
+		sources = append(sources, taskwarriorClient(tw))
+	}
+	if td := f.Integrations.Todoist; td != nil {
+		if client := todoistClient(td); client != nil {
+			sources = append(sources, client)
+		}
+	}
+	if gc := f.Integrations.GitHub; gc != nil {
+		// listing assigned issues needs a token
+		if client := githubClient(gc); client.Token != "" {
+			sources = append(sources, client)
+		}
+	}
+	return sources
+}
+
+// githubClient returns a client authenticated with the [integrations.github]
+// token, if any.
+func githubClient(gc *config.GitHub) *github.Client {
+	var token string
+	if gc != nil {
+		token = gc.Token
+	}
+	return &github.Client{Token: cmp.Or(token, os.Getenv("GITHUB_TOKEN"))}
+}
+
+// issueTask titles the -issue task like the issue, when GitHub can be
+// reached in time.
+func issueTask(f *config.File, t core.Task) core.Task {
+	ref, err := github.ParseRef(t.ID)
+	if err != nil {
+		return t
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if it, err := githubClient(f.Integrations.GitHub).Issue(ctx, ref); err == nil {
+		return it
+	}
+	return t
+}
+
+// todoistClient returns nil without a token.
+func todoistClient(td *config.Todoist) *todoist.Client {
+	token := cmp.Or(td.Token, os.Getenv("TODOIST_TOKEN"))
+	if token == "" {
+		return nil
+	}
+	return todoist.NewClient(token, td.Filter)
+}
+
+// pomodorosPerTask counts completed work sessions per task ID from
+// source in the history.
+func pomodorosPerTask(store *history.Store, source string, onErr func(error)) map[string]int {
+	sessions, err := store.List()
+	if err != nil {
+		if onErr != nil {
+			onErr(err)
+		}
+		return nil
+	}
+	done := map[string]int{}
+	for _, s := range sessions {
+		if s.Completed && s.Task != nil && s.Task.Source == source {
+			done[s.Task.ID]++
+		}
+	}
+	return done
+}
+
+func taskwarriorClient(tw *config.Taskwarrior) *taskwarrior.Client {
+	c := taskwarrior.NewClient(tw.Filter)
+	c.Bin = tw.Command
+	return c
+}
+
+// watchDND turns Do Not Disturb on for work phases when [dnd] is
+// enabled. The returned func unsubscribes and turns it back off.
+func watchDND(engine *core.PomodoroEngine, f *config.File, onErr func(error)) (func(), error) {
+	if !f.DND.Enabled {
+		return func() {}, nil
+	}
+	var sw dnd.Switch = dnd.Commands{On: f.DND.On, Off: f.DND.Off}
+	if len(f.DND.On) == 0 && len(f.DND.Off) == 0 {
+		var err error
+		if sw, err = dnd.New(); err != nil {
+			return nil, err
+		}
+	}
+	s := dnd.NewSync(sw, onErr)
+	cancel := engine.Subscribe(s.Handle)
+	return func() {
+		cancel()
+		s.Close()
+	}, nil
+}
+
+// watchEnforce locks the screen at the start of breaks when [enforce]
+// asks for it. tui says whether the TUI shows its overlay, the other
+// mode. The returned func unsubscribes.
+func watchEnforce(engine *core.PomodoroEngine, f *config.File, tui bool, onErr func(error)) (func(), error) {
+	ec := f.Enforce
+	mode, err := enforce.ParseMode(ec.Mode)
+	if err != nil {
+		return nil, err
+	}
+	switch mode {
+	case enforce.Off:
+		return func() {}, nil
+	case enforce.Overlay:
+		if !tui {
+			return nil, errors.New("enforce: the overlay needs the TUI, use mode \"lock\"")
+		}
+		return func() {}, nil
+	}
+	var locker enforce.Locker = enforce.Command(ec.Command)
+	if len(ec.Command) == 0 {
+		if locker, err = enforce.NewLocker(); err != nil {
+			return nil, err
+		}
+	}
+	return engine.Subscribe(enforce.NewEnforcer(locker, ec.LongOnly, onErr).Handle), nil
+}
+
+// watchBlock blocks the [block] domains during work phases. A block left
+// behind by an earlier run is removed first. The returned func
+// unsubscribes and unblocks.
+func watchBlock(engine *core.PomodoroEngine, f *config.File, onErr func(error)) (func(), error) {
+	bc := f.Block
+	if !bc.Enabled || len(bc.Domains) == 0 {
+		return func() {}, nil
+	}
+	if _, err := blocker.Normalize(bc.Domains); err != nil {
+		return nil, err
+	}
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	b := &blocker.Blocker{
+		Helper:  []string{exe, "blocker"},
+		Path:    bc.Hosts,
+		Domains: bc.Domains,
+		Sudo:    bc.Sudo == nil || *bc.Sudo,
+	}
+	if err := b.Check(); err != nil {
+		return nil, err
+	}
+	if err := b.Restore(); err != nil && onErr != nil {
+		onErr(err)
+	}
+	// blocking follows the same on-while-working rule as Do Not Disturb
+	s := dnd.NewSync(b, onErr)
+	cancel := engine.Subscribe(s.Handle)
+	return func() {
+		cancel()
+		s.Close()
+	}, nil
+}
+
+// watchMeetings applies the [meetings] policy to pomodoros that would
+// run into a meeting, refreshing the calendar until ctx is done.
+// Heads-ups go to notifier.
+func watchMeetings(ctx context.Context, engine *core.PomodoroEngine, f *config.File, notifier notify.Notifier, onErr func(error)) (func(), error) {
+	mc := f.Meetings
+	if mc == nil || mc.URL == "" {
+		return func() {}, nil
+	}
+	policy, err := meetings.ParsePolicy(mc.Policy)
+	if err != nil {
+		return nil, err
+	}
+	p := meetings.NewPlanner(meetings.Options{
+		Policy:    policy,
+		Margin:    mc.Margin.Duration,
+		MinLength: mc.MinLength.Duration,
+		Notify: func(body string) {
+			if err := notify.Send(notifier, notify.Message{Title: "GoPomodoro", Body: body}); err != nil && onErr != nil {
+				onErr(err)
+			}
+		},
+	})
+	src := meetings.Source{
+		URL:      mc.URL,
+		CalDAV:   mc.CalDAV,
+		Username: mc.Username,
+		Password: cmp.Or(mc.Password, os.Getenv("MEETINGS_PASSWORD")),
+	}
+	go p.Run(ctx, src, mc.Refresh.Duration, onErr)
+	engine.SetStartCheck(p.Check)
+	cancel := engine.Subscribe(p.Handle)
+	return func() {
+		cancel()
+		engine.SetStartCheck(nil)
+	}, nil
+}
+
+// watchMQTT publishes the timer's state to the [integrations.mqtt]
+// broker until ctx is done.
+func watchMQTT(ctx context.Context, engine *core.PomodoroEngine, f *config.File, onErr func(error)) (func(), error) {
+	mc := f.Integrations.MQTT
+	if mc == nil {
+		return func() {}, nil
+	}
+	if mc.Broker == "" {
+		return nil, errors.New("mqtt: broker is required")
+	}
+	if mc.QoS < 0 || mc.QoS > 1 {
+		return nil, fmt.Errorf("mqtt: qos %d not supported (want 0 or 1)", mc.QoS)
+	}
+	topics := mqtt.DefaultTopics(cmp.Or(mc.Prefix, "gopomodoro"))
+	topics.State = cmp.Or(mc.Topics.State, topics.State)
+	topics.Phase = cmp.Or(mc.Topics.Phase, topics.Phase)
+	topics.Remaining = cmp.Or(mc.Topics.Remaining, topics.Remaining)
+	topics.Event = cmp.Or(mc.Topics.Event, topics.Event)
+	topics.Availability = cmp.Or(mc.Topics.Availability, topics.Availability)
+	topics.Command = mc.Topics.Command
+	var discovery *mqtt.Discovery
+	if mc.HomeAssistant {
+		discovery = &mqtt.Discovery{Prefix: mc.DiscoveryPrefix, NodeID: mc.ClientID}
+		topics.Command = cmp.Or(topics.Command, strings.TrimSuffix(cmp.Or(mc.Prefix, "gopomodoro"), "/")+"/command")
+	}
+	client := &mqtt.Client{
+		Broker:   mc.Broker,
+		Username: mc.Username,
+		Password: cmp.Or(mc.Password, os.Getenv("MQTT_PASSWORD")),
+		ClientID: mc.ClientID,
+	}
+	p := mqtt.NewPublisher(client, mqtt.Options{
+		Topics:    topics,
+		QoS:       byte(mc.QoS),
+		Interval:  mc.Interval.Duration,
+		Discovery: discovery,
+		OnError:   onErr,
+	})
+	ctx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Run(ctx, engine)
+	}()
+	cancel := engine.Subscribe(p.Handle)
+	return func() {
+		cancel()
+		stop()
+		<-done
+	}, nil
+}
+
+// watchTelegram takes timer commands from the [integrations.telegram]
+// bot's chats until the returned func is called.
+func watchTelegram(ctx context.Context, engine *core.PomodoroEngine, f *config.File, onErr func(error)) (func(), error) {
+	tg := f.Integrations.Telegram
+	if tg == nil || !tg.Controls() {
+		return func() {}, nil
+	}
+	token := cmp.Or(tg.Token, os.Getenv("TELEGRAM_TOKEN"))
+	if token == "" {
+		return nil, errors.New("telegram: token is required")
+	}
+	bot := telegram.NewBot(telegram.NewClient(token), engine, telegram.Options{
+		Chats:   tg.Chats,
+		OnError: onErr,
+	})
+	ctx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bot.Run(ctx)
+	}()
+	return func() {
+		stop()
+		<-done
+	}, nil
+}
+
+// watchIdle starts auto-pausing when [idle] is configured. It returns an
+// error if the settings are bad or idle time can't be measured here.
+func watchIdle(ctx context.Context, engine *core.PomodoroEngine, f *config.File, onErr func(error)) error {
+	if f.Idle.After.Duration <= 0 {
+		return nil
+	}
+	action, err := idle.ParseReturnAction(f.Idle.OnReturn)
+	if err != nil {
+		return err
+	}
+	det, err := idle.New()
+	if err != nil {
+		return err
+	}
+	w := idle.NewWatcher(det, engine, idle.Options{
+		After:    f.Idle.After.Duration,
+		Poll:     f.Idle.Poll.Duration,
+		OnReturn: action,
+		OnError:  onErr,
+	})
+	go w.Run(ctx)
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// jiraCommand handles "jira worklog", which submits the worklogs of past
+// sessions that weren't logged yet, e.g. from before [integrations.jira]
+// was set up or while Jira was unreachable.
+func jiraCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := configFlag(fs)
+	openHistory := historyFlag(fs)
+	since := sinceFlag(fs)
+	dryRun := fs.Bool("dry-run", false, "print the worklogs instead of submitting them")
+	return func(args []string) error {
+		if len(args) == 0 || args[0] != "worklog" {
+			fs.Usage()
+			os.Exit(2)
+		}
+		_ = fs.Parse(args[1:])
+
+		file, err := loadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		jc := file.Integrations.Jira
+		if jc == nil {
+			return errors.New("jira: no [integrations.jira] in the config")
+		}
+		from, err := since()
+		if err != nil {
+			return err
+		}
+		if from.IsZero() {
+			y, m, d := time.Now().Date()
+			from = time.Date(y, m, d, 0, 0, 0, 0, time.Local)
+		}
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		sessions, err := store.List()
+		if err != nil {
+			return err
+		}
+
+		opts := *jc
+		opts.DryRun = opts.DryRun || *dryRun
+		var errs []error
+		l, err := jiraLogger(&opts, store, func(line string) {
+			fmt.Println(line)
+		}, func(err error) {
+			errs = append(errs, err)
+		})
+		if err != nil {
+			return err
+		}
+		l.SubmitSessions(stats.Filter(sessions, from))
+		return errors.Join(errs...)
+	}
+}
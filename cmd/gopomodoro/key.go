@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// keyCommand manages the key file encrypting the history at rest:
+// status shows it, init encrypts the history with it (making one if
+// needed), rotate swaps it for a new one, keeping the old one for
+// decrypting, and decrypt goes back to the clear. The TUI, daemon and tray should be stopped meanwhile, as the
+// files are rewritten under them.
+func keyCommand(fs *flag.FlagSet) func(args []string) error {
+	openHistory := historyKeyFlags(fs)
+	return func(args []string) error {
+		var action string
+		rest := parseInterspersed(fs, args)
+		if len(rest) > 0 {
+			action = rest[0]
+		}
+		store, path, err := openHistory()
+		if err != nil {
+			return err
+		}
+		key, err := history.LoadKey(path)
+		if err != nil {
+			return err
+		}
+		switch action {
+		case "", "status":
+			return keyStatus(store, key, path)
+		case "init":
+			created := false
+			if key == nil {
+				if key, err = history.GenerateKey(); err != nil {
+					return err
+				}
+				if err := key.Save(path); err != nil {
+					return err
+				}
+				created = true
+			}
+			if err := store.Rekey(key); err != nil {
+				return err
+			}
+			fmt.Printf("history encrypted with key %s\n", key.ID())
+			if created {
+				fmt.Printf("new key file %s: back it up, as without it the history can't be read\n", path)
+			}
+			return nil
+		case "rotate":
+			if key == nil {
+				return fmt.Errorf("key: no key file at %s; run gopomodoro key init", path)
+			}
+			next, err := key.Rotate()
+			if err != nil {
+				return err
+			}
+			// the new key is saved before the history is rewritten with
+			// it, and kept if that fails, as some files may already be
+			// under it; so an interruption never leaves the history
+			// keyless
+			tmp := path + ".new"
+			if err := next.Save(tmp); err != nil {
+				return err
+			}
+			if err := store.Rekey(next); err != nil {
+				return fmt.Errorf("key: %w; files already rewritten need the new key %s, kept in %s beside the old one", err, next.ID(), tmp)
+			}
+			if err := os.Rename(tmp, path); err != nil {
+				return fmt.Errorf("key: history encrypted with the new key %s in %s, but it couldn't replace %s: %w", next.ID(), tmp, path, err)
+			}
+			fmt.Printf("history encrypted with key %s in place of %s, which %s keeps for reading what it encrypted; copy the file to your other machines\n", next.ID(), key.ID(), path)
+			return nil
+		case "decrypt":
+			if err := store.Rekey(nil); err != nil {
+				return err
+			}
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			fmt.Println("history decrypted; key file removed")
+			return nil
+		default:
+			fs.Usage()
+			return fmt.Errorf("key: unknown action %q", action)
+		}
+	}
+}
+
+// keyStatus prints the key file and whether the history is encrypted.
+func keyStatus(store *history.Store, key *history.Key, path string) error {
+	encrypted, err := store.Encrypted()
+	if err != nil {
+		return err
+	}
+	switch {
+	case key != nil && encrypted:
+		fmt.Printf("history encrypted with key %s from %s\n", key.ID(), path)
+	case key != nil:
+		fmt.Printf("key %s at %s encrypts new sessions; gopomodoro key init encrypts the rest\n", key.ID(), path)
+	case encrypted:
+		fmt.Printf("history encrypted, but there's no key file at %s\n", path)
+	default:
+		fmt.Println("history not encrypted")
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/leaderboard"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// defaultLeaderboardAddr is where the leaderboard server listens
+// unless told otherwise: on every interface, as the team reaches it
+// from their own machines.
+const defaultLeaderboardAddr = ":7770"
+
+// leaderboardCommand runs the team leaderboard server of the
+// [leaderboard.server] section until interrupted.
+func leaderboardCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := configFlag(fs)
+	listen := fs.String("listen", "", "address to serve the leaderboard on (default [leaderboard.server] listen, else "+defaultLeaderboardAddr+")")
+	data := fs.String("data", "", "file keeping the board (default [leaderboard.server] data, else leaderboard.json next to the history)")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		file, err := loadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		var sc config.LeaderboardServer
+		if lb := file.Leaderboard; lb != nil && lb.Server != nil {
+			sc = *lb.Server
+		}
+		if len(sc.Members) == 0 {
+			return errors.New("leaderboard: no members in [leaderboard.server]")
+		}
+		path := cmp.Or(*data, sc.Data)
+		if path == "" {
+			hist, err := history.DefaultPath()
+			if err != nil {
+				return err
+			}
+			path = filepath.Join(filepath.Dir(hist), "leaderboard.json")
+		}
+		board, err := leaderboard.NewServer(sc.Members, path)
+		if err != nil {
+			return err
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		addr := cmp.Or(*listen, sc.Listen, defaultLeaderboardAddr)
+		srv := &http.Server{Addr: addr, Handler: board, ReadHeaderTimeout: 10 * time.Second}
+		errc := make(chan error, 1)
+		go func() { errc <- srv.ListenAndServe() }()
+		log.Printf("leaderboard for %d members listening on %s", len(sc.Members), addr)
+		select {
+		case <-ctx.Done():
+		case err := <-errc:
+			return err
+		}
+		shutdown, done := context.WithTimeout(context.Background(), 2*time.Second)
+		defer done()
+		return srv.Shutdown(shutdown)
+	}
+}
+
+// watchLeaderboard reports today's pomodoros from store to the
+// [leaderboard] server until ctx is done, and with watch fetches the
+// board too. The reporter is nil without the section.
+func watchLeaderboard(ctx context.Context, engine *core.PomodoroEngine, f *config.File, store *history.Store, watch bool, onErr func(error)) (*leaderboard.Reporter, func(), error) {
+	lb := f.Leaderboard
+	if lb == nil || lb.URL == "" {
+		return nil, func() {}, nil
+	}
+	token := cmp.Or(lb.Token, os.Getenv("LEADERBOARD_TOKEN"))
+	if token == "" {
+		return nil, nil, errors.New("leaderboard: token is required")
+	}
+	r := leaderboard.NewReporter(&leaderboard.Client{URL: lb.URL, Token: token}, leaderboard.Options{
+		Totals: func(t time.Time) (leaderboard.Totals, error) {
+			sessions, err := store.List()
+			if err != nil {
+				return leaderboard.Totals{}, err
+			}
+			day := stats.Daily(sessions, t, 1)[0]
+			return leaderboard.NewTotals(day.Pomodoros, day.Focus), nil
+		},
+		Watch:   watch,
+		OnError: onErr,
+	})
+	unsubscribe := engine.Subscribe(r.Handle)
+	ctx, stop := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		r.Run(ctx)
+	}()
+	return r, func() {
+		unsubscribe()
+		stop()
+		<-done
+	}, nil
+}
@@ -1,38 +1,220 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"log"
-	"time"
+	"net/http"
+	"os"
 
+	"github.com/ezchuang/GoPomodoro/internal/config"
 	"github.com/ezchuang/GoPomodoro/internal/core"
-	"github.com/ezchuang/GoPomodoro/internal/notify"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/logging"
+	"github.com/ezchuang/GoPomodoro/internal/server"
 	"github.com/ezchuang/GoPomodoro/internal/ui"
 )
 
 func main() {
-	work := flag.Duration("work", 25*time.Minute, "work duration")
-	short := flag.Duration("short", 5*time.Minute, "short break duration")
-	long := flag.Duration("long", 15*time.Minute, "long break duration")
-	longEvery := flag.Int("long-every", 4, "take a long break every N pomodoros")
-	flag.Parse()
-
-	cfg := core.Config{
-		Work:      *work,
-		ShortBrk:  *short,
-		LongBrk:   *long,
-		LongEvery: *longEvery,
+	name, args := "", os.Args[1:]
+	if len(args) > 0 {
+		if _, ok := commands[args[0]]; ok {
+			name, args = args[0], args[1:]
+		}
 	}
+	if err := runCommand(name, args); err != nil {
+		// not log.Fatal: the command may have pointed log at its log file
+		fmt.Fprintln(os.Stderr, "gopomodoro:", err)
+		os.Exit(1)
+	}
+}
 
-	engine := core.New(cfg)
-	notifier := notify.New()
+// tuiCommand runs the timer in the terminal UI, the default command.
+// With -cycles, quitting before they are done is an error, so scripts
+// can tell the two apart.
+func tuiCommand(fs *flag.FlagSet) func(args []string) error {
+	ef := registerEngineFlags(fs)
+	openHistory := historyFlag(fs)
+	openLog := logFlags(fs)
+	theme := fs.String("theme", "", "TUI color theme (default, nord, dracula, solarized, mono or one from the config)")
+	plain := fs.Bool("plain", os.Getenv("TERM") == "dumb", "line-based output for screen readers and dumb terminals: no alt screen, colors or box drawing")
+	mini := fs.Bool("mini", false, "start in mini mode: a single line without the alt screen, for a small tmux pane (z toggles it)")
+	listen := fs.String("listen", "", "serve the HTTP/WebSocket API on this address (e.g. 127.0.0.1:7767)")
+	tf := registerTeamFlags(fs)
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		logger, closeLog, err := openLog(true)
+		if err != nil {
+			return err
+		}
+		defer closeLog()
 
-	m, err := ui.NewModel(engine, notifier)
-	if err != nil {
-		log.Fatal(err)
-	}
-	if err := ui.Run(m); err != nil {
-		fmt.Println("error:", err)
+		res, err := ef.resolve()
+		if err != nil {
+			return err
+		}
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		engine := core.New(res.engine)
+		res.attach(engine)
+		defer engine.Subscribe(logging.Events(logger))()
+		notifier, err := buildNotifier(res.file, logger)
+		if err != nil {
+			return err
+		}
+		defer tf.shareRooms(notifier, nil)()
+
+		// errors can't be printed over the alt screen; history is best effort
+		recorder := history.NewRecorder(store, nil)
+		recovery := recoverJournal(recorder, "tui", log.Printf)
+		sinks, closeSinks, err := sessionSinks(res.file, store, nil, nil)
+		if err != nil {
+			return err
+		}
+		for _, sink := range sinks {
+			recorder.OnSession(sink)
+		}
+		defer closeSinks()
+		defer engine.Subscribe(recorder.Handle)()
+
+		loc := locale(res.file)
+		goal, err := dailyGoal(res.file.Goal, store, notifier, loc)
+		if err != nil {
+			return err
+		}
+		templates, err := notifyTemplates(res.file, engine)
+		if err != nil {
+			return err
+		}
+		defer subscribeIntegrations(engine, res.file, store, nil)()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		if err := watchIdle(ctx, engine, res.file, nil); err != nil {
+			log.Printf("idle detection disabled: %v", err)
+		}
+		if cancel, err := watchDND(engine, res.file, nil); err != nil {
+			log.Printf("do not disturb disabled: %v", err)
+		} else {
+			defer cancel()
+		}
+		if cancel, err := watchEnforce(engine, res.file, true, nil); err != nil {
+			log.Printf("break enforcement disabled: %v", err)
+		} else {
+			defer cancel()
+		}
+		if cancel, err := watchBlock(engine, res.file, nil); err != nil {
+			log.Printf("site blocking disabled: %v", err)
+		} else {
+			defer cancel()
+		}
+		if cancel, err := watchMeetings(ctx, engine, res.file, notifier, nil); err != nil {
+			log.Printf("meeting checks disabled: %v", err)
+		} else {
+			defer cancel()
+		}
+		// the on_shutdown report is the daemon's; the TUI only schedules it
+		if _, err := watchSummary(ctx, res.file, store, notifier, nil); err != nil {
+			log.Printf("end-of-day summary disabled: %v", err)
+		}
+		if err := watchWeekly(ctx, res.file, store, nil); err != nil {
+			log.Printf("weekly email disabled: %v", err)
+		}
+		if err := watchSync(ctx, res.file, store, nil); err != nil {
+			log.Printf("history sync disabled: %v", err)
+		}
+		if cancel, err := watchMQTT(ctx, engine, res.file, nil); err != nil {
+			log.Printf("mqtt disabled: %v", err)
+		} else {
+			defer cancel()
+		}
+		if cancel, err := watchTelegram(ctx, engine, res.file, nil); err != nil {
+			log.Printf("telegram commands disabled: %v", err)
+		} else {
+			defer cancel()
+		}
+		var board ui.Leaderboard
+		if r, cancel, err := watchLeaderboard(ctx, engine, res.file, store, true, nil); err != nil {
+			log.Printf("leaderboard disabled: %v", err)
+		} else {
+			defer cancel()
+			if r != nil {
+				board = r
+			}
+		}
+		// quitting mid-phase records it as unfinished
+		defer engine.Stop()
+		recovery.Restore(engine)
+		timers, err := openTimers(engine, res)
+		if err != nil {
+			return err
+		}
+		defer notifyTimers(timers, notifier, loc, nil)()
+
+		var api *server.Server
+		if *listen != "" {
+			if api, err = newAPI(timers, res, store, notifier.Health); err != nil {
+				return err
+			}
+			srv := &http.Server{Addr: *listen, Handler: api}
+			go func() {
+				if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Printf("http server: %v", err)
+				}
+			}()
+			defer srv.Close()
+		}
+		members, cancelTeam, err := tf.start(ctx, engine, api, nil)
+		if err != nil {
+			return err
+		}
+		defer cancelTeam()
+
+		// the TUI adopts config changes itself, for the profile it shows
+		reloads := make(chan ui.Reload)
+		sendReload := func(r ui.Reload) {
+			select {
+			case reloads <- r:
+			case <-ctx.Done():
+			}
+		}
+		if err := watchConfig(ctx, res.path, func(f *config.File) {
+			sendReload(ui.Reload{Config: f})
+		}, func(err error) {
+			sendReload(ui.Reload{Err: err})
+		}); err != nil {
+			log.Printf("config reload disabled: %v", err)
+		}
+
+		m, err := ui.NewModel(engine, notifier, ui.Options{
+			Config:      res.file,
+			Profile:     res.profileName,
+			Scheduled:   res.scheduled,
+			Goal:        goal,
+			Templates:   templates,
+			Locale:      loc,
+			Plain:       *plain,
+			Mini:        *mini,
+			Theme:       *theme,
+			History:     store,
+			Tasks:       taskSources(res.file),
+			Timers:      timers,
+			Team:        members,
+			Leaderboard: board,
+			Reloads:     reloads,
+		})
+		if err != nil {
+			return err
+		}
+		if err := ui.Run(m); err != nil {
+			fmt.Println("error:", err)
+		}
+		if n := res.engine.Cycles; n > 0 && !m.Finished() {
+			return fmt.Errorf("quit before completing %d pomodoros", n)
+		}
+		return nil
 	}
 }
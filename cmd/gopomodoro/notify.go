@@ -0,0 +1,208 @@
+package main
+
+import (
+	"cmp"
+	"log/slog"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/integrations/telegram"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+)
+
+// buildNotifier returns the notification backends enabled in the config
+// file, each behind its own event filter and quiet mode, and the routes
+// between them, logging to
+// logger if non-nil. Unless the bell is on anyway, it rings when the
+// desktop fails, e.g. over SSH or without D-Bus.
+func buildNotifier(f *config.File, logger *slog.Logger) (*notify.Registry, error) {
+	nc := f.Notify
+	reg := notify.Registry{Logger: logger}
+	if nc.Quiet != nil {
+		reg.QuietHours = nc.Quiet.Covers
+	}
+	add := func(name string, b *config.NotifyBackend, n notify.Notifier) error {
+		var events []string
+		if b != nil {
+			events = b.Events
+		}
+		if err := reg.Add(name, n, events); err != nil {
+			return err
+		}
+		return reg.SetQuiet(name, nc.QuietMode(b))
+	}
+	if nc.Desktop.On(true) {
+		if err := add("desktop", nc.Desktop, notify.New()); err != nil {
+			return nil, err
+		}
+		if nc.Bell == nil || !nc.Bell.On(false) {
+			if err := reg.Fallback("desktop", "bell", notify.Bell{W: os.Stderr, Mode: notify.ModeBell}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if sc := nc.Sound; sc != nil && sc.On(false) {
+		if err := add("sound", &sc.NotifyBackend, notify.Sound{Command: sc.Command}); err != nil {
+			return nil, err
+		}
+	}
+	if bc := nc.Bell; bc != nil && bc.On(false) {
+		b := notify.Bell{W: os.Stderr, Mode: bc.Mode, Tmux: os.Getenv("TMUX") != ""}
+		if b.Mode == "" || b.Mode == "auto" {
+			b.Mode = notify.TerminalMode(os.Getenv)
+		}
+		if err := add("bell", &bc.NotifyBackend, b); err != nil {
+			return nil, err
+		}
+	}
+	if lc := nc.Log; lc != nil && lc.On(false) {
+		w, err := openNotifyLog(lc.Path)
+		if err != nil {
+			return nil, err
+		}
+		if err := add("log", &lc.NotifyBackend, &notify.Log{W: w}); err != nil {
+			return nil, err
+		}
+	}
+	if wh := nc.Webhook; wh != nil && wh.On(false) && len(wh.URLs) > 0 {
+		n := notify.NewWebhook(notify.WebhookOptions{
+			URLs:    wh.URLs,
+			Headers: wh.Headers,
+			Timeout: wh.Timeout.Duration,
+			Retries: wh.Retries,
+		})
+		if err := add("webhook", &wh.NotifyBackend, n); err != nil {
+			return nil, err
+		}
+	}
+	if sc := nc.Speech; sc != nil && sc.On(false) {
+		n, err := notify.NewSpeech(notify.SpeechOptions{
+			Command:  sc.Command,
+			Voice:    sc.Voice,
+			Rate:     sc.Rate,
+			Messages: sc.Messages,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := add("speech", &sc.NotifyBackend, n); err != nil {
+			return nil, err
+		}
+	}
+	if nt := nc.Ntfy; nt != nil && nt.On(false) {
+		n, err := notify.NewNtfy(notify.NtfyOptions{
+			Server:   nt.Server,
+			Topic:    nt.Topic,
+			Token:    cmp.Or(nt.Token, os.Getenv("NTFY_TOKEN")),
+			Priority: nt.Priority,
+			Timeout:  nt.Timeout.Duration,
+			Retries:  nt.Retries,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := add("ntfy", &nt.NotifyBackend, n); err != nil {
+			return nil, err
+		}
+	}
+	if po := nc.Pushover; po != nil && po.On(false) {
+		n, err := notify.NewPushover(notify.PushoverOptions{
+			Token:    cmp.Or(po.Token, os.Getenv("PUSHOVER_TOKEN")),
+			User:     cmp.Or(po.User, os.Getenv("PUSHOVER_USER")),
+			Device:   po.Device,
+			Sound:    po.Sound,
+			Priority: po.Priority,
+			Timeout:  po.Timeout.Duration,
+			Retries:  po.Retries,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := add("pushover", &po.NotifyBackend, n); err != nil {
+			return nil, err
+		}
+	}
+	if mx := nc.Matrix; mx != nil && mx.On(false) {
+		n, err := notify.NewMatrix(notify.MatrixOptions{
+			Homeserver: mx.Homeserver,
+			Room:       mx.Room,
+			Token:      cmp.Or(mx.Token, os.Getenv("MATRIX_TOKEN")),
+			Notice:     mx.Notice,
+			Timeout:    mx.Timeout.Duration,
+			Retries:    mx.Retries,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := add("matrix", &mx.NotifyBackend, n); err != nil {
+			return nil, err
+		}
+	}
+	if ic := nc.IRC; ic != nil && ic.On(false) {
+		n, err := notify.NewIRC(notify.IRCOptions{
+			Server:   ic.Server,
+			TLS:      ic.TLS,
+			Nick:     ic.Nick,
+			Password: cmp.Or(ic.Password, os.Getenv("IRC_PASSWORD")),
+			Channels: ic.Channels,
+			NoJoin:   ic.Join != nil && !*ic.Join,
+			Notice:   ic.Notice,
+			Timeout:  ic.Timeout.Duration,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := add("irc", &ic.NotifyBackend, n); err != nil {
+			return nil, err
+		}
+	}
+	if tg := f.Integrations.Telegram; tg != nil && tg.Notify.On(true) && len(tg.Chats) > 0 {
+		n := &telegram.Notifier{Client: telegram.NewClient(cmp.Or(tg.Token, os.Getenv("TELEGRAM_TOKEN"))), Chats: tg.Chats}
+		if err := add("telegram", tg.Notify, n); err != nil {
+			return nil, err
+		}
+	}
+	for _, topic := range slices.Sorted(maps.Keys(nc.Routes)) {
+		if err := reg.Route(topic, nc.Routes[topic]); err != nil {
+			return nil, err
+		}
+	}
+	return &reg, nil
+}
+
+// notifyTemplates parses the [notify.templates] for engine's
+// notifications; nil without any.
+func notifyTemplates(f *config.File, engine *core.PomodoroEngine) (*notify.Templates, error) {
+	if len(f.Notify.Templates) == 0 {
+		return nil, nil
+	}
+	byTopic := make(map[string]notify.Template, len(f.Notify.Templates))
+	for topic, t := range f.Notify.Templates {
+		byTopic[topic] = notify.Template{Title: t.Title, Body: t.Body}
+	}
+	return notify.NewTemplates(byTopic, engine.PhaseDuration)
+}
+
+// openNotifyLog opens the [notify.log] file for appending; "-" or no
+// path is stderr.
+func openNotifyLog(path string) (*os.File, error) {
+	if path == "" || path == "-" {
+		return os.Stderr, nil
+	}
+	if strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[2:])
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	return os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+}
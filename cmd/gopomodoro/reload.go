@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// followConfig keeps engine's timings current until ctx is done: it
+// re-resolves res when the config file changes and, while res follows
+// the config's schedule, when a rule starts or ends. Like picking a
+// profile in the TUI, new timings apply from the next phase; apply is
+// told each new resolution. An error watching the file is returned,
+// but the schedule is still followed.
+func followConfig(ctx context.Context, engine *core.PomodoroEngine, res resolved, apply func(resolved), onErr func(error)) error {
+	reloads := make(chan *config.File)
+	err := watchConfig(ctx, res.path, func(f *config.File) {
+		select {
+		case reloads <- f:
+		case <-ctx.Done():
+		}
+	}, onErr)
+	go func() {
+		t := time.NewTicker(time.Minute)
+		defer t.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case now := <-t.C:
+				if !res.scheduled || res.file.Scheduled(now) == res.profileName {
+					continue
+				}
+				next, err := res.flags.resolveFile(res.file)
+				if err != nil {
+					onErr(err)
+					continue
+				}
+				res = next
+				engine.SetConfig(res.engine)
+				apply(res)
+			case f := <-reloads:
+				next, err := res.flags.resolveFile(f)
+				if err != nil {
+					onErr(err)
+					continue
+				}
+				res = next
+				engine.ReloadConfig(res.engine)
+				apply(res)
+			}
+		}
+	}()
+	return err
+}
+
+// watchConfig is config.Watch with the project config applied, leaving
+// out a config that can't exist: one without a path or a directory.
+func watchConfig(ctx context.Context, path string, onChange func(*config.File), onErr func(error)) error {
+	if path == "" {
+		return nil
+	}
+	err := config.Watch(ctx, path, func(f *config.File) {
+		if err := useProject(f); err != nil {
+			onErr(err)
+			return
+		}
+		onChange(f)
+	}, onErr)
+	if errors.Is(err, fs.ErrNotExist) {
+		// no config directory yet, so nothing to reload
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/service"
+)
+
+// serviceCommand installs, removes or checks the daemon's login service.
+// Arguments after install are passed on to the daemon.
+func serviceCommand(fs *flag.FlagSet) func(args []string) error {
+	return func(args []string) error {
+		_ = fs.Parse(args)
+
+		switch fs.Arg(0) {
+		case "install":
+			exe, err := os.Executable()
+			if err != nil {
+				return err
+			}
+			if exe, err = filepath.EvalSymlinks(exe); err != nil {
+				return err
+			}
+			// go run builds into the build cache, which gets cleaned
+			if strings.Contains(exe, "go-build") {
+				return errors.New("service: this binary is temporary (go run?); go install it first")
+			}
+			cfg := service.Config{Exe: exe, Args: append([]string{"daemon"}, fs.Args()[1:]...)}
+			if err := service.Install(cfg); err != nil {
+				return err
+			}
+			st, err := service.Query()
+			if err != nil {
+				return err
+			}
+			fmt.Println(st)
+			return nil
+		case "uninstall":
+			return service.Uninstall()
+		case "", "status":
+			st, err := service.Query()
+			if err != nil {
+				return err
+			}
+			fmt.Println(st)
+			return nil
+		}
+		fs.Usage()
+		return fmt.Errorf("service: unknown action %q", fs.Arg(0))
+	}
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/integrations/spotify"
+)
+
+// spotifyCommand handles "spotify login", which connects a Spotify account
+// for [integrations.spotify], and "spotify devices".
+func spotifyCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := configFlag(fs)
+	clientID := fs.String("client-id", "", "Spotify app client ID (default from the config or $SPOTIFY_CLIENT_ID)")
+	return func(args []string) error {
+		if len(args) == 0 {
+			fs.Usage()
+			os.Exit(2)
+		}
+		sub := args[0]
+		_ = fs.Parse(args[1:])
+
+		f, err := loadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		id := *clientID
+		if id == "" && f.Integrations.Spotify != nil {
+			id = f.Integrations.Spotify.ClientID
+		}
+		client, err := spotifyClient(id)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+		defer stop()
+
+		switch sub {
+		case "login":
+			ctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+			defer cancel()
+			fmt.Printf("Add %s as a redirect URI of your Spotify app, then grant access in the browser.\n", spotify.DefaultRedirect)
+			token, err := client.Auth.Login(ctx, func(url string) error {
+				fmt.Println("If no browser opens, visit:", url)
+				_ = openBrowser(url)
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			if err := spotify.SaveToken(client.TokenPath, token); err != nil {
+				return err
+			}
+			fmt.Println("Logged in; token saved to", client.TokenPath)
+			return nil
+		case "devices":
+			devices, err := client.Devices(ctx)
+			if err != nil {
+				return err
+			}
+			if len(devices) == 0 {
+				fmt.Println("Spotify isn't running on any device.")
+			}
+			for _, d := range devices {
+				active := ""
+				if d.IsActive {
+					active = " (active)"
+				}
+				fmt.Printf("%s\t%s\t%s%s\n", d.ID, d.Type, d.Name, active)
+			}
+			return nil
+		}
+		return errors.New("usage: gopomodoro spotify login|devices")
+	}
+}
+
+// openBrowser opens url with the desktop's default handler.
+func openBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	}
+	return exec.Command("xdg-open", url).Start()
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+	"github.com/ezchuang/GoPomodoro/internal/ui"
+)
+
+// statsCommand prints aggregate focus statistics from the history file.
+func statsCommand(fs *flag.FlagSet) func(args []string) error {
+	openHistory := historyFlag(fs)
+	configPath := configFlag(fs)
+	since := sinceFlag(fs)
+	heatmap := fs.Bool("heatmap", false, "draw a calendar heatmap of pomodoros per day over the past year")
+	week := fs.Bool("week", false, "print the weekly digest of the past seven days")
+	email := fs.Bool("email", false, "mail the weekly digest now, as configured in [weekly_email]")
+	byTag := fs.Bool("by-tag", false, "print focus time per session tag")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+
+		from, err := since()
+		if err != nil {
+			return err
+		}
+
+		file, err := loadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		sessions, err := store.List()
+		if err != nil {
+			return err
+		}
+		if *email {
+			w, err := weeklyDigest(file, store, nil)
+			if err != nil {
+				return err
+			}
+			return w.Deliver(time.Now())
+		}
+		if *week {
+			top := 5
+			if wc := file.WeeklyEmail; wc != nil && wc.Top > 0 {
+				top = wc.Top
+			}
+			fmt.Print(stats.Week(sessions, time.Now(), file.Goal).Text(top))
+			return nil
+		}
+		if *heatmap {
+			fmt.Println(ui.Heatmap(sessions, time.Now(), 4+2*ui.HeatmapWeeks))
+			return nil
+		}
+		filtered := stats.Filter(sessions, from)
+		sum := stats.Summarize(filtered)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		if *byTag {
+			// sessions with several tags count toward each, so shares can
+			// add up to more than 100%
+			fmt.Fprintln(w, "Tag\tPomodoros\tFocus\tShare")
+			for _, ts := range stats.ByTag(filtered) {
+				share := float64(ts.Focus) / float64(max(sum.Focus, 1)) * 100
+				fmt.Fprintf(w, "#%s\t%d\t%s\t%.0f%%\n", ts.Tag, ts.Pomodoros, ts.Focus.Round(time.Second), share)
+			}
+			return w.Flush()
+		}
+		today := stats.CompletedOn(sessions, time.Now())
+		if file.Goal > 0 {
+			fmt.Fprintf(w, "Today:\t%d/%d\n", today, file.Goal)
+		} else {
+			fmt.Fprintf(w, "Today:\t%d\n", today)
+		}
+		fmt.Fprintf(w, "Pomodoros:\t%d\n", sum.Pomodoros)
+		if sum.Pomodoros+sum.Abandoned > 0 {
+			fmt.Fprintf(w, "Completion:\t%.0f%% (%d abandoned)\n", sum.CompletionRate()*100, sum.Abandoned)
+		}
+		fmt.Fprintf(w, "Focus:\t%s\n", sum.Focus.Round(time.Second))
+		fmt.Fprintf(w, "Paused:\t%s\n", sum.Paused.Round(time.Second))
+		if sum.Overtime > 0 {
+			fmt.Fprintf(w, "Overtime:\t%s\n", sum.Overtime.Round(time.Second))
+		}
+		fmt.Fprintf(w, "Interruptions:\t%d internal, %d external (%.1f per pomodoro)\n",
+			sum.InternalInterruptions, sum.ExternalInterruptions, sum.InterruptionsPerPomodoro())
+		if len(sum.Pauses) > 0 {
+			fmt.Fprintln(w, "\nPause reason\tCount\tTime\tShare")
+			for _, rs := range sum.Pauses {
+				share := float64(rs.Total) / float64(sum.Paused) * 100
+				fmt.Fprintf(w, "%s\t%d\t%s\t%.0f%%\n", rs.Reason, rs.Count, rs.Total.Round(time.Second), share)
+			}
+		}
+		if ests := stats.Estimates(filtered); len(ests) > 0 {
+			fmt.Fprintln(w, "\nTask\tEstimate\tActual\tVariance")
+			for _, e := range ests {
+				fmt.Fprintf(w, "%s\t%d\t%d\t%+d\n", e.Title, e.Estimate, e.Actual, e.Variance())
+			}
+			fmt.Fprintf(w, "Estimates:\t%.0f%% of estimated pomodoros used\n", stats.EstimateAccuracy(ests)*100)
+		}
+		return w.Flush()
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/client"
+	"github.com/ezchuang/GoPomodoro/internal/server"
+)
+
+// daemonFlags registers -addr and -timer, which pick the daemon timer a
+// command talks to, and returns a function making its client.
+func daemonFlags(fs *flag.FlagSet) func() *client.Client {
+	addr := fs.String("addr", "", "daemon address (default $GOPOMODORO_ADDR or 127.0.0.1:7767)")
+	timer := fs.String("timer", "", "use a named timer instead of the default one")
+	return func() *client.Client {
+		c := client.New(*addr)
+		c.Timer = *timer
+		return c
+	}
+}
+
+// startCommand starts the daemon's timer, resumes it when paused, and
+// prints it.
+func startCommand(fs *flag.FlagSet) func(args []string) error {
+	newClient := daemonFlags(fs)
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		c := newClient()
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		st, err := c.State(ctx)
+		if err != nil {
+			return err
+		}
+		switch {
+		case st.Idle:
+			err = c.Post(ctx, "/start")
+		case st.Paused:
+			err = c.Post(ctx, "/resume")
+		}
+		if err != nil {
+			return err
+		}
+		if st, err = c.State(ctx); err != nil {
+			return err
+		}
+		fmt.Println(describeState(st, time.Now()))
+		return nil
+	}
+}
+
+// statusCommand prints the daemon's timer; unlike bar and tmux, a
+// missing daemon is an error.
+func statusCommand(fs *flag.FlagSet) func(args []string) error {
+	newClient := daemonFlags(fs)
+	asJSON := fs.Bool("json", false, "print the state as the API's JSON")
+	verbose := fs.Bool("verbose", false, "also print how the notification backends are doing")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		c := newClient()
+		st, err := c.State(ctx)
+		if err != nil {
+			return err
+		}
+		var notifiers []server.NotifierJSON
+		if *verbose {
+			if notifiers, err = c.Notifiers(ctx); err != nil {
+				return err
+			}
+		}
+		if *asJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			if *verbose {
+				return enc.Encode(struct {
+					State     server.StateJSON      `json:"state"`
+					Notifiers []server.NotifierJSON `json:"notifiers"`
+				}{st, notifiers})
+			}
+			return enc.Encode(st)
+		}
+		fmt.Println(describeState(st, time.Now()))
+		if *verbose {
+			fmt.Println("notifications:")
+			for _, n := range notifiers {
+				fmt.Println("  " + describeNotifier(n))
+			}
+		}
+		return nil
+	}
+}
+
+// describeState is the status bar text with the phase, pomodoro count
+// and task spelled out, e.g. "🍅 12:34 WORK, 2 done, Write report".
+func describeState(st server.StateJSON, now time.Time) string {
+	b := summarize(st, now)
+	if st.Idle {
+		return b.String() + " idle"
+	}
+	parts := []string{b.String() + " " + st.Name}
+	if st.Paused {
+		parts[0] += " (paused)"
+	}
+	parts = append(parts, fmt.Sprintf("%d done", st.PomodoroDone))
+	if st.Task != "" {
+		parts = append(parts, st.Task)
+	}
+	if len(st.Tags) > 0 {
+		parts = append(parts, strings.Join(st.Tags, " "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// describeNotifier is a backend's health on one line, e.g.
+// "desktop: failing (no D-Bus at 10:42:05), 3 failed, 3 of them sent
+// by bell".
+func describeNotifier(n server.NotifierJSON) string {
+	if n.OK {
+		if n.Sent == 0 {
+			return n.Name + ": ok, nothing sent yet"
+		}
+		s := fmt.Sprintf("%s: ok, %d sent", n.Name, n.Sent)
+		if n.Failed > 0 {
+			s += fmt.Sprintf(", %d failed (last: %s)", n.Failed, n.LastError)
+		}
+		return s
+	}
+	s := fmt.Sprintf("%s: failing (%s at %s), %d failed", n.Name, n.LastError, n.LastFailed.Local().Format(time.TimeOnly), n.Failed)
+	if n.Fallback != "" {
+		s += fmt.Sprintf(", %d of them sent by %s", n.FellBack, n.Fallback)
+	}
+	return s
+}
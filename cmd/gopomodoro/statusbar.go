@@ -0,0 +1,54 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/server"
+)
+
+// barStatus is the short form of the timer shown by status bars.
+type barStatus struct {
+	Icon  string
+	Text  string // remaining time, "+" overtime or open flow work, empty when idle
+	Class string // idle, work, short_break, long_break, paused or overtime
+}
+
+// summarize condenses a daemon state for a status bar.
+func summarize(st server.StateJSON, now time.Time) barStatus {
+	remain := time.Duration(st.Remaining * float64(time.Second))
+	switch {
+	case st.Idle:
+		return barStatus{Icon: "🍅", Class: "idle"}
+	case st.Overtime:
+		return barStatus{Icon: "⏰", Text: "+" + clock(now.Sub(st.EndsAt)), Class: "overtime"}
+	case st.Open && st.Paused:
+		return barStatus{Icon: "⏸", Text: "+" + clock(time.Duration(st.Elapsed*float64(time.Second))), Class: "paused"}
+	case st.Open:
+		return barStatus{Icon: "🍅", Text: "+" + clock(time.Duration(st.Elapsed*float64(time.Second))), Class: "work"}
+	case st.Paused:
+		return barStatus{Icon: "⏸", Text: clock(remain), Class: "paused"}
+	case st.Phase == "SHORT_BREAK":
+		return barStatus{Icon: "☕", Text: clock(remain), Class: "short_break"}
+	case st.Phase == "LONG_BREAK":
+		return barStatus{Icon: "🌴", Text: clock(remain), Class: "long_break"}
+	}
+	return barStatus{Icon: "🍅", Text: clock(remain), Class: "work"}
+}
+
+// String is "🍅 12:34", or just the icon when idle.
+func (b barStatus) String() string {
+	if b.Text == "" {
+		return b.Icon
+	}
+	return b.Icon + " " + b.Text
+}
+
+// clock formats d as m:ss, or h:mm:ss from an hour on.
+func clock(d time.Duration) string {
+	s := int(max(d, 0).Round(time.Second) / time.Second)
+	if s >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", s/3600, s/60%60, s%60)
+	}
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/remote"
+)
+
+// defaultSyncEvery is how often the TUI and the daemon sync unless
+// [sync] every says otherwise.
+const defaultSyncEvery = 15 * time.Minute
+
+// syncCommand syncs the history with the [sync] remote store once.
+func syncCommand(fs *flag.FlagSet) func(args []string) error {
+	configPath := configFlag(fs)
+	openHistory := historyFlag(fs)
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		file, err := loadConfig(*configPath)
+		if err != nil {
+			return err
+		}
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		backend, machine, err := syncBackend(file, store)
+		if err != nil {
+			return err
+		}
+		if backend == nil {
+			return errors.New("sync: no [sync] section in the config")
+		}
+		res, err := remote.Sync(context.Background(), store, backend, machine)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("synced %s: %d added, %d updated, %d deleted\n",
+			strings.Join(res.Machines, ", "), res.Added, res.Updated, res.Deleted)
+		return nil
+	}
+}
+
+// watchSync syncs store with the [sync] remote store now and then
+// every so often until ctx is done.
+func watchSync(ctx context.Context, f *config.File, store *history.Store, onErr func(error)) error {
+	backend, machine, err := syncBackend(f, store)
+	if err != nil || backend == nil {
+		return err
+	}
+	every := cmp.Or(f.Sync.Every.Duration, defaultSyncEvery)
+	go func() {
+		t := time.NewTicker(every)
+		defer t.Stop()
+		for {
+			if _, err := remote.Sync(ctx, store, backend, machine); err != nil && onErr != nil && ctx.Err() == nil {
+				onErr(err)
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-t.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// syncBackend returns the remote store of f's [sync] section and the
+// name this machine syncs as, or a nil backend without the section.
+func syncBackend(f *config.File, store *history.Store) (remote.Backend, string, error) {
+	sc := f.Sync
+	if sc == nil {
+		return nil, "", nil
+	}
+	machine := sc.Machine
+	if machine == "" {
+		host, err := os.Hostname()
+		if err != nil {
+			return nil, "", fmt.Errorf("sync: no machine name: %w", err)
+		}
+		machine = remote.Machine(strings.Split(host, ".")[0])
+	}
+	var (
+		backend remote.Backend
+		err     error
+	)
+	switch {
+	case sc.S3 != nil:
+		backend, err = remote.NewS3(remote.S3Options{
+			Endpoint:  sc.S3.Endpoint,
+			Region:    sc.S3.Region,
+			Bucket:    sc.S3.Bucket,
+			Prefix:    sc.S3.Prefix,
+			AccessKey: cmp.Or(sc.S3.AccessKey, os.Getenv("AWS_ACCESS_KEY_ID")),
+			SecretKey: cmp.Or(sc.S3.SecretKey, os.Getenv("AWS_SECRET_ACCESS_KEY")),
+		})
+	case sc.WebDAV != nil:
+		backend, err = remote.NewWebDAV(remote.WebDAVOptions{
+			URL:      sc.WebDAV.URL,
+			Username: sc.WebDAV.Username,
+			Password: cmp.Or(sc.WebDAV.Password, os.Getenv("WEBDAV_PASSWORD")),
+		})
+	case sc.Git != nil:
+		dir := cmp.Or(sc.Git.Dir, filepath.Join(filepath.Dir(store.Path()), "sync.git"))
+		backend, err = remote.NewGit(remote.GitOptions{Repo: sc.Git.Repo, Dir: dir})
+	}
+	if err != nil {
+		return nil, "", fmt.Errorf("sync: %w", err)
+	}
+	return backend, machine, nil
+}
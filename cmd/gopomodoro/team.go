@@ -0,0 +1,141 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/user"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+	"github.com/ezchuang/GoPomodoro/internal/server"
+	"github.com/ezchuang/GoPomodoro/internal/team"
+)
+
+// teamFlags host or join a team session.
+type teamFlags struct {
+	host  *bool
+	join  *string
+	name  *string
+	token *string
+}
+
+func registerTeamFlags(fs *flag.FlagSet) *teamFlags {
+	return &teamFlags{
+		host:  fs.Bool("team", false, "host a team session on the -listen API for others to -join"),
+		join:  fs.String("join", "", "join the team session of the instance at this address"),
+		name:  fs.String("name", "", "your name in a team session (default your user name)"),
+		token: fs.String("team-token", "", "the team session's shared secret, for the host and everyone joining (default $GOPOMODORO_TEAM_TOKEN)"),
+	}
+}
+
+// roomBackends post where the whole team may be listening: a chat room
+// or a shared endpoint.
+var roomBackends = []string{"webhook", "matrix", "irc", "telegram"}
+
+// Team sessions batch the host's room posts this long, and space them
+// at least teamRoomGap apart, so a burst of members' starts, pauses and
+// skips makes one post rather than one each.
+const (
+	teamRoomWindow = 5 * time.Second
+	teamRoomGap    = 30 * time.Second
+)
+
+// shareRooms readies reg's room backends for the team session the flags
+// ask for, before anything is sent: the host posts the shared timer's
+// messages, batched per backend, and the members leave them to it, so a
+// room hears each phase change once rather than once per member. The
+// returned function delivers whatever is still batched.
+func (f *teamFlags) shareRooms(reg *notify.Registry, onErr func(error)) (flush func()) {
+	var batchers []*notify.Batcher
+	switch {
+	case *f.host:
+		reg.Wrap(roomBackends, func(name string, n notify.Notifier) notify.Notifier {
+			b := notify.NewBatcher(n, notify.BatchOptions{
+				Window:      teamRoomWindow,
+				MinInterval: teamRoomGap,
+				OnError: func(err error) {
+					if onErr != nil {
+						onErr(fmt.Errorf("%s: %w", name, err))
+					}
+				},
+			})
+			batchers = append(batchers, b)
+			return b
+		})
+	case *f.join != "":
+		reg.Wrap(roomBackends, func(_ string, n notify.Notifier) notify.Notifier {
+			return hostPosts{n}
+		})
+	}
+	return func() {
+		for _, b := range batchers {
+			if err := b.Close(); err != nil && onErr != nil {
+				onErr(err)
+			}
+		}
+	}
+}
+
+// hostPosts passes on a member's own messages, such as the daily goal,
+// and drops those about the shared timer, which the host posts.
+type hostPosts struct{ notify.Notifier }
+
+func (h hostPosts) NotifyMessage(msg notify.Message) error {
+	if !msg.Event.At.IsZero() {
+		return nil
+	}
+	return notify.Send(h.Notifier, msg)
+}
+
+// roster lists the members of a team session.
+type roster interface{ Members() []string }
+
+// start hosts the session on srv (nil without an API) or joins one,
+// as the flags say. roster is nil outside a team session.
+func (f *teamFlags) start(ctx context.Context, engine *core.PomodoroEngine, srv *server.Server, onErr func(error)) (r roster, cancel func(), err error) {
+	name := *f.name
+	if name == "" {
+		if u, err := user.Current(); err == nil {
+			name = u.Username
+		}
+		name = cmp.Or(name, os.Getenv("USER"), "me")
+	}
+	token := cmp.Or(*f.token, os.Getenv("GOPOMODORO_TEAM_TOKEN"))
+	switch {
+	case *f.host && *f.join != "":
+		return nil, nil, errors.New("-team and -join don't mix: host or join")
+	case (*f.host || *f.join != "") && token == "":
+		return nil, nil, errors.New("a team session needs a shared token: -team-token or $GOPOMODORO_TEAM_TOKEN")
+	case *f.host:
+		if srv == nil {
+			return nil, nil, errors.New("-team needs -listen to serve the session")
+		}
+		h, err := team.NewHost(engine, name, token, onErr)
+		if err != nil {
+			return nil, nil, err
+		}
+		srv.ServeTeam(h)
+		return h, engine.Subscribe(h.Handle), nil
+	case *f.join != "":
+		m, err := team.NewMember(*f.join, name, token, engine, onErr)
+		if err != nil {
+			return nil, nil, err
+		}
+		ctx, stop := context.WithCancel(ctx)
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			m.Run(ctx)
+		}()
+		return m, func() {
+			stop()
+			<-done
+		}, nil
+	}
+	return nil, func() {}, nil
+}
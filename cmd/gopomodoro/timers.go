@@ -0,0 +1,138 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/client"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/i18n"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+)
+
+// openTimers puts engine into a Manager as the default timer, next to
+// the [timers] of the config file.
+func openTimers(engine *core.PomodoroEngine, res resolved) (*core.Manager, error) {
+	m := core.NewManager()
+	if err := m.Add(core.DefaultTimer, engine); err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(res.file.Timers))
+	for name := range res.file.Timers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		e, err := newTimer(res, res.file.Timers[name])
+		if err != nil {
+			return nil, fmt.Errorf("timer %s: %w", name, err)
+		}
+		if err := m.Add(name, e); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// newTimer creates an engine for an extra timer running profile, or the
+// default timer's timings without one.
+func newTimer(res resolved, profile string) (*core.PomodoroEngine, error) {
+	if profile == "" {
+		return core.New(res.engine), nil
+	}
+	prof, err := res.file.Resolve(profile)
+	if err != nil {
+		return nil, err
+	}
+	return core.New(prof.Core()), nil
+}
+
+// notifyTimers sends phase notifications for the extra timers, titled
+// with their names; the default timer has its own subscriber. Extra
+// timers are not recorded in the history.
+func notifyTimers(m *core.Manager, notifier notify.Notifier, loc *i18n.Locale, onErr func(error)) (cancel func()) {
+	return m.Subscribe(func(ev core.Event) {
+		e := m.Get(ev.Timer)
+		if ev.Timer == core.DefaultTimer || e == nil {
+			return
+		}
+		var body string
+		switch ev.Kind {
+		case core.EventAdvance:
+			body = loc.Sprintf("Phase: %s", loc.Name(ev.State))
+		case core.EventWarning:
+			body = notify.WarningBody(loc, ev)
+		case core.EventOvertime:
+			body = loc.T("Done, overtime running")
+		default:
+			return
+		}
+		err := notify.Send(notifier, notify.Message{
+			Title:   "GoPomodoro · " + ev.Timer,
+			Body:    body,
+			Event:   ev,
+			Actions: notify.PhaseActions(e, ev),
+		})
+		if err != nil && onErr != nil {
+			onErr(err)
+		}
+	})
+}
+
+// timerActions are the daemon calls of "gopomodoro timers <action> NAME".
+var timerActions = map[string]string{
+	"start":  "/start",
+	"pause":  "/pause",
+	"resume": "/resume",
+	"stop":   "/stop",
+	"skip":   "/skip",
+}
+
+// timersCommand lists or controls the daemon's named timers.
+func timersCommand(fs *flag.FlagSet) func(args []string) error {
+	addr := fs.String("addr", "", "daemon address (default $GOPOMODORO_ADDR or 127.0.0.1:7767)")
+	profile := fs.String("profile", "", "profile for a timer created with add (default the daemon's)")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		c := client.New(*addr)
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		cmd := fs.Arg(0)
+		if cmd == "" || cmd == "list" {
+			list, err := c.Timers(ctx)
+			if err != nil {
+				return err
+			}
+			now := time.Now()
+			for _, t := range list {
+				fmt.Printf("%-12s %s\n", t.Name, summarize(t.State, now))
+			}
+			return nil
+		}
+		if fs.NArg() != 2 {
+			fs.Usage()
+			return errors.New("timers: want an action and a timer name")
+		}
+		name := fs.Arg(1)
+		switch cmd {
+		case "add":
+			return c.AddTimer(ctx, name, *profile)
+		case "rm", "remove":
+			return c.RemoveTimer(ctx, name)
+		case "toggle":
+			c.Timer = name
+			return c.Toggle(ctx)
+		}
+		path, ok := timerActions[cmd]
+		if !ok {
+			return fmt.Errorf("timers: unknown action %q", cmd)
+		}
+		c.Timer = name
+		return c.Post(ctx, path)
+	}
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/client"
+)
+
+// tmuxColors styles each barStatus class with tmux format codes.
+var tmuxColors = map[string]string{
+	"idle":        "#[fg=colour244]",
+	"work":        "#[fg=red,bold]",
+	"short_break": "#[fg=green]",
+	"long_break":  "#[fg=blue]",
+	"paused":      "#[fg=yellow]",
+	"overtime":    "#[fg=magenta,bold,blink]",
+}
+
+const tmuxSnippet = `# ~/.tmux.conf
+set -g status-interval 1
+set -g status-right '#(gopomodoro tmux) | %H:%M'
+`
+
+// tmuxCommand prints the daemon's timer for tmux's status line. It prints
+// nothing when no daemon is running, so the status line stays clean.
+func tmuxCommand(fs *flag.FlagSet) func(args []string) error {
+	addr := fs.String("addr", "", "daemon address (default $GOPOMODORO_ADDR or 127.0.0.1:7767)")
+	timer := fs.String("timer", "", "show a named timer instead of the default one")
+	color := fs.Bool("color", true, "style the output with tmux #[…] codes")
+	snippet := fs.Bool("snippet", false, "print a sample tmux.conf snippet and exit")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		if *snippet {
+			fmt.Print(tmuxSnippet)
+			return nil
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+		defer cancel()
+		c := client.New(*addr)
+		c.Timer = *timer
+		st, err := c.State(ctx)
+		if err != nil {
+			return nil
+		}
+		b := summarize(st, time.Now())
+		if *color {
+			fmt.Println(tmuxColors[b.Class] + b.String() + "#[default]")
+		} else {
+			fmt.Println(b.String())
+		}
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/tray"
+)
+
+// trayCommand runs the engine behind a system tray icon instead of the TUI.
+func trayCommand(fs *flag.FlagSet) func(args []string) error {
+	ef := registerEngineFlags(fs)
+	openHistory := historyFlag(fs)
+	openLog := logFlags(fs)
+	listen := fs.String("listen", "", "also serve the HTTP/WebSocket API on this address")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+		logger, closeLog, err := openLog(false)
+		if err != nil {
+			return err
+		}
+		defer closeLog()
+
+		res, err := ef.resolve()
+		if err != nil {
+			return err
+		}
+		store, err := openHistory()
+		if err != nil {
+			return err
+		}
+		notifier, err := buildNotifier(res.file, logger)
+		if err != nil {
+			return err
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		engine := core.New(res.engine)
+		res.attach(engine)
+		cleanup, err := runHeadless(ctx, "tray", engine, res, store, notifier)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+		// quitting mid-phase records it as unfinished
+		defer engine.Stop()
+		timers, err := openTimers(engine, res)
+		if err != nil {
+			return err
+		}
+		defer notifyTimers(timers, notifier, locale(res.file), func(err error) {
+			log.Printf("notify: %v", err)
+		})()
+
+		if *listen != "" {
+			api, err := newAPI(timers, res, store, notifier.Health)
+			if err != nil {
+				return err
+			}
+			srv := &http.Server{Addr: *listen, Handler: api}
+			go func() {
+				if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+					log.Printf("http server: %v", err)
+				}
+			}()
+			defer srv.Close()
+		}
+		return tray.Run(ctx, engine)
+	}
+}
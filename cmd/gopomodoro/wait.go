@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/ezchuang/GoPomodoro/internal/client"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/server"
+)
+
+// waitCommand blocks until the daemon's current phase, or with -cycle its
+// run, is over. It returns nil, exiting 0, only if it completed, so
+// scripts can chain on it: gopomodoro wait && make release.
+func waitCommand(fs *flag.FlagSet) func(args []string) error {
+	addr := fs.String("addr", "", "daemon address (default $GOPOMODORO_ADDR or 127.0.0.1:7767)")
+	timer := fs.String("timer", "", "wait for a named timer instead of the default one")
+	cycle := fs.Bool("cycle", false, "wait for the daemon's -cycles to be done, or the next long break, instead of the current phase")
+	timeout := fs.Duration("timeout", 0, "give up after this long (0 waits as long as it takes)")
+	return func(args []string) error {
+		_ = fs.Parse(args)
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		if *timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, *timeout)
+			defer cancel()
+		}
+
+		c := client.New(*addr)
+		c.Timer = *timer
+		var outcome error
+		if err := c.Watch(ctx, func(ev server.EventJSON) bool {
+			var over bool
+			over, outcome = waitOver(ev, *cycle)
+			return !over
+		}); err != nil {
+			if errors.Is(err, context.DeadlineExceeded) {
+				return errors.New("wait: timed out")
+			}
+			return err
+		}
+		return outcome
+	}
+}
+
+// waitOver reports whether ev ends a wait, and with which outcome: nil
+// when what it waited for completed.
+func waitOver(ev server.EventJSON, cycle bool) (bool, error) {
+	switch ev.Type {
+	case "state":
+		if ev.State.Idle {
+			return true, errors.New("wait: no phase is running")
+		}
+	case core.EventAdvance.String():
+		return !cycle || ev.State.Phase == core.PhaseLongBreak.String(), nil
+	case core.EventOvertime.String():
+		// the work is done, only the break is on hold
+		return !cycle, nil
+	case core.EventSkip.String():
+		if !cycle {
+			return true, errors.New("wait: the phase was skipped")
+		}
+	case core.EventStop.String():
+		if ev.Finished {
+			return true, nil
+		}
+		return true, errors.New("wait: the timer was stopped")
+	}
+	return false, nil
+}
@@ -0,0 +1,131 @@
+package blocker
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Blocker turns blocking on and off by running the helper's "hold" in a
+// child process (through sudo -n where the hosts file isn't writable),
+// and stopping it by closing its stdin. Its Set fits dnd.Sync.
+type Blocker struct {
+	// Helper is the command that takes hold and restore, normally this
+	// program's "blocker" subcommand.
+	Helper  []string
+	Path    string // hosts file; default HostsPath()
+	Domains []string
+	Sudo    bool
+
+	mu    sync.Mutex
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+}
+
+func (b *Blocker) path() string {
+	if b.Path == "" {
+		return HostsPath()
+	}
+	return b.Path
+}
+
+// command builds a helper invocation, behind sudo if needed.
+func (b *Blocker) command(args ...string) *exec.Cmd {
+	argv := append(slices.Clone(b.Helper), args...)
+	if b.Sudo && !writable(b.path()) {
+		argv = append([]string{"sudo", "-n", "--"}, argv...)
+	}
+	return exec.Command(argv[0], argv[1:]...)
+}
+
+func writable(path string) bool {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return false
+	}
+	f.Close()
+	return true
+}
+
+// Check reports whether the helper can edit the hosts file: directly,
+// or through a sudo rule that doesn't ask for a password.
+func (b *Blocker) Check() error {
+	if writable(b.path()) {
+		return nil
+	}
+	if !b.Sudo {
+		return fmt.Errorf("blocker: %s is not writable", b.path())
+	}
+	// sudo -l with a command exits non-zero unless a rule allows it
+	argv := append([]string{"-n", "-l", "--"}, b.Helper...)
+	argv = append(argv, "hold", "-hosts", b.path(), "--", "example.com")
+	if err := exec.Command("sudo", argv...).Run(); err != nil {
+		return fmt.Errorf("blocker: sudo can't run the helper without a password (see gopomodoro blocker setup)")
+	}
+	return nil
+}
+
+// Set blocks the sites when on is true and unblocks them otherwise.
+func (b *Blocker) Set(on bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !on {
+		if b.cmd == nil {
+			return nil
+		}
+		b.stdin.Close()
+		err := b.cmd.Wait()
+		b.cmd, b.stdin = nil, nil
+		return err
+	}
+	if b.cmd != nil {
+		return nil
+	}
+	cmd := b.command(append([]string{"hold", "-hosts", b.path(), "--"}, b.Domains...)...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	// the helper prints "blocked" once the hosts file is written
+	line, _ := bufio.NewReader(stdout).ReadString('\n')
+	if strings.TrimSpace(line) != "blocked" {
+		stdin.Close()
+		_ = cmd.Wait()
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("blocker: %s", msg)
+		}
+		return errors.New("blocker: helper failed")
+	}
+	b.cmd, b.stdin = cmd, stdin
+	return nil
+}
+
+// Restore removes a block left behind by a helper that couldn't clean
+// up, e.g. after a power loss.
+func (b *Blocker) Restore() error {
+	data, err := os.ReadFile(b.path())
+	if err != nil || !Blocked(string(data)) {
+		return err
+	}
+	cmd := b.command("restore", "-hosts", b.path())
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("blocker restore: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package blocker
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const hosts = "127.0.0.1 localhost\n::1 localhost\n"
+
+func TestApplyStrip(t *testing.T) {
+	ds, err := Normalize([]string{"Reddit.com", "https://www.youtube.com/feed", "reddit.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"reddit.com", "www.reddit.com", "youtube.com", "www.youtube.com"}; !slices.Equal(ds, want) {
+		t.Fatalf("normalize: got %q, want %q", ds, want)
+	}
+	blocked := Apply(hosts, ds)
+	if !Blocked(blocked) || !strings.Contains(blocked, "0.0.0.0 www.youtube.com\n:: www.youtube.com\n") {
+		t.Fatalf("apply:\n%s", blocked)
+	}
+	if again := Apply(blocked, ds[:2]); strings.Count(again, begin) != 1 || strings.Contains(again, "youtube") {
+		t.Fatalf("reapply should replace the block:\n%s", again)
+	}
+	if got := Strip(blocked); got != hosts {
+		t.Fatalf("strip: got %q, want %q", got, hosts)
+	}
+	if got := Strip(Apply("127.0.0.1 localhost", ds)); got != "127.0.0.1 localhost\n" {
+		t.Fatalf("strip without trailing newline: got %q", got)
+	}
+}
+
+func TestNormalize_Rejects(t *testing.T) {
+	for _, d := range []string{"", "evil.com\n1.2.3.4 bank.com", "a b.com", "-flag"} {
+		if _, err := Normalize([]string{d}); err == nil {
+			t.Errorf("%q accepted", d)
+		}
+	}
+}
+
+func TestHold(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "hosts")
+	if err := os.WriteFile(path, []byte(hosts), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	stop := make(chan struct{})
+	done := make(chan error, 1)
+	blocked := make(chan struct{})
+	go func() {
+		done <- Hold(path, []string{"news.ycombinator.com"}, func() { close(blocked) }, stop)
+	}()
+	<-blocked
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "0.0.0.0 news.ycombinator.com") {
+		t.Fatalf("not blocked:\n%s", data)
+	}
+	close(stop)
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != hosts {
+		t.Fatalf("not restored:\n%s", data)
+	}
+}
+
+func TestRootOnly(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("no sudo rules on Windows")
+	}
+	exe := filepath.Join(t.TempDir(), "gopomodoro")
+	if err := os.WriteFile(exe, nil, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	// the temporary directory is the user's, or under a world-writable
+	// one when the tests run as root
+	if err := RootOnly(exe); err == nil {
+		t.Fatalf("%s passed as root-only", exe)
+	}
+	if err := RootOnly(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Fatal("a missing program passed")
+	}
+}
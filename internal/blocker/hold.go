@@ -0,0 +1,49 @@
+package blocker
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Hold blocks domains in the hosts file at path until stop is closed,
+// then removes the block. The helper process runs it, so a crash of the
+// timer, which closes the helper's stdin, still unblocks the sites.
+// blocked is called once the block is in place.
+func Hold(path string, domains []string, blocked func(), stop <-chan struct{}) error {
+	ds, err := Normalize(domains)
+	if err != nil {
+		return err
+	}
+	if err := rewrite(path, func(h string) string { return Apply(h, ds) }); err != nil {
+		return err
+	}
+	flushDNS()
+	if blocked != nil {
+		blocked()
+	}
+	<-stop
+	return Restore(path)
+}
+
+// Restore removes the block from the hosts file at path, if present.
+func Restore(path string) error {
+	if err := rewrite(path, Strip); err != nil {
+		return err
+	}
+	flushDNS()
+	return nil
+}
+
+// flushDNS drops cached lookups so the change takes effect at once. It
+// is best effort: not every system caches, or has these tools.
+func flushDNS() {
+	switch runtime.GOOS {
+	case "darwin":
+		_ = exec.Command("dscacheutil", "-flushcache").Run()
+		_ = exec.Command("killall", "-HUP", "mDNSResponder").Run()
+	case "windows":
+		_ = exec.Command("ipconfig", "/flushdns").Run()
+	case "linux":
+		_ = exec.Command("resolvectl", "flush-caches").Run()
+	}
+}
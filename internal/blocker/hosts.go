@@ -0,0 +1,127 @@
+// Package blocker blocks distracting sites during work phases by adding
+// them to the hosts file, and removes them again for breaks.
+package blocker
+
+import (
+	"fmt"
+	"os"
+	"runtime"
+	"strings"
+)
+
+const (
+	begin = "# BEGIN gopomodoro (sites blocked during work; removed automatically)"
+	end   = "# END gopomodoro"
+)
+
+// HostsPath is the system hosts file.
+func HostsPath() string {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("SystemRoot") + `\System32\drivers\etc\hosts`
+	}
+	return "/etc/hosts"
+}
+
+// Apply returns hosts with a block sending domains, as returned by
+// Normalize, nowhere. It replaces any earlier block.
+func Apply(hosts string, domains []string) string {
+	hosts = Strip(hosts)
+	var b strings.Builder
+	b.WriteString(hosts)
+	if hosts != "" && !strings.HasSuffix(hosts, "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(begin + "\n")
+	for _, d := range domains {
+		fmt.Fprintf(&b, "0.0.0.0 %s\n:: %s\n", d, d)
+	}
+	b.WriteString(end + "\n")
+	return b.String()
+}
+
+// Strip returns hosts without the block.
+func Strip(hosts string) string {
+	var b strings.Builder
+	in := false
+	for _, l := range strings.SplitAfter(hosts, "\n") {
+		switch trimmed := strings.TrimRight(l, "\r\n"); {
+		case trimmed == begin:
+			in = true
+		case in && trimmed == end:
+			in = false
+		case !in:
+			b.WriteString(l)
+		}
+	}
+	return b.String()
+}
+
+// Blocked reports whether hosts has the block.
+func Blocked(hosts string) bool {
+	for _, l := range strings.Split(hosts, "\n") {
+		if strings.TrimRight(l, "\r") == begin {
+			return true
+		}
+	}
+	return false
+}
+
+// Normalize lowercases domains, strips schemes, paths and "www." and
+// adds the www. variants, without duplicates.
+func Normalize(domains []string) ([]string, error) {
+	seen := map[string]bool{}
+	var out []string
+	add := func(d string) {
+		if !seen[d] {
+			seen[d] = true
+			out = append(out, d)
+		}
+	}
+	for _, raw := range domains {
+		d := strings.ToLower(strings.TrimSpace(raw))
+		d = strings.TrimPrefix(strings.TrimPrefix(d, "https://"), "http://")
+		d, _, _ = strings.Cut(d, "/")
+		d = strings.TrimPrefix(d, "www.")
+		if !validDomain(d) {
+			return nil, fmt.Errorf("blocker: bad domain %q", raw)
+		}
+		add(d)
+		add("www." + d)
+	}
+	return out, nil
+}
+
+// validDomain reports whether d is safe to put in a hosts file.
+func validDomain(d string) bool {
+	if d == "" || len(d) > 253 || strings.HasPrefix(d, "-") {
+		return false
+	}
+	for _, r := range d {
+		if !(r == '.' || r == '-' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9') {
+			return false
+		}
+	}
+	return true
+}
+
+// rewrite replaces the hosts file's content in place, keeping its owner
+// and mode (and working where the file is a bind mount).
+func rewrite(path string, edit func(string) string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	next := edit(string(data))
+	if next == string(data) {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_TRUNC, 0)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(next); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
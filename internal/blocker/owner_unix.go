@@ -0,0 +1,36 @@
+//go:build unix
+
+package blocker
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// RootOnly checks that only root can replace the program at path: that
+// it and every directory above it belong to root and no group or other
+// user may write them. A passwordless sudo rule for a program anyone
+// else can swap out hands them root.
+func RootOnly(path string) error {
+	path, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	for p := path; ; p = filepath.Dir(p) {
+		fi, err := os.Lstat(p)
+		if err != nil {
+			return err
+		}
+		if st, ok := fi.Sys().(*syscall.Stat_t); !ok || st.Uid != 0 {
+			return fmt.Errorf("%s isn't owned by root", p)
+		}
+		if fi.Mode().Perm()&0o022 != 0 {
+			return fmt.Errorf("%s is writable by others than root", p)
+		}
+		if p == filepath.Dir(p) {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package chaos injects faults into a running engine for soak testing:
+// late timers, dropped notifications and a scheduler that is killed and
+// restarted at random, while a Checker asserts state machine invariants.
+package chaos
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+)
+
+// Options tune how aggressive fault injection is.
+type Options struct {
+	Seed          uint64
+	MaxTimerDelay time.Duration // upper bound of the random delay added to every timer
+	DropRate      float64       // probability in [0,1] that a notification is dropped
+	RestartEvery  time.Duration // mean interval between scheduler restarts
+	Logf          func(format string, args ...any)
+}
+
+// DefaultOptions returns settings suitable for a manual soak run.
+func DefaultOptions(seed uint64) Options {
+	return Options{
+		Seed:          seed,
+		MaxTimerDelay: 2 * time.Second,
+		DropRate:      0.3,
+		RestartEvery:  5 * time.Second,
+	}
+}
+
+// Injector produces faulty versions of the engine's collaborators.
+// All of them share one seeded source so a run can be reproduced.
+type Injector struct {
+	opts Options
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+// New creates an Injector.
+func New(opts Options) *Injector {
+	if opts.Logf == nil {
+		opts.Logf = func(string, ...any) {}
+	}
+	return &Injector{
+		opts: opts,
+		rng:  rand.New(rand.NewPCG(opts.Seed, opts.Seed^0x9e3779b97f4a7c15)),
+	}
+}
+
+func (in *Injector) float() float64 {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.rng.Float64()
+}
+
+func (in *Injector) jitter(limit time.Duration) time.Duration {
+	if limit <= 0 {
+		return 0
+	}
+	return time.Duration(in.float() * float64(limit))
+}
+
+// Clock returns a core.MonotonicClock whose timers fire up to
+// MaxTimerDelay late.
+func (in *Injector) Clock() core.MonotonicClock {
+	return faultClock{in: in}
+}
+
+type faultClock struct{ in *Injector }
+
+// start anchors the monotonic readings.
+var start = time.Now()
+
+func (faultClock) Now() time.Time           { return time.Now() }
+func (faultClock) Monotonic() time.Duration { return time.Since(start) }
+
+func (c faultClock) NewTimer(d time.Duration) core.Timer {
+	delay := c.in.jitter(c.in.opts.MaxTimerDelay)
+	if delay > 0 {
+		c.in.opts.Logf("chaos: delaying timer by %v", delay)
+	}
+	return &timer{t: time.NewTimer(max(d, 0) + delay)}
+}
+
+type timer struct{ t *time.Timer }
+
+func (t *timer) C() <-chan time.Time { return t.t.C }
+func (t *timer) Stop() bool          { return t.t.Stop() }
+
+// Notifier wraps n so that a share of calls is silently dropped.
+func (in *Injector) Notifier(n notify.Notifier) notify.Notifier {
+	return faultNotifier{in: in, next: n}
+}
+
+type faultNotifier struct {
+	in   *Injector
+	next notify.Notifier
+}
+
+func (f faultNotifier) Notify(title, body string) error {
+	return f.NotifyMessage(notify.Message{Title: title, Body: body})
+}
+
+func (f faultNotifier) NotifyMessage(msg notify.Message) error {
+	if f.in.float() < f.in.opts.DropRate {
+		f.in.opts.Logf("chaos: dropped notification %q", msg.Body)
+		return nil
+	}
+	return notify.Send(f.next, msg)
+}
+
+// Run kills and restarts the engine's scheduler at random intervals
+// until ctx is done.
+func (in *Injector) Run(ctx context.Context, eng *core.PomodoroEngine) {
+	if in.opts.RestartEvery <= 0 {
+		return
+	}
+	for {
+		wait := in.jitter(2 * in.opts.RestartEvery)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+		in.opts.Logf("chaos: restarting scheduler")
+		eng.Reschedule()
+	}
+}
+
+var (
+	_ core.MonotonicClock    = faultClock{}
+	_ core.Timer             = (*timer)(nil)
+	_ notify.Notifier        = faultNotifier{}
+	_ notify.MessageNotifier = faultNotifier{}
+)
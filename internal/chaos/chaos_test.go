@@ -0,0 +1,85 @@
+package chaos
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestChecker_FlagsBrokenTransitions(t *testing.T) {
+	base := time.Unix(1000, 0)
+	work := core.State{Phase: core.PhaseWork, StartedAt: base, EndsAt: base.Add(time.Minute)}
+
+	cases := []struct {
+		name string
+		ev   core.Event
+		bad  bool
+	}{
+		{
+			name: "valid work end",
+			ev: core.Event{Kind: core.EventAdvance, At: work.EndsAt,
+				State: core.State{Phase: core.PhaseShortBreak, StartedAt: work.EndsAt, PomodoroDone: 1}},
+		},
+		{
+			name: "early advance",
+			ev: core.Event{Kind: core.EventAdvance, At: base.Add(time.Second),
+				State: core.State{Phase: core.PhaseShortBreak, StartedAt: base, PomodoroDone: 1}},
+			bad: true,
+		},
+		{
+			name: "lost count",
+			ev: core.Event{Kind: core.EventAdvance, At: work.EndsAt,
+				State: core.State{Phase: core.PhaseShortBreak, StartedAt: work.EndsAt}},
+			bad: true,
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			c := &Checker{prev: work}
+			c.check(tc.ev)
+			if got := len(c.Violations()) > 0; got != tc.bad {
+				t.Fatalf("want violation=%v, got %v", tc.bad, c.Violations())
+			}
+		})
+	}
+}
+
+// TestSoak runs a fast engine under fault injection and expects the
+// invariants to hold throughout.
+func TestSoak(t *testing.T) {
+	if testing.Short() {
+		t.Skip("soak test")
+	}
+	in := New(Options{
+		Seed:          1,
+		MaxTimerDelay: 3 * time.Millisecond,
+		DropRate:      0.5,
+		RestartEvery:  2 * time.Millisecond,
+	})
+	eng := core.NewWithClock(core.Config{
+		Work:      15 * time.Millisecond,
+		ShortBrk:  5 * time.Millisecond,
+		LongBrk:   10 * time.Millisecond,
+		LongEvery: 2,
+	}, in.Clock())
+	checker, cancel := Watch(eng, nil)
+	defer cancel()
+
+	ctx, stop := context.WithTimeout(context.Background(), 400*time.Millisecond)
+	defer stop()
+	go in.Run(ctx, eng)
+
+	eng.Start()
+	<-ctx.Done()
+	eng.Stop()
+	time.Sleep(20 * time.Millisecond) // let the subscriber drain
+
+	if v := checker.Violations(); len(v) > 0 {
+		t.Fatalf("invariants violated: %v", v)
+	}
+	if eng.State().PomodoroDone != 0 {
+		t.Fatal("expected reset after stop")
+	}
+}
@@ -0,0 +1,111 @@
+package chaos
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Checker watches engine events and records every broken invariant:
+// advancing early or twice for the same phase, advancing while idle or
+// paused, and pomodoro counts that skip or get lost.
+type Checker struct {
+	mu         sync.Mutex
+	prev       core.State
+	violations []string
+	onFail     func(string)
+}
+
+// Watch subscribes a Checker to eng. onFail, if non-nil, is called for
+// every violation as it is found.
+func Watch(eng *core.PomodoroEngine, onFail func(string)) (*Checker, func()) {
+	c := &Checker{prev: eng.State(), onFail: onFail}
+	cancel := eng.Subscribe(c.check)
+	return c, cancel
+}
+
+// Violations returns the invariant failures seen so far.
+func (c *Checker) Violations() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.violations...)
+}
+
+func (c *Checker) failf(format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	c.violations = append(c.violations, msg)
+	if c.onFail != nil {
+		c.onFail(msg)
+	}
+}
+
+func (c *Checker) check(ev core.Event) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	prev, cur := c.prev, ev.State
+	c.prev = cur
+
+	switch ev.Kind {
+	case core.EventAdvance:
+		if prev.Idle() {
+			c.failf("advance while idle")
+			return
+		}
+		if prev.Paused {
+			c.failf("advance while paused")
+		}
+		if ev.At.Before(prev.EndsAt) {
+			c.failf("advanced %v before deadline (double advance?)", prev.EndsAt.Sub(ev.At))
+		}
+		// custom cycles may put any kinds next to each other, so only the
+		// count is checked for them
+		custom := cur.Label != ""
+		switch prev.Phase {
+		case core.PhaseWork:
+			if cur.PomodoroDone != prev.PomodoroDone+1 {
+				c.failf("pomodoro count %d -> %d after work", prev.PomodoroDone, cur.PomodoroDone)
+			}
+			if !custom && cur.Phase == core.PhaseWork {
+				c.failf("work advanced to work")
+			}
+		default:
+			if cur.PomodoroDone != prev.PomodoroDone {
+				c.failf("pomodoro count %d -> %d after break", prev.PomodoroDone, cur.PomodoroDone)
+			}
+			if !custom && cur.Phase != core.PhaseWork {
+				c.failf("break advanced to %v", cur.Phase)
+			}
+		}
+	case core.EventOvertime:
+		if prev.Phase != core.PhaseWork || ev.At.Before(prev.EndsAt) {
+			c.failf("overtime entered from %v before deadline", prev.Phase)
+		}
+	case core.EventAbandon:
+		if prev.Idle() || prev.Overtime {
+			c.failf("abandoned a phase that wasn't running")
+		}
+		// the state doesn't change until the following stop/start
+		c.prev = prev
+	case core.EventSkip:
+		if prev.Idle() {
+			c.failf("skip while idle")
+		}
+		if cur.PomodoroDone != prev.PomodoroDone {
+			c.failf("skip changed pomodoro count %d -> %d", prev.PomodoroDone, cur.PomodoroDone)
+		}
+	case core.EventStart:
+		if cur.PomodoroDone != prev.PomodoroDone {
+			c.failf("start changed pomodoro count %d -> %d", prev.PomodoroDone, cur.PomodoroDone)
+		}
+	case core.EventPause, core.EventResume:
+		if cur.Phase != prev.Phase || cur.PomodoroDone != prev.PomodoroDone {
+			c.failf("%v changed phase or count: %v/%d -> %v/%d",
+				ev.Kind, prev.Phase, prev.PomodoroDone, cur.Phase, cur.PomodoroDone)
+		}
+	case core.EventStop:
+		if !cur.Idle() || cur.PomodoroDone != 0 {
+			c.failf("stop did not reset to idle")
+		}
+	}
+}
@@ -0,0 +1,186 @@
+// Package client talks to a running daemon's HTTP API, for status bar
+// commands and scripts.
+package client
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ezchuang/GoPomodoro/internal/server"
+)
+
+// AddrEnv overrides the default daemon address.
+const AddrEnv = "GOPOMODORO_ADDR"
+
+// Client is an HTTP client for one daemon.
+type Client struct {
+	// BaseURL is the daemon's root, e.g. "http://127.0.0.1:7767".
+	BaseURL string
+	// Timer addresses a named timer of the daemon; empty means the
+	// default one.
+	Timer string
+	// Token is the daemon's API token, which every call but a read
+	// needs.
+	Token string
+	HTTP  *http.Client // defaults to a client with a 2s timeout
+}
+
+// New returns a Client for addr ("host:port" or a URL). An empty addr
+// falls back to $GOPOMODORO_ADDR, then server.DefaultAddr. The token
+// comes from $GOPOMODORO_API_TOKEN, else the file a local daemon keeps
+// it in.
+func New(addr string) *Client {
+	addr = cmp.Or(addr, os.Getenv(AddrEnv), server.DefaultAddr)
+	if !strings.Contains(addr, "://") {
+		addr = "http://" + addr
+	}
+	c := &Client{BaseURL: strings.TrimRight(addr, "/"), Token: os.Getenv(server.TokenEnv)}
+	if c.Token == "" {
+		// without one, control calls fail with the daemon's reason
+		if path, err := server.DefaultTokenPath(); err == nil {
+			c.Token, _ = server.ReadToken(path)
+		}
+	}
+	return c
+}
+
+func (c *Client) http() *http.Client {
+	if c.HTTP != nil {
+		return c.HTTP
+	}
+	return &http.Client{Timeout: 2 * time.Second}
+}
+
+// timerPath prefixes an engine endpoint with the Timer's path.
+func (c *Client) timerPath(path string) string {
+	if c.Timer == "" {
+		return path
+	}
+	return "/timers/" + url.PathEscape(c.Timer) + path
+}
+
+// State fetches the current timer state.
+func (c *Client) State(ctx context.Context) (server.StateJSON, error) {
+	var st server.StateJSON
+	err := c.do(ctx, http.MethodGet, c.timerPath("/state"), &st)
+	return st, err
+}
+
+// Post calls a control endpoint such as "/pause".
+func (c *Client) Post(ctx context.Context, path string) error {
+	return c.do(ctx, http.MethodPost, c.timerPath(path), nil)
+}
+
+// Timers lists the daemon's timers.
+func (c *Client) Timers(ctx context.Context) ([]server.TimerJSON, error) {
+	var list []server.TimerJSON
+	err := c.do(ctx, http.MethodGet, "/timers", &list)
+	return list, err
+}
+
+// Notifiers fetches the health of the daemon's notification backends.
+func (c *Client) Notifiers(ctx context.Context) ([]server.NotifierJSON, error) {
+	var list []server.NotifierJSON
+	err := c.do(ctx, http.MethodGet, "/notifiers", &list)
+	return list, err
+}
+
+// AddTimer creates the timer name, with the timings of profile (empty
+// for the daemon's own).
+func (c *Client) AddTimer(ctx context.Context, name, profile string) error {
+	path := "/timers/" + url.PathEscape(name)
+	if profile != "" {
+		path += "?profile=" + url.QueryEscape(profile)
+	}
+	return c.do(ctx, http.MethodPost, path, nil)
+}
+
+// RemoveTimer stops and removes the timer name.
+func (c *Client) RemoveTimer(ctx context.Context, name string) error {
+	return c.do(ctx, http.MethodDelete, "/timers/"+url.PathEscape(name), nil)
+}
+
+// Watch streams the timer's events, starting with its current state,
+// to fn until fn returns false, ctx ends or the daemon goes away.
+func (c *Client) Watch(ctx context.Context, fn func(server.EventJSON) bool) error {
+	u := "ws" + strings.TrimPrefix(c.BaseURL, "http") + c.timerPath("/ws")
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, u, nil)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	// ReadJSON doesn't take a context, so closing the connection ends it
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+	for {
+		var ev server.EventJSON
+		if err := conn.ReadJSON(&ev); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if !fn(ev) {
+			return nil
+		}
+	}
+}
+
+// do sends a request and decodes a JSON reply into out, if non-nil.
+// Errors carry the daemon's message.
+func (c *Client) do(ctx context.Context, method, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.BaseURL+path, nil)
+	if err != nil {
+		return err
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	resp, err := c.http().Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		if m := strings.TrimSpace(string(msg)); m != "" {
+			return fmt.Errorf("%s %s: %s", method, path, m)
+		}
+		return fmt.Errorf("%s %s: %s", method, path, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	return nil
+}
+
+// Toggle does what a status bar click means: start when idle, take the
+// break in overtime, pause while running and resume when paused.
+func (c *Client) Toggle(ctx context.Context) error {
+	st, err := c.State(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case st.Idle:
+		return c.Post(ctx, "/start")
+	case st.Overtime:
+		return c.Post(ctx, "/acknowledge")
+	case st.Paused:
+		return c.Post(ctx, "/resume")
+	}
+	return c.Post(ctx, "/pause")
+}
@@ -0,0 +1,1011 @@
+// Package config loads the GoPomodoro config file (TOML) and resolves
+// named duration profiles into engine settings.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/i18n"
+)
+
+// DefaultProfile is used when neither the config nor a flag picks one.
+const DefaultProfile = "default"
+
+// Duration is a time.Duration written as a string ("25m") in TOML.
+type Duration struct{ time.Duration }
+
+func (d *Duration) UnmarshalText(b []byte) error {
+	v, err := time.ParseDuration(string(b))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
+
+// Profile is a named set of timings. Zero fields inherit from the
+// built-in default profile.
+type Profile struct {
+	Work          Duration `toml:"work"`
+	Short         Duration `toml:"short"`
+	Long          Duration `toml:"long"`
+	LongEvery     int      `toml:"long_every"`
+	Overtime      bool     `toml:"overtime"`
+	Strict        bool     `toml:"strict"` // no pausing, skipping or extending work
+	Notifications *bool    `toml:"notifications"`
+
+	// Cadence is "count" (default), a long break every LongEvery
+	// pomodoros, or "time", one after LongAfter of work (default 2h).
+	Cadence   string   `toml:"cadence"`
+	LongAfter Duration `toml:"long_after"`
+
+	// Flow makes work open-ended; breaks then last FlowRatio of the time
+	// worked (default 0.2), capped at FlowMaxBreak (default Long).
+	Flow         bool     `toml:"flow"`
+	FlowRatio    float64  `toml:"flow_ratio"`
+	FlowMaxBreak Duration `toml:"flow_max_break"`
+
+	// Cycle replaces work/short/long with a custom sequence of steps.
+	Cycle []Step `toml:"cycle"`
+
+	// Warnings notify this long before each phase ends, e.g. ["2m"];
+	// WarningSound adds a beep.
+	Warnings     []Duration `toml:"warnings"`
+	WarningSound bool       `toml:"warning_sound"`
+
+	// ClockPolicy is what a running phase does when the wall clock
+	// jumps: "monotonic" (default) keeps the time left, "wall" keeps the
+	// end time, "pause" pauses on a jump ahead.
+	ClockPolicy string `toml:"clock_policy"`
+
+	// QuittingTime, a local time like "18:00", ends the workday: work
+	// no longer starts after it unless overridden.
+	QuittingTime string `toml:"quitting_time"`
+}
+
+// Step is one phase of a custom cycle.
+type Step struct {
+	Name     string   `toml:"name"`
+	Kind     string   `toml:"kind"` // work, short_break or long_break
+	Duration Duration `toml:"duration"`
+}
+
+// kind resolves the step's phase. Without an explicit kind, a step
+// named like one ("work", "long") takes that kind; anything else is a
+// short break.
+func (s Step) kind() (core.Phase, bool) {
+	if s.Kind == "" {
+		if ph, ok := parseKind(s.Name); ok {
+			return ph, true
+		}
+	}
+	return parseKind(s.Kind)
+}
+
+// parseKind maps a step kind to a phase. Finishing a work step counts a
+// pomodoro; the break kinds only differ in how stats label them.
+func parseKind(s string) (core.Phase, bool) {
+	switch strings.ToLower(s) {
+	case "work":
+		return core.PhaseWork, true
+	case "", "break", "short", "short_break":
+		return core.PhaseShortBreak, true
+	case "long", "long_break":
+		return core.PhaseLongBreak, true
+	}
+	return 0, false
+}
+
+// Core converts the profile into an engine Config. The profile must
+// have been validated by Resolve.
+func (p Profile) Core() core.Config {
+	cfg := core.Config{
+		Work:      p.Work.Duration,
+		ShortBrk:  p.Short.Duration,
+		LongBrk:   p.Long.Duration,
+		LongEvery: p.LongEvery,
+		Overtime:  p.Overtime,
+		Strict:    p.Strict,
+
+		Flow:         p.Flow,
+		FlowRatio:    p.FlowRatio,
+		FlowMaxBreak: p.FlowMaxBreak.Duration,
+	}
+	cfg.ClockPolicy, _ = core.ParseClockPolicy(p.ClockPolicy)
+	cfg.Cadence, _ = core.ParseCadence(p.Cadence)
+	cfg.LongAfter = p.LongAfter.Duration
+	if p.QuittingTime != "" {
+		cfg.QuittingTime, _ = parseClock(p.QuittingTime)
+	}
+	for _, w := range p.Warnings {
+		cfg.Warnings = append(cfg.Warnings, w.Duration)
+	}
+	for _, st := range p.Cycle {
+		kind, _ := st.kind()
+		cfg.Cycle = append(cfg.Cycle, core.Step{Name: st.Name, Kind: kind, Duration: st.Duration.Duration})
+	}
+	return cfg
+}
+
+// NotificationsEnabled reports whether phase notifications are on.
+func (p Profile) NotificationsEnabled() bool {
+	return p.Notifications == nil || *p.Notifications
+}
+
+// File is the parsed config file.
+type File struct {
+	Profile  string             `toml:"profile"`
+	Goal     int                `toml:"goal"` // daily pomodoro target, 0 for none
+	Profiles map[string]Profile `toml:"profiles"`
+	Notify   Notify             `toml:"notify"`
+	// Locale picks the language of the TUI and notifications, one of
+	// i18n.Locales; empty follows $LANG.
+	Locale string `toml:"locale"`
+
+	// Schedule switches profiles by weekday and time of day; the first
+	// matching rule wins, Profile applies outside them all.
+	Schedule []Schedule `toml:"schedule"`
+
+	Integrations Integrations `toml:"integrations"`
+	Idle         Idle         `toml:"idle"`
+	DND          DND          `toml:"dnd"`
+	Enforce      Enforce      `toml:"enforce"`
+	Block        Block        `toml:"block"`
+	Meetings     *Meetings    `toml:"meetings"`
+	Log          *Log         `toml:"log"`
+	Suggestions  Suggestions  `toml:"suggestions"`
+	Summary      *Summary     `toml:"summary"`
+	WeeklyEmail  *WeeklyEmail `toml:"weekly_email"`
+	Leaderboard  *Leaderboard `toml:"leaderboard"`
+	Sync         *Sync        `toml:"sync"`
+	API          API          `toml:"api"`
+
+	// Timers are extra named timers next to the default one, each
+	// running the profile it maps to, e.g. laundry = "laundry".
+	Timers map[string]string `toml:"timers"`
+
+	// Theme names the TUI color scheme; Themes adds custom ones.
+	Theme  string           `toml:"theme"`
+	Themes map[string]Theme `toml:"themes"`
+
+	// CountUp shows the time elapsed in the TUI instead of the time
+	// left.
+	CountUp bool `toml:"count_up"`
+	// Reset asks before the TUI's reset key abandons a pomodoro.
+	Reset Reset `toml:"reset"`
+
+	// Keys remaps TUI actions, e.g. skip = "n" or quit = ["q", "esc"].
+	Keys map[string]Keys `toml:"keys"`
+
+	// Project is the project config in force, set by UseProject.
+	Project *Project `toml:"-"`
+}
+
+// Theme is a custom TUI color scheme. Colors are "#rrggbb" or ANSI
+// numbers; unset fields come from Base (default "default").
+type Theme struct {
+	Base          string `toml:"base"`
+	GradientStart string `toml:"gradient_start"`
+	GradientEnd   string `toml:"gradient_end"`
+	Work          string `toml:"work"`
+	ShortBreak    string `toml:"short_break"`
+	LongBreak     string `toml:"long_break"`
+	Overtime      string `toml:"overtime"`
+	Goal          string `toml:"goal"`
+	Faint         string `toml:"faint"`
+	Border        string `toml:"border"` // rounded, normal, thick, double or hidden
+
+	// Bars sets the progress bar per phase, keyed work, short_break or
+	// long_break.
+	Bars map[string]Bar `toml:"bars"`
+}
+
+// Bar is a phase's progress bar. Unset fields come from the base
+// theme's bar for the phase, then from the theme's gradient and the
+// phase's default mode.
+type Bar struct {
+	GradientStart string `toml:"gradient_start"`
+	GradientEnd   string `toml:"gradient_end"`
+	// Mode is "fill", growing as the phase passes (the default for
+	// work), or "drain", shrinking (the default for breaks).
+	Mode string `toml:"mode"`
+}
+
+// Keys is a list of key names; a single string is accepted too.
+type Keys []string
+
+func (k *Keys) UnmarshalTOML(v any) error {
+	switch v := v.(type) {
+	case string:
+		*k = Keys{v}
+	case []any:
+		ks := make(Keys, 0, len(v))
+		for _, e := range v {
+			s, ok := e.(string)
+			if !ok {
+				return fmt.Errorf("key binding must be a string, got %T", e)
+			}
+			ks = append(ks, s)
+		}
+		*k = ks
+	default:
+		return fmt.Errorf("key binding must be a string or list, got %T", v)
+	}
+	return nil
+}
+
+// Log appends finished pomodoros to plain-text daily notes. Path and
+// Template are Go templates, e.g. "~/notes/{{.Date}}.md".
+type Log struct {
+	Path      string `toml:"path"`
+	Template  string `toml:"template"`
+	Abandoned bool   `toml:"abandoned"` // also log abandoned pomodoros
+}
+
+// Summary delivers an end-of-day report at At ("18:00") and/or when
+// the daemon shuts down, as a notification and/or a Markdown file at
+// Path, a template like "~/pomodoro/{{.Date}}.md".
+type Summary struct {
+	At         string `toml:"at"`
+	OnShutdown bool   `toml:"on_shutdown"`
+	Notify     *bool  `toml:"notify"` // default true
+	Path       string `toml:"path"`
+	Top        int    `toml:"top"` // tasks listed; default 3
+}
+
+// NotifyEnabled reports whether the report is sent as a notification.
+func (s Summary) NotifyEnabled() bool {
+	return s.Notify == nil || *s.Notify
+}
+
+// WeeklyEmail mails a digest of the past seven days at At, e.g.
+// "Fri 17:00", through the SMTP server at host:port. Password falls
+// back to $SMTP_PASSWORD.
+type WeeklyEmail struct {
+	At       string   `toml:"at"`
+	To       []string `toml:"to"`
+	From     string   `toml:"from"` // default the first recipient
+	SMTP     string   `toml:"smtp"`
+	Username string   `toml:"username"`
+	Password string   `toml:"password"`
+	Top      int      `toml:"top"` // tasks listed; default 5
+}
+
+// Leaderboard reports the pomodoros completed each day to a team
+// leaderboard server, whose board the TUI shows. Token falls back to
+// $LEADERBOARD_TOKEN.
+type Leaderboard struct {
+	URL   string `toml:"url"`
+	Token string `toml:"token"`
+	// Server configures "gopomodoro leaderboard", which runs one.
+	Server *LeaderboardServer `toml:"server"`
+}
+
+// LeaderboardServer configures the leaderboard server.
+type LeaderboardServer struct {
+	Listen string `toml:"listen"` // default :7770
+	Data   string `toml:"data"`   // where the board is kept; default next to the history
+	// Members maps each member's name to the token they report with.
+	Members map[string]string `toml:"members"`
+}
+
+// Sync merges the history with that of the user's other machines
+// through one remote store: an S3 bucket, a WebDAV folder or a git
+// repository.
+type Sync struct {
+	Machine string   `toml:"machine"` // names this machine's object; default the hostname
+	Every   Duration `toml:"every"`   // default 15m
+
+	S3     *SyncS3     `toml:"s3"`
+	WebDAV *SyncWebDAV `toml:"webdav"`
+	Git    *SyncGit    `toml:"git"`
+}
+
+// SyncS3 keeps the history in an S3-compatible bucket. The keys fall
+// back to $AWS_ACCESS_KEY_ID and $AWS_SECRET_ACCESS_KEY.
+type SyncS3 struct {
+	Endpoint  string `toml:"endpoint"` // default AWS's for the region
+	Region    string `toml:"region"`   // default us-east-1
+	Bucket    string `toml:"bucket"`
+	Prefix    string `toml:"prefix"`
+	AccessKey string `toml:"access_key"`
+	SecretKey string `toml:"secret_key"`
+}
+
+// SyncWebDAV keeps the history in a WebDAV folder, e.g. on Nextcloud.
+// Password falls back to $WEBDAV_PASSWORD.
+type SyncWebDAV struct {
+	URL      string `toml:"url"`
+	Username string `toml:"username"`
+	Password string `toml:"password"`
+}
+
+// SyncGit keeps the history in a git repository, pushed with the
+// user's own git setup.
+type SyncGit struct {
+	Repo string `toml:"repo"`
+	Dir  string `toml:"dir"` // the clone; default sync.git next to the history
+}
+
+// validate checks that exactly one remote store is set.
+func (s *Sync) validate() error {
+	if s == nil {
+		return nil
+	}
+	n := 0
+	for _, set := range []bool{s.S3 != nil, s.WebDAV != nil, s.Git != nil} {
+		if set {
+			n++
+		}
+	}
+	if n != 1 {
+		return errors.New("sync: set exactly one of [sync.s3], [sync.webdav] and [sync.git]")
+	}
+	if s.Every.Duration < 0 {
+		return errors.New("sync: every must not be negative")
+	}
+	return nil
+}
+
+// API configures the HTTP API that -listen serves. Web pages may use it
+// from its own origin and from localhost; Origins lets others in, e.g.
+// "https://dash.example.com", "null" for pages opened from files, or
+// "*" for any.
+type API struct {
+	Origins []string `toml:"origins"`
+}
+
+// validate checks that every origin is a bare scheme://host[:port],
+// "null" or "*".
+func (a API) validate() error {
+	for _, o := range a.Origins {
+		if o == "null" || o == "*" {
+			continue
+		}
+		u, err := url.Parse(o)
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+			return fmt.Errorf("api: origin %q isn't scheme://host[:port]", o)
+		}
+	}
+	return nil
+}
+
+// Reset configures the TUI's reset key: whether it asks before
+// abandoning a running pomodoro, and whether it then asks why.
+type Reset struct {
+	Confirm *bool `toml:"confirm"` // default true
+	Reason  bool  `toml:"reason"`
+}
+
+// Confirms reports whether reset asks before abandoning a pomodoro.
+func (r Reset) Confirms() bool {
+	return r.Confirm == nil || *r.Confirm
+}
+
+// Suggestions are break activities shown in break notifications and
+// the TUI: Short ones for short breaks, Long ones for long breaks. An
+// unset list uses the built-in suggestions.
+type Suggestions struct {
+	Enabled *bool    `toml:"enabled"` // default true
+	Short   []string `toml:"short"`
+	Long    []string `toml:"long"`
+}
+
+// On reports whether break suggestions are shown.
+func (s Suggestions) On() bool {
+	return s.Enabled == nil || *s.Enabled
+}
+
+// Idle configures automatic pauses when the user is away. A zero After
+// turns them off.
+type Idle struct {
+	After    Duration `toml:"after"`
+	Poll     Duration `toml:"poll"`
+	OnReturn string   `toml:"on_return"` // resume, discard or stay
+}
+
+// DND turns on the system's Do Not Disturb mode during work phases.
+type DND struct {
+	Enabled bool `toml:"enabled"`
+	// On and Off are commands to use instead of the built-in backend.
+	On  []string `toml:"on"`
+	Off []string `toml:"off"`
+}
+
+// Enforce makes breaks hard to skip: Mode "lock" locks the screen when
+// a break starts, "overlay" covers the TUI until Dismiss has passed.
+type Enforce struct {
+	Mode     string   `toml:"mode"`
+	Dismiss  Duration `toml:"dismiss"`   // overlay; default 30s
+	LongOnly bool     `toml:"long_only"` // leave short breaks alone
+	// Command locks the screen instead of the built-in way.
+	Command []string `toml:"command"`
+}
+
+// Block keeps distracting sites out of reach during work phases by
+// pointing them nowhere in the hosts file.
+type Block struct {
+	Enabled bool     `toml:"enabled"`
+	Domains []string `toml:"domains"` // www. variants are added
+	Hosts   string   `toml:"hosts"`   // default the system hosts file
+	// Sudo runs the helper with "sudo -n" when the hosts file isn't
+	// writable; default true.
+	Sudo *bool `toml:"sudo"`
+}
+
+// Meetings keeps pomodoros clear of the meetings on a calendar.
+// Password falls back to $MEETINGS_PASSWORD.
+type Meetings struct {
+	URL       string   `toml:"url"`    // .ics feed or file, or a CalDAV collection
+	CalDAV    bool     `toml:"caldav"` // URL is a CalDAV collection
+	Username  string   `toml:"username"`
+	Password  string   `toml:"password"`
+	Policy    string   `toml:"policy"`     // warn, refuse or shorten; default warn
+	Margin    Duration `toml:"margin"`     // kept free before a meeting; default 2m
+	MinLength Duration `toml:"min_length"` // shortest shortened pomodoro; default 10m
+	Refresh   Duration `toml:"refresh"`    // default 10m
+}
+
+// Integrations configures third-party services driven by the timer.
+type Integrations struct {
+	Slack       *Slack       `toml:"slack"`
+	Taskwarrior *Taskwarrior `toml:"taskwarrior"`
+	Todoist     *Todoist     `toml:"todoist"`
+	TaskFile    *TaskFile    `toml:"task_file"`
+	Obsidian    *Obsidian    `toml:"obsidian"`
+	Media       *Media       `toml:"media"`
+	Spotify     *Spotify     `toml:"spotify"`
+	Calendar    *Calendar    `toml:"google_calendar"`
+	MQTT        *MQTT        `toml:"mqtt"`
+	Jira        *Jira        `toml:"jira"`
+	GitHub      *GitHub      `toml:"github"`
+	Telegram    *Telegram    `toml:"telegram"`
+}
+
+// Telegram sends notifications to chats through a bot and takes timer
+// commands, such as /pause, from them. Token falls back to
+// $TELEGRAM_TOKEN.
+type Telegram struct {
+	Token string  `toml:"token"` // from @BotFather
+	Chats []int64 `toml:"chats"` // notified, and allowed to send commands
+	// Notify filters the notifications like any backend's section;
+	// enabled = false leaves only the commands.
+	Notify  *NotifyBackend `toml:"notify"`
+	Control *bool          `toml:"control"` // take commands; default true
+}
+
+// validate checks the quiet mode of the bot's notifications.
+func (t *Telegram) validate() error {
+	if t == nil || t.Notify == nil || t.Notify.Quiet == "" || slices.Contains(QuietModes, t.Notify.Quiet) {
+		return nil
+	}
+	return fmt.Errorf("integrations.telegram.notify: quiet %q is not one of %s", t.Notify.Quiet, strings.Join(QuietModes, ", "))
+}
+
+// Controls reports whether the bot takes commands.
+func (t *Telegram) Controls() bool {
+	return t.Control == nil || *t.Control
+}
+
+// GitHub offers the issues assigned to you in the task picker and
+// reports the pomodoros spent on the attached issue. Token, a personal
+// access token, falls back to $GITHUB_TOKEN.
+type GitHub struct {
+	Token   string `toml:"token"`
+	Comment bool   `toml:"comment"` // 🍅 comment with the time spent per pomodoro
+	Field   string `toml:"field"`   // project number field to add the time to, e.g. "Time spent"
+	Unit    string `toml:"unit"`    // of field: hours (default) or minutes
+}
+
+// Jira logs work sessions tagged with an issue key, e.g. #SHOP-42, as
+// worklogs on the issue. Token falls back to $JIRA_TOKEN.
+type Jira struct {
+	URL     string   `toml:"url"`     // e.g. https://example.atlassian.net
+	Email   string   `toml:"email"`   // Jira Cloud account; empty to use Token as a personal access token
+	Token   string   `toml:"token"`   // API token
+	Comment string   `toml:"comment"` // default "🍅 Pomodoro"
+	Batch   Duration `toml:"batch"`   // merge worklogs per issue over this long
+	DryRun  bool     `toml:"dry_run"` // log what would be sent instead
+}
+
+// MQTT publishes the timer's state to an MQTT broker. Password falls
+// back to $MQTT_PASSWORD.
+type MQTT struct {
+	Broker   string     `toml:"broker"` // mqtt://host:1883 or mqtts://host:8883
+	Username string     `toml:"username"`
+	Password string     `toml:"password"`
+	ClientID string     `toml:"client_id"` // default "gopomodoro"
+	Prefix   string     `toml:"prefix"`    // topic prefix; default "gopomodoro"
+	QoS      int        `toml:"qos"`       // 0 or 1
+	Interval Duration   `toml:"interval"`  // remaining-time updates; default 10s
+	Topics   MQTTTopics `toml:"topics"`
+	// HomeAssistant announces the timer through MQTT discovery, with
+	// buttons that publish to the command topic.
+	HomeAssistant   bool   `toml:"home_assistant"`
+	DiscoveryPrefix string `toml:"discovery_prefix"` // default "homeassistant"
+}
+
+// MQTTTopics overrides single topics; unset ones go under the prefix.
+type MQTTTopics struct {
+	State        string `toml:"state"`
+	Phase        string `toml:"phase"`
+	Remaining    string `toml:"remaining"`
+	Event        string `toml:"event"`
+	Availability string `toml:"availability"`
+	// Command enables control by payloads such as "start"; on by
+	// default with home_assistant.
+	Command string `toml:"command"`
+}
+
+// Calendar blocks work phases as busy "Focus" events on Google
+// Calendar. ClientID and ClientSecret fall back to $GOOGLE_CLIENT_ID and
+// $GOOGLE_CLIENT_SECRET.
+type Calendar struct {
+	ClientID     string `toml:"client_id"`
+	ClientSecret string `toml:"client_secret"`
+	Calendar     string `toml:"calendar"` // calendar ID; default "primary"
+	Summary      string `toml:"summary"`  // event title; default "Focus"
+	ShowTask     bool   `toml:"show_task"`
+}
+
+// Obsidian keeps a focus summary under Heading in the vault's daily
+// notes, leaving the rest of each note alone.
+type Obsidian struct {
+	Vault      string `toml:"vault"`
+	Folder     string `toml:"folder"`      // daily notes folder in the vault
+	DateFormat string `toml:"date_format"` // Go layout; default "2006-01-02"
+	Heading    string `toml:"heading"`     // default "## Focus"
+	Template   string `toml:"template"`    // one line per pomodoro
+}
+
+// Media pauses and plays music players on phase changes.
+type Media struct {
+	Player string `toml:"player"` // e.g. "spotify"; default any MPRIS player / Music on macOS
+	MediaActions
+}
+
+// MediaActions says what phase changes do to playback: play, pause or
+// none.
+type MediaActions struct {
+	Work       string `toml:"work"`        // default play
+	ShortBreak string `toml:"short_break"` // default pause
+	LongBreak  string `toml:"long_break"`  // default pause
+	Stop       string `toml:"stop"`        // default none
+	Playlist   string `toml:"playlist"`    // URI to play when work starts
+}
+
+// Spotify controls playback through the Spotify Web API, on any Spotify
+// Connect device. ClientID falls back to $SPOTIFY_CLIENT_ID.
+type Spotify struct {
+	ClientID string `toml:"client_id"`
+	Device   string `toml:"device"` // name or ID; default the active device
+	MediaActions
+}
+
+// Todoist offers Todoist tasks in the task picker. Token falls back to
+// $TODOIST_TOKEN.
+type Todoist struct {
+	Token         string `toml:"token"`
+	Filter        string `toml:"filter"`         // e.g. "today | overdue"
+	Comment       bool   `toml:"comment"`        // 🍅 comment per pomodoro
+	CompleteAfter int    `toml:"complete_after"` // complete after N pomodoros
+}
+
+// TaskFile offers the open tasks of a local to-do file in the TUI's
+// task picker and panel, and counts completed pomodoros in it.
+type TaskFile struct {
+	Path string `toml:"path"` // e.g. "~/today.md"
+}
+
+// Taskwarrior offers pending taskwarrior tasks in the TUI's task picker
+// and starts the attached one while working.
+type Taskwarrior struct {
+	Command string `toml:"command"` // default "task"
+	Filter  string `toml:"filter"`  // e.g. "+work"
+	UDA     string `toml:"uda"`     // numeric UDA bumped per pomodoro
+	Track   *bool  `toml:"track"`   // task start/stop with work; default true
+}
+
+// Slack configures the Slack status integration. Token falls back to
+// $SLACK_TOKEN so it can stay out of the file.
+type Slack struct {
+	Token string `toml:"token"`
+	Emoji string `toml:"emoji"`
+	Text  string `toml:"text"`
+	DND   *bool  `toml:"dnd"` // default true
+}
+
+// Notify configures the notification backends, any number of which can
+// be on at once. The desktop one is on unless disabled; the others are
+// on once their section is present. Integrations.Telegram is one too.
+type Notify struct {
+	Desktop  *NotifyBackend `toml:"desktop"`
+	Sound    *Sound         `toml:"sound"`
+	Bell     *Bell          `toml:"bell"`
+	Log      *NotifyLog     `toml:"log"`
+	Webhook  *Webhook       `toml:"webhook"`
+	Speech   *Speech        `toml:"speech"`
+	Ntfy     *Ntfy          `toml:"ntfy"`
+	Pushover *Pushover      `toml:"pushover"`
+	Matrix   *Matrix        `toml:"matrix"`
+	IRC      *IRC           `toml:"irc"`
+	Quiet    *QuietHours    `toml:"quiet"`
+	// Templates rewrite notifications per event (see notify.Topics)
+	// with Go templates; see notify.TemplateData for the fields.
+	Templates map[string]NotifyTemplate `toml:"templates"`
+	// Routes send an event's notifications to the named backends only,
+	// e.g. work_end = ["desktop", "sound"], over their events filters.
+	Routes map[string][]string `toml:"routes"`
+}
+
+// NotifyBackends are the backends a route can name.
+var NotifyBackends = []string{"desktop", "sound", "bell", "log", "webhook", "speech", "ntfy", "pushover", "matrix", "irc", "telegram"}
+
+// validateRoutes checks the backends every route names.
+func (n Notify) validateRoutes() error {
+	for topic, names := range n.Routes {
+		for _, name := range names {
+			if !slices.Contains(NotifyBackends, name) {
+				return fmt.Errorf("notify.routes.%s: unknown backend %q (want one of %s)", topic, name, strings.Join(NotifyBackends, ", "))
+			}
+		}
+	}
+	return nil
+}
+
+// NotifyTemplate is an event's title and body template; either may be
+// left out to keep the built-in text.
+type NotifyTemplate struct {
+	Title string `toml:"title"`
+	Body  string `toml:"body"`
+}
+
+// NotifyBackend holds the settings every backend has.
+type NotifyBackend struct {
+	Enabled *bool `toml:"enabled"`
+	// Events limits the backend to these, e.g. ["work_end"]; see
+	// notify.Topics. Empty means all.
+	Events []string `toml:"events"`
+	// Quiet overrides QuietHours.Mode for this backend; "off" lets it
+	// notify during quiet hours as usual.
+	Quiet string `toml:"quiet"`
+}
+
+// QuietHours holds notifications back on Days from From to To, local
+// times like "22:00", as in Schedule; the timer keeps running.
+type QuietHours struct {
+	Days string `toml:"days"`
+	From string `toml:"from"`
+	To   string `toml:"to"`
+	// Mode is what the backends do meanwhile: "mute" (the default)
+	// drops notifications, "silent" delivers them without sound.
+	Mode string `toml:"mode"`
+}
+
+// QuietModes are the values of QuietHours.Mode and
+// NotifyBackend.Quiet.
+var QuietModes = []string{"mute", "silent", "off"}
+
+// Covers reports whether t falls in the quiet hours; never for a nil q.
+func (q *QuietHours) Covers(t time.Time) bool {
+	if q == nil {
+		return false
+	}
+	r, err := parseSpan(q.Days, q.From, q.To)
+	return err == nil && r.matches(t)
+}
+
+// QuietMode is what backend b does during quiet hours: its own Quiet,
+// else the quiet hours' Mode, else "mute".
+func (n Notify) QuietMode(b *NotifyBackend) string {
+	if b != nil && b.Quiet != "" {
+		return b.Quiet
+	}
+	if n.Quiet != nil && n.Quiet.Mode != "" {
+		return n.Quiet.Mode
+	}
+	return "mute"
+}
+
+// validateQuiet checks the quiet hours and every quiet mode.
+func (n Notify) validateQuiet() error {
+	if q := n.Quiet; q != nil {
+		if _, err := parseSpan(q.Days, q.From, q.To); err != nil {
+			return fmt.Errorf("notify.quiet: %w", err)
+		}
+		if q.Mode != "" && !slices.Contains(QuietModes, q.Mode) {
+			return fmt.Errorf("notify.quiet: mode %q is not one of %s", q.Mode, strings.Join(QuietModes, ", "))
+		}
+	}
+	backends := map[string]*NotifyBackend{"desktop": n.Desktop}
+	if n.Sound != nil {
+		backends["sound"] = &n.Sound.NotifyBackend
+	}
+	if n.Bell != nil {
+		backends["bell"] = &n.Bell.NotifyBackend
+	}
+	if n.Log != nil {
+		backends["log"] = &n.Log.NotifyBackend
+	}
+	if n.Webhook != nil {
+		backends["webhook"] = &n.Webhook.NotifyBackend
+	}
+	if n.Speech != nil {
+		backends["speech"] = &n.Speech.NotifyBackend
+	}
+	if n.Ntfy != nil {
+		backends["ntfy"] = &n.Ntfy.NotifyBackend
+	}
+	if n.Pushover != nil {
+		backends["pushover"] = &n.Pushover.NotifyBackend
+	}
+	if n.Matrix != nil {
+		backends["matrix"] = &n.Matrix.NotifyBackend
+	}
+	if n.IRC != nil {
+		backends["irc"] = &n.IRC.NotifyBackend
+	}
+	for name, b := range backends {
+		if b != nil && b.Quiet != "" && !slices.Contains(QuietModes, b.Quiet) {
+			return fmt.Errorf("notify.%s: quiet %q is not one of %s", name, b.Quiet, strings.Join(QuietModes, ", "))
+		}
+	}
+	return nil
+}
+
+// On reports whether a backend with this section is enabled; def
+// applies when the section is missing.
+func (b *NotifyBackend) On(def bool) bool {
+	if b == nil {
+		return def
+	}
+	return b.Enabled == nil || *b.Enabled
+}
+
+// Sound configures the sound notifier.
+type Sound struct {
+	NotifyBackend
+	// Command plays the sound, e.g. ["paplay", "bell.oga"]; default is
+	// the system beep.
+	Command []string `toml:"command"`
+}
+
+// Bell configures the terminal notifier.
+type Bell struct {
+	NotifyBackend
+	Mode string `toml:"mode"` // bell, osc9, osc777 or auto (default)
+}
+
+// NotifyLog configures the log notifier.
+type NotifyLog struct {
+	NotifyBackend
+	Path string `toml:"path"` // "-" for stderr
+}
+
+// Webhook configures the webhook notifier.
+type Webhook struct {
+	NotifyBackend
+	URLs    []string          `toml:"urls"`
+	Timeout Duration          `toml:"timeout"`
+	Retries int               `toml:"retries"`
+	Headers map[string]string `toml:"headers"`
+}
+
+// Speech configures the text-to-speech notifier.
+type Speech struct {
+	NotifyBackend
+	Voice string `toml:"voice"` // e.g. "Samantha"; default the system's
+	Rate  int    `toml:"rate"`  // words per minute; default the system's
+	// Command replaces the platform's speech, e.g. ["espeak-ng",
+	// "-v", "de"]; the text is added as the last argument.
+	Command []string `toml:"command"`
+	// Messages are Go templates of what to say per event, e.g.
+	// short_break_start = "Break time, {{.Minutes}} minutes".
+	Messages map[string]string `toml:"messages"`
+}
+
+// Ntfy configures the ntfy push notifier. Token falls back to
+// $NTFY_TOKEN.
+type Ntfy struct {
+	NotifyBackend
+	Server   string   `toml:"server"`   // default https://ntfy.sh
+	Topic    string   `toml:"topic"`    // hard to guess on the public server
+	Token    string   `toml:"token"`    // access token, for a protected topic
+	Priority int      `toml:"priority"` // 1 (min) to 5 (max)
+	Timeout  Duration `toml:"timeout"`
+	Retries  int      `toml:"retries"`
+}
+
+// Pushover configures the Pushover push notifier. Token and User fall
+// back to $PUSHOVER_TOKEN and $PUSHOVER_USER.
+type Pushover struct {
+	NotifyBackend
+	Token    string   `toml:"token"`    // the application's API token
+	User     string   `toml:"user"`     // user or group key
+	Device   string   `toml:"device"`   // default all of the user's devices
+	Sound    string   `toml:"sound"`    // default the user's
+	Priority int      `toml:"priority"` // -2 to 1
+	Timeout  Duration `toml:"timeout"`
+	Retries  int      `toml:"retries"`
+}
+
+// Matrix configures the Matrix room notifier. Token falls back to
+// $MATRIX_TOKEN.
+type Matrix struct {
+	NotifyBackend
+	Homeserver string   `toml:"homeserver"` // e.g. https://matrix.org
+	Room       string   `toml:"room"`       // ID or alias, e.g. "#focus:matrix.org"
+	Token      string   `toml:"token"`      // the posting account's access token
+	Notice     bool     `toml:"notice"`     // post notices, which don't alert
+	Timeout    Duration `toml:"timeout"`
+	Retries    int      `toml:"retries"`
+}
+
+// IRC configures the IRC channel notifier. Password falls back to
+// $IRC_PASSWORD.
+type IRC struct {
+	NotifyBackend
+	Server   string   `toml:"server"` // host:port
+	TLS      bool     `toml:"tls"`
+	Nick     string   `toml:"nick"` // default gopomodoro
+	Password string   `toml:"password"`
+	Channels []string `toml:"channels"`
+	Join     *bool    `toml:"join"`   // default true; false for channels open to outside messages
+	Notice   bool     `toml:"notice"` // send notices, which don't alert
+	Timeout  Duration `toml:"timeout"`
+}
+
+func minutes(n int) Duration { return Duration{time.Duration(n) * time.Minute} }
+
+// defaultLongAfter is the focus time before a long break with the
+// "time" cadence.
+const defaultLongAfter = 2 * time.Hour
+
+// builtin profiles are always available and can be overridden.
+var builtin = map[string]Profile{
+	"default":   {Work: minutes(25), Short: minutes(5), Long: minutes(15), LongEvery: 4},
+	"deep-work": {Work: minutes(50), Short: minutes(10), Long: minutes(30), LongEvery: 4},
+}
+
+// DefaultPath returns $XDG_CONFIG_HOME/gopomodoro/config.toml or the
+// platform equivalent.
+func DefaultPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "gopomodoro", "config.toml"), nil
+}
+
+// Load reads the config at path. A missing file is not an error; it
+// yields the built-in profiles only.
+func Load(path string) (*File, error) {
+	f := &File{}
+	if path != "" {
+		_, err := toml.DecodeFile(path, f)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return nil, fmt.Errorf("config %s: %w", path, err)
+		}
+	}
+	if f.Profiles == nil {
+		f.Profiles = make(map[string]Profile)
+	}
+	for name, p := range builtin {
+		if _, ok := f.Profiles[name]; !ok {
+			f.Profiles[name] = p
+		}
+	}
+	if f.Profile == "" {
+		f.Profile = DefaultProfile
+	}
+	if err := f.validateSchedule(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := f.Notify.validateQuiet(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := f.Notify.validateRoutes(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := f.Integrations.Telegram.validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := f.Sync.validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if err := f.API.validate(); err != nil {
+		return nil, fmt.Errorf("config %s: %w", path, err)
+	}
+	if f.Locale != "" {
+		if _, err := i18n.New(f.Locale); err != nil {
+			return nil, fmt.Errorf("config %s: locale: %w", path, err)
+		}
+	}
+	return f, nil
+}
+
+// Names returns the profile names, sorted.
+func (f *File) Names() []string {
+	names := make([]string, 0, len(f.Profiles))
+	for n := range f.Profiles {
+		names = append(names, n)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// Resolve returns the named profile with unset fields filled from the
+// built-in default. An empty name selects the file's default profile.
+func (f *File) Resolve(name string) (Profile, error) {
+	if name == "" {
+		name = f.Profile
+	}
+	p, ok := f.Profiles[name]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q", name)
+	}
+	def := builtin[DefaultProfile]
+	if p.Work.Duration <= 0 {
+		p.Work = def.Work
+	}
+	if p.Short.Duration <= 0 {
+		p.Short = def.Short
+	}
+	if p.Long.Duration <= 0 {
+		p.Long = def.Long
+	}
+	// a negative long_every turns long breaks off
+	if p.LongEvery == 0 {
+		p.LongEvery = def.LongEvery
+	}
+	if p.Cadence != "" {
+		if _, ok := core.ParseCadence(p.Cadence); !ok {
+			return Profile{}, fmt.Errorf("profile %q: cadence must be count or time", name)
+		}
+	}
+	if p.LongAfter.Duration <= 0 {
+		p.LongAfter = Duration{defaultLongAfter}
+	}
+	if p.FlowRatio < 0 || p.FlowRatio > 1 {
+		return Profile{}, fmt.Errorf("profile %q: flow_ratio must be between 0 and 1", name)
+	}
+	if p.ClockPolicy != "" {
+		if _, ok := core.ParseClockPolicy(p.ClockPolicy); !ok {
+			return Profile{}, fmt.Errorf("profile %q: clock_policy must be monotonic, wall or pause", name)
+		}
+	}
+	if p.QuittingTime != "" {
+		if _, err := parseClock(p.QuittingTime); err != nil {
+			return Profile{}, fmt.Errorf("profile %q: quitting_time: %w", name, err)
+		}
+	}
+	for _, w := range p.Warnings {
+		if w.Duration <= 0 {
+			return Profile{}, fmt.Errorf("profile %q: warnings must be positive", name)
+		}
+	}
+	for i, st := range p.Cycle {
+		if _, ok := st.kind(); !ok {
+			return Profile{}, fmt.Errorf("profile %q: cycle step %d: unknown kind %q", name, i+1, st.Kind)
+		}
+		if st.Duration.Duration <= 0 {
+			return Profile{}, fmt.Errorf("profile %q: cycle step %d: duration must be positive", name, i+1)
+		}
+	}
+	return p, nil
+}
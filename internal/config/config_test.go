@@ -0,0 +1,329 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.toml")
+	if err := os.WriteFile(path, []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad_MissingFileGivesBuiltins(t *testing.T) {
+	f, err := Load(filepath.Join(t.TempDir(), "nope.toml"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := f.Resolve("")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if p.Work.Duration != 25*time.Minute || p.LongEvery != 4 {
+		t.Fatalf("unexpected default profile %+v", p)
+	}
+	if _, err := f.Resolve("deep-work"); err != nil {
+		t.Fatalf("deep-work should be built in: %v", err)
+	}
+}
+
+func TestResolve_ProfileInheritsDefaults(t *testing.T) {
+	path := writeConfig(t, `
+profile = "study"
+
+[profiles.study]
+work = "40m"
+long_every = 3
+notifications = false
+quitting_time = "18:30"
+cadence = "time"
+
+[profiles.late]
+quitting_time = "6pm"
+`)
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := f.Resolve("")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	cfg := p.Core()
+	if cfg.Work != 40*time.Minute || cfg.ShortBrk != 5*time.Minute || cfg.LongEvery != 3 {
+		t.Fatalf("unexpected config %+v", cfg)
+	}
+	if p.NotificationsEnabled() {
+		t.Fatal("notifications should be off for study")
+	}
+	if cfg.Cadence != core.CadenceTime || cfg.LongAfter != 2*time.Hour {
+		t.Fatalf("cadence %v after %v, want time after 2h", cfg.Cadence, cfg.LongAfter)
+	}
+	if cfg.QuittingTime != 18*time.Hour+30*time.Minute {
+		t.Fatalf("quitting time %v", cfg.QuittingTime)
+	}
+	if _, err := f.Resolve("late"); err == nil {
+		t.Fatal("expected error for quitting_time 6pm")
+	}
+}
+
+func TestResolve_Unknown(t *testing.T) {
+	f, _ := Load("")
+	if _, err := f.Resolve("nope"); err == nil {
+		t.Fatal("expected error for unknown profile")
+	}
+}
+
+func TestLoad_BadDuration(t *testing.T) {
+	path := writeConfig(t, "[profiles.x]\nwork = \"soon\"\n")
+	if _, err := Load(path); err == nil {
+		t.Fatal("expected parse error")
+	}
+}
+
+func TestResolve_Cycle(t *testing.T) {
+	path := writeConfig(t, `
+[profiles.ultradian]
+[[profiles.ultradian.cycle]]
+name = "work"
+duration = "50m"
+[[profiles.ultradian.cycle]]
+name = "stretch"
+duration = "2m"
+[[profiles.ultradian.cycle]]
+name = "long"
+kind = "long_break"
+duration = "20m"
+
+[profiles.broken]
+[[profiles.broken.cycle]]
+kind = "nap"
+duration = "5m"
+`)
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	p, err := f.Resolve("ultradian")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	cfg := p.Core()
+	if len(cfg.Cycle) != 3 {
+		t.Fatalf("expected 3 steps, got %d", len(cfg.Cycle))
+	}
+	if cfg.Cycle[0].Kind != core.PhaseWork {
+		t.Fatalf("step named work should be a work step, got %v", cfg.Cycle[0].Kind)
+	}
+	if st := cfg.Cycle[1]; st.Name != "stretch" || st.Kind != core.PhaseShortBreak || st.Duration != 2*time.Minute {
+		t.Fatalf("unexpected step %+v", st)
+	}
+	if cfg.Cycle[2].Kind != core.PhaseLongBreak {
+		t.Fatalf("expected long break kind, got %v", cfg.Cycle[2].Kind)
+	}
+	if _, err := f.Resolve("broken"); err == nil {
+		t.Fatal("expected error for unknown step kind")
+	}
+}
+
+func TestLoad_Keys(t *testing.T) {
+	path := writeConfig(t, `
+[keys]
+skip = "x"
+quit = ["q", "ctrl+q"]
+`)
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := f.Keys["skip"]; len(got) != 1 || got[0] != "x" {
+		t.Fatalf("skip: got %v", got)
+	}
+	if got := f.Keys["quit"]; len(got) != 2 || got[1] != "ctrl+q" {
+		t.Fatalf("quit: got %v", got)
+	}
+}
+
+func TestLoad_NotifyBackends(t *testing.T) {
+	path := writeConfig(t, `
+[notify.desktop]
+enabled = false
+
+[notify.sound]
+events = ["work_end"]
+command = ["paplay", "bell.oga"]
+
+[notify.webhook]
+urls = ["https://example.com"]
+events = ["long_break_start"]
+`)
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	n := f.Notify
+	if n.Desktop.On(true) {
+		t.Error("desktop should be disabled")
+	}
+	if !n.Sound.On(false) || len(n.Sound.Events) != 1 || n.Sound.Command[0] != "paplay" {
+		t.Errorf("sound: got %+v", n.Sound)
+	}
+	if n.Webhook.Events[0] != "long_break_start" || n.Webhook.URLs[0] != "https://example.com" {
+		t.Errorf("webhook: got %+v", n.Webhook)
+	}
+	if n.Bell != nil {
+		t.Error("bell is off without a section")
+	}
+}
+
+func TestLoad_QuietHours(t *testing.T) {
+	path := writeConfig(t, `
+[notify.quiet]
+from = "22:00"
+to = "08:00"
+mode = "silent"
+
+[notify.log]
+quiet = "off"
+`)
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	n := f.Notify
+	night := time.Date(2024, 4, 1, 23, 30, 0, 0, time.Local)
+	if !n.Quiet.Covers(night) || !n.Quiet.Covers(night.Add(8*time.Hour)) || n.Quiet.Covers(night.Add(12*time.Hour)) {
+		t.Error("quiet hours should run from 22:00 to 08:00")
+	}
+	if got := n.QuietMode(n.Desktop); got != "silent" {
+		t.Errorf("desktop: got %q, want the quiet hours' mode", got)
+	}
+	if got := n.QuietMode(&n.Log.NotifyBackend); got != "off" {
+		t.Errorf("log: got %q, want its own", got)
+	}
+	if (Notify{}).QuietMode(nil) != "mute" {
+		t.Error("quiet hours should mute by default")
+	}
+
+	for _, bad := range []string{
+		"[notify.quiet]\nfrom = \"22:00\"\nto = \"22:00\"\n",
+		"[notify.quiet]\nfrom = \"22:00\"\nmode = \"loud\"\n",
+		"[notify.bell]\nquiet = \"never\"\n",
+	} {
+		if _, err := Load(writeConfig(t, bad)); err == nil {
+			t.Errorf("accepted %q", bad)
+		}
+	}
+}
+
+func TestLoad_NotifyRoutes(t *testing.T) {
+	f, err := Load(writeConfig(t, `
+[notify.routes]
+work_end = ["desktop", "sound"]
+warning = []
+`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := f.Notify.Routes["work_end"]; !slices.Equal(got, []string{"desktop", "sound"}) {
+		t.Errorf("work_end routed to %v", got)
+	}
+	if got, ok := f.Notify.Routes["warning"]; !ok || len(got) != 0 {
+		t.Errorf("warning routed to %v, %v", got, ok)
+	}
+	if _, err := Load(writeConfig(t, "[notify.routes]\nwork_end = [\"pager\"]\n")); err == nil {
+		t.Error("route to an unknown backend accepted")
+	}
+}
+
+func TestLoad_Sync(t *testing.T) {
+	f, err := Load(writeConfig(t, `
+[sync]
+machine = "laptop"
+every = "5m"
+
+[sync.webdav]
+url = "https://cloud.example.com/dav/pomo/"
+`))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if s := f.Sync; s.Machine != "laptop" || s.Every.Duration != 5*time.Minute || s.WebDAV == nil || s.S3 != nil {
+		t.Errorf("sync %+v", s)
+	}
+	for _, bad := range []string{
+		"[sync]\nmachine = \"laptop\"\n",
+		"[sync.webdav]\nurl = \"https://x\"\n[sync.git]\nrepo = \"x\"\n",
+	} {
+		if _, err := Load(writeConfig(t, bad)); err == nil {
+			t.Errorf("accepted %q", bad)
+		}
+	}
+}
+
+func TestLoad_API(t *testing.T) {
+	f, err := Load(writeConfig(t, "[api]\norigins = [\"https://dash.example.com\", \"null\"]\n"))
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if got := f.API.Origins; len(got) != 2 || got[0] != "https://dash.example.com" {
+		t.Errorf("origins %v", got)
+	}
+	for _, bad := range []string{"dash.example.com", "https://dash.example.com/overlay"} {
+		if _, err := Load(writeConfig(t, "[api]\norigins = [\""+bad+"\"]\n")); err == nil {
+			t.Errorf("accepted origin %q", bad)
+		}
+	}
+}
+
+func TestFindProject_UseProject(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "webshop")
+	sub := filepath.Join(root, "cmd", "server")
+	if err := os.MkdirAll(sub, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if p, err := FindProject(sub); err != nil || p != nil {
+		t.Fatalf("no project file: got %+v, %v", p, err)
+	}
+	body := "profile = \"deep-work\"\nshort = \"7m\"\ntask = \"checkout flow\"\ntags = [\"client-a\", \"#coding\"]\n"
+	if err := os.WriteFile(filepath.Join(root, ProjectFile), []byte(body), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := FindProject(sub)
+	if err != nil || p == nil {
+		t.Fatalf("find: %+v, %v", p, err)
+	}
+	if p.Name != "webshop" || p.Task != "checkout flow" || len(p.Tags) != 2 {
+		t.Fatalf("unexpected project %+v", p)
+	}
+
+	f, _ := Load("")
+	f.Schedule = []Schedule{{Profile: "default"}}
+	if err := f.UseProject(p); err != nil {
+		t.Fatalf("use: %v", err)
+	}
+	if f.Profile != "webshop" || f.Schedule != nil || f.Project != p {
+		t.Fatalf("project not in force: %+v", f)
+	}
+	prof, err := f.Resolve("")
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if prof.Work.Duration != 50*time.Minute || prof.Short.Duration != 7*time.Minute {
+		t.Fatalf("unexpected project profile %+v", prof)
+	}
+
+	p.Profile = "nope"
+	if err := f.UseProject(p); err == nil {
+		t.Fatal("expected error for an unknown base profile")
+	}
+}
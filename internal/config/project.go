@@ -0,0 +1,102 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// ProjectFile is the name of a project config, looked for in the
+// working directory and its parents.
+const ProjectFile = ".gopomodoro.toml"
+
+// Project is a project config: timings, a task and tags for the work
+// done in its directory tree.
+type Project struct {
+	// Name labels the project's profile; it defaults to the name of the
+	// directory holding the file.
+	Name string `toml:"name"`
+	// Profile is the config file's profile to start from; the timings
+	// below override it where set.
+	Profile   string   `toml:"profile"`
+	Work      Duration `toml:"work"`
+	Short     Duration `toml:"short"`
+	Long      Duration `toml:"long"`
+	LongEvery int      `toml:"long_every"`
+	// LongAfter switches to the "time" cadence: a long break after this
+	// much work.
+	LongAfter Duration `toml:"long_after"`
+
+	// Task is the task attached at startup and Tags the tags, e.g.
+	// ["client-a", "coding"].
+	Task string   `toml:"task"`
+	Tags []string `toml:"tags"`
+
+	// Path is the file the project was read from.
+	Path string `toml:"-"`
+}
+
+// FindProject looks for ProjectFile in dir and then its parents, like
+// direnv. It returns nil without an error when there is none.
+func FindProject(dir string) (*Project, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+	for {
+		path := filepath.Join(dir, ProjectFile)
+		p := &Project{Path: path}
+		_, err := toml.DecodeFile(path, p)
+		switch {
+		case err == nil:
+			if p.Name == "" {
+				p.Name = filepath.Base(dir)
+			}
+			return p, nil
+		case !errors.Is(err, fs.ErrNotExist):
+			return nil, fmt.Errorf("project config %s: %w", path, err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
+// UseProject makes p's timings the file's default profile, named after
+// the project, and drops the schedule so they stay in force. p is kept
+// in f.Project for its task and tags.
+func (f *File) UseProject(p *Project) error {
+	base, err := f.Resolve(p.Profile)
+	if err != nil {
+		return fmt.Errorf("project config %s: %w", p.Path, err)
+	}
+	if p.Work.Duration > 0 {
+		base.Work = p.Work
+	}
+	if p.Short.Duration > 0 {
+		base.Short = p.Short
+	}
+	if p.Long.Duration > 0 {
+		base.Long = p.Long
+	}
+	if p.LongEvery != 0 {
+		base.LongEvery = p.LongEvery
+		base.Cadence = core.CadenceCount.String()
+	}
+	if p.LongAfter.Duration > 0 {
+		base.LongAfter = p.LongAfter
+		base.Cadence = core.CadenceTime.String()
+	}
+	f.Profiles[p.Name] = base
+	f.Profile = p.Name
+	f.Schedule = nil
+	f.Project = p
+	return nil
+}
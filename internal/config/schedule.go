@@ -0,0 +1,152 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Schedule picks Profile on Days (e.g. "mon-fri" or "sat,sun"; empty
+// for every day) from From to To, local times like "09:00". An empty
+// From is midnight and an empty To the end of the day; a To before From
+// runs past midnight.
+type Schedule struct {
+	Days    string `toml:"days"`
+	From    string `toml:"from"`
+	To      string `toml:"to"`
+	Profile string `toml:"profile"`
+}
+
+// rule is a parsed Schedule.
+type rule struct {
+	days     [7]bool // by time.Weekday
+	from, to time.Duration
+	profile  string
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+func (s Schedule) parse() (rule, error) {
+	if s.Profile == "" {
+		return rule{}, errors.New("no profile")
+	}
+	r, err := parseSpan(s.Days, s.From, s.To)
+	r.profile = s.Profile
+	return r, err
+}
+
+// parseSpan parses days and a from-to time span, as in Schedule, into a
+// rule without a profile.
+func parseSpan(days, from, to string) (rule, error) {
+	r := rule{to: 24 * time.Hour}
+	if err := parseDays(days, &r.days); err != nil {
+		return rule{}, err
+	}
+	var err error
+	if from != "" {
+		if r.from, err = parseClock(from); err != nil {
+			return rule{}, fmt.Errorf("from: %w", err)
+		}
+	}
+	if to != "" {
+		if r.to, err = parseClock(to); err != nil {
+			return rule{}, fmt.Errorf("to: %w", err)
+		}
+	}
+	if r.from == r.to {
+		return rule{}, errors.New("from and to are the same time")
+	}
+	return r, nil
+}
+
+// parseDays sets days from a list of days and ranges, e.g.
+// "mon-wed,fri"; a range may wrap around the week ("fri-mon").
+func parseDays(s string, days *[7]bool) error {
+	if strings.TrimSpace(s) == "" {
+		*days = [7]bool{true, true, true, true, true, true, true}
+		return nil
+	}
+	for _, part := range strings.Split(s, ",") {
+		// accept an en dash too, as in "Mon–Fri"
+		first, last, isRange := strings.Cut(strings.ReplaceAll(part, "–", "-"), "-")
+		a, err := parseWeekday(first)
+		if err != nil {
+			return err
+		}
+		b := a
+		if isRange {
+			if b, err = parseWeekday(last); err != nil {
+				return err
+			}
+		}
+		for d := a; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == b {
+				break
+			}
+		}
+	}
+	return nil
+}
+
+func parseWeekday(s string) (time.Weekday, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if len(s) >= 3 {
+		if d, ok := weekdays[s[:3]]; ok {
+			return d, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown day %q", s)
+}
+
+// parseClock parses "15:04" into the time since midnight; "24:00" is
+// the end of the day.
+func parseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time like 09:30", s)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// matches reports whether the rule covers t.
+func (r rule) matches(t time.Time) bool {
+	clock := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute
+	if r.from < r.to {
+		return r.days[t.Weekday()] && clock >= r.from && clock < r.to
+	}
+	// past midnight: the evening part is on a listed day, the morning
+	// part on the day after one
+	return r.days[t.Weekday()] && clock >= r.from || r.days[(t.Weekday()+6)%7] && clock < r.to
+}
+
+// validateSchedule checks every rule and that its profile exists.
+func (f *File) validateSchedule() error {
+	for i, s := range f.Schedule {
+		if _, err := s.parse(); err != nil {
+			return fmt.Errorf("schedule %d: %w", i+1, err)
+		}
+		if _, ok := f.Profiles[s.Profile]; !ok {
+			return fmt.Errorf("schedule %d: unknown profile %q", i+1, s.Profile)
+		}
+	}
+	return nil
+}
+
+// Scheduled returns the profile the schedule picks at t: that of the
+// first rule covering it, or the file's default profile.
+func (f *File) Scheduled(t time.Time) string {
+	for _, s := range f.Schedule {
+		if r, err := s.parse(); err == nil && r.matches(t) {
+			return r.profile
+		}
+	}
+	return f.Profile
+}
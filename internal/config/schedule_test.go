@@ -0,0 +1,72 @@
+package config
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScheduled_PicksByWeekdayAndTime(t *testing.T) {
+	path := writeConfig(t, `
+[[schedule]]
+days = "Mon–Fri"
+from = "09:00"
+to = "12:00"
+profile = "deep-work"
+
+[[schedule]]
+days = "fri-sat"
+from = "22:00"
+to = "02:00"
+profile = "night"
+
+[profiles.night]
+work = "15m"
+`)
+	f, err := Load(path)
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	at := func(day int, clock string) time.Time {
+		c, _ := time.Parse("15:04", clock)
+		// 2024-04-01 is a Monday
+		return time.Date(2024, 4, day, c.Hour(), c.Minute(), 0, 0, time.Local)
+	}
+	for _, tc := range []struct {
+		t    time.Time
+		want string
+	}{
+		{at(1, "09:00"), "deep-work"},
+		{at(5, "11:59"), "deep-work"},
+		{at(1, "12:00"), "default"},
+		{at(1, "08:59"), "default"},
+		{at(6, "10:00"), "default"}, // Saturday
+		{at(5, "23:00"), "night"},
+		{at(7, "01:30"), "night"}, // Sunday morning, after Saturday night
+		{at(7, "23:00"), "default"},
+		{at(1, "01:30"), "default"},
+	} {
+		if got := f.Scheduled(tc.t); got != tc.want {
+			t.Errorf("%s: got %s, want %s", tc.t.Format("Mon 15:04"), got, tc.want)
+		}
+	}
+}
+
+func TestLoad_BadSchedule(t *testing.T) {
+	for body, want := range map[string]string{
+		`[[schedule]]
+days = "mon-fri"
+profile = "nope"`: `unknown profile "nope"`,
+		`[[schedule]]
+days = "someday"
+profile = "default"`: `unknown day "someday"`,
+		`[[schedule]]
+from = "9am"
+profile = "default"`: "from:",
+	} {
+		_, err := Load(writeConfig(t, body))
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("%s: got %v, want an error with %q", body, err, want)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// settle is how long Watch waits for writes to stop before reloading,
+// so a save that takes several writes loads once.
+const settle = 100 * time.Millisecond
+
+// Watch calls onChange with the config at path, reloaded each time the
+// file is written, until ctx is done. It watches the directory, so
+// editors that save by replacing the file and a file created later are
+// seen too. A reload that fails goes to onErr, keeping the last good
+// config.
+func Watch(ctx context.Context, path string, onChange func(*File), onErr func(error)) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	path = filepath.Clean(path)
+	if err := w.Add(filepath.Dir(path)); err != nil {
+		w.Close()
+		return err
+	}
+	go func() {
+		defer w.Close()
+		var timer <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case ev := <-w.Events:
+				if filepath.Clean(ev.Name) == path && ev.Has(fsnotify.Write|fsnotify.Create) {
+					timer = time.After(settle)
+				}
+			case err := <-w.Errors:
+				onErr(err)
+			case <-timer:
+				timer = nil
+				f, err := Load(path)
+				if err != nil {
+					onErr(err)
+					continue
+				}
+				onChange(f)
+			}
+		}
+	}()
+	return nil
+}
@@ -0,0 +1,63 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWatch_ReloadsOnWrite(t *testing.T) {
+	path := writeConfig(t, `goal = 4`)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	files := make(chan *File, 4)
+	errs := make(chan error, 4)
+	if err := Watch(ctx, path, func(f *File) { files <- f }, func(err error) { errs <- err }); err != nil {
+		t.Fatal(err)
+	}
+	next := func() *File {
+		t.Helper()
+		select {
+		case f := <-files:
+			return f
+		case err := <-errs:
+			t.Fatalf("reload: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatal("no reload")
+		}
+		return nil
+	}
+
+	if err := os.WriteFile(path, []byte(`goal = 6`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if f := next(); f.Goal != 6 {
+		t.Fatalf("goal %d, want 6", f.Goal)
+	}
+
+	// editors that save by renaming a new file over the old one
+	tmp := filepath.Join(filepath.Dir(path), "config.toml~")
+	if err := os.WriteFile(tmp, []byte(`goal = 8`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatal(err)
+	}
+	if f := next(); f.Goal != 8 {
+		t.Fatalf("goal %d, want 8", f.Goal)
+	}
+
+	// a broken file is reported, not applied
+	if err := os.WriteFile(path, []byte(`goal = "x`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-errs:
+	case f := <-files:
+		t.Fatalf("applied a broken config: %+v", f)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no error for a broken config")
+	}
+}
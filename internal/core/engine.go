@@ -4,15 +4,25 @@ package core
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"sync"
 	"time"
+	"unicode"
 )
 
 // Phase defines the type of a Pomodoro phase.
 type Phase int
 
 const (
-	PhaseWork Phase = iota
+	// PhaseIdle is the engine before Start and after Stop: no phase
+	// runs, and StartedAt and EndsAt are zero. Start leaves it for work,
+	// or the first step of a custom cycle. It is the zero Phase, so a
+	// zero State is idle.
+	PhaseIdle Phase = iota
+	PhaseWork
 	PhaseShortBreak
 	PhaseLongBreak
 )
@@ -25,11 +35,92 @@ func (p Phase) String() string {
 		return "SHORT_BREAK"
 	case PhaseLongBreak:
 		return "LONG_BREAK"
+	case PhaseIdle:
+		return "IDLE"
 	default:
 		return "UNKNOWN"
 	}
 }
 
+// PauseReason categorizes why a phase was paused.
+type PauseReason int
+
+const (
+	ReasonNone PauseReason = iota
+	ReasonMeeting
+	ReasonBio
+	ReasonInterruption
+	ReasonOther
+	// ReasonIdle is set by automatic pauses when the user walks away;
+	// it is not offered for manual pauses.
+	ReasonIdle
+)
+
+// PauseReasons lists the selectable reasons in display order.
+var PauseReasons = []PauseReason{ReasonMeeting, ReasonBio, ReasonInterruption, ReasonOther}
+
+func (r PauseReason) String() string {
+	switch r {
+	case ReasonMeeting:
+		return "meeting"
+	case ReasonBio:
+		return "bio"
+	case ReasonInterruption:
+		return "interruption"
+	case ReasonOther:
+		return "other"
+	case ReasonIdle:
+		return "idle"
+	default:
+		return ""
+	}
+}
+
+// ParsePauseReason is the inverse of PauseReason.String. Unknown names
+// map to ReasonNone.
+func ParsePauseReason(s string) PauseReason {
+	for _, r := range PauseReasons {
+		if r.String() == s {
+			return r
+		}
+	}
+	return ReasonNone
+}
+
+// InterruptionKind tells whether an interruption came from the user
+// (a sudden urge to check mail) or from outside (a colleague, a call).
+type InterruptionKind int
+
+const (
+	InterruptInternal InterruptionKind = iota
+	InterruptExternal
+)
+
+func (k InterruptionKind) String() string {
+	if k == InterruptExternal {
+		return "external"
+	}
+	return "internal"
+}
+
+// ParseInterruptionKind is the inverse of InterruptionKind.String.
+func ParseInterruptionKind(s string) (InterruptionKind, bool) {
+	switch s {
+	case "internal":
+		return InterruptInternal, true
+	case "external":
+		return InterruptExternal, true
+	}
+	return InterruptInternal, false
+}
+
+// Interruption is one logged interruption of a work phase.
+type Interruption struct {
+	Kind InterruptionKind
+	Note string
+	At   time.Time
+}
+
 type Timer interface {
 	C() <-chan time.Time
 	Stop() bool
@@ -47,9 +138,23 @@ type Clock interface {
 	NewTimer(d time.Duration) Timer
 }
 
+// MonotonicClock is a Clock that also reads a monotonic clock, which
+// wall-clock changes don't move. Engines on one time phases by it and
+// reconcile wall-clock jumps by Config.ClockPolicy; with a plain Clock,
+// Now serves as both.
+type MonotonicClock interface {
+	Clock
+	// Monotonic is the time since an arbitrary fixed point.
+	Monotonic() time.Duration
+}
+
 type realClock struct{}
 
-func (realClock) Now() time.Time { return time.Now() }
+// processStart anchors the real clock's monotonic readings.
+var processStart = time.Now()
+
+func (realClock) Now() time.Time           { return time.Now() }
+func (realClock) Monotonic() time.Duration { return time.Since(processStart) }
 func (realClock) NewTimer(d time.Duration) Timer {
 	d = max(d, 0)
 	return &realTimer{t: time.NewTimer(d)}
@@ -60,7 +165,162 @@ type Config struct {
 	Work      time.Duration
 	ShortBrk  time.Duration
 	LongBrk   time.Duration
-	LongEvery int // long break after N work sessions
+	LongEvery int // long break after N work sessions; never if <= 0
+
+	// Cadence picks which breaks are long: every LongEvery-th, or the
+	// first after LongAfter of focus time since the last long one.
+	Cadence   Cadence
+	LongAfter time.Duration
+
+	// Overtime keeps a finished work phase counting up until
+	// Acknowledge is called, instead of starting the break right away.
+	Overtime bool
+
+	// Cycle, when non-empty, replaces the work/short/long rotation with
+	// an ordered list of named steps repeated forever. Work, ShortBrk,
+	// LongBrk and the cadence are then ignored.
+	Cycle []Step
+
+	// Warnings are offsets before the end of every phase at which
+	// EventWarning fires, e.g. 2*time.Minute for "2 minutes left".
+	Warnings []time.Duration
+
+	// Strict refuses Pause, Skip and Extend during work phases, as in
+	// the orthodox technique: an interrupted pomodoro is void, so the
+	// only way out is Stop, which abandons it.
+	Strict bool
+
+	// Flow makes work phases open-ended: they count up until
+	// Acknowledge ends them, and the break that follows lasts FlowRatio
+	// (default 1/5) of the time worked, at least a minute and at most
+	// FlowMaxBreak (default LongBrk). The cadence still decides which
+	// breaks are long. Ignored with a Cycle.
+	Flow         bool
+	FlowRatio    float64
+	FlowMaxBreak time.Duration
+
+	// ClockPolicy says what a running phase does when the wall clock
+	// jumps against the monotonic one, as with an NTP correction, a
+	// manual change or, where the monotonic clock stops, a suspend.
+	ClockPolicy ClockPolicy
+
+	// QuittingTime is the local time of day, as an offset from
+	// midnight, after which work phases no longer start: Start is
+	// refused and a break ending then stops the engine instead of
+	// starting work, until OverrideQuittingTime. Zero disables it.
+	QuittingTime time.Duration
+
+	// Cycles, when positive, ends a run after that many completed
+	// pomodoros: the last work phase stops the engine instead of
+	// starting a break.
+	Cycles int
+}
+
+// Cadence decides which breaks are long; see Config.Cadence.
+type Cadence int
+
+const (
+	// CadenceCount makes every Config.LongEvery-th break long.
+	CadenceCount Cadence = iota
+	// CadenceTime makes a break long once Config.LongAfter of work,
+	// overtime included, has built up since the last long break.
+	CadenceTime
+)
+
+func (c Cadence) String() string {
+	if c == CadenceTime {
+		return "time"
+	}
+	return "count"
+}
+
+// ParseCadence is the inverse of Cadence.String.
+func ParseCadence(s string) (Cadence, bool) {
+	for _, c := range []Cadence{CadenceCount, CadenceTime} {
+		if c.String() == s {
+			return c, true
+		}
+	}
+	return CadenceCount, false
+}
+
+// ClockPolicy reconciles wall-clock jumps; see Config.ClockPolicy.
+type ClockPolicy int
+
+const (
+	// ClockMonotonic keeps the time left: StartedAt and EndsAt move with
+	// the jump.
+	ClockMonotonic ClockPolicy = iota
+	// ClockWall keeps EndsAt: the time left grows or shrinks by the
+	// jump, and a deadline jumped past ends the phase.
+	ClockWall
+	// ClockPause pauses the phase, with the reason ReasonIdle, when the
+	// wall clock jumps ahead, as it does over a suspend; it keeps the
+	// time left for jumps back.
+	ClockPause
+)
+
+// clockJump is the smallest wall-clock change reconciled as a jump;
+// clockCheck is how often running phases look for one.
+const (
+	clockJump  = 2 * time.Second
+	clockCheck = 2 * time.Second
+)
+
+func (c ClockPolicy) String() string {
+	switch c {
+	case ClockWall:
+		return "wall"
+	case ClockPause:
+		return "pause"
+	default:
+		return "monotonic"
+	}
+}
+
+// ParseClockPolicy is the inverse of ClockPolicy.String.
+func ParseClockPolicy(s string) (ClockPolicy, bool) {
+	for _, c := range []ClockPolicy{ClockMonotonic, ClockWall, ClockPause} {
+		if c.String() == s {
+			return c, true
+		}
+	}
+	return ClockMonotonic, false
+}
+
+// ErrStrict wraps the refusals of Config.Strict.
+var ErrStrict = errors.New("strict mode")
+
+// Control calls return these, possibly wrapped, when the engine's state
+// doesn't allow them; nothing changes then and no event is published.
+var (
+	// ErrNotRunning is returned while idle.
+	ErrNotRunning = errors.New("no phase running")
+	// ErrAlreadyPaused is returned by Pause while paused.
+	ErrAlreadyPaused = errors.New("already paused")
+	// ErrInvalidTransition is returned for calls the current phase
+	// doesn't allow, such as Resume when not paused.
+	ErrInvalidTransition = errors.New("invalid transition")
+)
+
+// ErrQuittingTime wraps the refusals of Config.QuittingTime.
+var ErrQuittingTime = errors.New("quitting time")
+
+// Step is one entry of a custom cycle. Kind decides how the step is
+// treated: finishing a PhaseWork step counts a pomodoro, and overtime
+// only applies to work steps.
+type Step struct {
+	Name     string
+	Kind     Phase
+	Duration time.Duration
+}
+
+// label is the display name of a step.
+func (s Step) label() string {
+	if s.Name != "" {
+		return s.Name
+	}
+	return s.Kind.String()
 }
 
 // State represents the current snapshot of the engine.
@@ -70,6 +330,54 @@ type State struct {
 	EndsAt       time.Time
 	PomodoroDone int
 	Paused       bool
+	PauseReason  PauseReason // set while Paused, if the user gave one
+	// Interruptions logged in the current work phase
+	Interruptions int
+	// Overtime is set once a work phase passed its deadline with
+	// Config.Overtime on; EndsAt then marks where overtime began.
+	Overtime bool
+	// Open marks a Config.Flow work phase: it has no deadline, so
+	// EndsAt and Length are zero and Elapsed counts up.
+	Open bool
+
+	// Length is the planned duration of the current phase.
+	Length time.Duration
+	// Step is the index into Config.Cycle and Label its name; both are
+	// zero values without a custom cycle.
+	Step  int
+	Label string
+
+	// Task is what the user is working on, if they picked one. It stays
+	// attached across phases until changed.
+	Task Task
+	// Tags label the work, e.g. "client-a" and "coding"; like Task they
+	// stay attached until changed. The slice is never modified in place.
+	Tags []string
+}
+
+// Task is a to-do item from a task manager, attached with SetTask.
+type Task struct {
+	Source string // integration that supplied it, e.g. "taskwarrior"
+	ID     string // the source's identifier
+	Title  string
+	// Estimate is how many pomodoros the task should take; 0 if not
+	// estimated.
+	Estimate int
+}
+
+// IsZero reports whether no task is set.
+func (t Task) IsZero() bool { return t == Task{} }
+
+// Idle reports whether no phase runs, before Start or after Stop.
+func (s State) Idle() bool { return s.Phase == PhaseIdle }
+
+// Name is the display name of the current phase: the custom step name
+// when running a cycle, the phase kind otherwise.
+func (s State) Name() string {
+	if s.Label != "" {
+		return s.Label
+	}
+	return s.Phase.String()
 }
 
 // PomodoroEngine manages the lifecycle of Pomodoro phases.
@@ -81,18 +389,41 @@ type PomodoroEngine struct {
 	clock        Clock
 	cancel       context.CancelFunc
 	pausedRemain time.Duration
+	worked       time.Duration // an open phase's active time before StartedAt
+	focus        time.Duration // work since the last long break, for CadenceTime
+	anchor       anchor
+	crunch       time.Time // midnight of the day OverrideQuittingTime was called
+	undo         []undoable
 
 	// optional subscribers
 	// Invoked on every phase change
 	onAdvance func(State)
+	check     StartCheck
+	name      string        // set by Manager.Add; stamped on events
+	forward   func(Command) // set while following a leader
+
+	subMu sync.Mutex
+	subs  []*subscriber
+}
+
+// anchor times a running phase by the monotonic clock: left is the
+// time left at the reading mark, negative once in overtime. Wall-clock
+// changes move StartedAt and EndsAt but not the anchor.
+type anchor struct {
+	mark, left time.Duration
 }
 
 // New creates a PomodoroEngine with the given config.
 func New(cfg Config) *PomodoroEngine {
+	return NewWithClock(cfg, realClock{})
+}
+
+// NewWithClock creates a PomodoroEngine driven by a custom Clock.
+func NewWithClock(cfg Config, clock Clock) *PomodoroEngine {
 	return &PomodoroEngine{
 		cfg:   cfg,
-		clock: realClock{},
-		state: State{Phase: PhaseWork},
+		clock: clock,
+		state: State{Phase: PhaseIdle},
 	}
 }
 
@@ -102,6 +433,53 @@ func (p *PomodoroEngine) SetOnAdvance(fn func(State)) {
 	p.onAdvance = fn
 }
 
+// StartCheck is consulted whenever a work phase is about to begin, with
+// the state it would enter. It returns the length to run, which may be
+// shorter than planned, or an error to refuse the phase. Only Start can
+// be refused, which leaves the engine as it was and publishes
+// EventRefused; a phase entered automatically still starts, with the
+// length returned alongside the error. It runs with the engine locked,
+// so it must not call back into the engine.
+type StartCheck func(next State) (time.Duration, error)
+
+// SetStartCheck sets fn as the StartCheck; nil removes it.
+func (p *PomodoroEngine) SetStartCheck(fn StartCheck) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.check = fn
+}
+
+// Name is the engine's timer name in a Manager, or "" outside one.
+func (p *PomodoroEngine) Name() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.name
+}
+
+// Config returns the current timings.
+func (p *PomodoroEngine) Config() Config {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.cfg
+}
+
+// SetConfig replaces the timings. A running phase keeps its deadline;
+// the new values apply from the next phase.
+func (p *PomodoroEngine) SetConfig(cfg Config) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = cfg
+}
+
+// ReloadConfig is SetConfig for a config file that changed on disk; it
+// also publishes EventConfigReloaded, even if the timings are the same.
+func (p *PomodoroEngine) ReloadConfig(cfg Config) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cfg = cfg
+	p.publishLocked(EventConfigReloaded)
+}
+
 // Snapshot of current state (thread-safe)
 func (p *PomodoroEngine) State() State {
 	p.mu.RLock()
@@ -109,9 +487,11 @@ func (p *PomodoroEngine) State() State {
 	return p.state
 }
 
-// optional subscriber invoked on every phase change.
-// For idle state (StartedAt zero), it returns 0.
+// PhaseDuration is the configured length of ph; PhaseIdle has none, so
+// it returns 0.
 func (p *PomodoroEngine) PhaseDuration(ph Phase) time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
 	switch ph {
 	case PhaseWork:
 		return p.cfg.Work
@@ -124,58 +504,328 @@ func (p *PomodoroEngine) PhaseDuration(ph Phase) time.Duration {
 	}
 }
 
-func (p *PomodoroEngine) Start() {
+// Start begins the first phase, cutting short the one running, if
+// any. A refusal by quitting time or the StartCheck is published as
+// EventRefused and returned.
+func (p *PomodoroEngine) Start() error {
+	if p.forwarded(Command{Action: "start"}) {
+		return nil
+	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	now := p.clock.Now()
-	p.state.Phase = PhaseWork
-	p.state.StartedAt = now
-	p.state.EndsAt = now.Add(p.cfg.Work)
+	if p.afterHoursLocked() {
+		at := time.Time{}.Add(p.cfg.QuittingTime).Format("15:04")
+		err := fmt.Errorf("%w: it's past %s, override it to keep working", ErrQuittingTime, at)
+		p.publishEventLocked(Event{Kind: EventRefused, Refusal: err})
+		return err
+	}
+	prev, prevWorked, prevAnchor := p.state, p.worked, p.anchor
+	if len(p.cfg.Cycle) > 0 {
+		p.enterStepLocked(0)
+	} else {
+		p.enterWorkLocked()
+	}
+	err := p.checkLocked()
+	// the running phase is only cut short once the new one is allowed
+	next, nextAnchor := p.state, p.anchor
+	p.state, p.worked, p.anchor = prev, prevWorked, prevAnchor
+	if err != nil {
+		p.publishEventLocked(Event{Kind: EventRefused, Refusal: err})
+		return err
+	}
+	p.abandonLocked("")
+	p.state, p.worked, p.anchor = next, 0, nextAnchor
 	p.state.Paused = false
+	p.state.PauseReason = ReasonNone
+	p.state.Interruptions = 0
+	p.state.Overtime = false
 	p.pausedRemain = 0
 	p.spawnLocked()
+	p.publishLocked(EventStart)
+	return nil
+}
+
+// Pause freezes the current phase, recording remaining time. It
+// returns ErrNotRunning while idle, ErrAlreadyPaused while paused,
+// ErrInvalidTransition in overtime and ErrStrict when strict mode
+// refuses.
+func (p *PomodoroEngine) Pause() error {
+	return p.PauseWithReason(ReasonNone)
 }
 
-// Pause freezes the current phase, recording remaining time.
-func (p *PomodoroEngine) Pause() {
+// PauseWithReason is Pause with a reason category attached.
+func (p *PomodoroEngine) PauseWithReason(r PauseReason) error {
+	if p.forwarded(Command{Action: "pause", Reason: r}) {
+		return nil
+	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if p.state.Paused {
-		return
+	switch {
+	case p.state.Idle():
+		return ErrNotRunning
+	case p.state.Paused:
+		return ErrAlreadyPaused
+	case p.state.Overtime:
+		return fmt.Errorf("%w: overtime has no deadline to pause", ErrInvalidTransition)
+	}
+	if err := p.refuseStrictLocked("pause"); err != nil {
+		return err
+	}
+	p.pauseLocked(r)
+	return nil
+}
+
+// pauseLocked pauses a running phase for r.
+func (p *PomodoroEngine) pauseLocked(r PauseReason) {
+	if p.state.Open {
+		p.worked += max(p.sinceLocked(), 0)
+	} else {
+		// Freeze remain into pausedRemain
+		p.pausedRemain = p.remainingLocked()
 	}
-	// Freeze remain into pausedRemain
-	rem := max(time.Until(p.state.EndsAt), 0)
-	p.pausedRemain = rem
 	p.state.Paused = true
+	p.state.PauseReason = r
 	p.stopLocked()
+	p.publishLocked(EventPause)
+}
+
+// SetPauseReason attaches a reason to the current pause, e.g. when the
+// UI asks for it after pausing. It returns ErrNotRunning while idle and
+// ErrInvalidTransition when not paused.
+func (p *PomodoroEngine) SetPauseReason(r PauseReason) error {
+	if p.forwarded(Command{Action: "reason", Reason: r}) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.pausedLocked(); err != nil {
+		return err
+	}
+	p.state.PauseReason = r
+	p.publishLocked(EventUpdate)
+	return nil
+}
+
+// pausedLocked returns ErrNotRunning while idle and
+// ErrInvalidTransition unless paused.
+func (p *PomodoroEngine) pausedLocked() error {
+	switch {
+	case p.state.Idle():
+		return ErrNotRunning
+	case !p.state.Paused:
+		return fmt.Errorf("%w: not paused", ErrInvalidTransition)
+	}
+	return nil
 }
 
-func (p *PomodoroEngine) Resume() {
+// SetTask attaches t to the timer; a zero Task detaches the current one.
+func (p *PomodoroEngine) SetTask(t Task) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
-	if !p.state.Paused {
+	if p.state.Task == t {
 		return
 	}
+	p.state.Task = t
+	p.publishLocked(EventTask)
+}
+
+// SetTags replaces the tags of the work, normalized with ParseTags
+// rules: no leading '#', no blanks or duplicates.
+func (p *PomodoroEngine) SetTags(tags []string) {
+	tags = ParseTags(strings.Join(tags, " "))
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if slices.Equal(p.state.Tags, tags) {
+		return
+	}
+	p.state.Tags = tags
+	p.publishLocked(EventTask)
+}
+
+// ParseTags reads tags separated by spaces or commas, such as
+// "#client-a #coding", dropping the '#' and repeats. It returns nil for
+// no tags.
+func ParseTags(s string) []string {
+	var tags []string
+	for _, f := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || unicode.IsSpace(r) }) {
+		f = strings.TrimLeft(f, "#")
+		if f != "" && !slices.Contains(tags, f) {
+			tags = append(tags, f)
+		}
+	}
+	return tags
+}
+
+// FormatTags renders tags the way ParseTags reads them, e.g.
+// "#client-a #coding".
+func FormatTags(tags []string) string {
+	var b strings.Builder
+	for i, t := range tags {
+		if i > 0 {
+			b.WriteByte(' ')
+		}
+		b.WriteString("#" + t)
+	}
+	return b.String()
+}
+
+// Resume continues a paused phase. It returns ErrNotRunning while idle
+// and ErrInvalidTransition when not paused.
+func (p *PomodoroEngine) Resume() error {
+	if p.forwarded(Command{Action: "resume"}) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if err := p.pausedLocked(); err != nil {
+		return err
+	}
 	now := p.clock.Now()
 	p.state.StartedAt = now
 	p.pausedRemain = max(p.pausedRemain, 0)
 	p.state.EndsAt = now.Add(p.pausedRemain)
+	p.anchorLocked(p.pausedRemain)
+	if p.state.Open {
+		p.state.EndsAt = time.Time{}
+	}
 	p.state.Paused = false
+	p.state.PauseReason = ReasonNone
 	p.pausedRemain = 0
 	p.spawnLocked()
+	p.publishLocked(EventResume)
+	return nil
+}
+
+// Restore puts the engine into st, a phase an earlier process left
+// running or paused, e.g. as recovered from a journal: remaining is the
+// time it has left and elapsed the active time an open phase has run.
+// A running phase goes on from now and publishes EventResume; a paused
+// one stays paused and publishes EventUpdate.
+func (p *PomodoroEngine) Restore(st State, remaining, elapsed time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stopLocked()
+	p.state = st
+	p.state.Overtime = false
+	p.pausedRemain, p.worked, p.focus = 0, 0, 0
+	now := p.clock.Now()
+	switch {
+	case st.Open:
+		p.worked = elapsed
+		if !st.Paused {
+			p.state.StartedAt = now
+		}
+		p.anchorLocked(0)
+	case st.Paused:
+		p.pausedRemain = max(remaining, 0)
+	default:
+		p.state.StartedAt = now
+		p.state.EndsAt = now.Add(remaining)
+		p.anchorLocked(remaining)
+	}
+	if st.Paused {
+		p.publishLocked(EventUpdate)
+		return
+	}
+	p.spawnLocked()
+	p.publishLocked(EventResume)
 }
 
-// Stop cancels the current phase and resets to idle work state.
+// Interrupt logs an interruption against the current work phase
+// without stopping the timer, as in the original technique. It returns
+// ErrNotRunning while idle and ErrInvalidTransition during a break.
+func (p *PomodoroEngine) Interrupt(kind InterruptionKind, note string) error {
+	if p.forwarded(Command{Action: "interrupt", Kind: kind, Note: note}) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case p.state.Idle():
+		return ErrNotRunning
+	case p.state.Phase != PhaseWork:
+		return fmt.Errorf("%w: interruptions are logged against work", ErrInvalidTransition)
+	}
+	p.state.Interruptions++
+	it := Interruption{Kind: kind, Note: note, At: p.clock.Now()}
+	p.publishEventLocked(Event{Kind: EventInterrupt, Interruption: &it})
+	return nil
+}
+
+// Stop cancels the current phase and resets to PhaseIdle.
 // A snapshot notification is sent asynchronously if onAdvance is set.
 func (p *PomodoroEngine) Stop() {
+	p.StopWithReason("")
+}
+
+// StopWithReason is Stop with a note on why the phase was abandoned,
+// carried by its EventAbandon. Like a Skip, a stop in overtime counts
+// the pomodoro, so Undo can't take it back.
+func (p *PomodoroEngine) StopWithReason(reason string) {
+	if p.forwarded(Command{Action: "stop", Note: reason}) {
+		return
+	}
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	running := !p.state.Idle()
+	u := p.snapshotUndoLocked("stop")
 	p.stopLocked()
-	// reset to idle work phase
-	p.state = State{Phase: PhaseWork}
-	// if p.onAdvance != nil {
-	// 	go p.onAdvance(p.state)
-	// }
+	p.abandonLocked(reason)
+	p.resetLocked()
+	if running && !u.state.Overtime {
+		p.keepLocked(u)
+	}
+	p.publishLocked(EventStop)
+}
+
+// resetLocked makes the engine idle, keeping the task and tags.
+func (p *PomodoroEngine) resetLocked() {
+	p.state = State{Phase: PhaseIdle, Task: p.state.Task, Tags: p.state.Tags}
+	p.pausedRemain = 0
+	p.worked = 0
+	p.focus = 0
+}
+
+// OverrideQuittingTime lets work phases start past Config.QuittingTime
+// for the rest of the day.
+func (p *PomodoroEngine) OverrideQuittingTime() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.crunch = midnight(p.clock.Now())
+	p.publishLocked(EventUpdate)
+}
+
+// AfterHours reports whether Config.QuittingTime has passed today and
+// wasn't overridden, so work phases don't start.
+func (p *PomodoroEngine) AfterHours() bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.afterHoursLocked()
+}
+
+func (p *PomodoroEngine) afterHoursLocked() bool {
+	if p.cfg.QuittingTime <= 0 {
+		return false
+	}
+	now := p.clock.Now()
+	day := midnight(now)
+	return now.Sub(day) >= p.cfg.QuittingTime && !p.crunch.Equal(day)
+}
+
+// midnight is the start of t's day in its location.
+func midnight(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// abandonLocked publishes EventAbandon, with reason, if a phase is
+// being cut short before its deadline. Overtime has passed the
+// deadline, so it counts as complete.
+func (p *PomodoroEngine) abandonLocked(reason string) {
+	if p.state.Idle() || p.state.Overtime {
+		return
+	}
+	p.publishEventLocked(Event{Kind: EventAbandon, AbandonReason: reason})
 }
 
 // spawnLocked schedules a goroutine that waits until the current
@@ -184,10 +834,28 @@ func (p *PomodoroEngine) spawnLocked() {
 	if p.cancel != nil {
 		p.cancel()
 	}
+	if p.forward != nil || p.state.Open {
+		// the leader decides when phases end, or the user does
+		p.cancel = nil
+		return
+	}
 	ctx, cancel := context.WithCancel(context.Background())
 	p.cancel = cancel
 
-	d := max(time.Until(p.state.EndsAt), 0)
+	d := p.remainingLocked()
+	p.afterLocked(ctx, d, p.advance)
+	for _, w := range p.cfg.Warnings {
+		// warnings already passed (e.g. before a pause) don't fire again
+		if w > 0 && w < d {
+			p.afterLocked(ctx, d-w, func(ctx context.Context) { p.warn(ctx, w) })
+		}
+	}
+	p.watchClockLocked(ctx)
+}
+
+// afterLocked runs fn on its own goroutine once d has passed, unless ctx
+// is cancelled first.
+func (p *PomodoroEngine) afterLocked(ctx context.Context, d time.Duration, fn func(context.Context)) {
 	t := p.clock.NewTimer(d)
 
 	go func() {
@@ -204,13 +872,35 @@ func (p *PomodoroEngine) spawnLocked() {
 		// wait until deadline with monotonic time
 		select {
 		case <-t.C():
-			p.advance()
+			fn(ctx)
 		case <-ctx.Done():
 			return
 		}
 	}()
 }
 
+// warn publishes EventWarning for a phase with w left.
+func (p *PomodoroEngine) warn(ctx context.Context, w time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
+	p.publishEventLocked(Event{Kind: EventWarning, Warning: w})
+}
+
+// Reschedule cancels the deadline watcher and arms a new one for the
+// current phase. It is a no-op while idle or paused. Regular callers
+// never need it; it exists so fault injection can kill the scheduler.
+func (p *PomodoroEngine) Reschedule() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state.Idle() || p.state.Paused || p.state.Overtime {
+		return
+	}
+	p.spawnLocked()
+}
+
 // stopLocked cancels the current deadline goroutine if any.
 func (p *PomodoroEngine) stopLocked() {
 	if p.cancel != nil {
@@ -221,47 +911,455 @@ func (p *PomodoroEngine) stopLocked() {
 
 // advance transitions the engine to the next phase based on rules.
 // It spawns a new deadline watcher and notifies subscribers.
-func (p *PomodoroEngine) advance() {
+// A timer that fired while its runner was being cancelled is ignored,
+// so Stop/Pause always win against a concurrent deadline.
+func (p *PomodoroEngine) advance(ctx context.Context) {
 	p.mu.Lock()
 	defer p.mu.Unlock()
+	if ctx.Err() != nil {
+		return
+	}
 
-	switch p.state.Phase {
-	case PhaseWork:
-		p.state.PomodoroDone++
-		p.state.StartedAt = p.clock.Now()
-		if p.state.PomodoroDone%p.cfg.LongEvery == 0 {
-			p.state.Phase = PhaseLongBreak
-			p.state.EndsAt = p.state.StartedAt.Add(p.cfg.LongBrk)
-		} else {
-			p.state.Phase = PhaseShortBreak
-			p.state.EndsAt = p.state.StartedAt.Add(p.cfg.ShortBrk)
-		}
+	if p.cfg.Overtime && p.state.Phase == PhaseWork {
+		p.state.Overtime = true
+		p.stopLocked() // no deadline to wait for until Acknowledge
+		p.publishLocked(EventOvertime)
+		return
+	}
+	p.advanceLocked()
+}
+
+// Acknowledge ends overtime, or an open flow work phase, and starts
+// the break. It returns ErrNotRunning while idle and
+// ErrInvalidTransition for any other phase.
+func (p *PomodoroEngine) Acknowledge() error {
+	if p.forwarded(Command{Action: "acknowledge"}) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch {
+	case p.state.Idle():
+		return ErrNotRunning
+	case !p.state.Overtime && !p.state.Open:
+		return fmt.Errorf("%w: nothing to acknowledge before the deadline", ErrInvalidTransition)
+	}
+	p.advanceLocked()
+	return nil
+}
+
+// enterLocked begins a phase of the given kind and length now.
+func (p *PomodoroEngine) enterLocked(ph Phase, d time.Duration) {
+	p.state.Phase = ph
+	p.state.StartedAt = p.clock.Now()
+	p.state.EndsAt = p.state.StartedAt.Add(d)
+	p.state.Length = d
+	p.anchorLocked(d)
+	p.state.Step = 0
+	p.state.Label = ""
+	p.state.Open = false
+	p.worked = 0
+}
+
+// enterWorkLocked begins a work phase of the configured kind: fixed,
+// or open with Config.Flow.
+func (p *PomodoroEngine) enterWorkLocked() {
+	if !p.cfg.Flow {
+		p.enterLocked(PhaseWork, p.cfg.Work)
+		return
+	}
+	p.enterLocked(PhaseWork, 0)
+	p.state.EndsAt = time.Time{}
+	p.state.Open = true
+}
+
+// flowBreak is the break earned by worked in flow mode.
+func (p *PomodoroEngine) flowBreak(worked time.Duration) time.Duration {
+	ratio := p.cfg.FlowRatio
+	if ratio <= 0 {
+		ratio = 0.2
+	}
+	limit := p.cfg.FlowMaxBreak
+	if limit <= 0 {
+		limit = p.cfg.LongBrk
+	}
+	return max(min(time.Duration(float64(worked)*ratio), limit), time.Minute)
+}
+
+// checkLocked runs the StartCheck on a work phase just entered and
+// applies the length it returns.
+func (p *PomodoroEngine) checkLocked() error {
+	if p.check == nil || p.state.Phase != PhaseWork {
+		return nil
+	}
+	d, err := p.check(p.state)
+	if d > 0 && d < p.state.Length {
+		p.state.EndsAt = p.state.StartedAt.Add(d)
+		p.state.Length = d
+		p.anchor.left = d
+	}
+	return err
+}
+
+// enterStepLocked begins step i of the custom cycle.
+func (p *PomodoroEngine) enterStepLocked(i int) {
+	st := p.cfg.Cycle[i]
+	p.enterLocked(st.Kind, st.Duration)
+	p.state.Step = i
+	p.state.Label = st.label()
+}
+
+// Extend adds d, which may be negative, to the current phase. The time
+// left never drops below zero, so shortening past it ends the phase
+// right away. In overtime a positive d snoozes: the work phase runs
+// again for d. It returns ErrNotRunning while idle,
+// ErrInvalidTransition in an open flow phase or to shorten overtime,
+// and ErrStrict when strict mode refuses; a zero d changes nothing.
+func (p *PomodoroEngine) Extend(d time.Duration) error {
+	if p.forwarded(Command{Action: "extend", By: d}) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.extendLocked(d)
+}
+
+// SetRemaining reshapes the current phase to end d from now, extending
+// or shortening it as Extend does and publishing EventExtend with the
+// difference; d <= 0 ends it right away. In overtime it snoozes for d.
+// It returns the errors of Extend.
+func (p *PomodoroEngine) SetRemaining(d time.Duration) error {
+	if p.forwarded(Command{Action: "remaining", By: d}) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.extendLocked(max(d, 0) - p.remainingLocked())
+}
+
+// SetDeadline is SetRemaining for the phase to end at t, by the
+// engine's clock. A paused phase keeps the time until t to run once
+// resumed.
+func (p *PomodoroEngine) SetDeadline(t time.Time) error {
+	return p.SetRemaining(t.Sub(p.clock.Now()))
+}
+
+// extendLocked is Extend with the engine locked.
+func (p *PomodoroEngine) extendLocked(d time.Duration) error {
+	switch {
+	case p.state.Idle():
+		return ErrNotRunning
+	case p.state.Open:
+		return fmt.Errorf("%w: an open phase has no deadline", ErrInvalidTransition)
+	case p.state.Overtime && d < 0:
+		return fmt.Errorf("%w: overtime can't be shortened", ErrInvalidTransition)
+	case d == 0:
+		return nil
+	}
+	if err := p.refuseStrictLocked("extend"); err != nil {
+		return err
+	}
+	u := p.snapshotUndoLocked("extend")
+	var applied time.Duration
+	switch {
+	case p.state.Overtime:
+		now := p.clock.Now()
+		p.state.Overtime = false
+		p.state.Length += p.overtimeLocked() + d
+		p.state.EndsAt = now.Add(d)
+		p.anchorLocked(d)
+		applied = d
 		p.spawnLocked()
-	case PhaseShortBreak, PhaseLongBreak:
-		p.state.Phase = PhaseWork
-		p.state.StartedAt = p.clock.Now()
-		p.state.EndsAt = p.state.StartedAt.Add(p.cfg.Work)
+	case p.state.Paused:
+		rem := max(p.pausedRemain+d, 0)
+		applied = rem - p.pausedRemain
+		p.pausedRemain = rem
+		p.state.Length += applied
+	default:
+		left := p.remainingLocked()
+		applied = max(left+d, 0) - left
+		p.state.EndsAt = p.state.EndsAt.Add(applied)
+		p.state.Length += applied
+		p.anchor.left += applied
 		p.spawnLocked()
 	}
+	p.keepLocked(u)
+	p.publishEventLocked(Event{Kind: EventExtend, Extension: applied})
+	return nil
+}
+
+// Skip ends the current phase early and moves on to the next one. A
+// skipped work phase is not counted as a pomodoro. Skipping in overtime
+// is the same as Acknowledge, since the work was done. It returns
+// ErrNotRunning while idle and ErrStrict when strict mode refuses.
+func (p *PomodoroEngine) Skip() error {
+	if p.forwarded(Command{Action: "skip"}) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.state.Idle() {
+		return ErrNotRunning
+	}
+	if p.state.Overtime {
+		p.advanceLocked()
+		return nil
+	}
+	if err := p.refuseStrictLocked("skip"); err != nil {
+		return err
+	}
+	u := p.snapshotUndoLocked("skip")
+	p.stopLocked()
+	p.state.Paused = false
+	p.state.PauseReason = ReasonNone
+	p.pausedRemain = 0
+	p.nextLocked(false)
+	p.keepLocked(u)
+	p.publishLocked(EventSkip)
+	return nil
+}
+
+// Strict reports whether Config.Strict holds the current phase: a work
+// phase is running and not yet in overtime.
+func (p *PomodoroEngine) Strict() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.strictLocked()
+}
+
+func (p *PomodoroEngine) strictLocked() bool {
+	return p.cfg.Strict && p.state.Phase == PhaseWork && !p.state.Overtime
+}
+
+// refuseStrictLocked publishes EventRefused and returns the ErrStrict
+// refusal if strict mode forbids action now.
+func (p *PomodoroEngine) refuseStrictLocked(action string) error {
+	if !p.strictLocked() {
+		return nil
+	}
+	err := fmt.Errorf("%w: can't %s a pomodoro, stop to void it", ErrStrict, action)
+	p.publishEventLocked(Event{Kind: EventRefused, Refusal: err})
+	return err
+}
+
+// advanceLocked performs the phase transition. The caller holds p.mu.
+func (p *PomodoroEngine) advanceLocked() {
+	if p.nextKindLocked() == PhaseWork && p.afterHoursLocked() {
+		// the phase is over, and so is the day
+		p.stopLocked()
+		p.resetLocked()
+		p.publishEventLocked(Event{Kind: EventStop, QuittingTime: true})
+		return
+	}
+	if p.state.Phase == PhaseWork && p.cfg.Cycles > 0 && p.state.PomodoroDone+1 >= p.cfg.Cycles {
+		p.stopLocked()
+		p.resetLocked()
+		p.publishEventLocked(Event{Kind: EventStop, Finished: true})
+		return
+	}
+	p.nextLocked(true)
 
 	if p.onAdvance != nil {
 		// notify subscriber (system notification)
 		// execute outside the lock to prevent blocking
 		go p.onAdvance(p.state)
 	}
+	p.publishLocked(EventAdvance)
+}
+
+// nextLocked enters the phase after the current one and arms its
+// deadline. counted says whether a finished work phase adds a pomodoro.
+func (p *PomodoroEngine) nextLocked(counted bool) {
+	worked := p.elapsedLocked()
+	open := p.state.Open
+	p.state.Interruptions = 0
+	p.state.Overtime = false
+	p.state.Paused = false
+	p.state.PauseReason = ReasonNone
+	done := p.state.PomodoroDone
+	if p.state.Phase == PhaseWork {
+		// a skipped work phase still leads to the break it would have earned
+		done++
+		if counted {
+			p.state.PomodoroDone++
+		}
+	}
+	switch {
+	case len(p.cfg.Cycle) > 0:
+		p.enterStepLocked(p.nextStepLocked())
+	case p.state.Phase == PhaseWork:
+		long, short := p.cfg.LongBrk, p.cfg.ShortBrk
+		if open {
+			long = p.flowBreak(worked)
+			short = long
+		}
+		if p.longDueLocked(done, worked) {
+			p.focus = 0
+			p.enterLocked(PhaseLongBreak, long)
+		} else {
+			p.enterLocked(PhaseShortBreak, short)
+		}
+	default:
+		p.enterWorkLocked()
+	}
+	_ = p.checkLocked() // too late to refuse; the break is over
+	p.spawnLocked()
+}
+
+// longDueLocked reports whether the break after the work phase just
+// worked, the done-th, is long.
+func (p *PomodoroEngine) longDueLocked(done int, worked time.Duration) bool {
+	if p.cfg.Cadence == CadenceTime && p.cfg.LongAfter > 0 {
+		p.focus += worked
+		return p.focus >= p.cfg.LongAfter
+	}
+	return p.cfg.LongEvery > 0 && done%p.cfg.LongEvery == 0
+}
+
+// nextStepLocked is the custom cycle step after the current one.
+func (p *PomodoroEngine) nextStepLocked() int {
+	// the cycle may have been swapped for a shorter one meanwhile
+	if p.state.Label == "" || p.state.Step >= len(p.cfg.Cycle) {
+		return 0
+	}
+	return (p.state.Step + 1) % len(p.cfg.Cycle)
+}
+
+// nextKindLocked is the kind of phase nextLocked enters.
+func (p *PomodoroEngine) nextKindLocked() Phase {
+	switch {
+	case len(p.cfg.Cycle) > 0:
+		return p.cfg.Cycle[p.nextStepLocked()].Kind
+	case p.state.Phase == PhaseWork:
+		return PhaseShortBreak // or long; either is a break
+	}
+	return PhaseWork
 }
 
 // Helper: Remaining time (non-negative)
 func (p *PomodoroEngine) Remaining() time.Duration {
 	p.mu.RLock()
 	defer p.mu.RUnlock()
+	return p.remainingLocked()
+}
+
+// Overtime returns how long the engine has been in overtime, or 0.
+func (p *PomodoroEngine) Overtime() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.overtimeLocked()
+}
+
+func (p *PomodoroEngine) overtimeLocked() time.Duration {
+	if !p.state.Overtime {
+		return 0
+	}
+	return max(p.sinceLocked()-p.anchor.left, 0)
+}
+
+// Elapsed returns the active time spent in the current phase, overtime
+// included, or 0 while idle.
+func (p *PomodoroEngine) Elapsed() time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.elapsedLocked()
+}
+
+func (p *PomodoroEngine) elapsedLocked() time.Duration {
+	switch {
+	case p.state.Idle():
+		return 0
+	case p.state.Open:
+		if p.state.Paused {
+			return p.worked
+		}
+		return p.worked + max(p.sinceLocked(), 0)
+	case p.state.Overtime:
+		return p.state.Length + p.overtimeLocked()
+	}
+	return max(p.state.Length-p.remainingLocked(), 0)
+}
+
+func (p *PomodoroEngine) remainingLocked() time.Duration {
+	if p.state.Overtime || p.state.Open {
+		return 0
+	}
 	if p.state.Paused {
 		return max(p.pausedRemain, 0)
 	}
-	rem := time.Until(p.state.EndsAt)
-	return max(rem, 0)
+	return max(p.anchor.left-p.sinceLocked(), 0)
+}
+
+// monotonic reads the monotonic clock, or the wall clock when the
+// engine's clock has none.
+func (p *PomodoroEngine) monotonic() time.Duration {
+	if mc, ok := p.clock.(MonotonicClock); ok {
+		return mc.Monotonic()
+	}
+	return p.clock.Now().Sub(time.Unix(0, 0))
+}
+
+// anchorLocked times the running phase from now, with left to go.
+func (p *PomodoroEngine) anchorLocked(left time.Duration) {
+	p.anchor = anchor{mark: p.monotonic(), left: left}
+}
+
+// sinceLocked is the monotonic time since the anchor was set.
+func (p *PomodoroEngine) sinceLocked() time.Duration {
+	return p.monotonic() - p.anchor.mark
+}
+
+// watchClockLocked checks the wall clock against the monotonic one
+// every clockCheck while ctx lasts, reconciling jumps by the clock
+// policy. Clocks without a monotonic reading aren't watched.
+func (p *PomodoroEngine) watchClockLocked(ctx context.Context) {
+	mc, ok := p.clock.(MonotonicClock)
+	if !ok {
+		return
+	}
+	// Round(0) drops the monotonic reading, so Sub compares wall times
+	wall, mono := mc.Now().Round(0), mc.Monotonic()
+	var check func(context.Context)
+	check = func(ctx context.Context) {
+		p.mu.Lock()
+		defer p.mu.Unlock()
+		if ctx.Err() != nil {
+			return
+		}
+		nowWall, nowMono := mc.Now().Round(0), mc.Monotonic()
+		drift := nowWall.Sub(wall) - (nowMono - mono)
+		wall, mono = nowWall, nowMono
+		if drift >= clockJump || drift <= -clockJump {
+			p.reconcileLocked(drift)
+			if ctx.Err() != nil {
+				// reconciling re-armed or paused the phase
+				return
+			}
+		}
+		p.afterLocked(ctx, clockCheck, check)
+	}
+	p.afterLocked(ctx, clockCheck, check)
+}
+
+// reconcileLocked applies the clock policy to a running phase after
+// the wall clock jumped by drift against the monotonic clock.
+func (p *PomodoroEngine) reconcileLocked(drift time.Duration) {
+	switch {
+	case p.cfg.ClockPolicy == ClockWall:
+		// EndsAt stays put, so the time left takes the jump
+		p.anchor.left -= drift
+		p.spawnLocked()
+	case p.cfg.ClockPolicy == ClockPause && drift > 0 && !p.strictLocked():
+		p.pauseLocked(ReasonIdle)
+		return
+	default:
+		// the time left stays, so the phase's wall times move
+		p.state.StartedAt = p.state.StartedAt.Add(drift)
+		p.state.EndsAt = p.state.EndsAt.Add(drift)
+	}
+	p.publishLocked(EventUpdate)
 }
 
 // Compile-time interface assertions
-var _ Clock = (*realClock)(nil)
+var _ MonotonicClock = (*realClock)(nil)
 var _ Timer = (*realTimer)(nil)
@@ -1,6 +1,10 @@
 package core
 
 import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -9,6 +13,7 @@ import (
 /*********** fakes for deterministic testing ***********/
 
 type fakeTimer struct {
+	mu      sync.Mutex
 	ch      chan time.Time
 	stopped bool
 }
@@ -19,12 +24,16 @@ func newFakeTimer() *fakeTimer {
 
 func (ft *fakeTimer) C() <-chan time.Time { return ft.ch }
 func (ft *fakeTimer) Stop() bool {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
 	ft.stopped = true
 	return true
 }
 
 // fire pushes a single event if not stopped.
 func (ft *fakeTimer) fire(now time.Time) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
 	if !ft.stopped {
 		select {
 		case ft.ch <- now:
@@ -67,6 +76,93 @@ func (f *fakeClock) fireLast() {
 	}
 }
 
+// jumpClock keeps a wall clock that can jump apart from its monotonic
+// one; timers fire as run moves the monotonic clock past them.
+type jumpClock struct {
+	mu     sync.Mutex
+	wall   time.Time
+	mono   time.Duration
+	timers []jumpTimer
+}
+
+type jumpTimer struct {
+	*fakeTimer
+	due time.Duration
+}
+
+func (c *jumpClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.wall
+}
+
+func (c *jumpClock) Monotonic() time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.mono
+}
+
+func (c *jumpClock) NewTimer(d time.Duration) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ft := newFakeTimer()
+	if d <= 0 {
+		ft.fire(c.wall)
+		return ft
+	}
+	c.timers = append(c.timers, jumpTimer{ft, c.mono + d})
+	return ft
+}
+
+// run lets d pass on both clocks.
+func (c *jumpClock) run(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wall = c.wall.Add(d)
+	c.mono += d
+	pending := c.timers[:0]
+	for _, t := range c.timers {
+		if t.due <= c.mono {
+			t.fire(c.wall)
+		} else {
+			pending = append(pending, t)
+		}
+	}
+	c.timers = pending
+}
+
+// jump moves the wall clock alone.
+func (c *jumpClock) jump(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.wall = c.wall.Add(d)
+}
+
+// waitArmed waits until n timers are pending.
+func (c *jumpClock) waitArmed(t *testing.T, n int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		c.mu.Lock()
+		armed := 0
+		for _, t := range c.timers {
+			t.mu.Lock()
+			if !t.stopped {
+				armed++
+			}
+			t.mu.Unlock()
+		}
+		c.mu.Unlock()
+		if armed == n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d timers armed, want %d", armed, n)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
 /*********** tests ***********/
 
 func newTestEngine(cfg Config) (*PomodoroEngine, *fakeClock) {
@@ -96,6 +192,7 @@ func TestPhaseString(t *testing.T) {
 		phase Phase
 		want  string
 	}{
+		{name: "idle", phase: PhaseIdle, want: "IDLE"},
 		{name: "work", phase: PhaseWork, want: "WORK"},
 		{name: "short break", phase: PhaseShortBreak, want: "SHORT_BREAK"},
 		{name: "long break", phase: PhaseLongBreak, want: "LONG_BREAK"},
@@ -142,6 +239,21 @@ func TestPhaseDuration(t *testing.T) {
 	}
 }
 
+func TestIdle_BeforeStartAndAfterStop(t *testing.T) {
+	eng := New(Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	if st := eng.State(); !st.Idle() || st.Name() != "IDLE" || eng.PhaseDuration(PhaseIdle) != 0 {
+		t.Fatalf("new engine: %+v, want idle", st)
+	}
+	eng.Start()
+	if st := eng.State(); st.Idle() || st.Phase != PhaseWork {
+		t.Fatalf("after start: %+v, want work", st)
+	}
+	eng.Stop()
+	if st := eng.State(); !st.Idle() || !st.StartedAt.IsZero() {
+		t.Fatalf("after stop: %+v, want idle", st)
+	}
+}
+
 func TestStart_AdvanceToShortBreak(t *testing.T) {
 	cfg := Config{
 		Work:      1 * time.Second,
@@ -170,6 +282,30 @@ func TestStart_AdvanceToShortBreak(t *testing.T) {
 	}
 }
 
+func TestCadenceTime_LongBreakAfterFocusTime(t *testing.T) {
+	cfg := Config{
+		Work:      50 * time.Minute,
+		ShortBrk:  time.Second,
+		LongBrk:   time.Second,
+		LongEvery: 2, // ignored with the time cadence
+		Cadence:   CadenceTime,
+		LongAfter: 2 * time.Hour,
+	}
+	eng, fc := newTestEngine(cfg)
+	ch := waitAdvance(t, eng.SetOnAdvance)
+	eng.Start()
+
+	// 50m, 100m: short; 150m: long; then counting starts over
+	for i, want := range []Phase{PhaseShortBreak, PhaseShortBreak, PhaseLongBreak, PhaseShortBreak} {
+		fc.fireLast()
+		if st := <-ch; st.Phase != want {
+			t.Fatalf("break %d: got %v, want %v", i+1, st.Phase, want)
+		}
+		fc.fireLast()
+		<-ch
+	}
+}
+
 func TestPauseResume_FreezesRemaining(t *testing.T) {
 	cfg := Config{
 		Work:      10 * time.Second,
@@ -255,3 +391,1113 @@ func TestLongEvery_TriggersLongBreak(t *testing.T) {
 		t.Fatalf("expected PomodoroDone=2, got %d", st.PomodoroDone)
 	}
 }
+
+func TestLongEvery_ZeroNeverLong(t *testing.T) {
+	eng, fc := newTestEngine(Config{Work: time.Second, ShortBrk: time.Second, LongBrk: time.Second})
+	ch := waitAdvance(t, eng.SetOnAdvance)
+
+	eng.Start()
+	for i := 0; i < 8; i++ {
+		fc.fireLast()
+		if st := <-ch; st.Phase == PhaseLongBreak {
+			t.Fatalf("long break after %d pomodoros with LongEvery=0", st.PomodoroDone)
+		}
+	}
+}
+
+func TestSubscribe_ReceivesEventsInOrder(t *testing.T) {
+	cfg := Config{
+		Work:      1 * time.Second,
+		ShortBrk:  1 * time.Second,
+		LongBrk:   1 * time.Second,
+		LongEvery: 4,
+	}
+	eng, fc := newTestEngine(cfg)
+
+	ch := make(chan Event, 8)
+	cancel := eng.Subscribe(func(ev Event) { ch <- ev })
+	defer cancel()
+
+	eng.Start()
+	eng.Pause()
+	eng.Resume()
+	fc.fireLast()
+
+	want := []EventKind{EventStart, EventPause, EventResume, EventAdvance}
+	for i, k := range want {
+		select {
+		case ev := <-ch:
+			if ev.Kind != k {
+				t.Fatalf("event %d: want %v, got %v", i, k, ev.Kind)
+			}
+		case <-time.After(200 * time.Millisecond):
+			t.Fatalf("timeout waiting for event %d (%v)", i, k)
+		}
+	}
+
+	eng.Stop()
+	select {
+	case ev := <-ch:
+		if ev.Kind != EventAbandon || ev.State.Phase != PhaseShortBreak {
+			t.Fatalf("want abandon of the break, got %v %+v", ev.Kind, ev.State)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("timeout waiting for abandon event")
+	}
+	select {
+	case ev := <-ch:
+		if ev.Kind != EventStop || !ev.State.StartedAt.IsZero() {
+			t.Fatalf("want idle stop event, got %v %+v", ev.Kind, ev.State)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("timeout waiting for stop event")
+	}
+}
+
+func TestControl_InvalidTransitions(t *testing.T) {
+	eng := New(Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	events := make(chan Event, 8)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+
+	for name, err := range map[string]error{
+		"pause":       eng.Pause(),
+		"resume":      eng.Resume(),
+		"skip":        eng.Skip(),
+		"acknowledge": eng.Acknowledge(),
+		"reason":      eng.SetPauseReason(ReasonMeeting),
+	} {
+		if !errors.Is(err, ErrNotRunning) {
+			t.Errorf("%s while idle: %v, want ErrNotRunning", name, err)
+		}
+	}
+	if st := eng.State(); st.Paused || !st.StartedAt.IsZero() {
+		t.Fatalf("refused calls changed the idle state to %+v", st)
+	}
+
+	if err := eng.Start(); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	if err := eng.Resume(); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("resume while running: %v, want ErrInvalidTransition", err)
+	}
+	if err := eng.Acknowledge(); !errors.Is(err, ErrInvalidTransition) {
+		t.Errorf("acknowledge before the deadline: %v, want ErrInvalidTransition", err)
+	}
+	if err := eng.Pause(); err != nil {
+		t.Fatalf("pause: %v", err)
+	}
+	if err := eng.Pause(); !errors.Is(err, ErrAlreadyPaused) {
+		t.Errorf("pause while paused: %v, want ErrAlreadyPaused", err)
+	}
+
+	// only the start and the pause were published
+	for _, want := range []EventKind{EventStart, EventPause} {
+		select {
+		case ev := <-events:
+			if ev.Kind != want {
+				t.Fatalf("event %v, want %v", ev.Kind, want)
+			}
+		case <-time.After(200 * time.Millisecond):
+			t.Fatalf("timeout waiting for %v", want)
+		}
+	}
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event %v", ev.Kind)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInterrupt_OnlyDuringWork(t *testing.T) {
+	cfg := Config{
+		Work:      1 * time.Second,
+		ShortBrk:  1 * time.Second,
+		LongBrk:   1 * time.Second,
+		LongEvery: 4,
+	}
+	eng, fc := newTestEngine(cfg)
+
+	if err := eng.Interrupt(InterruptInternal, ""); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("interrupt while idle: %v, want ErrNotRunning", err)
+	}
+
+	ch := make(chan Event, 8)
+	defer eng.Subscribe(func(ev Event) {
+		if ev.Kind == EventInterrupt {
+			ch <- ev
+		}
+	})()
+	adv := waitAdvance(t, eng.SetOnAdvance)
+
+	eng.Start()
+	if eng.Interrupt(InterruptExternal, "phone") != nil {
+		t.Fatal("interrupt during work should be recorded")
+	}
+	if eng.State().Interruptions != 1 {
+		t.Fatalf("expected 1 interruption, got %d", eng.State().Interruptions)
+	}
+	select {
+	case ev := <-ch:
+		if ev.Interruption == nil || ev.Interruption.Kind != InterruptExternal || ev.Interruption.Note != "phone" {
+			t.Fatalf("unexpected interruption %+v", ev.Interruption)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("timeout waiting for interrupt event")
+	}
+
+	fc.fireLast()
+	if st := <-adv; st.Interruptions != 0 {
+		t.Fatalf("break should start with no interruptions, got %d", st.Interruptions)
+	}
+	if err := eng.Interrupt(InterruptInternal, ""); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("interrupt during a break: %v, want ErrInvalidTransition", err)
+	}
+}
+
+func TestOvertime_WaitsForAcknowledge(t *testing.T) {
+	cfg := Config{
+		Work:      1 * time.Second,
+		ShortBrk:  1 * time.Second,
+		LongBrk:   1 * time.Second,
+		LongEvery: 4,
+		Overtime:  true,
+	}
+	eng, fc := newTestEngine(cfg)
+
+	overtime := make(chan State, 1)
+	defer eng.Subscribe(func(ev Event) {
+		if ev.Kind == EventOvertime {
+			overtime <- ev.State
+		}
+	})()
+	adv := waitAdvance(t, eng.SetOnAdvance)
+
+	eng.Start()
+	fc.fireLast()
+
+	select {
+	case st := <-overtime:
+		if st.Phase != PhaseWork || !st.Overtime || st.PomodoroDone != 0 {
+			t.Fatalf("unexpected overtime state %+v", st)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("timeout waiting for overtime")
+	}
+	if eng.Remaining() != 0 {
+		t.Fatalf("remaining in overtime: %v", eng.Remaining())
+	}
+
+	// pausing in overtime is a no-op
+	eng.Pause()
+	if eng.State().Paused {
+		t.Fatal("pause should be ignored in overtime")
+	}
+
+	eng.Acknowledge()
+	select {
+	case st := <-adv:
+		if st.Phase != PhaseShortBreak || st.Overtime || st.PomodoroDone != 1 {
+			t.Fatalf("unexpected state after acknowledge %+v", st)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("timeout waiting for advance after acknowledge")
+	}
+}
+
+func TestCycle_FollowsCustomSteps(t *testing.T) {
+	cfg := Config{
+		Cycle: []Step{
+			{Name: "focus", Kind: PhaseWork, Duration: 50 * time.Second},
+			{Name: "stretch", Kind: PhaseShortBreak, Duration: 2 * time.Second},
+			{Kind: PhaseWork, Duration: 50 * time.Second},
+			{Name: "long", Kind: PhaseLongBreak, Duration: 20 * time.Second},
+		},
+	}
+	eng, fc := newTestEngine(cfg)
+	ch := waitAdvance(t, eng.SetOnAdvance)
+
+	eng.Start()
+	if st := eng.State(); st.Name() != "focus" || st.Length != 50*time.Second {
+		t.Fatalf("unexpected first step %+v", st)
+	}
+
+	want := []struct {
+		name string
+		done int
+	}{
+		{"stretch", 1},
+		{"WORK", 1},
+		{"long", 2},
+		{"focus", 2}, // wraps around
+	}
+	for i, w := range want {
+		fc.fireLast()
+		select {
+		case st := <-ch:
+			if st.Name() != w.name || st.PomodoroDone != w.done || st.Step != (i+1)%len(cfg.Cycle) {
+				t.Fatalf("step %d: want %s/%d, got %s/%d (step %d)", i, w.name, w.done, st.Name(), st.PomodoroDone, st.Step)
+			}
+		case <-time.After(200 * time.Millisecond):
+			t.Fatalf("timeout waiting for step %d", i)
+		}
+	}
+}
+
+func TestSkip_DoesNotCountPomodoro(t *testing.T) {
+	cfg := Config{
+		Work:      10 * time.Second,
+		ShortBrk:  5 * time.Second,
+		LongBrk:   15 * time.Second,
+		LongEvery: 1,
+	}
+	eng, _ := newTestEngine(cfg)
+
+	eng.Skip() // idle: no-op
+	if !eng.State().StartedAt.IsZero() {
+		t.Fatal("skip while idle should do nothing")
+	}
+
+	eng.Start()
+	eng.Pause()
+	eng.Skip()
+	st := eng.State()
+	if st.Phase != PhaseLongBreak || st.PomodoroDone != 0 || st.Paused {
+		t.Fatalf("unexpected state after skipping work %+v", st)
+	}
+
+	eng.Skip()
+	if st := eng.State(); st.Phase != PhaseWork || st.PomodoroDone != 0 {
+		t.Fatalf("unexpected state after skipping break %+v", st)
+	}
+}
+
+func TestWarnings_FireBeforeDeadline(t *testing.T) {
+	cfg := Config{
+		Work:      10 * time.Minute,
+		ShortBrk:  time.Minute,
+		LongBrk:   time.Minute,
+		LongEvery: 4,
+		// the second one is longer than a break and never fires there
+		Warnings: []time.Duration{2 * time.Minute, 5 * time.Minute},
+	}
+	eng, fc := newTestEngine(cfg)
+	// the scheduler measures the time left against the wall clock
+	fc.now = time.Now()
+	warnings := make(chan Event, 4)
+	defer eng.Subscribe(func(ev Event) {
+		if ev.Kind == EventWarning {
+			warnings <- ev
+		}
+	})()
+
+	eng.Start()
+	fc.mu.Lock()
+	timers := append([]*fakeTimer(nil), fc.dlist...)
+	fc.mu.Unlock()
+	if len(timers) != 3 {
+		t.Fatalf("want deadline + 2 warning timers, got %d", len(timers))
+	}
+	timers[1].fire(fc.Now())
+	select {
+	case ev := <-warnings:
+		if ev.Warning != 2*time.Minute || ev.State.Phase != PhaseWork {
+			t.Fatalf("unexpected warning %+v", ev)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("timeout waiting for warning")
+	}
+
+	// stopped phases don't warn
+	eng.Stop()
+	timers[2].fire(fc.Now())
+	select {
+	case ev := <-warnings:
+		t.Fatalf("warning after stop: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestExtend(t *testing.T) {
+	cfg := Config{
+		Work:      10 * time.Minute,
+		ShortBrk:  time.Minute,
+		LongBrk:   time.Minute,
+		LongEvery: 4,
+		Overtime:  true,
+	}
+	eng := New(cfg)
+	defer eng.Stop()
+
+	if err := eng.Extend(time.Minute); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("extend while idle: %v, want ErrNotRunning", err)
+	}
+	eng.Start()
+	eng.Extend(5 * time.Minute)
+	if st := eng.State(); st.Length != 15*time.Minute || eng.Remaining() <= 14*time.Minute {
+		t.Fatalf("unexpected state after extend: %+v, %v left", st, eng.Remaining())
+	}
+
+	// shortening stops at zero time left
+	eng.Pause()
+	eng.Extend(-20 * time.Minute)
+	if eng.Remaining() != 0 {
+		t.Fatalf("remaining should clamp to zero, got %v", eng.Remaining())
+	}
+	if st := eng.State(); st.Length > time.Second {
+		t.Fatalf("length should shrink with the time left, got %v", st.Length)
+	}
+}
+
+func TestSetRemainingAndDeadline(t *testing.T) {
+	eng := New(Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	if err := eng.SetRemaining(time.Minute); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("SetRemaining while idle: %v, want ErrNotRunning", err)
+	}
+	eng.Start()
+	events := make(chan Event, 4)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+
+	if err := eng.SetRemaining(10 * time.Minute); err != nil {
+		t.Fatalf("SetRemaining: %v", err)
+	}
+	select {
+	case ev := <-events:
+		if ev.Kind != EventExtend || ev.Extension > -15*time.Minute+time.Second || ev.Extension < -15*time.Minute {
+			t.Fatalf("event %v by %v, want an extend by -15m", ev.Kind, ev.Extension)
+		}
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("no extend event")
+	}
+	if rem := eng.Remaining(); rem > 10*time.Minute || rem < 10*time.Minute-time.Second {
+		t.Fatalf("remaining %v, want 10m", rem)
+	}
+
+	eng.Pause()
+	if err := eng.SetDeadline(time.Now().Add(3 * time.Minute)); err != nil {
+		t.Fatalf("SetDeadline while paused: %v", err)
+	}
+	if rem := eng.Remaining(); rem > 3*time.Minute || rem < 3*time.Minute-time.Second {
+		t.Fatalf("remaining %v while paused, want 3m", rem)
+	}
+}
+
+func TestExtend_SnoozesOvertime(t *testing.T) {
+	cfg := Config{Work: time.Second, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4, Overtime: true}
+	eng, fc := newTestEngine(cfg)
+	overtime := make(chan struct{}, 1)
+	defer eng.Subscribe(func(ev Event) {
+		if ev.Kind == EventOvertime {
+			overtime <- struct{}{}
+		}
+	})()
+
+	eng.Start()
+	fc.fireLast()
+	select {
+	case <-overtime:
+	case <-time.After(200 * time.Millisecond):
+		t.Fatal("timeout waiting for overtime")
+	}
+	if err := eng.Extend(5 * time.Minute); err != nil {
+		t.Fatalf("snooze from overtime: %v", err)
+	}
+	if st := eng.State(); st.Overtime || st.Phase != PhaseWork || st.PomodoroDone != 0 {
+		t.Fatalf("unexpected state after snooze %+v", st)
+	}
+}
+
+func TestSetTask_SurvivesStop(t *testing.T) {
+	eng := New(Config{Work: time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4})
+	events := make(chan EventKind, 8)
+	defer eng.Subscribe(func(ev Event) { events <- ev.Kind })()
+
+	task := Task{Source: "test", ID: "1", Title: "write docs"}
+	eng.SetTask(task)
+	eng.SetTask(task) // unchanged, no event
+	eng.Start()
+	eng.Stop()
+	if got := eng.State().Task; got != task {
+		t.Fatalf("task after stop = %+v, want %+v", got, task)
+	}
+	if k := <-events; k != EventTask {
+		t.Fatalf("first event = %v, want task", k)
+	}
+	if k := <-events; k != EventStart {
+		t.Fatalf("second event = %v, want start", k)
+	}
+}
+
+func TestParseTags(t *testing.T) {
+	got := ParseTags(" #client-a #coding,review  coding ## ")
+	want := []string{"client-a", "coding", "review"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("ParseTags = %q, want %q", got, want)
+	}
+	if s := FormatTags(got); s != "#client-a #coding #review" {
+		t.Fatalf("FormatTags = %q", s)
+	}
+	if got := ParseTags(""); got != nil {
+		t.Fatalf("ParseTags(\"\") = %q, want nil", got)
+	}
+}
+
+func TestSetTags(t *testing.T) {
+	eng := New(Config{Work: time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4})
+	events := make(chan Event, 8)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+
+	eng.SetTags([]string{"#coding", "client-a"})
+	eng.SetTags([]string{"coding", "#client-a"}) // unchanged, no event
+	eng.SetTags(nil)
+	for _, want := range [][]string{{"coding", "client-a"}, nil} {
+		ev := <-events
+		if ev.Kind != EventTask || !slices.Equal(ev.State.Tags, want) {
+			t.Fatalf("event %v with tags %q, want task with %q", ev.Kind, ev.State.Tags, want)
+		}
+	}
+}
+
+func TestStartCheck_ShortensAndRefuses(t *testing.T) {
+	eng := New(Config{Work: 25 * time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4})
+	events := make(chan Event, 8)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+
+	refuse := errors.New("meeting at 10:00")
+	var err error
+	eng.SetStartCheck(func(next State) (time.Duration, error) {
+		if next.Phase != PhaseWork {
+			t.Errorf("checked a %v phase", next.Phase)
+		}
+		return 10 * time.Minute, err
+	})
+	eng.Start()
+	if st := eng.State(); st.Length != 10*time.Minute || st.EndsAt.Sub(st.StartedAt) != 10*time.Minute {
+		t.Fatalf("shortened state %+v", st)
+	}
+	if ev := <-events; ev.Kind != EventStart {
+		t.Fatalf("event %v, want start", ev.Kind)
+	}
+
+	running := eng.State()
+	err = refuse
+	eng.Start()
+	if st := eng.State(); !reflect.DeepEqual(st, running) {
+		t.Fatalf("refused start changed state to %+v", st)
+	}
+	if ev := <-events; ev.Kind != EventRefused || ev.Refusal != refuse {
+		t.Fatalf("event %v (%v), want refused", ev.Kind, ev.Refusal)
+	}
+
+	// automatic entries can't be refused, only shortened
+	eng.Skip()
+	<-events
+	eng.Skip()
+	if ev := <-events; ev.Kind != EventSkip || ev.State.Phase != PhaseWork || ev.State.Length != 10*time.Minute {
+		t.Fatalf("event %v %+v, want a shortened work phase", ev.Kind, ev.State)
+	}
+}
+
+func TestQuittingTime_StopsAfterBreakAndRefusesStart(t *testing.T) {
+	eng, fc := newTestEngine(Config{Work: 5 * time.Minute, ShortBrk: 10 * time.Minute, LongBrk: time.Hour, LongEvery: 4,
+		QuittingTime: 18 * time.Hour})
+	fc.now = time.Date(2025, 5, 1, 17, 50, 0, 0, time.Local)
+	events := make(chan Event, 8)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+	next := func() Event {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("timeout waiting for an event")
+			return Event{}
+		}
+	}
+
+	eng.Start()
+	next()
+	fc.fireLast() // 17:55, the break still starts
+	if ev := next(); ev.Kind != EventAdvance || ev.State.Phase != PhaseShortBreak {
+		t.Fatalf("event %v %v, want advance to a break", ev.Kind, ev.State.Phase)
+	}
+	fc.fireLast() // 18:05, no more work
+	if ev := next(); ev.Kind != EventStop || !ev.QuittingTime || !ev.State.StartedAt.IsZero() {
+		t.Fatalf("event %v %+v, want a quitting time stop", ev.Kind, ev)
+	}
+	if !eng.AfterHours() {
+		t.Fatal("not after hours")
+	}
+	eng.Start()
+	if ev := next(); ev.Kind != EventRefused || !errors.Is(ev.Refusal, ErrQuittingTime) {
+		t.Fatalf("event %v (%v), want refused", ev.Kind, ev.Refusal)
+	}
+
+	eng.OverrideQuittingTime()
+	next()
+	eng.Start()
+	if ev := next(); ev.Kind != EventStart || eng.AfterHours() {
+		t.Fatalf("event %v after override, want start", ev.Kind)
+	}
+	// the override lasts the day
+	fc.mu.Lock()
+	fc.now = fc.now.Add(24 * time.Hour)
+	fc.mu.Unlock()
+	if !eng.AfterHours() {
+		t.Fatal("override outlived its day")
+	}
+}
+
+func TestCycles_StopsAfterLastPomodoro(t *testing.T) {
+	eng, fc := newTestEngine(Config{Work: time.Second, ShortBrk: time.Second, LongBrk: time.Second, LongEvery: 4, Cycles: 2})
+	events := make(chan Event, 16)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+	next := func() Event {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("timeout waiting for an event")
+			return Event{}
+		}
+	}
+
+	eng.Start()
+	next()
+	eng.Skip() // a skipped pomodoro doesn't count
+	next()
+	fc.fireLast()
+	next()
+	fc.fireLast() // first pomodoro
+	if ev := next(); ev.Kind != EventAdvance || ev.State.Phase != PhaseShortBreak {
+		t.Fatalf("event %v %v, want advance to a break", ev.Kind, ev.State.Phase)
+	}
+	fc.fireLast()
+	next()
+	fc.fireLast() // second, and last
+	if ev := next(); ev.Kind != EventStop || !ev.Finished || !ev.State.StartedAt.IsZero() {
+		t.Fatalf("event %v %+v, want a finished stop", ev.Kind, ev)
+	}
+}
+
+func TestEvent_CompletedWork(t *testing.T) {
+	work := State{Phase: PhaseWork}
+	over := State{Phase: PhaseWork, Overtime: true}
+	brk := State{Phase: PhaseShortBreak}
+	for _, tc := range []struct {
+		name string
+		ev   Event
+		prev State
+		want bool
+	}{
+		{"advance from work", Event{Kind: EventAdvance}, work, true},
+		{"advance from a break", Event{Kind: EventAdvance}, brk, false},
+		{"last of the cycles", Event{Kind: EventStop, Finished: true}, work, true},
+		{"quitting time", Event{Kind: EventStop, QuittingTime: true}, work, true},
+		{"quitting time after a break", Event{Kind: EventStop, QuittingTime: true}, brk, false},
+		{"stop in overtime", Event{Kind: EventStop}, over, true},
+		{"stop", Event{Kind: EventStop}, work, false},
+		{"skip", Event{Kind: EventSkip}, work, false},
+		{"skip in overtime", Event{Kind: EventSkip}, over, true},
+		{"pause", Event{Kind: EventPause}, over, false},
+	} {
+		if got := tc.ev.CompletedWork(tc.prev); got != tc.want {
+			t.Errorf("%s: CompletedWork = %v, want %v", tc.name, got, tc.want)
+		}
+	}
+}
+
+func TestRestore_RunningPausedAndOpen(t *testing.T) {
+	eng := New(Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	events := make(chan Event, 4)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+	next := func() Event {
+		t.Helper()
+		select {
+		case ev := <-events:
+			return ev
+		case <-time.After(200 * time.Millisecond):
+			t.Fatal("timeout waiting for an event")
+			return Event{}
+		}
+	}
+	st := State{Phase: PhaseWork, StartedAt: time.Now().Add(-time.Hour), Length: 25 * time.Minute, PomodoroDone: 2}
+
+	eng.Restore(st, 10*time.Minute, 0)
+	if ev := next(); ev.Kind != EventResume || ev.State.PomodoroDone != 2 {
+		t.Fatalf("event %v %+v, want a resume keeping the count", ev.Kind, ev.State)
+	}
+	if rem := eng.Remaining(); rem > 10*time.Minute || rem < 10*time.Minute-time.Second {
+		t.Fatalf("remaining %v, want 10m", rem)
+	}
+
+	st.Paused = true
+	eng.Restore(st, 5*time.Minute, 0)
+	if ev := next(); ev.Kind != EventUpdate || !ev.State.Paused {
+		t.Fatalf("event %v %+v, want a paused update", ev.Kind, ev.State)
+	}
+	if rem := eng.Remaining(); rem != 5*time.Minute {
+		t.Fatalf("remaining %v while paused, want 5m", rem)
+	}
+
+	st = State{Phase: PhaseWork, StartedAt: st.StartedAt, Open: true}
+	eng.Restore(st, 0, 3*time.Minute)
+	next()
+	if el := eng.Elapsed(); el < 3*time.Minute || el > 3*time.Minute+time.Second {
+		t.Fatalf("elapsed %v, want 3m", el)
+	}
+}
+
+func TestStrict_RefusesPauseSkipExtend(t *testing.T) {
+	eng := New(Config{Work: 25 * time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4, Strict: true})
+	events := make(chan Event, 16)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+
+	eng.Start()
+	<-events
+	running := eng.State()
+	for _, err := range []error{eng.Pause(), eng.Skip(), eng.Extend(5 * time.Minute)} {
+		if !errors.Is(err, ErrStrict) {
+			t.Fatalf("got %v, want a strict refusal", err)
+		}
+	}
+	if st := eng.State(); !reflect.DeepEqual(st, running) {
+		t.Fatalf("strict mode let the state change to %+v", st)
+	}
+	for range 3 {
+		if ev := <-events; ev.Kind != EventRefused || !errors.Is(ev.Refusal, ErrStrict) {
+			t.Fatalf("event %v (%v), want a strict refusal", ev.Kind, ev.Refusal)
+		}
+	}
+
+	// stopping is the way out, and voids the pomodoro
+	eng.Stop()
+	if ev := <-events; ev.Kind != EventAbandon || ev.State.Phase != PhaseWork {
+		t.Fatalf("event %v %+v, want the work phase abandoned", ev.Kind, ev.State)
+	}
+	<-events
+
+	// breaks are not strict
+	eng.SetConfig(Config{Work: time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4, Strict: true, Cycle: []Step{{Kind: PhaseShortBreak, Duration: time.Minute}}})
+	eng.Start()
+	<-events
+	eng.Pause()
+	if ev := <-events; ev.Kind != EventPause {
+		t.Fatalf("event %v, want a paused break", ev.Kind)
+	}
+}
+
+func TestFlow_OpenWorkSizesBreak(t *testing.T) {
+	eng, fc := newTestEngine(Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 30 * time.Minute, LongEvery: 4, Flow: true})
+	pass := func(d time.Duration) {
+		fc.mu.Lock()
+		fc.now = fc.now.Add(d)
+		fc.mu.Unlock()
+	}
+
+	eng.Start()
+	st := eng.State()
+	if !st.Open || !st.EndsAt.IsZero() || eng.Remaining() != 0 || fc.last != nil {
+		t.Fatalf("flow work should be open without a deadline: %+v", st)
+	}
+	pass(40 * time.Minute)
+	eng.Pause()
+	pass(10 * time.Minute) // paused time doesn't count
+	eng.Resume()
+	pass(10 * time.Minute)
+	if got := eng.Elapsed(); got != 50*time.Minute {
+		t.Fatalf("elapsed: want 50m, got %v", got)
+	}
+	if err := eng.Extend(time.Minute); !errors.Is(err, ErrInvalidTransition) {
+		t.Fatalf("extending an open phase: %v, want ErrInvalidTransition", err)
+	}
+
+	eng.Acknowledge()
+	st = eng.State()
+	if st.Phase != PhaseShortBreak || st.Open || st.Length != 10*time.Minute || st.PomodoroDone != 1 {
+		t.Fatalf("want a 10m break after 50m of flow, got %+v", st)
+	}
+
+	eng.Skip()
+	if st := eng.State(); st.Phase != PhaseWork || !st.Open || eng.Elapsed() != 0 {
+		t.Fatalf("want open work again, got %+v", st)
+	}
+	pass(4 * time.Hour)
+	eng.Acknowledge()
+	if st := eng.State(); st.Length != 30*time.Minute {
+		t.Fatalf("break should be capped at 30m, got %v", st.Length)
+	}
+}
+
+func TestReloadConfig_AppliesFromNextPhase(t *testing.T) {
+	eng := New(Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	events := make(chan Event, 16)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+
+	eng.Start()
+	<-events
+	eng.ReloadConfig(Config{Work: 50 * time.Minute, ShortBrk: 10 * time.Minute, LongBrk: 30 * time.Minute, LongEvery: 4})
+	ev := <-events
+	if ev.Kind != EventConfigReloaded {
+		t.Fatalf("event %v, want config_reloaded", ev.Kind)
+	}
+	if ev.State.Length != 25*time.Minute {
+		t.Fatalf("running phase changed to %v", ev.State.Length)
+	}
+	eng.Skip()
+	if ev := <-events; ev.State.Length != 10*time.Minute {
+		t.Fatalf("break of %v, want the reloaded 10m", ev.State.Length)
+	}
+}
+
+func TestSubscribeTicks_AlignsToSeconds(t *testing.T) {
+	eng := New(Config{Work: 2500 * time.Millisecond, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4})
+	ticks := make(chan Event, 16)
+	defer eng.SubscribeTicks(func(ev Event) { ticks <- ev })()
+	events := make(chan Event, 16)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+
+	eng.Start()
+	for _, want := range []time.Duration{2 * time.Second, time.Second} {
+		ev := <-ticks
+		if ev.Kind != EventTick {
+			t.Fatalf("event %v, want tick", ev.Kind)
+		}
+		// just past the boundary, so the shown second has just turned
+		if ev.Remaining >= want || ev.Remaining < want-50*time.Millisecond {
+			t.Fatalf("tick with %v left, want just under %v", ev.Remaining, want)
+		}
+	}
+	eng.Pause()
+	select {
+	case ev := <-ticks:
+		t.Fatalf("tick while paused: %+v", ev)
+	case <-time.After(1200 * time.Millisecond):
+	}
+	for ev := range events {
+		if ev.Kind == EventTick {
+			t.Fatal("plain subscribers got a tick")
+		}
+		if ev.Kind == EventPause {
+			break
+		}
+	}
+}
+
+func TestClockJump_Policies(t *testing.T) {
+	const left = 15*time.Minute - clockCheck // after the check that sees the jump
+	tests := []struct {
+		name   string
+		policy ClockPolicy
+		jump   time.Duration
+		kind   EventKind
+		left   time.Duration
+		moved  bool // EndsAt moved with the jump
+	}{
+		{"monotonic ahead", ClockMonotonic, time.Hour, EventUpdate, left, true},
+		{"monotonic back", ClockMonotonic, -time.Hour, EventUpdate, left, true},
+		{"wall ahead", ClockWall, 5 * time.Minute, EventUpdate, left - 5*time.Minute, false},
+		{"wall back", ClockWall, -5 * time.Minute, EventUpdate, left + 5*time.Minute, false},
+		{"wall past the deadline", ClockWall, time.Hour, EventAdvance, time.Minute, false},
+		{"pause ahead", ClockPause, time.Hour, EventPause, left, false},
+		{"pause back", ClockPause, -time.Hour, EventUpdate, left, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &jumpClock{wall: time.Unix(1_000_000, 0)}
+			eng := NewWithClock(Config{
+				Work: 25 * time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4,
+				ClockPolicy: tt.policy,
+			}, c)
+			events := make(chan Event, 16)
+			defer eng.Subscribe(func(ev Event) { events <- ev })()
+			eng.Start()
+			<-events
+			c.waitArmed(t, 2) // deadline and clock check
+			c.run(10 * time.Minute)
+			c.waitArmed(t, 2)
+			endsAt := eng.State().EndsAt
+
+			c.jump(tt.jump)
+			c.run(clockCheck)
+			ev := <-events
+			if ev.Kind == EventUpdate && tt.kind == EventAdvance {
+				// the deadline passed in the jump
+				ev = <-events
+			}
+			if ev.Kind != tt.kind {
+				t.Fatalf("event %v, want %v", ev.Kind, tt.kind)
+			}
+			if got := eng.Remaining(); got != tt.left {
+				t.Errorf("remaining %v, want %v", got, tt.left)
+			}
+			st := eng.State()
+			if tt.kind == EventAdvance {
+				if st.Phase != PhaseShortBreak {
+					t.Errorf("phase %v, want a short break", st.Phase)
+				}
+				return
+			}
+			if tt.kind == EventPause && st.PauseReason != ReasonIdle {
+				t.Errorf("pause reason %v, want idle", st.PauseReason)
+			}
+			want := endsAt
+			if tt.moved {
+				want = endsAt.Add(tt.jump)
+			}
+			if !st.EndsAt.Equal(want) {
+				t.Errorf("ends at %v, want %v", st.EndsAt, want)
+			}
+		})
+	}
+}
+
+func TestClockJump_SmallDriftIgnored(t *testing.T) {
+	c := &jumpClock{wall: time.Unix(1_000_000, 0)}
+	eng := NewWithClock(Config{Work: 25 * time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4, ClockPolicy: ClockWall}, c)
+	eng.Start()
+	c.waitArmed(t, 2)
+	c.jump(clockJump / 2)
+	c.run(clockCheck)
+	c.waitArmed(t, 2)
+	if got, want := eng.Remaining(), 25*time.Minute-clockCheck; got != want {
+		t.Fatalf("remaining %v, want %v", got, want)
+	}
+}
+
+func TestParseClockPolicy(t *testing.T) {
+	for _, c := range []ClockPolicy{ClockMonotonic, ClockWall, ClockPause} {
+		if got, ok := ParseClockPolicy(c.String()); !ok || got != c {
+			t.Errorf("ParseClockPolicy(%q) = %v, %v", c, got, ok)
+		}
+	}
+	if _, ok := ParseClockPolicy("ntp"); ok {
+		t.Error("ParseClockPolicy accepted ntp")
+	}
+}
+
+func TestManager_NamespacesEvents(t *testing.T) {
+	cfg := Config{Work: 25 * time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4}
+	m := NewManager()
+	work := New(cfg)
+	if err := m.Add(DefaultTimer, work); err != nil {
+		t.Fatal(err)
+	}
+	events := make(chan Event, 8)
+	defer m.Subscribe(func(ev Event) { events <- ev })()
+
+	// timers added after Subscribe are followed too
+	laundry := New(cfg)
+	if err := m.Add("laundry", laundry); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Add("laundry", New(cfg)); !errors.Is(err, ErrTimerExists) {
+		t.Fatalf("duplicate Add: %v", err)
+	}
+	if err := m.Add("two words", New(cfg)); err == nil {
+		t.Fatal("Add accepted a name with a space")
+	}
+
+	laundry.Start()
+	if ev := <-events; ev.Timer != "laundry" || ev.Kind != EventStart {
+		t.Fatalf("event %v from %q", ev.Kind, ev.Timer)
+	}
+	if !work.State().StartedAt.IsZero() {
+		t.Fatal("starting laundry started the default timer")
+	}
+	work.Start()
+	if ev := <-events; ev.Timer != DefaultTimer {
+		t.Fatalf("event from %q", ev.Timer)
+	}
+
+	if err := m.Remove("laundry"); err != nil {
+		t.Fatal(err)
+	}
+	// the removal stops the timer, and subscribers see it
+	if ev := <-events; ev.Timer != "laundry" || ev.Kind != EventAbandon {
+		t.Fatalf("event %v from %q", ev.Kind, ev.Timer)
+	}
+	<-events // stop
+	if got := m.Names(); len(got) != 1 || got[0] != DefaultTimer || m.Get("laundry") != nil {
+		t.Fatalf("timers %v after Remove", got)
+	}
+	if err := m.Remove(DefaultTimer); err == nil {
+		t.Fatal("removed the default timer")
+	}
+}
+
+func TestFollow_ForwardsAndSyncs(t *testing.T) {
+	cfg := Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: time.Minute, LongEvery: 4}
+	leader, member := New(cfg), New(cfg)
+	defer leader.Stop()
+	var forwarded []Command
+	member.Follow(func(cmd Command) {
+		forwarded = append(forwarded, cmd)
+		if err := leader.Do(cmd); err != nil {
+			t.Error(err)
+		}
+	})
+	events := make(chan Event, 8)
+	defer member.Subscribe(func(ev Event) { events <- ev })()
+
+	member.SetTask(Task{Title: "mine"})
+	<-events
+	member.Start()
+	if len(forwarded) != 1 || forwarded[0].Action != "start" || !member.State().StartedAt.IsZero() {
+		t.Fatalf("Start wasn't forwarded: %v, state %+v", forwarded, member.State())
+	}
+	// the leader's clock runs a minute behind
+	ev := Event{Kind: EventStart, State: leader.State(), Remaining: leader.Remaining(), At: time.Now().Add(-time.Minute)}
+	if err := member.Sync(ev); err != nil {
+		t.Fatal(err)
+	}
+	got := <-events
+	if got.Kind != EventStart || got.State.Phase != PhaseWork || got.State.Task.Title != "mine" {
+		t.Fatalf("synced event %v %+v", got.Kind, got.State)
+	}
+	if d := got.State.EndsAt.Sub(leader.State().EndsAt); d < 59*time.Second || d > 61*time.Second {
+		t.Fatalf("deadline shifted by %v, want the clock difference", d)
+	}
+
+	member.Pause()
+	if !leader.State().Paused || member.State().Paused {
+		t.Fatal("Pause should only reach the leader")
+	}
+	if member.Extend(time.Minute); leader.State().Length != 26*time.Minute {
+		t.Fatalf("leader length %v after a forwarded extend", leader.State().Length)
+	}
+	if err := member.Interrupt(InterruptExternal, "phone"); err != nil || leader.State().Interruptions != 1 || member.State().Interruptions != 0 {
+		t.Fatalf("Interrupt should only reach the leader: %v, leader %d, member %d", err, leader.State().Interruptions, member.State().Interruptions)
+	}
+
+	// on its own again, the member runs from the last synced state
+	member.Follow(nil)
+	member.Pause()
+	if !member.State().Paused {
+		t.Fatal("the unfollowed member didn't pause")
+	}
+	if err := member.Sync(ev); err == nil {
+		t.Fatal("Sync while not following")
+	}
+}
+
+func TestUndo_SkipExtendStop(t *testing.T) {
+	eng := New(Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	if err := eng.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("undo with nothing done: %v, want ErrNothingToUndo", err)
+	}
+	events := make(chan Event, 16)
+	defer eng.Subscribe(func(ev Event) { events <- ev })()
+
+	eng.Start()
+	work := eng.State()
+	eng.Skip()
+	eng.Skip()
+	if st := eng.State(); st.Phase != PhaseWork || st.StartedAt.Equal(work.StartedAt) {
+		t.Fatalf("unexpected state after two skips %+v", st)
+	}
+	if got := eng.CanUndo(); got != "skip" {
+		t.Fatalf("CanUndo = %q, want skip", got)
+	}
+	// skips are taken back one at a time
+	eng.Undo()
+	if st := eng.State(); st.Phase != PhaseShortBreak {
+		t.Fatalf("unexpected state after one undo %+v", st)
+	}
+	eng.Undo()
+	if st := eng.State(); st.Phase != PhaseWork || !st.StartedAt.Equal(work.StartedAt) || st.PomodoroDone != 0 {
+		t.Fatalf("undo didn't restore the work phase: %+v", st)
+	}
+	if left := eng.Remaining(); left <= 24*time.Minute {
+		t.Fatalf("undone work should keep its time, %v left", left)
+	}
+
+	eng.Extend(5 * time.Minute)
+	eng.Undo()
+	if st := eng.State(); st.Length != 25*time.Minute || eng.Remaining() > 25*time.Minute {
+		t.Fatalf("undo didn't take the extension back: %+v, %v left", st, eng.Remaining())
+	}
+
+	eng.Pause()
+	eng.StopWithReason("meeting")
+	eng.Undo()
+	if st := eng.State(); st.Phase != PhaseWork || !st.Paused || !st.StartedAt.Equal(work.StartedAt) {
+		t.Fatalf("undo didn't restore the paused work phase: %+v", st)
+	}
+	if err := eng.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("undo past the stack: %v, want ErrNothingToUndo", err)
+	}
+
+	var undone []string
+	for len(undone) < 4 {
+		select {
+		case ev := <-events:
+			if ev.Kind == EventUndo {
+				undone = append(undone, ev.Undone)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("undo events %q, want 4", undone)
+		}
+	}
+	if got := strings.Join(undone, ","); got != "skip,skip,extend,stop" {
+		t.Fatalf("undo events %q", got)
+	}
+}
+
+func TestUndo_StrictStopStaysVoid(t *testing.T) {
+	eng, fc := newTestEngine(Config{Work: time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4, Strict: true})
+	defer eng.Stop()
+
+	eng.Start()
+	eng.Stop()
+	if got := eng.CanUndo(); got != "" {
+		t.Fatalf("CanUndo = %q after a strict stop", got)
+	}
+	if err := eng.Undo(); !errors.Is(err, ErrStrict) || !eng.State().Idle() {
+		t.Fatalf("undo of a strict stop: %v, %+v", err, eng.State())
+	}
+
+	// strict mode doesn't hold breaks, so stopping one can be undone
+	advanced := make(chan struct{}, 1)
+	defer eng.Subscribe(func(ev Event) {
+		if ev.Kind == EventAdvance {
+			advanced <- struct{}{}
+		}
+	})()
+	eng.Start()
+	fc.fireLast()
+	select {
+	case <-advanced:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for the break")
+	}
+	eng.Stop()
+	if err := eng.Undo(); err != nil || eng.State().Phase != PhaseShortBreak {
+		t.Fatalf("undo of a break's stop: %v, %+v", err, eng.State())
+	}
+}
+
+func TestUndo_WindowAndLaterChanges(t *testing.T) {
+	eng, fc := newTestEngine(Config{Work: 10 * time.Second, ShortBrk: 5 * time.Second, LongBrk: 15 * time.Second, LongEvery: 4})
+	defer eng.Stop()
+
+	eng.Start()
+	eng.Skip()
+	fc.mu.Lock()
+	fc.now = fc.now.Add(UndoWindow + time.Second)
+	fc.mu.Unlock()
+	if err := eng.Undo(); !errors.Is(err, ErrNothingToUndo) {
+		t.Fatalf("undo after the window: %v, want ErrNothingToUndo", err)
+	}
+
+	// a pause since the skip makes it stick
+	eng.Skip()
+	eng.Pause()
+	if err := eng.Undo(); !errors.Is(err, ErrNothingToUndo) || eng.State().Phase != PhaseWork {
+		t.Fatalf("undo after a pause: %v, %+v", err, eng.State())
+	}
+}
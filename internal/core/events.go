@@ -0,0 +1,336 @@
+package core
+
+import (
+	"sync"
+	"time"
+)
+
+// EventKind identifies what happened in the engine.
+type EventKind int
+
+const (
+	EventStart EventKind = iota
+	EventAdvance
+	EventPause
+	EventResume
+	EventStop
+	// EventUpdate reports a change that is not a transition,
+	// such as a pause reason being set.
+	EventUpdate
+	// EventInterrupt carries a logged Interruption.
+	EventInterrupt
+	// EventOvertime fires when a work phase ends with Config.Overtime on.
+	EventOvertime
+	// EventSkip fires when a phase is ended early with Skip; State is
+	// the phase that follows.
+	EventSkip
+	// EventWarning fires Config.Warnings ahead of a phase's end.
+	EventWarning
+	// EventExtend fires when Extend lengthens or shortens a phase.
+	EventExtend
+	// EventAbandon fires when Stop or Start cuts a phase short; State is
+	// the abandoned phase. EventStop or EventStart follows.
+	EventAbandon
+	// EventTask fires when SetTask or SetTags changes the attached task
+	// or tags.
+	EventTask
+	// EventRefused fires when the StartCheck or quitting time refuses
+	// Start, or strict mode a pause, skip or extension; State is
+	// unchanged and Refusal says why.
+	EventRefused
+	// EventConfigReloaded fires when ReloadConfig applies a changed
+	// config file.
+	EventConfigReloaded
+	// EventTick goes to SubscribeTicks subscribers only, each time a
+	// running phase's shown time turns over a whole second.
+	EventTick
+	// EventUndo fires when Undo takes back a skip, stop or extension;
+	// State is the phase restored and Undone names the action.
+	EventUndo
+)
+
+func (k EventKind) String() string {
+	switch k {
+	case EventStart:
+		return "start"
+	case EventAdvance:
+		return "advance"
+	case EventPause:
+		return "pause"
+	case EventResume:
+		return "resume"
+	case EventStop:
+		return "stop"
+	case EventUpdate:
+		return "update"
+	case EventInterrupt:
+		return "interrupt"
+	case EventOvertime:
+		return "overtime"
+	case EventSkip:
+		return "skip"
+	case EventWarning:
+		return "warning"
+	case EventExtend:
+		return "extend"
+	case EventAbandon:
+		return "abandon"
+	case EventTask:
+		return "task"
+	case EventRefused:
+		return "refused"
+	case EventConfigReloaded:
+		return "config_reloaded"
+	case EventTick:
+		return "tick"
+	case EventUndo:
+		return "undo"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is published to subscribers after every state change.
+type Event struct {
+	Kind      EventKind
+	State     State
+	Remaining time.Duration
+	// Elapsed is the active time spent in the phase, as Elapsed returns.
+	Elapsed time.Duration
+	At      time.Time
+	// Timer is the engine's name in a Manager, or "" outside one.
+	Timer string
+
+	// Interruption is set for EventInterrupt.
+	Interruption *Interruption
+	// Warning is the configured time left for EventWarning.
+	Warning time.Duration
+	// Extension is the change Extend applied, for EventExtend.
+	Extension time.Duration
+	// AbandonReason is why the phase was stopped, for the EventAbandon
+	// of a StopWithReason; empty when none was given.
+	AbandonReason string
+	// Undone is the action Undo took back, "skip", "stop" or "extend",
+	// for EventUndo.
+	Undone string
+	// Refusal is the StartCheck's, strict mode's or quitting time's
+	// error, for EventRefused.
+	Refusal error
+	// QuittingTime is set on the EventStop of a phase that ended past
+	// Config.QuittingTime instead of leading to work; the phase was
+	// completed.
+	QuittingTime bool
+	// Finished is set on the EventStop that ends a run of Config.Cycles
+	// pomodoros; the last one was completed.
+	Finished bool
+}
+
+// CompletedWork reports whether ev ends a work phase that ran its
+// course, given prev, the state before ev: an advance from work, the
+// stop ending a Cycles run or the day at quitting time, or any end of
+// a work phase in overtime. It counts pomodoros as the history does.
+func (ev Event) CompletedWork(prev State) bool {
+	if prev.Phase != PhaseWork {
+		return false
+	}
+	switch ev.Kind {
+	case EventAdvance:
+		return true
+	case EventStop:
+		return ev.Finished || ev.QuittingTime || prev.Overtime
+	case EventSkip, EventStart:
+		// the deadline was reached, so leaving overtime still counts
+		return prev.Overtime
+	}
+	return false
+}
+
+// subscriber delivers events in order on its own goroutine, so a slow
+// or re-entrant callback never blocks the engine.
+type subscriber struct {
+	fn     func(Event)
+	mu     sync.Mutex
+	queue  []Event
+	wake   chan struct{}
+	done   chan struct{}
+	exited chan struct{}
+}
+
+func newSubscriber(fn func(Event)) *subscriber {
+	s := &subscriber{
+		fn:     fn,
+		wake:   make(chan struct{}, 1),
+		done:   make(chan struct{}),
+		exited: make(chan struct{}),
+	}
+	go s.run()
+	return s
+}
+
+func (s *subscriber) push(ev Event) {
+	s.mu.Lock()
+	s.queue = append(s.queue, ev)
+	s.mu.Unlock()
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *subscriber) run() {
+	defer close(s.exited)
+	for {
+		select {
+		case <-s.done:
+			s.drain()
+			return
+		case <-s.wake:
+			s.drain()
+		}
+	}
+}
+
+func (s *subscriber) drain() {
+	for {
+		s.mu.Lock()
+		if len(s.queue) == 0 {
+			s.mu.Unlock()
+			return
+		}
+		ev := s.queue[0]
+		s.queue = s.queue[1:]
+		s.mu.Unlock()
+		s.fn(ev)
+	}
+}
+
+// Subscribe registers fn for every engine event. Events are delivered
+// in order on a dedicated goroutine, so fn may call back into the engine.
+// The returned function unsubscribes and waits until events already
+// queued have been delivered; don't call it from inside fn.
+func (p *PomodoroEngine) Subscribe(fn func(Event)) (cancel func()) {
+	s := newSubscriber(fn)
+	p.subMu.Lock()
+	p.subs = append(p.subs, s)
+	p.subMu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			p.subMu.Lock()
+			for i, cur := range p.subs {
+				if cur == s {
+					p.subs = append(p.subs[:i], p.subs[i+1:]...)
+					break
+				}
+			}
+			p.subMu.Unlock()
+			close(s.done)
+			<-s.exited
+		})
+	}
+}
+
+// publishLocked fans an event out to subscribers. The caller holds p.mu.
+func (p *PomodoroEngine) publishLocked(kind EventKind) {
+	p.publishEventLocked(Event{Kind: kind})
+}
+
+// publishEventLocked fills in the snapshot fields of ev and publishes it.
+func (p *PomodoroEngine) publishEventLocked(ev Event) {
+	ev = p.snapshotLocked(ev)
+	p.subMu.Lock()
+	defer p.subMu.Unlock()
+	for _, s := range p.subs {
+		s.push(ev)
+	}
+}
+
+// snapshotLocked fills in the snapshot fields of ev.
+func (p *PomodoroEngine) snapshotLocked(ev Event) Event {
+	ev.State = p.state
+	ev.Remaining = p.remainingLocked()
+	ev.Elapsed = p.elapsedLocked()
+	ev.At = p.clock.Now()
+	ev.Timer = p.name
+	return ev
+}
+
+// SubscribeTicks registers fn for EventTick, which fires while a phase
+// runs each time the time left, or the time elapsed in open and
+// overtime phases, crosses a whole second. A display redrawn on ticks
+// shows every second exactly once, however its own timers drift. Ticks
+// are delivered in order on their own goroutine; cancel as for
+// Subscribe.
+func (p *PomodoroEngine) SubscribeTicks(fn func(Event)) (cancel func()) {
+	// any event may start, stop or move the next tick
+	wake := make(chan struct{}, 1)
+	unsubscribe := p.Subscribe(func(Event) {
+		select {
+		case wake <- struct{}{}:
+		default:
+		}
+	})
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	go func() {
+		defer close(exited)
+		for {
+			var fire <-chan time.Time
+			var t Timer
+			if d, ok := p.nextTick(); ok {
+				t = p.clock.NewTimer(d)
+				fire = t.C()
+			}
+			select {
+			case <-done:
+			case <-wake:
+			case <-fire:
+				p.mu.RLock()
+				ev := p.snapshotLocked(Event{Kind: EventTick})
+				p.mu.RUnlock()
+				fn(ev)
+			}
+			if t != nil {
+				t.Stop()
+			}
+			select {
+			case <-done:
+				return
+			default:
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			unsubscribe()
+			close(done)
+			<-exited
+		})
+	}
+}
+
+// nextTick is how long until the shown time next crosses a whole
+// second, or false while no phase runs.
+func (p *PomodoroEngine) nextTick() (time.Duration, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	st := p.state
+	if st.Idle() || st.Paused {
+		return 0, false
+	}
+	if st.Open || st.Overtime {
+		return time.Second - p.elapsedLocked()%time.Second, true
+	}
+	rem := p.remainingLocked()
+	if rem <= 0 {
+		// the phase is ending; its events wake us
+		return 0, false
+	}
+	if d := rem % time.Second; d > 0 {
+		return d, true
+	}
+	return time.Second, true
+}
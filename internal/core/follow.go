@@ -0,0 +1,134 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Command is a control call on an engine, as forwarded by a following
+// engine to its leader.
+type Command struct {
+	// Action is start, pause, reason (set the pause reason), resume,
+	// stop, skip, acknowledge, extend, remaining (SetRemaining), undo or
+	// interrupt.
+	Action string           `json:"action"`
+	Reason PauseReason      `json:"reason,omitempty"`
+	By     time.Duration    `json:"by,omitempty"`   // for extend and remaining
+	Note   string           `json:"note,omitempty"` // why, for stop and interrupt
+	Kind   InterruptionKind `json:"kind,omitempty"` // for interrupt
+}
+
+// Do applies cmd to the engine, returning the error of the call.
+func (p *PomodoroEngine) Do(cmd Command) error {
+	switch cmd.Action {
+	case "start":
+		return p.Start()
+	case "pause":
+		return p.PauseWithReason(cmd.Reason)
+	case "reason":
+		return p.SetPauseReason(cmd.Reason)
+	case "resume":
+		return p.Resume()
+	case "stop":
+		p.StopWithReason(cmd.Note)
+	case "skip":
+		return p.Skip()
+	case "acknowledge":
+		return p.Acknowledge()
+	case "extend":
+		return p.Extend(cmd.By)
+	case "remaining":
+		return p.SetRemaining(cmd.By)
+	case "undo":
+		return p.Undo()
+	case "interrupt":
+		return p.Interrupt(cmd.Kind, cmd.Note)
+	default:
+		return fmt.Errorf("unknown command %q", cmd.Action)
+	}
+	return nil
+}
+
+// Follow makes the engine mirror a leader, such as the host of a team
+// session: control calls (all but SetTask) go to forward
+// instead of changing the engine and return nil, leaving the leader to
+// refuse them; the engine no longer advances on its own, and the
+// leader's events come in through Sync. Follow(nil) makes the engine
+// independent again, running on from the last synced state.
+func (p *PomodoroEngine) Follow(forward func(Command)) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.forward = forward
+	if forward != nil {
+		p.stopLocked()
+		return
+	}
+	if !p.state.Idle() && !p.state.Paused && !p.state.Overtime {
+		p.spawnLocked()
+	}
+}
+
+// forwarded hands cmd to the leader while following, reporting whether
+// it did. It must be called without holding p.mu.
+func (p *PomodoroEngine) forwarded(cmd Command) bool {
+	p.mu.RLock()
+	forward := p.forward
+	p.mu.RUnlock()
+	if forward == nil {
+		return false
+	}
+	forward(cmd)
+	return true
+}
+
+// Sync adopts a leader's event while following: the engine takes over
+// its state, with times shifted by the difference between the two
+// clocks, and publishes it as its own. The task and the tags stay
+// local, as do the leader's task and config reload events.
+func (p *PomodoroEngine) Sync(ev Event) error {
+	if ev.Kind == EventTask || ev.Kind == EventConfigReloaded {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.forward == nil {
+		return errors.New("sync: engine is not following")
+	}
+	skew := p.clock.Now().Sub(ev.At)
+	st := ev.State
+	if !st.StartedAt.IsZero() {
+		st.StartedAt = st.StartedAt.Add(skew)
+	}
+	if !st.EndsAt.IsZero() {
+		st.EndsAt = st.EndsAt.Add(skew)
+	}
+	st.Task, st.Tags = p.state.Task, p.state.Tags
+	p.state = st
+	p.pausedRemain = 0
+	if st.Paused {
+		p.pausedRemain = ev.Remaining
+	}
+	p.worked = 0
+	p.anchorLocked(st.Length - ev.Elapsed)
+	if st.Open {
+		// count the leader's time so far from now on
+		p.worked = ev.Elapsed
+		p.state.StartedAt = p.clock.Now()
+		p.anchorLocked(0)
+	}
+	var it *Interruption
+	if ev.Interruption != nil {
+		shifted := *ev.Interruption
+		shifted.At = shifted.At.Add(skew)
+		it = &shifted
+	}
+	p.publishEventLocked(Event{
+		Kind:         ev.Kind,
+		Warning:      ev.Warning,
+		Extension:    ev.Extension,
+		Refusal:      ev.Refusal,
+		Interruption: it,
+	})
+	return nil
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// DefaultTimer is the name of the main timer, the one history and the
+// integrations follow.
+const DefaultTimer = "default"
+
+// ErrTimerExists is returned by Manager.Add for a name already in use.
+var ErrTimerExists = errors.New("timer already exists")
+
+// Manager runs independent engines side by side, addressed by name,
+// such as "default" for work and "laundry" for the washing machine.
+type Manager struct {
+	mu      sync.Mutex
+	engines map[string]*PomodoroEngine
+	names   []string // in the order added
+	subs    []*managerSub
+}
+
+// managerSub is one Manager.Subscribe, subscribed to every engine.
+type managerSub struct {
+	fn      func(Event)
+	cancels map[string]func()
+}
+
+// NewManager returns an empty Manager.
+func NewManager() *Manager {
+	return &Manager{engines: map[string]*PomodoroEngine{}}
+}
+
+// ValidTimerName reports whether name can name a timer: letters,
+// digits, '-' and '_', as it ends up in URLs and topics.
+func ValidTimerName(name string) bool {
+	if name == "" || len(name) > 64 {
+		return false
+	}
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// Add registers e under name. Its events carry the name from now on.
+func (m *Manager) Add(name string, e *PomodoroEngine) error {
+	if !ValidTimerName(name) {
+		return fmt.Errorf("timer name %q: use letters, digits, - and _", name)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.engines[name]; ok {
+		return fmt.Errorf("%w: %s", ErrTimerExists, name)
+	}
+	e.mu.Lock()
+	e.name = name
+	e.mu.Unlock()
+	m.engines[name] = e
+	m.names = append(m.names, name)
+	for _, s := range m.subs {
+		s.cancels[name] = e.Subscribe(s.fn)
+	}
+	return nil
+}
+
+// Get returns the engine named name, or nil.
+func (m *Manager) Get(name string) *PomodoroEngine {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.engines[name]
+}
+
+// Names lists the timers in the order they were added.
+func (m *Manager) Names() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.names...)
+}
+
+// Remove stops the named timer and forgets it. The default timer can't
+// be removed.
+func (m *Manager) Remove(name string) error {
+	if name == DefaultTimer {
+		return errors.New("the default timer can't be removed")
+	}
+	m.mu.Lock()
+	e, ok := m.engines[name]
+	if !ok {
+		m.mu.Unlock()
+		return fmt.Errorf("no timer %q", name)
+	}
+	delete(m.engines, name)
+	for i, n := range m.names {
+		if n == name {
+			m.names = append(m.names[:i], m.names[i+1:]...)
+			break
+		}
+	}
+	var cancels []func()
+	for _, s := range m.subs {
+		cancels = append(cancels, s.cancels[name])
+		delete(s.cancels, name)
+	}
+	m.mu.Unlock()
+	// subscribers still see the stop
+	e.Stop()
+	for _, cancel := range cancels {
+		cancel()
+	}
+	return nil
+}
+
+// Subscribe registers fn for the events of every timer, present and
+// future; Event.Timer says which one. Events of one timer arrive in
+// order, with no ordering across timers.
+func (m *Manager) Subscribe(fn func(Event)) (cancel func()) {
+	s := &managerSub{fn: fn, cancels: map[string]func(){}}
+	m.mu.Lock()
+	for name, e := range m.engines {
+		s.cancels[name] = e.Subscribe(fn)
+	}
+	m.subs = append(m.subs, s)
+	m.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			m.mu.Lock()
+			for i, cur := range m.subs {
+				if cur == s {
+					m.subs = append(m.subs[:i], m.subs[i+1:]...)
+					break
+				}
+			}
+			cancels := s.cancels
+			s.cancels = nil
+			m.mu.Unlock()
+			for _, cancel := range cancels {
+				cancel()
+			}
+		})
+	}
+}
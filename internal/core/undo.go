@@ -0,0 +1,134 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// UndoWindow is how long after a Skip, Stop or Extend Undo can still
+// take it back.
+const UndoWindow = time.Minute
+
+// UndoDepth is how many actions Undo can take back in a row.
+const UndoDepth = 5
+
+// ErrNothingToUndo is returned by Undo when there is no action it may
+// take back.
+var ErrNothingToUndo = errors.New("nothing to undo")
+
+// undoable is the engine as it was before an action Undo can take
+// back, and the state the action left, which must still hold.
+type undoable struct {
+	action       string // skip, stop or extend
+	at           time.Time
+	state        State
+	anchor       anchor
+	pausedRemain time.Duration
+	worked       time.Duration
+	focus        time.Duration
+	after        State
+	// strict is whether Config.Strict held the phase: a strict stop
+	// voided the pomodoro for good.
+	strict bool
+}
+
+// snapshotUndoLocked takes the engine as it is before action, to be
+// completed by keepLocked once the action is done.
+func (p *PomodoroEngine) snapshotUndoLocked(action string) undoable {
+	return undoable{
+		action:       action,
+		at:           p.clock.Now(),
+		state:        p.state,
+		anchor:       p.anchor,
+		pausedRemain: p.pausedRemain,
+		worked:       p.worked,
+		focus:        p.focus,
+		strict:       p.strictLocked(),
+	}
+}
+
+// keepLocked pushes u, taken before an action that has now changed the
+// engine, onto the undo stack.
+func (p *PomodoroEngine) keepLocked(u undoable) {
+	u.after = p.state
+	p.undo = append(p.undo, u)
+	if len(p.undo) > UndoDepth {
+		p.undo = p.undo[1:]
+	}
+}
+
+// sameTiming reports whether a and b are the same phase at the same
+// point, ignoring what doesn't bear on timing, such as the task.
+func sameTiming(a, b State) bool {
+	return a.Phase == b.Phase && a.Step == b.Step && a.PomodoroDone == b.PomodoroDone &&
+		a.StartedAt.Equal(b.StartedAt) && a.EndsAt.Equal(b.EndsAt) && a.Length == b.Length &&
+		a.Paused == b.Paused && a.Overtime == b.Overtime && a.Open == b.Open
+}
+
+// CanUndo names the action Undo would take back, "skip", "stop" or
+// "extend", or returns "" when there is none.
+func (p *PomodoroEngine) CanUndo() string {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if u, ok := p.lastUndoLocked(); ok && !u.voids() {
+		return u.action
+	}
+	return ""
+}
+
+// lastUndoLocked is the action on top of the undo stack, if it is less
+// than UndoWindow old and nothing changed the phase since.
+func (p *PomodoroEngine) lastUndoLocked() (undoable, bool) {
+	if len(p.undo) == 0 {
+		return undoable{}, false
+	}
+	u := p.undo[len(p.undo)-1]
+	if p.clock.Now().Sub(u.at) > UndoWindow || !sameTiming(u.after, p.state) {
+		return undoable{}, false
+	}
+	return u, true
+}
+
+// voids reports whether u is a stop that voided a strict pomodoro,
+// which strict mode doesn't let Undo bring back.
+func (u undoable) voids() bool {
+	return u.action == "stop" && u.strict
+}
+
+// Undo takes back the last Skip, Stop or Extend, made at most
+// UndoWindow ago with nothing else changing the phase since, and
+// publishes EventUndo. The phase carries on as if the action never
+// happened: a running one kept counting down meanwhile. Several actions
+// in a row are taken back one at a time. The task and tags stay as
+// they are now. It returns ErrNothingToUndo otherwise, and ErrStrict
+// for the stop of a strict pomodoro, which stays void.
+func (p *PomodoroEngine) Undo() error {
+	if p.forwarded(Command{Action: "undo"}) {
+		return nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	u, ok := p.lastUndoLocked()
+	if !ok {
+		p.undo = nil
+		return ErrNothingToUndo
+	}
+	if u.voids() {
+		err := fmt.Errorf("%w: a stopped pomodoro stays void", ErrStrict)
+		p.publishEventLocked(Event{Kind: EventRefused, Refusal: err})
+		return err
+	}
+	p.undo = p.undo[:len(p.undo)-1]
+	p.stopLocked()
+	task, tags := p.state.Task, p.state.Tags
+	p.state = u.state
+	p.state.Task, p.state.Tags = task, tags
+	p.anchor = u.anchor
+	p.pausedRemain, p.worked, p.focus = u.pausedRemain, u.worked, u.focus
+	if !p.state.Paused && !p.state.Overtime {
+		p.spawnLocked()
+	}
+	p.publishEventLocked(Event{Kind: EventUndo, Undone: u.action})
+	return nil
+}
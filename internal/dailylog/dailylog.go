@@ -0,0 +1,157 @@
+// Package dailylog appends finished pomodoros to plain-text daily notes
+// (Markdown, Org or anything else line-based) and keeps a focus section
+// up to date in Obsidian daily notes.
+package dailylog
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// DefaultLine is the line written per session.
+const DefaultLine = "- {{.Start}}–{{.End}} 🍅{{with .Task}} {{.}}{{end}}"
+
+// Entry is the data a path or line template sees.
+type Entry struct {
+	Date          string // 2006-01-02, the day the session started
+	Start, End    string // 15:04
+	Minutes       int    // active minutes
+	Phase         string // WORK
+	Name          string // custom cycle step name
+	Task          string
+	Status        string // completed or abandoned
+	Interruptions int
+
+	Session history.Session
+}
+
+// NewEntry prepares s for the templates, in local time.
+func NewEntry(s history.Session) Entry {
+	e := Entry{
+		Date:          s.Start.Local().Format(time.DateOnly),
+		Start:         s.Start.Local().Format("15:04"),
+		End:           s.End.Local().Format("15:04"),
+		Minutes:       int(s.Active().Round(time.Minute) / time.Minute),
+		Phase:         s.Phase,
+		Name:          s.Name,
+		Status:        s.Status(),
+		Interruptions: len(s.Interruptions),
+		Session:       s,
+	}
+	if s.Task != nil {
+		e.Task = s.Task.Title
+	}
+	return e
+}
+
+// Options configures a Writer.
+type Options struct {
+	// Path is a template for the file, e.g. "~/notes/{{.Date}}.md".
+	Path string
+	// Line is the template for one session; default DefaultLine.
+	Line string
+	// Abandoned also logs work sessions stopped early.
+	Abandoned bool
+	OnError   func(error)
+}
+
+// Writer appends a line per finished work session to the day's file.
+type Writer struct {
+	path, line *template.Template
+	opts       Options
+}
+
+// New parses the templates in opts.
+func New(opts Options) (*Writer, error) {
+	if opts.Path == "" {
+		return nil, fmt.Errorf("log: path is required")
+	}
+	if opts.Line == "" {
+		opts.Line = DefaultLine
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	path, err := template.New("path").Option("missingkey=error").Parse(opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("log path: %w", err)
+	}
+	line, err := template.New("line").Option("missingkey=error").Parse(opts.Line)
+	if err != nil {
+		return nil, fmt.Errorf("log template: %w", err)
+	}
+	return &Writer{path: path, line: line, opts: opts}, nil
+}
+
+// Handle logs s if it is a completed (or, if enabled, abandoned) work
+// session. Pass it to history.Recorder.OnSession.
+func (w *Writer) Handle(s history.Session) {
+	if s.Phase != core.PhaseWork.String() || !(s.Completed || w.opts.Abandoned && s.Abandoned) {
+		return
+	}
+	if err := w.Write(s); err != nil {
+		w.opts.OnError(err)
+	}
+}
+
+// Write appends s to its file, creating the file and its directory as
+// needed.
+func (w *Writer) Write(s history.Session) error {
+	e := NewEntry(s)
+	path, err := expandPath(w.path, e)
+	if err != nil {
+		return err
+	}
+	var line bytes.Buffer
+	if err := w.line.Execute(&line, e); err != nil {
+		return fmt.Errorf("log template: %w", err)
+	}
+	line.WriteString("\n")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	out := line.Bytes()
+	// don't glue the line onto a file that lacks a trailing newline
+	if st, err := f.Stat(); err == nil && st.Size() > 0 {
+		last := make([]byte, 1)
+		if _, err := f.ReadAt(last, st.Size()-1); err == nil && last[0] != '\n' {
+			out = append([]byte("\n"), out...)
+		}
+	}
+	_, err = f.Write(out)
+	return err
+}
+
+// expandPath renders a path template for e and expands a leading "~".
+func expandPath(t *template.Template, e Entry) (string, error) {
+	var b strings.Builder
+	if err := t.Execute(&b, e); err != nil {
+		return "", fmt.Errorf("log path: %w", err)
+	}
+	return expandHome(b.String())
+}
+
+// expandHome replaces a leading "~" with the home directory.
+func expandHome(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return path, nil
+}
@@ -0,0 +1,91 @@
+package dailylog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestWriter_AppendsPerDay(t *testing.T) {
+	dir := t.TempDir()
+	w, err := New(Options{Path: filepath.Join(dir, "{{.Date}}.md"), OnError: func(err error) { t.Fatal(err) }})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.Local)
+	path := filepath.Join(dir, "2025-05-01.md")
+	if err := os.WriteFile(path, []byte("# Thursday"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	w.Handle(history.Session{Phase: "WORK", Start: base, End: base.Add(25 * time.Minute), Completed: true,
+		Task: &history.Task{Title: "write report"}})
+	w.Handle(history.Session{Phase: "SHORT_BREAK", Start: base.Add(25 * time.Minute), End: base.Add(30 * time.Minute), Completed: true})
+	w.Handle(history.Session{Phase: "WORK", Start: base.Add(30 * time.Minute), End: base.Add(40 * time.Minute), Abandoned: true})
+	w.Handle(history.Session{Phase: "WORK", Start: base.Add(time.Hour), End: base.Add(85 * time.Minute), Completed: true})
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "# Thursday\n- 09:00–09:25 🍅 write report\n- 10:00–10:25 🍅\n"
+	if string(got) != want {
+		t.Fatalf("log = %q, want %q", got, want)
+	}
+}
+
+func TestNew_BadTemplate(t *testing.T) {
+	if _, err := New(Options{Path: "x.md", Line: "{{.Start"}); err == nil {
+		t.Fatal("expected a template error")
+	}
+	if _, err := New(Options{}); err == nil {
+		t.Fatal("expected an error without a path")
+	}
+}
+
+func TestMergeSection(t *testing.T) {
+	doc := "# Today\nnotes\n\n## Focus\nold\n### sub\nold too\n\n## Later\n```\n## Focus\n```\n"
+	got := mergeSection(doc, "## Focus", "new\n")
+	want := "# Today\nnotes\n\n## Focus\nnew\n\n## Later\n```\n## Focus\n```\n"
+	if got != want {
+		t.Fatalf("merge =\n%q\nwant\n%q", got, want)
+	}
+	if got := mergeSection("# Today", "## Focus", "new"); got != "# Today\n\n## Focus\nnew\n" {
+		t.Fatalf("append = %q", got)
+	}
+}
+
+func TestNote_Update(t *testing.T) {
+	vault := t.TempDir()
+	day := time.Date(2025, 5, 1, 9, 0, 0, 0, time.Local)
+	sessions := []history.Session{
+		{Phase: "WORK", Start: day, End: day.Add(25 * time.Minute), Completed: true},
+		{Phase: "WORK", Start: day.Add(time.Hour), End: day.Add(85 * time.Minute), Completed: true},
+		{Phase: "WORK", Start: day.AddDate(0, 0, 1), End: day.AddDate(0, 0, 1).Add(25 * time.Minute), Completed: true},
+	}
+	n, err := NewNote(NoteOptions{Vault: vault, Folder: "Daily",
+		List: func() ([]history.Session, error) { return sessions, nil }})
+	if err != nil {
+		t.Fatalf("new: %v", err)
+	}
+	path := filepath.Join(vault, "Daily", "2025-05-01.md")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte("journal entry\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for range 2 { // updating twice must not duplicate the section
+		if err := n.Update(day); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+	}
+	got, _ := os.ReadFile(path)
+	want := "journal entry\n\n## Focus\n**2 pomodoros**, 50m0s focus\n- 09:00–09:25 🍅\n- 10:00–10:25 🍅\n"
+	if string(got) != want {
+		t.Fatalf("note = %q, want %q", got, want)
+	}
+}
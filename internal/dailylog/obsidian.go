@@ -0,0 +1,218 @@
+package dailylog
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// NoteOptions configures a Note.
+type NoteOptions struct {
+	// Vault is the Obsidian vault directory and Folder the daily notes
+	// folder inside it.
+	Vault, Folder string
+	// DateFormat is the Go layout of note file names; default
+	// "2006-01-02", Obsidian's default.
+	DateFormat string
+	// Heading is the section GoPomodoro owns; default "## Focus".
+	Heading string
+	// Line is the template for one session; default DefaultLine.
+	Line string
+	// List returns the history; the summary covers its sessions from
+	// the note's day.
+	List    func() ([]history.Session, error)
+	OnError func(error)
+}
+
+// Note keeps a focus summary under a heading in Obsidian daily notes.
+// Everything outside that section is left alone.
+type Note struct {
+	line *template.Template
+	opts NoteOptions
+}
+
+// NewNote checks opts and parses the line template.
+func NewNote(opts NoteOptions) (*Note, error) {
+	if opts.Vault == "" {
+		return nil, errors.New("obsidian: vault is required")
+	}
+	if opts.List == nil {
+		return nil, errors.New("obsidian: no history")
+	}
+	if opts.DateFormat == "" {
+		opts.DateFormat = time.DateOnly
+	}
+	if opts.Heading == "" {
+		opts.Heading = "## Focus"
+	}
+	if headingLevel(opts.Heading) == 0 {
+		return nil, fmt.Errorf("obsidian: heading %q must start with #", opts.Heading)
+	}
+	if opts.Line == "" {
+		opts.Line = DefaultLine
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	line, err := template.New("line").Option("missingkey=error").Parse(opts.Line)
+	if err != nil {
+		return nil, fmt.Errorf("obsidian template: %w", err)
+	}
+	return &Note{line: line, opts: opts}, nil
+}
+
+// Handle refreshes the note for the day of a completed work session.
+// Pass it to history.Recorder.OnSession.
+func (n *Note) Handle(s history.Session) {
+	if s.Phase != core.PhaseWork.String() || !s.Completed {
+		return
+	}
+	if err := n.Update(s.Start); err != nil {
+		n.opts.OnError(err)
+	}
+}
+
+// Path is the daily note for day.
+func (n *Note) Path(day time.Time) (string, error) {
+	vault, err := expandHome(n.opts.Vault)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(vault, n.opts.Folder, day.Local().Format(n.opts.DateFormat)+".md"), nil
+}
+
+// Update rewrites the focus section of day's note from history.
+func (n *Note) Update(day time.Time) error {
+	sessions, err := n.opts.List()
+	if err != nil {
+		return err
+	}
+	body, err := n.summary(sessions, day)
+	if err != nil {
+		return err
+	}
+	path, err := n.Path(day)
+	if err != nil {
+		return err
+	}
+	doc, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	merged := mergeSection(string(doc), n.opts.Heading, body)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	// write beside the note and rename, so a crash can't truncate it
+	tmp := path + ".gopomodoro~"
+	if err := os.WriteFile(tmp, []byte(merged), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// summary renders the section body: a totals line and one line per
+// completed pomodoro of day.
+func (n *Note) summary(sessions []history.Session, day time.Time) (string, error) {
+	date := day.Local().Format(time.DateOnly)
+	var lines bytes.Buffer
+	count := 0
+	var focus time.Duration
+	for _, s := range sessions {
+		if s.Phase != core.PhaseWork.String() || !s.Completed || s.Start.Local().Format(time.DateOnly) != date {
+			continue
+		}
+		count++
+		focus += s.Active()
+		if err := n.line.Execute(&lines, NewEntry(s)); err != nil {
+			return "", fmt.Errorf("obsidian template: %w", err)
+		}
+		lines.WriteString("\n")
+	}
+	noun := "pomodoros"
+	if count == 1 {
+		noun = "pomodoro"
+	}
+	return fmt.Sprintf("**%d %s**, %s focus\n%s", count, noun, focus.Round(time.Minute), lines.String()), nil
+}
+
+// headingLevel is the number of leading #s of a Markdown heading line,
+// or 0 if it isn't one.
+func headingLevel(line string) int {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ') {
+		return 0
+	}
+	return level
+}
+
+// mergeSection replaces the content under heading in doc with body,
+// up to the next heading of the same or a higher level. Without the
+// heading, the section is appended. Lines in code fences are never
+// taken for headings.
+func mergeSection(doc, heading, body string) string {
+	body = strings.TrimRight(body, "\n") + "\n"
+	lines := strings.SplitAfter(doc, "\n")
+	level := headingLevel(heading)
+	// heads[i] is the heading level of line i, 0 for text
+	heads := make([]int, len(lines))
+	fenced := false
+	for i, l := range lines {
+		l = strings.TrimRight(l, " \r\n")
+		if strings.HasPrefix(l, "```") || strings.HasPrefix(l, "~~~") {
+			fenced = !fenced
+		} else if !fenced {
+			heads[i] = headingLevel(l)
+		}
+	}
+	start := -1
+	for i, l := range lines {
+		if heads[i] > 0 && strings.TrimRight(l, " \r\n") == heading {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		var b strings.Builder
+		b.WriteString(doc)
+		if doc != "" {
+			if !strings.HasSuffix(doc, "\n") {
+				b.WriteString("\n")
+			}
+			b.WriteString("\n")
+		}
+		b.WriteString(heading + "\n" + body)
+		return b.String()
+	}
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if l := heads[i]; l > 0 && l <= level {
+			end = i
+			break
+		}
+	}
+	var b strings.Builder
+	for _, l := range lines[:start+1] {
+		b.WriteString(l)
+	}
+	if !strings.HasSuffix(lines[start], "\n") {
+		b.WriteString("\n")
+	}
+	b.WriteString(body)
+	if end < len(lines) {
+		b.WriteString("\n")
+		for _, l := range lines[end:] {
+			b.WriteString(l)
+		}
+	}
+	return b.String()
+}
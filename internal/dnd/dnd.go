@@ -0,0 +1,62 @@
+// Package dnd switches the system's Do Not Disturb mode on for work
+// phases and off again for breaks.
+package dnd
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// ErrUnsupported is returned by New when no backend works on this
+// system.
+var ErrUnsupported = errors.New("do not disturb is not supported on this system")
+
+// Switch turns Do Not Disturb on or off.
+type Switch interface {
+	Set(on bool) error
+}
+
+// backend is a Switch that can tell whether it works here.
+type backend interface {
+	Switch
+	Available() bool
+}
+
+// New returns the first backend that works on this system.
+func New() (Switch, error) {
+	for _, b := range backends() {
+		if b.Available() {
+			return b, nil
+		}
+	}
+	return nil, ErrUnsupported
+}
+
+// Commands is a Switch running a command for each direction, for
+// setups without a built-in backend.
+type Commands struct {
+	On, Off []string
+}
+
+func (c Commands) Set(on bool) error {
+	args := c.Off
+	if on {
+		args = c.On
+	}
+	if len(args) == 0 {
+		return nil
+	}
+	return exec.Command(args[0], args[1:]...).Run()
+}
+
+// commands is a backend built from two commands and a probe that
+// succeeds where they work.
+type commands struct {
+	Commands
+	probe []string
+}
+
+func (c commands) Available() bool {
+	_, err := exec.LookPath(c.probe[0])
+	return err == nil && exec.Command(c.probe[0], c.probe[1:]...).Run() == nil
+}
@@ -0,0 +1,40 @@
+package dnd
+
+import (
+	"os/exec"
+	"strings"
+)
+
+// Shortcut names for backends: macOS has no public Focus API, but
+// Shortcuts' "Set Focus" action can be run from the command line.
+const (
+	ShortcutOn  = "GoPomodoro Focus On"
+	ShortcutOff = "GoPomodoro Focus Off"
+)
+
+func backends() []backend { return []backend{shortcuts{}} }
+
+// shortcuts runs the two user-made shortcuts above.
+type shortcuts struct{}
+
+func (shortcuts) Available() bool {
+	out, err := exec.Command("shortcuts", "list").Output()
+	if err != nil {
+		return false
+	}
+	names := strings.Split(string(out), "\n")
+	var on, off bool
+	for _, n := range names {
+		on = on || strings.TrimSpace(n) == ShortcutOn
+		off = off || strings.TrimSpace(n) == ShortcutOff
+	}
+	return on && off
+}
+
+func (shortcuts) Set(on bool) error {
+	name := ShortcutOff
+	if on {
+		name = ShortcutOn
+	}
+	return exec.Command("shortcuts", "run", name).Run()
+}
@@ -0,0 +1,102 @@
+package dnd
+
+import (
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+// backends tries the desktop's own switch (KDE Plasma, GNOME), then the
+// dunst and mako notification daemons.
+func backends() []backend {
+	desktop := strings.ToUpper(os.Getenv("XDG_CURRENT_DESKTOP"))
+	var bs []backend
+	if strings.Contains(desktop, "KDE") {
+		bs = append(bs, &inhibitor{})
+	}
+	if strings.Contains(desktop, "GNOME") || strings.Contains(desktop, "UNITY") {
+		bs = append(bs, commands{
+			Commands: Commands{
+				On:  []string{"gsettings", "set", "org.gnome.desktop.notifications", "show-banners", "false"},
+				Off: []string{"gsettings", "set", "org.gnome.desktop.notifications", "show-banners", "true"},
+			},
+			probe: []string{"gsettings", "get", "org.gnome.desktop.notifications", "show-banners"},
+		})
+	}
+	return append(bs,
+		commands{
+			Commands: Commands{
+				On:  []string{"dunstctl", "set-paused", "true"},
+				Off: []string{"dunstctl", "set-paused", "false"},
+			},
+			probe: []string{"dunstctl", "is-paused"},
+		},
+		commands{
+			// needs a [mode=do-not-disturb] section with invisible=1 in
+			// mako's config
+			Commands: Commands{
+				On:  []string{"makoctl", "mode", "-a", "do-not-disturb"},
+				Off: []string{"makoctl", "mode", "-r", "do-not-disturb"},
+			},
+			probe: []string{"makoctl", "mode"},
+		},
+	)
+}
+
+// inhibitor uses the Inhibit call KDE Plasma's notification server
+// adds. Plasma drops an inhibition when its caller disconnects, so the
+// connection is kept open while it is on.
+type inhibitor struct {
+	mu     sync.Mutex
+	conn   *dbus.Conn
+	cookie uint32
+}
+
+const (
+	notifyDest = "org.freedesktop.Notifications"
+	notifyPath = dbus.ObjectPath("/org/freedesktop/Notifications")
+)
+
+func (i *inhibitor) Available() bool {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return false
+	}
+	defer conn.Close()
+	var inhibited bool
+	v, err := conn.Object(notifyDest, notifyPath).GetProperty(notifyDest + ".Inhibited")
+	return err == nil && v.Store(&inhibited) == nil
+}
+
+func (i *inhibitor) Set(on bool) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !on {
+		if i.conn == nil {
+			return nil
+		}
+		defer func() {
+			i.conn.Close()
+			i.conn = nil
+		}()
+		return i.conn.Object(notifyDest, notifyPath).Call(notifyDest+".UnInhibit", 0, i.cookie).Err
+	}
+	if i.conn != nil {
+		return nil
+	}
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return err
+	}
+	hints := map[string]dbus.Variant{"x-kde-pid": dbus.MakeVariant(strconv.Itoa(os.Getpid()))}
+	if err := conn.Object(notifyDest, notifyPath).Call(notifyDest+".Inhibit", 0,
+		"gopomodoro", "Focusing", hints).Store(&i.cookie); err != nil {
+		conn.Close()
+		return err
+	}
+	i.conn = conn
+	return nil
+}
@@ -0,0 +1,5 @@
+//go:build !linux && !darwin && !windows
+
+package dnd
+
+func backends() []backend { return nil }
@@ -0,0 +1,15 @@
+package dnd
+
+// pushKey holds the "get notifications from apps" switch. Focus Assist
+// has no public API, so this silences toasts instead.
+const pushKey = `HKCU\Software\Microsoft\Windows\CurrentVersion\PushNotifications`
+
+func backends() []backend {
+	return []backend{commands{
+		Commands: Commands{
+			On:  []string{"reg", "add", pushKey, "/v", "ToastEnabled", "/t", "REG_DWORD", "/d", "0", "/f"},
+			Off: []string{"reg", "add", pushKey, "/v", "ToastEnabled", "/t", "REG_DWORD", "/d", "1", "/f"},
+		},
+		probe: []string{"reg", "query", pushKey},
+	}}
+}
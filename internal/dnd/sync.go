@@ -0,0 +1,42 @@
+package dnd
+
+import "github.com/ezchuang/GoPomodoro/internal/core"
+
+// Sync keeps a Switch on while a work phase runs and off during breaks,
+// pauses and after a stop. Subscribe its Handle method to an engine.
+type Sync struct {
+	sw      Switch
+	onError func(error)
+	on      bool
+}
+
+// NewSync creates a Sync driving sw.
+func NewSync(sw Switch, onError func(error)) *Sync {
+	if onError == nil {
+		onError = func(error) {}
+	}
+	return &Sync{sw: sw, onError: onError}
+}
+
+// Handle consumes one engine event.
+func (s *Sync) Handle(ev core.Event) {
+	st := ev.State
+	working := st.Phase == core.PhaseWork && !st.Paused && !st.Overtime
+	if ev.Kind == core.EventStop {
+		working = false
+	}
+	s.set(working)
+}
+
+// Close turns Do Not Disturb off if Sync turned it on.
+func (s *Sync) Close() { s.set(false) }
+
+func (s *Sync) set(on bool) {
+	if on == s.on {
+		return
+	}
+	s.on = on
+	if err := s.sw.Set(on); err != nil {
+		s.onError(err)
+	}
+}
@@ -0,0 +1,47 @@
+package dnd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type recordSwitch []bool
+
+func (r *recordSwitch) Set(on bool) error {
+	*r = append(*r, on)
+	return nil
+}
+
+func TestSync(t *testing.T) {
+	var sw recordSwitch
+	s := NewSync(&sw, nil)
+	started := time.Now()
+	work := core.State{Phase: core.PhaseWork, StartedAt: started}
+	paused := work
+	paused.Paused = true
+	brk := core.State{Phase: core.PhaseShortBreak, StartedAt: started}
+
+	for _, ev := range []core.Event{
+		{Kind: core.EventStart, State: work},
+		{Kind: core.EventWarning, State: work},
+		{Kind: core.EventPause, State: paused},
+		{Kind: core.EventResume, State: work},
+		{Kind: core.EventAdvance, State: brk},
+		{Kind: core.EventAdvance, State: work},
+		{Kind: core.EventStop, State: work},
+	} {
+		s.Handle(ev)
+	}
+	s.Close()
+	want := []bool{true, false, true, false, true, false}
+	if len(sw) != len(want) {
+		t.Fatalf("got %v, want %v", sw, want)
+	}
+	for i := range want {
+		if sw[i] != want[i] {
+			t.Fatalf("got %v, want %v", sw, want)
+		}
+	}
+}
@@ -0,0 +1,113 @@
+// Package enforce makes breaks hard to skip: it locks the screen when a
+// break starts, or tells the TUI to cover itself with an overlay that
+// can only be dismissed after a while.
+package enforce
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Mode is how breaks are enforced.
+type Mode int
+
+const (
+	Off Mode = iota
+	// Overlay covers the TUI for the start of the break.
+	Overlay
+	// Lock locks the screen.
+	Lock
+)
+
+func (m Mode) String() string {
+	switch m {
+	case Overlay:
+		return "overlay"
+	case Lock:
+		return "lock"
+	}
+	return "off"
+}
+
+// ParseMode is the inverse of Mode.String; "" is Off.
+func ParseMode(s string) (Mode, error) {
+	switch s {
+	case "", "off":
+		return Off, nil
+	case "overlay":
+		return Overlay, nil
+	case "lock":
+		return Lock, nil
+	}
+	return Off, fmt.Errorf("enforce: unknown mode %q (want overlay or lock)", s)
+}
+
+// Applies reports whether a break starting in st is enforced; with
+// longOnly only long breaks are.
+func Applies(st core.State, longOnly bool) bool {
+	if st.Idle() || st.Phase == core.PhaseWork {
+		return false
+	}
+	return !longOnly || st.Phase == core.PhaseLongBreak
+}
+
+// ErrUnsupported is returned by NewLocker when the screen can't be
+// locked on this system.
+var ErrUnsupported = errors.New("locking the screen is not supported on this system")
+
+// Locker locks the screen.
+type Locker interface {
+	Lock() error
+}
+
+// NewLocker returns the first built-in way to lock the screen that
+// works on this system.
+func NewLocker() (Locker, error) {
+	for _, c := range lockCommands() {
+		if _, err := exec.LookPath(c[0]); err == nil {
+			return c, nil
+		}
+	}
+	return nil, ErrUnsupported
+}
+
+// Command is a Locker running a command, for setups without a built-in
+// one.
+type Command []string
+
+func (c Command) Lock() error {
+	if len(c) == 0 {
+		return nil
+	}
+	return exec.Command(c[0], c[1:]...).Run()
+}
+
+// Enforcer locks the screen when a break starts. Subscribe its Handle
+// method to an engine.
+type Enforcer struct {
+	locker   Locker
+	longOnly bool
+	onError  func(error)
+}
+
+// NewEnforcer creates an Enforcer using locker; with longOnly it leaves
+// short breaks alone.
+func NewEnforcer(locker Locker, longOnly bool, onError func(error)) *Enforcer {
+	if onError == nil {
+		onError = func(error) {}
+	}
+	return &Enforcer{locker: locker, longOnly: longOnly, onError: onError}
+}
+
+// Handle consumes one engine event.
+func (e *Enforcer) Handle(ev core.Event) {
+	if ev.Kind != core.EventAdvance && ev.Kind != core.EventSkip || !Applies(ev.State, e.longOnly) {
+		return
+	}
+	if err := e.locker.Lock(); err != nil {
+		e.onError(err)
+	}
+}
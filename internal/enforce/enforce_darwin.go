@@ -0,0 +1,7 @@
+package enforce
+
+// lockCommands puts the display to sleep, which locks the screen when a
+// password is required after sleep, as it is by default.
+func lockCommands() []Command {
+	return []Command{{"pmset", "displaysleepnow"}}
+}
@@ -0,0 +1,10 @@
+package enforce
+
+// lockCommands asks systemd-logind to lock the session, which every
+// desktop honors, falling back to the XDG screensaver script.
+func lockCommands() []Command {
+	return []Command{
+		{"loginctl", "lock-session"},
+		{"xdg-screensaver", "lock"},
+	}
+}
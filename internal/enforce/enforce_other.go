@@ -0,0 +1,5 @@
+//go:build !linux && !darwin && !windows
+
+package enforce
+
+func lockCommands() []Command { return nil }
@@ -0,0 +1,54 @@
+package enforce
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type countLocker int
+
+func (c *countLocker) Lock() error {
+	*c++
+	return nil
+}
+
+func TestEnforcer(t *testing.T) {
+	started := time.Now()
+	work := core.State{Phase: core.PhaseWork, StartedAt: started}
+	short := core.State{Phase: core.PhaseShortBreak, StartedAt: started}
+	long := core.State{Phase: core.PhaseLongBreak, StartedAt: started}
+	events := []core.Event{
+		{Kind: core.EventStart, State: work},
+		{Kind: core.EventAdvance, State: short},
+		{Kind: core.EventAdvance, State: work},
+		{Kind: core.EventSkip, State: short},
+		{Kind: core.EventAdvance, State: long},
+	}
+
+	for _, tc := range []struct {
+		longOnly bool
+		want     countLocker
+	}{{false, 3}, {true, 1}} {
+		var locks countLocker
+		e := NewEnforcer(&locks, tc.longOnly, nil)
+		for _, ev := range events {
+			e.Handle(ev)
+		}
+		if locks != tc.want {
+			t.Errorf("longOnly=%v: locked %d times, want %d", tc.longOnly, locks, tc.want)
+		}
+	}
+}
+
+func TestParseMode(t *testing.T) {
+	for _, m := range []Mode{Off, Overlay, Lock} {
+		if got, err := ParseMode(m.String()); err != nil || got != m {
+			t.Errorf("ParseMode(%q) = %v, %v", m, got, err)
+		}
+	}
+	if _, err := ParseMode("nag"); err == nil {
+		t.Error("ParseMode(nag) succeeded")
+	}
+}
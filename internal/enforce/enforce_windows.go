@@ -0,0 +1,5 @@
+package enforce
+
+func lockCommands() []Command {
+	return []Command{{"rundll32.exe", "user32.dll,LockWorkStation"}}
+}
@@ -0,0 +1,272 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// keyPrefix starts the key line of a key file.
+const keyPrefix = "GOPOMODORO-KEY-1-"
+
+// sealedPrefix starts an encrypted line of a history file; plain lines
+// are JSON and start with "{".
+const sealedPrefix = "enc1:"
+
+// ErrEncrypted is returned for an encrypted history read without its
+// key.
+var ErrEncrypted = errors.New("history is encrypted and no key file was found")
+
+// Key encrypts the history at rest with AES-256-GCM, line by line, so
+// sessions are still appended rather than the file rewritten. A rotated
+// key keeps the keys it replaced, to decrypt what they encrypted on
+// other machines.
+type Key struct {
+	aead cipher.AEAD
+	raw  []byte
+	old  []*Key
+}
+
+// GenerateKey returns a new random key.
+func GenerateKey() (*Key, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, err
+	}
+	return newKey(raw)
+}
+
+func newKey(raw []byte) (*Key, error) {
+	block, err := aes.NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &Key{aead: aead, raw: raw}, nil
+}
+
+// Rotate returns a new random key encrypting in place of k, which it
+// keeps, with the keys k kept, for decrypting.
+func (k *Key) Rotate() (*Key, error) {
+	next, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+	next.old = append([]*Key{{aead: k.aead, raw: k.raw}}, k.old...)
+	return next, nil
+}
+
+// ID names the key by its fingerprint, so two copies can be told apart
+// from two keys without showing either.
+func (k *Key) ID() string {
+	sum := sha256.Sum256(k.raw)
+	return hex.EncodeToString(sum[:6])
+}
+
+// LoadKey reads the key file at path: the first key line encrypts and
+// any later ones only decrypt. A missing file gives a nil key and no
+// error: the history is then kept in the clear.
+func LoadKey(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var key *Key
+	sc := bufio.NewScanner(bytes.NewReader(data))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		enc, ok := strings.CutPrefix(line, keyPrefix)
+		if !ok {
+			continue
+		}
+		raw, err := base64.RawURLEncoding.DecodeString(enc)
+		if err != nil || len(raw) != 32 {
+			return nil, fmt.Errorf("key file %s: malformed key", path)
+		}
+		k, err := newKey(raw)
+		if err != nil {
+			return nil, err
+		}
+		if key == nil {
+			key = k
+		} else {
+			key.old = append(key.old, k)
+		}
+	}
+	if key == nil {
+		return nil, fmt.Errorf("key file %s: no %s line", path, strings.TrimSuffix(keyPrefix, "-"))
+	}
+	return key, nil
+}
+
+// Save writes the key to a new file at path, readable by the user
+// only. It won't overwrite an existing file, which may hold the only
+// copy of another key.
+func (k *Key) Save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(f, "# GoPomodoro history key, created %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(f, "# id: %s\n", k.ID())
+	fmt.Fprintf(f, "# Without this file the encrypted history can't be read: back it up.\n")
+	if len(k.old) > 0 {
+		fmt.Fprintf(f, "# The keys after the first are older ones, kept to decrypt what they encrypted.\n")
+	}
+	for _, key := range append([]*Key{k}, k.old...) {
+		if _, err := fmt.Fprintf(f, "%s%s\n", keyPrefix, base64.RawURLEncoding.EncodeToString(key.raw)); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
+
+// seal encrypts one JSON line; a nil key leaves it as it is.
+func (k *Key) seal(line []byte) []byte {
+	if k == nil {
+		return line
+	}
+	nonce := make([]byte, k.aead.NonceSize())
+	_, _ = rand.Read(nonce)
+	sealed := k.aead.Seal(nonce, nonce, line, nil)
+	return append([]byte(sealedPrefix), base64.RawStdEncoding.AppendEncode(nil, sealed)...)
+}
+
+// open decrypts one line sealed by seal; plain lines pass through, so a
+// history encrypted partway reads whole.
+func (k *Key) open(line []byte) ([]byte, error) {
+	enc, ok := bytes.CutPrefix(line, []byte(sealedPrefix))
+	if !ok {
+		return line, nil
+	}
+	if k == nil {
+		return nil, ErrEncrypted
+	}
+	sealed, err := base64.RawStdEncoding.AppendDecode(nil, enc)
+	n := k.aead.NonceSize()
+	if err != nil || len(sealed) < n {
+		return nil, errors.New("malformed encrypted line")
+	}
+	for _, key := range append([]*Key{k}, k.old...) {
+		if plain, err := key.aead.Open(nil, sealed[:n], sealed[n:], nil); err == nil {
+			return plain, nil
+		}
+	}
+	return nil, errors.New("can't decrypt: wrong key or damaged line")
+}
+
+// Rekey rewrites the history and the files kept beside it, the audit
+// journal, sync marks and phase journals, with key in place of the
+// store's own; a nil key decrypts them. The store uses key from then
+// on.
+func (s *Store) Rekey(key *Key) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	paths := []string{s.path, s.AuditPath(), s.SyncPath()}
+	journals, err := filepath.Glob(s.JournalPath("*"))
+	if err != nil {
+		return err
+	}
+	// every file is read before any is written, so a wrong key fails
+	// with nothing changed
+	contents := make([][][]byte, 0, len(paths))
+	for _, path := range append(paths, journals...) {
+		lines, err := s.rawLines(path)
+		if err != nil {
+			return err
+		}
+		contents = append(contents, lines)
+	}
+	// and every file is written beside itself before any is renamed into
+	// place, so a failed write leaves them all under the old key
+	var written []string
+	defer func() {
+		for _, path := range written {
+			os.Remove(path + ".tmp")
+		}
+	}()
+	for i, path := range append(paths, journals...) {
+		if contents[i] == nil {
+			continue
+		}
+		var buf bytes.Buffer
+		for _, line := range contents[i] {
+			buf.Write(key.seal(line))
+			buf.WriteByte('\n')
+		}
+		if err := os.WriteFile(path+".tmp", buf.Bytes(), 0o600); err != nil {
+			return err
+		}
+		written = append(written, path)
+	}
+	for i, path := range written {
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return fmt.Errorf("%w (%d of %d files rewritten)", err, i, len(written))
+		}
+	}
+	s.key = key
+	return nil
+}
+
+// rawLines returns the decrypted lines of the file at path; nil for a
+// missing file.
+func (s *Store) rawLines(path string) ([][]byte, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	lines := [][]byte{}
+	for n, line := 1, []byte(nil); len(data) > 0; n++ {
+		line, data, _ = bytes.Cut(data, []byte("\n"))
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		plain, err := s.key.open(line)
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		lines = append(lines, plain)
+	}
+	return lines, nil
+}
+
+// Encrypted reports whether any line of the history is encrypted.
+func (s *Store) Encrypted() (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return false, nil
+	}
+	for line := range bytes.Lines(data) {
+		if bytes.HasPrefix(line, []byte(sealedPrefix)) {
+			return true, nil
+		}
+	}
+	return false, err
+}
@@ -0,0 +1,123 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Status is "completed", "abandoned" or "incomplete" (skipped, or
+// stopped by an older version that didn't tell).
+func (s Session) Status() string {
+	switch {
+	case s.Completed:
+		return "completed"
+	case s.Abandoned:
+		return "abandoned"
+	default:
+		return "incomplete"
+	}
+}
+
+// exportRecord is the flattened session written by WriteJSON.
+type exportRecord struct {
+	ID              string         `json:"id"`
+	Phase           string         `json:"phase"`
+	Name            string         `json:"name,omitempty"`
+	Task            *Task          `json:"task,omitempty"`
+	Start           time.Time      `json:"start"`
+	End             time.Time      `json:"end"`
+	Status          string         `json:"status"`
+	ActiveMinutes   float64        `json:"active_minutes"`
+	PausedMinutes   float64        `json:"paused_minutes"`
+	OvertimeMinutes float64        `json:"overtime_minutes"`
+	Interruptions   []Interruption `json:"interruptions"`
+}
+
+func minutes(d time.Duration) float64 {
+	return float64(d.Round(time.Second)) / float64(time.Minute)
+}
+
+// csvHeader lists the columns written by WriteCSV.
+var csvHeader = []string{
+	"id", "phase", "name", "task", "start", "end", "status",
+	"active_minutes", "paused_minutes", "overtime_minutes",
+	"internal_interruptions", "external_interruptions",
+}
+
+// WriteCSV writes sessions as CSV with a header row. Times are RFC 3339.
+func WriteCSV(w io.Writer, sessions []Session) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvHeader); err != nil {
+		return err
+	}
+	num := func(f float64) string { return strconv.FormatFloat(f, 'f', 2, 64) }
+	for _, s := range sessions {
+		var internal, external int
+		for _, it := range s.Interruptions {
+			if it.Kind == "external" {
+				external++
+			} else {
+				internal++
+			}
+		}
+		task := ""
+		if s.Task != nil {
+			task = s.Task.Title
+		}
+		err := cw.Write([]string{
+			s.ID, s.Phase, s.Name, task,
+			s.Start.Format(time.RFC3339), s.End.Format(time.RFC3339), s.Status(),
+			num(minutes(s.Active())), num(minutes(s.Paused())), num(minutes(s.Overtime)),
+			strconv.Itoa(internal), strconv.Itoa(external),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSON writes sessions as an indented JSON array.
+func WriteJSON(w io.Writer, sessions []Session) error {
+	recs := make([]exportRecord, 0, len(sessions))
+	for _, s := range sessions {
+		its := s.Interruptions
+		if its == nil {
+			its = []Interruption{}
+		}
+		recs = append(recs, exportRecord{
+			ID:              s.ID,
+			Phase:           s.Phase,
+			Name:            s.Name,
+			Task:            s.Task,
+			Start:           s.Start,
+			End:             s.End,
+			Status:          s.Status(),
+			ActiveMinutes:   minutes(s.Active()),
+			PausedMinutes:   minutes(s.Paused()),
+			OvertimeMinutes: minutes(s.Overtime),
+			Interruptions:   its,
+		})
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(recs)
+}
+
+// Export writes sessions in format, "csv", "json" or "ics".
+func Export(w io.Writer, format string, sessions []Session) error {
+	switch format {
+	case "csv":
+		return WriteCSV(w, sessions)
+	case "json":
+		return WriteJSON(w, sessions)
+	case "ics":
+		return WriteICS(w, sessions)
+	}
+	return fmt.Errorf("unknown export format %q (want csv, json or ics)", format)
+}
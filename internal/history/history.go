@@ -0,0 +1,331 @@
+// Package history persists finished phases as session records in a
+// JSON Lines file, appended to as they finish, and records them from
+// engine events.
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Pause is one paused interval inside a session.
+type Pause struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// Duration is the length of the pause.
+func (p Pause) Duration() time.Duration { return p.End.Sub(p.Start) }
+
+// Interruption is one logged interruption of a work session.
+type Interruption struct {
+	At   time.Time `json:"at"`
+	Kind string    `json:"kind"` // "internal" or "external"
+	Note string    `json:"note,omitempty"`
+}
+
+// Extension records a phase being lengthened (positive By) or
+// shortened while it ran.
+type Extension struct {
+	At time.Time     `json:"at"`
+	By time.Duration `json:"by"`
+}
+
+// Task is the to-do item a work session was spent on.
+type Task struct {
+	Source string `json:"source,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Title  string `json:"title"`
+	// Estimate is the pomodoros the task was expected to take when the
+	// session ran.
+	Estimate int `json:"estimate,omitempty"`
+}
+
+// newTask converts an engine task; a zero one yields nil.
+func newTask(t core.Task) *Task {
+	if t.IsZero() {
+		return nil
+	}
+	return &Task{Source: t.Source, ID: t.ID, Title: t.Title, Estimate: t.Estimate}
+}
+
+// Session is one phase (work or break) as it actually happened.
+type Session struct {
+	ID        string    `json:"id"`
+	Phase     string    `json:"phase"`
+	Name      string    `json:"name,omitempty"` // custom cycle step name
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Completed bool      `json:"completed"`
+	// Abandoned is set when the phase was stopped or restarted before
+	// its deadline; skipped phases are neither completed nor abandoned.
+	Abandoned bool `json:"abandoned,omitempty"`
+	// AbandonReason is why an abandoned phase was stopped, if the user
+	// said.
+	AbandonReason string  `json:"abandon_reason,omitempty"`
+	Pauses        []Pause `json:"pauses,omitempty"`
+
+	Interruptions []Interruption `json:"interruptions,omitempty"`
+	// Overtime is the extra focus time after the work deadline, included
+	// in End, when overtime mode kept the phase running.
+	Overtime time.Duration `json:"overtime,omitempty"`
+
+	Extensions []Extension `json:"extensions,omitempty"`
+	// Task is the task attached while a work session ran.
+	Task *Task `json:"task,omitempty"`
+	// Tags are the work session's tags, without '#'.
+	Tags []string `json:"tags,omitempty"`
+}
+
+// Paused is the total time spent paused.
+func (s Session) Paused() time.Duration {
+	var d time.Duration
+	for _, p := range s.Pauses {
+		d += p.Duration()
+	}
+	return d
+}
+
+// Active is the wall time of the session minus pauses.
+func (s Session) Active() time.Duration {
+	return max(s.End.Sub(s.Start)-s.Paused(), 0)
+}
+
+// DefaultPath returns $XDG_DATA_HOME/gopomodoro/history.jsonl, falling
+// back to ~/.local/share on Unix and the user config dir elsewhere.
+func DefaultPath() (string, error) {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		switch runtime.GOOS {
+		case "windows", "darwin", "ios", "plan9":
+			d, err := os.UserConfigDir()
+			if err != nil {
+				return "", err
+			}
+			dir = d
+		default:
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			dir = filepath.Join(home, ".local", "share")
+		}
+	}
+	return filepath.Join(dir, "gopomodoro", "history.jsonl"), nil
+}
+
+// Store is a JSON Lines file of sessions, safe for concurrent use
+// within one process.
+type Store struct {
+	path string
+	key  *Key
+	mu   sync.Mutex
+}
+
+// Open returns a Store at path, creating its directory if needed. The
+// directory and files are made readable by the user only, as task
+// names may be confidential.
+func Open(path string) (*Store, error) {
+	return OpenEncrypted(path, nil)
+}
+
+// OpenEncrypted is Open for a history encrypted with key; lines are
+// written encrypted from now on, and those written in the clear before
+// still read. A nil key keeps the history in the clear.
+func OpenEncrypted(path string, key *Key) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, err
+	}
+	return &Store{path: path, key: key}, nil
+}
+
+// Path returns the file backing the store.
+func (s *Store) Path() string { return s.path }
+
+// Append writes one session to the end of the file.
+func (s *Store) Append(sess Session) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return appendLine(s.path, sess, s.key)
+}
+
+// appendLine writes v as a JSON line, encrypted with key if set, to the
+// end of the file at path.
+func appendLine(path string, v any, key *Key) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(key.seal(line), '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// List returns every stored session in file order. A missing file is
+// an empty history.
+func (s *Store) List() ([]Session, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listLocked()
+}
+
+// ErrNotFound is returned for a session ID the store doesn't hold.
+var ErrNotFound = errors.New("no such session")
+
+// Change is an entry of the audit journal kept beside the history:
+// one session edited or deleted.
+type Change struct {
+	At     time.Time `json:"at"`
+	Action string    `json:"action"` // "edit" or "delete"
+	Before Session   `json:"before"`
+	After  *Session  `json:"after,omitempty"` // nil for a deletion
+}
+
+// AuditPath returns the audit journal's file, e.g. history.audit.jsonl
+// beside history.jsonl.
+func (s *Store) AuditPath() string {
+	ext := filepath.Ext(s.path)
+	return strings.TrimSuffix(s.path, ext) + ".audit" + ext
+}
+
+// JournalPath returns the file journaling the phase in progress of the
+// named command, e.g. history.tui.journal.jsonl beside history.jsonl;
+// see Recorder.Recover.
+func (s *Store) JournalPath(name string) string {
+	ext := filepath.Ext(s.path)
+	return strings.TrimSuffix(s.path, ext) + "." + name + ".journal" + ext
+}
+
+// Update replaces the stored session with sess.ID by sess, journaling
+// the change.
+func (s *Store) Update(sess Session) error {
+	return s.rewrite(sess.ID, func(sessions []Session, i int) ([]Session, Change) {
+		c := Change{Action: "edit", Before: sessions[i], After: &sess}
+		sessions[i] = sess
+		return sessions, c
+	})
+}
+
+// Delete removes the session with the given ID, journaling the change.
+func (s *Store) Delete(id string) error {
+	return s.rewrite(id, func(sessions []Session, i int) ([]Session, Change) {
+		c := Change{Action: "delete", Before: sessions[i]}
+		return slices.Delete(sessions, i, i+1), c
+	})
+}
+
+// Changes returns the audit journal, oldest first.
+func (s *Store) Changes() ([]Change, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return readLines[Change](s.AuditPath(), s.key)
+}
+
+// rewrite applies edit to the session with id and rewrites the file
+// beside itself, renaming it into place so a crash can't truncate it.
+// The change edit reports is then appended to the audit journal.
+func (s *Store) rewrite(id string, edit func(sessions []Session, i int) ([]Session, Change)) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sessions, err := s.listLocked()
+	if err != nil {
+		return err
+	}
+	i := slices.IndexFunc(sessions, func(sess Session) bool { return sess.ID == id })
+	if id == "" || i < 0 {
+		return fmt.Errorf("%w: %q", ErrNotFound, id)
+	}
+	sessions, change := edit(sessions, i)
+	if err := s.writeLocked(sessions); err != nil {
+		return err
+	}
+	change.At = time.Now()
+	return appendLine(s.AuditPath(), change, s.key)
+}
+
+// writeLocked replaces the file with sessions.
+func (s *Store) writeLocked(sessions []Session) error {
+	var buf bytes.Buffer
+	for _, sess := range sessions {
+		line, err := json.Marshal(sess)
+		if err != nil {
+			return err
+		}
+		buf.Write(append(s.key.seal(line), '\n'))
+	}
+	return writeFile(s.path, buf.Bytes())
+}
+
+// writeFile replaces the file at path with data, writing it beside the
+// file and renaming it into place so a crash can't truncate it.
+func writeFile(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (s *Store) listLocked() ([]Session, error) {
+	return readLines[Session](s.path, s.key)
+}
+
+// readLines decodes the JSON Lines file at path, decrypting lines with
+// key. A missing file has no lines.
+func readLines[T any](path string, key *Key) ([]T, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []T
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		line, err := key.open(sc.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		var v T
+		if err := json.Unmarshal(line, &v); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		out = append(out, v)
+	}
+	return out, sc.Err()
+}
+
+func newID() string {
+	var b [8]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
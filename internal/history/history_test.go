@@ -0,0 +1,585 @@
+package history
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestStore_AppendList(t *testing.T) {
+	st, err := Open(filepath.Join(t.TempDir(), "sub", "history.jsonl"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	if got, err := st.List(); err != nil || len(got) != 0 {
+		t.Fatalf("empty store: %v %v", got, err)
+	}
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	want := Session{ID: "a", Phase: "WORK", Start: base, End: base.Add(25 * time.Minute), Completed: true}
+	if err := st.Append(want); err != nil {
+		t.Fatalf("append: %v", err)
+	}
+	got, err := st.List()
+	if err != nil || len(got) != 1 {
+		t.Fatalf("list: %v %v", got, err)
+	}
+	if got[0].ID != want.ID || !got[0].End.Equal(want.End) || !got[0].Completed {
+		t.Fatalf("round trip mismatch: %+v", got[0])
+	}
+}
+
+func TestStore_UpdateDelete(t *testing.T) {
+	st, err := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	for _, id := range []string{"a", "b", "c"} {
+		if err := st.Append(Session{ID: id, Phase: "WORK", Start: base, End: base.Add(25 * time.Minute)}); err != nil {
+			t.Fatalf("append: %v", err)
+		}
+	}
+	if err := st.Update(Session{ID: "b", Phase: "WORK", Start: base, End: base.Add(20 * time.Minute), Completed: true}); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if err := st.Delete("a"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if err := st.Delete("zz"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("delete unknown: %v", err)
+	}
+	got, err := st.List()
+	if err != nil || len(got) != 2 {
+		t.Fatalf("list: %v %v", got, err)
+	}
+	if got[0].ID != "b" || !got[0].Completed || got[0].Active() != 20*time.Minute || got[1].ID != "c" {
+		t.Fatalf("after edits: %+v", got)
+	}
+	changes, err := st.Changes()
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("audit journal: %+v %v", changes, err)
+	}
+	if c := changes[0]; c.Action != "edit" || c.Before.Completed || c.After == nil || !c.After.Completed {
+		t.Fatalf("edit entry: %+v", c)
+	}
+	if c := changes[1]; c.Action != "delete" || c.Before.ID != "a" || c.After != nil {
+		t.Fatalf("delete entry: %+v", c)
+	}
+	if filepath.Base(st.AuditPath()) != "history.audit.jsonl" {
+		t.Fatalf("audit path %s", st.AuditPath())
+	}
+}
+
+func TestStore_Merge(t *testing.T) {
+	dir := t.TempDir()
+	laptop, _ := Open(filepath.Join(dir, "laptop", "history.jsonl"))
+	desktop, _ := Open(filepath.Join(dir, "desktop", "history.jsonl"))
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	session := func(id string, start time.Duration) Session {
+		return Session{ID: id, Phase: "WORK", Start: base.Add(start), End: base.Add(start + 25*time.Minute), Completed: true}
+	}
+	_ = laptop.Append(session("a", 0))
+	_ = laptop.Append(session("c", 2*time.Hour))
+	_ = desktop.Append(session("b", time.Hour))
+	_ = desktop.Append(session("d", 3*time.Hour))
+	if err := desktop.Delete("d"); err != nil {
+		t.Fatal(err)
+	}
+
+	// each machine merges the other's records, in either order
+	sync := func(a, b *Store) {
+		t.Helper()
+		ra, err := a.Records()
+		if err != nil {
+			t.Fatal(err)
+		}
+		rb, err := b.Records()
+		if err != nil {
+			t.Fatal(err)
+		}
+		merged := MergeRecords(ra, rb)
+		if _, err := a.Merge(merged); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := b.Merge(merged); err != nil {
+			t.Fatal(err)
+		}
+	}
+	ids := func(st *Store) string {
+		sessions, _ := st.List()
+		var out []string
+		for _, s := range sessions {
+			out = append(out, s.ID)
+		}
+		return strings.Join(out, ",")
+	}
+	sync(laptop, desktop)
+	if ids(laptop) != "a,b,c" || ids(desktop) != "a,b,c" {
+		t.Fatalf("after sync: laptop %s, desktop %s, want a,b,c in start order", ids(laptop), ids(desktop))
+	}
+
+	// an edit on one machine and a deletion on the other both carry over
+	edited := session("a", 0)
+	edited.Completed = false
+	if err := desktop.Update(edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := laptop.Delete("c"); err != nil {
+		t.Fatal(err)
+	}
+	records, _ := desktop.Records()
+	res, err := laptop.Merge(records)
+	if err != nil || res != (MergeResult{Updated: 1}) {
+		t.Fatalf("merge: %+v, %v", res, err)
+	}
+	sync(laptop, desktop)
+	got, _ := laptop.List()
+	if ids(laptop) != "a,b" || ids(desktop) != "a,b" || got[0].Completed {
+		t.Fatalf("after edits: laptop %+v, desktop %s", got, ids(desktop))
+	}
+	// merging again changes nothing
+	records, _ = laptop.Records()
+	if res, err := desktop.Merge(records); err != nil || res.Changed() {
+		t.Fatalf("second merge: %+v, %v", res, err)
+	}
+	if filepath.Base(laptop.SyncPath()) != "history.sync.jsonl" {
+		t.Fatalf("sync path %s", laptop.SyncPath())
+	}
+}
+
+func TestRecords_RoundTrip(t *testing.T) {
+	at := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	s := Session{ID: "a", Phase: "WORK", Start: at, End: at}
+	in := []Record{{ID: "a", Updated: at, Session: &s}, {ID: "b", Updated: at}}
+	st, _ := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	var buf strings.Builder
+	if err := st.WriteRecords(&buf, in); err != nil {
+		t.Fatal(err)
+	}
+	out, err := st.ReadRecords(strings.NewReader(buf.String()))
+	if err != nil || len(out) != 2 || out[0].Session == nil || out[1].Session != nil {
+		t.Fatalf("round trip %+v, %v", out, err)
+	}
+	// on a tie the deletion wins, whichever set it comes from
+	for _, sets := range [][][]Record{{in[:1], {{ID: "a", Updated: at}}}, {{{ID: "a", Updated: at}}, in[:1]}} {
+		if got := MergeRecords(sets...); len(got) != 1 || got[0].Session != nil {
+			t.Fatalf("tie merged to %+v", got)
+		}
+	}
+}
+
+func TestStore_Encrypted(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "history.jsonl")
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	plain, _ := Open(path)
+	_ = plain.Append(Session{ID: "a", Phase: "WORK", Name: "Acme merger memo", Start: base, End: base.Add(25 * time.Minute)})
+
+	keyPath := filepath.Join(dir, "keys", "history.key")
+	if k, err := LoadKey(keyPath); k != nil || err != nil {
+		t.Fatalf("missing key file: %v, %v", k, err)
+	}
+	key, _ := GenerateKey()
+	if err := key.Save(keyPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := key.Save(keyPath); err == nil {
+		t.Fatal("overwrote a key file")
+	}
+	if fi, _ := os.Stat(keyPath); fi.Mode().Perm() != 0o600 {
+		t.Errorf("key file mode %v", fi.Mode())
+	}
+	loaded, err := LoadKey(keyPath)
+	if err != nil || loaded.ID() != key.ID() {
+		t.Fatalf("loaded key %v, %v", loaded, err)
+	}
+
+	// a history encrypted partway still reads whole
+	st, _ := OpenEncrypted(path, loaded)
+	_ = st.Append(Session{ID: "b", Phase: "WORK", Name: "Acme due diligence", Start: base.Add(time.Hour), End: base.Add(85 * time.Minute)})
+	if got, err := st.List(); err != nil || len(got) != 2 || got[1].Name != "Acme due diligence" {
+		t.Fatalf("mixed history: %+v, %v", got, err)
+	}
+	if err := st.Rekey(loaded); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(path)
+	if strings.Contains(string(data), "Acme") {
+		t.Fatalf("task names in the clear:\n%s", data)
+	}
+	if enc, err := st.Encrypted(); !enc || err != nil {
+		t.Fatalf("encrypted %v, %v", enc, err)
+	}
+	if err := st.Delete("a"); err != nil {
+		t.Fatal(err)
+	}
+	if changes, err := st.Changes(); err != nil || len(changes) != 1 {
+		t.Fatalf("audit journal: %+v, %v", changes, err)
+	}
+	data, _ = os.ReadFile(st.AuditPath())
+	if strings.Contains(string(data), "Acme") {
+		t.Fatalf("audit journal in the clear:\n%s", data)
+	}
+
+	// without the key, or with another, nothing reads
+	if _, err := plain.List(); !errors.Is(err, ErrEncrypted) {
+		t.Fatalf("read without the key: %v", err)
+	}
+	other, _ := GenerateKey()
+	wrong, _ := OpenEncrypted(path, other)
+	if _, err := wrong.List(); err == nil {
+		t.Fatal("read with the wrong key")
+	}
+	if err := wrong.Rekey(nil); err == nil {
+		t.Fatal("rekeyed with the wrong key")
+	}
+
+	// decrypting puts it back in the clear
+	if err := st.Rekey(nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := plain.List(); err != nil || len(got) != 1 || got[0].Name != "Acme due diligence" {
+		t.Fatalf("decrypted history: %+v, %v", got, err)
+	}
+	if enc, _ := plain.Encrypted(); enc {
+		t.Fatal("still encrypted")
+	}
+}
+
+func TestStore_RekeyAllOrNothing(t *testing.T) {
+	dir := t.TempDir()
+	key, _ := GenerateKey()
+	st, _ := OpenEncrypted(filepath.Join(dir, "history.jsonl"), key)
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	for _, id := range []string{"a", "b"} {
+		_ = st.Append(Session{ID: id, Phase: "WORK", Start: base, End: base.Add(25 * time.Minute)})
+	}
+	_ = st.Delete("a")
+	if fi, _ := os.Stat(st.Path()); fi.Mode().Perm() != 0o600 {
+		t.Errorf("history mode %v", fi.Mode())
+	}
+
+	// the audit journal can't be rewritten: the history isn't either
+	if err := os.Mkdir(st.AuditPath()+".tmp", 0o700); err != nil {
+		t.Fatal(err)
+	}
+	next, _ := GenerateKey()
+	if err := st.Rekey(next); err == nil {
+		t.Fatal("rekeyed around an unwritable file")
+	}
+	old, _ := OpenEncrypted(st.Path(), key)
+	if got, err := old.List(); err != nil || len(got) != 1 {
+		t.Fatalf("history after a failed rekey: %+v, %v", got, err)
+	}
+	if _, err := os.Stat(st.Path() + ".tmp"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("left a temporary file: %v", err)
+	}
+}
+
+func TestRecorder_PauseReasons(t *testing.T) {
+	st, _ := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	rec := NewRecorder(st, func(err error) { t.Fatalf("record: %v", err) })
+
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+	work := core.State{Phase: core.PhaseWork, StartedAt: base}
+
+	paused := work
+	paused.Paused = true
+	withReason := paused
+	withReason.PauseReason = core.ReasonMeeting
+
+	rec.Handle(core.Event{Kind: core.EventStart, State: work, At: at(0)})
+	rec.Handle(core.Event{Kind: core.EventPause, State: paused, At: at(10)})
+	rec.Handle(core.Event{Kind: core.EventUpdate, State: withReason, At: at(10)})
+	rec.Handle(core.Event{Kind: core.EventResume, State: work, At: at(15)})
+	rec.Handle(core.Event{Kind: core.EventAdvance,
+		State: core.State{Phase: core.PhaseShortBreak, PomodoroDone: 1}, At: at(30)})
+	rec.Handle(core.Event{Kind: core.EventAbandon, At: at(32)})
+	rec.Handle(core.Event{Kind: core.EventStop, At: at(32)})
+
+	got, err := st.List()
+	if err != nil {
+		t.Fatalf("list: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(got))
+	}
+	w := got[0]
+	if w.Phase != "WORK" || !w.Completed || len(w.Pauses) != 1 {
+		t.Fatalf("unexpected work session %+v", w)
+	}
+	if w.Pauses[0].Reason != "meeting" || w.Pauses[0].Duration() != 5*time.Minute {
+		t.Fatalf("unexpected pause %+v", w.Pauses[0])
+	}
+	if w.Active() != 25*time.Minute {
+		t.Fatalf("active: want 25m, got %v", w.Active())
+	}
+	if b := got[1]; b.Phase != "SHORT_BREAK" || b.Completed || !b.Abandoned {
+		t.Fatalf("stopped break should be abandoned: %+v", b)
+	}
+}
+
+func TestRecorder_Overtime(t *testing.T) {
+	st, _ := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	rec := NewRecorder(st, nil)
+
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+	work := core.State{Phase: core.PhaseWork, StartedAt: base}
+	over := work
+	over.Overtime = true
+
+	rec.Handle(core.Event{Kind: core.EventStart, State: work, At: at(0)})
+	rec.Handle(core.Event{Kind: core.EventOvertime, State: over, At: at(25)})
+	rec.Handle(core.Event{Kind: core.EventStop, At: at(32)})
+
+	got, _ := st.List()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 session, got %d", len(got))
+	}
+	if !got[0].Completed || got[0].Overtime != 7*time.Minute {
+		t.Fatalf("unexpected overtime session %+v", got[0])
+	}
+}
+
+func TestRecorder_Extensions(t *testing.T) {
+	st, _ := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	rec := NewRecorder(st, nil)
+
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	work := core.State{Phase: core.PhaseWork, StartedAt: base}
+	rec.Handle(core.Event{Kind: core.EventStart, State: work, At: base})
+	rec.Handle(core.Event{Kind: core.EventExtend, State: work, At: base.Add(20 * time.Minute), Extension: 5 * time.Minute})
+	rec.Handle(core.Event{Kind: core.EventExtend, State: work, At: base.Add(21 * time.Minute), Extension: -time.Minute})
+	rec.Handle(core.Event{Kind: core.EventStop, At: base.Add(22 * time.Minute)})
+
+	got, _ := st.List()
+	if len(got) != 1 || len(got[0].Extensions) != 2 {
+		t.Fatalf("expected one session with 2 extensions, got %+v", got)
+	}
+	if e := got[0].Extensions[1]; e.By != -time.Minute || !e.At.Equal(base.Add(21*time.Minute)) {
+		t.Fatalf("unexpected extension %+v", e)
+	}
+}
+
+func TestRecorder_Tags(t *testing.T) {
+	st, _ := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	rec := NewRecorder(st, nil)
+
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	work := core.State{Phase: core.PhaseWork, StartedAt: base, Tags: []string{"coding"}}
+	tagged := work
+	tagged.Tags = []string{"coding", "client-a"}
+	rec.Handle(core.Event{Kind: core.EventStart, State: work, At: base})
+	rec.Handle(core.Event{Kind: core.EventTask, State: tagged, At: base.Add(time.Minute)})
+	rec.Handle(core.Event{Kind: core.EventAdvance,
+		State: core.State{Phase: core.PhaseShortBreak, Tags: tagged.Tags}, At: base.Add(25 * time.Minute)})
+	rec.Handle(core.Event{Kind: core.EventStop, At: base.Add(30 * time.Minute)})
+
+	got, _ := st.List()
+	if len(got) != 2 || !slices.Equal(got[0].Tags, tagged.Tags) || got[1].Tags != nil {
+		t.Fatalf("expected a work session tagged %q and an untagged break, got %+v", tagged.Tags, got)
+	}
+}
+
+func TestRecorder_Undo(t *testing.T) {
+	st, _ := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	rec := NewRecorder(st, func(err error) { t.Fatalf("record: %v", err) })
+	if _, err := rec.Recover("tui", time.Now()); err != nil {
+		t.Fatalf("recover: %v", err)
+	}
+
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+	work := core.State{Phase: core.PhaseWork, StartedAt: base, Length: 25 * time.Minute}
+	paused := work
+	paused.Paused = true
+	brk := core.State{Phase: core.PhaseShortBreak, StartedAt: at(12)}
+
+	rec.Handle(core.Event{Kind: core.EventStart, State: work, At: at(0)})
+	rec.Handle(core.Event{Kind: core.EventExtend, State: work, At: at(5), Extension: 5 * time.Minute})
+	rec.Handle(core.Event{Kind: core.EventUndo, State: work, At: at(6), Undone: "extend"})
+	rec.Handle(core.Event{Kind: core.EventPause, State: paused, At: at(10)})
+	rec.Handle(core.Event{Kind: core.EventSkip, State: brk, At: at(12)})
+	if got, _ := st.List(); len(got) != 1 {
+		t.Fatalf("skip stored %+v", got)
+	}
+	rec.Handle(core.Event{Kind: core.EventUndo, State: paused, At: at(13), Undone: "skip"})
+	if got, _ := st.List(); len(got) != 0 {
+		t.Fatalf("undone skip left %+v", got)
+	}
+
+	// the session taken up again survives a crash
+	rec = NewRecorder(st, nil)
+	if got, err := rec.Recover("tui", at(14)); err != nil || !got.Resume {
+		t.Fatalf("recovery %+v, %v: want a resume", got, err)
+	}
+	rec.Handle(core.Event{Kind: core.EventResume, State: work, At: at(15)})
+	rec.Handle(core.Event{Kind: core.EventAdvance, State: core.State{Phase: core.PhaseShortBreak, PomodoroDone: 1}, At: at(30)})
+
+	got, _ := st.List()
+	if len(got) != 1 {
+		t.Fatalf("expected 1 session, got %+v", got)
+	}
+	w := got[0]
+	if !w.Start.Equal(at(0)) || !w.Completed || len(w.Extensions) != 0 {
+		t.Fatalf("unexpected work session %+v", w)
+	}
+	if len(w.Pauses) != 1 || w.Pauses[0].Duration() != 5*time.Minute {
+		t.Fatalf("unexpected pauses %+v", w.Pauses)
+	}
+}
+
+func TestRecorder_Recover(t *testing.T) {
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+	work := core.State{Phase: core.PhaseWork, StartedAt: base, Length: 25 * time.Minute, PomodoroDone: 1}
+	paused := work
+	paused.Paused = true
+
+	// crash leaves the journal of a process that was killed after events
+	crash := func(t *testing.T, events ...core.Event) *Store {
+		t.Helper()
+		st, _ := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+		rec := NewRecorder(st, func(err error) { t.Fatalf("record: %v", err) })
+		if _, err := rec.Recover("tui", at(0)); err != nil {
+			t.Fatalf("recover: %v", err)
+		}
+		for _, ev := range events {
+			rec.Handle(ev)
+		}
+		return st
+	}
+	start := core.Event{Kind: core.EventStart, State: work, At: at(0), Remaining: 25 * time.Minute}
+	pause := core.Event{Kind: core.EventPause, State: paused, At: at(10), Remaining: 15 * time.Minute, Elapsed: 10 * time.Minute}
+
+	t.Run("resumes with time left", func(t *testing.T) {
+		st := crash(t, start)
+		rec := NewRecorder(st, nil)
+		got, err := rec.Recover("tui", at(20))
+		if err != nil || !got.Resume || got.Remaining != 5*time.Minute || got.Elapsed != 20*time.Minute {
+			t.Fatalf("recovery %+v, %v: want a resume with 5m left", got, err)
+		}
+		rec.Handle(core.Event{Kind: core.EventAdvance, State: core.State{Phase: core.PhaseShortBreak}, At: at(25)})
+		sessions, _ := st.List()
+		if len(sessions) != 1 || !sessions[0].Start.Equal(at(0)) || !sessions[0].Completed {
+			t.Fatalf("resumed session stored as %+v", sessions)
+		}
+	})
+	t.Run("counts past the deadline", func(t *testing.T) {
+		st := crash(t, start)
+		got, err := NewRecorder(st, nil).Recover("tui", at(90))
+		if err != nil || got.Resume || got.Session == nil || !got.Session.Completed || !got.Session.End.Equal(at(25)) {
+			t.Fatalf("recovery %+v, %v: want a completed session ending at the deadline", got, err)
+		}
+		if _, err := os.Stat(st.JournalPath("tui")); !os.IsNotExist(err) {
+			t.Fatalf("journal left after storing the phase: %v", err)
+		}
+	})
+	t.Run("resumes a recent pause", func(t *testing.T) {
+		got, err := NewRecorder(crash(t, start, pause), nil).Recover("tui", at(30))
+		if err != nil || !got.Resume || !got.State.Paused || got.Remaining != 15*time.Minute {
+			t.Fatalf("recovery %+v, %v: want a paused resume with 15m left", got, err)
+		}
+	})
+	t.Run("abandons an old pause", func(t *testing.T) {
+		got, err := NewRecorder(crash(t, start, pause), nil).Recover("tui", at(180))
+		if err != nil || got.Session == nil || got.Session.Completed || !got.Session.Abandoned || !got.Session.End.Equal(at(10)) {
+			t.Fatalf("recovery %+v, %v: want an abandoned session", got, err)
+		}
+	})
+	t.Run("nothing after a clean stop", func(t *testing.T) {
+		st := crash(t, start, core.Event{Kind: core.EventAbandon, State: work, At: at(5)}, core.Event{Kind: core.EventStop, At: at(5)})
+		got, err := NewRecorder(st, nil).Recover("tui", at(6))
+		if err != nil || got.Resume || got.Session != nil {
+			t.Fatalf("recovery %+v, %v: want nothing", got, err)
+		}
+	})
+}
+
+func TestExport(t *testing.T) {
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{ID: "a", Phase: "WORK", Start: base, End: base.Add(25 * time.Minute), Completed: true,
+			Interruptions: []Interruption{{At: base, Kind: "internal"}, {At: base, Kind: "external"}, {At: base, Kind: "external"}}},
+		{ID: "b", Phase: "SHORT_BREAK", Start: base.Add(25 * time.Minute), End: base.Add(27 * time.Minute), Abandoned: true},
+	}
+
+	var csvOut strings.Builder
+	if err := Export(&csvOut, "csv", sessions); err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected header + 2 rows, got %q", lines)
+	}
+	if want := "a,WORK,,,2025-05-01T09:00:00Z,2025-05-01T09:25:00Z,completed,25.00,0.00,0.00,1,2"; lines[1] != want {
+		t.Fatalf("row = %q, want %q", lines[1], want)
+	}
+	if !strings.Contains(lines[2], ",abandoned,2.00,") {
+		t.Fatalf("row = %q", lines[2])
+	}
+
+	var jsonOut strings.Builder
+	if err := Export(&jsonOut, "json", sessions); err != nil {
+		t.Fatalf("json: %v", err)
+	}
+	var recs []map[string]any
+	if err := json.Unmarshal([]byte(jsonOut.String()), &recs); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if len(recs) != 2 || recs[0]["status"] != "completed" || len(recs[1]["interruptions"].([]any)) != 0 {
+		t.Fatalf("unexpected records %v", recs)
+	}
+
+	if err := Export(&jsonOut, "xml", sessions); err == nil {
+		t.Fatal("expected an error for an unknown format")
+	}
+}
+
+func TestWriteICS(t *testing.T) {
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{ID: "a", Phase: "WORK", Start: base, End: base.Add(25 * time.Minute), Completed: true,
+			Task: &Task{Title: "write the report, part 2; with a long title that needs folding"}},
+		{ID: "b", Phase: "SHORT_BREAK", Start: base.Add(25 * time.Minute), End: base.Add(30 * time.Minute), Completed: true},
+		{ID: "c", Phase: "WORK", Start: base.Add(30 * time.Minute), End: base.Add(40 * time.Minute), Abandoned: true},
+	}
+	var out strings.Builder
+	if err := Export(&out, "ics", sessions); err != nil {
+		t.Fatal(err)
+	}
+	ics := out.String()
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\nVERSION:2.0\r\n",
+		"UID:a@gopomodoro\r\n",
+		"DTSTART:20250501T090000Z\r\nDTEND:20250501T092500Z\r\n",
+		"SUMMARY:☕ Short break\r\n",
+		"TRANSP:TRANSPARENT\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("missing %q in\n%s", want, ics)
+		}
+	}
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	if want := "SUMMARY:🍅 Pomodoro: write the report\\, part 2\\; with a long title that needs folding\r\n"; !strings.Contains(unfolded, want) {
+		t.Errorf("missing %q", want)
+	}
+	if strings.Contains(ics, "UID:c@") {
+		t.Error("abandoned session exported")
+	}
+	for _, l := range strings.Split(ics, "\r\n") {
+		if len(l) > 75 {
+			t.Errorf("line longer than 75 octets: %q", l)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package history
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// WriteICS writes the completed sessions as an iCalendar feed, one event
+// per pomodoro or break, for calendar apps to import or subscribe to.
+func WriteICS(w io.Writer, sessions []Session) error {
+	bw := bufio.NewWriter(w)
+	line := func(name, value string) {
+		writeFolded(bw, name+":"+value)
+	}
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//GoPomodoro//GoPomodoro//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", "GoPomodoro")
+	for _, s := range sessions {
+		if !s.Completed {
+			continue
+		}
+		line("BEGIN", "VEVENT")
+		line("UID", s.ID+"@gopomodoro")
+		line("DTSTAMP", icsTime(s.End))
+		line("DTSTART", icsTime(s.Start))
+		line("DTEND", icsTime(s.End))
+		line("SUMMARY", icsText(icsSummary(s)))
+		line("DESCRIPTION", icsText(icsDescription(s)))
+		line("CATEGORIES", icsText(s.Phase))
+		if s.Phase == "WORK" {
+			line("TRANSP", "OPAQUE")
+		} else {
+			line("TRANSP", "TRANSPARENT")
+		}
+		line("END", "VEVENT")
+	}
+	line("END", "VCALENDAR")
+	return bw.Flush()
+}
+
+func icsSummary(s Session) string {
+	title := s.Name
+	if title == "" || title == s.Phase {
+		switch s.Phase {
+		case "WORK":
+			title = "Pomodoro"
+		case "SHORT_BREAK":
+			title = "Short break"
+		case "LONG_BREAK":
+			title = "Long break"
+		default:
+			title = s.Phase
+		}
+	}
+	if s.Task != nil && s.Task.Title != "" {
+		title += ": " + s.Task.Title
+	}
+	if s.Phase == "WORK" {
+		return "🍅 " + title
+	}
+	return "☕ " + title
+}
+
+func icsDescription(s Session) string {
+	d := fmt.Sprintf("%d min active", int(s.Active().Round(time.Minute)/time.Minute))
+	if p := s.Paused(); p > 0 {
+		d += fmt.Sprintf(", %d min paused", int(p.Round(time.Minute)/time.Minute))
+	}
+	if s.Overtime > 0 {
+		d += fmt.Sprintf(", %d min overtime", int(s.Overtime.Round(time.Minute)/time.Minute))
+	}
+	if n := len(s.Interruptions); n > 0 {
+		d += fmt.Sprintf(", %d interruptions", n)
+	}
+	return d
+}
+
+func icsTime(t time.Time) string { return t.UTC().Format("20060102T150405Z") }
+
+// icsText escapes a TEXT value (RFC 5545 3.3.11).
+var icsText = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\n", `\n`).Replace
+
+// writeFolded writes a content line, folded at 75 octets without
+// splitting UTF-8 sequences, and ends it with CRLF.
+func writeFolded(w *bufio.Writer, l string) {
+	limit := 75
+	for len(l) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(l[cut]) {
+			cut--
+		}
+		w.WriteString(l[:cut] + "\r\n ")
+		l = l[cut:]
+		limit = 74 // the leading space counts
+	}
+	w.WriteString(l + "\r\n")
+}
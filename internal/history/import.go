@@ -0,0 +1,358 @@
+package history
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// ImportFormats lists the formats accepted by Import.
+var ImportFormats = []string{"csv", "flow", "pomotroid"}
+
+// Import reads sessions written by another tool. format is one of
+// ImportFormats; mapping only applies to the CSV formats.
+func Import(r io.Reader, format string, mapping Mapping) ([]Session, error) {
+	switch format {
+	case "csv", "flow":
+		return ReadCSV(r, mapping)
+	case "pomotroid":
+		return ReadPomotroid(r)
+	}
+	return nil, fmt.Errorf("unknown import format %q (want %s)", format, strings.Join(ImportFormats, ", "))
+}
+
+// Mapping names the CSV column holding each field: phase, name, task,
+// start, end, duration and status. Fields not mapped are found by common
+// header names, which cover GoPomodoro's own export and Flow's.
+type Mapping map[string]string
+
+// importFields are the fields a Mapping may set.
+var importFields = []string{"phase", "name", "task", "start", "end", "duration", "status"}
+
+// columnAliases are the headers tried, case-insensitively, for fields
+// without an explicit mapping.
+var columnAliases = map[string][]string{
+	"phase":    {"phase", "type", "kind", "session type", "session"},
+	"name":     {"name", "label"},
+	"task":     {"task", "title", "description"},
+	"start":    {"start", "start date", "start time", "started", "started at", "begin"},
+	"end":      {"end", "end date", "end time", "ended", "ended at", "finished", "finish"},
+	"duration": {"duration", "minutes", "length"},
+	"status":   {"status", "completed", "complete", "state"},
+}
+
+// ParseMapping parses "field=column,..." as given on the command line.
+func ParseMapping(s string) (Mapping, error) {
+	m := Mapping{}
+	if strings.TrimSpace(s) == "" {
+		return m, nil
+	}
+	for _, pair := range strings.Split(s, ",") {
+		field, col, ok := strings.Cut(pair, "=")
+		field = strings.ToLower(strings.TrimSpace(field))
+		if !ok || col == "" {
+			return nil, fmt.Errorf("mapping %q: want field=column", pair)
+		}
+		if !slices.Contains(importFields, field) {
+			return nil, fmt.Errorf("mapping %q: unknown field %q (want %s)", pair, field, strings.Join(importFields, ", "))
+		}
+		m[field] = strings.TrimSpace(col)
+	}
+	return m, nil
+}
+
+// ReadCSV reads sessions from a CSV file with a header row. A row needs
+// a start and either an end or a duration; a missing phase means work
+// and a missing status means completed.
+func ReadCSV(r io.Reader, mapping Mapping) ([]Session, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	header, err := cr.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	index := make(map[string]int, len(header))
+	for i, h := range header {
+		index[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))] = i
+	}
+	cols := map[string]int{}
+	for _, field := range importFields {
+		if name, ok := mapping[field]; ok {
+			i, ok := index[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("column %q for %s not in header", name, field)
+			}
+			cols[field] = i
+			continue
+		}
+		for _, alias := range columnAliases[field] {
+			if i, ok := index[alias]; ok {
+				cols[field] = i
+				break
+			}
+		}
+	}
+	if _, ok := cols["start"]; !ok {
+		return nil, errors.New("no start column; map one with start=<column>")
+	}
+	_, hasEnd := cols["end"]
+	_, hasDur := cols["duration"]
+	if !hasEnd && !hasDur {
+		return nil, errors.New("no end or duration column; map one with end=<column> or duration=<column>")
+	}
+
+	var out []Session
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return out, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		get := func(field string) string {
+			i, ok := cols[field]
+			if !ok || i >= len(rec) {
+				return ""
+			}
+			return strings.TrimSpace(rec[i])
+		}
+		if strings.Join(rec, "") == "" {
+			continue
+		}
+		sess, err := importSession(get("phase"), get("name"), get("start"), get("end"), get("duration"), get("status"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		if title := get("task"); title != "" && sess.Phase == core.PhaseWork.String() {
+			sess.Task = &Task{Title: title}
+		}
+		out = append(out, sess)
+	}
+}
+
+// pomotroidRound is one entry of a Pomotroid statistics export. Field
+// names vary between versions, so the common spellings are all read.
+type pomotroidRound struct {
+	Type      string          `json:"type"`
+	RoundType string          `json:"roundType"`
+	Start     json.RawMessage `json:"start"`
+	StartedAt json.RawMessage `json:"startedAt"`
+	End       json.RawMessage `json:"end"`
+	EndedAt   json.RawMessage `json:"completedAt"`
+	Duration  json.RawMessage `json:"duration"` // seconds
+	Completed *bool           `json:"completed"`
+}
+
+// ReadPomotroid reads a Pomotroid statistics export: a JSON array of
+// rounds (or an object with a "rounds" array) whose times are RFC 3339
+// strings or Unix milliseconds.
+func ReadPomotroid(r io.Reader) ([]Session, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var rounds []pomotroidRound
+	if err := json.Unmarshal(data, &rounds); err != nil {
+		var wrapped struct {
+			Rounds []pomotroidRound `json:"rounds"`
+		}
+		if json.Unmarshal(data, &wrapped) != nil {
+			return nil, fmt.Errorf("pomotroid: %w", err)
+		}
+		rounds = wrapped.Rounds
+	}
+	out := make([]Session, 0, len(rounds))
+	for i, rd := range rounds {
+		status := ""
+		if rd.Completed != nil {
+			status = strconv.FormatBool(*rd.Completed)
+		}
+		dur := rawString(rd.Duration)
+		if dur != "" {
+			if _, err := strconv.ParseFloat(dur, 64); err == nil {
+				dur += "s"
+			}
+		}
+		sess, err := importSession(first(rd.Type, rd.RoundType), "",
+			first(rawString(rd.Start), rawString(rd.StartedAt)),
+			first(rawString(rd.End), rawString(rd.EndedAt)), dur, status)
+		if err != nil {
+			return nil, fmt.Errorf("pomotroid round %d: %w", i+1, err)
+		}
+		out = append(out, sess)
+	}
+	return out, nil
+}
+
+// rawString returns a JSON string or number as text.
+func rawString(raw json.RawMessage) string {
+	var s string
+	if json.Unmarshal(raw, &s) == nil {
+		return s
+	}
+	var n json.Number
+	if json.Unmarshal(raw, &n) == nil {
+		return n.String()
+	}
+	return ""
+}
+
+func first(vals ...string) string {
+	for _, v := range vals {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// importSession builds a session from text fields.
+func importSession(phase, name, start, end, dur, status string) (Session, error) {
+	ph, err := importPhase(phase)
+	if err != nil {
+		return Session{}, err
+	}
+	s := Session{ID: newID(), Phase: ph.String(), Name: name}
+	if s.Start, err = importTime(start); err != nil {
+		return Session{}, fmt.Errorf("start: %w", err)
+	}
+	if end != "" {
+		if s.End, err = importTime(end); err != nil {
+			return Session{}, fmt.Errorf("end: %w", err)
+		}
+	} else {
+		d, err := importDuration(dur)
+		if err != nil {
+			return Session{}, fmt.Errorf("duration: %w", err)
+		}
+		s.End = s.Start.Add(d)
+	}
+	if s.End.Before(s.Start) {
+		return Session{}, errors.New("ends before it starts")
+	}
+	switch strings.ToLower(status) {
+	case "", "completed", "complete", "done", "true", "yes", "1":
+		s.Completed = true
+	case "abandoned", "cancelled", "canceled", "stopped":
+		s.Abandoned = true
+	case "incomplete", "skipped", "false", "no", "0":
+	default:
+		return Session{}, fmt.Errorf("unknown status %q", status)
+	}
+	return s, nil
+}
+
+// importPhase maps the phase names other tools use; empty means work.
+func importPhase(s string) (core.Phase, error) {
+	norm := strings.NewReplacer(" ", "", "-", "", "_", "").Replace(strings.ToLower(s))
+	switch norm {
+	case "", "work", "pomodoro", "focus":
+		return core.PhaseWork, nil
+	case "shortbreak", "short", "break", "rest":
+		return core.PhaseShortBreak, nil
+	case "longbreak", "long":
+		return core.PhaseLongBreak, nil
+	}
+	return 0, fmt.Errorf("unknown phase %q", s)
+}
+
+// importLayouts are the date-time layouts tried after RFC 3339; they
+// are read in local time.
+var importLayouts = []string{
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04",
+	"01/02/2006 15:04:05",
+	"01/02/2006 15:04",
+	"02.01.2006 15:04:05",
+	"02.01.2006 15:04",
+}
+
+// importTime parses RFC 3339, the layouts above, or Unix seconds or
+// milliseconds.
+func importTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, errors.New("missing")
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	for _, layout := range importLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if n > 1e11 {
+			return time.UnixMilli(n), nil
+		}
+		return time.Unix(n, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("unrecognized time %q", s)
+}
+
+// importDuration parses a Go duration ("25m"), "[h:]mm:ss", or a plain
+// number of minutes.
+func importDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, errors.New("missing")
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return d, nil
+	}
+	if f, err := strconv.ParseFloat(s, 64); err == nil {
+		return time.Duration(f * float64(time.Minute)), nil
+	}
+	if parts := strings.Split(s, ":"); len(parts) == 2 || len(parts) == 3 {
+		var secs int
+		for _, p := range parts {
+			n, err := strconv.Atoi(p)
+			if err != nil {
+				return 0, fmt.Errorf("unrecognized duration %q", s)
+			}
+			secs = secs*60 + n
+		}
+		return time.Duration(secs) * time.Second, nil
+	}
+	return 0, fmt.Errorf("unrecognized duration %q", s)
+}
+
+// Import appends sessions not already in the store, matching on phase
+// and start time so the same file can be imported twice. It returns how
+// many were added.
+func (s *Store) Import(sessions []Session) (int, error) {
+	existing, err := s.List()
+	if err != nil {
+		return 0, err
+	}
+	seen := make(map[string]bool, len(existing))
+	key := func(sess Session) string { return sess.Phase + "@" + strconv.FormatInt(sess.Start.Unix(), 10) }
+	for _, sess := range existing {
+		seen[key(sess)] = true
+	}
+	added := 0
+	for _, sess := range sessions {
+		if seen[key(sess)] {
+			continue
+		}
+		seen[key(sess)] = true
+		if err := s.Append(sess); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
@@ -0,0 +1,93 @@
+package history
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSV_Mapping(t *testing.T) {
+	in := "Begin,Kind,Len,Done\n" +
+		"2025-05-01 09:00,Pomodoro,25:00,yes\n" +
+		"2025-05-01 09:25,Short Break,5,no\n"
+	m, err := ParseMapping("start=Begin,duration=Len,status=Done")
+	if err != nil {
+		t.Fatalf("mapping: %v", err)
+	}
+	got, err := ReadCSV(strings.NewReader(in), m)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 sessions, got %d", len(got))
+	}
+	if got[0].Phase != "WORK" || !got[0].Completed || got[0].End.Sub(got[0].Start) != 25*time.Minute {
+		t.Fatalf("unexpected first session %+v", got[0])
+	}
+	if got[1].Phase != "SHORT_BREAK" || got[1].Completed || got[1].End.Sub(got[1].Start) != 5*time.Minute {
+		t.Fatalf("unexpected second session %+v", got[1])
+	}
+
+	if _, err := ReadCSV(strings.NewReader("When,What\n"), nil); err == nil {
+		t.Fatal("expected an error without a start column")
+	}
+	if _, err := ParseMapping("begin=Start"); err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
+
+func TestReadCSV_RoundTripsExport(t *testing.T) {
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	want := []Session{
+		{Phase: "WORK", Start: base, End: base.Add(25 * time.Minute), Completed: true},
+		{Phase: "WORK", Start: base.Add(time.Hour), End: base.Add(70 * time.Minute), Abandoned: true},
+	}
+	var buf strings.Builder
+	if err := WriteCSV(&buf, want); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	got, err := ReadCSV(strings.NewReader(buf.String()), nil)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	for i := range want {
+		if got[i].Phase != want[i].Phase || !got[i].Start.Equal(want[i].Start) || !got[i].End.Equal(want[i].End) ||
+			got[i].Completed != want[i].Completed || got[i].Abandoned != want[i].Abandoned {
+			t.Fatalf("session %d = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestReadPomotroid(t *testing.T) {
+	in := `[{"type":"work","startedAt":1746090000000,"duration":1500},
+		{"type":"long-break","start":"2025-05-01T10:00:00Z","end":"2025-05-01T10:15:00Z","completed":false}]`
+	got, err := ReadPomotroid(strings.NewReader(in))
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	if len(got) != 2 || got[0].Phase != "WORK" || got[0].End.Sub(got[0].Start) != 25*time.Minute || !got[0].Completed {
+		t.Fatalf("unexpected sessions %+v", got)
+	}
+	if got[1].Phase != "LONG_BREAK" || got[1].Completed {
+		t.Fatalf("unexpected long break %+v", got[1])
+	}
+}
+
+func TestStore_ImportSkipsDuplicates(t *testing.T) {
+	st, _ := Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	sessions := []Session{
+		{ID: "a", Phase: "WORK", Start: base, End: base.Add(25 * time.Minute), Completed: true},
+		{ID: "b", Phase: "SHORT_BREAK", Start: base.Add(25 * time.Minute), End: base.Add(30 * time.Minute), Completed: true},
+	}
+	if n, err := st.Import(sessions); err != nil || n != 2 {
+		t.Fatalf("first import: %d %v", n, err)
+	}
+	if n, err := st.Import(sessions); err != nil || n != 0 {
+		t.Fatalf("second import: %d %v", n, err)
+	}
+	if got, _ := st.List(); len(got) != 2 {
+		t.Fatalf("expected 2 stored sessions, got %d", len(got))
+	}
+}
@@ -0,0 +1,173 @@
+package history
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// resumeWithin is how long after its last event a paused or open phase
+// left in the journal still resumes; after that it was abandoned.
+const resumeWithin = time.Hour
+
+// journalEntry is an engine event as written to the journal.
+type journalEntry struct {
+	Kind          core.EventKind     `json:"kind"`
+	At            time.Time          `json:"at"`
+	State         core.State         `json:"state"`
+	Remaining     time.Duration      `json:"remaining"`
+	Elapsed       time.Duration      `json:"elapsed"`
+	Interruption  *core.Interruption `json:"interruption,omitempty"`
+	Extension     time.Duration      `json:"extension,omitempty"`
+	AbandonReason string             `json:"abandon_reason,omitempty"`
+	Undone        string             `json:"undone,omitempty"`
+	// Session is the stored session an undo took up again; the entry
+	// then starts the journal in place of the session's events.
+	Session *Session `json:"session,omitempty"`
+}
+
+func newJournalEntry(ev core.Event) journalEntry {
+	return journalEntry{
+		Kind:          ev.Kind,
+		At:            ev.At,
+		State:         ev.State,
+		Remaining:     ev.Remaining,
+		Elapsed:       ev.Elapsed,
+		Interruption:  ev.Interruption,
+		Extension:     ev.Extension,
+		AbandonReason: ev.AbandonReason,
+		Undone:        ev.Undone,
+	}
+}
+
+func (e journalEntry) event() core.Event {
+	return core.Event{
+		Kind:          e.Kind,
+		At:            e.At,
+		State:         e.State,
+		Remaining:     e.Remaining,
+		Elapsed:       e.Elapsed,
+		Interruption:  e.Interruption,
+		Extension:     e.Extension,
+		AbandonReason: e.AbandonReason,
+		Undone:        e.Undone,
+	}
+}
+
+// readJournal decodes the journal at path, decrypting lines with key. A
+// line cut short by a crash ends it.
+func readJournal(path string, key *Key) ([]journalEntry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var out []journalEntry
+	for line := range bytes.Lines(data) {
+		plain, err := key.open(bytes.TrimSuffix(line, []byte("\n")))
+		if errors.Is(err, ErrEncrypted) {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		var e journalEntry
+		if err != nil || json.Unmarshal(plain, &e) != nil {
+			break
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// Recovery is what Recover made of the phase an earlier process left
+// in the journal.
+type Recovery struct {
+	// Session is the phase as stored, counted or abandoned, when it
+	// doesn't go on; nil otherwise.
+	Session *Session
+	// Resume is set when the phase goes on: State is where it was, and
+	// Remaining and Elapsed are its times as of Recover.
+	Resume    bool
+	State     core.State
+	Remaining time.Duration
+	Elapsed   time.Duration
+}
+
+// Restore puts a phase that goes on back into engine; otherwise it does
+// nothing.
+func (rc Recovery) Restore(engine *core.PomodoroEngine) {
+	if rc.Resume {
+		engine.Restore(rc.State, rc.Remaining, rc.Elapsed)
+	}
+}
+
+// Recover turns on the journal: from now on, the events of the phase
+// in progress are appended to the store's JournalPath for name until
+// the phase is stored, so a process killed mid-phase loses nothing.
+// name keeps apart commands sharing the store, e.g. the TUI and the
+// daemon. It first picks up a phase an earlier process left there, as
+// of now:
+//
+//   - running with time left, it resumes, as if it had run on;
+//   - running past its deadline, it counts, ending at the deadline;
+//   - in overtime, it counts, ending at its last event;
+//   - paused or open (flow) for less than an hour, it resumes;
+//   - paused or open longer, it is abandoned at its last event.
+//
+// A resumed phase is back in the recorder, and Recovery.Restore puts it
+// back into the engine. Recover must be called before the first event.
+func (r *Recorder) Recover(name string, now time.Time) (Recovery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	path := r.store.JournalPath(name)
+	entries, err := readJournal(path, r.store.key)
+	if err != nil {
+		return Recovery{}, err
+	}
+	var last core.Event
+	for _, e := range entries {
+		last = e.event()
+		if e.Session != nil {
+			r.reopenLocked(*e.Session, last)
+			continue
+		}
+		r.handleLocked(last)
+	}
+	r.journal = path
+	if r.cur == nil {
+		// nothing was in progress, or only a torn line was written
+		if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			return Recovery{}, err
+		}
+		return Recovery{}, nil
+	}
+
+	st := last.State
+	resume := Recovery{Resume: true, State: st, Remaining: last.Remaining, Elapsed: last.Elapsed}
+	switch {
+	case st.Overtime:
+		return Recovery{Session: r.closeLocked(core.Event{At: last.At}, true)}, nil
+	case st.Paused || st.Open:
+		if now.Sub(last.At) >= resumeWithin {
+			r.cur.Abandoned = true
+			return Recovery{Session: r.closeLocked(core.Event{At: last.At}, false)}, nil
+		}
+		if !st.Paused {
+			resume.Elapsed += max(now.Sub(last.At), 0)
+		}
+		return resume, nil
+	}
+	deadline := last.At.Add(last.Remaining)
+	if !now.Before(deadline) {
+		return Recovery{Session: r.closeLocked(core.Event{At: deadline}, true)}, nil
+	}
+	resume.Remaining = deadline.Sub(now)
+	resume.Elapsed = max(st.Length-resume.Remaining, 0)
+	return resume, nil
+}
@@ -0,0 +1,235 @@
+package history
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Recorder turns engine events into session records. Subscribe its
+// Handle method to an engine; a session is written when its phase ends.
+// An engine Undo of a skip or stop deletes the session it stored and
+// takes it up again.
+type Recorder struct {
+	store *Store
+	onErr func(error)
+
+	mu       sync.Mutex
+	cur      *Session
+	pause    *Pause
+	overtime time.Time // when the current work phase went into overtime
+	closed   []Session // stored by the latest skips and stops, for undo
+	sinks    []func(Session)
+	journal  string // the store's journal, once Recover turned it on
+}
+
+// NewRecorder creates a Recorder writing to store. onErr receives write
+// failures and may be nil.
+func NewRecorder(store *Store, onErr func(error)) *Recorder {
+	if onErr == nil {
+		onErr = func(error) {}
+	}
+	return &Recorder{store: store, onErr: onErr}
+}
+
+// OnSession registers fn to receive every session as it is stored, e.g.
+// to mirror it into notes. It must be called before the first event. A
+// session taken up again by an undo is sent again once it ends; fn
+// keeps the earlier copy.
+func (r *Recorder) OnSession(fn func(Session)) {
+	r.sinks = append(r.sinks, fn)
+}
+
+// Handle consumes one engine event.
+func (r *Recorder) Handle(ev core.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handleLocked(ev)
+	if r.journal != "" && r.cur != nil {
+		if err := appendLine(r.journal, newJournalEntry(ev), r.store.key); err != nil {
+			r.onErr(err)
+		}
+	}
+}
+
+func (r *Recorder) handleLocked(ev core.Event) {
+	switch ev.Kind {
+	case core.EventStart:
+		r.closeLocked(ev, false)
+		r.openLocked(ev)
+	case core.EventAdvance:
+		r.closeLocked(ev, true)
+		r.openLocked(ev)
+	case core.EventSkip:
+		r.keepClosedLocked(r.closeLocked(ev, false))
+		r.openLocked(ev)
+	case core.EventPause:
+		if r.cur != nil {
+			r.pause = &Pause{Start: ev.At, Reason: ev.State.PauseReason.String()}
+		}
+	case core.EventUpdate:
+		if r.pause != nil && ev.State.Paused {
+			r.pause.Reason = ev.State.PauseReason.String()
+		}
+	case core.EventResume:
+		r.endPauseLocked(ev)
+	case core.EventInterrupt:
+		if r.cur != nil && ev.Interruption != nil {
+			r.cur.Interruptions = append(r.cur.Interruptions, Interruption{
+				At:   ev.Interruption.At,
+				Kind: ev.Interruption.Kind.String(),
+				Note: ev.Interruption.Note,
+			})
+		}
+	case core.EventAbandon:
+		if r.cur != nil {
+			r.cur.Abandoned = true
+			r.cur.AbandonReason = ev.AbandonReason
+		}
+	case core.EventExtend:
+		if r.cur != nil {
+			r.cur.Extensions = append(r.cur.Extensions, Extension{At: ev.At, By: ev.Extension})
+		}
+	case core.EventTask:
+		if r.cur != nil && r.cur.Phase == core.PhaseWork.String() {
+			r.cur.Task = newTask(ev.State.Task)
+			r.cur.Tags = ev.State.Tags
+		}
+	case core.EventOvertime:
+		if r.cur != nil {
+			r.overtime = ev.At
+		}
+	case core.EventStop:
+		sess := r.closeLocked(ev, ev.QuittingTime || ev.Finished)
+		if !ev.QuittingTime && !ev.Finished {
+			r.keepClosedLocked(sess)
+		}
+	case core.EventUndo:
+		r.undoLocked(ev)
+	}
+}
+
+func (r *Recorder) openLocked(ev core.Event) {
+	r.cur = &Session{
+		ID:    newID(),
+		Phase: ev.State.Phase.String(),
+		Name:  ev.State.Label,
+		Start: ev.At,
+	}
+	if ev.State.Phase == core.PhaseWork {
+		r.cur.Task = newTask(ev.State.Task)
+		r.cur.Tags = ev.State.Tags
+	}
+}
+
+func (r *Recorder) endPauseLocked(ev core.Event) {
+	if r.pause == nil || r.cur == nil {
+		r.pause = nil
+		return
+	}
+	r.pause.End = ev.At
+	r.cur.Pauses = append(r.cur.Pauses, *r.pause)
+	r.pause = nil
+}
+
+// closeLocked finishes the current session, if any, and stores it,
+// clearing the journal.
+func (r *Recorder) closeLocked(ev core.Event, completed bool) *Session {
+	if r.cur == nil {
+		return nil
+	}
+	r.endPauseLocked(ev)
+	sess := *r.cur
+	sess.End = ev.At
+	sess.Completed = completed
+	if !r.overtime.IsZero() {
+		// the deadline was reached, so stopping in overtime still counts
+		sess.Completed = true
+		sess.Overtime = max(ev.At.Sub(r.overtime), 0)
+		r.overtime = time.Time{}
+	}
+	r.cur = nil
+	if err := r.store.Append(sess); err != nil {
+		r.onErr(err)
+	}
+	if r.journal != "" {
+		if err := os.Remove(r.journal); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			r.onErr(err)
+		}
+	}
+	for _, fn := range r.sinks {
+		fn(sess)
+	}
+	return &sess
+}
+
+// keepClosedLocked remembers sess, if any, for an undo of the skip or
+// stop that stored it.
+func (r *Recorder) keepClosedLocked(sess *Session) {
+	if sess == nil {
+		return
+	}
+	r.closed = append(r.closed, *sess)
+	if len(r.closed) > core.UndoDepth {
+		r.closed = r.closed[1:]
+	}
+}
+
+// undoLocked follows an engine Undo: an extension is dropped from the
+// current session; after a skip or stop, the phase that follows is
+// dropped and the session the action stored is deleted and taken up
+// again, journaled whole since its events went with the journal.
+func (r *Recorder) undoLocked(ev core.Event) {
+	if ev.Undone == "extend" {
+		if r.cur != nil && len(r.cur.Extensions) > 0 {
+			r.cur.Extensions = r.cur.Extensions[:len(r.cur.Extensions)-1]
+		}
+		return
+	}
+	n := len(r.closed)
+	if n == 0 || r.closed[n-1].Phase != ev.State.Phase.String() {
+		return
+	}
+	sess := r.closed[n-1]
+	r.closed = r.closed[:n-1]
+	if err := r.store.Delete(sess.ID); err != nil {
+		r.onErr(err)
+	}
+	r.reopenLocked(sess, ev)
+	if r.journal != "" {
+		entry := newJournalEntry(ev)
+		entry.Session = &sess
+		if err := os.Remove(r.journal); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			r.onErr(err)
+		}
+		if err := appendLine(r.journal, entry, r.store.key); err != nil {
+			r.onErr(err)
+		}
+	}
+}
+
+// reopenLocked makes sess, as stored when its phase ended early, the
+// current session again for the phase ev restored.
+func (r *Recorder) reopenLocked(sess Session, ev core.Event) {
+	r.pause = nil
+	r.overtime = time.Time{}
+	if n := len(sess.Pauses); ev.State.Paused && n > 0 {
+		p := sess.Pauses[n-1]
+		p.End = time.Time{}
+		r.pause = &p
+		sess.Pauses = sess.Pauses[: n-1 : n-1]
+	}
+	if ev.State.Overtime {
+		r.overtime = sess.End.Add(-sess.Overtime)
+		sess.Overtime = 0
+	}
+	sess.End = time.Time{}
+	sess.Completed = false
+	sess.Abandoned = false
+	sess.AbandonReason = ""
+	r.cur = &sess
+}
@@ -0,0 +1,236 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Record is a session's state for syncing histories between machines:
+// the session as of Updated, or its deletion when Session is nil.
+// Merging keeps the latest record per ID, so every machine ends up with
+// the same history whatever order they sync in.
+type Record struct {
+	ID      string    `json:"id"`
+	Updated time.Time `json:"updated"`
+	Session *Session  `json:"session,omitempty"`
+}
+
+// newer reports whether a wins over b: the later one, or on a tie the
+// deletion, then the greater encoding, so every machine picks the same.
+func newer(a, b Record) bool {
+	if !a.Updated.Equal(b.Updated) {
+		return a.Updated.After(b.Updated)
+	}
+	if (a.Session == nil) != (b.Session == nil) {
+		return a.Session == nil
+	}
+	ja, _ := json.Marshal(a.Session)
+	jb, _ := json.Marshal(b.Session)
+	return bytes.Compare(ja, jb) > 0
+}
+
+// MergeRecords keeps the winning record per ID of all the sets, ordered
+// by ID.
+func MergeRecords(sets ...[]Record) []Record {
+	best := map[string]Record{}
+	for _, set := range sets {
+		for _, r := range set {
+			if cur, ok := best[r.ID]; r.ID != "" && (!ok || newer(r, cur)) {
+				best[r.ID] = r
+			}
+		}
+	}
+	out := make([]Record, 0, len(best))
+	for _, id := range slices.Sorted(maps.Keys(best)) {
+		out = append(out, best[id])
+	}
+	return out
+}
+
+// WriteRecords writes records as JSON Lines, encrypted like the store
+// when it has a key, so records leave the machine no less protected
+// than the history.
+func (s *Store) WriteRecords(w io.Writer, records []Record) error {
+	for _, r := range records {
+		line, err := json.Marshal(r)
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(append(s.key.seal(line), '\n')); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ReadRecords decodes the JSON Lines WriteRecords wrote.
+func (s *Store) ReadRecords(r io.Reader) ([]Record, error) {
+	var out []Record
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+	for n := 1; sc.Scan(); n++ {
+		if len(bytes.TrimSpace(sc.Bytes())) == 0 {
+			continue
+		}
+		line, err := s.key.open(sc.Bytes())
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		var rec Record
+		if err := json.Unmarshal(line, &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n, err)
+		}
+		out = append(out, rec)
+	}
+	return out, sc.Err()
+}
+
+// syncMark notes when a record merged in from another machine was
+// made, so the session keeps that time rather than its own.
+type syncMark struct {
+	ID      string    `json:"id"`
+	Updated time.Time `json:"updated"`
+	Deleted bool      `json:"deleted,omitempty"`
+}
+
+// SyncPath returns the file of the marks sync leaves, e.g.
+// history.sync.jsonl beside history.jsonl.
+func (s *Store) SyncPath() string {
+	ext := filepath.Ext(s.path)
+	return strings.TrimSuffix(s.path, ext) + ".sync" + ext
+}
+
+// Records returns the store's sessions as records, deletions included.
+// A session was last updated when it ended, was edited here or came in
+// through a sync, whichever is latest.
+func (s *Store) Records() ([]Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.recordsLocked()
+}
+
+func (s *Store) recordsLocked() ([]Record, error) {
+	sessions, err := s.listLocked()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := readLines[Change](s.AuditPath(), s.key)
+	if err != nil {
+		return nil, err
+	}
+	marks, err := readLines[syncMark](s.SyncPath(), s.key)
+	if err != nil {
+		return nil, err
+	}
+	updated := map[string]time.Time{}
+	deleted := map[string]bool{}
+	touch := func(id string, at time.Time, del bool) {
+		if at.After(updated[id]) || updated[id].IsZero() {
+			updated[id] = at
+			deleted[id] = del
+		}
+	}
+	for _, c := range changes {
+		touch(c.Before.ID, c.At, c.After == nil)
+	}
+	for _, m := range marks {
+		touch(m.ID, m.Updated, m.Deleted)
+	}
+
+	records := make([]Record, 0, len(sessions))
+	live := map[string]bool{}
+	for _, sess := range sessions {
+		if sess.ID == "" {
+			continue
+		}
+		live[sess.ID] = true
+		at := sess.End
+		if updated[sess.ID].After(at) {
+			at = updated[sess.ID]
+		}
+		records = append(records, Record{ID: sess.ID, Updated: at, Session: &sess})
+	}
+	for id, at := range updated {
+		if deleted[id] && !live[id] && id != "" {
+			records = append(records, Record{ID: id, Updated: at})
+		}
+	}
+	return MergeRecords(records), nil
+}
+
+// MergeResult counts what a merge changed in the store.
+type MergeResult struct {
+	Added, Updated, Deleted int
+}
+
+// Changed reports whether the merge changed anything.
+func (r MergeResult) Changed() bool {
+	return r.Added+r.Updated+r.Deleted > 0
+}
+
+// Merge applies the records that win over the store's own: sessions it
+// lacks are added, newer versions replace its own and deletions remove
+// them. The file is then rewritten in start order and the applied
+// records marked, so a later Records reports them as they came.
+func (s *Store) Merge(records []Record) (MergeResult, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var res MergeResult
+	local, err := s.recordsLocked()
+	if err != nil {
+		return res, err
+	}
+	mine := make(map[string]Record, len(local))
+	for _, r := range local {
+		mine[r.ID] = r
+	}
+	sessions, err := s.listLocked()
+	if err != nil {
+		return res, err
+	}
+	var marks []syncMark
+	for _, r := range records {
+		cur, ok := mine[r.ID]
+		if r.ID == "" || ok && !newer(r, cur) {
+			continue
+		}
+		i := slices.IndexFunc(sessions, func(sess Session) bool { return sess.ID == r.ID })
+		switch {
+		case r.Session == nil && i >= 0:
+			sessions = slices.Delete(sessions, i, i+1)
+			res.Deleted++
+		case r.Session == nil:
+			// a deletion of a session never seen here is only kept
+		case i >= 0:
+			sessions[i] = *r.Session
+			res.Updated++
+		default:
+			sessions = append(sessions, *r.Session)
+			res.Added++
+		}
+		marks = append(marks, syncMark{ID: r.ID, Updated: r.Updated, Deleted: r.Session == nil})
+	}
+	if len(marks) == 0 {
+		return res, nil
+	}
+	if res.Changed() {
+		slices.SortStableFunc(sessions, func(a, b Session) int { return a.Start.Compare(b.Start) })
+		if err := s.writeLocked(sessions); err != nil {
+			return res, err
+		}
+	}
+	for _, m := range marks {
+		if err := appendLine(s.SyncPath(), m, s.key); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
@@ -0,0 +1,316 @@
+package i18n
+
+// catalogs map English text to its translation, per locale.
+var catalogs = map[string]map[string]string{
+	"zh-TW": {
+		// phases
+		"WORK":        "工作",
+		"SHORT_BREAK": "短休息",
+		"LONG_BREAK":  "長休息",
+		"IDLE":        "閒置",
+
+		// notifications
+		"Phase: %s":                                                   "階段：%s",
+		"%s left in %s":                                               "%[2]s 還剩 %[1]s",
+		"Work done, overtime running":                                 "工作完成，正在超時",
+		"Done, overtime running":                                      "完成，正在超時",
+		"Quitting time, done for today":                               "下班時間到，今天到此為止",
+		"Pomodoro done, well earned rest":                             "番茄鐘完成，好好休息吧",
+		"All %d pomodoros done, well earned rest":                     "%d 個番茄鐘全部完成，好好休息吧",
+		"Work done, overtime running. Press [%s] to take your break.": "工作完成，正在超時。按 [%s] 開始休息。",
+		"Quitting time, done for today. Press [%s] to keep working.":  "下班時間到，今天到此為止。按 [%s] 繼續工作。",
+		"🎉 Daily goal reached: %d/%d pomodoros today!":                "🎉 達成每日目標：今天 %d/%d 個番茄鐘！",
+
+		// key help
+		"start/resume":                          "開始/繼續",
+		"pause":                                 "暫停",
+		"interruption":                          "中斷",
+		"skip":                                  "跳過",
+		"reset":                                 "重設",
+		"task":                                  "任務",
+		"task list":                             "任務清單",
+		"estimate":                              "預估",
+		"tags":                                  "標籤",
+		"profile":                               "設定檔",
+		"theme":                                 "主題",
+		"big clock":                             "大時鐘",
+		"zen mode":                              "禪模式",
+		"count up/down":                         "正數/倒數",
+		"mini mode":                             "迷你模式",
+		"stats":                                 "統計",
+		"heatmap":                               "熱度圖",
+		"history":                               "歷史",
+		"next timer":                            "下一個計時器",
+		"previous timer":                        "上一個計時器",
+		"acknowledge and take your break":       "確認並開始休息",
+		"keep working past quitting time today": "今天下班後繼續工作",
+		"quit":                                  "離開",
+		"keyboard help":                         "鍵盤說明",
+		"undo":                                  "復原",
+		"Keyboard help":                         "鍵盤說明",
+		"Timer":                                 "計時器",
+		"Views":                                 "檢視",
+		"Tasks":                                 "任務",
+		"History":                               "歷史",
+		"close":                                 "關閉",
+		"scroll":                                "捲動",
+
+		// the TUI
+		"Remaining: %s":                  "剩餘：%s",
+		"Elapsed: %s":                    "已過：%s",
+		"Completed: %d":                  "已完成：%d",
+		"Paused: %s":                     "暫停：%s",
+		"Interruptions: %d":              "中斷次數：%d",
+		"Profile: %s":                    "設定檔：%s",
+		"Task: %s":                       "任務：%s",
+		" (estimate %d 🍅)":               "（預估 %d 🍅）",
+		"Tags: %s":                       "標籤：%s",
+		"Break idea: %s":                 "休息點子：%s",
+		"Today: %d":                      "今天：%d",
+		"Today: %d/%d":                   "今天：%d/%d",
+		"Team: %s":                       "團隊：%s",
+		"Config not reloaded: %s":        "設定未重新載入：%s",
+		"Quitting time: done for today":  "下班時間：今天到此為止",
+		"OVERTIME +%s":                   "超時 +%s",
+		"FLOW":                           "心流",
+		"true":                           "是",
+		"false":                          "否",
+		"Start":                          "開始",
+		"Pause":                          "暫停",
+		"Skip":                           "跳過",
+		"Reset":                          "重設",
+		"Paused":                         "已暫停",
+		"Resumed":                        "已繼續",
+		"Stopped":                        "已停止",
+		"Timers: %s":                     "計時器：%s",
+		"Abandon current pomodoro?":      "要放棄目前的番茄鐘嗎？",
+		"Why? (optional)":                "原因？（可留空）",
+		"Nothing to undo.":               "沒有可以復原的動作。",
+		"leaderboard":                    "排行榜",
+		"Leaderboard":                    "排行榜",
+		"Leaderboard unreachable: %s":    "無法連上排行榜：%s",
+		"No pomodoros yet today.":        "今天還沒有番茄鐘。",
+		"Pause reason":                   "暫停原因",
+		"meeting":                        "會議",
+		"bio":                            "如廁等",
+		"other":                          "其他",
+		"Loading tasks…":                 "正在載入任務…",
+		"No tasks.":                      "沒有任務。",
+		"Loading tasks failed: %s":       "載入任務失敗：%s",
+		"Task":                           "任務",
+		"Task (some sources failed: %s)": "任務（部分來源失敗：%s）",
+		"(no task)":                      "（無任務）",
+		"Tags":                           "標籤",
+		"Profile":                        "設定檔",
+		"Theme":                          "主題",
+
+		// the break overlay
+		"%s: step away from the screen": "%s：離開螢幕休息一下",
+		"Press any key to return":       "按任意鍵返回",
+		"You can return in %ds":         "%d 秒後可以返回",
+	},
+	"ja": {
+		"WORK":        "作業",
+		"SHORT_BREAK": "小休憩",
+		"LONG_BREAK":  "長い休憩",
+		"IDLE":        "待機中",
+
+		"Phase: %s":                                                   "フェーズ：%s",
+		"%s left in %s":                                               "%[2]s 残り %[1]s",
+		"Work done, overtime running":                                 "作業終了、超過時間を計測中",
+		"Done, overtime running":                                      "終了、超過時間を計測中",
+		"Quitting time, done for today":                               "終業時間です。今日はここまで",
+		"Pomodoro done, well earned rest":                             "ポモドーロ完了、ゆっくり休みましょう",
+		"All %d pomodoros done, well earned rest":                     "%d 個のポモドーロをすべて完了、ゆっくり休みましょう",
+		"Work done, overtime running. Press [%s] to take your break.": "作業終了、超過時間を計測中。[%s] で休憩を始めます。",
+		"Quitting time, done for today. Press [%s] to keep working.":  "終業時間です。今日はここまで。[%s] で作業を続けます。",
+		"🎉 Daily goal reached: %d/%d pomodoros today!":                "🎉 今日の目標を達成：%d/%d ポモドーロ！",
+
+		"start/resume":                          "開始/再開",
+		"pause":                                 "一時停止",
+		"interruption":                          "中断",
+		"skip":                                  "スキップ",
+		"reset":                                 "リセット",
+		"task":                                  "タスク",
+		"task list":                             "タスク一覧",
+		"estimate":                              "見積もり",
+		"tags":                                  "タグ",
+		"profile":                               "プロファイル",
+		"theme":                                 "テーマ",
+		"big clock":                             "大きな時計",
+		"zen mode":                              "禅モード",
+		"count up/down":                         "カウントアップ/ダウン",
+		"mini mode":                             "ミニモード",
+		"stats":                                 "統計",
+		"heatmap":                               "ヒートマップ",
+		"history":                               "履歴",
+		"next timer":                            "次のタイマー",
+		"previous timer":                        "前のタイマー",
+		"acknowledge and take your break":       "確認して休憩する",
+		"keep working past quitting time today": "今日は終業後も作業を続ける",
+		"quit":                                  "終了",
+		"keyboard help":                         "キー操作のヘルプ",
+		"undo":                                  "元に戻す",
+		"Keyboard help":                         "キー操作のヘルプ",
+		"Timer":                                 "タイマー",
+		"Views":                                 "表示",
+		"Tasks":                                 "タスク",
+		"History":                               "履歴",
+		"close":                                 "閉じる",
+		"scroll":                                "スクロール",
+
+		"Remaining: %s":                  "残り：%s",
+		"Elapsed: %s":                    "経過：%s",
+		"Completed: %d":                  "完了：%d",
+		"Paused: %s":                     "一時停止：%s",
+		"Interruptions: %d":              "中断：%d",
+		"Profile: %s":                    "プロファイル：%s",
+		"Task: %s":                       "タスク：%s",
+		" (estimate %d 🍅)":               "（見積もり %d 🍅）",
+		"Tags: %s":                       "タグ：%s",
+		"Break idea: %s":                 "休憩のアイデア：%s",
+		"Today: %d":                      "今日：%d",
+		"Today: %d/%d":                   "今日：%d/%d",
+		"Team: %s":                       "チーム：%s",
+		"Config not reloaded: %s":        "設定を再読み込みできません：%s",
+		"Quitting time: done for today":  "終業時間：今日はここまで",
+		"OVERTIME +%s":                   "超過 +%s",
+		"FLOW":                           "フロー",
+		"true":                           "はい",
+		"false":                          "いいえ",
+		"Start":                          "開始",
+		"Pause":                          "一時停止",
+		"Skip":                           "スキップ",
+		"Reset":                          "リセット",
+		"Paused":                         "一時停止しました",
+		"Resumed":                        "再開しました",
+		"Stopped":                        "停止しました",
+		"Timers: %s":                     "タイマー：%s",
+		"Abandon current pomodoro?":      "現在のポモドーロを中止しますか？",
+		"Why? (optional)":                "理由は？（省略可）",
+		"Nothing to undo.":               "元に戻せる操作はありません。",
+		"leaderboard":                    "ランキング",
+		"Leaderboard":                    "ランキング",
+		"Leaderboard unreachable: %s":    "ランキングに接続できません：%s",
+		"No pomodoros yet today.":        "今日のポモドーロはまだありません。",
+		"Pause reason":                   "一時停止の理由",
+		"meeting":                        "会議",
+		"bio":                            "お手洗いなど",
+		"other":                          "その他",
+		"Loading tasks…":                 "タスクを読み込み中…",
+		"No tasks.":                      "タスクはありません。",
+		"Loading tasks failed: %s":       "タスクを読み込めません：%s",
+		"Task":                           "タスク",
+		"Task (some sources failed: %s)": "タスク（一部の取得元で失敗：%s）",
+		"(no task)":                      "（タスクなし）",
+		"Tags":                           "タグ",
+		"Profile":                        "プロファイル",
+		"Theme":                          "テーマ",
+
+		"%s: step away from the screen": "%s：画面から離れましょう",
+		"Press any key to return":       "何かキーを押すと戻ります",
+		"You can return in %ds":         "%d 秒後に戻れます",
+	},
+	"de": {
+		"WORK":        "ARBEIT",
+		"SHORT_BREAK": "KURZE PAUSE",
+		"LONG_BREAK":  "LANGE PAUSE",
+		"IDLE":        "BEREIT",
+
+		"Phase: %s":                                                   "Phase: %s",
+		"%s left in %s":                                               "Noch %s in %s",
+		"Work done, overtime running":                                 "Arbeit erledigt, Überzeit läuft",
+		"Done, overtime running":                                      "Fertig, Überzeit läuft",
+		"Quitting time, done for today":                               "Feierabend, für heute fertig",
+		"Pomodoro done, well earned rest":                             "Pomodoro geschafft, wohlverdiente Pause",
+		"All %d pomodoros done, well earned rest":                     "Alle %d Pomodoros geschafft, wohlverdiente Pause",
+		"Work done, overtime running. Press [%s] to take your break.": "Arbeit erledigt, Überzeit läuft. [%s] drücken, um Pause zu machen.",
+		"Quitting time, done for today. Press [%s] to keep working.":  "Feierabend, für heute fertig. [%s] drücken, um weiterzuarbeiten.",
+		"🎉 Daily goal reached: %d/%d pomodoros today!":                "🎉 Tagesziel erreicht: heute %d/%d Pomodoros!",
+
+		"start/resume":                          "starten/fortsetzen",
+		"pause":                                 "pausieren",
+		"interruption":                          "Unterbrechung",
+		"skip":                                  "überspringen",
+		"reset":                                 "zurücksetzen",
+		"task":                                  "Aufgabe",
+		"task list":                             "Aufgabenliste",
+		"estimate":                              "Schätzung",
+		"tags":                                  "Tags",
+		"profile":                               "Profil",
+		"theme":                                 "Design",
+		"big clock":                             "große Uhr",
+		"zen mode":                              "Zen-Modus",
+		"count up/down":                         "auf-/abwärts zählen",
+		"mini mode":                             "Minimodus",
+		"stats":                                 "Statistik",
+		"heatmap":                               "Heatmap",
+		"history":                               "Verlauf",
+		"next timer":                            "nächster Timer",
+		"previous timer":                        "vorheriger Timer",
+		"acknowledge and take your break":       "bestätigen und Pause machen",
+		"keep working past quitting time today": "heute nach Feierabend weiterarbeiten",
+		"quit":                                  "beenden",
+		"keyboard help":                         "Tastenhilfe",
+		"undo":                                  "rückgängig",
+		"Keyboard help":                         "Tastenhilfe",
+		"Timer":                                 "Timer",
+		"Views":                                 "Ansichten",
+		"Tasks":                                 "Aufgaben",
+		"History":                               "Verlauf",
+		"close":                                 "schließen",
+		"scroll":                                "blättern",
+
+		"Remaining: %s":                  "Verbleibend: %s",
+		"Elapsed: %s":                    "Vergangen: %s",
+		"Completed: %d":                  "Abgeschlossen: %d",
+		"Paused: %s":                     "Pausiert: %s",
+		"Interruptions: %d":              "Unterbrechungen: %d",
+		"Profile: %s":                    "Profil: %s",
+		"Task: %s":                       "Aufgabe: %s",
+		" (estimate %d 🍅)":               " (Schätzung %d 🍅)",
+		"Tags: %s":                       "Tags: %s",
+		"Break idea: %s":                 "Pausenidee: %s",
+		"Today: %d":                      "Heute: %d",
+		"Today: %d/%d":                   "Heute: %d/%d",
+		"Team: %s":                       "Team: %s",
+		"Config not reloaded: %s":        "Konfiguration nicht neu geladen: %s",
+		"Quitting time: done for today":  "Feierabend: für heute fertig",
+		"OVERTIME +%s":                   "ÜBERZEIT +%s",
+		"FLOW":                           "FLOW",
+		"true":                           "ja",
+		"false":                          "nein",
+		"Start":                          "Start",
+		"Pause":                          "Pause",
+		"Skip":                           "Überspringen",
+		"Reset":                          "Zurücksetzen",
+		"Paused":                         "Pausiert",
+		"Resumed":                        "Fortgesetzt",
+		"Stopped":                        "Gestoppt",
+		"Timers: %s":                     "Timer: %s",
+		"Abandon current pomodoro?":      "Aktuellen Pomodoro abbrechen?",
+		"Why? (optional)":                "Warum? (optional)",
+		"Nothing to undo.":               "Nichts rückgängig zu machen.",
+		"leaderboard":                    "Bestenliste",
+		"Leaderboard":                    "Bestenliste",
+		"Leaderboard unreachable: %s":    "Bestenliste nicht erreichbar: %s",
+		"No pomodoros yet today.":        "Heute noch keine Pomodoros.",
+		"Pause reason":                   "Grund der Pause",
+		"meeting":                        "Besprechung",
+		"bio":                            "Biopause",
+		"other":                          "Sonstiges",
+		"Loading tasks…":                 "Aufgaben werden geladen…",
+		"No tasks.":                      "Keine Aufgaben.",
+		"Loading tasks failed: %s":       "Aufgaben konnten nicht geladen werden: %s",
+		"Task":                           "Aufgabe",
+		"Task (some sources failed: %s)": "Aufgabe (einige Quellen fehlgeschlagen: %s)",
+		"(no task)":                      "(keine Aufgabe)",
+		"Tags":                           "Tags",
+		"Profile":                        "Profil",
+		"Theme":                          "Design",
+
+		"%s: step away from the screen": "%s: weg vom Bildschirm",
+		"Press any key to return":       "Beliebige Taste drücken, um zurückzukehren",
+		"You can return in %ds":         "Zurück in %d s",
+	},
+}
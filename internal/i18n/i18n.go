@@ -0,0 +1,111 @@
+// Package i18n translates what users read: phase names, the TUI's
+// labels and key help, and notifications. The English text is the
+// message key, so anything without a translation shows in English.
+package i18n
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// English is the source language, needing no catalog.
+const English = "en"
+
+// Locales are the supported locale tags, English first.
+var Locales = []string{English, "zh-TW", "ja", "de"}
+
+// Locale translates into one language. A nil *Locale is English.
+type Locale struct {
+	tag     string
+	catalog map[string]string
+}
+
+// New returns the locale for tag, e.g. "de", "zh-TW" or "ja_JP.UTF-8".
+func New(tag string) (*Locale, error) {
+	t, ok := normalize(tag)
+	if !ok {
+		return nil, fmt.Errorf("unknown locale %q (want one of %s)", tag, strings.Join(Locales, ", "))
+	}
+	return &Locale{tag: t, catalog: catalogs[t]}, nil
+}
+
+// Detect returns the locale the environment asks for through LC_ALL,
+// LC_MESSAGES or LANG, in that order, if it is supported; English
+// otherwise.
+func Detect(getenv func(string) string) *Locale {
+	for _, name := range []string{"LC_ALL", "LC_MESSAGES", "LANG"} {
+		v := getenv(name)
+		if v == "" {
+			continue
+		}
+		// the first one set decides, as in setlocale
+		l, err := New(v)
+		if err != nil {
+			return nil
+		}
+		return l
+	}
+	return nil
+}
+
+// normalize turns a BCP 47 tag or POSIX locale into one of Locales.
+func normalize(tag string) (string, bool) {
+	tag, _, _ = strings.Cut(tag, ".") // ja_JP.UTF-8
+	tag, _, _ = strings.Cut(tag, "@") // de_DE@euro
+	lang, region, _ := strings.Cut(strings.ReplaceAll(tag, "_", "-"), "-")
+	lang, region = strings.ToLower(lang), strings.ToUpper(region)
+	switch lang {
+	case "en", "c", "posix":
+		return English, true
+	case "ja", "de":
+		return lang, true
+	case "zh":
+		// Taiwan and Hong Kong write traditional characters
+		switch region {
+		case "TW", "HK", "MO", "HANT":
+			return "zh-TW", true
+		}
+	}
+	return "", false
+}
+
+// Tag is the locale's tag, one of Locales.
+func (l *Locale) Tag() string {
+	if l == nil {
+		return English
+	}
+	return l.tag
+}
+
+// T translates s.
+func (l *Locale) T(s string) string {
+	if l == nil {
+		return s
+	}
+	if t, ok := l.catalog[s]; ok {
+		return t
+	}
+	return s
+}
+
+// Sprintf formats the translation of format; translations may reorder
+// the arguments with %[n]s.
+func (l *Locale) Sprintf(format string, a ...any) string {
+	return fmt.Sprintf(l.T(format), a...)
+}
+
+// Phase is ph's display name, e.g. "WORK".
+func (l *Locale) Phase(ph core.Phase) string {
+	return l.T(ph.String())
+}
+
+// Name is State.Name translated: a custom step's name as it is, the
+// phase otherwise.
+func (l *Locale) Name(st core.State) string {
+	if st.Label != "" {
+		return st.Label
+	}
+	return l.Phase(st.Phase)
+}
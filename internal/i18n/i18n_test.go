@@ -0,0 +1,103 @@
+package i18n
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestNew_Normalizes(t *testing.T) {
+	for in, want := range map[string]string{
+		"de": "de", "de_DE.UTF-8": "de", "de_AT@euro": "de",
+		"ja_JP.UTF-8": "ja", "zh_TW.UTF-8": "zh-TW", "zh-Hant": "zh-TW", "zh_HK": "zh-TW",
+		"C": "en", "en_US.UTF-8": "en",
+	} {
+		l, err := New(in)
+		if err != nil || l.Tag() != want {
+			t.Errorf("%q: got %q, %v, want %q", in, l.Tag(), err, want)
+		}
+	}
+	for _, bad := range []string{"fr_FR", "zh_CN", ""} {
+		if _, err := New(bad); err == nil {
+			t.Errorf("%q accepted", bad)
+		}
+	}
+}
+
+func TestDetect(t *testing.T) {
+	env := func(vars map[string]string) func(string) string {
+		return func(k string) string { return vars[k] }
+	}
+	if got := Detect(env(map[string]string{"LANG": "ja_JP.UTF-8"})).Tag(); got != "ja" {
+		t.Errorf("LANG: got %s", got)
+	}
+	if got := Detect(env(map[string]string{"LC_ALL": "de_DE.UTF-8", "LANG": "ja_JP.UTF-8"})).Tag(); got != "de" {
+		t.Errorf("LC_ALL first: got %s", got)
+	}
+	if got := Detect(env(map[string]string{"LC_MESSAGES": "fr_FR.UTF-8", "LANG": "ja_JP.UTF-8"})).Tag(); got != English {
+		t.Errorf("unsupported LC_MESSAGES: got %s, want English", got)
+	}
+}
+
+func TestLocale_Translates(t *testing.T) {
+	var en *Locale
+	if got := en.Sprintf("%s left in %s", "2m", en.Phase(core.PhaseWork)); got != "2m left in WORK" {
+		t.Errorf("English: got %q", got)
+	}
+	zh, _ := New("zh-TW")
+	if got := zh.Sprintf("%s left in %s", "2m", zh.Phase(core.PhaseWork)); got != "工作 還剩 2m" {
+		t.Errorf("zh-TW: got %q", got)
+	}
+	de, _ := New("de")
+	if got := de.Name(core.State{Phase: core.PhaseShortBreak}); got != "KURZE PAUSE" {
+		t.Errorf("de phase: got %q", got)
+	}
+	if got := de.Name(core.State{Phase: core.PhaseShortBreak, Label: "stretch"}); got != "stretch" {
+		t.Errorf("custom step: got %q", got)
+	}
+	if got := de.T("no such text"); got != "no such text" {
+		t.Errorf("fallback: got %q", got)
+	}
+}
+
+// TestCatalogs_Complete checks that every locale translates the same
+// texts, with the same verbs.
+func TestCatalogs_Complete(t *testing.T) {
+	ref := catalogs["zh-TW"]
+	for tag, cat := range catalogs {
+		for key, text := range cat {
+			if _, ok := ref[key]; !ok {
+				t.Errorf("%s: %q is missing from zh-TW", tag, key)
+			}
+			if verbs(key) != verbs(text) {
+				t.Errorf("%s: %q has other verbs than %q", tag, text, key)
+			}
+		}
+		for key := range ref {
+			if _, ok := cat[key]; !ok {
+				t.Errorf("%s: no translation for %q", tag, key)
+			}
+		}
+	}
+}
+
+// verbs lists the formatting verbs in s, ignoring argument indexes.
+func verbs(s string) string {
+	var out []string
+	for i := 0; i < len(s); i++ {
+		if s[i] != '%' || i+1 >= len(s) {
+			continue
+		}
+		j := i + 1
+		if s[j] == '[' {
+			j = strings.IndexByte(s[j:], ']') + j + 1
+		}
+		out = append(out, string(s[j]))
+		i = j
+	}
+	slices.Sort(out)
+	return fmt.Sprint(out)
+}
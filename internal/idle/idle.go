@@ -0,0 +1,56 @@
+// Package idle measures how long the user has been away from the
+// keyboard and mouse, and pauses work phases when they walk away.
+package idle
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrUnsupported is returned by New when no backend works on this
+// system.
+var ErrUnsupported = errors.New("idle detection is not supported on this system")
+
+// Detector reports the time since the last user input.
+type Detector interface {
+	Idle() (time.Duration, error)
+}
+
+// DetectorFunc adapts a function to a Detector.
+type DetectorFunc func() (time.Duration, error)
+
+func (f DetectorFunc) Idle() (time.Duration, error) { return f() }
+
+// New returns the first backend that works on this system.
+func New() (Detector, error) {
+	for _, d := range backends() {
+		if _, err := d.Idle(); err == nil {
+			return d, nil
+		}
+	}
+	return nil, ErrUnsupported
+}
+
+// command runs name with args and parses a number from its output with
+// parse.
+func command(parse func(string) (time.Duration, error), name string, args ...string) Detector {
+	return DetectorFunc(func() (time.Duration, error) {
+		out, err := exec.Command(name, args...).Output()
+		if err != nil {
+			return 0, err
+		}
+		return parse(strings.TrimSpace(string(out)))
+	})
+}
+
+// millis parses a plain millisecond count, as printed by xprintidle.
+func millis(s string) (time.Duration, error) {
+	ms, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ms) * time.Millisecond, nil
+}
@@ -0,0 +1,28 @@
+package idle
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+var hidIdleRe = regexp.MustCompile(`"HIDIdleTime" = (\d+)`)
+
+// backends reads HIDIdleTime (nanoseconds) from the IOHIDSystem registry
+// entry.
+func backends() []Detector {
+	return []Detector{command(hidIdle, "ioreg", "-c", "IOHIDSystem", "-d", "4")}
+}
+
+func hidIdle(s string) (time.Duration, error) {
+	m := hidIdleRe.FindStringSubmatch(s)
+	if m == nil {
+		return 0, errors.New("ioreg: HIDIdleTime not found")
+	}
+	ns, err := strconv.ParseInt(m[1], 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	return time.Duration(ns), nil
+}
@@ -0,0 +1,32 @@
+package idle
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// backends tries GNOME's Mutter idle monitor (X11 and Wayland), then
+// xprintidle for other X11 desktops.
+func backends() []Detector {
+	return []Detector{
+		command(mutterIdle, "gdbus", "call", "--session",
+			"--dest", "org.gnome.Mutter.IdleMonitor",
+			"--object-path", "/org/gnome/Mutter/IdleMonitor/Core",
+			"--method", "org.gnome.Mutter.IdleMonitor.GetIdletime"),
+		command(millis, "xprintidle"),
+	}
+}
+
+// mutterIdle parses gdbus output such as "(uint64 12345,)".
+func mutterIdle(s string) (time.Duration, error) {
+	s = strings.TrimPrefix(s, "(")
+	s = strings.TrimSuffix(s, ")")
+	s = strings.TrimSuffix(s, ",")
+	s = strings.TrimPrefix(s, "uint64 ")
+	d, err := millis(s)
+	if err != nil {
+		return 0, fmt.Errorf("mutter idle monitor: %w", err)
+	}
+	return d, nil
+}
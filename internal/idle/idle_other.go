@@ -0,0 +1,5 @@
+//go:build !linux && !darwin && !windows
+
+package idle
+
+func backends() []Detector { return nil }
@@ -0,0 +1,35 @@
+package idle
+
+import (
+	"errors"
+	"syscall"
+	"time"
+	"unsafe"
+)
+
+var (
+	user32               = syscall.NewLazyDLL("user32.dll")
+	kernel32             = syscall.NewLazyDLL("kernel32.dll")
+	procGetLastInputInfo = user32.NewProc("GetLastInputInfo")
+	procGetTickCount     = kernel32.NewProc("GetTickCount")
+)
+
+type lastInputInfo struct {
+	cbSize uint32
+	dwTime uint32
+}
+
+// backends uses GetLastInputInfo, which covers the whole session.
+func backends() []Detector {
+	return []Detector{DetectorFunc(lastInput)}
+}
+
+func lastInput() (time.Duration, error) {
+	info := lastInputInfo{cbSize: uint32(unsafe.Sizeof(lastInputInfo{}))}
+	if r, _, err := procGetLastInputInfo.Call(uintptr(unsafe.Pointer(&info))); r == 0 {
+		return 0, errors.Join(errors.New("GetLastInputInfo failed"), err)
+	}
+	now, _, _ := procGetTickCount.Call()
+	// both are 32-bit millisecond tick counts; the subtraction wraps
+	return time.Duration(uint32(now)-info.dwTime) * time.Millisecond, nil
+}
@@ -0,0 +1,109 @@
+package idle
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// ReturnAction says what happens when the user comes back to an
+// automatically paused work phase.
+type ReturnAction int
+
+const (
+	// ReturnResume resumes the phase where it was paused.
+	ReturnResume ReturnAction = iota
+	// ReturnDiscard drops the phase and starts a fresh work phase.
+	ReturnDiscard
+	// ReturnStay leaves the phase paused until resumed by hand.
+	ReturnStay
+)
+
+// ParseReturnAction maps "resume", "discard" or "stay" to a ReturnAction.
+func ParseReturnAction(s string) (ReturnAction, error) {
+	switch s {
+	case "", "resume":
+		return ReturnResume, nil
+	case "discard":
+		return ReturnDiscard, nil
+	case "stay":
+		return ReturnStay, nil
+	}
+	return 0, fmt.Errorf("unknown idle return action %q", s)
+}
+
+// Options configures a Watcher.
+type Options struct {
+	After    time.Duration // idle time before a work phase is paused
+	Poll     time.Duration // how often to check; default 5s
+	OnReturn ReturnAction
+	OnError  func(error)
+}
+
+// Watcher pauses running work phases once the user has been idle for
+// Options.After and acts on their return. It only touches pauses it
+// made itself.
+type Watcher struct {
+	det  Detector
+	eng  *core.PomodoroEngine
+	opts Options
+
+	paused bool // the current pause is ours
+}
+
+// NewWatcher creates a Watcher for eng.
+func NewWatcher(det Detector, eng *core.PomodoroEngine, opts Options) *Watcher {
+	if opts.Poll <= 0 {
+		opts.Poll = 5 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	return &Watcher{det: det, eng: eng, opts: opts}
+}
+
+// Run polls the detector until ctx is done.
+func (w *Watcher) Run(ctx context.Context) {
+	t := time.NewTicker(w.opts.Poll)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.C:
+			w.poll()
+		}
+	}
+}
+
+func (w *Watcher) poll() {
+	idle, err := w.det.Idle()
+	if err != nil {
+		w.opts.OnError(err)
+		return
+	}
+	st := w.eng.State()
+	if w.paused && (!st.Paused || st.PauseReason != core.ReasonIdle) {
+		// resumed, stopped or re-tagged by hand meanwhile
+		w.paused = false
+	}
+
+	switch {
+	case !w.paused && idle >= w.opts.After:
+		if st.Phase != core.PhaseWork || st.Paused || st.Overtime {
+			return
+		}
+		// strict mode may refuse
+		w.paused = w.eng.PauseWithReason(core.ReasonIdle) == nil
+	case w.paused && idle < w.opts.After:
+		w.paused = false
+		switch w.opts.OnReturn {
+		case ReturnResume:
+			w.eng.Resume()
+		case ReturnDiscard:
+			w.eng.Start()
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package idle
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestWatcher_PausesAndResumes(t *testing.T) {
+	for _, tc := range []struct {
+		action     ReturnAction
+		wantPaused bool
+	}{
+		{ReturnResume, false},
+		{ReturnStay, true},
+		{ReturnDiscard, false},
+	} {
+		eng := core.New(core.Config{Work: time.Hour, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4})
+		var idle time.Duration
+		w := NewWatcher(DetectorFunc(func() (time.Duration, error) { return idle, nil }), eng,
+			Options{After: 5 * time.Minute, OnReturn: tc.action})
+
+		w.poll() // idle engine: nothing to pause
+		eng.Start()
+		idle = 6 * time.Minute
+		w.poll()
+		if st := eng.State(); !st.Paused || st.PauseReason != core.ReasonIdle {
+			t.Fatalf("%v: expected idle pause, got %+v", tc.action, st)
+		}
+
+		idle = time.Second
+		w.poll()
+		if st := eng.State(); st.Paused != tc.wantPaused {
+			t.Fatalf("%v: paused=%v after return", tc.action, st.Paused)
+		}
+		eng.Stop()
+	}
+}
+
+func TestWatcher_LeavesManualPauses(t *testing.T) {
+	eng := core.New(core.Config{Work: time.Hour, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4})
+	var idle time.Duration
+	w := NewWatcher(DetectorFunc(func() (time.Duration, error) { return idle, nil }), eng,
+		Options{After: time.Minute})
+	defer eng.Stop()
+
+	eng.Start()
+	eng.PauseWithReason(core.ReasonMeeting)
+	idle = time.Hour
+	w.poll()
+	idle = 0
+	w.poll()
+	if st := eng.State(); !st.Paused || st.PauseReason != core.ReasonMeeting {
+		t.Fatalf("manual pause should be left alone, got %+v", st)
+	}
+}
@@ -0,0 +1,189 @@
+// Package gcal blocks time on Google Calendar: a "Focus" event is
+// created when a work phase starts and trimmed to what was actually
+// worked when it ends.
+package gcal
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the Calendar API v3 endpoint.
+const DefaultBaseURL = "https://www.googleapis.com/calendar/v3/"
+
+// ErrNotLoggedIn is returned when there is no saved token.
+var ErrNotLoggedIn = errors.New("google calendar: not logged in (run gopomodoro gcal login)")
+
+// Client is a minimal Calendar API client. It loads the token saved by
+// the login from TokenPath and refreshes it as needed.
+type Client struct {
+	Auth      Auth
+	TokenPath string
+	Calendar  string       // calendar ID; default "primary"
+	BaseURL   string       // defaults to DefaultBaseURL
+	HTTP      *http.Client // defaults to http.DefaultClient
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewClient returns a client for the OAuth app clientID that keeps its
+// token at tokenPath.
+func NewClient(clientID, clientSecret, tokenPath string) *Client {
+	return &Client{Auth: Auth{ClientID: clientID, ClientSecret: clientSecret}, TokenPath: tokenPath}
+}
+
+// APIError is a non-2xx response.
+type APIError struct {
+	Path    string
+	Status  int
+	Message string
+}
+
+func (e *APIError) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("google calendar %s: status %d: %s", e.Path, e.Status, e.Message)
+	}
+	return fmt.Sprintf("google calendar %s: status %d", e.Path, e.Status)
+}
+
+// Event is the subset of a calendar event GoPomodoro sets.
+type Event struct {
+	ID           string    `json:"id,omitempty"`
+	Summary      string    `json:"summary,omitempty"`
+	Description  string    `json:"description,omitempty"`
+	Start        *EventAt  `json:"start,omitempty"`
+	End          *EventAt  `json:"end,omitempty"`
+	Transparency string    `json:"transparency,omitempty"` // "opaque" shows as busy
+	Reminders    *Reminder `json:"reminders,omitempty"`
+}
+
+// EventAt is an event's start or end.
+type EventAt struct {
+	DateTime time.Time `json:"dateTime"`
+}
+
+// Reminder turns off the calendar's own popups for the event.
+type Reminder struct {
+	UseDefault bool `json:"useDefault"`
+}
+
+// Insert creates ev and returns it with its ID.
+func (c *Client) Insert(ctx context.Context, ev Event) (Event, error) {
+	var out Event
+	err := c.do(ctx, http.MethodPost, c.events(""), ev, &out)
+	return out, err
+}
+
+// SetEnd moves the end of event id.
+func (c *Client) SetEnd(ctx context.Context, id string, end time.Time) error {
+	return c.do(ctx, http.MethodPatch, c.events(id), Event{End: &EventAt{DateTime: end}}, nil)
+}
+
+// Delete removes event id.
+func (c *Client) Delete(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodDelete, c.events(id), nil, nil)
+}
+
+func (c *Client) events(id string) string {
+	p := "calendars/" + url.PathEscape(cmp.Or(c.Calendar, "primary")) + "/events"
+	if id != "" {
+		p += "/" + url.PathEscape(id)
+	}
+	return p
+}
+
+// accessToken returns a valid access token, refreshing and saving it
+// when it is about to expire or force is set.
+func (c *Client) accessToken(ctx context.Context, force bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == nil {
+		t, err := LoadToken(c.TokenPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", ErrNotLoggedIn
+		}
+		if err != nil {
+			return "", err
+		}
+		c.token = t
+	}
+	if force || time.Until(c.token.Expiry) < time.Minute {
+		t, err := c.Auth.Refresh(ctx, c.token.RefreshToken)
+		if err != nil {
+			return "", err
+		}
+		c.token = t
+		if err := SaveToken(c.TokenPath, t); err != nil {
+			return "", err
+		}
+	}
+	return c.token.AccessToken, nil
+}
+
+// do sends a JSON request and decodes the response into out, if set,
+// refreshing the token once on 401.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	base := cmp.Or(c.BaseURL, DefaultBaseURL)
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	for refreshed := false; ; refreshed = true {
+		token, err := c.accessToken(ctx, refreshed)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, method, base+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("google calendar %s: %w", path, err)
+		}
+		if resp.StatusCode == http.StatusUnauthorized && !refreshed {
+			resp.Body.Close()
+			continue
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			var e struct {
+				Error struct {
+					Message string `json:"message"`
+				} `json:"error"`
+			}
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			_ = json.Unmarshal(data, &e)
+			return &APIError{Path: path, Status: resp.StatusCode, Message: e.Error.Message}
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("google calendar %s: %w", path, err)
+		}
+		return nil
+	}
+}
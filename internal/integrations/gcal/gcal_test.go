@@ -0,0 +1,189 @@
+package gcal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// fakeGoogle serves the OAuth endpoints and the events API.
+type fakeGoogle struct {
+	mu       sync.Mutex
+	calls    []string
+	pending  int    // device polls answered with authorization_pending
+	rejectAt string // access token answered with 401 once
+	refresh  int
+}
+
+func (f *fakeGoogle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch r.URL.Path {
+	case "/oauth/device/code":
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"device_code": "dev", "user_code": "ABCD-EFGH",
+			"verification_url": "https://www.google.com/device", "expires_in": 60, "interval": 0,
+		})
+		return
+	case "/oauth/token":
+		_ = r.ParseForm()
+		switch r.PostForm.Get("grant_type") {
+		case "refresh_token":
+			f.refresh++
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "fresh", "expires_in": 3600})
+		default:
+			if f.pending > 0 {
+				f.pending--
+				w.WriteHeader(http.StatusPreconditionRequired)
+				_, _ = io.WriteString(w, `{"error":"authorization_pending"}`)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "a", "refresh_token": "r", "expires_in": 3600})
+		}
+		return
+	}
+	if auth := r.Header.Get("Authorization"); auth == "Bearer "+f.rejectAt {
+		f.rejectAt = ""
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	f.calls = append(f.calls, strings.TrimSpace(r.Method+" "+r.URL.Path+" "+string(body)))
+	switch {
+	case r.Method == http.MethodPost:
+		_ = json.NewEncoder(w).Encode(map[string]any{"id": "ev1"})
+	case r.Method == http.MethodDelete:
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		_, _ = io.WriteString(w, "{}")
+	}
+}
+
+func (f *fakeGoogle) got() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+func newTestClient(t *testing.T, f *fakeGoogle, token Token) *Client {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	path := filepath.Join(t.TempDir(), "token.json")
+	if err := SaveToken(path, &token); err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient("app", "secret", path)
+	c.BaseURL = srv.URL + "/v3/"
+	c.Auth.OAuthURL = srv.URL + "/oauth/"
+	return c
+}
+
+func TestAuth_DeviceFlow(t *testing.T) {
+	f := &fakeGoogle{pending: 2}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	a := Auth{ClientID: "app", OAuthURL: srv.URL + "/oauth/"}
+	dc, err := a.Start(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dc.UserCode != "ABCD-EFGH" {
+		t.Fatalf("user code %q", dc.UserCode)
+	}
+	dc.Interval = 0
+	tok, err := a.Poll(context.Background(), dc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tok.AccessToken != "a" || tok.RefreshToken != "r" || f.pending != 0 {
+		t.Fatalf("token %+v after %d pending", tok, f.pending)
+	}
+}
+
+func TestClient_RefreshesOn401(t *testing.T) {
+	f := &fakeGoogle{rejectAt: "old"}
+	c := newTestClient(t, f, Token{AccessToken: "old", RefreshToken: "r", Expiry: time.Now().Add(time.Hour)})
+	if err := c.Delete(context.Background(), "x"); err != nil {
+		t.Fatal(err)
+	}
+	if f.refresh != 1 {
+		t.Fatalf("refreshed %d times", f.refresh)
+	}
+	saved, err := LoadToken(c.TokenPath)
+	if err != nil || saved.AccessToken != "fresh" || saved.RefreshToken != "r" {
+		t.Fatalf("saved %+v, %v", saved, err)
+	}
+}
+
+func TestClient_NotLoggedIn(t *testing.T) {
+	c := NewClient("app", "", filepath.Join(t.TempDir(), "none.json"))
+	if _, err := c.Insert(context.Background(), Event{}); err != ErrNotLoggedIn {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestTracker_CreatesAndTrims(t *testing.T) {
+	f := &fakeGoogle{}
+	c := newTestClient(t, f, Token{AccessToken: "a", Expiry: time.Now().Add(time.Hour)})
+	var errs []error
+	tr := NewTracker(c, Options{ShowTask: true, OnError: func(err error) { errs = append(errs, err) }})
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	work := core.State{Phase: core.PhaseWork, StartedAt: start, EndsAt: start.Add(25 * time.Minute),
+		Length: 25 * time.Minute, Task: core.Task{Title: "Report"}}
+	tr.Handle(core.Event{Kind: core.EventStart, State: work, At: start})
+	work.EndsAt = start.Add(30 * time.Minute)
+	tr.Handle(core.Event{Kind: core.EventExtend, State: work, At: start.Add(time.Minute)})
+	brk := core.State{Phase: core.PhaseShortBreak, StartedAt: start.Add(20 * time.Minute)}
+	tr.Handle(core.Event{Kind: core.EventSkip, State: brk, At: start.Add(20 * time.Minute)})
+
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	calls := f.got()
+	if len(calls) != 3 {
+		t.Fatalf("calls %q", calls)
+	}
+	for i, want := range []string{
+		`POST /v3/calendars/primary/events {"summary":"Focus: Report"`,
+		`PATCH /v3/calendars/primary/events/ev1 {"end":{"dateTime":"2026-03-02T09:30:00Z"}}`,
+		`PATCH /v3/calendars/primary/events/ev1 {"end":{"dateTime":"2026-03-02T09:20:00Z"}}`,
+	} {
+		if !strings.HasPrefix(calls[i], want) {
+			t.Errorf("call %d = %q, want %q…", i, calls[i], want)
+		}
+	}
+	if !strings.Contains(calls[0], `"end":{"dateTime":"2026-03-02T09:25:00Z"}`) || !strings.Contains(calls[0], `"transparency":"opaque"`) {
+		t.Errorf("insert %q", calls[0])
+	}
+}
+
+func TestTracker_DeletesShortAbandoned(t *testing.T) {
+	f := &fakeGoogle{}
+	c := newTestClient(t, f, Token{AccessToken: "a", Expiry: time.Now().Add(time.Hour)})
+	tr := NewTracker(c, Options{})
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	work := core.State{Phase: core.PhaseWork, StartedAt: start, EndsAt: start.Add(25 * time.Minute)}
+	tr.Handle(core.Event{Kind: core.EventStart, State: work, At: start})
+	tr.Handle(core.Event{Kind: core.EventAbandon, State: work, At: start.Add(20 * time.Second)})
+	tr.Handle(core.Event{Kind: core.EventStop, At: start.Add(20 * time.Second)})
+
+	calls := f.got()
+	if len(calls) != 2 || calls[1] != "DELETE /v3/calendars/primary/events/ev1" {
+		t.Fatalf("calls %q", calls)
+	}
+	if !strings.Contains(calls[0], `"summary":"Focus"`) {
+		t.Errorf("insert %q", calls[0])
+	}
+}
@@ -0,0 +1,194 @@
+package gcal
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultOAuthURL is Google's OAuth 2.0 server.
+	DefaultOAuthURL = "https://oauth2.googleapis.com/"
+	// Scope lets GoPomodoro manage events, and nothing else.
+	Scope = "https://www.googleapis.com/auth/calendar.events"
+)
+
+// Token is an OAuth token, saved as JSON between runs.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Auth runs the OAuth device flow, which suits a terminal program: the
+// user enters a code on another device. It needs an OAuth client of type
+// "TVs and Limited Input devices".
+type Auth struct {
+	ClientID     string
+	ClientSecret string
+	OAuthURL     string // defaults to DefaultOAuthURL
+	HTTP         *http.Client
+}
+
+// DeviceCode is what the user needs to grant access.
+type DeviceCode struct {
+	DeviceCode      string `json:"device_code"`
+	UserCode        string `json:"user_code"`
+	VerificationURL string `json:"verification_url"`
+	ExpiresIn       int    `json:"expires_in"`
+	Interval        int    `json:"interval"`
+}
+
+// Start asks for a device and user code.
+func (a Auth) Start(ctx context.Context) (*DeviceCode, error) {
+	var dc DeviceCode
+	if err := a.post(ctx, "device/code", url.Values{"client_id": {a.ClientID}, "scope": {Scope}}, &dc); err != nil {
+		return nil, err
+	}
+	return &dc, nil
+}
+
+// Poll waits until the user has granted (or denied) access.
+func (a Auth) Poll(ctx context.Context, dc *DeviceCode) (*Token, error) {
+	interval := time.Duration(max(dc.Interval, 1)) * time.Second
+	deadline := time.Now().Add(time.Duration(dc.ExpiresIn) * time.Second)
+	for {
+		if err := sleep(ctx, interval); err != nil {
+			return nil, err
+		}
+		t, err := a.token(ctx, url.Values{
+			"client_id":     {a.ClientID},
+			"client_secret": {a.ClientSecret},
+			"device_code":   {dc.DeviceCode},
+			"grant_type":    {"urn:ietf:params:oauth:grant-type:device_code"},
+		})
+		var oe *oauthError
+		switch {
+		case err == nil:
+			return t, nil
+		case errors.As(err, &oe) && oe.Code == "authorization_pending":
+		case errors.As(err, &oe) && oe.Code == "slow_down":
+			interval += 5 * time.Second
+		default:
+			return nil, err
+		}
+		if dc.ExpiresIn > 0 && time.Now().After(deadline) {
+			return nil, errors.New("google login: the code expired")
+		}
+	}
+}
+
+// Refresh gets a new access token.
+func (a Auth) Refresh(ctx context.Context, refresh string) (*Token, error) {
+	t, err := a.token(ctx, url.Values{
+		"client_id":     {a.ClientID},
+		"client_secret": {a.ClientSecret},
+		"refresh_token": {refresh},
+		"grant_type":    {"refresh_token"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.RefreshToken = cmp.Or(t.RefreshToken, refresh)
+	return t, nil
+}
+
+// oauthError is an error response from the token endpoint.
+type oauthError struct {
+	Code        string `json:"error"`
+	Description string `json:"error_description"`
+}
+
+func (e *oauthError) Error() string {
+	return strings.TrimSpace("google oauth: " + e.Code + " " + e.Description)
+}
+
+func (a Auth) token(ctx context.Context, form url.Values) (*Token, error) {
+	var out struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+	}
+	if err := a.post(ctx, "token", form, &out); err != nil {
+		return nil, err
+	}
+	return &Token{
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(out.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func (a Auth) post(ctx context.Context, path string, form url.Values, out any) error {
+	client := a.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cmp.Or(a.OAuthURL, DefaultOAuthURL)+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("google oauth: %w", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		oe := &oauthError{}
+		if json.Unmarshal(data, oe) != nil || oe.Code == "" {
+			oe.Code = resp.Status
+		}
+		return oe
+	}
+	return json.Unmarshal(data, out)
+}
+
+// LoadToken reads a token saved with SaveToken.
+func LoadToken(path string) (*Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t Token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("google token %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// SaveToken writes t to path, readable only by the user.
+func SaveToken(path string, t *Token) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
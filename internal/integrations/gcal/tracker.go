@@ -0,0 +1,115 @@
+package gcal
+
+import (
+	"cmp"
+	"context"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Options configures a Tracker.
+type Options struct {
+	Summary string // event title; default "Focus"
+	// ShowTask appends the attached task to the title. Off by default,
+	// since colleagues can usually see the event.
+	ShowTask bool
+	// MinLength is the shortest abandoned pomodoro kept on the
+	// calendar; shorter ones are deleted. Default one minute.
+	MinLength time.Duration
+	Timeout   time.Duration // per event; default 10s
+	OnError   func(error)
+}
+
+// Tracker keeps a busy event on the calendar for each work phase: it is
+// created for the planned length when the phase starts, moved when the
+// phase is extended or paused, and trimmed to the actual end when the
+// phase is over. Subscribe its Handle method to an engine.
+type Tracker struct {
+	client *Client
+	opts   Options
+	id     string    // event of the running work phase
+	start  time.Time // when that phase started
+}
+
+// NewTracker creates a Tracker using client.
+func NewTracker(client *Client, opts Options) *Tracker {
+	if opts.Summary == "" {
+		opts.Summary = "Focus"
+	}
+	if opts.MinLength <= 0 {
+		opts.MinLength = time.Minute
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	return &Tracker{client: client, opts: opts}
+}
+
+// Handle consumes one engine event. It calls the API, which is fine on
+// a subscriber's own goroutine.
+func (t *Tracker) Handle(ev core.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.opts.Timeout)
+	defer cancel()
+	st := ev.State
+
+	switch ev.Kind {
+	case core.EventAdvance, core.EventSkip, core.EventStop, core.EventStart:
+		t.finish(ctx, ev.At, false)
+	case core.EventAbandon:
+		t.finish(ctx, ev.At, true)
+		return
+	case core.EventExtend, core.EventResume:
+		if t.id != "" && st.Phase == core.PhaseWork && !st.Overtime {
+			if err := t.client.SetEnd(ctx, t.id, st.EndsAt); err != nil {
+				t.opts.OnError(err)
+			}
+		}
+	}
+	if t.id == "" && st.Phase == core.PhaseWork && !st.Paused && !st.Overtime {
+		t.begin(ctx, st)
+	}
+}
+
+func (t *Tracker) begin(ctx context.Context, st core.State) {
+	summary := t.opts.Summary
+	if t.opts.ShowTask && st.Task.Title != "" {
+		summary += ": " + st.Task.Title
+	}
+	end := cmp.Or(st.EndsAt, st.StartedAt.Add(st.Length))
+	ev, err := t.client.Insert(ctx, Event{
+		Summary:      summary,
+		Description:  "Blocked by GoPomodoro.",
+		Start:        &EventAt{DateTime: st.StartedAt},
+		End:          &EventAt{DateTime: end},
+		Transparency: "opaque",
+		Reminders:    &Reminder{},
+	})
+	if err != nil {
+		t.opts.OnError(err)
+		return
+	}
+	t.id, t.start = ev.ID, st.StartedAt
+}
+
+// finish ends the running event at end. Abandoned phases shorter than
+// MinLength are deleted instead.
+func (t *Tracker) finish(ctx context.Context, end time.Time, abandoned bool) {
+	if t.id == "" {
+		return
+	}
+	id := t.id
+	t.id = ""
+	var err error
+	if abandoned && end.Sub(t.start) < t.opts.MinLength {
+		err = t.client.Delete(ctx, id)
+	} else {
+		err = t.client.SetEnd(ctx, id, end)
+	}
+	if err != nil {
+		t.opts.OnError(err)
+	}
+}
@@ -0,0 +1,145 @@
+// Package git annotates commits with pomodoro context: hooks that add a
+// "Pomodoros: N" trailer to commit messages and record each commit in
+// a journal, from which work sessions are attributed to repositories.
+package git
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrailerKey is the commit message trailer the prepare-commit-msg hook
+// adds.
+const TrailerKey = "Pomodoros"
+
+// Hooks the installer writes: PrepareHook adds the trailer, CommitHook
+// records the commit.
+const (
+	PrepareHook = "prepare-commit-msg"
+	CommitHook  = "post-commit"
+)
+
+// marker is the line identifying a hook as ours.
+const marker = "# installed by gopomodoro"
+
+// Repo runs git in a work tree.
+type Repo struct {
+	// Dir is a directory inside the work tree; "" is the current one.
+	Dir string
+	// Run executes git; tests replace it. Nil runs the real command.
+	Run func(ctx context.Context, dir string, args ...string) ([]byte, error)
+}
+
+func (r *Repo) git(ctx context.Context, args ...string) ([]byte, error) {
+	if r.Run != nil {
+		return r.Run(ctx, r.Dir, args...)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "git", args...)
+	cmd.Dir = r.Dir
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return out, fmt.Errorf("git %s: %w: %s", args[0], err, msg)
+		}
+		return out, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
+
+// Root returns the top directory of the work tree.
+func (r *Repo) Root(ctx context.Context) (string, error) {
+	out, err := r.git(ctx, "rev-parse", "--show-toplevel")
+	return strings.TrimSpace(string(out)), err
+}
+
+// HooksDir returns the directory git runs hooks from, honoring
+// core.hooksPath.
+func (r *Repo) HooksDir(ctx context.Context) (string, error) {
+	out, err := r.git(ctx, "rev-parse", "--path-format=absolute", "--git-path", "hooks")
+	return strings.TrimSpace(string(out)), err
+}
+
+// Head returns the hash and commit time of HEAD. A repository without
+// commits yet has neither.
+func (r *Repo) Head(ctx context.Context) (string, time.Time, error) {
+	if _, err := r.git(ctx, "rev-parse", "--verify", "--quiet", "HEAD"); err != nil {
+		return "", time.Time{}, nil
+	}
+	out, err := r.git(ctx, "log", "-1", "--format=%H %ct", "HEAD")
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	hash, ts, _ := strings.Cut(strings.TrimSpace(string(out)), " ")
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("git log: bad commit time %q", ts)
+	}
+	return hash, time.Unix(sec, 0), nil
+}
+
+// AddTrailer sets the Pomodoros trailer of the commit message in file
+// to n, replacing one already there.
+func (r *Repo) AddTrailer(ctx context.Context, file string, n int) error {
+	_, err := r.git(ctx, "interpret-trailers", "--in-place", "--if-exists", "replace",
+		"--trailer", fmt.Sprintf("%s: %d", TrailerKey, n), file)
+	return err
+}
+
+// Install writes the named hooks into dir, each running command with
+// the hook's name and arguments appended, e.g. "gopomodoro git hook".
+// Hooks not installed by gopomodoro are left alone and reported as an
+// error; ours are replaced.
+func Install(dir string, command []string, hooks ...string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	quoted := make([]string, len(command))
+	for i, arg := range command {
+		quoted[i] = shellQuote(arg)
+	}
+	for _, name := range hooks {
+		path := filepath.Join(dir, name)
+		if old, err := os.ReadFile(path); err == nil && !bytes.Contains(old, []byte(marker)) {
+			return fmt.Errorf("%s exists and wasn't installed by gopomodoro; add %q to it yourself",
+				path, strings.Join(quoted, " ")+" "+name+` "$@"`)
+		}
+		// a failing hook would block the commit, so errors only warn
+		script := fmt.Sprintf("#!/bin/sh\n%s\n%s %s \"$@\" || true\n", marker, strings.Join(quoted, " "), name)
+		if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Uninstall removes the hooks in dir that gopomodoro installed and
+// reports which.
+func Uninstall(dir string) ([]string, error) {
+	var removed []string
+	for _, name := range []string{PrepareHook, CommitHook} {
+		path := filepath.Join(dir, name)
+		b, err := os.ReadFile(path)
+		if err != nil || !bytes.Contains(b, []byte(marker)) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return removed, err
+		}
+		removed = append(removed, name)
+	}
+	return removed, nil
+}
+
+// shellQuote quotes s for a POSIX shell.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,103 @@
+package git
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestInstallUninstall(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "hooks")
+	cmd := []string{"/opt/it's/gopomodoro", "git", "hook"}
+	if err := Install(dir, cmd, PrepareHook, CommitHook); err != nil {
+		t.Fatalf("install: %v", err)
+	}
+	b, err := os.ReadFile(filepath.Join(dir, PrepareHook))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(b), `'/opt/it'\''s/gopomodoro' 'git' 'hook' prepare-commit-msg "$@" || true`) {
+		t.Fatalf("unexpected hook:\n%s", b)
+	}
+	// reinstalling replaces our own hooks
+	if err := Install(dir, cmd, PrepareHook); err != nil {
+		t.Fatalf("reinstall: %v", err)
+	}
+
+	foreign := filepath.Join(dir, CommitHook)
+	if err := os.WriteFile(foreign, []byte("#!/bin/sh\nmake lint\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Install(dir, cmd, CommitHook); err == nil {
+		t.Fatal("expected an error for a foreign hook")
+	}
+	removed, err := Uninstall(dir)
+	if err != nil || !reflect.DeepEqual(removed, []string{PrepareHook}) {
+		t.Fatalf("uninstall removed %v, %v", removed, err)
+	}
+	if _, err := os.Stat(foreign); err != nil {
+		t.Fatalf("foreign hook removed: %v", err)
+	}
+}
+
+func TestHead(t *testing.T) {
+	var calls []string
+	r := &Repo{Run: func(_ context.Context, _ string, args ...string) ([]byte, error) {
+		calls = append(calls, args[0])
+		if args[0] == "log" {
+			return []byte("abc123 1746090000\n"), nil
+		}
+		return nil, nil
+	}}
+	hash, at, err := r.Head(context.Background())
+	if err != nil || hash != "abc123" || !at.Equal(time.Unix(1746090000, 0)) {
+		t.Fatalf("head %q %v %v", hash, at, err)
+	}
+	if !reflect.DeepEqual(calls, []string{"rev-parse", "log"}) {
+		t.Fatalf("ran %v", calls)
+	}
+}
+
+func TestJournalAndByRepo(t *testing.T) {
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+	path := JournalPath(filepath.Join(t.TempDir(), "history.jsonl"))
+	for _, c := range []Commit{
+		{Hash: "a", Repo: "/src/shop", At: at(20)},
+		{Hash: "b", Repo: "/src/shop", At: at(28)}, // on the break
+		{Hash: "c", Repo: "/src/blog", At: at(40)},
+		{Hash: "d", Repo: "/src/blog", At: at(120)}, // outside any session
+	} {
+		if err := Record(path, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commits, err := Commits(path)
+	if err != nil || len(commits) != 4 {
+		t.Fatalf("commits %+v, %v", commits, err)
+	}
+
+	sessions := []history.Session{
+		{Phase: "WORK", Start: at(0), End: at(25), Completed: true},
+		{Phase: "SHORT_BREAK", Start: at(25), End: at(30), Completed: true},
+		{Phase: "WORK", Start: at(30), End: at(45), Abandoned: true},
+		{Phase: "WORK", Start: at(60), End: at(85), Completed: true},
+	}
+	got := ByRepo(sessions, commits)
+	want := []RepoStat{
+		{Repo: "/src/shop", Pomodoros: 1, Focus: 25 * time.Minute, Commits: 2},
+		{Repo: "/src/blog", Pomodoros: 0, Focus: 15 * time.Minute, Commits: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if n := Pomodoros(sessions, at(20)); n != 2 {
+		t.Fatalf("pomodoros since 9:20: %d, want 2", n)
+	}
+}
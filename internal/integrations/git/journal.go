@@ -0,0 +1,144 @@
+package git
+
+import (
+	"bufio"
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// Commit is one commit recorded by the post-commit hook.
+type Commit struct {
+	Hash string    `json:"hash"`
+	Repo string    `json:"repo"` // top directory of the work tree
+	At   time.Time `json:"at"`
+}
+
+// JournalPath is the commit journal kept beside the history file at
+// historyPath.
+func JournalPath(historyPath string) string {
+	return filepath.Join(filepath.Dir(historyPath), "commits.jsonl")
+}
+
+// Record appends c to the journal at path.
+func Record(path string, c Commit) error {
+	line, err := json.Marshal(c)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Commits reads the journal at path, oldest first. A missing journal
+// has no commits.
+func Commits(path string) ([]Commit, error) {
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var out []Commit
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var c Commit
+		if err := json.Unmarshal(sc.Bytes(), &c); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		out = append(out, c)
+	}
+	return out, sc.Err()
+}
+
+// Pomodoros counts the work sessions completed after since, for the
+// trailer of a commit following one made at since.
+func Pomodoros(sessions []history.Session, since time.Time) int {
+	n := 0
+	for _, s := range sessions {
+		if s.Phase == core.PhaseWork.String() && s.Completed && s.End.After(since) {
+			n++
+		}
+	}
+	return n
+}
+
+// Grace is how long after a work session a commit still counts toward
+// it, so one made on the break that follows does.
+const Grace = 10 * time.Minute
+
+// RepoStat is the work attributed to one repository.
+type RepoStat struct {
+	Repo      string
+	Pomodoros int           // completed work sessions with commits to it
+	Focus     time.Duration // their active time
+	Commits   int           // commits recorded, during sessions or not
+}
+
+// ByRepo attributes work sessions to the repositories committed to
+// during them, or within Grace after, most focus first. A session with
+// commits to two repositories counts toward both.
+func ByRepo(sessions []history.Session, commits []Commit) []RepoStat {
+	byRepo := map[string]*RepoStat{}
+	stat := func(repo string) *RepoStat {
+		rs := byRepo[repo]
+		if rs == nil {
+			rs = &RepoStat{Repo: repo}
+			byRepo[repo] = rs
+		}
+		return rs
+	}
+	for _, c := range commits {
+		stat(c.Repo).Commits++
+	}
+	for _, s := range sessions {
+		if s.Phase != core.PhaseWork.String() {
+			continue
+		}
+		seen := map[string]bool{}
+		for _, c := range commits {
+			if c.At.Before(s.Start) || c.At.After(s.End.Add(Grace)) || seen[c.Repo] {
+				continue
+			}
+			seen[c.Repo] = true
+			rs := stat(c.Repo)
+			if s.Completed {
+				rs.Pomodoros++
+			}
+			rs.Focus += s.Active()
+		}
+	}
+	out := make([]RepoStat, 0, len(byRepo))
+	for _, rs := range byRepo {
+		out = append(out, *rs)
+	}
+	slices.SortFunc(out, func(a, b RepoStat) int {
+		if c := cmp.Compare(b.Focus, a.Focus); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Repo, b.Repo)
+	})
+	return out
+}
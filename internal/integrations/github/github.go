@@ -0,0 +1,164 @@
+// Package github links work sessions to GitHub issues: it offers the
+// issues assigned to you as tasks, and reports the time spent on the
+// attached one as issue comments or in a number field of the projects
+// the issue is on.
+package github
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Source is the core.Task source name for GitHub issues.
+const Source = "github"
+
+// DefaultBaseURL is the GitHub REST API endpoint; GraphQL is under it.
+const DefaultBaseURL = "https://api.github.com/"
+
+// Client is a minimal GitHub API client authenticated with a personal
+// access token; without one it can only read public issues.
+type Client struct {
+	Token   string
+	BaseURL string       // defaults to DefaultBaseURL
+	HTTP    *http.Client // defaults to http.DefaultClient
+}
+
+// APIError is a non-2xx response or a GraphQL error.
+type APIError struct {
+	Path   string
+	Status int
+	Body   string
+}
+
+func (e *APIError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("github %s: status %d: %s", e.Path, e.Status, e.Body)
+	}
+	return fmt.Sprintf("github %s: status %d", e.Path, e.Status)
+}
+
+// Ref identifies an issue or pull request.
+type Ref struct {
+	Owner, Repo string
+	Number      int
+}
+
+func (r Ref) String() string { return fmt.Sprintf("%s/%s#%d", r.Owner, r.Repo, r.Number) }
+
+// refPattern matches "owner/repo#12" and issue or pull request URLs.
+var refPattern = regexp.MustCompile(`^(?:https?://github\.com/)?([\w.-]+)/([\w.-]+)(?:#|/(?:issues|pull)/)(\d+)/?(?:[#?].*)?$`)
+
+// ParseRef reads an issue URL such as
+// https://github.com/owner/repo/issues/12, or "owner/repo#12".
+func ParseRef(s string) (Ref, error) {
+	m := refPattern.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return Ref{}, fmt.Errorf("github: %q is not an issue URL or owner/repo#number", s)
+	}
+	n, _ := strconv.Atoi(m[3])
+	return Ref{Owner: m[1], Repo: m[2], Number: n}, nil
+}
+
+// Task is the task for the issue at ref titled title; its ID is the
+// ref, e.g. "owner/repo#12".
+func Task(ref Ref, title string) core.Task {
+	return core.Task{Source: Source, ID: ref.String(), Title: cmp.Or(title, ref.String())}
+}
+
+// issue is the subset of an issue used here.
+type issue struct {
+	Number     int    `json:"number"`
+	Title      string `json:"title"`
+	Repository struct {
+		FullName string `json:"full_name"`
+	} `json:"repository"`
+}
+
+// Issue returns the task for the issue at ref, titled like it.
+func (c *Client) Issue(ctx context.Context, ref Ref) (core.Task, error) {
+	var is issue
+	path := fmt.Sprintf("repos/%s/%s/issues/%d", url.PathEscape(ref.Owner), url.PathEscape(ref.Repo), ref.Number)
+	if err := c.do(ctx, http.MethodGet, path, nil, &is); err != nil {
+		return core.Task{}, err
+	}
+	return Task(ref, is.Title), nil
+}
+
+// Tasks returns the open issues assigned to the token's user, most
+// recently updated first.
+func (c *Client) Tasks(ctx context.Context) ([]core.Task, error) {
+	var issues []issue
+	if err := c.do(ctx, http.MethodGet, "issues?filter=assigned&state=open&per_page=100", nil, &issues); err != nil {
+		return nil, err
+	}
+	out := make([]core.Task, 0, len(issues))
+	for _, is := range issues {
+		owner, repo, _ := strings.Cut(is.Repository.FullName, "/")
+		ref := Ref{Owner: owner, Repo: repo, Number: is.Number}
+		out = append(out, Task(ref, fmt.Sprintf("%s#%d %s", repo, is.Number, is.Title)))
+	}
+	return out, nil
+}
+
+// Comment posts a comment on the issue with the task ID id.
+func (c *Client) Comment(ctx context.Context, id, body string) error {
+	ref, err := ParseRef(id)
+	if err != nil {
+		return err
+	}
+	path := fmt.Sprintf("repos/%s/%s/issues/%d/comments", url.PathEscape(ref.Owner), url.PathEscape(ref.Repo), ref.Number)
+	return c.do(ctx, http.MethodPost, path, map[string]string{"body": body}, nil)
+}
+
+// do sends a JSON request and decodes the response into out, if set.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	req, err := http.NewRequestWithContext(ctx, method, cmp.Or(c.BaseURL, DefaultBaseURL)+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("github %s: %w", path, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &APIError{Path: path, Status: resp.StatusCode, Body: string(bytes.TrimSpace(msg))}
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("github %s: %w", path, err)
+	}
+	return nil
+}
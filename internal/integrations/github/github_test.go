@@ -0,0 +1,163 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestParseRef(t *testing.T) {
+	want := Ref{Owner: "ezchuang", Repo: "GoPomodoro", Number: 12}
+	for _, s := range []string{
+		"https://github.com/ezchuang/GoPomodoro/issues/12",
+		"https://github.com/ezchuang/GoPomodoro/pull/12/",
+		"https://github.com/ezchuang/GoPomodoro/issues/12#issuecomment-1",
+		"ezchuang/GoPomodoro#12",
+	} {
+		if got, err := ParseRef(s); err != nil || got != want {
+			t.Errorf("ParseRef(%q) = %v, %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"", "ezchuang/GoPomodoro", "https://github.com/ezchuang/GoPomodoro/issues/x", "https://example.com/a/b/issues/1"} {
+		if _, err := ParseRef(s); err == nil {
+			t.Errorf("ParseRef(%q) succeeded", s)
+		}
+	}
+}
+
+func TestIssueAndComment(t *testing.T) {
+	var comment map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Bearer tok" {
+			t.Errorf("auth %q", got)
+		}
+		switch r.Method + " " + r.URL.Path {
+		case "GET /repos/o/r/issues/7":
+			_, _ = w.Write([]byte(`{"number":7,"title":"Fix login"}`))
+		case "POST /repos/o/r/issues/7/comments":
+			_ = json.NewDecoder(r.Body).Decode(&comment)
+			w.WriteHeader(http.StatusCreated)
+		default:
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	c := &Client{Token: "tok", BaseURL: srv.URL + "/"}
+	task, err := c.Issue(context.Background(), Ref{Owner: "o", Repo: "r", Number: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (core.Task{Source: Source, ID: "o/r#7", Title: "Fix login"}); task != want {
+		t.Fatalf("task %+v, want %+v", task, want)
+	}
+	if err := c.Comment(context.Background(), task.ID, "🍅"); err != nil {
+		t.Fatal(err)
+	}
+	if comment["body"] != "🍅" {
+		t.Fatalf("comment %v", comment)
+	}
+}
+
+func TestAddToField(t *testing.T) {
+	var updates []map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		if strings.HasPrefix(req.Query, "mutation") {
+			updates = append(updates, req.Variables)
+			_, _ = w.Write([]byte(`{"data":{"updateProjectV2ItemFieldValue":{"projectV2Item":{"id":"x"}}}}`))
+			return
+		}
+		if req.Variables["field"] != "Time spent" || req.Variables["number"] != 7.0 {
+			t.Errorf("query variables %v", req.Variables)
+		}
+		_, _ = w.Write([]byte(`{"data":{"repository":{"issueOrPullRequest":{"projectItems":{"nodes":[
+			{"id":"I1","project":{"id":"P1","field":{"id":"F1","dataType":"NUMBER"}},"fieldValueByName":{"number":1.5}},
+			{"id":"I2","project":{"id":"P2","field":{"id":"F2","dataType":"NUMBER"}},"fieldValueByName":null},
+			{"id":"I3","project":{"id":"P3","field":null},"fieldValueByName":null},
+			{"id":"I4","project":{"id":"P4","field":{"id":"F4","dataType":"TEXT"}},"fieldValueByName":null}
+		]}}}}}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{Token: "tok", BaseURL: srv.URL + "/"}
+	n, err := c.AddToField(context.Background(), "o/r#7", "Time spent", 0.42)
+	if err != nil || n != 2 {
+		t.Fatalf("updated %d, %v", n, err)
+	}
+	if len(updates) != 2 || updates[0]["item"] != "I1" || updates[0]["value"] != 1.92 ||
+		updates[1]["item"] != "I2" || updates[1]["value"] != 0.42 {
+		t.Fatalf("updates %v", updates)
+	}
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"errors":[{"message":"Could not resolve to a Repository"}]}`))
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL + "/"}
+	if _, err := c.AddToField(context.Background(), "o/r#7", "Time spent", 1); err == nil || !strings.Contains(err.Error(), "Could not resolve") {
+		t.Fatalf("err %v", err)
+	}
+}
+
+func TestReporter(t *testing.T) {
+	var mu sync.Mutex
+	var comments []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		comments = append(comments, r.URL.Path+" "+body["body"])
+		mu.Unlock()
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	r, err := NewReporter(&Client{BaseURL: srv.URL + "/"}, Options{Comment: true, Done: map[string]int{"o/r#7": 2}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	s := history.Session{ID: "s1", Phase: "WORK", Start: start, End: start.Add(25 * time.Minute), Completed: true,
+		Task: &history.Task{Source: Source, ID: "o/r#7", Title: "Fix login"}}
+	r.Handle(s)
+	unfinished := s
+	unfinished.Completed = false
+	r.Handle(unfinished)
+	other := s
+	other.Task = &history.Task{Source: "todoist", ID: "1"}
+	r.Handle(other)
+	r.Close()
+
+	if len(comments) != 1 || comments[0] != "/repos/o/r/issues/7/comments 🍅 Pomodoro #3 done: 25m0s" {
+		t.Fatalf("comments %q", comments)
+	}
+}
+
+func TestAmount(t *testing.T) {
+	if got := Amount(25*time.Minute, ""); got != 0.42 {
+		t.Errorf("hours %v", got)
+	}
+	if got := Amount(25*time.Minute+20*time.Second, Minutes); got != 25 {
+		t.Errorf("minutes %v", got)
+	}
+	if _, err := NewReporter(&Client{}, Options{Unit: "days"}); err == nil {
+		t.Error("unit days accepted")
+	}
+}
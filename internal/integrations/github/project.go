@@ -0,0 +1,129 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// itemsQuery finds the issue's project items with the value of the
+// named field and the field's ID in each project.
+const itemsQuery = `query($owner: String!, $repo: String!, $number: Int!, $field: String!) {
+  repository(owner: $owner, name: $repo) {
+    issueOrPullRequest(number: $number) {
+      ... on Issue { projectItems(first: 20) { ...items } }
+      ... on PullRequest { projectItems(first: 20) { ...items } }
+    }
+  }
+}
+fragment items on ProjectV2ItemConnection {
+  nodes {
+    id
+    project { id field(name: $field) { ... on ProjectV2Field { id dataType } } }
+    fieldValueByName(name: $field) { ... on ProjectV2ItemFieldNumberValue { number } }
+  }
+}`
+
+const updateMutation = `mutation($project: ID!, $item: ID!, $field: ID!, $value: Float!) {
+  updateProjectV2ItemFieldValue(input: {projectId: $project, itemId: $item, fieldId: $field, value: {number: $value}}) {
+    projectV2Item { id }
+  }
+}`
+
+type itemsResult struct {
+	Repository struct {
+		IssueOrPullRequest struct {
+			ProjectItems struct {
+				Nodes []struct {
+					ID      string `json:"id"`
+					Project struct {
+						ID    string `json:"id"`
+						Field *struct {
+							ID       string `json:"id"`
+							DataType string `json:"dataType"`
+						} `json:"field"`
+					} `json:"project"`
+					Value *struct {
+						Number float64 `json:"number"`
+					} `json:"fieldValueByName"`
+				} `json:"nodes"`
+			} `json:"projectItems"`
+		} `json:"issueOrPullRequest"`
+	} `json:"repository"`
+}
+
+// AddToField adds amount to the number field named field of every
+// project the issue with the task ID id is on, and returns how many it
+// updated. Projects without such a field are left alone.
+func (c *Client) AddToField(ctx context.Context, id, field string, amount float64) (int, error) {
+	ref, err := ParseRef(id)
+	if err != nil {
+		return 0, err
+	}
+	var res itemsResult
+	if err := c.graphQL(ctx, itemsQuery, map[string]any{
+		"owner": ref.Owner, "repo": ref.Repo, "number": ref.Number, "field": field,
+	}, &res); err != nil {
+		return 0, err
+	}
+	n := 0
+	var errs []error
+	for _, item := range res.Repository.IssueOrPullRequest.ProjectItems.Nodes {
+		f := item.Project.Field
+		if f == nil || f.DataType != "NUMBER" {
+			continue
+		}
+		value := amount
+		if item.Value != nil {
+			value += item.Value.Number
+		}
+		if err := c.graphQL(ctx, updateMutation, map[string]any{
+			"project": item.Project.ID, "item": item.ID, "field": f.ID, "value": value,
+		}, nil); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		n++
+	}
+	return n, errors.Join(errs...)
+}
+
+// graphQL runs a query and decodes its data into out, if set.
+func (c *Client) graphQL(ctx context.Context, query string, vars map[string]any, out any) error {
+	var resp struct {
+		Data   any `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	resp.Data = out
+	if err := c.do(ctx, http.MethodPost, "graphql", map[string]any{"query": query, "variables": vars}, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		msgs := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			msgs[i] = e.Message
+		}
+		return &APIError{Path: "graphql", Status: 200, Body: strings.Join(msgs, "; ")}
+	}
+	return nil
+}
+
+// Hours and Minutes are the units a project field can count time in.
+const (
+	Hours   = "hours"
+	Minutes = "minutes"
+)
+
+// checkUnit rejects units other than Hours and Minutes; empty means
+// Hours.
+func checkUnit(unit string) error {
+	switch unit {
+	case "", Hours, Minutes:
+		return nil
+	}
+	return fmt.Errorf("github: unknown unit %q (want %s or %s)", unit, Hours, Minutes)
+}
@@ -0,0 +1,106 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// Options configures a Reporter.
+type Options struct {
+	// Comment adds a "🍅" comment with the time spent to the issue per
+	// completed pomodoro.
+	Comment bool
+	// Field names a number field of the issue's projects to add the time
+	// spent to, e.g. "Time spent"; empty leaves projects alone.
+	Field string
+	// Unit is what Field counts: Hours (the default, to two decimals) or
+	// Minutes.
+	Unit string
+	// Done seeds the per-issue pomodoro counts the comments number, e.g.
+	// from history.
+	Done    map[string]int
+	Timeout time.Duration // per session; default 30s
+	OnError func(error)
+}
+
+// Reporter reports the time spent in completed work sessions on their
+// GitHub issue. Register its Handle method with history.Recorder.OnSession
+// and Close it on exit.
+type Reporter struct {
+	client *Client
+	opts   Options
+
+	mu   sync.Mutex
+	done map[string]int
+	wg   sync.WaitGroup
+}
+
+// NewReporter creates a Reporter using client.
+func NewReporter(client *Client, opts Options) (*Reporter, error) {
+	if err := checkUnit(opts.Unit); err != nil {
+		return nil, err
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	done := make(map[string]int, len(opts.Done))
+	for id, n := range opts.Done {
+		done[id] = n
+	}
+	return &Reporter{client: client, opts: opts, done: done}, nil
+}
+
+// Handle reports a finished session if it is a completed pomodoro spent
+// on a GitHub issue. The API calls run off the recorder's goroutine.
+func (r *Reporter) Handle(s history.Session) {
+	if !s.Completed || s.Phase != core.PhaseWork.String() || s.Task == nil || s.Task.Source != Source {
+		return
+	}
+	r.mu.Lock()
+	r.done[s.Task.ID]++
+	n := r.done[s.Task.ID]
+	r.mu.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.report(s.Task.ID, n, s.Active())
+	}()
+}
+
+// Close waits for reports in flight.
+func (r *Reporter) Close() { r.wg.Wait() }
+
+func (r *Reporter) report(id string, n int, spent time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), r.opts.Timeout)
+	defer cancel()
+	if r.opts.Comment {
+		body := fmt.Sprintf("🍅 Pomodoro #%d done: %s", n, spent.Round(time.Minute))
+		if err := r.client.Comment(ctx, id, body); err != nil {
+			r.opts.OnError(err)
+		}
+	}
+	if r.opts.Field != "" {
+		if _, err := r.client.AddToField(ctx, id, r.opts.Field, Amount(spent, r.opts.Unit)); err != nil {
+			r.opts.OnError(err)
+		}
+	}
+}
+
+// Amount is spent in unit, Hours rounded to two decimals or whole
+// Minutes.
+func Amount(spent time.Duration, unit string) float64 {
+	if unit == Minutes {
+		return math.Round(spent.Minutes())
+	}
+	return math.Round(spent.Hours()*100) / 100
+}
@@ -0,0 +1,163 @@
+// Package jira logs work sessions tagged with a Jira issue key, such as
+// #SHOP-42, as worklogs on that issue through the Jira REST API.
+package jira
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// Client is a minimal Jira REST API v2 client. With an Email it signs in
+// with an API token the Jira Cloud way; without one Token is a personal
+// access token, as on Jira Server and Data Center.
+type Client struct {
+	BaseURL string // e.g. https://example.atlassian.net
+	Email   string
+	Token   string
+	HTTP    *http.Client // defaults to http.DefaultClient
+}
+
+// APIError is a non-2xx response.
+type APIError struct {
+	Issue  string
+	Status int
+	Body   string
+}
+
+func (e *APIError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("jira %s: status %d: %s", e.Issue, e.Status, e.Body)
+	}
+	return fmt.Sprintf("jira %s: status %d", e.Issue, e.Status)
+}
+
+// Worklog is time spent on an issue.
+type Worklog struct {
+	Issue   string
+	Started time.Time
+	Spent   time.Duration
+	Comment string
+	// Sessions are the IDs of the work sessions it covers.
+	Sessions []string
+}
+
+func (w Worklog) String() string {
+	return fmt.Sprintf("%s %s from %s", w.Issue, w.Spent.Round(time.Minute), w.Started.Local().Format("2006-01-02 15:04"))
+}
+
+// AddWorklog submits w; Jira rounds the time spent to whole minutes.
+func (c *Client) AddWorklog(ctx context.Context, w Worklog) error {
+	body, err := json.Marshal(map[string]any{
+		// Jira wants milliseconds and a zone without a colon
+		"started":          w.Started.Format("2006-01-02T15:04:05.000-0700"),
+		"timeSpentSeconds": int(w.Spent.Seconds()),
+		"comment":          w.Comment,
+	})
+	if err != nil {
+		return err
+	}
+	u := strings.TrimRight(c.BaseURL, "/") + "/rest/api/2/issue/" + url.PathEscape(w.Issue) + "/worklog"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.Email != "" {
+		req.SetBasicAuth(c.Email, c.Token)
+	} else {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("jira %s: %w", w.Issue, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &APIError{Issue: w.Issue, Status: resp.StatusCode, Body: string(bytes.TrimSpace(msg))}
+	}
+	return nil
+}
+
+// issueKey matches a Jira issue key such as SHOP-42.
+var issueKey = regexp.MustCompile(`^[A-Z][A-Z0-9_]+-[1-9][0-9]*$`)
+
+// Issues returns the tags that are issue keys, in any case, upper-cased.
+func Issues(tags []string) []string {
+	var keys []string
+	for _, t := range tags {
+		if k := strings.ToUpper(t); issueKey.MatchString(k) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+// MinSpent is the shortest time logged; Jira refuses less than a
+// minute.
+const MinSpent = time.Minute
+
+// Worklogs turns a work session into worklogs for the issues it is
+// tagged with, splitting its active time evenly between them. Breaks,
+// untagged sessions and ones shorter than MinSpent yield none.
+func Worklogs(s history.Session, comment string) []Worklog {
+	keys := Issues(s.Tags)
+	if s.Phase != core.PhaseWork.String() || len(keys) == 0 {
+		return nil
+	}
+	spent := s.Active() / time.Duration(len(keys))
+	if spent < MinSpent {
+		return nil
+	}
+	if s.Task != nil {
+		comment += ": " + s.Task.Title
+	}
+	out := make([]Worklog, len(keys))
+	for i, k := range keys {
+		out[i] = Worklog{Issue: k, Started: s.Start, Spent: spent, Comment: comment, Sessions: []string{s.ID}}
+	}
+	return out
+}
+
+// Merge combines the worklogs on the same issue and day into one each,
+// starting at the earliest, in order of first appearance.
+func Merge(logs []Worklog) []Worklog {
+	var out []Worklog
+	at := map[string]int{}
+	for _, w := range logs {
+		key := w.Issue + " " + w.Started.Local().Format(time.DateOnly)
+		i, ok := at[key]
+		if !ok {
+			at[key] = len(out)
+			w.Sessions = slices.Clone(w.Sessions)
+			out = append(out, w)
+			continue
+		}
+		m := &out[i]
+		if w.Started.Before(m.Started) {
+			m.Started = w.Started
+		}
+		m.Spent += w.Spent
+		m.Sessions = append(m.Sessions, w.Sessions...)
+		if !strings.Contains(m.Comment, w.Comment) {
+			m.Comment += "; " + w.Comment
+		}
+	}
+	return out
+}
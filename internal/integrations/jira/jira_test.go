@@ -0,0 +1,106 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestAddWorklog(t *testing.T) {
+	var got map[string]any
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rest/api/2/issue/SHOP-42/worklog" {
+			t.Errorf("path %s", r.URL.Path)
+		}
+		if user, pass, ok := r.BasicAuth(); !ok || user != "me@example.com" || pass != "tok" {
+			t.Errorf("auth %q %q", user, pass)
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer srv.Close()
+
+	c := &Client{BaseURL: srv.URL + "/", Email: "me@example.com", Token: "tok"}
+	started := time.Date(2025, 5, 1, 9, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	err := c.AddWorklog(context.Background(), Worklog{Issue: "SHOP-42", Started: started, Spent: 25 * time.Minute, Comment: "🍅"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]any{"started": "2025-05-01T09:00:00.000+0200", "timeSpentSeconds": 1500.0, "comment": "🍅"}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("sent %v, want %v", got, want)
+	}
+}
+
+func TestWorklogs(t *testing.T) {
+	start := time.Date(2025, 5, 1, 9, 0, 0, 0, time.Local)
+	s := history.Session{ID: "s1", Phase: "WORK", Start: start, End: start.Add(25 * time.Minute),
+		Tags: []string{"coding", "shop-42", "OPS-7"}, Task: &history.Task{Title: "checkout"}}
+	got := Worklogs(s, "🍅")
+	want := []Worklog{
+		{Issue: "SHOP-42", Started: start, Spent: 12*time.Minute + 30*time.Second, Comment: "🍅: checkout", Sessions: []string{"s1"}},
+		{Issue: "OPS-7", Started: start, Spent: 12*time.Minute + 30*time.Second, Comment: "🍅: checkout", Sessions: []string{"s1"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	s.Phase = "SHORT_BREAK"
+	if got := Worklogs(s, "🍅"); got != nil {
+		t.Fatalf("break logged: %+v", got)
+	}
+}
+
+// fakeJira records worklogs, failing while err is set.
+type fakeJira struct {
+	logs []Worklog
+	err  error
+}
+
+func (f *fakeJira) AddWorklog(_ context.Context, w Worklog) error {
+	if f.err != nil {
+		return f.err
+	}
+	f.logs = append(f.logs, w)
+	return nil
+}
+
+func TestLogger_BatchRetryJournal(t *testing.T) {
+	at := func(d, h int) time.Time { return time.Date(2025, 5, d, h, 0, 0, 0, time.Local) }
+	work := func(id string, start time.Time) history.Session {
+		return history.Session{ID: id, Phase: "WORK", Start: start, End: start.Add(25 * time.Minute), Completed: true, Tags: []string{"SHOP-42"}}
+	}
+	journal := JournalPath(filepath.Join(t.TempDir(), "history.jsonl"))
+	fake := &fakeJira{err: errors.New("offline")}
+	var errs int
+	l := NewLogger(fake, Options{Journal: journal, Batch: time.Hour, OnError: func(error) { errs++ }})
+	l.Handle(work("a", at(1, 9)))
+	l.Handle(work("b", at(1, 10)))
+	l.flush()
+	if errs != 1 || len(l.pending) != 1 {
+		t.Fatalf("after a failure: %d errors, pending %+v", errs, l.pending)
+	}
+
+	fake.err = nil
+	l.Handle(work("c", at(2, 9)))
+	l.Close()
+	if len(fake.logs) != 2 || fake.logs[0].Spent != 50*time.Minute || !fake.logs[1].Started.Equal(at(2, 9)) {
+		t.Fatalf("submitted %+v", fake.logs)
+	}
+
+	// the journal keeps a catch-up from logging them again
+	var lines []string
+	again := NewLogger(fake, Options{Journal: journal, DryRun: true, Log: func(s string) { lines = append(lines, s) }})
+	again.SubmitSessions([]history.Session{work("a", at(1, 9)), work("d", at(3, 9))})
+	if len(fake.logs) != 2 || len(lines) != 1 || !strings.Contains(lines[0], "would log SHOP-42 25m") {
+		t.Fatalf("catch-up sent %+v, logged %q", fake.logs, lines)
+	}
+}
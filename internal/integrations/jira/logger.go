@@ -0,0 +1,220 @@
+package jira
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// submitTimeout bounds one worklog submission.
+const submitTimeout = 30 * time.Second
+
+// Submitter adds worklogs; *Client is one.
+type Submitter interface {
+	AddWorklog(ctx context.Context, w Worklog) error
+}
+
+// Options configures a Logger.
+type Options struct {
+	// Journal is where submitted sessions are remembered so none is
+	// logged twice; see JournalPath.
+	Journal string
+	// Comment starts each worklog's comment; the session's task title
+	// follows. Default "🍅 Pomodoro".
+	Comment string
+	// DryRun hands worklogs to Log instead of submitting them.
+	DryRun bool
+	// Batch collects worklogs this long and submits them merged per
+	// issue and day; zero submits each session as it ends.
+	Batch time.Duration
+	// Log receives a line per worklog submitted or, with DryRun, not.
+	Log func(string)
+	// OnError receives submission failures; those worklogs are retried
+	// with the next submission.
+	OnError func(error)
+}
+
+// Logger submits the worklogs of finished sessions. Register its Handle
+// method with history.Recorder.OnSession and Close it on exit.
+type Logger struct {
+	client Submitter
+	opts   Options
+
+	mu      sync.Mutex
+	pending []Worklog
+	timer   *time.Timer
+	closed  bool
+}
+
+// NewLogger creates a Logger submitting through client.
+func NewLogger(client Submitter, opts Options) *Logger {
+	if opts.Comment == "" {
+		opts.Comment = "🍅 Pomodoro"
+	}
+	if opts.Log == nil {
+		opts.Log = func(string) {}
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	return &Logger{client: client, opts: opts}
+}
+
+// Handle queues the worklogs of a finished session.
+func (l *Logger) Handle(s history.Session) {
+	logs := Worklogs(s, l.opts.Comment)
+	if len(logs) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+	l.pending = append(l.pending, logs...)
+	if l.timer == nil {
+		// submit off the recorder's goroutine even without batching
+		l.timer = time.AfterFunc(l.opts.Batch, l.flush)
+	}
+}
+
+// Close submits anything still pending and stops accepting sessions.
+func (l *Logger) Close() {
+	l.mu.Lock()
+	l.closed = true
+	if l.timer != nil {
+		l.timer.Stop()
+	}
+	l.mu.Unlock()
+	l.flush()
+}
+
+func (l *Logger) flush() {
+	l.mu.Lock()
+	batch := l.pending
+	l.pending = nil
+	l.timer = nil
+	l.mu.Unlock()
+
+	failed := l.Submit(batch)
+	if len(failed) == 0 {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(failed, l.pending...)
+}
+
+// Submit merges logs per issue and sends them, leaving out sessions the
+// journal has already logged on the issue, and returns the ones that
+// failed.
+func (l *Logger) Submit(logs []Worklog) (failed []Worklog) {
+	done, err := Logged(l.opts.Journal)
+	if err != nil {
+		l.opts.OnError(err)
+		return logs
+	}
+	logs = slices.DeleteFunc(slices.Clone(logs), func(w Worklog) bool {
+		return slices.ContainsFunc(w.Sessions, func(id string) bool { return done[w.Issue+"\x00"+id] })
+	})
+	for _, w := range Merge(logs) {
+		if l.opts.DryRun {
+			l.opts.Log("jira: would log " + w.String())
+			continue
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), submitTimeout)
+		err := l.client.AddWorklog(ctx, w)
+		cancel()
+		if err != nil {
+			l.opts.OnError(err)
+			failed = append(failed, w)
+			continue
+		}
+		l.opts.Log("jira: logged " + w.String())
+		if err := record(l.opts.Journal, w); err != nil {
+			l.opts.OnError(err)
+		}
+	}
+	return failed
+}
+
+// SubmitSessions submits the worklogs of sessions, e.g. to catch up on
+// past ones, and returns the ones that failed.
+func (l *Logger) SubmitSessions(sessions []history.Session) (failed []Worklog) {
+	var logs []Worklog
+	for _, s := range sessions {
+		logs = append(logs, Worklogs(s, l.opts.Comment)...)
+	}
+	return l.Submit(logs)
+}
+
+// entry is a journal line: one session logged on one issue.
+type entry struct {
+	Session string    `json:"session"`
+	Issue   string    `json:"issue"`
+	At      time.Time `json:"at"`
+}
+
+// JournalPath is the worklog journal kept beside the history file at
+// historyPath.
+func JournalPath(historyPath string) string {
+	return filepath.Join(filepath.Dir(historyPath), "jira.jsonl")
+}
+
+// Logged reads the journal at path into a set of issue and session ID
+// pairs, joined by a NUL. A missing or unset journal is empty.
+func Logged(path string) (map[string]bool, error) {
+	done := map[string]bool{}
+	if path == "" {
+		return done, nil
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return done, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	sc := bufio.NewScanner(f)
+	for n := 1; sc.Scan(); n++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e entry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, n, err)
+		}
+		done[e.Issue+"\x00"+e.Session] = true
+	}
+	return done, sc.Err()
+}
+
+// record appends the sessions of w to the journal at path.
+func record(path string, w Worklog) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(f)
+	for _, id := range w.Sessions {
+		if err := enc.Encode(entry{Session: id, Issue: w.Issue, At: time.Now()}); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	return f.Close()
+}
@@ -0,0 +1,99 @@
+// Package media pauses and resumes music players on phase changes:
+// MPRIS players on Linux, Music or Spotify on macOS and the system media
+// session on Windows.
+package media
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// ErrUnsupported is returned by New where no player can be controlled.
+var ErrUnsupported = errors.New("media control is not supported on this system")
+
+// Player controls music playback.
+type Player interface {
+	// Play resumes playback, or starts uri (a playlist, album…) if set.
+	Play(uri string) error
+	// Pause pauses whatever is playing.
+	Pause() error
+}
+
+// Action is what a phase change does to the player.
+type Action string
+
+const (
+	None  Action = "none"
+	Play  Action = "play"
+	Pause Action = "pause"
+)
+
+// ParseAction parses a config value; empty gives def.
+func ParseAction(s string, def Action) (Action, error) {
+	switch a := Action(s); a {
+	case "":
+		return def, nil
+	case None, Play, Pause:
+		return a, nil
+	}
+	return "", fmt.Errorf("media: unknown action %q (want play, pause or none)", s)
+}
+
+// Options configures a Sync.
+type Options struct {
+	// Work, ShortBreak and LongBreak run when those phases start, Stop
+	// when the timer is stopped.
+	Work, ShortBreak, LongBreak, Stop Action
+	// Playlist is played when work starts, if Work is Play.
+	Playlist string
+	OnError  func(error)
+}
+
+// Sync applies Options to a Player. Subscribe its Handle method to an
+// engine.
+type Sync struct {
+	player Player
+	opts   Options
+}
+
+// NewSync creates a Sync driving player.
+func NewSync(player Player, opts Options) *Sync {
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	return &Sync{player: player, opts: opts}
+}
+
+// Handle consumes one engine event.
+func (s *Sync) Handle(ev core.Event) {
+	var action Action
+	switch ev.Kind {
+	case core.EventStart, core.EventAdvance, core.EventSkip:
+		switch ev.State.Phase {
+		case core.PhaseWork:
+			action = s.opts.Work
+		case core.PhaseShortBreak:
+			action = s.opts.ShortBreak
+		case core.PhaseLongBreak:
+			action = s.opts.LongBreak
+		}
+	case core.EventStop:
+		action = s.opts.Stop
+	}
+	var err error
+	switch action {
+	case Play:
+		uri := ""
+		if ev.State.Phase == core.PhaseWork && ev.Kind != core.EventStop {
+			uri = s.opts.Playlist
+		}
+		err = s.player.Play(uri)
+	case Pause:
+		err = s.player.Pause()
+	}
+	if err != nil {
+		s.opts.OnError(err)
+	}
+}
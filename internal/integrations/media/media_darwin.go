@@ -0,0 +1,41 @@
+package media
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// appleScript controls Music or Spotify with osascript, and only while
+// the app is running so a phase change never launches it.
+type appleScript struct {
+	app string
+}
+
+// New controls the named app; default "Music".
+func New(name string) (Player, error) {
+	switch strings.ToLower(name) {
+	case "", "music":
+		return appleScript{app: "Music"}, nil
+	case "spotify":
+		return appleScript{app: "Spotify"}, nil
+	}
+	return nil, fmt.Errorf("media: can't control %q on macOS (want music or spotify)", name)
+}
+
+func (a appleScript) run(command string) error {
+	script := fmt.Sprintf("if application %q is running then tell application %q to %s", a.app, a.app, command)
+	return exec.Command("osascript", "-e", script).Run()
+}
+
+func (a appleScript) Pause() error { return a.run("pause") }
+
+func (a appleScript) Play(uri string) error {
+	if uri == "" {
+		return a.run("play")
+	}
+	if a.app != "Spotify" {
+		return fmt.Errorf("media: %s can't open %s", a.app, uri)
+	}
+	return a.run(fmt.Sprintf("play track %q", uri))
+}
@@ -0,0 +1,6 @@
+//go:build !linux && !darwin && !windows
+
+package media
+
+// New returns ErrUnsupported.
+func New(name string) (Player, error) { return nil, ErrUnsupported }
@@ -0,0 +1,51 @@
+package media
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type recordPlayer []string
+
+func (r *recordPlayer) Play(uri string) error {
+	*r = append(*r, "play "+uri)
+	return nil
+}
+
+func (r *recordPlayer) Pause() error {
+	*r = append(*r, "pause")
+	return nil
+}
+
+func TestSync(t *testing.T) {
+	var p recordPlayer
+	s := NewSync(&p, Options{Work: Play, ShortBreak: Pause, LongBreak: None, Stop: Pause, Playlist: "spotify:playlist:focus"})
+	at := time.Now()
+	state := func(ph core.Phase) core.State { return core.State{Phase: ph, StartedAt: at} }
+	for _, ev := range []core.Event{
+		{Kind: core.EventStart, State: state(core.PhaseWork)},
+		{Kind: core.EventPause, State: state(core.PhaseWork)},
+		{Kind: core.EventAdvance, State: state(core.PhaseShortBreak)},
+		{Kind: core.EventSkip, State: state(core.PhaseWork)},
+		{Kind: core.EventAdvance, State: state(core.PhaseLongBreak)},
+		{Kind: core.EventStop, State: state(core.PhaseLongBreak)},
+	} {
+		s.Handle(ev)
+	}
+	want := recordPlayer{"play spotify:playlist:focus", "pause", "play spotify:playlist:focus", "pause"}
+	if !slices.Equal(p, want) {
+		t.Fatalf("got %q, want %q", p, want)
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	if a, err := ParseAction("", Pause); err != nil || a != Pause {
+		t.Fatalf("default: got %q, %v", a, err)
+	}
+	if _, err := ParseAction("skip", None); err == nil {
+		t.Fatal("unknown action accepted")
+	}
+}
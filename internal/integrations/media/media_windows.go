@@ -0,0 +1,53 @@
+package media
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+// smtcScript drives the current media session through WinRT's
+// GlobalSystemMediaTransportControlsSessionManager; %s is the method,
+// TryPlayAsync or TryPauseAsync.
+const smtcScript = `$ErrorActionPreference = 'Stop'
+Add-Type -AssemblyName System.Runtime.WindowsRuntime
+$asTask = [System.WindowsRuntimeSystemExtensions].GetMethods() | Where-Object { $_.Name -eq 'AsTask' -and $_.GetParameters().Count -eq 1 -and $_.GetParameters()[0].ParameterType.Name -eq 'IAsyncOperation` + "`" + `1' } | Select-Object -First 1
+function Await($op, $type) { $t = $asTask.MakeGenericMethod($type).Invoke($null, @($op)); $t.Wait() | Out-Null; $t.Result }
+[Windows.Media.Control.GlobalSystemMediaTransportControlsSessionManager, Windows.Media.Control, ContentType = WindowsRuntime] | Out-Null
+$mgr = Await ([Windows.Media.Control.GlobalSystemMediaTransportControlsSessionManager]::RequestAsync()) ([Windows.Media.Control.GlobalSystemMediaTransportControlsSessionManager])
+$session = $mgr.GetCurrentSession()
+if ($session) { Await ($session.%s()) ([bool]) | Out-Null }
+`
+
+// smtc controls whichever app owns the current media session.
+type smtc struct{}
+
+// New returns the system media session controller; name is not used on
+// Windows, where only the current session can be reached.
+func New(name string) (Player, error) {
+	if _, err := exec.LookPath("powershell.exe"); err != nil {
+		return nil, ErrUnsupported
+	}
+	return smtc{}, nil
+}
+
+func (smtc) run(method string) error {
+	cmd := exec.Command("powershell.exe", "-NoProfile", "-NonInteractive", "-Command", "-")
+	cmd.Stdin = strings.NewReader(fmt.Sprintf(smtcScript, method))
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: 0x08000000} // CREATE_NO_WINDOW
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("media: %v: %s", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (s smtc) Pause() error { return s.run("TryPauseAsync") }
+
+func (s smtc) Play(uri string) error {
+	if uri != "" {
+		// hand the URI (e.g. spotify:playlist:…) to its app
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", uri).Run()
+	}
+	return s.run("TryPlayAsync")
+}
@@ -0,0 +1,124 @@
+package media
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/godbus/dbus/v5"
+)
+
+const (
+	mprisPrefix = "org.mpris.MediaPlayer2."
+	mprisPath   = dbus.ObjectPath("/org/mpris/MediaPlayer2")
+	mprisPlayer = "org.mpris.MediaPlayer2.Player"
+)
+
+// mpris controls the players on the session bus. Pause remembers which
+// were playing so Play resumes just those.
+type mpris struct {
+	conn   *dbus.Conn
+	filter string
+
+	mu     sync.Mutex
+	paused []string
+}
+
+// New connects to the session bus. name limits control to players whose
+// bus name contains it, e.g. "spotify"; empty means all.
+func New(name string) (Player, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, errors.Join(ErrUnsupported, err)
+	}
+	return &mpris{conn: conn, filter: strings.ToLower(name)}, nil
+}
+
+// players lists the matching players' bus names.
+func (m *mpris) players() ([]string, error) {
+	var names []string
+	if err := m.conn.BusObject().Call("org.freedesktop.DBus.ListNames", 0).Store(&names); err != nil {
+		return nil, err
+	}
+	var out []string
+	for _, n := range names {
+		if strings.HasPrefix(n, mprisPrefix) && strings.Contains(strings.ToLower(n[len(mprisPrefix):]), m.filter) {
+			out = append(out, n)
+		}
+	}
+	slices.Sort(out)
+	return out, nil
+}
+
+func (m *mpris) status(player string) string {
+	v, err := m.conn.Object(player, mprisPath).GetProperty(mprisPlayer + ".PlaybackStatus")
+	if err != nil {
+		return ""
+	}
+	s, _ := v.Value().(string)
+	return s
+}
+
+func (m *mpris) call(player, method string, args ...any) error {
+	return m.conn.Object(player, mprisPath).Call(mprisPlayer+"."+method, 0, args...).Err
+}
+
+func (m *mpris) Pause() error {
+	players, err := m.players()
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var errs []error
+	for _, p := range players {
+		if m.status(p) != "Playing" {
+			continue
+		}
+		if err := m.call(p, "Pause"); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !slices.Contains(m.paused, p) {
+			m.paused = append(m.paused, p)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *mpris) Play(uri string) error {
+	players, err := m.players()
+	if err != nil {
+		return err
+	}
+	if len(players) == 0 {
+		// nothing running: not worth an error every phase
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if uri != "" {
+		target := players[0]
+		if len(m.paused) > 0 && slices.Contains(players, m.paused[0]) {
+			target = m.paused[0]
+		}
+		m.paused = nil
+		return m.call(target, "OpenUri", uri)
+	}
+	targets := m.paused
+	m.paused = nil
+	if len(targets) == 0 {
+		targets = players[:1]
+	}
+	var errs []error
+	for _, p := range targets {
+		if !slices.Contains(players, p) || m.status(p) == "Playing" {
+			continue
+		}
+		if err := m.call(p, "Play"); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
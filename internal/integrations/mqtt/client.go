@@ -0,0 +1,484 @@
+// Package mqtt publishes the timer's state to an MQTT broker, for home
+// automation such as Home Assistant. It speaks just enough MQTT 3.1.1 for
+// that: connect (with a last will), publish at QoS 0 or 1, subscribe to
+// command topics, and ping.
+package mqtt
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// Message is a publication, also used for the last will.
+type Message struct {
+	Topic   string
+	Payload []byte
+	QoS     byte // 0 or 1
+	Retain  bool
+}
+
+// Client is a small MQTT client. It connects on first use and
+// reconnects on the next Publish or Connect after the connection drops.
+type Client struct {
+	// Broker is mqtt://[user:pass@]host[:1883] or mqtts:// (port 8883).
+	Broker string
+	// Username and Password, if set, override the broker URL's.
+	Username, Password string
+	ClientID           string
+	KeepAlive          time.Duration // default 60s
+	// Will is published by the broker if the connection drops.
+	Will *Message
+	TLS  *tls.Config // for mqtts; defaults to the system roots
+	// OnReconnect runs after a lost connection is reestablished, e.g. to
+	// publish retained state again.
+	OnReconnect func()
+
+	mu      sync.Mutex
+	conn    net.Conn
+	nextID  uint16
+	pending map[uint16]chan struct{} // acks awaited, by packet id
+	subs    map[string]func(Message)
+	dialed  bool // connected before
+	// after a failed connect, attempts fail fast with connErr until
+	// retryAt
+	retryAt time.Time
+	connErr error
+}
+
+// retryDelay spaces out connection attempts to an unreachable broker.
+const retryDelay = 5 * time.Second
+
+var errConnLost = errors.New("mqtt: connection lost")
+
+// Connect makes sure the client is connected, subscribing again to the
+// topics of Subscribe.
+func (c *Client) Connect(ctx context.Context) error {
+	c.mu.Lock()
+	reconnected, err := c.ensureLocked(ctx)
+	c.mu.Unlock()
+	if reconnected && c.OnReconnect != nil {
+		c.OnReconnect()
+	}
+	return err
+}
+
+// Publish sends msg, waiting for the broker's acknowledgement at QoS 1.
+// A broken connection is retried once on a fresh one.
+func (c *Client) Publish(ctx context.Context, msg Message) error {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		var ack chan struct{}
+		c.mu.Lock()
+		reconnected, cerr := c.ensureLocked(ctx)
+		if cerr == nil {
+			var id uint16
+			if msg.QoS > 0 {
+				id, ack = c.awaitLocked()
+			}
+			err = c.writeLocked(publishPacket(msg, id))
+		}
+		c.mu.Unlock()
+		if reconnected && c.OnReconnect != nil {
+			c.OnReconnect()
+		}
+		if cerr != nil {
+			return cerr
+		}
+		if err == nil {
+			if err = wait(ctx, ack); err == nil {
+				return nil
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return fmt.Errorf("mqtt: publish %s: %w", msg.Topic, err)
+}
+
+// Subscribe calls fn for every message on topic (no wildcards), from
+// now on and after reconnects.
+func (c *Client) Subscribe(ctx context.Context, topic string, fn func(Message)) error {
+	c.mu.Lock()
+	if c.subs == nil {
+		c.subs = map[string]func(Message){}
+	}
+	c.subs[topic] = fn
+	var ack chan struct{}
+	err := error(nil)
+	if c.conn != nil {
+		var id uint16
+		id, ack = c.awaitLocked()
+		err = c.writeLocked(subscribePacket(id, topic))
+	}
+	c.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if ack == nil {
+		return c.Connect(ctx) // subscribes on connect
+	}
+	return wait(ctx, ack)
+}
+
+// Close disconnects cleanly, so the will is not published.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.conn == nil {
+		return nil
+	}
+	_, err := c.conn.Write([]byte{0xe0, 0})
+	c.dropLocked()
+	return err
+}
+
+func wait(ctx context.Context, ack chan struct{}) error {
+	if ack == nil {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case _, ok := <-ack:
+		if !ok {
+			return errConnLost
+		}
+		return nil
+	}
+}
+
+// awaitLocked allocates a packet id and the channel its ack arrives on.
+func (c *Client) awaitLocked() (uint16, chan struct{}) {
+	c.nextID++
+	if c.nextID == 0 {
+		c.nextID = 1
+	}
+	ch := make(chan struct{}, 1)
+	c.pending[c.nextID] = ch
+	return c.nextID, ch
+}
+
+func (c *Client) writeLocked(p []byte) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.conn.Write(p); err != nil {
+		c.dropLocked()
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	return nil
+}
+
+// dropLocked closes the connection; waiters see errConnLost.
+func (c *Client) dropLocked() {
+	if c.conn == nil {
+		return
+	}
+	c.conn.Close()
+	c.conn = nil
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+}
+
+// ensureLocked connects if needed. reconnected reports a new connection
+// after an earlier one.
+func (c *Client) ensureLocked(ctx context.Context) (reconnected bool, err error) {
+	if c.conn != nil {
+		return false, nil
+	}
+	if time.Now().Before(c.retryAt) {
+		return false, c.connErr
+	}
+	if err := c.connectLocked(ctx); err != nil {
+		c.retryAt, c.connErr = time.Now().Add(retryDelay), err
+		return false, err
+	}
+	reconnected = c.dialed
+	c.dialed = true
+	return reconnected, nil
+}
+
+func (c *Client) connectLocked(ctx context.Context) error {
+	u, err := url.Parse(c.Broker)
+	if err != nil {
+		return fmt.Errorf("mqtt: broker: %w", err)
+	}
+	var d net.Dialer
+	var conn net.Conn
+	switch u.Scheme {
+	case "mqtt", "tcp":
+		conn, err = d.DialContext(ctx, "tcp", hostPort(u, "1883"))
+	case "mqtts", "ssl", "tls":
+		cfg := c.TLS
+		if cfg == nil {
+			cfg = &tls.Config{ServerName: u.Hostname()}
+		}
+		td := tls.Dialer{NetDialer: &d, Config: cfg}
+		conn, err = td.DialContext(ctx, "tcp", hostPort(u, "8883"))
+	default:
+		return fmt.Errorf("mqtt: broker %q: want mqtt:// or mqtts://", c.Broker)
+	}
+	if err != nil {
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	dl, ok := ctx.Deadline()
+	if !ok {
+		dl = time.Now().Add(10 * time.Second)
+	}
+	_ = conn.SetDeadline(dl)
+	keepAlive := cmp.Or(c.KeepAlive, 60*time.Second)
+	user, pass := u.User.Username(), ""
+	if u.User != nil {
+		pass, _ = u.User.Password()
+	}
+	if c.Username != "" {
+		user, pass = c.Username, c.Password
+	}
+	if _, err := conn.Write(connectPacket(cmp.Or(c.ClientID, "gopomodoro"), user, pass, keepAlive, c.Will)); err != nil {
+		conn.Close()
+		return fmt.Errorf("mqtt: %w", err)
+	}
+	r := bufio.NewReader(conn)
+	typ, body, err := readPacket(r)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("mqtt: connect: %w", err)
+	}
+	if typ != 0x20 || len(body) != 2 {
+		conn.Close()
+		return fmt.Errorf("mqtt: connect: unexpected packet %#x", typ)
+	}
+	if code := body[1]; code != 0 {
+		conn.Close()
+		return fmt.Errorf("mqtt: connect refused: %s", connackReason(code))
+	}
+	_ = conn.SetDeadline(time.Time{})
+	c.conn, c.pending = conn, map[uint16]chan struct{}{}
+	go c.read(conn, r, keepAlive)
+	go c.ping(conn, keepAlive/2)
+	for topic := range c.subs {
+		// the SUBACK is read by read; nothing to wait for here
+		id, _ := c.awaitLocked()
+		if err := c.writeLocked(subscribePacket(id, topic)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// read handles what the broker sends until the connection breaks.
+func (c *Client) read(conn net.Conn, r *bufio.Reader, keepAlive time.Duration) {
+	defer func() {
+		c.mu.Lock()
+		if c.conn == conn {
+			c.dropLocked()
+		}
+		c.mu.Unlock()
+	}()
+	for {
+		// pings go out every keepAlive/2, so silence means a dead link
+		_ = conn.SetReadDeadline(time.Now().Add(keepAlive + keepAlive/2))
+		first, err := r.Peek(1)
+		if err != nil {
+			return
+		}
+		flags := first[0] & 0x0f
+		typ, body, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		switch typ {
+		case 0x40, 0x90: // PUBACK, SUBACK
+			if len(body) < 2 {
+				return
+			}
+			c.mu.Lock()
+			id := binary.BigEndian.Uint16(body)
+			if ch, ok := c.pending[id]; ok {
+				ch <- struct{}{}
+				delete(c.pending, id)
+			}
+			c.mu.Unlock()
+		case 0x30: // PUBLISH
+			msg, id, ok := parsePublish(flags, body)
+			if !ok {
+				return
+			}
+			c.mu.Lock()
+			fn := c.subs[msg.Topic]
+			if msg.QoS > 0 && c.conn == conn {
+				_ = c.writeLocked(append([]byte{0x40, 2}, byte(id>>8), byte(id)))
+			}
+			c.mu.Unlock()
+			if fn != nil {
+				fn(msg)
+			}
+		}
+	}
+}
+
+// ping keeps an idle connection alive.
+func (c *Client) ping(conn net.Conn, every time.Duration) {
+	t := time.NewTicker(every)
+	defer t.Stop()
+	for range t.C {
+		c.mu.Lock()
+		if c.conn != conn {
+			c.mu.Unlock()
+			return
+		}
+		_ = c.writeLocked([]byte{0xc0, 0})
+		c.mu.Unlock()
+	}
+}
+
+func parsePublish(flags byte, body []byte) (Message, uint16, bool) {
+	if len(body) < 2 {
+		return Message{}, 0, false
+	}
+	n := int(binary.BigEndian.Uint16(body))
+	if len(body) < 2+n {
+		return Message{}, 0, false
+	}
+	msg := Message{Topic: string(body[2 : 2+n]), QoS: flags >> 1 & 3, Retain: flags&1 != 0}
+	rest := body[2+n:]
+	var id uint16
+	if msg.QoS > 0 {
+		if len(rest) < 2 {
+			return Message{}, 0, false
+		}
+		id, rest = binary.BigEndian.Uint16(rest), rest[2:]
+	}
+	msg.Payload = rest
+	return msg, id, true
+}
+
+func connectPacket(clientID, user, pass string, keepAlive time.Duration, will *Message) []byte {
+	var flags byte = 0x02 // clean session
+	var payload []byte
+	payload = appendString(payload, clientID)
+	if will != nil {
+		flags |= 0x04 | will.QoS<<3
+		if will.Retain {
+			flags |= 0x20
+		}
+		payload = appendString(payload, will.Topic)
+		payload = appendBytes(payload, will.Payload)
+	}
+	if user != "" {
+		flags |= 0x80
+		payload = appendString(payload, user)
+		if pass != "" {
+			flags |= 0x40
+			payload = appendString(payload, pass)
+		}
+	}
+	body := appendString(nil, "MQTT")
+	body = append(body, 4, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(min(keepAlive/time.Second, 0xffff)))
+	return packet(0x10, append(body, payload...))
+}
+
+func publishPacket(msg Message, id uint16) []byte {
+	typ := 0x30 | msg.QoS<<1
+	if msg.Retain {
+		typ |= 1
+	}
+	body := appendString(nil, msg.Topic)
+	if msg.QoS > 0 {
+		body = binary.BigEndian.AppendUint16(body, id)
+	}
+	return packet(typ, append(body, msg.Payload...))
+}
+
+// subscribePacket asks for topic at QoS 0.
+func subscribePacket(id uint16, topic string) []byte {
+	body := binary.BigEndian.AppendUint16(nil, id)
+	body = appendString(body, topic)
+	return packet(0x82, append(body, 0))
+}
+
+// packet adds the fixed header: type and the variable-length size.
+func packet(typ byte, body []byte) []byte {
+	out := []byte{typ}
+	n := len(body)
+	for {
+		b := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			b |= 0x80
+		}
+		out = append(out, b)
+		if n == 0 {
+			break
+		}
+	}
+	return append(out, body...)
+}
+
+// readPacket returns the type nibble (as the high bits of the first
+// byte) and body of the next packet.
+func readPacket(r *bufio.Reader) (byte, []byte, error) {
+	typ, err := r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, mult := 0, 1
+	for i := 0; ; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		n += int(b&0x7f) * mult
+		if b&0x80 == 0 {
+			break
+		}
+		if mult *= 128; i == 3 {
+			return 0, nil, errors.New("malformed packet length")
+		}
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return 0, nil, err
+	}
+	return typ & 0xf0, body, nil
+}
+
+func appendString(b []byte, s string) []byte { return appendBytes(b, []byte(s)) }
+
+func appendBytes(b, s []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(s)))
+	return append(b, s...)
+}
+
+// hostPort is the broker address, with port def unless given.
+func hostPort(u *url.URL, def string) string {
+	return net.JoinHostPort(u.Hostname(), cmp.Or(u.Port(), def))
+}
+
+func connackReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client ID rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad username or password"
+	case 5:
+		return "not authorized"
+	}
+	return fmt.Sprintf("code %d", code)
+}
@@ -0,0 +1,179 @@
+package mqtt
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Discovery announces the timer to Home Assistant through MQTT
+// discovery, so it shows up as a device with sensors for the phase and
+// remaining time and buttons to control it, without any YAML.
+type Discovery struct {
+	Prefix string // Home Assistant's discovery prefix; default "homeassistant"
+	NodeID string // unique per timer; default "gopomodoro"
+	Name   string // device name; default "GoPomodoro"
+}
+
+// haDevice groups the entities into one device.
+type haDevice struct {
+	Identifiers  []string `json:"identifiers"`
+	Name         string   `json:"name"`
+	Manufacturer string   `json:"manufacturer"`
+	Model        string   `json:"model"`
+}
+
+// haEntity is a discovery config payload.
+type haEntity struct {
+	Name              string   `json:"name"`
+	UniqueID          string   `json:"unique_id"`
+	Icon              string   `json:"icon,omitempty"`
+	StateTopic        string   `json:"state_topic,omitempty"`
+	ValueTemplate     string   `json:"value_template,omitempty"`
+	DeviceClass       string   `json:"device_class,omitempty"`
+	Unit              string   `json:"unit_of_measurement,omitempty"`
+	Options           []string `json:"options,omitempty"`
+	CommandTopic      string   `json:"command_topic,omitempty"`
+	PayloadPress      string   `json:"payload_press,omitempty"`
+	AvailabilityTopic string   `json:"availability_topic,omitempty"`
+	Device            haDevice `json:"device"`
+}
+
+// haButtons are the command buttons, by payload.
+var haButtons = []struct{ command, name, icon string }{
+	{"start", "Start", "mdi:play"},
+	{"pause", "Pause", "mdi:pause"},
+	{"resume", "Resume", "mdi:play-pause"},
+	{"skip", "Skip", "mdi:skip-next"},
+	{"stop", "Stop", "mdi:stop"},
+}
+
+// StatusTopic is where Home Assistant announces it came (back) online,
+// which asks for the discovery messages again.
+func (d Discovery) StatusTopic() string {
+	return cmp.Or(d.Prefix, "homeassistant") + "/status"
+}
+
+// Messages are the retained discovery configs for the entities whose
+// topics are set; buttons need t.Command.
+func (d Discovery) Messages(t Topics) ([]Message, error) {
+	prefix := cmp.Or(d.Prefix, "homeassistant")
+	node := nodeID(cmp.Or(d.NodeID, "gopomodoro"))
+	device := haDevice{
+		Identifiers:  []string{node},
+		Name:         cmp.Or(d.Name, "GoPomodoro"),
+		Manufacturer: "GoPomodoro",
+		Model:        "Pomodoro timer",
+	}
+	var msgs []Message
+	add := func(component, object string, e haEntity) error {
+		e.UniqueID = node + "_" + object
+		e.AvailabilityTopic = t.Availability
+		e.Device = device
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, Message{
+			Topic:   fmt.Sprintf("%s/%s/%s/%s/config", prefix, component, node, object),
+			Payload: data,
+			Retain:  true,
+		})
+		return nil
+	}
+	if t.Phase != "" {
+		err := add("sensor", "phase", haEntity{
+			Name:        "Phase",
+			Icon:        "mdi:timer-outline",
+			StateTopic:  t.Phase,
+			DeviceClass: "enum",
+			Options:     []string{"idle", "work", "short_break", "long_break", "paused"},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.Remaining != "" {
+		err := add("sensor", "remaining", haEntity{
+			Name:        "Remaining",
+			Icon:        "mdi:timer-sand",
+			StateTopic:  t.Remaining,
+			DeviceClass: "duration",
+			Unit:        "s",
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.State != "" {
+		err := add("sensor", "pomodoros", haEntity{
+			Name:          "Pomodoros",
+			Icon:          "mdi:counter",
+			StateTopic:    t.State,
+			ValueTemplate: "{{ value_json.pomodoro_done }}",
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if t.Command != "" {
+		for _, b := range haButtons {
+			err := add("button", b.command, haEntity{
+				Name:         b.name,
+				Icon:         b.icon,
+				CommandTopic: t.Command,
+				PayloadPress: b.command,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	return msgs, nil
+}
+
+// nodeID keeps the characters Home Assistant allows in discovery topics.
+func nodeID(s string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+			return r
+		}
+		return '_'
+	}, s)
+}
+
+// Command applies a command-topic payload to engine: start, pause,
+// resume, toggle, skip or stop.
+func Command(engine *core.PomodoroEngine, payload string) error {
+	st := engine.State()
+	switch strings.ToLower(strings.TrimSpace(payload)) {
+	case "start":
+		engine.Start()
+	case "pause":
+		engine.Pause()
+	case "resume":
+		engine.Resume()
+	case "toggle":
+		switch {
+		case st.Idle():
+			engine.Start()
+		case st.Overtime:
+			engine.Acknowledge()
+		case st.Paused:
+			engine.Resume()
+		default:
+			engine.Pause()
+		}
+	case "skip":
+		engine.Skip()
+	case "stop":
+		engine.Stop()
+	default:
+		return fmt.Errorf("mqtt: unknown command %q", payload)
+	}
+	return nil
+}
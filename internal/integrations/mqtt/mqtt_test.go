@@ -0,0 +1,349 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// fakeBroker accepts connections and records CONNECT and PUBLISH
+// packets, acknowledging them, and remembers subscriptions for send.
+type fakeBroker struct {
+	ln net.Listener
+
+	mu       sync.Mutex
+	connects []string // "client user pass will"
+	msgs     []string // "topic payload [retained]"
+	refuse   byte     // CONNACK return code
+	subs     map[string]net.Conn
+}
+
+func newFakeBroker(t *testing.T) *fakeBroker {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &fakeBroker{ln: ln, subs: map[string]net.Conn{}}
+	t.Cleanup(func() { ln.Close() })
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			go b.serve(conn)
+		}
+	}()
+	return b
+}
+
+func (b *fakeBroker) url() string { return "mqtt://" + b.ln.Addr().String() }
+
+func str(body []byte) (string, []byte) {
+	n := binary.BigEndian.Uint16(body)
+	return string(body[2 : 2+n]), body[2+n:]
+}
+
+func (b *fakeBroker) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	for {
+		first, err := r.Peek(1)
+		if err != nil {
+			return
+		}
+		flags := first[0] & 0x0f
+		typ, body, err := readPacket(r)
+		if err != nil {
+			return
+		}
+		b.mu.Lock()
+		switch typ {
+		case 0x10:
+			_, rest := str(body) // "MQTT"
+			connFlags := rest[1]
+			client, rest := str(rest[4:])
+			rec := client
+			will := ""
+			if connFlags&0x04 != 0 {
+				var topic, payload string
+				topic, rest = str(rest)
+				payload, rest = str(rest)
+				will = topic + "=" + payload
+			}
+			if connFlags&0x80 != 0 {
+				var user string
+				user, rest = str(rest)
+				rec += " " + user
+			}
+			if connFlags&0x40 != 0 {
+				pass, _ := str(rest)
+				rec += ":" + pass
+			}
+			b.connects = append(b.connects, rec+" "+will)
+			_, _ = conn.Write([]byte{0x20, 2, 0, b.refuse})
+		case 0x30:
+			topic, rest := str(body)
+			qos := flags >> 1 & 3
+			if qos > 0 {
+				_, _ = conn.Write(append([]byte{0x40, 2}, rest[:2]...))
+				rest = rest[2:]
+			}
+			m := topic + " " + string(rest)
+			if flags&1 != 0 {
+				m += " [retained]"
+			}
+			b.msgs = append(b.msgs, m)
+		case 0x80:
+			topic, _ := str(body[2:])
+			b.subs[topic] = conn
+			_, _ = conn.Write(append([]byte{0x90, 3}, body[0], body[1], 0))
+		case 0xc0:
+			_, _ = conn.Write([]byte{0xd0, 0})
+		case 0xe0:
+			b.mu.Unlock()
+			return
+		}
+		b.mu.Unlock()
+	}
+}
+
+// send publishes payload to the client subscribed to topic.
+func (b *fakeBroker) send(t *testing.T, topic, payload string) {
+	t.Helper()
+	b.mu.Lock()
+	conn := b.subs[topic]
+	b.mu.Unlock()
+	if conn == nil {
+		t.Fatalf("nobody subscribed to %s", topic)
+	}
+	_, _ = conn.Write(publishPacket(Message{Topic: topic, Payload: []byte(payload)}, 0))
+}
+
+func (b *fakeBroker) subscribed(topic string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.subs[topic] != nil
+}
+
+func (b *fakeBroker) got() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]string(nil), b.msgs...)
+}
+
+func TestClient_PublishQoS1WithAuth(t *testing.T) {
+	b := newFakeBroker(t)
+	c := &Client{
+		Broker:   strings.Replace(b.url(), "mqtt://", "mqtt://ha:secret@", 1),
+		ClientID: "desk",
+		Will:     &Message{Topic: "t/availability", Payload: []byte("offline"), Retain: true},
+	}
+	defer c.Close()
+	if err := c.Publish(context.Background(), Message{Topic: "t/phase", Payload: []byte("work"), QoS: 1, Retain: true}); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.got(); len(got) != 1 || got[0] != "t/phase work [retained]" {
+		t.Fatalf("messages %q", got)
+	}
+	if b.connects[0] != "desk ha:secret t/availability=offline" {
+		t.Fatalf("connect %q", b.connects[0])
+	}
+}
+
+func TestClient_Refused(t *testing.T) {
+	b := newFakeBroker(t)
+	b.refuse = 5
+	c := &Client{Broker: b.url()}
+	err := c.Publish(context.Background(), Message{Topic: "x"})
+	if err == nil || !strings.Contains(err.Error(), "not authorized") {
+		t.Fatalf("err = %v", err)
+	}
+	// then fails fast without dialing again
+	if err2 := c.Publish(context.Background(), Message{Topic: "x"}); err2 != err || len(b.connects) != 1 {
+		t.Fatalf("second err = %v after %d connects", err2, len(b.connects))
+	}
+}
+
+func TestPublisher_MirrorsState(t *testing.T) {
+	b := newFakeBroker(t)
+	c := &Client{Broker: b.url()}
+	defer c.Close()
+	var errs []error
+	p := NewPublisher(c, Options{Topics: DefaultTopics("home/pomodoro/"), QoS: 1, OnError: func(err error) { errs = append(errs, err) }})
+
+	start := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	p.Handle(core.Event{
+		Kind:      core.EventStart,
+		State:     core.State{Phase: core.PhaseWork, StartedAt: start, EndsAt: start.Add(25 * time.Minute), Task: core.Task{Title: "Report"}},
+		Remaining: 25 * time.Minute,
+		At:        start,
+	})
+	if len(errs) > 0 {
+		t.Fatal(errs)
+	}
+	got := b.got()
+	want := []string{
+		"home/pomodoro/event start",
+		`home/pomodoro/state {"phase":"work","name":"WORK","remaining_seconds":1500,"ends_at":"2026-03-02T09:25:00Z","pomodoro_done":0,"paused":false,"overtime":false,"task":"Report"} [retained]`,
+		"home/pomodoro/phase work [retained]",
+		"home/pomodoro/remaining 1500 [retained]",
+	}
+	for i, w := range want {
+		if len(got) != len(want) || got[i] != w {
+			t.Fatalf("messages\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+		}
+	}
+	if b.connects[0] != "gopomodoro home/pomodoro/availability=offline" {
+		t.Fatalf("connect %q", b.connects[0])
+	}
+}
+
+func TestPhase(t *testing.T) {
+	now := time.Now()
+	for _, tc := range []struct {
+		st   core.State
+		want string
+	}{
+		{core.State{}, "idle"},
+		{core.State{Phase: core.PhaseWork, StartedAt: now, Paused: true}, "paused"},
+		{core.State{Phase: core.PhaseShortBreak, StartedAt: now}, "short_break"},
+		{core.State{Phase: core.PhaseLongBreak, StartedAt: now}, "long_break"},
+	} {
+		if got := Phase(tc.st); got != tc.want {
+			t.Errorf("Phase(%+v) = %q, want %q", tc.st, got, tc.want)
+		}
+	}
+}
+
+func TestClient_Reconnects(t *testing.T) {
+	b := newFakeBroker(t)
+	reconnected := make(chan struct{}, 1)
+	c := &Client{Broker: b.url(), OnReconnect: func() { reconnected <- struct{}{} }}
+	defer c.Close()
+	ctx := context.Background()
+	if err := c.Publish(ctx, Message{Topic: "a", QoS: 1}); err != nil {
+		t.Fatal(err)
+	}
+	c.mu.Lock()
+	c.conn.Close() // the broker went away
+	c.mu.Unlock()
+	if err := c.Publish(ctx, Message{Topic: "b", QoS: 1}); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-reconnected:
+	case <-time.After(time.Second):
+		t.Fatal("OnReconnect not called")
+	}
+	if got := b.got(); len(got) != 2 || len(b.connects) != 2 {
+		t.Fatalf("messages %q after %d connects", got, len(b.connects))
+	}
+}
+
+func TestDiscovery_Messages(t *testing.T) {
+	topics := DefaultTopics("gopomodoro")
+	topics.Command = "gopomodoro/command"
+	msgs, err := Discovery{NodeID: "desk timer"}.Messages(topics)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, m := range msgs {
+		if !m.Retain {
+			t.Errorf("%s not retained", m.Topic)
+		}
+		names = append(names, m.Topic)
+	}
+	want := []string{
+		"homeassistant/sensor/desk_timer/phase/config",
+		"homeassistant/sensor/desk_timer/remaining/config",
+		"homeassistant/sensor/desk_timer/pomodoros/config",
+		"homeassistant/button/desk_timer/start/config",
+		"homeassistant/button/desk_timer/pause/config",
+		"homeassistant/button/desk_timer/resume/config",
+		"homeassistant/button/desk_timer/skip/config",
+		"homeassistant/button/desk_timer/stop/config",
+	}
+	if strings.Join(names, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("topics\n%s\nwant\n%s", strings.Join(names, "\n"), strings.Join(want, "\n"))
+	}
+	var remaining, start map[string]any
+	if err := json.Unmarshal(msgs[1].Payload, &remaining); err != nil {
+		t.Fatal(err)
+	}
+	if remaining["state_topic"] != "gopomodoro/remaining" || remaining["device_class"] != "duration" || remaining["unique_id"] != "desk_timer_remaining" {
+		t.Errorf("remaining config %v", remaining)
+	}
+	if err := json.Unmarshal(msgs[3].Payload, &start); err != nil {
+		t.Fatal(err)
+	}
+	if start["command_topic"] != "gopomodoro/command" || start["payload_press"] != "start" || start["availability_topic"] != "gopomodoro/availability" {
+		t.Errorf("start config %v", start)
+	}
+
+	// read-only: no buttons
+	msgs, _ = Discovery{}.Messages(DefaultTopics("gopomodoro"))
+	if len(msgs) != 3 {
+		t.Errorf("%d configs without a command topic", len(msgs))
+	}
+}
+
+func TestPublisher_Commands(t *testing.T) {
+	b := newFakeBroker(t)
+	c := &Client{Broker: b.url()}
+	topics := DefaultTopics("gopomodoro")
+	topics.Command = "gopomodoro/command"
+	p := NewPublisher(c, Options{Topics: topics, Discovery: &Discovery{}, OnError: func(err error) { t.Error(err) }})
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		p.Run(ctx, eng)
+	}()
+	defer func() {
+		cancel()
+		<-done
+	}()
+
+	waitFor := func(what string, ok func() bool) {
+		t.Helper()
+		for deadline := time.Now().Add(2 * time.Second); !ok(); time.Sleep(5 * time.Millisecond) {
+			if time.Now().After(deadline) {
+				t.Fatalf("timed out waiting for %s", what)
+			}
+		}
+	}
+	waitFor("subscription", func() bool { return b.subscribed("gopomodoro/command") })
+	b.send(t, "gopomodoro/command", "start")
+	waitFor("start", func() bool { return !eng.State().Idle() })
+	b.send(t, "gopomodoro/command", "toggle")
+	waitFor("pause", func() bool { return eng.State().Paused })
+
+	configs := func() int {
+		n := 0
+		for _, m := range b.got() {
+			if strings.HasPrefix(m, "homeassistant/") {
+				n++
+			}
+		}
+		return n
+	}
+	if n := configs(); n != 8 {
+		t.Fatalf("%d discovery configs", n)
+	}
+	// Home Assistant restarted and asks again
+	b.send(t, "homeassistant/status", "online")
+	waitFor("rediscovery", func() bool { return configs() == 16 })
+}
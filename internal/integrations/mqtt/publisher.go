@@ -0,0 +1,249 @@
+package mqtt
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Topics are where the Publisher publishes. Empty ones are off.
+type Topics struct {
+	State        string // JSON snapshot, retained
+	Phase        string // work, short_break, long_break, paused or idle, retained
+	Remaining    string // whole seconds left, retained
+	Event        string // engine event kind, e.g. "advance"
+	Availability string // "online", or "offline" through the last will
+	// Command is subscribed to for commands such as "start" (see
+	// Command); empty means read-only.
+	Command string
+}
+
+// DefaultTopics puts the published topics under prefix, e.g.
+// "gopomodoro/phase". Command stays empty.
+func DefaultTopics(prefix string) Topics {
+	prefix = strings.TrimSuffix(prefix, "/")
+	return Topics{
+		State:        prefix + "/state",
+		Phase:        prefix + "/phase",
+		Remaining:    prefix + "/remaining",
+		Event:        prefix + "/event",
+		Availability: prefix + "/availability",
+	}
+}
+
+// Options configures a Publisher.
+type Options struct {
+	Topics Topics
+	QoS    byte
+	// Interval is how often Remaining is refreshed while a phase runs;
+	// default 10s.
+	Interval time.Duration
+	Timeout  time.Duration // per publish; default 10s
+	// Discovery, if set, announces the timer to Home Assistant.
+	Discovery *Discovery
+	OnError   func(error)
+}
+
+// StateJSON is the payload of the State topic.
+type StateJSON struct {
+	Phase        string    `json:"phase"` // as on the Phase topic
+	Name         string    `json:"name"`
+	Remaining    int       `json:"remaining_seconds"`
+	EndsAt       time.Time `json:"ends_at,omitzero"`
+	PomodoroDone int       `json:"pomodoro_done"`
+	Paused       bool      `json:"paused"`
+	Overtime     bool      `json:"overtime"`
+	Task         string    `json:"task,omitempty"`
+}
+
+// Publisher mirrors engine state onto MQTT topics. Subscribe its Handle
+// method to an engine and run Run for the remaining-time updates.
+type Publisher struct {
+	client *Client
+	opts   Options
+
+	mu   sync.Mutex
+	last core.Event // for republishing after a reconnect
+}
+
+// NewPublisher publishes through client. It sets the client's last will
+// to mark the timer offline, and republishes the state on reconnect.
+func NewPublisher(client *Client, opts Options) *Publisher {
+	if opts.Interval <= 0 {
+		opts.Interval = 10 * time.Second
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	p := &Publisher{client: client, opts: opts}
+	if t := opts.Topics.Availability; t != "" {
+		client.Will = &Message{Topic: t, Payload: []byte("offline"), QoS: opts.QoS, Retain: true}
+	}
+	client.OnReconnect = func() { go p.republish() }
+	return p
+}
+
+// Phase is the Phase topic's value for st.
+func Phase(st core.State) string {
+	switch {
+	case st.Idle():
+		return "idle"
+	case st.Paused:
+		return "paused"
+	}
+	return strings.ToLower(st.Phase.String())
+}
+
+// Handle consumes one engine event.
+func (p *Publisher) Handle(ev core.Event) {
+	p.mu.Lock()
+	p.last = ev
+	p.mu.Unlock()
+	if t := p.opts.Topics.Event; t != "" {
+		p.publish(Message{Topic: t, Payload: []byte(ev.Kind.String())})
+	}
+	p.publishState(ev.State, ev.Remaining)
+}
+
+// Run refreshes the remaining time every Interval while a phase runs,
+// until ctx is done, then marks the timer offline.
+// With a Command topic it also applies commands to engine, keeping the
+// connection up while idle.
+func (p *Publisher) Run(ctx context.Context, engine *core.PomodoroEngine) {
+	p.discover()
+	if t := p.opts.Topics.Availability; t != "" {
+		p.publish(Message{Topic: t, Payload: []byte("online"), Retain: true})
+	}
+	p.publishState(engine.State(), engine.Remaining())
+	if d := p.opts.Discovery; d != nil {
+		p.subscribe(ctx, d.StatusTopic(), func(m Message) {
+			if string(m.Payload) == "online" {
+				go p.republish()
+			}
+		})
+	}
+	if t := p.opts.Topics.Command; t != "" {
+		p.subscribe(ctx, t, func(m Message) {
+			// a retained command would replay on every connect
+			if m.Retain {
+				return
+			}
+			if err := Command(engine, string(m.Payload)); err != nil {
+				p.opts.OnError(err)
+			}
+		})
+	}
+	tick := time.NewTicker(p.opts.Interval)
+	defer tick.Stop()
+	down := false
+	for {
+		select {
+		case <-ctx.Done():
+			if t := p.opts.Topics.Availability; t != "" {
+				p.publish(Message{Topic: t, Payload: []byte("offline"), Retain: true})
+			}
+			_ = p.client.Close()
+			return
+		case <-tick.C:
+			if p.opts.Topics.Command != "" {
+				// report a lost broker once, not every tick
+				err := p.client.Connect(ctx)
+				if err != nil && !down {
+					p.opts.OnError(err)
+				}
+				down = err != nil
+			}
+			if st := engine.State(); !st.Idle() && !st.Paused {
+				if t := p.opts.Topics.Remaining; t != "" {
+					p.publish(Message{Topic: t, Payload: seconds(engine.Remaining()), Retain: true})
+				}
+			}
+		}
+	}
+}
+
+// republish restores the retained topics on a new connection.
+func (p *Publisher) republish() {
+	p.mu.Lock()
+	ev := p.last
+	p.mu.Unlock()
+	p.discover()
+	if t := p.opts.Topics.Availability; t != "" {
+		p.publish(Message{Topic: t, Payload: []byte("online"), Retain: true})
+	}
+	if !ev.At.IsZero() {
+		p.publishState(ev.State, ev.Remaining)
+	}
+}
+
+// discover publishes the Home Assistant discovery configs, if enabled.
+func (p *Publisher) discover() {
+	if p.opts.Discovery == nil {
+		return
+	}
+	msgs, err := p.opts.Discovery.Messages(p.opts.Topics)
+	if err != nil {
+		p.opts.OnError(err)
+		return
+	}
+	for _, m := range msgs {
+		p.publish(m)
+	}
+}
+
+func (p *Publisher) subscribe(ctx context.Context, topic string, fn func(Message)) {
+	ctx, cancel := context.WithTimeout(ctx, p.opts.Timeout)
+	defer cancel()
+	// the subscription is kept and retried on reconnect even if this fails
+	if err := p.client.Subscribe(ctx, topic, fn); err != nil {
+		p.opts.OnError(err)
+	}
+}
+
+func (p *Publisher) publishState(st core.State, remaining time.Duration) {
+	t := p.opts.Topics
+	if t.State != "" {
+		data, err := json.Marshal(StateJSON{
+			Phase:        Phase(st),
+			Name:         st.Name(),
+			Remaining:    int(remaining.Round(time.Second) / time.Second),
+			EndsAt:       st.EndsAt,
+			PomodoroDone: st.PomodoroDone,
+			Paused:       st.Paused,
+			Overtime:     st.Overtime,
+			Task:         st.Task.Title,
+		})
+		if err != nil {
+			p.opts.OnError(err)
+		} else {
+			p.publish(Message{Topic: t.State, Payload: data, Retain: true})
+		}
+	}
+	if t.Phase != "" {
+		p.publish(Message{Topic: t.Phase, Payload: []byte(Phase(st)), Retain: true})
+	}
+	if t.Remaining != "" {
+		p.publish(Message{Topic: t.Remaining, Payload: seconds(remaining), Retain: true})
+	}
+}
+
+func (p *Publisher) publish(msg Message) {
+	msg.QoS = p.opts.QoS
+	ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+	defer cancel()
+	if err := p.client.Publish(ctx, msg); err != nil {
+		p.opts.OnError(err)
+	}
+}
+
+func seconds(d time.Duration) []byte {
+	return []byte(strconv.Itoa(int(d.Round(time.Second) / time.Second)))
+}
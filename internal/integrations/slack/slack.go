@@ -0,0 +1,151 @@
+// Package slack sets the user's Slack status and Do Not Disturb while a
+// work phase runs.
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultBaseURL is the Slack Web API endpoint.
+const DefaultBaseURL = "https://slack.com/api/"
+
+// Client is a minimal Slack Web API client for the status and DND
+// methods. It needs a user token with users.profile:write and dnd:write.
+type Client struct {
+	Token   string
+	BaseURL string       // defaults to DefaultBaseURL
+	HTTP    *http.Client // defaults to http.DefaultClient
+
+	// MaxRetries bounds how often a rate-limited call is retried.
+	MaxRetries int
+}
+
+// NewClient creates a Client for token.
+func NewClient(token string) *Client {
+	return &Client{Token: token, MaxRetries: 3}
+}
+
+// SetStatus sets the profile status. A zero expiration keeps it until
+// cleared.
+func (c *Client) SetStatus(ctx context.Context, text, emoji string, expiration time.Time) error {
+	var exp int64
+	if !expiration.IsZero() {
+		exp = expiration.Unix()
+	}
+	profile, err := json.Marshal(map[string]any{
+		"status_text":       text,
+		"status_emoji":      emoji,
+		"status_expiration": exp,
+	})
+	if err != nil {
+		return err
+	}
+	return c.call(ctx, "users.profile.set", url.Values{"profile": {string(profile)}})
+}
+
+// ClearStatus removes the profile status.
+func (c *Client) ClearStatus(ctx context.Context) error {
+	return c.SetStatus(ctx, "", "", time.Time{})
+}
+
+// SetSnooze turns on Do Not Disturb for d, rounded up to whole minutes.
+func (c *Client) SetSnooze(ctx context.Context, d time.Duration) error {
+	mins := int((d + time.Minute - 1) / time.Minute)
+	if mins < 1 {
+		mins = 1
+	}
+	return c.call(ctx, "dnd.setSnooze", url.Values{"num_minutes": {strconv.Itoa(mins)}})
+}
+
+// EndSnooze turns Do Not Disturb off. Ending a snooze that isn't active
+// is not an error.
+func (c *Client) EndSnooze(ctx context.Context) error {
+	err := c.call(ctx, "dnd.endSnooze", nil)
+	if e, ok := err.(*APIError); ok && e.Code == "snooze_not_active" {
+		return nil
+	}
+	return err
+}
+
+// APIError is an error reported by Slack in an ok=false response.
+type APIError struct {
+	Method string
+	Code   string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("slack %s: %s", e.Method, e.Code)
+}
+
+// call posts form to method, waiting out 429 responses as told by
+// Retry-After.
+func (c *Client) call(ctx context.Context, method string, form url.Values) error {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, base+method, strings.NewReader(form.Encode()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("slack %s: %w", method, err)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.MaxRetries {
+			resp.Body.Close()
+			if err := sleep(ctx, retryAfter(resp.Header)); err != nil {
+				return err
+			}
+			continue
+		}
+		var body struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		err = json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return fmt.Errorf("slack %s: status %s", method, resp.Status)
+		}
+		if err != nil {
+			return fmt.Errorf("slack %s: %w", method, err)
+		}
+		if !body.OK {
+			return &APIError{Method: method, Code: body.Error}
+		}
+		return nil
+	}
+}
+
+func retryAfter(h http.Header) time.Duration {
+	if s, err := strconv.Atoi(h.Get("Retry-After")); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second
+	}
+	return time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
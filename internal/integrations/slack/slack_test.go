@@ -0,0 +1,92 @@
+package slack
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type fakeSlack struct {
+	mu        sync.Mutex
+	calls     []string
+	status    map[string]any
+	limitNext bool
+}
+
+func (f *fakeSlack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer xoxp-test" {
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": false, "error": "invalid_auth"})
+		return
+	}
+	if f.limitNext {
+		f.limitNext = false
+		w.Header().Set("Retry-After", "0")
+		w.WriteHeader(http.StatusTooManyRequests)
+		return
+	}
+	method := r.URL.Path[1:]
+	f.calls = append(f.calls, method)
+	if method == "users.profile.set" {
+		f.status = nil
+		_ = json.Unmarshal([]byte(r.FormValue("profile")), &f.status)
+	}
+	_ = json.NewEncoder(w).Encode(map[string]any{"ok": true})
+}
+
+func TestStatusSync_FocusAndClear(t *testing.T) {
+	fake := &fakeSlack{limitNext: true}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+
+	client := NewClient("xoxp-test")
+	client.BaseURL = srv.URL + "/"
+	var errs []error
+	ss := NewStatusSync(client, Options{DND: true, OnError: func(err error) { errs = append(errs, err) }})
+
+	ends := time.Date(2025, 5, 1, 10, 25, 0, 0, time.Local)
+	work := core.State{Phase: core.PhaseWork, StartedAt: ends.Add(-25 * time.Minute), EndsAt: ends}
+	ss.Handle(core.Event{Kind: core.EventStart, State: work, Remaining: 25 * time.Minute})
+
+	if fake.status["status_text"] != "Focusing until 10:25" || fake.status["status_emoji"] != ":tomato:" {
+		t.Fatalf("unexpected status %v", fake.status)
+	}
+
+	brk := core.State{Phase: core.PhaseShortBreak, StartedAt: ends, EndsAt: ends.Add(5 * time.Minute)}
+	ss.Handle(core.Event{Kind: core.EventAdvance, State: brk, Remaining: 5 * time.Minute})
+	// a second break event must not clear again
+	ss.Handle(core.Event{Kind: core.EventResume, State: brk})
+
+	if len(errs) > 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	want := []string{"users.profile.set", "dnd.setSnooze", "users.profile.set", "dnd.endSnooze"}
+	if len(fake.calls) != len(want) {
+		t.Fatalf("calls: got %v, want %v", fake.calls, want)
+	}
+	for i := range want {
+		if fake.calls[i] != want[i] {
+			t.Fatalf("calls: got %v, want %v", fake.calls, want)
+		}
+	}
+	if fake.status["status_text"] != "" {
+		t.Fatalf("status not cleared: %v", fake.status)
+	}
+}
+
+func TestClient_APIError(t *testing.T) {
+	srv := httptest.NewServer(&fakeSlack{})
+	defer srv.Close()
+	client := NewClient("wrong")
+	client.BaseURL = srv.URL + "/"
+	err := client.ClearStatus(t.Context())
+	if e, ok := err.(*APIError); !ok || e.Code != "invalid_auth" {
+		t.Fatalf("expected invalid_auth, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package slack
+
+import (
+	"context"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Options configures a StatusSync.
+type Options struct {
+	Emoji   string // default ":tomato:"
+	Text    string // time.Format layout is appended; default "Focusing until"
+	DND     bool
+	Timeout time.Duration // per event; default 10s
+	OnError func(error)
+}
+
+// StatusSync mirrors work phases into Slack: status and DND on while
+// working, cleared when a break starts or the timer stops. Subscribe its
+// Handle method to an engine.
+type StatusSync struct {
+	client  *Client
+	opts    Options
+	focused bool
+}
+
+// NewStatusSync creates a StatusSync using client.
+func NewStatusSync(client *Client, opts Options) *StatusSync {
+	if opts.Emoji == "" {
+		opts.Emoji = ":tomato:"
+	}
+	if opts.Text == "" {
+		opts.Text = "Focusing until"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	return &StatusSync{client: client, opts: opts}
+}
+
+// Handle consumes one engine event. It makes blocking API calls, which is
+// fine on a subscriber's own goroutine.
+func (s *StatusSync) Handle(ev core.Event) {
+	st := ev.State
+	working := st.Phase == core.PhaseWork
+	switch ev.Kind {
+	case core.EventStart, core.EventAdvance, core.EventSkip, core.EventResume:
+		if working {
+			s.focus(st.EndsAt, ev.Remaining)
+		} else if s.focused {
+			s.clear()
+		}
+	case core.EventStop:
+		if s.focused {
+			s.clear()
+		}
+	}
+}
+
+func (s *StatusSync) focus(until time.Time, remaining time.Duration) {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+	s.focused = true
+	// open flow work has no end to show or snooze until
+	text := s.opts.Text
+	if !until.IsZero() {
+		text += " " + until.Local().Format("15:04")
+	}
+	if err := s.client.SetStatus(ctx, text, s.opts.Emoji, until); err != nil {
+		s.opts.OnError(err)
+	}
+	if s.opts.DND && remaining > 0 {
+		if err := s.client.SetSnooze(ctx, remaining); err != nil {
+			s.opts.OnError(err)
+		}
+	}
+}
+
+func (s *StatusSync) clear() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.opts.Timeout)
+	defer cancel()
+	s.focused = false
+	if err := s.client.ClearStatus(ctx); err != nil {
+		s.opts.OnError(err)
+	}
+	if s.opts.DND {
+		if err := s.client.EndSnooze(ctx); err != nil {
+			s.opts.OnError(err)
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package spotify
+
+import (
+	"cmp"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultAccountsURL is Spotify's OAuth server.
+	DefaultAccountsURL = "https://accounts.spotify.com/"
+	// DefaultRedirect must be added as a redirect URI in the app's
+	// settings on the Spotify developer dashboard.
+	DefaultRedirect = "http://127.0.0.1:8974/callback"
+	// Scopes are the permissions requested at login.
+	Scopes = "user-read-playback-state user-modify-playback-state"
+)
+
+// Token is an OAuth token, saved as JSON between runs.
+type Token struct {
+	AccessToken  string    `json:"access_token"`
+	RefreshToken string    `json:"refresh_token"`
+	Expiry       time.Time `json:"expiry"`
+}
+
+// Auth runs the authorization code flow with PKCE, which needs a client
+// ID but no secret.
+type Auth struct {
+	ClientID    string
+	Redirect    string // defaults to DefaultRedirect
+	AccountsURL string // defaults to DefaultAccountsURL
+	HTTP        *http.Client
+}
+
+// URL is the page where the user grants access.
+func (a Auth) URL(state, verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	q := url.Values{
+		"client_id":             {a.ClientID},
+		"response_type":         {"code"},
+		"redirect_uri":          {cmp.Or(a.Redirect, DefaultRedirect)},
+		"scope":                 {Scopes},
+		"state":                 {state},
+		"code_challenge_method": {"S256"},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(sum[:])},
+	}
+	return cmp.Or(a.AccountsURL, DefaultAccountsURL) + "authorize?" + q.Encode()
+}
+
+// Exchange trades the code from the redirect for a token.
+func (a Auth) Exchange(ctx context.Context, code, verifier string) (*Token, error) {
+	return a.token(ctx, url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {code},
+		"redirect_uri":  {cmp.Or(a.Redirect, DefaultRedirect)},
+		"client_id":     {a.ClientID},
+		"code_verifier": {verifier},
+	})
+}
+
+// Refresh gets a new access token. Spotify may rotate the refresh
+// token; the old one is kept if it doesn't.
+func (a Auth) Refresh(ctx context.Context, refresh string) (*Token, error) {
+	t, err := a.token(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {refresh},
+		"client_id":     {a.ClientID},
+	})
+	if err != nil {
+		return nil, err
+	}
+	t.RefreshToken = cmp.Or(t.RefreshToken, refresh)
+	return t, nil
+}
+
+func (a Auth) token(ctx context.Context, form url.Values) (*Token, error) {
+	client := a.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, cmp.Or(a.AccountsURL, DefaultAccountsURL)+"api/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("spotify token: %w", err)
+	}
+	defer resp.Body.Close()
+	var out struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		ExpiresIn    int    `json:"expires_in"`
+		Error        string `json:"error"`
+		Description  string `json:"error_description"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<16)).Decode(&out); err != nil {
+		return nil, fmt.Errorf("spotify token: status %d: %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK || out.AccessToken == "" {
+		return nil, fmt.Errorf("spotify token: status %d: %s %s", resp.StatusCode, out.Error, out.Description)
+	}
+	return &Token{
+		AccessToken:  out.AccessToken,
+		RefreshToken: out.RefreshToken,
+		Expiry:       time.Now().Add(time.Duration(out.ExpiresIn) * time.Second),
+	}, nil
+}
+
+// Login has the user grant access in the browser: open is called with
+// the consent page, and a server on the redirect address catches the
+// code.
+func (a Auth) Login(ctx context.Context, open func(url string) error) (*Token, error) {
+	redirect, err := url.Parse(cmp.Or(a.Redirect, DefaultRedirect))
+	if err != nil {
+		return nil, err
+	}
+	ln, err := net.Listen("tcp", redirect.Host)
+	if err != nil {
+		return nil, fmt.Errorf("spotify login: %w", err)
+	}
+	state, verifier := randomString(16), randomString(48)
+
+	type result struct {
+		code string
+		err  error
+	}
+	done := make(chan result, 1)
+	srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != redirect.Path {
+			http.NotFound(w, r)
+			return
+		}
+		q := r.URL.Query()
+		var res result
+		switch {
+		case q.Get("state") != state:
+			res.err = errors.New("spotify login: state mismatch")
+		case q.Get("error") != "":
+			res.err = fmt.Errorf("spotify login: %s", q.Get("error"))
+		default:
+			res.code = q.Get("code")
+		}
+		if res.err != nil {
+			http.Error(w, res.err.Error(), http.StatusBadRequest)
+		} else {
+			fmt.Fprintln(w, "GoPomodoro is connected to Spotify. You can close this tab.")
+		}
+		select {
+		case done <- res:
+		default:
+		}
+	})}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	if err := open(a.URL(state, verifier)); err != nil {
+		return nil, err
+	}
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return a.Exchange(ctx, res.code, verifier)
+	}
+}
+
+// randomString returns n random bytes, URL-safe base64 encoded.
+func randomString(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// LoadToken reads a token saved with SaveToken.
+func LoadToken(path string) (*Token, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var t Token
+	if err := json.Unmarshal(data, &t); err != nil {
+		return nil, fmt.Errorf("spotify token %s: %w", path, err)
+	}
+	return &t, nil
+}
+
+// SaveToken writes t to path, readable only by the user.
+func SaveToken(path string, t *Token) error {
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
@@ -0,0 +1,84 @@
+package spotify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/integrations/media"
+)
+
+// Player adapts a Client to media.Player. When Spotify isn't running on
+// any device it does nothing rather than fail every phase change.
+type Player struct {
+	Client *Client
+	// Device is the name or ID of the device to play on; default the
+	// active one, else the first available.
+	Device  string
+	Timeout time.Duration // per call; default 10s
+}
+
+// device picks the target device; ok is false if there is none.
+func (p *Player) device(ctx context.Context) (id string, ok bool, err error) {
+	devices, err := p.Client.Devices(ctx)
+	if err != nil || len(devices) == 0 {
+		return "", false, err
+	}
+	if p.Device != "" {
+		for _, d := range devices {
+			if d.ID == p.Device || strings.EqualFold(d.Name, p.Device) {
+				return d.ID, true, nil
+			}
+		}
+		return "", false, fmt.Errorf("spotify: no device %q is online", p.Device)
+	}
+	for _, d := range devices {
+		if d.IsActive {
+			return d.ID, true, nil
+		}
+	}
+	return devices[0].ID, true, nil
+}
+
+func (p *Player) withTimeout() (context.Context, context.CancelFunc) {
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
+
+func (p *Player) Play(uri string) error {
+	ctx, cancel := p.withTimeout()
+	defer cancel()
+	id, ok, err := p.device(ctx)
+	if !ok {
+		return err
+	}
+	return ignoreNoDevice(p.Client.Play(ctx, id, uri))
+}
+
+func (p *Player) Pause() error {
+	ctx, cancel := p.withTimeout()
+	defer cancel()
+	// pausing while paused is an error to Spotify
+	playing, err := p.Client.Playing(ctx)
+	if err != nil || !playing {
+		return err
+	}
+	return ignoreNoDevice(p.Client.Pause(ctx, ""))
+}
+
+// ignoreNoDevice drops the error for a device that went away meanwhile.
+func ignoreNoDevice(err error) error {
+	var e *APIError
+	if errors.As(err, &e) && e.Status == http.StatusNotFound && e.Reason == "NO_ACTIVE_DEVICE" {
+		return nil
+	}
+	return err
+}
+
+var _ media.Player = (*Player)(nil)
@@ -0,0 +1,233 @@
+// Package spotify starts a focus playlist on Spotify Connect when work
+// begins and pauses playback for breaks, through the Web API.
+package spotify
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// DefaultBaseURL is the Spotify Web API endpoint.
+const DefaultBaseURL = "https://api.spotify.com/v1/"
+
+// ErrNotLoggedIn is returned when there is no saved token.
+var ErrNotLoggedIn = errors.New("spotify: not logged in (run gopomodoro spotify login)")
+
+// Client is a minimal Spotify Web API client. It loads the token saved
+// by Login from TokenPath and refreshes it as needed.
+type Client struct {
+	Auth      Auth
+	TokenPath string
+	BaseURL   string       // defaults to DefaultBaseURL
+	HTTP      *http.Client // defaults to http.DefaultClient
+
+	// MaxRetries bounds how often a rate-limited call is retried.
+	MaxRetries int
+
+	mu    sync.Mutex
+	token *Token
+}
+
+// NewClient creates a Client for the app clientID.
+func NewClient(clientID, tokenPath string) *Client {
+	return &Client{Auth: Auth{ClientID: clientID}, TokenPath: tokenPath, MaxRetries: 3}
+}
+
+// APIError is a non-2xx response. Reason is Spotify's error reason,
+// e.g. NO_ACTIVE_DEVICE or PREMIUM_REQUIRED, if it gave one.
+type APIError struct {
+	Path    string
+	Status  int
+	Message string
+	Reason  string
+}
+
+func (e *APIError) Error() string {
+	msg := fmt.Sprintf("spotify %s: status %d", e.Path, e.Status)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	if e.Reason != "" {
+		msg += " (" + e.Reason + ")"
+	}
+	return msg
+}
+
+// Device is a Spotify Connect device.
+type Device struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Type     string `json:"type"`
+	IsActive bool   `json:"is_active"`
+}
+
+// Devices lists the devices Spotify is running on.
+func (c *Client) Devices(ctx context.Context) ([]Device, error) {
+	var out struct {
+		Devices []Device `json:"devices"`
+	}
+	if err := c.do(ctx, http.MethodGet, "me/player/devices", nil, &out); err != nil {
+		return nil, err
+	}
+	return out.Devices, nil
+}
+
+// Playing reports whether playback is running anywhere.
+func (c *Client) Playing(ctx context.Context) (bool, error) {
+	var out struct {
+		IsPlaying bool `json:"is_playing"`
+	}
+	// 204 (nothing to report) leaves out zero
+	err := c.do(ctx, http.MethodGet, "me/player", nil, &out)
+	return out.IsPlaying, err
+}
+
+// Play resumes playback on device, or starts contextURI (a playlist,
+// album or artist URI) if set.
+func (c *Client) Play(ctx context.Context, device, contextURI string) error {
+	var body any
+	if contextURI != "" {
+		body = map[string]string{"context_uri": contextURI}
+	}
+	return c.do(ctx, http.MethodPut, "me/player/play"+deviceQuery(device), body, nil)
+}
+
+// Pause pauses playback on device.
+func (c *Client) Pause(ctx context.Context, device string) error {
+	return c.do(ctx, http.MethodPut, "me/player/pause"+deviceQuery(device), nil, nil)
+}
+
+func deviceQuery(device string) string {
+	if device == "" {
+		return ""
+	}
+	return "?" + url.Values{"device_id": {device}}.Encode()
+}
+
+// accessToken returns a valid access token, refreshing and saving it
+// when it is about to expire or force is set.
+func (c *Client) accessToken(ctx context.Context, force bool) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.token == nil {
+		t, err := LoadToken(c.TokenPath)
+		if errors.Is(err, fs.ErrNotExist) {
+			return "", ErrNotLoggedIn
+		}
+		if err != nil {
+			return "", err
+		}
+		c.token = t
+	}
+	if force || time.Until(c.token.Expiry) < time.Minute {
+		t, err := c.Auth.Refresh(ctx, c.token.RefreshToken)
+		if err != nil {
+			return "", err
+		}
+		c.token = t
+		if err := SaveToken(c.TokenPath, t); err != nil {
+			return "", err
+		}
+	}
+	return c.token.AccessToken, nil
+}
+
+// do sends a JSON request and decodes the response into out, if set. It
+// refreshes the token once on 401 and waits out 429 responses as told
+// by Retry-After.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	base := cmp.Or(c.BaseURL, DefaultBaseURL)
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	refreshed := false
+	for attempt := 0; ; attempt++ {
+		token, err := c.accessToken(ctx, false)
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, method, base+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("spotify %s: %w", path, err)
+		}
+		switch {
+		case resp.StatusCode == http.StatusUnauthorized && !refreshed:
+			resp.Body.Close()
+			refreshed = true
+			if _, err := c.accessToken(ctx, true); err != nil {
+				return err
+			}
+			continue
+		case resp.StatusCode == http.StatusTooManyRequests && attempt < c.MaxRetries:
+			resp.Body.Close()
+			if err := sleep(ctx, retryAfter(resp.Header)); err != nil {
+				return err
+			}
+			continue
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			var e struct {
+				Error struct {
+					Message string `json:"message"`
+					Reason  string `json:"reason"`
+				} `json:"error"`
+			}
+			data, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+			_ = json.Unmarshal(data, &e)
+			return &APIError{Path: path, Status: resp.StatusCode, Message: e.Error.Message, Reason: e.Error.Reason}
+		}
+		if out == nil || resp.StatusCode == http.StatusNoContent {
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("spotify %s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+func retryAfter(h http.Header) time.Duration {
+	if s, err := strconv.Atoi(h.Get("Retry-After")); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second
+	}
+	return time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
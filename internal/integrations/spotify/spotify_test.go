@@ -0,0 +1,129 @@
+package spotify
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSpotify serves the token endpoint and the player API.
+type fakeSpotify struct {
+	mu       sync.Mutex
+	devices  []Device
+	playing  bool
+	calls    []string
+	refresh  int
+	rejectAt string // access token answered with 401 once
+}
+
+func (f *fakeSpotify) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.URL.Path == "/api/token" {
+		_ = r.ParseForm()
+		f.refresh++
+		_ = json.NewEncoder(w).Encode(map[string]any{"access_token": "fresh", "expires_in": 3600})
+		return
+	}
+	if auth := r.Header.Get("Authorization"); auth == "Bearer "+f.rejectAt {
+		f.rejectAt = ""
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	body, _ := io.ReadAll(r.Body)
+	f.calls = append(f.calls, strings.TrimSpace(r.Method+" "+r.URL.RequestURI()+" "+string(body)))
+	switch r.URL.Path {
+	case "/v1/me/player/devices":
+		_ = json.NewEncoder(w).Encode(map[string]any{"devices": f.devices})
+	case "/v1/me/player":
+		if !f.playing {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"is_playing": true})
+	case "/v1/me/player/play":
+		f.playing = true
+		w.WriteHeader(http.StatusNoContent)
+	case "/v1/me/player/pause":
+		f.playing = false
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func newTestPlayer(t *testing.T, f *fakeSpotify, token Token) *Player {
+	t.Helper()
+	srv := httptest.NewServer(f)
+	t.Cleanup(srv.Close)
+	path := filepath.Join(t.TempDir(), "token.json")
+	if err := SaveToken(path, &token); err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient("app", path)
+	c.BaseURL = srv.URL + "/v1/"
+	c.Auth.AccountsURL = srv.URL + "/"
+	return &Player{Client: c, Device: "Desk"}
+}
+
+func TestPlayer_PlaysOnDeviceAndPauses(t *testing.T) {
+	f := &fakeSpotify{
+		devices:  []Device{{ID: "phone", Name: "Phone", IsActive: true}, {ID: "desk", Name: "Desk"}},
+		rejectAt: "old",
+	}
+	p := newTestPlayer(t, f, Token{AccessToken: "old", RefreshToken: "r", Expiry: time.Now().Add(time.Hour)})
+
+	if err := p.Play("spotify:playlist:focus"); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Pause(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"GET /v1/me/player/devices",
+		`PUT /v1/me/player/play?device_id=desk {"context_uri":"spotify:playlist:focus"}`,
+		"GET /v1/me/player",
+		"PUT /v1/me/player/pause",
+		"GET /v1/me/player",
+	}
+	if strings.Join(f.calls, "\n") != strings.Join(want, "\n") {
+		t.Errorf("calls:\n%s\nwant:\n%s", strings.Join(f.calls, "\n"), strings.Join(want, "\n"))
+	}
+	if f.refresh != 1 {
+		t.Errorf("refreshed %d times after a 401, want 1", f.refresh)
+	}
+	saved, err := LoadToken(p.Client.TokenPath)
+	if err != nil || saved.AccessToken != "fresh" || saved.RefreshToken != "r" {
+		t.Errorf("saved token %+v, %v", saved, err)
+	}
+}
+
+func TestPlayer_NotRunning(t *testing.T) {
+	f := &fakeSpotify{}
+	p := newTestPlayer(t, f, Token{AccessToken: "a", RefreshToken: "r", Expiry: time.Now().Add(-time.Hour)})
+	if err := p.Play(""); err != nil {
+		t.Fatalf("play without devices: %v", err)
+	}
+	if f.refresh != 1 {
+		t.Errorf("expired token refreshed %d times, want 1", f.refresh)
+	}
+	if len(f.calls) != 1 {
+		t.Errorf("calls: %q", f.calls)
+	}
+}
+
+func TestClient_NotLoggedIn(t *testing.T) {
+	c := NewClient("app", filepath.Join(t.TempDir(), "missing.json"))
+	if _, err := c.Devices(t.Context()); err != ErrNotLoggedIn {
+		t.Fatalf("got %v, want ErrNotLoggedIn", err)
+	}
+}
@@ -0,0 +1,180 @@
+// Package taskfile reads tasks from a plain-text or Markdown to-do file
+// and counts the pomodoros spent on them in the file.
+//
+// One task per line, optionally as a Markdown list item or checkbox;
+// a trailing "(N)" estimates N pomodoros and "(D/N)" records D done so
+// far, with "?" for N when there's no estimate. Blank lines, headings
+// and checked items are skipped:
+//
+//	# Today
+//	- [ ] Write the report (1/4)
+//	- [ ] Review PRs (2)
+//	- [x] Answer mail
+package taskfile
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Source is the core.Task source name for tasks from the file.
+const Source = "file"
+
+// File is a task file on disk.
+type File struct {
+	path string
+	mu   sync.Mutex // serializes Increment's read-modify-write
+}
+
+// New returns the task file at path; a leading "~" is the home
+// directory.
+func New(path string) (*File, error) {
+	if path == "" {
+		return nil, errors.New("task file: path is required")
+	}
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return &File{path: path}, nil
+}
+
+// line is a parsed task line.
+type line struct {
+	prefix string // list marker and checkbox, kept on rewrite
+	title  string
+	done   int
+	est    int
+	counts bool // had a "(...)" suffix
+}
+
+var (
+	prefixRe = regexp.MustCompile(`^\s*(?:[-*+]\s+)?(?:\[([ xX])\]\s+)?`)
+	countsRe = regexp.MustCompile(`\s*\((?:(\d+)/)?(\d+|\?)\)\s*$`)
+)
+
+// parse reads a task from l; ok is false for lines that aren't open
+// tasks.
+func parse(l string) (ln line, ok bool) {
+	if strings.TrimSpace(l) == "" || strings.HasPrefix(strings.TrimSpace(l), "#") {
+		return line{}, false
+	}
+	m := prefixRe.FindStringSubmatch(l)
+	if m[1] == "x" || m[1] == "X" {
+		return line{}, false
+	}
+	ln.prefix = m[0]
+	rest := l[len(m[0]):]
+	if c := countsRe.FindStringSubmatchIndex(rest); c != nil {
+		if c[2] >= 0 {
+			ln.done, _ = strconv.Atoi(rest[c[2]:c[3]])
+		}
+		ln.est, _ = strconv.Atoi(rest[c[4]:c[5]]) // "?" stays 0
+		ln.counts = true
+		rest = rest[:c[0]]
+	}
+	ln.title = strings.TrimSpace(rest)
+	return ln, ln.title != ""
+}
+
+// String renders ln back as a line of the file.
+func (ln line) String() string {
+	s := ln.prefix + ln.title
+	switch {
+	case ln.done > 0 && ln.est == 0:
+		s += fmt.Sprintf(" (%d/?)", ln.done)
+	case ln.done > 0:
+		s += fmt.Sprintf(" (%d/%d)", ln.done, ln.est)
+	case ln.counts:
+		s += fmt.Sprintf(" (%d)", ln.est)
+	}
+	return s
+}
+
+// Tasks returns the open tasks in file order. A task's ID is its
+// title, so editing the counts doesn't detach it.
+func (f *File) Tasks(ctx context.Context) ([]core.Task, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, fmt.Errorf("task file: %w", err)
+	}
+	var tasks []core.Task
+	for _, l := range strings.Split(string(data), "\n") {
+		if ln, ok := parse(l); ok {
+			tasks = append(tasks, core.Task{Source: Source, ID: ln.title, Title: ln.title, Estimate: ln.est})
+		}
+	}
+	return tasks, nil
+}
+
+// Increment adds a done pomodoro to the task with the given ID.
+func (f *File) Increment(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return fmt.Errorf("task file: %w", err)
+	}
+	lines := strings.Split(string(data), "\n")
+	found := false
+	for i, l := range lines {
+		ln, ok := parse(l)
+		if !ok || ln.title != id {
+			continue
+		}
+		ln.done++
+		lines[i] = ln.String()
+		found = true
+		break
+	}
+	if !found {
+		return fmt.Errorf("task file: no open task %q", id)
+	}
+	// write beside the file and rename, so a crash can't truncate it
+	tmp := f.path + ".gopomodoro~"
+	if err := os.WriteFile(tmp, []byte(strings.Join(lines, "\n")), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// Tracker credits completed pomodoros to their task in the file.
+// Subscribe its Handle method to an engine.
+type Tracker struct {
+	file  *File
+	onErr func(error)
+	prev  core.State // state after the previous event
+}
+
+// NewTracker creates a Tracker writing to file; onErr, if set, gets
+// write failures.
+func NewTracker(file *File, onErr func(error)) *Tracker {
+	if onErr == nil {
+		onErr = func(error) {}
+	}
+	return &Tracker{file: file, onErr: onErr}
+}
+
+// Handle consumes one engine event.
+func (t *Tracker) Handle(ev core.Event) {
+	prev := t.prev
+	t.prev = ev.State
+	if !ev.CompletedWork(prev) || prev.Task.Source != Source {
+		return
+	}
+	if err := t.file.Increment(prev.Task.ID); err != nil {
+		t.onErr(err)
+	}
+}
@@ -0,0 +1,79 @@
+package taskfile
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+const sample = `# Today
+- [ ] Write the report (1/4)
+- [ ] Review PRs (2)
+* call the bank
+- [x] Answer mail (3)
+
+`
+
+func TestTasks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "today.md")
+	if err := os.WriteFile(path, []byte(sample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := New(path)
+	got, err := f.Tasks(context.Background())
+	if err != nil {
+		t.Fatalf("tasks: %v", err)
+	}
+	want := []core.Task{
+		{Source: Source, ID: "Write the report", Title: "Write the report", Estimate: 4},
+		{Source: Source, ID: "Review PRs", Title: "Review PRs", Estimate: 2},
+		{Source: Source, ID: "call the bank", Title: "call the bank"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestTracker_CountsCompletedWork(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "today.md")
+	if err := os.WriteFile(path, []byte(sample), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	f, _ := New(path)
+	tr := NewTracker(f, func(err error) { t.Fatal(err) })
+
+	work := func(title string) core.State {
+		return core.State{Phase: core.PhaseWork, StartedAt: time.Unix(1, 0), Task: core.Task{Source: Source, ID: title, Title: title}}
+	}
+	brk := core.State{Phase: core.PhaseShortBreak, StartedAt: time.Unix(2, 0)}
+	for _, title := range []string{"Write the report", "Review PRs", "call the bank"} {
+		tr.Handle(core.Event{Kind: core.EventStart, State: work(title)})
+		tr.Handle(core.Event{Kind: core.EventAdvance, State: brk})
+	}
+	// skipped work isn't credited
+	tr.Handle(core.Event{Kind: core.EventStart, State: work("Review PRs")})
+	tr.Handle(core.Event{Kind: core.EventSkip, State: brk})
+	// the last pomodoro of a -cycles run ends in a stop
+	tr.Handle(core.Event{Kind: core.EventStart, State: work("call the bank")})
+	tr.Handle(core.Event{Kind: core.EventStop, Finished: true})
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `# Today
+- [ ] Write the report (2/4)
+- [ ] Review PRs (1/2)
+* call the bank (2/?)
+- [x] Answer mail (3)
+
+`
+	if string(data) != want {
+		t.Fatalf("file:\n%s\nwant:\n%s", data, want)
+	}
+}
@@ -0,0 +1,131 @@
+// Package taskwarrior lists pending taskwarrior tasks and tracks time on
+// them with task start/stop while a work phase runs.
+package taskwarrior
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Source is the core.Task source name for taskwarrior tasks.
+const Source = "taskwarrior"
+
+// Client runs the task command.
+type Client struct {
+	// Bin is the task executable; default "task".
+	Bin string
+	// Filter narrows the pending tasks offered, e.g. "+work".
+	Filter string
+	// Run executes Bin; tests replace it. Nil runs the real command.
+	Run func(ctx context.Context, name string, args ...string) ([]byte, error)
+}
+
+// NewClient returns a Client running "task" with filter.
+func NewClient(filter string) *Client {
+	return &Client{Filter: filter}
+}
+
+// base turns off prompts and chatter for every call.
+var base = []string{"rc.confirmation=off", "rc.verbose=nothing"}
+
+func (c *Client) run(ctx context.Context, args ...string) ([]byte, error) {
+	bin := cmp.Or(c.Bin, "task")
+	args = append(slices.Clone(base), args...)
+	if c.Run != nil {
+		return c.Run(ctx, bin, args...)
+	}
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, bin, args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return out, fmt.Errorf("task %s: %w: %s", strings.Join(args[len(base):], " "), err, msg)
+		}
+		return out, fmt.Errorf("task %s: %w", strings.Join(args[len(base):], " "), err)
+	}
+	return out, nil
+}
+
+func (c *Client) export(ctx context.Context, filter ...string) ([]map[string]any, error) {
+	out, err := c.run(ctx, append(filter, "export")...)
+	if err != nil {
+		return nil, err
+	}
+	var tasks []map[string]any
+	if err := json.Unmarshal(out, &tasks); err != nil {
+		return nil, fmt.Errorf("task export: %w", err)
+	}
+	return tasks, nil
+}
+
+// Tasks returns the pending tasks matching the filter, most urgent
+// first. Titles carry the project, e.g. "home: fix the sink".
+func (c *Client) Tasks(ctx context.Context) ([]core.Task, error) {
+	filter := []string{"status:pending"}
+	if c.Filter != "" {
+		filter = append(filter, strings.Fields(c.Filter)...)
+	}
+	raw, err := c.export(ctx, filter...)
+	if err != nil {
+		return nil, err
+	}
+	slices.SortStableFunc(raw, func(a, b map[string]any) int {
+		ua, _ := a["urgency"].(float64)
+		ub, _ := b["urgency"].(float64)
+		return cmp.Compare(ub, ua)
+	})
+	out := make([]core.Task, 0, len(raw))
+	for _, t := range raw {
+		uuid, _ := t["uuid"].(string)
+		title, _ := t["description"].(string)
+		if project, _ := t["project"].(string); project != "" {
+			title = project + ": " + title
+		}
+		out = append(out, core.Task{Source: Source, ID: uuid, Title: title})
+	}
+	return out, nil
+}
+
+// Start marks the task active, so taskwarrior (and timewarrior's hook)
+// count time on it.
+func (c *Client) Start(ctx context.Context, uuid string) error {
+	_, err := c.run(ctx, uuid, "start")
+	return err
+}
+
+// Stop marks the task inactive.
+func (c *Client) Stop(ctx context.Context, uuid string) error {
+	_, err := c.run(ctx, uuid, "stop")
+	return err
+}
+
+// Increment adds one to the numeric UDA on the task, e.g. a
+// "pomodoros" counter.
+func (c *Client) Increment(ctx context.Context, uuid, uda string) error {
+	raw, err := c.export(ctx, uuid)
+	if err != nil {
+		return err
+	}
+	if len(raw) == 0 {
+		return fmt.Errorf("task %s not found", uuid)
+	}
+	var n int64
+	switch v := raw[0][uda].(type) {
+	case float64:
+		n = int64(v)
+	case string:
+		n, _ = strconv.ParseInt(v, 10, 64)
+	}
+	_, err = c.run(ctx, uuid, "modify", fmt.Sprintf("%s:%d", uda, n+1))
+	return err
+}
@@ -0,0 +1,82 @@
+package taskwarrior
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// fakeTask records task invocations and answers exports with out.
+type fakeTask struct {
+	calls []string
+	out   string
+}
+
+func (f *fakeTask) run(_ context.Context, _ string, args ...string) ([]byte, error) {
+	cmd := strings.Join(args[len(base):], " ")
+	f.calls = append(f.calls, cmd)
+	if strings.HasSuffix(cmd, "export") {
+		return []byte(f.out), nil
+	}
+	return nil, nil
+}
+
+func TestTasks_SortedByUrgency(t *testing.T) {
+	f := &fakeTask{out: `[{"uuid":"a","description":"low","urgency":1},
+		{"uuid":"b","description":"high","project":"home","urgency":9}]`}
+	c := &Client{Filter: "+work", Run: f.run}
+	got, err := c.Tasks(context.Background())
+	if err != nil {
+		t.Fatalf("tasks: %v", err)
+	}
+	want := []core.Task{
+		{Source: Source, ID: "b", Title: "home: high"},
+		{Source: Source, ID: "a", Title: "low"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if f.calls[0] != "status:pending +work export" {
+		t.Fatalf("unexpected command %q", f.calls[0])
+	}
+}
+
+func TestTracker(t *testing.T) {
+	f := &fakeTask{out: `[{"uuid":"a","pomodoros":2}]`}
+	tr := NewTracker(&Client{Run: f.run}, Options{UDA: "pomodoros", OnError: func(err error) { t.Fatal(err) }})
+
+	task := core.Task{Source: Source, ID: "a", Title: "write"}
+	work := core.State{Phase: core.PhaseWork, StartedAt: time.Unix(1, 0), Task: task}
+	paused := work
+	paused.Paused = true
+	brk := core.State{Phase: core.PhaseShortBreak, StartedAt: time.Unix(1, 0), Task: task}
+
+	tr.Handle(core.Event{Kind: core.EventStart, State: work})
+	tr.Handle(core.Event{Kind: core.EventPause, State: paused})
+	tr.Handle(core.Event{Kind: core.EventResume, State: work})
+	tr.Handle(core.Event{Kind: core.EventAdvance, State: brk})
+
+	want := []string{"a start", "a stop", "a start", "a stop", "a export", "a modify pomodoros:3"}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Fatalf("calls = %q, want %q", f.calls, want)
+	}
+}
+
+func TestTracker_FinishedRun(t *testing.T) {
+	f := &fakeTask{out: `[{"uuid":"a","pomodoros":2}]`}
+	tr := NewTracker(&Client{Run: f.run}, Options{UDA: "pomodoros", OnError: func(err error) { t.Fatal(err) }})
+
+	work := core.State{Phase: core.PhaseWork, StartedAt: time.Unix(1, 0), Task: core.Task{Source: Source, ID: "a", Title: "write"}}
+	tr.Handle(core.Event{Kind: core.EventStart, State: work})
+	// the last pomodoro of a -cycles run ends in a stop
+	tr.Handle(core.Event{Kind: core.EventStop, Finished: true})
+
+	want := []string{"a start", "a stop", "a export", "a modify pomodoros:3"}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Fatalf("calls = %q, want %q", f.calls, want)
+	}
+}
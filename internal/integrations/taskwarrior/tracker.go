@@ -0,0 +1,71 @@
+package taskwarrior
+
+import (
+	"context"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Options configures a Tracker.
+type Options struct {
+	// UDA names a numeric user-defined attribute bumped for every
+	// completed pomodoro, e.g. "pomodoros"; empty turns it off.
+	UDA     string
+	Timeout time.Duration // per event; default 10s
+	OnError func(error)
+}
+
+// Tracker keeps the attached taskwarrior task started while a work
+// phase runs and stopped otherwise, and credits completed pomodoros to
+// it. Subscribe its Handle method to an engine.
+type Tracker struct {
+	client *Client
+	opts   Options
+	active string     // uuid of the task we started
+	prev   core.State // state after the previous event
+}
+
+// NewTracker creates a Tracker using client.
+func NewTracker(client *Client, opts Options) *Tracker {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	return &Tracker{client: client, opts: opts}
+}
+
+// Handle consumes one engine event. It runs the task command, which is
+// fine on a subscriber's own goroutine.
+func (t *Tracker) Handle(ev core.Event) {
+	ctx, cancel := context.WithTimeout(context.Background(), t.opts.Timeout)
+	defer cancel()
+	st := ev.State
+	prev := t.prev
+	t.prev = st
+
+	want := ""
+	if st.Phase == core.PhaseWork && !st.Paused && st.Task.Source == Source {
+		want = st.Task.ID
+	}
+	if t.active != "" && t.active != want {
+		if err := t.client.Stop(ctx, t.active); err != nil {
+			t.opts.OnError(err)
+		}
+		t.active = ""
+	}
+	if ev.CompletedWork(prev) && prev.Task.Source == Source && t.opts.UDA != "" {
+		if err := t.client.Increment(ctx, prev.Task.ID, t.opts.UDA); err != nil {
+			t.opts.OnError(err)
+		}
+	}
+	if want != "" && t.active == "" {
+		if err := t.client.Start(ctx, want); err != nil {
+			t.opts.OnError(err)
+			return
+		}
+		t.active = want
+	}
+}
@@ -0,0 +1,183 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Help lists the bot's commands.
+const Help = `/status: what the timer is doing
+/work: start a pomodoro, or resume
+/pause, /resume
+/skip: end the phase early
+/stop [why]: stop the timer
+/undo: take back the last skip, stop or extension
+/ack: end overtime and take the break`
+
+// commands maps a chat command to the engine's control action.
+var commands = map[string]string{
+	"pause":  "pause",
+	"resume": "resume",
+	"skip":   "skip",
+	"stop":   "stop",
+	"undo":   "undo",
+	"ack":    "acknowledge",
+}
+
+// Options configures a Bot.
+type Options struct {
+	// Chats may send commands; messages from others are ignored, but
+	// for /start, which tells the chat its ID.
+	Chats []int64
+	// Wait is how long each poll waits for a message; default 30s.
+	Wait time.Duration
+	// Retry is the pause after a failed poll; default 10s.
+	Retry   time.Duration
+	OnError func(error)
+}
+
+// Bot applies the commands the allowed chats send to an engine,
+// replying with the timer's status.
+type Bot struct {
+	client *Client
+	engine *core.PomodoroEngine
+	opts   Options
+}
+
+// NewBot creates a Bot controlling engine.
+func NewBot(client *Client, engine *core.PomodoroEngine, opts Options) *Bot {
+	if opts.Wait <= 0 {
+		opts.Wait = 30 * time.Second
+	}
+	if opts.Retry <= 0 {
+		opts.Retry = 10 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	return &Bot{client: client, engine: engine, opts: opts}
+}
+
+// Run polls for commands until ctx is done. Commands sent before it
+// started are dropped rather than replayed on a timer that moved on. A
+// lost connection is reported once, not on every retry.
+func (b *Bot) Run(ctx context.Context) {
+	// the first poll doesn't wait and only skips the backlog
+	offset, wait := int64(-1), time.Duration(0)
+	down := false
+	for ctx.Err() == nil {
+		updates, err := b.client.Updates(ctx, offset, wait)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			if !down {
+				b.opts.OnError(err)
+			}
+			down = true
+			select {
+			case <-ctx.Done():
+			case <-time.After(b.opts.Retry):
+			}
+			continue
+		}
+		down = false
+		for _, u := range updates {
+			if wait > 0 && u.Message != nil {
+				b.handle(ctx, *u.Message)
+			}
+			offset = u.ID + 1
+		}
+		offset = max(offset, 0)
+		wait = b.opts.Wait
+	}
+}
+
+// handle answers one message.
+func (b *Bot) handle(ctx context.Context, m Message) {
+	name, arg, _ := strings.Cut(strings.TrimSpace(m.Text), " ")
+	if !strings.HasPrefix(name, "/") {
+		return
+	}
+	// in groups commands may be addressed, e.g. /pause@pomodoro_bot
+	name, _, _ = strings.Cut(strings.TrimPrefix(name, "/"), "@")
+	allowed := slices.Contains(b.opts.Chats, m.Chat.ID)
+	if name == "start" {
+		text := Help
+		if !allowed {
+			text = fmt.Sprintf("This chat isn't allowed to control the timer. Add %d to chats in [integrations.telegram].", m.Chat.ID)
+		}
+		b.reply(ctx, m.Chat.ID, text)
+		return
+	}
+	if !allowed {
+		return
+	}
+	b.reply(ctx, m.Chat.ID, b.command(name, strings.TrimSpace(arg)))
+}
+
+// command applies the named command, returning the reply.
+func (b *Bot) command(name, arg string) string {
+	var err error
+	switch action, ok := commands[name]; {
+	case name == "status":
+	case name == "help":
+		return Help
+	case name == "work":
+		// like the TUI's start: resume a paused phase, else start
+		if err = b.engine.Resume(); errors.Is(err, core.ErrNotRunning) {
+			err = b.engine.Start()
+		}
+	case ok:
+		err = b.engine.Do(core.Command{Action: action, Note: arg})
+	default:
+		return "Unknown command.\n\n" + Help
+	}
+	if err != nil {
+		return err.Error()
+	}
+	return Status(b.engine.State(), b.engine.Remaining(), b.engine.Overtime())
+}
+
+// Status describes the timer, e.g. "WORK, 12:05 left, 2 🍅 done".
+func Status(st core.State, remaining, overtime time.Duration) string {
+	if st.Idle() {
+		return fmt.Sprintf("Idle, %d 🍅 done", st.PomodoroDone)
+	}
+	parts := []string{st.Name()}
+	switch {
+	case st.Overtime:
+		parts = append(parts, "overtime +"+clock(overtime))
+	case st.Open:
+		parts = append(parts, "flow")
+	default:
+		parts = append(parts, clock(remaining)+" left")
+	}
+	if st.Paused {
+		parts = append(parts, "paused")
+	}
+	if st.Task.Title != "" {
+		parts = append(parts, "on "+st.Task.Title)
+	}
+	parts = append(parts, fmt.Sprintf("%d 🍅 done", st.PomodoroDone))
+	return strings.Join(parts, ", ")
+}
+
+// clock formats d as minutes and seconds, e.g. "12:05".
+func clock(d time.Duration) string {
+	d = d.Round(time.Second)
+	return fmt.Sprintf("%d:%02d", int(d/time.Minute), int(d%time.Minute/time.Second))
+}
+
+// reply sends text to chat, reporting a failure.
+func (b *Bot) reply(ctx context.Context, chat int64, text string) {
+	if err := b.client.SendMessage(ctx, chat, text, false); err != nil {
+		b.opts.OnError(err)
+	}
+}
@@ -0,0 +1,163 @@
+// Package telegram sends notifications through a Telegram bot and
+// takes timer commands, such as /pause, from the chats allowed to.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+)
+
+// DefaultBaseURL is the Telegram Bot API endpoint.
+const DefaultBaseURL = "https://api.telegram.org"
+
+// Client is a minimal Telegram Bot API client for sending messages and
+// polling updates.
+type Client struct {
+	Token   string
+	BaseURL string       // defaults to DefaultBaseURL
+	HTTP    *http.Client // defaults to http.DefaultClient
+}
+
+// NewClient creates a Client for the bot token BotFather gave.
+func NewClient(token string) *Client {
+	return &Client{Token: token}
+}
+
+// APIError is an error reported by Telegram in an ok=false response.
+type APIError struct {
+	Method      string
+	Code        int
+	Description string
+}
+
+func (e *APIError) Error() string {
+	return fmt.Sprintf("telegram %s: %d %s", e.Method, e.Code, e.Description)
+}
+
+// Update is an incoming update; only messages are asked for.
+type Update struct {
+	ID      int64    `json:"update_id"`
+	Message *Message `json:"message"`
+}
+
+// Message is a chat message.
+type Message struct {
+	Chat struct {
+		ID int64 `json:"id"`
+	} `json:"chat"`
+	Text string `json:"text"`
+}
+
+// SendMessage posts text to chat, without sound when silent.
+func (c *Client) SendMessage(ctx context.Context, chat int64, text string, silent bool) error {
+	return c.call(ctx, "sendMessage", map[string]any{
+		"chat_id":              chat,
+		"text":                 text,
+		"disable_notification": silent,
+	}, nil)
+}
+
+// Updates long-polls for messages from offset on, waiting up to wait
+// for one to arrive.
+func (c *Client) Updates(ctx context.Context, offset int64, wait time.Duration) ([]Update, error) {
+	var out []Update
+	err := c.call(ctx, "getUpdates", map[string]any{
+		"offset":          offset,
+		"timeout":         int(wait / time.Second),
+		"allowed_updates": []string{"message"},
+	}, &out)
+	return out, err
+}
+
+// call posts params to method and decodes its result into out, if set.
+// Errors never carry the request URL, which holds the token.
+func (c *Client) call(ctx context.Context, method string, params map[string]any, out any) error {
+	base := c.BaseURL
+	if base == "" {
+		base = DefaultBaseURL
+	}
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(base, "/")+"/bot"+c.Token+"/"+method, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("telegram %s: bad request", method)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		var uerr *url.Error
+		if errors.As(err, &uerr) {
+			err = uerr.Err
+		}
+		return fmt.Errorf("telegram %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+	var body struct {
+		OK          bool            `json:"ok"`
+		ErrorCode   int             `json:"error_code"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return fmt.Errorf("telegram %s: status %s", method, resp.Status)
+	}
+	if !body.OK {
+		return &APIError{Method: method, Code: body.ErrorCode, Description: body.Description}
+	}
+	if out == nil {
+		return nil
+	}
+	return json.Unmarshal(body.Result, out)
+}
+
+// Notifier sends notifications to chats through a bot.
+type Notifier struct {
+	Client *Client
+	Chats  []int64
+	// Timeout bounds each message; default 10s.
+	Timeout time.Duration
+}
+
+func (n *Notifier) Notify(title, body string) error {
+	return n.NotifyMessage(notify.Message{Title: title, Body: body})
+}
+
+// NotifyMessage sends msg to every chat; a silent one arrives without
+// sound.
+func (n *Notifier) NotifyMessage(msg notify.Message) error {
+	text := msg.Body
+	if msg.Title != "" {
+		text = msg.Title + "\n" + msg.Body
+	}
+	timeout := n.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	var errs []error
+	for _, chat := range n.Chats {
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		errs = append(errs, n.Client.SendMessage(ctx, chat, text, msg.Silent))
+		cancel()
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	_ notify.Notifier        = (*Notifier)(nil)
+	_ notify.MessageNotifier = (*Notifier)(nil)
+)
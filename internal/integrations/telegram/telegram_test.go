@@ -0,0 +1,164 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+)
+
+type sent struct {
+	Chat   int64  `json:"chat_id"`
+	Text   string `json:"text"`
+	Silent bool   `json:"disable_notification"`
+}
+
+// fakeTelegram serves queued updates and records sent messages.
+type fakeTelegram struct {
+	mu      sync.Mutex
+	updates []Update
+	sent    []sent
+	polled  chan int64 // each poll's offset
+}
+
+func (f *fakeTelegram) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, method, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/bot"), "/")
+	if token != "123:secret" {
+		w.WriteHeader(http.StatusUnauthorized)
+		_, _ = w.Write([]byte(`{"ok":false,"error_code":401,"description":"Unauthorized"}`))
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch method {
+	case "getUpdates":
+		var req struct {
+			Offset int64 `json:"offset"`
+		}
+		_ = json.NewDecoder(r.Body).Decode(&req)
+		var out []Update
+		for _, u := range f.updates {
+			if req.Offset < 0 || u.ID >= req.Offset {
+				out = append(out, u)
+			}
+		}
+		if req.Offset < 0 && len(out) > 0 {
+			out = out[len(out)-1:]
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{"ok": true, "result": out})
+		select {
+		case f.polled <- req.Offset:
+		default:
+		}
+	case "sendMessage":
+		var m sent
+		_ = json.NewDecoder(r.Body).Decode(&m)
+		f.sent = append(f.sent, m)
+		_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+	}
+}
+
+func (f *fakeTelegram) send(id, chat int64, text string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	m := &Message{Text: text}
+	m.Chat.ID = chat
+	f.updates = append(f.updates, Update{ID: id, Message: m})
+}
+
+func TestBot_Commands(t *testing.T) {
+	fake := &fakeTelegram{polled: make(chan int64, 16)}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	client := NewClient("123:secret")
+	client.BaseURL = srv.URL
+
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	eng.Start()
+	fake.send(1, 42, "/pause") // sent before the bot ran
+	bot := NewBot(client, eng, Options{Chats: []int64{42}, Wait: time.Millisecond, Retry: time.Millisecond})
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		bot.Run(ctx)
+	}()
+	// wait for the backlog to be skipped
+	for off := int64(-1); off != 2; off = <-fake.polled {
+	}
+
+	fake.send(2, 7, "/stop")
+	fake.send(3, 7, "/start")
+	fake.send(4, 42, "/pause@pomodoro_bot")
+	deadline := time.After(2 * time.Second)
+	for {
+		fake.mu.Lock()
+		n := len(fake.sent)
+		fake.mu.Unlock()
+		if n == 2 {
+			break
+		}
+		select {
+		case <-deadline:
+			t.Fatalf("got %d replies, want 2", n)
+		case <-time.After(time.Millisecond):
+		}
+	}
+	cancel()
+	<-done
+
+	if st := eng.State(); st.Idle() || !st.Paused {
+		t.Fatalf("state %+v, want only the allowed chat's pause applied", st)
+	}
+	if r := fake.sent[0]; r.Chat != 7 || !strings.Contains(r.Text, "Add 7 to chats") {
+		t.Errorf("reply to /start from a stranger: %+v", r)
+	}
+	if r := fake.sent[1]; r.Chat != 42 || !strings.HasPrefix(r.Text, "WORK, 2") || !strings.Contains(r.Text, "paused, 0 🍅 done") {
+		t.Errorf("reply to /pause: %+v", r)
+	}
+}
+
+func TestStatus(t *testing.T) {
+	for _, tc := range []struct {
+		st   core.State
+		want string
+	}{
+		{core.State{Phase: core.PhaseIdle, PomodoroDone: 3}, "Idle, 3 🍅 done"},
+		{core.State{Phase: core.PhaseWork, Task: core.Task{Title: "report"}}, "WORK, 12:05 left, on report, 0 🍅 done"},
+		{core.State{Phase: core.PhaseWork, Overtime: true, PomodoroDone: 1}, "WORK, overtime +1:30, 1 🍅 done"},
+	} {
+		if got := Status(tc.st, 12*time.Minute+5*time.Second, 90*time.Second); got != tc.want {
+			t.Errorf("Status(%+v) = %q, want %q", tc.st, got, tc.want)
+		}
+	}
+}
+
+func TestNotifier(t *testing.T) {
+	fake := &fakeTelegram{}
+	srv := httptest.NewServer(fake)
+	defer srv.Close()
+	client := NewClient("123:secret")
+	client.BaseURL = srv.URL
+
+	n := &Notifier{Client: client, Chats: []int64{42, 43}}
+	if err := notify.Send(n, notify.Message{Title: "GoPomodoro", Body: "Phase: SHORT_BREAK", Silent: true}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(fake.sent) != 2 || fake.sent[1].Chat != 43 || fake.sent[0].Text != "GoPomodoro\nPhase: SHORT_BREAK" || !fake.sent[0].Silent {
+		t.Fatalf("sent %+v", fake.sent)
+	}
+
+	client.Token = "123:wrong"
+	err := n.Notify("GoPomodoro", "hi")
+	if err == nil || !strings.Contains(err.Error(), "telegram sendMessage: 401 Unauthorized") || strings.Contains(err.Error(), "wrong") {
+		t.Fatalf("got %v, want Telegram's reason without the token", err)
+	}
+}
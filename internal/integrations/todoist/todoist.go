@@ -0,0 +1,175 @@
+// Package todoist lists Todoist tasks for the task picker and reports
+// finished pomodoros back as comments or by completing the task.
+package todoist
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Source is the core.Task source name for Todoist tasks.
+const Source = "todoist"
+
+// DefaultBaseURL is the Todoist API v1 endpoint.
+const DefaultBaseURL = "https://api.todoist.com/api/v1/"
+
+// Client is a minimal Todoist API client. It needs a personal API token
+// (Settings → Integrations → Developer).
+type Client struct {
+	Token   string
+	BaseURL string       // defaults to DefaultBaseURL
+	HTTP    *http.Client // defaults to http.DefaultClient
+	// Filter is a Todoist filter query, e.g. "today | overdue"; empty
+	// lists every active task.
+	Filter string
+
+	// MaxRetries bounds how often a rate-limited call is retried.
+	MaxRetries int
+}
+
+// NewClient creates a Client for token.
+func NewClient(token, filter string) *Client {
+	return &Client{Token: token, Filter: filter, MaxRetries: 3}
+}
+
+// APIError is a non-2xx response.
+type APIError struct {
+	Path   string
+	Status int
+	Body   string
+}
+
+func (e *APIError) Error() string {
+	if e.Body != "" {
+		return fmt.Sprintf("todoist %s: status %d: %s", e.Path, e.Status, e.Body)
+	}
+	return fmt.Sprintf("todoist %s: status %d", e.Path, e.Status)
+}
+
+// task is the subset of a Todoist task used here.
+type task struct {
+	ID       string `json:"id"`
+	Content  string `json:"content"`
+	Priority int    `json:"priority"` // 4 is the most urgent
+}
+
+// Tasks returns the active tasks matching the filter, most urgent
+// first.
+func (c *Client) Tasks(ctx context.Context) ([]core.Task, error) {
+	path := "tasks"
+	q := url.Values{"limit": {"200"}}
+	if c.Filter != "" {
+		path = "tasks/filter"
+		q.Set("query", c.Filter)
+	}
+	var all []task
+	for {
+		var page struct {
+			Results    []task  `json:"results"`
+			NextCursor *string `json:"next_cursor"`
+		}
+		if err := c.do(ctx, http.MethodGet, path+"?"+q.Encode(), nil, &page); err != nil {
+			return nil, err
+		}
+		all = append(all, page.Results...)
+		if page.NextCursor == nil || *page.NextCursor == "" {
+			break
+		}
+		q.Set("cursor", *page.NextCursor)
+	}
+	slices.SortStableFunc(all, func(a, b task) int { return cmp.Compare(b.Priority, a.Priority) })
+	out := make([]core.Task, len(all))
+	for i, t := range all {
+		out[i] = core.Task{Source: Source, ID: t.ID, Title: t.Content}
+	}
+	return out, nil
+}
+
+// Comment adds a comment to the task.
+func (c *Client) Comment(ctx context.Context, id, text string) error {
+	return c.do(ctx, http.MethodPost, "comments", map[string]string{"task_id": id, "content": text}, nil)
+}
+
+// Close completes the task.
+func (c *Client) Close(ctx context.Context, id string) error {
+	return c.do(ctx, http.MethodPost, "tasks/"+url.PathEscape(id)+"/close", nil, nil)
+}
+
+// do sends a JSON request and decodes the response into out, if set,
+// waiting out 429 responses as told by Retry-After.
+func (c *Client) do(ctx context.Context, method, path string, in, out any) error {
+	base := cmp.Or(c.BaseURL, DefaultBaseURL)
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	var body []byte
+	if in != nil {
+		var err error
+		if body, err = json.Marshal(in); err != nil {
+			return err
+		}
+	}
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequestWithContext(ctx, method, base+path, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		if in != nil {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		resp, err := client.Do(req)
+		if err != nil {
+			return fmt.Errorf("todoist %s: %w", path, err)
+		}
+		if resp.StatusCode == http.StatusTooManyRequests && attempt < c.MaxRetries {
+			resp.Body.Close()
+			if err := sleep(ctx, retryAfter(resp.Header)); err != nil {
+				return err
+			}
+			continue
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode/100 != 2 {
+			msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+			return &APIError{Path: path, Status: resp.StatusCode, Body: string(bytes.TrimSpace(msg))}
+		}
+		if out == nil {
+			return nil
+		}
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("todoist %s: %w", path, err)
+		}
+		return nil
+	}
+}
+
+func retryAfter(h http.Header) time.Duration {
+	if s, err := strconv.Atoi(h.Get("Retry-After")); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second
+	}
+	return time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
@@ -0,0 +1,101 @@
+package todoist
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type fakeTodoist struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (f *fakeTodoist) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer tok" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	f.calls = append(f.calls, r.Method+" "+r.URL.Path)
+	switch r.URL.Path {
+	case "/tasks/filter":
+		if r.URL.Query().Get("query") != "today" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Query().Get("cursor") == "" {
+			_, _ = w.Write([]byte(`{"results":[{"id":"1","content":"low","priority":1}],"next_cursor":"p2"}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"results":[{"id":"2","content":"urgent","priority":4}],"next_cursor":null}`))
+	case "/comments":
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.calls[len(f.calls)-1] += " " + body["task_id"] + " " + body["content"]
+		_, _ = w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestTasks_PagesAndSorts(t *testing.T) {
+	srv := httptest.NewServer(&fakeTodoist{})
+	defer srv.Close()
+	c := NewClient("tok", "today")
+	c.BaseURL = srv.URL + "/"
+
+	got, err := c.Tasks(context.Background())
+	if err != nil {
+		t.Fatalf("tasks: %v", err)
+	}
+	want := []core.Task{{Source: Source, ID: "2", Title: "urgent"}, {Source: Source, ID: "1", Title: "low"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+
+	c.Token = "bad"
+	if _, err := c.Tasks(context.Background()); err == nil {
+		t.Fatal("expected an error for a bad token")
+	}
+}
+
+func TestTracker_CommentsAndCompletes(t *testing.T) {
+	f := &fakeTodoist{}
+	srv := httptest.NewServer(f)
+	defer srv.Close()
+	c := NewClient("tok", "")
+	c.BaseURL = srv.URL + "/"
+
+	var completed []core.Task
+	tr := NewTracker(c, Options{
+		Comment:       true,
+		CompleteAfter: 2,
+		Done:          map[string]int{"7": 1},
+		OnComplete:    func(task core.Task) { completed = append(completed, task) },
+		OnError:       func(err error) { t.Fatal(err) },
+	})
+	task := core.Task{Source: Source, ID: "7", Title: "write"}
+	start := time.Unix(1, 0)
+	tr.Handle(core.Event{Kind: core.EventStart, State: core.State{Phase: core.PhaseWork, StartedAt: start, Task: task}})
+	tr.Handle(core.Event{Kind: core.EventAdvance, State: core.State{Phase: core.PhaseShortBreak, StartedAt: start, Task: task}})
+	// the last pomodoro of a -cycles run ends in a stop
+	tr.Handle(core.Event{Kind: core.EventStart, State: core.State{Phase: core.PhaseWork, StartedAt: start, Task: task}})
+	tr.Handle(core.Event{Kind: core.EventStop, Finished: true})
+
+	want := []string{"POST /comments 7 🍅 Pomodoro #2 done", "POST /tasks/7/close", "POST /comments 7 🍅 Pomodoro #3 done"}
+	if !reflect.DeepEqual(f.calls, want) {
+		t.Fatalf("calls = %q, want %q", f.calls, want)
+	}
+	if len(completed) != 1 || completed[0] != task {
+		t.Fatalf("OnComplete got %+v", completed)
+	}
+}
@@ -0,0 +1,77 @@
+package todoist
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Options configures a Tracker.
+type Options struct {
+	// Comment adds a "🍅" comment to the task per completed pomodoro.
+	Comment bool
+	// CompleteAfter completes the task once this many pomodoros were
+	// spent on it; 0 never does.
+	CompleteAfter int
+	// Done seeds the per-task pomodoro counts, e.g. from history, so
+	// CompleteAfter survives restarts.
+	Done map[string]int
+	// OnComplete is called after a task was completed, e.g. to detach it.
+	OnComplete func(core.Task)
+	Timeout    time.Duration // per event; default 10s
+	OnError    func(error)
+}
+
+// Tracker reports completed pomodoros on the attached Todoist task.
+// Subscribe its Handle method to an engine.
+type Tracker struct {
+	client *Client
+	opts   Options
+	done   map[string]int
+	prev   core.State
+}
+
+// NewTracker creates a Tracker using client.
+func NewTracker(client *Client, opts Options) *Tracker {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	done := make(map[string]int, len(opts.Done))
+	for id, n := range opts.Done {
+		done[id] = n
+	}
+	return &Tracker{client: client, opts: opts, done: done}
+}
+
+// Handle consumes one engine event. It makes blocking API calls, which is
+// fine on a subscriber's own goroutine.
+func (t *Tracker) Handle(ev core.Event) {
+	prev := t.prev
+	t.prev = ev.State
+	if !ev.CompletedWork(prev) || prev.Task.Source != Source {
+		return
+	}
+	id := prev.Task.ID
+	t.done[id]++
+	n := t.done[id]
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.opts.Timeout)
+	defer cancel()
+	if t.opts.Comment {
+		if err := t.client.Comment(ctx, id, fmt.Sprintf("🍅 Pomodoro #%d done", n)); err != nil {
+			t.opts.OnError(err)
+		}
+	}
+	if t.opts.CompleteAfter > 0 && n == t.opts.CompleteAfter {
+		if err := t.client.Close(ctx, id); err != nil {
+			t.opts.OnError(err)
+		} else if t.opts.OnComplete != nil {
+			t.opts.OnComplete(prev.Task)
+		}
+	}
+}
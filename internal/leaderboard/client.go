@@ -0,0 +1,74 @@
+package leaderboard
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Client talks to a leaderboard server as one member.
+type Client struct {
+	URL   string // the server, e.g. https://pomo.example.com
+	Token string
+	HTTP  *http.Client // defaults to http.DefaultClient
+}
+
+// Report sets the member's totals for day, a date like 2024-04-01.
+// Reporting totals rather than single pomodoros makes a repeated report
+// harmless.
+func (c *Client) Report(ctx context.Context, day string, t Totals) error {
+	data, err := json.Marshal(t)
+	if err != nil {
+		return err
+	}
+	return c.do(ctx, http.MethodPut, day, data, nil)
+}
+
+// Board fetches the team's board for day.
+func (c *Client) Board(ctx context.Context, day string) (Board, error) {
+	var b Board
+	err := c.do(ctx, http.MethodGet, day, nil, &b)
+	return b, err
+}
+
+// do sends a request about day, decoding the response into out if set.
+func (c *Client) do(ctx context.Context, method, day string, data []byte, out any) error {
+	client := c.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	endpoint := strings.TrimSuffix(c.URL, "/") + "/v1/days/" + url.PathEscape(day)
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("leaderboard: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+c.Token)
+	req.Header.Set("User-Agent", "GoPomodoro")
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("leaderboard: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		if why := strings.TrimSpace(string(body)); why != "" {
+			return fmt.Errorf("leaderboard: status %s: %s", resp.Status, why)
+		}
+		return fmt.Errorf("leaderboard: status %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("leaderboard: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+// Package leaderboard is a small server that team members' GoPomodoro
+// instances report their completed pomodoros to, and the client side
+// that reports them and reads back the team's daily board. Both ends
+// are opt-in, and every request carries a member's token, which also
+// tells the server who is reporting.
+package leaderboard
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// Totals are one member's work on one day.
+type Totals struct {
+	Pomodoros    int   `json:"pomodoros"`
+	FocusSeconds int64 `json:"focus_seconds"`
+}
+
+// NewTotals returns the totals for pomodoros and focus time.
+func NewTotals(pomodoros int, focus time.Duration) Totals {
+	return Totals{Pomodoros: pomodoros, FocusSeconds: int64(focus / time.Second)}
+}
+
+// Focus is the focus time.
+func (t Totals) Focus() time.Duration {
+	return time.Duration(t.FocusSeconds) * time.Second
+}
+
+// Entry is a member's line on the board.
+type Entry struct {
+	Name string `json:"name"`
+	Totals
+	Updated time.Time `json:"updated"`
+}
+
+// Board is the team's work on one day.
+type Board struct {
+	Day string `json:"day"` // YYYY-MM-DD, the members' local date
+	// Entries are the members who reported, most pomodoros first,
+	// then most focus time.
+	Entries []Entry `json:"entries"`
+	// You is the name of the member who asked.
+	You string `json:"you"`
+}
+
+// sortEntries orders entries for a Board.
+func sortEntries(entries []Entry) {
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Or(
+			cmp.Compare(b.Pomodoros, a.Pomodoros),
+			cmp.Compare(b.FocusSeconds, a.FocusSeconds),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+}
+
+// validDay reports whether day is a date like 2024-04-01.
+func validDay(day string) bool {
+	_, err := time.Parse(time.DateOnly, day)
+	return err == nil
+}
@@ -0,0 +1,224 @@
+package leaderboard
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+var members = map[string]string{"ana": "tok-ana", "bo": "tok-bo", "cy": "tok-cy"}
+
+func TestServer_Board(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "board.json")
+	s, err := NewServer(members, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.now = func() time.Time { return time.Date(2024, 4, 1, 12, 0, 0, 0, time.Local) }
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	ctx := context.Background()
+	client := func(token string) *Client { return &Client{URL: srv.URL + "/", Token: token} }
+
+	for token, totals := range map[string]Totals{
+		"tok-ana": NewTotals(3, 75*time.Minute),
+		"tok-bo":  NewTotals(5, 2*time.Hour),
+		"tok-cy":  NewTotals(3, 80*time.Minute),
+	} {
+		if err := client(token).Report(ctx, "2024-04-01", totals); err != nil {
+			t.Fatalf("report: %v", err)
+		}
+	}
+	// a report replaces the member's earlier one
+	if err := client("tok-ana").Report(ctx, "2024-04-01", NewTotals(4, 100*time.Minute)); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+	if err := client("tok-ana").Report(ctx, "2024-03-31", NewTotals(8, 0)); err != nil {
+		t.Fatalf("report: %v", err)
+	}
+
+	b, err := client("tok-cy").Board(ctx, "2024-04-01")
+	if err != nil {
+		t.Fatalf("board: %v", err)
+	}
+	var names []string
+	for _, e := range b.Entries {
+		names = append(names, e.Name)
+	}
+	if strings.Join(names, ",") != "bo,ana,cy" || b.You != "cy" || b.Day != "2024-04-01" {
+		t.Fatalf("board %+v, want bo, ana then cy, asked by cy", b)
+	}
+	if e := b.Entries[1]; e.Pomodoros != 4 || e.Focus() != 100*time.Minute || e.Updated.IsZero() {
+		t.Fatalf("ana's entry %+v", e)
+	}
+
+	// the board outlives the server
+	s2, err := NewServer(members, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv2 := httptest.NewServer(s2)
+	defer srv2.Close()
+	b, err = (&Client{URL: srv2.URL, Token: "tok-bo"}).Board(ctx, "2024-03-31")
+	if err != nil || len(b.Entries) != 1 || b.Entries[0].Pomodoros != 8 {
+		t.Fatalf("reloaded board %+v, %v", b, err)
+	}
+	if b, err := client("tok-bo").Board(ctx, "2024-04-02"); err != nil || b.Entries == nil || len(b.Entries) != 0 {
+		t.Fatalf("empty day %+v, %v", b, err)
+	}
+}
+
+func TestServer_Refuses(t *testing.T) {
+	s, _ := NewServer(members, "")
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	ctx := context.Background()
+
+	err := (&Client{URL: srv.URL, Token: "guess"}).Report(ctx, "2024-04-01", NewTotals(1, 0))
+	if err == nil || !strings.Contains(err.Error(), "401 Unauthorized: unknown token") {
+		t.Fatalf("bad token: %v", err)
+	}
+	good := &Client{URL: srv.URL, Token: "tok-ana"}
+	if _, err := good.Board(ctx, "yesterday"); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("bad day: %v", err)
+	}
+	if err := good.Report(ctx, "2024-04-01", Totals{Pomodoros: -1}); err == nil {
+		t.Fatal("negative totals accepted")
+	}
+
+	for _, bad := range []map[string]string{nil, {"ana": ""}, {"ana": "x", "bo": "x"}} {
+		if _, err := NewServer(bad, ""); err == nil {
+			t.Errorf("accepted members %v", bad)
+		}
+	}
+}
+
+func TestServer_Prunes(t *testing.T) {
+	s, _ := NewServer(members, "")
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.Local)
+	s.now = func() time.Time { return now }
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+	c := &Client{URL: srv.URL, Token: "tok-ana"}
+	ctx := context.Background()
+	if err := c.Report(ctx, "2024-01-01", NewTotals(1, 0)); err != nil {
+		t.Fatal(err)
+	}
+	// a day far off is refused, rather than pruning every real one
+	if err := c.Report(ctx, "9999-12-31", NewTotals(2, 0)); err == nil || !strings.Contains(err.Error(), "400") {
+		t.Fatalf("far-future day: %v", err)
+	}
+	if err := c.Report(ctx, "2023-12-25", NewTotals(2, 0)); err == nil {
+		t.Fatal("accepted a day a week old")
+	}
+	if b, _ := c.Board(ctx, "2024-01-01"); len(b.Entries) != 1 {
+		t.Fatalf("lost today to a far-off report: %+v", b)
+	}
+
+	now = time.Date(2024, 6, 1, 12, 0, 0, 0, time.Local)
+	_ = c.Report(ctx, "2024-06-01", NewTotals(2, 0))
+	if b, _ := c.Board(ctx, "2024-01-01"); len(b.Entries) != 0 {
+		t.Fatalf("kept a day months old: %+v", b)
+	}
+}
+
+func TestReporter(t *testing.T) {
+	s, _ := NewServer(members, "")
+	s.now = func() time.Time { return time.Date(2024, 4, 1, 15, 0, 0, 0, time.Local) }
+	var puts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPut {
+			puts.Add(1)
+		}
+		s.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+	bo := &Client{URL: srv.URL, Token: "tok-bo"}
+	if err := bo.Report(context.Background(), "2024-04-01", NewTotals(2, time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	puts.Store(0)
+
+	var done atomic.Int32
+	errs := make(chan error, 4)
+	r := NewReporter(&Client{URL: srv.URL, Token: "tok-ana"}, Options{
+		Totals: func(time.Time) (Totals, error) {
+			return NewTotals(int(done.Load()), time.Duration(done.Load())*25*time.Minute), nil
+		},
+		Watch:    true,
+		Interval: time.Hour,
+		Settle:   time.Millisecond,
+		OnError:  func(err error) { errs <- err },
+		Now:      func() time.Time { return time.Date(2024, 4, 1, 15, 0, 0, 0, time.Local) },
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go r.Run(ctx)
+
+	waitBoard := func(want string) Board {
+		t.Helper()
+		deadline := time.Now().Add(2 * time.Second)
+		for {
+			b, err := r.Board()
+			var got []string
+			for _, e := range b.Entries {
+				got = append(got, fmt.Sprintf("%s=%d", e.Name, e.Pomodoros))
+			}
+			if strings.Join(got, ",") == want {
+				return b
+			}
+			if time.Now().After(deadline) {
+				t.Fatalf("board %v (%v), want %s", got, err, want)
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+	}
+	if b := waitBoard("bo=2,ana=0"); b.You != "ana" {
+		t.Fatalf("board asked as %q", b.You)
+	}
+	done.Store(3)
+	r.Handle(core.Event{Kind: core.EventAdvance})
+	waitBoard("ana=3,bo=2")
+	// unchanged totals aren't sent again
+	r.Handle(core.Event{Kind: core.EventAdvance})
+	time.Sleep(50 * time.Millisecond)
+	if n := puts.Load(); n != 2 {
+		t.Fatalf("%d reports, want 2", n)
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	default:
+	}
+
+	// an unreachable server is reported once, keeping the last board
+	srv.Close()
+	done.Store(4)
+	r.Handle(core.Event{Kind: core.EventStop})
+	select {
+	case err := <-errs:
+		if !strings.HasPrefix(err.Error(), "leaderboard: ") {
+			t.Fatalf("error %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no error for the lost server")
+	}
+	r.Handle(core.Event{Kind: core.EventStart})
+	time.Sleep(50 * time.Millisecond)
+	if b, err := r.Board(); len(b.Entries) != 2 || err == nil {
+		t.Fatalf("board %+v, %v, want the last one and the error", b, err)
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("reported again: %v", err)
+	default:
+	}
+}
@@ -0,0 +1,141 @@
+package leaderboard
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Options configures a Reporter.
+type Options struct {
+	// Totals returns the member's totals for the local day of t, e.g.
+	// from the history.
+	Totals func(t time.Time) (Totals, error)
+	// Watch fetches the board too, for Board.
+	Watch bool
+	// Interval is how often the totals are checked and the board
+	// fetched; default 1m.
+	Interval time.Duration
+	// Settle is the wait after a phase ends before the totals are read,
+	// giving the history time to record it; default 2s.
+	Settle  time.Duration
+	OnError func(error)
+	Now     func() time.Time
+}
+
+// Reporter keeps the server's copy of the member's totals for today
+// current and, with Watch, the team's board at hand. Subscribe Handle
+// to the engine and Run it.
+type Reporter struct {
+	client *Client
+	opts   Options
+	nudge  chan struct{}
+
+	// the last totals the server accepted
+	day  string
+	sent Totals
+
+	mu    sync.Mutex
+	board Board
+	err   error
+}
+
+// NewReporter creates a Reporter reporting through client.
+func NewReporter(client *Client, opts Options) *Reporter {
+	if opts.Interval <= 0 {
+		opts.Interval = time.Minute
+	}
+	if opts.Settle <= 0 {
+		opts.Settle = 2 * time.Second
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &Reporter{client: client, opts: opts, nudge: make(chan struct{}, 1)}
+}
+
+// Handle reports soon after a phase ends, when a pomodoro may have
+// completed, rather than at the next interval.
+func (r *Reporter) Handle(ev core.Event) {
+	switch ev.Kind {
+	case core.EventAdvance, core.EventStop, core.EventStart, core.EventUndo:
+		select {
+		case r.nudge <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// Run reports, and fetches the board, until ctx is done. A lost server
+// is reported once, not on every retry.
+func (r *Reporter) Run(ctx context.Context) {
+	tick := time.NewTicker(r.opts.Interval)
+	defer tick.Stop()
+	down := false
+	for {
+		err := r.sync(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil && !down {
+			r.opts.OnError(err)
+		}
+		down = err != nil
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		case <-r.nudge:
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(r.opts.Settle):
+			}
+		}
+	}
+}
+
+// sync reports today's totals if they changed and fetches the board.
+func (r *Reporter) sync(ctx context.Context) error {
+	now := r.opts.Now()
+	day := now.Format(time.DateOnly)
+	t, err := r.opts.Totals(now)
+	if err != nil {
+		return err
+	}
+	if day != r.day || t != r.sent {
+		if err := r.client.Report(ctx, day, t); err != nil {
+			r.setBoard(Board{}, err)
+			return err
+		}
+		r.day, r.sent = day, t
+	}
+	if !r.opts.Watch {
+		return nil
+	}
+	b, err := r.client.Board(ctx, day)
+	r.setBoard(b, err)
+	return err
+}
+
+func (r *Reporter) setBoard(b Board, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err == nil {
+		r.board = b
+	}
+	r.err = err
+}
+
+// Board returns the last board fetched and the error of the last
+// attempt, if it failed; the board stays until a newer one arrives.
+func (r *Reporter) Board() (Board, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.board, r.err
+}
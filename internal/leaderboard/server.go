@@ -0,0 +1,215 @@
+package leaderboard
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// keepDays is how many days the server keeps, counting back from
+// today.
+const keepDays = 90
+
+// Server keeps the members' daily totals and serves the board:
+//
+//	PUT /v1/days/{day}  report the caller's totals for day
+//	GET /v1/days/{day}  the board for day
+//
+// Both need "Authorization: Bearer <token>" with a member's token.
+type Server struct {
+	tokens map[string]string // token → member name
+	path   string
+	now    func() time.Time
+	mux    *http.ServeMux
+
+	mu   sync.Mutex
+	days map[string]map[string]Entry // day → name → entry
+}
+
+// NewServer serves the members, a map of name to token, keeping the
+// board in the JSON file at path, if set, across restarts.
+func NewServer(members map[string]string, path string) (*Server, error) {
+	if len(members) == 0 {
+		return nil, errors.New("leaderboard: no members")
+	}
+	s := &Server{
+		tokens: make(map[string]string, len(members)),
+		path:   path,
+		now:    time.Now,
+		mux:    http.NewServeMux(),
+		days:   map[string]map[string]Entry{},
+	}
+	for _, name := range slices.Sorted(maps.Keys(members)) {
+		token := members[name]
+		if token == "" {
+			return nil, fmt.Errorf("leaderboard: %s has no token", name)
+		}
+		if other, ok := s.tokens[token]; ok {
+			return nil, fmt.Errorf("leaderboard: %s and %s share a token", other, name)
+		}
+		s.tokens[token] = name
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	s.mux.HandleFunc("PUT /v1/days/{day}", s.report)
+	s.mux.HandleFunc("GET /v1/days/{day}", s.board)
+	return s, nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+// member is the name the request's token belongs to, "" for none.
+func (s *Server) member(r *http.Request) string {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return ""
+	}
+	// compare with every token, so the time taken tells nothing
+	name := ""
+	for t, n := range s.tokens {
+		if subtle.ConstantTimeCompare([]byte(t), []byte(token)) == 1 {
+			name = n
+		}
+	}
+	return name
+}
+
+// auth returns the caller's name and day, or answers the request with
+// why not and returns ok false.
+func (s *Server) auth(w http.ResponseWriter, r *http.Request) (name, day string, ok bool) {
+	name = s.member(r)
+	if name == "" {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gopomodoro"`)
+		http.Error(w, "unknown token", http.StatusUnauthorized)
+		return "", "", false
+	}
+	day = r.PathValue("day")
+	if !validDay(day) {
+		http.Error(w, "day: want YYYY-MM-DD", http.StatusBadRequest)
+		return "", "", false
+	}
+	return name, day, true
+}
+
+func (s *Server) report(w http.ResponseWriter, r *http.Request) {
+	name, day, ok := s.auth(w, r)
+	if !ok {
+		return
+	}
+	var t Totals
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<10)).Decode(&t); err != nil {
+		http.Error(w, "bad totals: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if t.Pomodoros < 0 || t.FocusSeconds < 0 || t.FocusSeconds > 24*60*60 {
+		http.Error(w, "totals out of range", http.StatusBadRequest)
+		return
+	}
+
+	// a member's today may be the server's yesterday or tomorrow, but no
+	// further: a day far off would upset the pruning
+	today := dayStart(s.now())
+	d, _ := time.ParseInLocation(time.DateOnly, day, today.Location())
+	if d.Before(today.AddDate(0, 0, -1)) || d.After(today.AddDate(0, 0, 1)) {
+		http.Error(w, "day: too far from today", http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.days[day] == nil {
+		s.days[day] = map[string]Entry{}
+	}
+	s.days[day][name] = Entry{Name: name, Totals: t, Updated: s.now().UTC()}
+	s.pruneLocked()
+	if err := s.saveLocked(); err != nil {
+		http.Error(w, "saving: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) board(w http.ResponseWriter, r *http.Request) {
+	name, day, ok := s.auth(w, r)
+	if !ok {
+		return
+	}
+	s.mu.Lock()
+	b := Board{Day: day, Entries: slices.Collect(maps.Values(s.days[day])), You: name}
+	s.mu.Unlock()
+	if b.Entries == nil {
+		b.Entries = []Entry{}
+	}
+	sortEntries(b.Entries)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(b)
+}
+
+// pruneLocked forgets the days more than keepDays before today.
+func (s *Server) pruneLocked() {
+	oldest := s.now().AddDate(0, 0, -keepDays+1).Format(time.DateOnly)
+	for day := range s.days {
+		if day < oldest {
+			delete(s.days, day)
+		}
+	}
+}
+
+// load reads the board saved at path; a missing file is an empty one.
+func (s *Server) load() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("leaderboard: %w", err)
+	}
+	if err := json.Unmarshal(data, &s.days); err != nil {
+		return fmt.Errorf("leaderboard %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// saveLocked writes the board to path, replacing the file whole so a
+// crash never leaves half of it.
+func (s *Server) saveLocked() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(s.days)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// dayStart returns the midnight starting t's day.
+func dayStart(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+var _ http.Handler = (*Server)(nil)
@@ -0,0 +1,119 @@
+// Package logging is the structured log of the TUI, daemon and tray:
+// engine events, notifications and outgoing HTTP calls, for finding
+// out after the fact why something did or didn't happen.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// ParseLevel parses "debug", "info", "warn" or "error".
+func ParseLevel(s string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("log level %q: want debug, info, warn or error", s)
+	}
+	return l, nil
+}
+
+// Open returns a logger at level appending JSON lines to path, or
+// writing text to stderr when path is empty, and a function closing it.
+func Open(path string, level slog.Level) (*slog.Logger, io.Closer, error) {
+	opts := &slog.HandlerOptions{Level: level}
+	if path == "" {
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), io.NopCloser(nil), nil
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, nil, err
+	}
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+	return slog.New(slog.NewJSONHandler(f, opts)), f, nil
+}
+
+// Events returns an engine subscriber logging its events: phase
+// changes at info, refusals at warn and the rest at debug.
+func Events(l *slog.Logger) func(core.Event) {
+	return func(ev core.Event) {
+		level := slog.LevelDebug
+		switch ev.Kind {
+		case core.EventStart, core.EventAdvance, core.EventSkip, core.EventStop,
+			core.EventOvertime, core.EventAbandon, core.EventConfigReloaded, core.EventUndo:
+			level = slog.LevelInfo
+		case core.EventRefused:
+			level = slog.LevelWarn
+		}
+		attrs := []slog.Attr{
+			slog.String("phase", ev.State.Name()),
+			slog.Int("pomodoros", ev.State.PomodoroDone),
+		}
+		if ev.Timer != "" {
+			attrs = append(attrs, slog.String("timer", ev.Timer))
+		}
+		if !ev.State.Idle() {
+			attrs = append(attrs, slog.Duration("remaining", ev.Remaining.Round(time.Second)))
+		}
+		if ev.State.Paused {
+			attrs = append(attrs, slog.String("pause_reason", ev.State.PauseReason.String()))
+		}
+		if ev.Refusal != nil {
+			attrs = append(attrs, slog.String("error", ev.Refusal.Error()))
+		}
+		if ev.QuittingTime {
+			attrs = append(attrs, slog.Bool("quitting_time", true))
+		}
+		if ev.Finished {
+			attrs = append(attrs, slog.Bool("finished", true))
+		}
+		if ev.Undone != "" {
+			attrs = append(attrs, slog.String("undone", ev.Undone))
+		}
+		l.LogAttrs(context.Background(), level, "engine "+ev.Kind.String(), attrs...)
+	}
+}
+
+// Transport wraps next, or http.DefaultTransport when nil, logging
+// every request at debug and failures at warn. Only the method, host
+// and status are logged: some APIs carry tokens in paths and queries.
+func Transport(next http.RoundTripper, l *slog.Logger) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return transport{next: next, l: l}
+}
+
+type transport struct {
+	next http.RoundTripper
+	l    *slog.Logger
+}
+
+func (t transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.next.RoundTrip(req)
+	attrs := []slog.Attr{
+		slog.String("method", req.Method),
+		slog.String("host", req.URL.Host),
+		slog.Duration("took", time.Since(start).Round(time.Millisecond)),
+	}
+	ctx := req.Context()
+	switch {
+	case err != nil:
+		t.l.LogAttrs(ctx, slog.LevelWarn, "http request failed", append(attrs, slog.String("error", err.Error()))...)
+	case resp.StatusCode >= 400:
+		t.l.LogAttrs(ctx, slog.LevelWarn, "http request", append(attrs, slog.Int("status", resp.StatusCode))...)
+	default:
+		t.l.LogAttrs(ctx, slog.LevelDebug, "http request", append(attrs, slog.Int("status", resp.StatusCode))...)
+	}
+	return resp, err
+}
@@ -0,0 +1,83 @@
+package logging
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func newLogger(buf *strings.Builder, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewTextHandler(buf, &slog.HandlerOptions{
+		Level: level,
+		// drop the time so lines compare
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestEvents(t *testing.T) {
+	var buf strings.Builder
+	log := Events(newLogger(&buf, slog.LevelInfo))
+	now := time.Now()
+	log(core.Event{Kind: core.EventAdvance, At: now, Remaining: 5 * time.Minute,
+		State: core.State{Phase: core.PhaseShortBreak, PomodoroDone: 1, StartedAt: now}})
+	log(core.Event{Kind: core.EventWarning, At: now}) // debug, filtered
+	log(core.Event{Kind: core.EventRefused, At: now, Refusal: errors.New("strict mode")})
+
+	want := `level=INFO msg="engine advance" phase=SHORT_BREAK pomodoros=1 remaining=5m0s
+level=WARN msg="engine refused" phase=IDLE pomodoros=0 error="strict mode"
+`
+	if got := buf.String(); got != want {
+		t.Fatalf("log:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	var buf strings.Builder
+	c := &http.Client{Transport: Transport(nil, newLogger(&buf, slog.LevelDebug))}
+	resp, err := c.Get(srv.URL + "/bot-secret/send?token=secret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	out := buf.String()
+	if !strings.Contains(out, "level=WARN") || !strings.Contains(out, "status=401") || strings.Contains(out, "secret") {
+		t.Fatalf("log: %s", out)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	if _, err := ParseLevel("verbose"); err == nil {
+		t.Error("level verbose accepted")
+	}
+	path := filepath.Join(t.TempDir(), "logs", "gopomodoro.log")
+	l, closer, err := Open(path, slog.LevelInfo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l.Debug("hidden")
+	l.Info("shown", "n", 1)
+	closer.Close()
+	b, _ := os.ReadFile(path)
+	if got := string(b); strings.Contains(got, "hidden") || !strings.Contains(got, `"msg":"shown","n":1`) {
+		t.Fatalf("file: %s", got)
+	}
+}
@@ -0,0 +1,364 @@
+// Package meetings reads a calendar (an iCalendar feed or a CalDAV
+// collection) and keeps pomodoros from running into meetings: it can
+// warn, refuse the start, or shorten the pomodoro to end before the
+// meeting.
+package meetings
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Meeting is one occurrence of a calendar event.
+type Meeting struct {
+	Summary    string
+	Start, End time.Time
+}
+
+// Calendar holds the timed events of one or more iCalendar documents.
+// All-day, cancelled and free (TRANSP:TRANSPARENT) events are left out,
+// since they don't keep anyone from working.
+type Calendar struct {
+	events []event
+}
+
+type event struct {
+	uid, summary string
+	start, end   time.Time
+	rule         *rrule
+	exdates      []time.Time
+	recurrenceID time.Time // set on an event overriding one occurrence
+}
+
+// rrule is the subset of recurrence rules meetings use: FREQ, INTERVAL,
+// COUNT, UNTIL and, for weekly rules, BYDAY.
+type rrule struct {
+	freq     string
+	interval int
+	count    int
+	until    time.Time
+	byDay    []time.Weekday
+}
+
+// Parse reads the VEVENTs of r. Everything else is ignored, so several
+// documents may simply be concatenated.
+func Parse(r io.Reader) (*Calendar, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+	cal := &Calendar{}
+	var cur *event
+	skip := false
+	var dur time.Duration
+	for _, line := range lines {
+		name, params, value := splitProp(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			cur, skip, dur = &event{}, false, 0
+			continue
+		case name == "END" && value == "VEVENT":
+			if cur != nil && !skip && !cur.start.IsZero() {
+				if cur.end.IsZero() {
+					cur.end = cur.start.Add(dur)
+				}
+				if cur.end.After(cur.start) {
+					cal.events = append(cal.events, *cur)
+				}
+			}
+			cur = nil
+			continue
+		case cur == nil:
+			continue
+		}
+		switch name {
+		case "UID":
+			cur.uid = value
+		case "SUMMARY":
+			cur.summary = unescape(value)
+		case "DTSTART", "DTEND", "RECURRENCE-ID":
+			if params["VALUE"] == "DATE" || len(value) == 8 {
+				if name == "DTSTART" {
+					skip = true // all-day
+				}
+				continue
+			}
+			t, err := parseTime(value, params["TZID"])
+			if err != nil {
+				return nil, fmt.Errorf("ics %s: %w", name, err)
+			}
+			switch name {
+			case "DTSTART":
+				cur.start = t
+			case "DTEND":
+				cur.end = t
+			default:
+				cur.recurrenceID = t
+			}
+		case "DURATION":
+			if dur, err = parseDuration(value); err != nil {
+				return nil, fmt.Errorf("ics DURATION: %w", err)
+			}
+		case "STATUS":
+			skip = skip || value == "CANCELLED"
+		case "TRANSP":
+			skip = skip || value == "TRANSPARENT"
+		case "RRULE":
+			if cur.rule, err = parseRule(value, params); err != nil {
+				return nil, err
+			}
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				if t, err := parseTime(v, params["TZID"]); err == nil {
+					cur.exdates = append(cur.exdates, t)
+				}
+			}
+		}
+	}
+	return cal, nil
+}
+
+// Meetings returns the occurrences overlapping [from, to), by start.
+func (c *Calendar) Meetings(from, to time.Time) []Meeting {
+	// occurrences moved or edited individually replace the rule's ones
+	overridden := map[string]bool{}
+	for _, e := range c.events {
+		if !e.recurrenceID.IsZero() {
+			overridden[e.uid+"@"+strconv.FormatInt(e.recurrenceID.Unix(), 10)] = true
+		}
+	}
+	var out []Meeting
+	for _, e := range c.events {
+		length := e.end.Sub(e.start)
+		e.occurrences(from.Add(-length), to, func(start time.Time) {
+			if !e.recurrenceID.IsZero() || !overridden[e.uid+"@"+strconv.FormatInt(start.Unix(), 10)] {
+				if start.Add(length).After(from) && start.Before(to) {
+					out = append(out, Meeting{Summary: e.summary, Start: start, End: start.Add(length)})
+				}
+			}
+		})
+	}
+	slices.SortFunc(out, func(a, b Meeting) int { return a.Start.Compare(b.Start) })
+	return out
+}
+
+// maxOccurrences bounds the expansion of rules without an end.
+const maxOccurrences = 100000
+
+// occurrences calls fn with every start before to, skipping EXDATEs.
+// Starts before from may be reported too.
+func (e event) occurrences(from, to time.Time, fn func(time.Time)) {
+	emit := func(t time.Time) {
+		for _, x := range e.exdates {
+			if x.Equal(t) {
+				return
+			}
+		}
+		fn(t)
+	}
+	r := e.rule
+	if r == nil {
+		emit(e.start)
+		return
+	}
+	n := 0
+	done := func(t time.Time) bool {
+		return !t.Before(to) || (r.count > 0 && n >= r.count) || (!r.until.IsZero() && t.After(r.until)) || n >= maxOccurrences
+	}
+	if r.freq == "WEEKLY" && len(r.byDay) > 0 {
+		// weeks start on Monday
+		monday := e.start.AddDate(0, 0, -(int(e.start.Weekday())+6)%7)
+		for week := 0; ; week += r.interval {
+			for _, d := range r.byDay {
+				t := monday.AddDate(0, 0, week*7+(int(d)+6)%7)
+				if t.Before(e.start) {
+					continue
+				}
+				if done(t) {
+					return
+				}
+				n++
+				emit(t)
+			}
+		}
+	}
+	for i := 0; ; i += r.interval {
+		var t time.Time
+		switch r.freq {
+		case "DAILY":
+			t = e.start.AddDate(0, 0, i)
+		case "WEEKLY":
+			t = e.start.AddDate(0, 0, 7*i)
+		case "MONTHLY":
+			t = e.start.AddDate(0, i, 0)
+		case "YEARLY":
+			t = e.start.AddDate(i, 0, 0)
+		}
+		if done(t) {
+			return
+		}
+		n++
+		emit(t)
+	}
+}
+
+func parseRule(value string, params map[string]string) (*rrule, error) {
+	r := &rrule{interval: 1}
+	for _, part := range strings.Split(value, ";") {
+		k, v, _ := strings.Cut(part, "=")
+		var err error
+		switch k {
+		case "FREQ":
+			r.freq = v
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(v)
+		case "COUNT":
+			r.count, err = strconv.Atoi(v)
+		case "UNTIL":
+			if len(v) == 8 {
+				v += "T235959"
+			}
+			r.until, err = parseTime(v, params["TZID"])
+		case "BYDAY":
+			for _, d := range strings.Split(v, ",") {
+				wd, ok := weekdays[d]
+				if !ok {
+					return nil, nil // e.g. "1MO": not supported, keep the first occurrence only
+				}
+				r.byDay = append(r.byDay, wd)
+			}
+		case "BYMONTHDAY", "BYMONTH", "BYSETPOS", "BYYEARDAY", "BYWEEKNO", "BYHOUR", "BYMINUTE":
+			return nil, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ics RRULE %s: %w", k, err)
+		}
+	}
+	switch r.freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	default:
+		return nil, nil
+	}
+	if r.interval < 1 {
+		r.interval = 1
+	}
+	if r.freq != "WEEKLY" {
+		r.byDay = nil
+	}
+	// in week order, Monday first, so occurrences come out sorted
+	slices.SortFunc(r.byDay, func(a, b time.Weekday) int { return (int(a)+6)%7 - (int(b)+6)%7 })
+	return r, nil
+}
+
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// parseTime reads a DATE-TIME: UTC with a trailing Z, in tzid, or
+// floating (local time). Unknown time zones, such as Windows names,
+// are taken as local time.
+func parseTime(v, tzid string) (time.Time, error) {
+	if t, ok := strings.CutSuffix(v, "Z"); ok {
+		return time.Parse("20060102T150405", t)
+	}
+	loc := time.Local
+	if tzid != "" {
+		if l, err := time.LoadLocation(strings.Trim(tzid, `"`)); err == nil {
+			loc = l
+		}
+	}
+	return time.ParseInLocation("20060102T150405", v, loc)
+}
+
+// parseDuration reads an iCalendar duration such as PT1H30M or P1D.
+func parseDuration(v string) (time.Duration, error) {
+	s := strings.TrimPrefix(strings.TrimPrefix(v, "+"), "P")
+	if strings.HasPrefix(v, "-") {
+		return 0, fmt.Errorf("negative duration %q", v)
+	}
+	var d time.Duration
+	inTime := false
+	num := ""
+	for _, c := range s {
+		switch {
+		case c >= '0' && c <= '9':
+			num += string(c)
+			continue
+		case c == 'T':
+			inTime = true
+			continue
+		}
+		n, err := strconv.Atoi(num)
+		if err != nil {
+			return 0, fmt.Errorf("bad duration %q", v)
+		}
+		num = ""
+		switch {
+		case c == 'W':
+			d += time.Duration(n) * 7 * 24 * time.Hour
+		case c == 'D':
+			d += time.Duration(n) * 24 * time.Hour
+		case c == 'H' && inTime:
+			d += time.Duration(n) * time.Hour
+		case c == 'M' && inTime:
+			d += time.Duration(n) * time.Minute
+		case c == 'S' && inTime:
+			d += time.Duration(n) * time.Second
+		default:
+			return 0, fmt.Errorf("bad duration %q", v)
+		}
+	}
+	if num != "" {
+		return 0, fmt.Errorf("bad duration %q", v)
+	}
+	return d, nil
+}
+
+// unfold joins continuation lines (starting with a space or tab).
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1<<20)
+	for sc.Scan() {
+		line := strings.TrimRight(sc.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, sc.Err()
+}
+
+// splitProp splits "NAME;K=V;K2=V2:value", allowing ":" inside quoted
+// parameter values.
+func splitProp(line string) (name string, params map[string]string, value string) {
+	quoted := false
+	i := strings.IndexFunc(line, func(r rune) bool {
+		if r == '"' {
+			quoted = !quoted
+		}
+		return r == ':' && !quoted
+	})
+	if i < 0 {
+		return strings.ToUpper(line), nil, ""
+	}
+	head, value := line[:i], line[i+1:]
+	parts := strings.Split(head, ";")
+	params = map[string]string{}
+	for _, p := range parts[1:] {
+		k, v, _ := strings.Cut(p, "=")
+		params[strings.ToUpper(k)] = v
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+var unescaper = strings.NewReplacer(`\\`, `\`, `\;`, `;`, `\,`, `,`, `\n`, "\n", `\N`, "\n")
+
+func unescape(s string) string { return unescaper.Replace(s) }
@@ -0,0 +1,155 @@
+package meetings
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+const feed = "BEGIN:VCALENDAR\r\n" +
+	"BEGIN:VEVENT\r\nUID:standup\r\nSUMMARY:Stand\r\n  up\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20260302T093000\r\nDTEND;TZID=Europe/Berlin:20260302T094500\r\n" +
+	"RRULE:FREQ=WEEKLY;BYDAY=MO,WE,FR;COUNT=6\r\nEXDATE;TZID=Europe/Berlin:20260304T093000\r\nEND:VEVENT\r\n" +
+	// the Friday standup moved to 10:00
+	"BEGIN:VEVENT\r\nUID:standup\r\nSUMMARY:Stand up\r\nRECURRENCE-ID;TZID=Europe/Berlin:20260306T093000\r\n" +
+	"DTSTART;TZID=Europe/Berlin:20260306T100000\r\nDURATION:PT15M\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:review\r\nSUMMARY:Review\\, Q1\r\nDTSTART:20260302T130000Z\r\nDTEND:20260302T140000Z\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:holiday\r\nSUMMARY:Holiday\r\nDTSTART;VALUE=DATE:20260303\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:gone\r\nSTATUS:CANCELLED\r\nDTSTART:20260302T150000Z\r\nDTEND:20260302T160000Z\r\nEND:VEVENT\r\n" +
+	"BEGIN:VEVENT\r\nUID:free\r\nTRANSP:TRANSPARENT\r\nDTSTART:20260302T160000Z\r\nDTEND:20260302T170000Z\r\nEND:VEVENT\r\n" +
+	"END:VCALENDAR\r\n"
+
+func TestParse_Meetings(t *testing.T) {
+	cal, err := Parse(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	got := cal.Meetings(from, from.AddDate(0, 0, 14))
+	var lines []string
+	for _, m := range got {
+		lines = append(lines, m.Start.UTC().Format("Mon 02 15:04")+"-"+m.End.UTC().Format("15:04")+" "+m.Summary)
+	}
+	want := []string{
+		"Mon 02 08:30-08:45 Stand up",
+		"Mon 02 13:00-14:00 Review, Q1",
+		"Fri 06 09:00-09:15 Stand up",
+		"Mon 09 08:30-08:45 Stand up",
+		"Wed 11 08:30-08:45 Stand up",
+		"Fri 13 08:30-08:45 Stand up",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("meetings:\n%s\nwant:\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+
+	// only what overlaps the window
+	got = cal.Meetings(time.Date(2026, 3, 2, 8, 40, 0, 0, time.UTC), time.Date(2026, 3, 2, 13, 0, 0, 0, time.UTC))
+	if len(got) != 1 || got[0].Summary != "Stand up" {
+		t.Fatalf("window meetings %+v", got)
+	}
+}
+
+func TestPlanner_Policies(t *testing.T) {
+	cal, err := Parse(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 3, 2, 12, 30, 0, 0, time.UTC) // review at 13:00
+	work := core.State{Phase: core.PhaseWork, StartedAt: start, EndsAt: start.Add(40 * time.Minute), Length: 40 * time.Minute}
+	early := work
+	early.StartedAt, early.EndsAt = start.Add(-time.Hour), start.Add(-35*time.Minute)
+
+	for _, tc := range []struct {
+		policy  Policy
+		state   core.State
+		length  time.Duration
+		refused bool
+	}{
+		{Warn, work, 40 * time.Minute, false},
+		{Refuse, work, 28 * time.Minute, true},
+		{Shorten, work, 28 * time.Minute, false},
+		{Shorten, early, 40 * time.Minute, false},
+	} {
+		p := NewPlanner(Options{Policy: tc.policy})
+		p.Set(cal)
+		d, err := p.Check(tc.state)
+		if d != tc.length || (err != nil) != tc.refused {
+			t.Errorf("%v: length %v, err %v; want %v, refused %v", tc.policy, d, err, tc.length, tc.refused)
+		}
+	}
+
+	// too little time left to shorten
+	p := NewPlanner(Options{Policy: Shorten, MinLength: 20 * time.Minute})
+	p.Set(cal)
+	late := work
+	late.StartedAt = start.Add(15 * time.Minute)
+	var ce *ConflictError
+	if _, err := p.Check(late); !errors.As(err, &ce) || ce.Meeting.Summary != "Review, Q1" {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestPlanner_HandleNotifies(t *testing.T) {
+	cal, err := Parse(strings.NewReader(feed))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notes []string
+	p := NewPlanner(Options{Policy: Shorten, Notify: func(s string) { notes = append(notes, s) }})
+	p.Set(cal)
+
+	start := time.Date(2026, 3, 2, 12, 30, 0, 0, time.UTC)
+	work := core.State{Phase: core.PhaseWork, StartedAt: start, EndsAt: start.Add(40 * time.Minute), Length: 40 * time.Minute}
+	d, _ := p.Check(work)
+	work.EndsAt, work.Length = start.Add(d), d
+	p.Handle(core.Event{Kind: core.EventStart, State: work})
+	if len(notes) != 1 || !strings.HasPrefix(notes[0], `Shortened to 28m to end before "Review, Q1"`) {
+		t.Fatalf("notes %q", notes)
+	}
+
+	// a break is never flagged
+	p.Handle(core.Event{Kind: core.EventAdvance, State: core.State{Phase: core.PhaseShortBreak, StartedAt: start, EndsAt: start.Add(time.Hour)}})
+	if len(notes) != 1 {
+		t.Fatalf("notes %q", notes)
+	}
+}
+
+func TestSource_CalDAV(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if u, pw, _ := r.BasicAuth(); r.Method != "REPORT" || r.Header.Get("Depth") != "1" || u != "me" || pw != "pw" ||
+			!strings.Contains(string(body), `<c:time-range start="20260302T000000Z" end="20260303T000000Z"/>`) {
+			t.Errorf("request %s %q", r.Method, body)
+		}
+		w.WriteHeader(http.StatusMultiStatus)
+		_, _ = io.WriteString(w, `<?xml version="1.0"?>
+<d:multistatus xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+ <d:response><d:href>/cal/1.ics</d:href><d:propstat><d:prop><c:calendar-data>BEGIN:VCALENDAR
+BEGIN:VEVENT
+UID:1
+SUMMARY:Planning
+DTSTART:20260302T100000Z
+DTEND:20260302T110000Z
+END:VEVENT
+END:VCALENDAR
+</c:calendar-data></d:prop><d:status>HTTP/1.1 200 OK</d:status></d:propstat></d:response>
+</d:multistatus>`)
+	}))
+	defer srv.Close()
+
+	from := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	cal, err := Source{URL: srv.URL, CalDAV: true, Username: "me", Password: "pw"}.Fetch(context.Background(), from, from.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ms := cal.Meetings(from, from.AddDate(0, 0, 1)); len(ms) != 1 || ms[0].Summary != "Planning" {
+		t.Fatalf("meetings %+v", ms)
+	}
+}
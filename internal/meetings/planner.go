@@ -0,0 +1,204 @@
+package meetings
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Policy is what to do about a pomodoro that would run into a meeting.
+type Policy int
+
+const (
+	// Warn starts the pomodoro anyway, with a heads-up.
+	Warn Policy = iota
+	// Refuse doesn't start it.
+	Refuse
+	// Shorten ends it just before the meeting, or refuses it if that
+	// leaves less than MinLength.
+	Shorten
+)
+
+func (p Policy) String() string {
+	switch p {
+	case Refuse:
+		return "refuse"
+	case Shorten:
+		return "shorten"
+	default:
+		return "warn"
+	}
+}
+
+// ParsePolicy reads a policy name; empty means Warn.
+func ParsePolicy(s string) (Policy, error) {
+	switch strings.ToLower(s) {
+	case "", "warn":
+		return Warn, nil
+	case "refuse":
+		return Refuse, nil
+	case "shorten":
+		return Shorten, nil
+	}
+	return 0, fmt.Errorf("meetings: unknown policy %q (want warn, refuse or shorten)", s)
+}
+
+// ConflictError refuses a pomodoro because of Meeting.
+type ConflictError struct {
+	Meeting Meeting
+	Now     time.Time
+}
+
+func (e *ConflictError) Error() string {
+	m := e.Meeting
+	if !m.Start.After(e.Now) {
+		return fmt.Sprintf("Pomodoro not started: %s runs until %s", quote(m.Summary), m.End.Local().Format("15:04"))
+	}
+	return fmt.Sprintf("Pomodoro not started: %s starts at %s", quote(m.Summary), m.Start.Local().Format("15:04"))
+}
+
+func quote(summary string) string {
+	if summary == "" {
+		return "a meeting"
+	}
+	return fmt.Sprintf("%q", summary)
+}
+
+// Options configures a Planner.
+type Options struct {
+	Policy Policy
+	// Margin is the time kept free before a meeting; default 2m.
+	Margin time.Duration
+	// MinLength is the shortest pomodoro Shorten leaves; default 10m.
+	MinLength time.Duration
+	// Notify shows a heads-up: an overlap under Warn (or one that
+	// couldn't be avoided) and a shortened pomodoro.
+	Notify func(body string)
+}
+
+// Planner knows the upcoming meetings. Its Check method is an engine
+// StartCheck applying the policy, and its Handle method, subscribed to
+// the engine, sends the heads-ups.
+type Planner struct {
+	opts Options
+
+	mu        sync.Mutex
+	cal       *Calendar
+	shortened map[time.Time]Meeting // by StartedAt of the shortened phase
+}
+
+// NewPlanner creates a Planner without meetings; see Set and Run.
+func NewPlanner(opts Options) *Planner {
+	if opts.Margin <= 0 {
+		opts.Margin = 2 * time.Minute
+	}
+	if opts.MinLength <= 0 {
+		opts.MinLength = 10 * time.Minute
+	}
+	if opts.Notify == nil {
+		opts.Notify = func(string) {}
+	}
+	return &Planner{opts: opts, shortened: map[time.Time]Meeting{}}
+}
+
+// Set replaces the calendar.
+func (p *Planner) Set(cal *Calendar) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.cal = cal
+}
+
+// Next is the first meeting overlapping [start, end+Margin).
+func (p *Planner) Next(start, end time.Time) (Meeting, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.cal == nil {
+		return Meeting{}, false
+	}
+	ms := p.cal.Meetings(start, end.Add(p.opts.Margin))
+	if len(ms) == 0 {
+		return Meeting{}, false
+	}
+	return ms[0], true
+}
+
+// Check is a core.StartCheck.
+func (p *Planner) Check(next core.State) (time.Duration, error) {
+	m, ok := p.Next(next.StartedAt, next.EndsAt)
+	if !ok || p.opts.Policy == Warn {
+		return next.Length, nil
+	}
+	refused := &ConflictError{Meeting: m, Now: next.StartedAt}
+	free := m.Start.Add(-p.opts.Margin).Sub(next.StartedAt)
+	if free < p.opts.MinLength {
+		return next.Length, refused
+	}
+	// Refuse only holds for Start; a phase that starts anyway is
+	// shortened under either policy
+	p.mu.Lock()
+	p.shortened[next.StartedAt] = m
+	p.mu.Unlock()
+	if p.opts.Policy == Refuse {
+		return free, refused
+	}
+	return free, nil
+}
+
+// Handle consumes one engine event.
+func (p *Planner) Handle(ev core.Event) {
+	st := ev.State
+	switch ev.Kind {
+	case core.EventStart, core.EventAdvance, core.EventSkip:
+	default:
+		return
+	}
+	p.mu.Lock()
+	m, short := p.shortened[st.StartedAt]
+	clear(p.shortened)
+	p.mu.Unlock()
+	if st.Phase != core.PhaseWork {
+		return
+	}
+	if short {
+		p.opts.Notify(fmt.Sprintf("Shortened to %dm to end before %s at %s",
+			int(st.Length.Round(time.Minute)/time.Minute), quote(m.Summary), m.Start.Local().Format("15:04")))
+		return
+	}
+	if m, ok := p.Next(st.StartedAt, st.EndsAt); ok {
+		if !m.Start.After(st.StartedAt) {
+			p.opts.Notify(fmt.Sprintf("Heads-up: you're in %s until %s", quote(m.Summary), m.End.Local().Format("15:04")))
+			return
+		}
+		p.opts.Notify(fmt.Sprintf("Heads-up: %s starts at %s, before this pomodoro ends", quote(m.Summary), m.Start.Local().Format("15:04")))
+	}
+}
+
+// Run fetches the next day of meetings from src every interval (default
+// 10m) until ctx is done. Fetch errors keep the previous calendar.
+func (p *Planner) Run(ctx context.Context, src Source, every time.Duration, onErr func(error)) {
+	if every <= 0 {
+		every = 10 * time.Minute
+	}
+	for {
+		now := time.Now()
+		fetch, cancel := context.WithTimeout(ctx, 30*time.Second)
+		cal, err := src.Fetch(fetch, now.Add(-12*time.Hour), now.Add(36*time.Hour))
+		cancel()
+		if err != nil {
+			if onErr != nil && ctx.Err() == nil {
+				onErr(err)
+			}
+		} else {
+			p.Set(cal)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(every):
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package meetings
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Source is where the calendar comes from.
+type Source struct {
+	// URL is an iCalendar feed (http, https or webcal) or file, or, with
+	// CalDAV set, a CalDAV calendar collection.
+	URL                string
+	CalDAV             bool
+	Username, Password string       // HTTP basic auth, if set
+	HTTP               *http.Client // defaults to http.DefaultClient
+}
+
+// Fetch reads the calendar. A CalDAV server is asked for the events
+// overlapping [from, to) only; a feed is read whole.
+func (s Source) Fetch(ctx context.Context, from, to time.Time) (*Calendar, error) {
+	u := s.URL
+	if rest, ok := strings.CutPrefix(u, "webcal://"); ok {
+		u = "https://" + rest
+	}
+	if !strings.HasPrefix(u, "http://") && !strings.HasPrefix(u, "https://") {
+		f, err := os.Open(u)
+		if err != nil {
+			return nil, err
+		}
+		defer f.Close()
+		return Parse(f)
+	}
+	var req *http.Request
+	var err error
+	if s.CalDAV {
+		req, err = http.NewRequestWithContext(ctx, "REPORT", u, strings.NewReader(calendarQuery(from, to)))
+		if err == nil {
+			req.Header.Set("Depth", "1")
+			req.Header.Set("Content-Type", "application/xml; charset=utf-8")
+		}
+	} else {
+		req, err = http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if s.Username != "" || s.Password != "" {
+		req.SetBasicAuth(s.Username, s.Password)
+	}
+	client := s.HTTP
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("calendar: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return nil, fmt.Errorf("calendar: %s", resp.Status)
+	}
+	body := io.LimitReader(resp.Body, 32<<20)
+	if !s.CalDAV {
+		return Parse(body)
+	}
+	var ms multistatus
+	if err := xml.NewDecoder(body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("calendar: %w", err)
+	}
+	var data bytes.Buffer
+	for _, r := range ms.Responses {
+		for _, ps := range r.Propstats {
+			data.WriteString(ps.CalendarData)
+			data.WriteString("\n")
+		}
+	}
+	return Parse(&data)
+}
+
+// multistatus is the part of a CalDAV REPORT response we read.
+type multistatus struct {
+	Responses []struct {
+		Propstats []struct {
+			CalendarData string `xml:"prop>calendar-data"`
+		} `xml:"propstat"`
+	} `xml:"response"`
+}
+
+// calendarQuery asks for the VEVENTs overlapping [from, to), recurring
+// ones included (RFC 4791 section 7.8.1).
+func calendarQuery(from, to time.Time) string {
+	const layout = "20060102T150405Z"
+	return `<?xml version="1.0" encoding="utf-8"?>
+<c:calendar-query xmlns:d="DAV:" xmlns:c="urn:ietf:params:xml:ns:caldav">
+  <d:prop><c:calendar-data/></d:prop>
+  <c:filter>
+    <c:comp-filter name="VCALENDAR">
+      <c:comp-filter name="VEVENT">
+        <c:time-range start="` + from.UTC().Format(layout) + `" end="` + to.UTC().Format(layout) + `"/>
+      </c:comp-filter>
+    </c:comp-filter>
+  </c:filter>
+</c:calendar-query>`
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// SnoozeFor is how long the Snooze button extends a phase.
+const SnoozeFor = 5 * time.Minute
+
+// PhaseActions returns the buttons for the notification about ev: start
+// the break or snooze when overtime begins, snooze or skip a phase that
+// just started. A click is ignored once the engine has moved on.
+func PhaseActions(eng *core.PomodoroEngine, ev core.Event) []Action {
+	same := func() bool {
+		st := eng.State()
+		return st.StartedAt.Equal(ev.State.StartedAt) && st.Overtime == ev.State.Overtime
+	}
+	snooze := Action{ID: "snooze", Label: "Snooze 5 min", Do: func() {
+		if same() {
+			eng.Extend(SnoozeFor)
+		}
+	}}
+	switch ev.Kind {
+	case core.EventOvertime:
+		return []Action{
+			{ID: "start-break", Label: "Start break", Do: func() {
+				if same() {
+					eng.Acknowledge()
+				}
+			}},
+			snooze,
+		}
+	case core.EventAdvance:
+		return []Action{
+			snooze,
+			{ID: "skip", Label: "Skip", Do: func() {
+				if same() {
+					eng.Skip()
+				}
+			}},
+		}
+	}
+	return nil
+}
@@ -0,0 +1,153 @@
+package notify
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// BatchOptions controls how a Batcher groups and throttles messages.
+type BatchOptions struct {
+	// Window is how long messages are collected before a flush.
+	Window time.Duration
+	// MinInterval is the minimum gap between two deliveries to the
+	// wrapped backend. Zero means Window is the only limit.
+	MinInterval time.Duration
+	// Key groups identical events in a window (e.g. the same phase change
+	// reported by several participants). Defaults to title+body.
+	Key func(title, body string) string
+	// OnError receives delivery errors, since Notify returns before the
+	// wrapped backend is called.
+	OnError func(error)
+}
+
+// Batcher wraps a Notifier and turns a burst of messages into a single
+// delivery: duplicates sharing a key collapse into one message and
+// distinct messages inside the same window are merged into a digest.
+// Each backend should get its own Batcher so throttling is per backend.
+type Batcher struct {
+	next Notifier
+	opts BatchOptions
+
+	mu      sync.Mutex
+	pending []Message
+	seen    map[string]bool
+	timer   *time.Timer
+	last    time.Time
+	closed  bool
+}
+
+// NewBatcher creates a Batcher delivering to next.
+func NewBatcher(next Notifier, opts BatchOptions) *Batcher {
+	if opts.Key == nil {
+		opts.Key = func(title, body string) string { return title + "\x00" + body }
+	}
+	return &Batcher{
+		next: next,
+		opts: opts,
+		seen: make(map[string]bool),
+	}
+}
+
+// Notify queues a message. It never blocks on the wrapped backend.
+func (b *Batcher) Notify(title, body string) error {
+	return b.NotifyMessage(Message{Title: title, Body: body})
+}
+
+// NotifyMessage queues msg, keeping its event and silence for the
+// delivery.
+func (b *Batcher) NotifyMessage(msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil
+	}
+	key := b.opts.Key(msg.Title, msg.Body)
+	if b.seen[key] {
+		return nil
+	}
+	b.seen[key] = true
+	b.pending = append(b.pending, msg)
+
+	if b.timer == nil {
+		wait := b.opts.Window
+		if b.opts.MinInterval > 0 && !b.last.IsZero() {
+			wait = max(wait, time.Until(b.last.Add(b.opts.MinInterval)))
+		}
+		b.timer = time.AfterFunc(wait, b.flush)
+	}
+	return nil
+}
+
+// Close delivers anything still pending and stops accepting messages.
+func (b *Batcher) Close() error {
+	b.mu.Lock()
+	b.closed = true
+	if b.timer != nil {
+		b.timer.Stop()
+	}
+	b.mu.Unlock()
+	return b.deliver()
+}
+
+func (b *Batcher) flush() {
+	if err := b.deliver(); err != nil && b.opts.OnError != nil {
+		b.opts.OnError(err)
+	}
+}
+
+// deliver sends the pending batch, as one message or a digest.
+func (b *Batcher) deliver() error {
+	b.mu.Lock()
+	batch := b.pending
+	b.pending = nil
+	b.seen = make(map[string]bool)
+	b.timer = nil
+	if len(batch) > 0 {
+		b.last = time.Now()
+	}
+	b.mu.Unlock()
+
+	if len(batch) == 0 {
+		return nil
+	}
+	return Send(b.next, digest(batch))
+}
+
+// digest merges a batch into a single message. When every message
+// shares a title, it is kept and the bodies are listed below. The
+// digest carries the latest event, and is silent only if every message
+// was.
+func digest(batch []Message) Message {
+	if len(batch) == 1 {
+		return batch[0]
+	}
+	out := Message{Title: batch[0].Title, Silent: true}
+	for _, m := range batch {
+		if m.Title != out.Title {
+			out.Title = ""
+		}
+		if !m.Event.At.Before(out.Event.At) {
+			out.Event = m.Event
+		}
+		out.Silent = out.Silent && m.Silent
+	}
+	lines := make([]string, 0, len(batch))
+	for _, m := range batch {
+		if out.Title == "" {
+			lines = append(lines, m.Title+": "+m.Body)
+		} else {
+			lines = append(lines, m.Body)
+		}
+	}
+	if out.Title == "" {
+		out.Title = "GoPomodoro"
+	}
+	out.Body = strings.Join(lines, "\n")
+	return out
+}
+
+var (
+	_ Notifier        = (*Batcher)(nil)
+	_ MessageNotifier = (*Batcher)(nil)
+)
@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type recordNotifier struct {
+	mu   sync.Mutex
+	msgs []Message
+	sent chan struct{}
+}
+
+func newRecordNotifier() *recordNotifier {
+	return &recordNotifier{sent: make(chan struct{}, 16)}
+}
+
+func (r *recordNotifier) Notify(title, body string) error {
+	r.mu.Lock()
+	r.msgs = append(r.msgs, Message{Title: title, Body: body})
+	r.mu.Unlock()
+	r.sent <- struct{}{}
+	return nil
+}
+
+func (r *recordNotifier) wait(t *testing.T) {
+	t.Helper()
+	select {
+	case <-r.sent:
+	case <-time.After(time.Second):
+		t.Fatal("timeout waiting for delivery")
+	}
+}
+
+func TestBatcher_CollapsesDuplicates(t *testing.T) {
+	rec := newRecordNotifier()
+	b := NewBatcher(rec, BatchOptions{Window: 20 * time.Millisecond})
+
+	for range 5 {
+		_ = b.Notify("GoPomodoro", "Phase: SHORT_BREAK")
+	}
+	rec.wait(t)
+
+	if len(rec.msgs) != 1 {
+		t.Fatalf("expected 1 delivery, got %d", len(rec.msgs))
+	}
+	if rec.msgs[0].Body != "Phase: SHORT_BREAK" {
+		t.Fatalf("unexpected body %q", rec.msgs[0].Body)
+	}
+}
+
+func TestBatcher_DigestsDistinctMessages(t *testing.T) {
+	rec := newRecordNotifier()
+	b := NewBatcher(rec, BatchOptions{Window: 20 * time.Millisecond})
+
+	_ = b.Notify("GoPomodoro", "alice paused")
+	_ = b.Notify("GoPomodoro", "bob paused")
+	rec.wait(t)
+
+	if len(rec.msgs) != 1 {
+		t.Fatalf("expected 1 digest, got %d", len(rec.msgs))
+	}
+	want := "alice paused\nbob paused"
+	if rec.msgs[0].Title != "GoPomodoro" || rec.msgs[0].Body != want {
+		t.Fatalf("unexpected digest %+v", rec.msgs[0])
+	}
+}
+
+func TestBatcher_MinInterval(t *testing.T) {
+	rec := newRecordNotifier()
+	b := NewBatcher(rec, BatchOptions{
+		Window:      time.Millisecond,
+		MinInterval: 80 * time.Millisecond,
+	})
+
+	_ = b.Notify("GoPomodoro", "first")
+	rec.wait(t)
+	start := time.Now()
+	_ = b.Notify("GoPomodoro", "second")
+	rec.wait(t)
+
+	if gap := time.Since(start); gap < 60*time.Millisecond {
+		t.Fatalf("second delivery not throttled: %v", gap)
+	}
+}
+
+func TestBatcher_CloseFlushes(t *testing.T) {
+	rec := newRecordNotifier()
+	b := NewBatcher(rec, BatchOptions{Window: time.Hour})
+
+	_ = b.Notify("GoPomodoro", "pending")
+	if err := b.Close(); err != nil {
+		t.Fatalf("close: %v", err)
+	}
+	if len(rec.msgs) != 1 {
+		t.Fatalf("expected flush on close, got %d deliveries", len(rec.msgs))
+	}
+	_ = b.Notify("GoPomodoro", "after close")
+	if len(rec.msgs) != 1 {
+		t.Fatal("notify after close should be dropped")
+	}
+}
+
+func TestBatcher_KeepsEventAndSilence(t *testing.T) {
+	got := make(chan WebhookPayload, 4)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var p WebhookPayload
+		_ = json.NewDecoder(r.Body).Decode(&p)
+		got <- p
+	}))
+	defer srv.Close()
+	wh := NewWebhook(WebhookOptions{URLs: []string{srv.URL}})
+	b := NewBatcher(wh, BatchOptions{Window: time.Hour})
+
+	at := time.Now()
+	first := core.Event{Kind: core.EventStart, State: core.State{Phase: core.PhaseWork}, At: at}
+	last := core.Event{Kind: core.EventAdvance, State: core.State{Phase: core.PhaseShortBreak, PomodoroDone: 1}, At: at.Add(time.Second)}
+	_ = Send(b, Message{Title: "GoPomodoro", Body: "alice started", Event: first, Silent: true})
+	_ = Send(b, Message{Title: "GoPomodoro", Body: "bob's pomodoro done", Event: last, Silent: true})
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	p := <-got
+	if p.Event != "advance" || p.Phase != "SHORT_BREAK" || p.PomodoroDone != 1 || !p.Silent {
+		t.Fatalf("digest of two silent messages = %+v", p)
+	}
+
+	b = NewBatcher(wh, BatchOptions{Window: time.Hour})
+	_ = Send(b, Message{Title: "GoPomodoro", Body: "alice paused", Event: first, Silent: true})
+	_ = Send(b, Message{Title: "GoPomodoro", Body: "bob paused", Event: first})
+	if err := b.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if p := <-got; p.Silent || p.Event != "start" {
+		t.Fatalf("digest with a loud message = %+v", p)
+	}
+}
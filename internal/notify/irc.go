@@ -0,0 +1,241 @@
+package notify
+
+import (
+	"bufio"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// IRCOptions configures an IRC notifier.
+type IRCOptions struct {
+	Server   string   // host:port, e.g. irc.libera.chat:6697
+	TLS      bool     // connect with TLS, as port 6697 expects
+	Nick     string   // default "gopomodoro"
+	Password string   // server password, e.g. for a bouncer or NickServ
+	Channels []string // e.g. ["#focus"]
+	// NoJoin sends without joining, to channels that allow messages
+	// from outside (mode -n); else every message joins and leaves.
+	NoJoin bool
+	// Notice sends NOTICEs, which clients don't alert on, as bots
+	// usually do; silent messages are always notices.
+	Notice  bool
+	Timeout time.Duration // bounds each message; default 20s
+	Dialer  func(network, addr string) (net.Conn, error)
+}
+
+// IRC posts notifications to IRC channels. It connects for each
+// message rather than staying online, so it never needs reconnecting.
+type IRC struct {
+	opts IRCOptions
+}
+
+// NewIRC creates an IRC notifier, failing without a server or channel.
+func NewIRC(opts IRCOptions) (*IRC, error) {
+	if opts.Server == "" || len(opts.Channels) == 0 {
+		return nil, fmt.Errorf("irc: server and channels are required")
+	}
+	if _, _, err := net.SplitHostPort(opts.Server); err != nil {
+		return nil, fmt.Errorf("irc: server %q: %w", opts.Server, err)
+	}
+	for _, ch := range opts.Channels {
+		if ch == "" || !strings.ContainsRune("#&+!", rune(ch[0])) || strings.ContainsAny(ch, " ,") {
+			return nil, fmt.Errorf("irc: %q is not a channel", ch)
+		}
+	}
+	if opts.Nick == "" {
+		opts.Nick = "gopomodoro"
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 20 * time.Second
+	}
+	if opts.Dialer == nil {
+		d := &net.Dialer{Timeout: opts.Timeout}
+		opts.Dialer = d.Dial
+	}
+	return &IRC{opts: opts}, nil
+}
+
+func (c *IRC) Notify(title, body string) error {
+	return c.NotifyMessage(Message{Title: title, Body: body})
+}
+
+// NotifyMessage sends msg to every channel as one line.
+func (c *IRC) NotifyMessage(msg Message) error {
+	verb := "PRIVMSG"
+	if c.opts.Notice || msg.Silent {
+		verb = "NOTICE"
+	}
+	if err := c.send(verb, ircLine(msg)); err != nil {
+		return fmt.Errorf("irc %s: %w", c.opts.Server, err)
+	}
+	return nil
+}
+
+// send connects, registers, sends text to the channels with verb and
+// quits, reporting what the server refused on the way.
+func (c *IRC) send(verb, text string) error {
+	conn, err := c.opts.Dialer("tcp", c.opts.Server)
+	if err != nil {
+		return err
+	}
+	if c.opts.TLS {
+		host, _, _ := net.SplitHostPort(c.opts.Server)
+		conn = tls.Client(conn, &tls.Config{ServerName: host})
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(c.opts.Timeout)); err != nil {
+		return err
+	}
+	r := bufio.NewReader(conn)
+	write := func(format string, args ...any) error {
+		_, err := fmt.Fprintf(conn, format+"\r\n", args...)
+		return err
+	}
+
+	if c.opts.Password != "" {
+		if err := write("PASS %s", c.opts.Password); err != nil {
+			return err
+		}
+	}
+	nick := c.opts.Nick
+	if err := write("NICK %s", nick); err != nil {
+		return err
+	}
+	if err := write("USER %s 0 * :GoPomodoro", c.opts.Nick); err != nil {
+		return err
+	}
+	// wait for the welcome, answering pings and taking another nick
+	// while ours is in use
+	for registered := false; !registered; {
+		m, err := readIRC(r)
+		if err != nil {
+			return err
+		}
+		switch m.command {
+		case "001":
+			registered = true
+		case "PING":
+			err = write("PONG :%s", m.trailing())
+		case "433": // nickname in use
+			if len(nick) >= len(c.opts.Nick)+3 {
+				return errors.New("nickname in use")
+			}
+			nick += "_"
+			err = write("NICK %s", nick)
+		case "ERROR":
+			return errors.New(m.trailing())
+		default:
+			if m.failed() {
+				return errors.New(m.trailing())
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if !c.opts.NoJoin {
+		if err := write("JOIN %s", strings.Join(c.opts.Channels, ",")); err != nil {
+			return err
+		}
+	}
+	for _, ch := range c.opts.Channels {
+		if err := write("%s %s :%s", verb, ch, text); err != nil {
+			return err
+		}
+	}
+	if err := write("QUIT :GoPomodoro"); err != nil {
+		return err
+	}
+	// the server answers in order, so whatever it refused comes before
+	// it closes the link after QUIT
+	var errs []error
+	for {
+		m, err := readIRC(r)
+		if err != nil || m.command == "ERROR" {
+			break
+		}
+		if m.command == "PING" {
+			_ = write("PONG :%s", m.trailing())
+		} else if m.failed() {
+			errs = append(errs, errors.New(strings.Join(m.params[1:], " ")))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ircLine is msg as one line, "Title: Body", cut to what fits in an IRC
+// message.
+func ircLine(msg Message) string {
+	text := msg.Body
+	if msg.Title != "" {
+		text = msg.Title + ": " + msg.Body
+	}
+	text = strings.Join(strings.Fields(text), " ")
+	const limit = 400 // of the 512 bytes, leaving room for the prefix
+	if len(text) > limit {
+		cut := limit - len("…")
+		for !utf8.RuneStart(text[cut]) {
+			cut--
+		}
+		text = text[:cut] + "…"
+	}
+	return text
+}
+
+// ircMessage is a message from the server.
+type ircMessage struct {
+	command string
+	params  []string
+}
+
+// trailing is the last parameter, usually the human-readable one.
+func (m ircMessage) trailing() string {
+	if len(m.params) == 0 {
+		return ""
+	}
+	return m.params[len(m.params)-1]
+}
+
+// failed reports whether m is an error reply, numerics 400 to 599.
+func (m ircMessage) failed() bool {
+	return len(m.command) == 3 && (m.command[0] == '4' || m.command[0] == '5') && len(m.params) > 1
+}
+
+// readIRC reads the next message, e.g. ":srv 404 nick #focus :Cannot
+// send to channel".
+func readIRC(r *bufio.Reader) (ircMessage, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return ircMessage{}, err
+	}
+	line = strings.TrimRight(line, "\r\n")
+	if strings.HasPrefix(line, "@") { // message tags
+		_, line, _ = strings.Cut(line, " ")
+	}
+	if strings.HasPrefix(line, ":") { // source
+		_, line, _ = strings.Cut(line, " ")
+	}
+	var m ircMessage
+	line, trailing, hasTrailing := strings.Cut(line, " :")
+	fields := strings.Fields(line)
+	if len(fields) == 0 {
+		return readIRC(r)
+	}
+	m.command = strings.ToUpper(fields[0])
+	m.params = fields[1:]
+	if hasTrailing {
+		m.params = append(m.params, trailing)
+	}
+	return m, nil
+}
+
+var (
+	_ Notifier        = (*IRC)(nil)
+	_ MessageNotifier = (*IRC)(nil)
+)
@@ -0,0 +1,120 @@
+package notify
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeIRC serves one connection, answering as a server would and
+// returning the lines the client sent; refuse makes messages to that
+// channel fail.
+func fakeIRC(t *testing.T, refuse string) (func(string, string) (net.Conn, error), <-chan []string) {
+	t.Helper()
+	lines := make(chan []string, 1)
+	dial := func(string, string) (net.Conn, error) {
+		// a real connection, as a pipe would block the server's replies
+		// until the client reads them
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			return nil, err
+		}
+		defer ln.Close()
+		client, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			return nil, err
+		}
+		server, err := ln.Accept()
+		if err != nil {
+			return nil, err
+		}
+		go func() {
+			defer server.Close()
+			var got []string
+			defer func() { lines <- got }()
+			r := bufio.NewReader(server)
+			say := func(s string) { fmt.Fprintf(server, "%s\r\n", s) }
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				line = strings.TrimRight(line, "\r\n")
+				got = append(got, line)
+				switch cmd, rest, _ := strings.Cut(line, " "); cmd {
+				case "NICK":
+					if rest == "pomo" {
+						say(":srv 433 * pomo :Nickname is already in use")
+					} else {
+						say(":srv PING :123")
+						say(":srv 001 " + rest + " :Welcome")
+					}
+				case "PRIVMSG", "NOTICE":
+					if ch, _, _ := strings.Cut(rest, " "); ch == refuse {
+						say(":srv 404 pomo_ " + ch + " :Cannot send to channel")
+					}
+				case "QUIT":
+					say("ERROR :Closing link")
+					return
+				}
+			}
+		}()
+		return client, nil
+	}
+	return dial, lines
+}
+
+func TestIRC_Sends(t *testing.T) {
+	dial, lines := fakeIRC(t, "")
+	c, err := NewIRC(IRCOptions{Server: "irc.example.org:6667", Nick: "pomo", Password: "hunter2", Channels: []string{"#focus", "#team"}, Dialer: dial})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Notify("GoPomodoro", "Break\ntime"); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	want := []string{
+		"PASS hunter2",
+		"NICK pomo",
+		"USER pomo 0 * :GoPomodoro",
+		"NICK pomo_",
+		"PONG :123",
+		"JOIN #focus,#team",
+		"PRIVMSG #focus :GoPomodoro: Break time",
+		"PRIVMSG #team :GoPomodoro: Break time",
+		"QUIT :GoPomodoro",
+	}
+	if got := <-lines; strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("sent\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+
+	c.opts.NoJoin = true
+	if err := Send(c, Message{Body: "at night", Silent: true}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	got := <-lines
+	if strings.Contains(strings.Join(got, "\n"), "JOIN") || got[len(got)-2] != "NOTICE #team :at night" {
+		t.Fatalf("silent message sent as %q, want a notice without joining", got)
+	}
+}
+
+func TestIRC_ReportsErrors(t *testing.T) {
+	dial, _ := fakeIRC(t, "#team")
+	c, _ := NewIRC(IRCOptions{Server: "irc.example.org:6667", Channels: []string{"#focus", "#team"}, Dialer: dial, Timeout: time.Second})
+	err := c.Notify("GoPomodoro", "hi")
+	if err == nil || err.Error() != "irc irc.example.org:6667: #team Cannot send to channel" {
+		t.Fatalf("got %v, want the refusal", err)
+	}
+
+	for _, opts := range []IRCOptions{{}, {Server: "irc.example.org", Channels: []string{"#focus"}}, {Server: "h:6667", Channels: []string{"focus"}}} {
+		if _, err := NewIRC(opts); err == nil {
+			t.Errorf("accepted %+v", opts)
+		}
+	}
+	if got := ircLine(Message{Body: strings.Repeat("é", 300)}); len(got) > 400 || !strings.HasSuffix(got, "é…") {
+		t.Errorf("long line cut to %d bytes, %q", len(got), got[len(got)-8:])
+	}
+}
@@ -0,0 +1,55 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Sound plays a sound per notification: Command if set (e.g. paplay
+// with a file), the system beep otherwise.
+type Sound struct {
+	Command []string
+}
+
+func (s Sound) Notify(title, body string) error {
+	if len(s.Command) == 0 {
+		return Beep()
+	}
+	return exec.Command(s.Command[0], s.Command[1:]...).Run()
+}
+
+func (s Sound) NotifyMessage(msg Message) error {
+	if msg.Silent {
+		return nil
+	}
+	return s.Notify(msg.Title, msg.Body)
+}
+
+// Log appends a timestamped line per notification to W.
+type Log struct {
+	W   io.Writer
+	Now func() time.Time // default time.Now
+
+	mu sync.Mutex
+}
+
+func (l *Log) Notify(title, body string) error {
+	now := time.Now
+	if l.Now != nil {
+		now = l.Now
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, err := fmt.Fprintf(l.W, "%s %s: %s\n", now().Format(time.RFC3339), title, strings.ReplaceAll(body, "\n", "; "))
+	return err
+}
+
+var (
+	_ Notifier        = Sound{}
+	_ MessageNotifier = Sound{}
+	_ Notifier        = (*Log)(nil)
+)
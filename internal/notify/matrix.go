@@ -0,0 +1,173 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MatrixOptions configures a Matrix notifier.
+type MatrixOptions struct {
+	Homeserver string // e.g. https://matrix.org
+	// Room is a room ID, e.g. "!abc:matrix.org", or an alias, e.g.
+	// "#focus:matrix.org"; the account must have joined it.
+	Room  string
+	Token string // the account's access token
+	// Notice posts m.notice messages, which clients don't alert on, as
+	// bots usually do; silent messages are always notices.
+	Notice  bool
+	Timeout time.Duration
+	Retries int
+	Backoff time.Duration
+	Client  *http.Client
+}
+
+// Matrix posts notifications to a Matrix room.
+type Matrix struct {
+	opts MatrixOptions
+	txn  atomic.Int64
+
+	mu   sync.Mutex
+	room string // the resolved room ID
+}
+
+// NewMatrix creates a Matrix notifier, failing without a homeserver,
+// room or token.
+func NewMatrix(opts MatrixOptions) (*Matrix, error) {
+	if opts.Homeserver == "" || opts.Room == "" || opts.Token == "" {
+		return nil, fmt.Errorf("matrix: homeserver, room and token are required")
+	}
+	if !strings.HasPrefix(opts.Room, "!") && !strings.HasPrefix(opts.Room, "#") {
+		return nil, fmt.Errorf("matrix: room %q is neither an ID (!…) nor an alias (#…)", opts.Room)
+	}
+	opts.Homeserver = strings.TrimSuffix(opts.Homeserver, "/")
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	m := &Matrix{opts: opts}
+	if strings.HasPrefix(opts.Room, "!") {
+		m.room = opts.Room
+	}
+	return m, nil
+}
+
+func (m *Matrix) Notify(title, body string) error {
+	return m.NotifyMessage(Message{Title: title, Body: body})
+}
+
+// NotifyMessage posts msg to the room, the title in bold.
+func (m *Matrix) NotifyMessage(msg Message) error {
+	room, err := m.roomID()
+	if err != nil {
+		return fmt.Errorf("matrix %s: %w", m.opts.Room, err)
+	}
+	content := map[string]string{"msgtype": "m.text", "body": msg.Body}
+	if m.opts.Notice || msg.Silent {
+		content["msgtype"] = "m.notice"
+	}
+	if msg.Title != "" {
+		content["body"] = msg.Title + ": " + msg.Body
+		content["format"] = "org.matrix.custom.html"
+		content["formatted_body"] = "<b>" + html.EscapeString(msg.Title) + "</b>: " + html.EscapeString(msg.Body)
+	}
+	data, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	// retries reuse the transaction ID, so the homeserver posts the
+	// message once however many attempts reach it
+	txn := fmt.Sprintf("gopomodoro.%d.%d", time.Now().UnixNano(), m.txn.Add(1))
+	endpoint := m.opts.Homeserver + "/_matrix/client/v3/rooms/" + url.PathEscape(room) + "/send/m.room.message/" + txn
+	err = retrying(m.opts.Retries, m.opts.Backoff, func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), m.opts.Timeout)
+		defer cancel()
+		req, err := m.request(ctx, http.MethodPut, endpoint, data)
+		if err != nil {
+			return false, err
+		}
+		return deliver(m.opts.Client, req, matrixError)
+	})
+	if err != nil {
+		return fmt.Errorf("matrix %s: %w", m.opts.Room, err)
+	}
+	return nil
+}
+
+// roomID returns the room's ID, looking an alias up the first time.
+func (m *Matrix) roomID() (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.room != "" {
+		return m.room, nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), m.opts.Timeout)
+	defer cancel()
+	req, err := m.request(ctx, http.MethodGet, m.opts.Homeserver+"/_matrix/client/v3/directory/room/"+url.PathEscape(m.opts.Room), nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := m.opts.Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	var body struct {
+		RoomID  string `json:"room_id"`
+		Code    string `json:"errcode"`
+		Message string `json:"error"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	if resp.StatusCode >= 300 || body.RoomID == "" {
+		if body.Code != "" {
+			return "", fmt.Errorf("alias lookup: status %s: %s %s", resp.Status, body.Code, body.Message)
+		}
+		return "", fmt.Errorf("alias lookup: status %s", resp.Status)
+	}
+	m.room = body.RoomID
+	return m.room, nil
+}
+
+// request builds an authorized request, with data as its JSON body if
+// set.
+func (m *Matrix) request(ctx context.Context, method, endpoint string, data []byte) (*http.Request, error) {
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	if data != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Authorization", "Bearer "+m.opts.Token)
+	req.Header.Set("User-Agent", "GoPomodoro")
+	return req, nil
+}
+
+// matrixError is the reason in a Matrix error response, e.g.
+// {"errcode":"M_FORBIDDEN","error":"User not in room"}.
+func matrixError(body []byte) string {
+	var resp struct {
+		Code    string `json:"errcode"`
+		Message string `json:"error"`
+	}
+	_ = json.Unmarshal(body, &resp)
+	return strings.TrimSpace(resp.Code + " " + resp.Message)
+}
+
+var (
+	_ Notifier        = (*Matrix)(nil)
+	_ MessageNotifier = (*Matrix)(nil)
+)
@@ -0,0 +1,70 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMatrix_Posts(t *testing.T) {
+	var paths []string
+	var got map[string]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer syt_secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		paths = append(paths, r.Method+" "+r.URL.EscapedPath())
+		if r.Method == http.MethodGet {
+			_, _ = w.Write([]byte(`{"room_id":"!abc:example.org"}`))
+			return
+		}
+		got = nil
+		_ = json.NewDecoder(r.Body).Decode(&got)
+		_, _ = w.Write([]byte(`{"event_id":"$1"}`))
+	}))
+	defer srv.Close()
+
+	m, err := NewMatrix(MatrixOptions{Homeserver: srv.URL + "/", Room: "#focus:example.org", Token: "syt_secret"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Notify("GoPomodoro", "Break <5m>"); err != nil {
+		t.Fatalf("notify: %v", err)
+	}
+	if err := Send(m, Message{Title: "GoPomodoro", Body: "at night", Silent: true}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if len(paths) != 3 || paths[0] != "GET /_matrix/client/v3/directory/room/%23focus:example.org" {
+		t.Fatalf("requests %q, want one alias lookup and two messages", paths)
+	}
+	if !strings.HasPrefix(paths[1], "PUT /_matrix/client/v3/rooms/%21abc:example.org/send/m.room.message/") || paths[1] == paths[2] {
+		t.Fatalf("requests %q, want distinct transactions", paths)
+	}
+	if got["msgtype"] != "m.notice" || got["body"] != "GoPomodoro: at night" || got["formatted_body"] != "<b>GoPomodoro</b>: at night" {
+		t.Fatalf("silent message %v, want a notice", got)
+	}
+}
+
+func TestMatrix_ReportsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errcode":"M_FORBIDDEN","error":"User not in room"}`))
+	}))
+	defer srv.Close()
+
+	m, _ := NewMatrix(MatrixOptions{Homeserver: srv.URL, Room: "!abc:example.org", Token: "t", Retries: 2, Backoff: time.Millisecond})
+	err := m.Notify("GoPomodoro", "hi")
+	if err == nil || !strings.Contains(err.Error(), "matrix !abc:example.org: status 403 Forbidden: M_FORBIDDEN User not in room") {
+		t.Fatalf("got %v, want the homeserver's reason", err)
+	}
+
+	for _, opts := range []MatrixOptions{{}, {Homeserver: "https://h", Room: "focus", Token: "t"}} {
+		if _, err := NewMatrix(opts); err == nil {
+			t.Errorf("accepted %+v", opts)
+		}
+	}
+}
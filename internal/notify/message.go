@@ -0,0 +1,87 @@
+package notify
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/i18n"
+)
+
+// Message is a notification together with the engine event that caused
+// it. Event is the zero value for messages not tied to one.
+type Message struct {
+	Title string
+	Body  string
+	Event core.Event
+
+	// Actions are offered as buttons by backends that support them and
+	// ignored by the rest.
+	Actions []Action
+	// Silent asks for delivery without sound, e.g. during quiet hours;
+	// backends that only make sound drop the message.
+	Silent bool
+}
+
+// Action is a notification button; Do runs when it is clicked.
+type Action struct {
+	ID    string
+	Label string
+	Do    func()
+}
+
+// MessageNotifier is implemented by backends that can use more than
+// the title and body, such as webhooks.
+type MessageNotifier interface {
+	NotifyMessage(msg Message) error
+}
+
+// Send delivers msg through n, using NotifyMessage when n supports it.
+func Send(n Notifier, msg Message) error {
+	if mn, ok := n.(MessageNotifier); ok {
+		return mn.NotifyMessage(msg)
+	}
+	return n.Notify(msg.Title, msg.Body)
+}
+
+// Fanout delivers every message to all of its notifiers and joins
+// their errors.
+type Fanout []Notifier
+
+func (f Fanout) Notify(title, body string) error {
+	return f.NotifyMessage(Message{Title: title, Body: body})
+}
+
+func (f Fanout) NotifyMessage(msg Message) error {
+	var errs []error
+	for _, n := range f {
+		if err := Send(n, msg); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+var (
+	_ Notifier        = Fanout(nil)
+	_ MessageNotifier = Fanout(nil)
+)
+
+// WarningBody is the text for an EventWarning in loc, e.g. "2m left in
+// WORK".
+func WarningBody(loc *i18n.Locale, ev core.Event) string {
+	left := ev.Warning.String()
+	if strings.HasSuffix(left, "m0s") {
+		left = strings.TrimSuffix(left, "0s")
+	}
+	return loc.Sprintf("%s left in %s", left, loc.Name(ev.State))
+}
+
+// FinishedBody is the text in loc for the EventStop ending a run of n
+// pomodoros, e.g. "All 4 pomodoros done, well earned rest".
+func FinishedBody(loc *i18n.Locale, n int) string {
+	if n == 1 {
+		return loc.T("Pomodoro done, well earned rest")
+	}
+	return loc.Sprintf("All %d pomodoros done, well earned rest", n)
+}
@@ -13,6 +13,18 @@ func (beeepNotifier) Notify(title, body string) error {
 	return beeep.Notify(title, body, "")
 }
 
+// New returns the desktop notifier: D-Bus with action buttons on Linux
+// when a notification server is running, native toasts with a progress
+// bar and buttons on Windows, beeep otherwise.
 func New() Notifier {
+	if n := newPlatform(); n != nil {
+		return n
+	}
 	return beeepNotifier{}
 }
+
+// Beep plays a short system beep, e.g. as a gentle heads-up alongside a
+// notification.
+func Beep() error {
+	return beeep.Beep(beeep.DefaultFreq, beeep.DefaultDuration)
+}
@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// NtfyOptions configures an Ntfy notifier.
+type NtfyOptions struct {
+	Server   string // default https://ntfy.sh
+	Topic    string
+	Token    string // access token, for a protected topic
+	Priority int    // 1 (min) to 5 (max); 0 is the server's default
+	Timeout  time.Duration
+	Retries  int
+	Backoff  time.Duration
+	Client   *http.Client
+}
+
+// Ntfy publishes notifications to an ntfy topic, so they reach the
+// phones and browsers subscribed to it.
+type Ntfy struct {
+	opts NtfyOptions
+}
+
+// ntfyMessage is ntfy's JSON publishing format.
+type ntfyMessage struct {
+	Topic    string   `json:"topic"`
+	Title    string   `json:"title,omitempty"`
+	Message  string   `json:"message"`
+	Priority int      `json:"priority,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// NewNtfy creates an Ntfy notifier, failing without a topic or with a
+// priority out of range.
+func NewNtfy(opts NtfyOptions) (*Ntfy, error) {
+	if opts.Topic == "" {
+		return nil, fmt.Errorf("ntfy: no topic")
+	}
+	if opts.Priority < 0 || opts.Priority > 5 {
+		return nil, fmt.Errorf("ntfy: priority %d is not between 1 and 5", opts.Priority)
+	}
+	if opts.Server == "" {
+		opts.Server = "https://ntfy.sh"
+	}
+	opts.Server = strings.TrimSuffix(opts.Server, "/")
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &Ntfy{opts: opts}, nil
+}
+
+func (n *Ntfy) Notify(title, body string) error {
+	return n.NotifyMessage(Message{Title: title, Body: body})
+}
+
+// NotifyMessage publishes msg tagged with the phase's emoji; a silent
+// one goes out at the min priority, which doesn't ring.
+func (n *Ntfy) NotifyMessage(msg Message) error {
+	m := ntfyMessage{Topic: n.opts.Topic, Title: msg.Title, Message: msg.Body, Priority: n.opts.Priority}
+	if msg.Silent {
+		m.Priority = 1
+	}
+	if !msg.Event.At.IsZero() {
+		switch msg.Event.State.Phase {
+		case core.PhaseWork:
+			m.Tags = []string{"tomato"}
+		case core.PhaseShortBreak, core.PhaseLongBreak:
+			m.Tags = []string{"coffee"}
+		}
+	}
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	err = retrying(n.opts.Retries, n.opts.Backoff, func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), n.opts.Timeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.opts.Server, bytes.NewReader(data))
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "GoPomodoro")
+		if n.opts.Token != "" {
+			req.Header.Set("Authorization", "Bearer "+n.opts.Token)
+		}
+		return deliver(n.opts.Client, req, ntfyError)
+	})
+	if err != nil {
+		return fmt.Errorf("ntfy %s: %w", n.opts.Topic, err)
+	}
+	return nil
+}
+
+// ntfyError is the reason in an ntfy error response, e.g.
+// {"code":40101,"http":401,"error":"unauthorized"}.
+func ntfyError(body []byte) string {
+	var resp struct {
+		Error string `json:"error"`
+	}
+	_ = json.Unmarshal(body, &resp)
+	return resp.Error
+}
+
+var (
+	_ Notifier        = (*Ntfy)(nil)
+	_ MessageNotifier = (*Ntfy)(nil)
+)
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestNtfy_Publishes(t *testing.T) {
+	var got ntfyMessage
+	var auth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		got = ntfyMessage{}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	n, err := NewNtfy(NtfyOptions{Server: srv.URL + "/", Topic: "pomodoro-42", Token: "tk_secret", Priority: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ev := core.Event{Kind: core.EventAdvance, State: core.State{Phase: core.PhaseShortBreak}, At: time.Now()}
+	if err := Send(n, Message{Title: "GoPomodoro", Body: "Phase: SHORT_BREAK", Event: ev}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got.Topic != "pomodoro-42" || got.Title != "GoPomodoro" || got.Message != "Phase: SHORT_BREAK" ||
+		got.Priority != 4 || !slices.Equal(got.Tags, []string{"coffee"}) {
+		t.Fatalf("unexpected message %+v", got)
+	}
+	if auth != "Bearer tk_secret" {
+		t.Fatalf("authorization %q", auth)
+	}
+
+	if err := Send(n, Message{Title: "GoPomodoro", Body: "at night", Silent: true}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got.Priority != 1 || got.Tags != nil {
+		t.Fatalf("silent message %+v, want min priority", got)
+	}
+}
+
+func TestNtfy_ReportsErrors(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"code":40301,"http":403,"error":"forbidden"}`))
+	}))
+	defer srv.Close()
+
+	n, _ := NewNtfy(NtfyOptions{Server: srv.URL, Topic: "pomodoro-42", Retries: 2, Backoff: time.Millisecond})
+	err := n.Notify("GoPomodoro", "hi")
+	if err == nil || !strings.Contains(err.Error(), "ntfy pomodoro-42: status 403 Forbidden: forbidden") {
+		t.Fatalf("got %v, want the server's reason", err)
+	}
+
+	for _, opts := range []NtfyOptions{{}, {Topic: "t", Priority: 6}} {
+		if _, err := NewNtfy(opts); err == nil {
+			t.Errorf("accepted %+v", opts)
+		}
+	}
+}
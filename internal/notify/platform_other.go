@@ -0,0 +1,7 @@
+//go:build !linux && !windows
+
+package notify
+
+// newPlatform returns nil: action buttons aren't wired up on this
+// platform yet, so beeep is used.
+func newPlatform() Notifier { return nil }
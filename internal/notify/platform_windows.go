@@ -0,0 +1,134 @@
+package notify
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// toastAppID is PowerShell's registered app ID. Toasts from unpackaged
+// programs need one, and borrowing it saves installing a shortcut.
+const toastAppID = `{1AC14E77-02E7-4E5D-B744-2EB1AE5198B7}\WindowsPowerShell\v1.0\powershell.exe`
+
+// toastScript shows the toast in %[1]s (base64 XML) and, if %[2]s is
+// $true, waits for a click and prints the button's arguments. "shown" is
+// printed once the toast is up so Notify can report failures.
+const toastScript = `$ErrorActionPreference = 'Stop'
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+[Windows.Data.Xml.Dom.XmlDocument, Windows.Data.Xml.Dom, ContentType = WindowsRuntime] | Out-Null
+$xml = New-Object Windows.Data.Xml.Dom.XmlDocument
+$xml.LoadXml([Text.Encoding]::UTF8.GetString([Convert]::FromBase64String('%[1]s')))
+$toast = New-Object Windows.UI.Notifications.ToastNotification $xml
+$toast.Tag = 'phase'
+$toast.Group = 'gopomodoro'
+if (%[2]s) {
+  Register-ObjectEvent -InputObject $toast -EventName Activated -SourceIdentifier click | Out-Null
+  Register-ObjectEvent -InputObject $toast -EventName Dismissed -SourceIdentifier gone | Out-Null
+  Register-ObjectEvent -InputObject $toast -EventName Failed -SourceIdentifier gone2 | Out-Null
+}
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('%[3]s').Show($toast)
+[Console]::Out.WriteLine('shown')
+if (%[2]s) {
+  $ev = Wait-Event -Timeout 3600
+  if ($ev -and $ev.SourceIdentifier -eq 'click') { [Console]::Out.WriteLine($ev.SourceArgs[1].Arguments) }
+}
+`
+
+// toastNotifier shows native WinRT toasts, with a progress bar for the
+// phase and action buttons, by driving PowerShell; that needs neither
+// cgo nor an installed app. Each toast replaces the previous one.
+type toastNotifier struct {
+	powershell string
+
+	mu      sync.Mutex
+	waiting *exec.Cmd // the script waiting for a click on the last toast
+}
+
+// newPlatform returns the toast notifier, or nil without PowerShell.
+func newPlatform() Notifier {
+	ps, err := exec.LookPath("powershell.exe")
+	if err != nil {
+		return nil
+	}
+	return &toastNotifier{powershell: ps}
+}
+
+func (n *toastNotifier) Notify(title, body string) error {
+	return n.NotifyMessage(Message{Title: title, Body: body})
+}
+
+func (n *toastNotifier) NotifyMessage(msg Message) error {
+	doc, err := toastXML(msg)
+	if err != nil {
+		return err
+	}
+	wait := "$false"
+	if len(msg.Actions) > 0 {
+		wait = "$true"
+	}
+	script := fmt.Sprintf(toastScript, base64.StdEncoding.EncodeToString([]byte(doc)), wait, toastAppID)
+	cmd := exec.Command(n.powershell, "-NoProfile", "-NonInteractive", "-Command", "-")
+	cmd.Stdin = strings.NewReader(script)
+	cmd.SysProcAttr = &syscall.SysProcAttr{HideWindow: true, CreationFlags: 0x08000000} // CREATE_NO_WINDOW
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	// the new toast replaces the old one, so its buttons are gone
+	if n.waiting != nil {
+		_ = n.waiting.Process.Kill()
+		n.waiting = nil
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	lines := bufio.NewScanner(stdout)
+	if !lines.Scan() || lines.Text() != "shown" {
+		_ = cmd.Wait()
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("toast: %s", msg)
+		}
+		return errors.New("toast: not shown")
+	}
+	if len(msg.Actions) == 0 {
+		return cmd.Wait()
+	}
+	n.waiting = cmd
+	go n.clicked(cmd, lines, msg.Actions)
+	return nil
+}
+
+// clicked runs the action whose ID the script prints, if any.
+func (n *toastNotifier) clicked(cmd *exec.Cmd, lines *bufio.Scanner, actions []Action) {
+	var key string
+	if lines.Scan() {
+		key = strings.TrimSpace(lines.Text())
+	}
+	_ = cmd.Wait()
+	n.mu.Lock()
+	if n.waiting == cmd {
+		n.waiting = nil
+	}
+	n.mu.Unlock()
+	for _, a := range actions {
+		if a.ID == key && a.Do != nil {
+			a.Do()
+		}
+	}
+}
+
+var (
+	_ Notifier        = (*toastNotifier)(nil)
+	_ MessageNotifier = (*toastNotifier)(nil)
+)
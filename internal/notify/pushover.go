@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// pushoverAPI is Pushover's message endpoint.
+const pushoverAPI = "https://api.pushover.net/1/messages.json"
+
+// PushoverOptions configures a Pushover notifier.
+type PushoverOptions struct {
+	Token  string // the application's API token
+	User   string // user or group key
+	Device string // device names, comma separated; default all
+	Sound  string // one of Pushover's sounds; default the user's
+	// Priority is -2 (no alert) to 1 (high); emergency priority, which
+	// repeats until acknowledged, is not supported.
+	Priority int
+	API      string // default Pushover's message endpoint
+	Timeout  time.Duration
+	Retries  int
+	Backoff  time.Duration
+	Client   *http.Client
+}
+
+// Pushover sends notifications to the Pushover apps of a user or
+// group.
+type Pushover struct {
+	opts PushoverOptions
+}
+
+// NewPushover creates a Pushover notifier, failing without a token or
+// user key or with a priority out of range.
+func NewPushover(opts PushoverOptions) (*Pushover, error) {
+	if opts.Token == "" || opts.User == "" {
+		return nil, fmt.Errorf("pushover: token and user are required")
+	}
+	if opts.Priority < -2 || opts.Priority > 1 {
+		return nil, fmt.Errorf("pushover: priority %d is not between -2 and 1", opts.Priority)
+	}
+	if opts.API == "" {
+		opts.API = pushoverAPI
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &Pushover{opts: opts}, nil
+}
+
+func (p *Pushover) Notify(title, body string) error {
+	return p.NotifyMessage(Message{Title: title, Body: body})
+}
+
+// NotifyMessage sends msg; a silent one goes out at quiet priority,
+// which shows it without sound or vibration.
+func (p *Pushover) NotifyMessage(msg Message) error {
+	priority := p.opts.Priority
+	if msg.Silent {
+		priority = min(priority, -1)
+	}
+	form := url.Values{
+		"token":    {p.opts.Token},
+		"user":     {p.opts.User},
+		"title":    {msg.Title},
+		"message":  {msg.Body},
+		"priority": {strconv.Itoa(priority)},
+	}
+	if p.opts.Device != "" {
+		form.Set("device", p.opts.Device)
+	}
+	if p.opts.Sound != "" {
+		form.Set("sound", p.opts.Sound)
+	}
+	if !msg.Event.At.IsZero() {
+		form.Set("timestamp", strconv.FormatInt(msg.Event.At.Unix(), 10))
+	}
+	data := form.Encode()
+	err := retrying(p.opts.Retries, p.opts.Backoff, func() (bool, error) {
+		ctx, cancel := context.WithTimeout(context.Background(), p.opts.Timeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.opts.API, strings.NewReader(data))
+		if err != nil {
+			return false, err
+		}
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		req.Header.Set("User-Agent", "GoPomodoro")
+		return deliver(p.opts.Client, req, pushoverError)
+	})
+	if err != nil {
+		return fmt.Errorf("pushover: %w", err)
+	}
+	return nil
+}
+
+// pushoverError is the reason in a Pushover error response, e.g.
+// {"user":"invalid","errors":["user identifier is invalid"],"status":0}.
+func pushoverError(body []byte) string {
+	var resp struct {
+		Errors []string `json:"errors"`
+	}
+	_ = json.Unmarshal(body, &resp)
+	return strings.Join(resp.Errors, "; ")
+}
+
+var (
+	_ Notifier        = (*Pushover)(nil)
+	_ MessageNotifier = (*Pushover)(nil)
+)
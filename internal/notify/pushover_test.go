@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPushover_Sends(t *testing.T) {
+	var got url.Values
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		got = r.PostForm
+		_, _ = w.Write([]byte(`{"status":1,"request":"abc"}`))
+	}))
+	defer srv.Close()
+
+	p, err := NewPushover(PushoverOptions{Token: "app", User: "me", Device: "phone", Sound: "bike", API: srv.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Notify("GoPomodoro", "Phase: WORK"); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	for k, want := range map[string]string{
+		"token": "app", "user": "me", "device": "phone", "sound": "bike",
+		"title": "GoPomodoro", "message": "Phase: WORK", "priority": "0",
+	} {
+		if got.Get(k) != want {
+			t.Errorf("%s = %q, want %q", k, got.Get(k), want)
+		}
+	}
+
+	if err := Send(p, Message{Title: "GoPomodoro", Body: "at night", Silent: true}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if got.Get("priority") != "-1" {
+		t.Errorf("silent priority %q, want -1", got.Get("priority"))
+	}
+}
+
+func TestPushover_ReportsErrors(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"user":"invalid","errors":["user identifier is invalid"],"status":0}`))
+	}))
+	defer srv.Close()
+
+	p, _ := NewPushover(PushoverOptions{Token: "app", User: "nobody", API: srv.URL, Retries: 2, Backoff: time.Millisecond})
+	err := p.Notify("GoPomodoro", "hi")
+	if err == nil || !strings.Contains(err.Error(), "user identifier is invalid") {
+		t.Fatalf("got %v, want Pushover's reason", err)
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("4xx should not be retried, got %d attempts", calls.Load())
+	}
+
+	for _, opts := range []PushoverOptions{{Token: "app"}, {Token: "app", User: "me", Priority: 2}} {
+		if _, err := NewPushover(opts); err == nil {
+			t.Errorf("accepted %+v", opts)
+		}
+	}
+}
@@ -0,0 +1,296 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Quiet modes say what a backend does during quiet hours.
+const (
+	QuietMute   = "mute"   // drop messages
+	QuietSilent = "silent" // deliver them without sound
+	QuietOff    = "off"    // notify as usual
+)
+
+// Topics are the names a backend's event filter can list.
+var Topics = []string{
+	"work_start", "work_end",
+	"break_start", "break_end", "short_break_start", "long_break_start",
+	"warning", "overtime",
+	"info", // messages not about a phase, such as the daily goal
+}
+
+// MessageTopics returns the topics msg belongs to. A new break is both
+// "work_end" and "break_start" (plus its kind); a new work phase is
+// "break_end" and "work_start".
+func MessageTopics(msg Message) []string {
+	ev := msg.Event
+	if ev.At.IsZero() {
+		return []string{"info"}
+	}
+	switch ev.Kind {
+	case core.EventAdvance, core.EventSkip, core.EventStart:
+		switch ev.State.Phase {
+		case core.PhaseWork:
+			if ev.Kind == core.EventStart {
+				return []string{"work_start"}
+			}
+			return []string{"break_end", "work_start"}
+		case core.PhaseShortBreak:
+			return []string{"work_end", "break_start", "short_break_start"}
+		case core.PhaseLongBreak:
+			return []string{"work_end", "break_start", "long_break_start"}
+		}
+	case core.EventWarning:
+		return []string{"warning"}
+	case core.EventOvertime:
+		return []string{"overtime"}
+	case core.EventRefused:
+		return []string{"info"}
+	}
+	return []string{ev.Kind.String()}
+}
+
+// Registry delivers every message to the backends added to it whose
+// filter matches, or those its route names, so several can be on at
+// once. A backend that fails hands the message to its fallback, if it
+// has one.
+type Registry struct {
+	backends []*backend
+	routes   map[string][]string // topic to backend names
+	// Logger, if set, records each delivery, failure and filtered-out
+	// backend.
+	Logger *slog.Logger
+	// QuietHours, if set, reports whether a time falls in quiet hours,
+	// when each backend's quiet mode applies.
+	QuietHours func(time.Time) bool
+
+	mu sync.Mutex // guards the backends' health
+}
+
+type backend struct {
+	name   string
+	n      Notifier
+	topics []string // nil for all
+	quiet  string   // a Quiet mode; "" is QuietMute
+
+	fallbackName string
+	fallback     Notifier
+	health       Health
+}
+
+// Health is how a backend's deliveries have gone since it was added.
+type Health struct {
+	Name string
+	// Sent and Failed count deliveries; FellBack counts the failed ones
+	// Fallback delivered instead.
+	Sent, Failed, FellBack int
+	Fallback               string
+	LastSent               time.Time
+	LastFailed             time.Time
+	LastError              string
+}
+
+// OK reports whether the backend's last delivery, if any, went through.
+func (h Health) OK() bool {
+	return !h.LastFailed.After(h.LastSent)
+}
+
+// Add enables n under name. topics limits it to those (see Topics);
+// empty means every message.
+func (r *Registry) Add(name string, n Notifier, topics []string) error {
+	for _, t := range topics {
+		if !slices.Contains(Topics, t) {
+			return fmt.Errorf("notify %s: unknown event %q (want one of %s)", name, t, strings.Join(Topics, ", "))
+		}
+	}
+	r.backends = append(r.backends, &backend{name: name, n: n, topics: slices.Clone(topics), health: Health{Name: name}})
+	return nil
+}
+
+// Fallback makes n, called fallbackName, deliver the messages the
+// backend name fails to, e.g. the terminal bell where there is no
+// desktop session.
+func (r *Registry) Fallback(name, fallbackName string, n Notifier) error {
+	for _, b := range r.backends {
+		if b.name == name {
+			b.fallbackName, b.fallback = fallbackName, n
+			b.health.Fallback = fallbackName
+			return nil
+		}
+	}
+	return fmt.Errorf("notify: no backend %q to fall back from", name)
+}
+
+// Wrap hands the notifier of each backend in names to wrap and sends
+// through what it returns instead, e.g. a Batcher. Names that were not
+// added are skipped. Wrap before the first message: it doesn't guard
+// against one being sent meanwhile.
+func (r *Registry) Wrap(names []string, wrap func(name string, n Notifier) Notifier) {
+	for _, b := range r.backends {
+		if slices.Contains(names, b.name) {
+			b.n = wrap(b.name, b.n)
+		}
+	}
+}
+
+// Route sends the messages of topic to the backends names only, in
+// place of their own filters; no names drops them. A message goes by
+// the route of its most specific topic, e.g. long_break_start over
+// work_end, and by the filters without one. Names that were not added,
+// such as a disabled backend, are skipped.
+func (r *Registry) Route(topic string, names []string) error {
+	if !slices.Contains(Topics, topic) {
+		return fmt.Errorf("notify route: unknown event %q (want one of %s)", topic, strings.Join(Topics, ", "))
+	}
+	if r.routes == nil {
+		r.routes = make(map[string][]string)
+	}
+	r.routes[topic] = slices.Clone(names)
+	return nil
+}
+
+// route returns the backends routed the most specific of topics, and
+// whether any has a route.
+func (r *Registry) route(topics []string) (topic string, names []string, ok bool) {
+	for i := len(topics) - 1; i >= 0; i-- {
+		if names, ok := r.routes[topics[i]]; ok {
+			return topics[i], names, true
+		}
+	}
+	return "", nil, false
+}
+
+// SetQuiet sets the backend name's quiet mode, QuietMute unless set.
+func (r *Registry) SetQuiet(name, mode string) error {
+	switch mode {
+	case QuietMute, QuietSilent, QuietOff:
+	default:
+		return fmt.Errorf("notify %s: unknown quiet mode %q", name, mode)
+	}
+	for _, b := range r.backends {
+		if b.name == name {
+			b.quiet = mode
+			return nil
+		}
+	}
+	return fmt.Errorf("notify: no backend %q to quiet", name)
+}
+
+// Health returns every backend's health, in the order they were added.
+func (r *Registry) Health() []Health {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := make([]Health, len(r.backends))
+	for i, b := range r.backends {
+		out[i] = b.health
+	}
+	return out
+}
+
+// Names lists the enabled backends in the order they were added.
+func (r *Registry) Names() []string {
+	names := make([]string, len(r.backends))
+	for i, b := range r.backends {
+		names[i] = b.name
+	}
+	return names
+}
+
+func (r *Registry) Notify(title, body string) error {
+	return r.NotifyMessage(Message{Title: title, Body: body})
+}
+
+// NotifyMessage sends msg to the routed or matching backends, minding
+// quiet hours, and joins their errors, each prefixed with the backend's
+// name.
+func (r *Registry) NotifyMessage(msg Message) error {
+	topics := MessageTopics(msg)
+	routed, route, hasRoute := r.route(topics)
+	quiet := r.QuietHours != nil && r.QuietHours(time.Now())
+	var errs []error
+	for _, b := range r.backends {
+		switch {
+		case hasRoute:
+			if !slices.Contains(route, b.name) {
+				r.log(slog.LevelDebug, "notify routed elsewhere", b.name, msg, slog.String("route", routed))
+				continue
+			}
+		case b.topics != nil && !slices.ContainsFunc(topics, func(t string) bool { return slices.Contains(b.topics, t) }):
+			r.log(slog.LevelDebug, "notify filtered out", b.name, msg, slog.Any("topics", topics))
+			continue
+		}
+		m := msg
+		if quiet {
+			switch b.quiet {
+			case QuietOff:
+			case QuietSilent:
+				m.Silent = true
+			default:
+				r.log(slog.LevelDebug, "notify muted for quiet hours", b.name, msg)
+				continue
+			}
+		}
+		if err := r.send(b, m); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", b.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// send delivers msg through b, or its fallback when b fails, and
+// records how it went. The error is nil once the fallback delivered.
+func (r *Registry) send(b *backend, msg Message) error {
+	err := Send(b.n, msg)
+	r.mu.Lock()
+	h := &b.health
+	if err == nil {
+		h.Sent++
+		h.LastSent = time.Now()
+	} else {
+		h.Failed++
+		h.LastFailed = time.Now()
+		h.LastError = err.Error()
+	}
+	r.mu.Unlock()
+	if err == nil {
+		r.log(slog.LevelInfo, "notified", b.name, msg)
+		return nil
+	}
+	if b.fallback == nil {
+		r.log(slog.LevelError, "notify failed", b.name, msg, slog.String("error", err.Error()))
+		return err
+	}
+	if ferr := Send(b.fallback, msg); ferr != nil {
+		r.log(slog.LevelError, "notify failed", b.name, msg, slog.String("error", err.Error()),
+			slog.String("fallback", b.fallbackName), slog.String("fallback_error", ferr.Error()))
+		return fmt.Errorf("%w; %s: %w", err, b.fallbackName, ferr)
+	}
+	r.mu.Lock()
+	h.FellBack++
+	r.mu.Unlock()
+	r.log(slog.LevelWarn, "notify failed, fell back", b.name, msg, slog.String("error", err.Error()),
+		slog.String("fallback", b.fallbackName))
+	return nil
+}
+
+func (r *Registry) log(level slog.Level, text, name string, msg Message, attrs ...slog.Attr) {
+	if r.Logger == nil {
+		return
+	}
+	attrs = append(attrs, slog.String("backend", name), slog.String("title", msg.Title), slog.String("body", msg.Body))
+	r.Logger.LogAttrs(context.Background(), level, text, attrs...)
+}
+
+var (
+	_ Notifier        = (*Registry)(nil)
+	_ MessageNotifier = (*Registry)(nil)
+)
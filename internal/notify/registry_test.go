@@ -0,0 +1,249 @@
+package notify
+
+import (
+	"errors"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type failNotifier struct{}
+
+func (failNotifier) Notify(title, body string) error { return errors.New("boom") }
+
+func TestRegistry_FiltersPerBackend(t *testing.T) {
+	var reg Registry
+	all, workEnd, longBreak := newRecordNotifier(), newRecordNotifier(), newRecordNotifier()
+	for _, b := range []struct {
+		name   string
+		n      Notifier
+		topics []string
+	}{
+		{"all", all, nil},
+		{"work_end", workEnd, []string{"work_end"}},
+		{"long", longBreak, []string{"long_break_start"}},
+	} {
+		if err := reg.Add(b.name, b.n, b.topics); err != nil {
+			t.Fatal(err)
+		}
+	}
+	at := time.Now()
+	send := func(kind core.EventKind, phase core.Phase) {
+		t.Helper()
+		ev := core.Event{Kind: kind, State: core.State{Phase: phase}, At: at}
+		if err := Send(&reg, Message{Title: "GoPomodoro", Body: phase.String(), Event: ev}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send(core.EventAdvance, core.PhaseShortBreak)
+	send(core.EventAdvance, core.PhaseWork)
+	send(core.EventAdvance, core.PhaseLongBreak)
+	send(core.EventWarning, core.PhaseWork)
+	_ = reg.Notify("GoPomodoro", "goal reached")
+
+	if got := len(all.msgs); got != 5 {
+		t.Errorf("unfiltered backend got %d messages, want 5", got)
+	}
+	if got := len(workEnd.msgs); got != 2 {
+		t.Errorf("work_end backend got %d messages, want 2", got)
+	}
+	if got := len(longBreak.msgs); got != 1 || longBreak.msgs[0].Body != "LONG_BREAK" {
+		t.Errorf("long_break_start backend got %v", longBreak.msgs)
+	}
+}
+
+func TestRegistry_Errors(t *testing.T) {
+	var reg Registry
+	if err := reg.Add("bad", failNotifier{}, []string{"lunch"}); err == nil {
+		t.Fatal("unknown event accepted")
+	}
+	_ = reg.Add("desktop", failNotifier{}, nil)
+	err := reg.Notify("GoPomodoro", "hi")
+	if err == nil || !strings.HasPrefix(err.Error(), "desktop: ") {
+		t.Fatalf("got %v, want error naming the backend", err)
+	}
+}
+
+func TestRegistry_Logs(t *testing.T) {
+	var buf strings.Builder
+	reg := Registry{Logger: slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))}
+	_ = reg.Add("desktop", failNotifier{}, nil)
+	_ = reg.Add("webhook", newRecordNotifier(), []string{"overtime"})
+	_ = reg.Notify("GoPomodoro", "hi")
+
+	out := buf.String()
+	if !strings.Contains(out, `msg="notify failed" error=boom backend=desktop`) ||
+		!strings.Contains(out, `msg="notify filtered out" topics=[info] backend=webhook`) {
+		t.Fatalf("log:\n%s", out)
+	}
+}
+
+func TestRegistry_Fallback(t *testing.T) {
+	var buf strings.Builder
+	reg := Registry{Logger: slog.New(slog.NewTextHandler(&buf, nil))}
+	bell := newRecordNotifier()
+	_ = reg.Add("desktop", failNotifier{}, nil)
+	_ = reg.Add("log", newRecordNotifier(), nil)
+	if err := reg.Fallback("desktop", "bell", bell); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.Fallback("sound", "bell", bell); err == nil {
+		t.Fatal("fallback for a missing backend accepted")
+	}
+
+	if err := reg.Notify("GoPomodoro", "hi"); err != nil {
+		t.Fatalf("got %v, want the fallback to cover the failure", err)
+	}
+	if len(bell.msgs) != 1 || bell.msgs[0].Body != "hi" {
+		t.Fatalf("fallback got %v", bell.msgs)
+	}
+	if out := buf.String(); !strings.Contains(out, `level=WARN msg="notify failed, fell back" error=boom fallback=bell backend=desktop`) {
+		t.Fatalf("log:\n%s", out)
+	}
+
+	health := reg.Health()
+	if len(health) != 2 {
+		t.Fatalf("got %d backends, want 2", len(health))
+	}
+	desktop, log := health[0], health[1]
+	if desktop.OK() || desktop.Failed != 1 || desktop.FellBack != 1 || desktop.Fallback != "bell" || desktop.LastError != "boom" {
+		t.Errorf("desktop health %+v", desktop)
+	}
+	if !log.OK() || log.Sent != 1 || log.Failed != 0 {
+		t.Errorf("log health %+v", log)
+	}
+
+	_ = reg.Fallback("desktop", "bell", failNotifier{})
+	if err := reg.Notify("GoPomodoro", "hi"); err == nil || !strings.Contains(err.Error(), "bell: boom") {
+		t.Fatalf("got %v, want both failures", err)
+	}
+}
+
+// silentNotifier records whether each message asked for silence.
+type silentNotifier struct{ silent []bool }
+
+func (n *silentNotifier) Notify(title, body string) error {
+	return n.NotifyMessage(Message{Title: title, Body: body})
+}
+
+func (n *silentNotifier) NotifyMessage(msg Message) error {
+	n.silent = append(n.silent, msg.Silent)
+	return nil
+}
+
+func TestRegistry_QuietHours(t *testing.T) {
+	quiet := true
+	reg := Registry{QuietHours: func(time.Time) bool { return quiet }}
+	muted, silent, off := &silentNotifier{}, &silentNotifier{}, &silentNotifier{}
+	_ = reg.Add("desktop", muted, nil)
+	_ = reg.Add("sound", silent, nil)
+	_ = reg.Add("log", off, nil)
+	if err := reg.SetQuiet("sound", QuietSilent); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SetQuiet("log", QuietOff); err != nil {
+		t.Fatal(err)
+	}
+	if err := reg.SetQuiet("log", "loud"); err == nil {
+		t.Fatal("unknown quiet mode accepted")
+	}
+
+	_ = reg.Notify("GoPomodoro", "at night")
+	quiet = false
+	_ = reg.Notify("GoPomodoro", "by day")
+
+	if !slices.Equal(muted.silent, []bool{false}) {
+		t.Errorf("muted backend got %v, want only the day's message", muted.silent)
+	}
+	if !slices.Equal(silent.silent, []bool{true, false}) {
+		t.Errorf("silent backend got %v, want the night's message silent", silent.silent)
+	}
+	if !slices.Equal(off.silent, []bool{false, false}) {
+		t.Errorf("backend ignoring quiet hours got %v", off.silent)
+	}
+}
+
+func TestRegistry_Routes(t *testing.T) {
+	var reg Registry
+	desktop, sound, webhook := newRecordNotifier(), newRecordNotifier(), newRecordNotifier()
+	_ = reg.Add("desktop", desktop, nil)
+	_ = reg.Add("sound", sound, nil)
+	// a route overrides the backend's own filter
+	_ = reg.Add("webhook", webhook, []string{"overtime"})
+	for topic, names := range map[string][]string{
+		"work_end":         {"desktop", "sound"},
+		"break_end":        {"desktop"},
+		"long_break_start": {"webhook", "speech"}, // speech is off
+		"warning":          nil,
+	} {
+		if err := reg.Route(topic, names); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := reg.Route("lunch", []string{"desktop"}); err == nil {
+		t.Fatal("route for an unknown event accepted")
+	}
+
+	at := time.Now()
+	send := func(kind core.EventKind, phase core.Phase) {
+		t.Helper()
+		ev := core.Event{Kind: kind, State: core.State{Phase: phase}, At: at}
+		if err := Send(&reg, Message{Title: "GoPomodoro", Body: phase.String(), Event: ev}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	send(core.EventAdvance, core.PhaseShortBreak)
+	send(core.EventAdvance, core.PhaseWork)
+	send(core.EventAdvance, core.PhaseLongBreak)
+	send(core.EventWarning, core.PhaseWork)
+	send(core.EventOvertime, core.PhaseWork)
+
+	bodies := func(n *recordNotifier) string {
+		var out []string
+		for _, m := range n.msgs {
+			out = append(out, m.Body)
+		}
+		return strings.Join(out, ",")
+	}
+	if got := bodies(desktop); got != "SHORT_BREAK,WORK,WORK" {
+		t.Errorf("desktop got %s", got)
+	}
+	if got := bodies(sound); got != "SHORT_BREAK,WORK" {
+		t.Errorf("sound got %s", got)
+	}
+	if got := bodies(webhook); got != "LONG_BREAK,WORK" {
+		t.Errorf("webhook got %s", got)
+	}
+}
+
+func TestRegistry_Wrap(t *testing.T) {
+	var reg Registry
+	room, desk := newRecordNotifier(), newRecordNotifier()
+	_ = reg.Add("matrix", room, nil)
+	_ = reg.Add("desktop", desk, nil)
+	var batcher *Batcher
+	reg.Wrap([]string{"matrix", "irc"}, func(name string, n Notifier) Notifier {
+		if name != "matrix" {
+			t.Errorf("wrapped %s", name)
+		}
+		batcher = NewBatcher(n, BatchOptions{Window: time.Hour})
+		return batcher
+	})
+	// a burst of the same message makes one post
+	for range 3 {
+		_ = reg.Notify("GoPomodoro", "break time")
+	}
+	if len(desk.msgs) != 3 {
+		t.Errorf("unwrapped backend got %d messages, want 3", len(desk.msgs))
+	}
+	if err := batcher.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if len(room.msgs) != 1 {
+		t.Errorf("wrapped backend got %v, want one message", room.msgs)
+	}
+}
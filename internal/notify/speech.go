@@ -0,0 +1,154 @@
+package notify
+
+import (
+	"fmt"
+	"os/exec"
+	"slices"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// SpeechOptions configures a Speech notifier.
+type SpeechOptions struct {
+	// Command speaks the text given as its last argument, e.g.
+	// ["espeak-ng", "-v", "en-us"]. Empty uses the platform's speech:
+	// say on macOS, System.Speech on Windows, espeak-ng, espeak or
+	// spd-say elsewhere.
+	Command []string
+	// Voice and Rate (words per minute) tune the platform's speech; 0
+	// keeps its default rate. A Command is used as is.
+	Voice string
+	Rate  int
+	// Messages are Go templates of what to say per topic (see Topics),
+	// over DefaultSpeech. Topics without one say the notification body.
+	Messages map[string]string
+}
+
+// DefaultSpeech are the messages spoken unless SpeechOptions.Messages
+// replaces them.
+var DefaultSpeech = map[string]string{
+	"work_start":        "Back to work{{with .Task}}: {{.}}{{end}}.",
+	"short_break_start": "Break time. Step away for {{.Minutes}} minutes.",
+	"long_break_start":  "Long break. Step away for {{.Minutes}} minutes, well earned.",
+	"overtime":          "Work time is up.",
+}
+
+// SpeechData is what the message templates see.
+type SpeechData struct {
+	Title, Body string
+	Phase       string // e.g. "SHORT_BREAK"
+	Name        string // the phase's name in the cycle
+	Minutes     int    // the phase's length
+	Task        string
+	Done        int // pomodoros done
+}
+
+// Speech reads notifications out loud, one at a time.
+type Speech struct {
+	command  []string
+	voice    string
+	rate     int
+	messages map[string]*template.Template
+
+	mu sync.Mutex // one voice at a time
+}
+
+// NewSpeech checks opts and parses the message templates.
+func NewSpeech(opts SpeechOptions) (*Speech, error) {
+	s := &Speech{command: slices.Clone(opts.Command), voice: opts.Voice, rate: opts.Rate, messages: map[string]*template.Template{}}
+	if s.rate < 0 {
+		return nil, fmt.Errorf("speech: negative rate %d", s.rate)
+	}
+	texts := map[string]string{}
+	for topic, text := range DefaultSpeech {
+		texts[topic] = text
+	}
+	for topic, text := range opts.Messages {
+		if !slices.Contains(Topics, topic) {
+			return nil, fmt.Errorf("speech: unknown event %q (want one of %s)", topic, strings.Join(Topics, ", "))
+		}
+		texts[topic] = text
+	}
+	for topic, text := range texts {
+		t, err := template.New(topic).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("speech %s: %w", topic, err)
+		}
+		s.messages[topic] = t
+	}
+	return s, nil
+}
+
+func (s *Speech) Notify(title, body string) error {
+	return s.NotifyMessage(Message{Title: title, Body: body})
+}
+
+// NotifyMessage speaks msg, unless it is silent.
+func (s *Speech) NotifyMessage(msg Message) error {
+	if msg.Silent {
+		return nil
+	}
+	text, err := s.Text(msg)
+	if err != nil || text == "" {
+		return err
+	}
+	name, args, env, err := s.speaker(text)
+	if err != nil {
+		return err
+	}
+	cmd := exec.Command(name, args...)
+	cmd.Env = env
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		if m := strings.TrimSpace(string(out)); m != "" {
+			return fmt.Errorf("speech: %w: %s", err, m)
+		}
+		return fmt.Errorf("speech: %w", err)
+	}
+	return nil
+}
+
+// Text is what NotifyMessage says for msg: the template of its most
+// specific topic, or its body.
+func (s *Speech) Text(msg Message) (string, error) {
+	topics := MessageTopics(msg)
+	for i := len(topics) - 1; i >= 0; i-- {
+		t, ok := s.messages[topics[i]]
+		if !ok {
+			continue
+		}
+		st := msg.Event.State
+		data := SpeechData{
+			Title:   msg.Title,
+			Body:    msg.Body,
+			Phase:   st.Phase.String(),
+			Name:    st.Name(),
+			Minutes: int(st.Length.Round(time.Minute) / time.Minute),
+			Task:    st.Task.Title,
+			Done:    st.PomodoroDone,
+		}
+		var b strings.Builder
+		if err := t.Execute(&b, data); err != nil {
+			return "", fmt.Errorf("speech %s: %w", topics[i], err)
+		}
+		return strings.TrimSpace(b.String()), nil
+	}
+	return strings.ReplaceAll(msg.Body, "\n", ". "), nil
+}
+
+// speaker is the command saying text, with its environment (nil for
+// the process's).
+func (s *Speech) speaker(text string) (name string, args, env []string, err error) {
+	if len(s.command) > 0 {
+		return s.command[0], append(slices.Clone(s.command[1:]), text), nil, nil
+	}
+	return platformSpeaker(s.voice, s.rate, text)
+}
+
+var (
+	_ Notifier        = (*Speech)(nil)
+	_ MessageNotifier = (*Speech)(nil)
+)
@@ -0,0 +1,15 @@
+package notify
+
+import "strconv"
+
+// platformSpeaker uses say.
+func platformSpeaker(voice string, rate int, text string) (string, []string, []string, error) {
+	var args []string
+	if voice != "" {
+		args = append(args, "-v", voice)
+	}
+	if rate > 0 {
+		args = append(args, "-r", strconv.Itoa(rate))
+	}
+	return "say", append(args, text), nil, nil
+}
@@ -0,0 +1,39 @@
+//go:build !darwin && !windows
+
+package notify
+
+import (
+	"errors"
+	"os/exec"
+	"strconv"
+)
+
+// platformSpeaker uses espeak-ng or espeak, or speech-dispatcher's
+// spd-say, whose rate runs from -100 to 100 around about 180 words per
+// minute.
+func platformSpeaker(voice string, rate int, text string) (string, []string, []string, error) {
+	for _, name := range []string{"espeak-ng", "espeak"} {
+		if _, err := exec.LookPath(name); err != nil {
+			continue
+		}
+		var args []string
+		if voice != "" {
+			args = append(args, "-v", voice)
+		}
+		if rate > 0 {
+			args = append(args, "-s", strconv.Itoa(rate))
+		}
+		return name, append(args, "--", text), nil, nil
+	}
+	if _, err := exec.LookPath("spd-say"); err == nil {
+		args := []string{"-w"}
+		if voice != "" {
+			args = append(args, "-y", voice)
+		}
+		if rate > 0 {
+			args = append(args, "-r", strconv.Itoa(min(max((rate-180)*100/180, -100), 100)))
+		}
+		return "spd-say", append(args, "--", text), nil, nil
+	}
+	return "", nil, nil, errors.New("speech: no espeak-ng, espeak or spd-say found")
+}
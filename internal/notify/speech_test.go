@@ -0,0 +1,76 @@
+package notify
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestSpeech_Text(t *testing.T) {
+	s, err := NewSpeech(SpeechOptions{Messages: map[string]string{
+		"break_start": "Time off, {{.Name}}.",
+		"work_start":  "Focus on {{.Task}} now.",
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now()
+	msg := func(kind core.EventKind, st core.State, body string) Message {
+		return Message{Title: "GoPomodoro", Body: body, Event: core.Event{Kind: kind, State: st, At: at}}
+	}
+	for _, tc := range []struct {
+		msg  Message
+		want string
+	}{
+		// short_break_start, from DefaultSpeech, beats break_start
+		{msg(core.EventAdvance, core.State{Phase: core.PhaseShortBreak, Length: 5 * time.Minute}, "Phase: SHORT_BREAK"),
+			"Break time. Step away for 5 minutes."},
+		{msg(core.EventAdvance, core.State{Phase: core.PhaseWork, Length: 25 * time.Minute, Task: core.Task{Title: "the report"}}, "Phase: WORK"),
+			"Focus on the report now."},
+		{msg(core.EventWarning, core.State{Phase: core.PhaseWork}, "2m left in WORK"), "2m left in WORK"},
+		{Message{Title: "GoPomodoro", Body: "Daily goal reached\n4 pomodoros"}, "Daily goal reached. 4 pomodoros"},
+	} {
+		got, err := s.Text(tc.msg)
+		if err != nil || got != tc.want {
+			t.Errorf("%q: got %q, %v, want %q", tc.msg.Body, got, err, tc.want)
+		}
+	}
+}
+
+func TestSpeech_Errors(t *testing.T) {
+	if _, err := NewSpeech(SpeechOptions{Messages: map[string]string{"lunch": "Eat"}}); err == nil {
+		t.Error("unknown event accepted")
+	}
+	if _, err := NewSpeech(SpeechOptions{Messages: map[string]string{"warning": "{{.Left"}}); err == nil {
+		t.Error("broken template accepted")
+	}
+	s, _ := NewSpeech(SpeechOptions{Messages: map[string]string{"info": "{{.Nope}}"}})
+	if _, err := s.Text(Message{Body: "hi"}); err == nil {
+		t.Error("unknown field accepted")
+	}
+}
+
+func TestSpeech_Command(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("needs sh")
+	}
+	out := filepath.Join(t.TempDir(), "said")
+	s, _ := NewSpeech(SpeechOptions{Command: []string{"sh", "-c", `printf %s "$1" > ` + out, "speak"}})
+	if err := s.Notify("GoPomodoro", "hello"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(out); string(got) != "hello" {
+		t.Errorf("said %q", got)
+	}
+	os.Remove(out)
+	if err := s.NotifyMessage(Message{Body: "shh", Silent: true}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(out); err == nil {
+		t.Error("a silent message was spoken")
+	}
+}
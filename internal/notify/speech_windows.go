@@ -0,0 +1,24 @@
+package notify
+
+import (
+	"os"
+	"strconv"
+)
+
+// speechScript speaks $env:GOPOMODORO_SPEECH; the text and voice go
+// through the environment so they need no quoting.
+const speechScript = `Add-Type -AssemblyName System.Speech
+$s = New-Object System.Speech.Synthesis.SpeechSynthesizer
+if ($env:GOPOMODORO_VOICE) { $s.SelectVoice($env:GOPOMODORO_VOICE) }
+if ($env:GOPOMODORO_RATE) { $s.Rate = [int]$env:GOPOMODORO_RATE }
+$s.Speak($env:GOPOMODORO_SPEECH)`
+
+// platformSpeaker uses System.Speech through PowerShell. Its rate runs
+// from -10 to 10 around a default of about 180 words per minute.
+func platformSpeaker(voice string, rate int, text string) (string, []string, []string, error) {
+	env := append(os.Environ(), "GOPOMODORO_SPEECH="+text, "GOPOMODORO_VOICE="+voice)
+	if rate > 0 {
+		env = append(env, "GOPOMODORO_RATE="+strconv.Itoa(min(max((rate-180)/20, -10), 10)))
+	}
+	return "powershell.exe", []string{"-NoProfile", "-NonInteractive", "-Command", speechScript}, env, nil
+}
@@ -0,0 +1,155 @@
+package notify
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Template is a title and body template for one topic; either may be
+// empty to keep the built-in text.
+type Template struct {
+	Title, Body string
+}
+
+// Templates rewrite messages from Go templates per topic (see Topics).
+// A nil *Templates leaves them alone.
+type Templates struct {
+	title, body map[string]*template.Template
+	// lengths gives the configured phase lengths, for NextDuration.
+	lengths func(core.Phase) time.Duration
+}
+
+// TemplateData is what the templates see.
+type TemplateData struct {
+	Title, Body string // the built-in text
+	Event       string // e.g. "advance"
+	Phase       string // e.g. "SHORT_BREAK", the phase starting for an advance
+	Name        string // the phase's name in the cycle
+	Length      Duration
+	Remaining   Duration
+	// Next and NextDuration are the phase starting with the event, or
+	// for other events the one after the current phase (after work,
+	// the short break's length stands in for either break).
+	Next         string
+	NextDuration Duration
+	Task         string
+	Tags         []string
+	Timer        string // the named timer, "" for the default one
+	Cycle        int    // pomodoros done in this run
+	Done         int    // pomodoros done today
+	Goal         int    // the daily goal, 0 for none
+}
+
+// Duration prints as "5m" or "1h30m" in templates rather than "5m0s".
+type Duration time.Duration
+
+func (d Duration) String() string {
+	s := time.Duration(d).Round(time.Second).String()
+	if strings.HasSuffix(s, "m0s") {
+		s = strings.TrimSuffix(s, "0s")
+	}
+	if strings.HasSuffix(s, "h0m") {
+		s = strings.TrimSuffix(s, "0m")
+	}
+	return s
+}
+
+// Minutes is d in whole minutes, e.g. for "{{.Length.Minutes}} minutes".
+func (d Duration) Minutes() int {
+	return int(time.Duration(d).Round(time.Minute) / time.Minute)
+}
+
+// NewTemplates parses the templates by topic. lengths, if non-nil,
+// gives the configured phase lengths, e.g. PomodoroEngine.PhaseDuration.
+func NewTemplates(byTopic map[string]Template, lengths func(core.Phase) time.Duration) (*Templates, error) {
+	t := &Templates{title: map[string]*template.Template{}, body: map[string]*template.Template{}, lengths: lengths}
+	for topic, tt := range byTopic {
+		if !slices.Contains(Topics, topic) {
+			return nil, fmt.Errorf("notify template: unknown event %q (want one of %s)", topic, strings.Join(Topics, ", "))
+		}
+		for _, part := range []struct {
+			text string
+			into map[string]*template.Template
+			name string
+		}{{tt.Title, t.title, "title"}, {tt.Body, t.body, "body"}} {
+			if part.text == "" {
+				continue
+			}
+			parsed, err := template.New(topic).Option("missingkey=error").Parse(part.text)
+			if err != nil {
+				return nil, fmt.Errorf("notify template %s %s: %w", topic, part.name, err)
+			}
+			part.into[topic] = parsed
+		}
+	}
+	return t, nil
+}
+
+// Apply returns msg with the title and body of its most specific
+// topic's templates, e.g. short_break_start over break_start. done and
+// goal are today's pomodoros and the daily goal. On an error msg is
+// returned as it was, with the error.
+func (t *Templates) Apply(msg Message, done, goal int) (Message, error) {
+	if t == nil {
+		return msg, nil
+	}
+	data := t.data(msg, done, goal)
+	out := msg
+	topics := MessageTopics(msg)
+	for _, part := range []struct {
+		by   map[string]*template.Template
+		into *string
+	}{{t.title, &out.Title}, {t.body, &out.Body}} {
+		for i := len(topics) - 1; i >= 0; i-- {
+			tmpl, ok := part.by[topics[i]]
+			if !ok {
+				continue
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, data); err != nil {
+				return msg, fmt.Errorf("notify template %s: %w", topics[i], err)
+			}
+			*part.into = b.String()
+			break
+		}
+	}
+	return out, nil
+}
+
+func (t *Templates) data(msg Message, done, goal int) TemplateData {
+	ev := msg.Event
+	st := ev.State
+	d := TemplateData{
+		Title:     msg.Title,
+		Body:      msg.Body,
+		Phase:     st.Phase.String(),
+		Name:      st.Name(),
+		Length:    Duration(st.Length),
+		Remaining: Duration(ev.Remaining),
+		Task:      st.Task.Title,
+		Tags:      st.Tags,
+		Timer:     ev.Timer,
+		Cycle:     st.PomodoroDone,
+		Done:      done,
+		Goal:      goal,
+	}
+	if ev.At.IsZero() {
+		return d
+	}
+	d.Event = ev.Kind.String()
+	switch {
+	case ev.Kind == core.EventStart || ev.Kind == core.EventAdvance || ev.Kind == core.EventSkip:
+		d.Next, d.NextDuration = st.Name(), Duration(st.Length)
+	case st.Idle() || t.lengths == nil:
+	case st.Phase == core.PhaseWork:
+		d.Next, d.NextDuration = core.PhaseShortBreak.String(), Duration(t.lengths(core.PhaseShortBreak))
+	default:
+		d.Next, d.NextDuration = core.PhaseWork.String(), Duration(t.lengths(core.PhaseWork))
+	}
+	return d
+}
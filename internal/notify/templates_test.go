@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestTemplates_Apply(t *testing.T) {
+	lengths := func(ph core.Phase) time.Duration {
+		if ph == core.PhaseWork {
+			return 25 * time.Minute
+		}
+		return 5 * time.Minute
+	}
+	tmpl, err := NewTemplates(map[string]Template{
+		"work_end":  {Body: "{{.Task}} done — {{.Done}} 🍅 today, take {{.NextDuration}} off"},
+		"break_end": {Title: "Break over", Body: "Next up: {{.Next}}"},
+		"warning":   {Body: "{{.Remaining}} left, then {{.NextDuration.Minutes}} minutes of {{.Next}}"},
+	}, lengths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	at := time.Now()
+	task := core.Task{Title: "Write report"}
+	msg := func(kind core.EventKind, st core.State, remaining time.Duration) Message {
+		return Message{Title: "GoPomodoro", Body: "built-in", Event: core.Event{Kind: kind, State: st, Remaining: remaining, At: at}}
+	}
+	for _, tc := range []struct {
+		msg         Message
+		title, body string
+	}{
+		{msg(core.EventAdvance, core.State{Phase: core.PhaseLongBreak, Length: 15 * time.Minute, Task: task}, 0),
+			"GoPomodoro", "Write report done — 4 🍅 today, take 15m off"},
+		{msg(core.EventAdvance, core.State{Phase: core.PhaseWork, Length: 25 * time.Minute}, 0),
+			"Break over", "Next up: WORK"},
+		{msg(core.EventWarning, core.State{Phase: core.PhaseWork, Length: 25 * time.Minute}, 2*time.Minute),
+			"GoPomodoro", "2m left, then 5 minutes of SHORT_BREAK"},
+		{msg(core.EventOvertime, core.State{Phase: core.PhaseWork}, 0), "GoPomodoro", "built-in"},
+	} {
+		got, err := tmpl.Apply(tc.msg, 4, 8)
+		if err != nil || got.Title != tc.title || got.Body != tc.body {
+			t.Errorf("%s: got %q / %q, %v, want %q / %q", tc.msg.Event.Kind, got.Title, got.Body, err, tc.title, tc.body)
+		}
+	}
+
+	var none *Templates
+	if got, err := none.Apply(Message{Body: "as is"}, 0, 0); err != nil || got.Body != "as is" {
+		t.Errorf("nil templates changed the message: %q, %v", got.Body, err)
+	}
+}
+
+func TestTemplates_Errors(t *testing.T) {
+	if _, err := NewTemplates(map[string]Template{"lunch": {Body: "Eat"}}, nil); err == nil {
+		t.Error("unknown event accepted")
+	}
+	if _, err := NewTemplates(map[string]Template{"info": {Title: "{{.Title"}}, nil); err == nil {
+		t.Error("broken template accepted")
+	}
+	tmpl, _ := NewTemplates(map[string]Template{"info": {Body: "{{.Nope}}"}}, nil)
+	got, err := tmpl.Apply(Message{Body: "goal reached"}, 0, 0)
+	if err == nil || got.Body != "goal reached" {
+		t.Errorf("got %q, %v, want the built-in text and an error", got.Body, err)
+	}
+}
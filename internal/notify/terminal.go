@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Terminal notification modes for Bell.
+const (
+	ModeBell   = "bell"   // BEL only
+	ModeOSC9   = "osc9"   // iTerm2, WezTerm, kitty, Windows Terminal, ghostty
+	ModeOSC777 = "osc777" // foot, urxvt, kitty, VTE terminals with the patch
+)
+
+// Bell notifies through the terminal on W: a plain bell, or an OSC 9 or
+// OSC 777 escape that the terminal turns into a desktop notification.
+// It needs no desktop session, so it reaches users over SSH.
+type Bell struct {
+	W    io.Writer
+	Mode string // one of the Mode constants; default ModeBell
+	// Tmux wraps escapes in tmux's passthrough so they reach the outer
+	// terminal (tmux needs allow-passthrough on).
+	Tmux bool
+}
+
+func (b Bell) Notify(title, body string) error {
+	var seq string
+	switch b.Mode {
+	case ModeOSC9:
+		seq = "\x1b]9;" + oscText(title+": "+body) + "\a"
+	case ModeOSC777:
+		seq = "\x1b]777;notify;" + oscText(strings.ReplaceAll(title, ";", ",")) + ";" + oscText(body) + "\a"
+	case ModeBell, "":
+		seq = "\a"
+	default:
+		return fmt.Errorf("bell: unknown mode %q", b.Mode)
+	}
+	if b.Tmux && seq != "\a" {
+		seq = "\x1bPtmux;" + strings.ReplaceAll(seq, "\x1b", "\x1b\x1b") + "\x1b\\"
+	}
+	_, err := io.WriteString(b.W, seq)
+	return err
+}
+
+// NotifyMessage is Notify, except that a silent msg doesn't ring a
+// plain bell.
+func (b Bell) NotifyMessage(msg Message) error {
+	if msg.Silent && (b.Mode == ModeBell || b.Mode == "") {
+		return nil
+	}
+	return b.Notify(msg.Title, msg.Body)
+}
+
+// oscText drops control characters, which would end the escape early,
+// and joins lines.
+func oscText(s string) string {
+	s = strings.ReplaceAll(s, "\n", " · ")
+	return strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || (r >= 0x80 && r < 0xa0) {
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// TerminalMode picks the richest mode the terminal described by getenv
+// is known to support, falling back to ModeBell.
+func TerminalMode(getenv func(string) string) string {
+	switch getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "ghostty":
+		return ModeOSC9
+	}
+	if getenv("WT_SESSION") != "" || getenv("KITTY_WINDOW_ID") != "" {
+		return ModeOSC9
+	}
+	term := getenv("TERM")
+	switch {
+	case strings.HasPrefix(term, "foot"), strings.HasPrefix(term, "rxvt"):
+		return ModeOSC777
+	case term == "xterm-kitty", term == "xterm-ghostty":
+		return ModeOSC9
+	}
+	return ModeBell
+}
+
+var (
+	_ Notifier        = Bell{}
+	_ MessageNotifier = Bell{}
+)
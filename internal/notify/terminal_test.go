@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBell_Modes(t *testing.T) {
+	for _, tc := range []struct {
+		bell Bell
+		want string
+	}{
+		{Bell{}, "\a"},
+		{Bell{Mode: ModeOSC9}, "\x1b]9;GoPomodoro: Phase: WORK · next\a"},
+		{Bell{Mode: ModeOSC777}, "\x1b]777;notify;GoPomodoro;Phase: WORK · next\a"},
+		{Bell{Mode: ModeOSC9, Tmux: true}, "\x1bPtmux;\x1b\x1b]9;GoPomodoro: Phase: WORK · next\a\x1b\\"},
+	} {
+		var out strings.Builder
+		tc.bell.W = &out
+		if err := tc.bell.Notify("GoPomodoro", "Phase: WORK\nnext\x1b"); err != nil {
+			t.Fatal(err)
+		}
+		if out.String() != tc.want {
+			t.Errorf("%s: got %q, want %q", tc.bell.Mode, out.String(), tc.want)
+		}
+	}
+	if err := (Bell{W: &strings.Builder{}, Mode: "osc99"}).Notify("a", "b"); err == nil {
+		t.Error("unknown mode accepted")
+	}
+}
+
+func TestTerminalMode(t *testing.T) {
+	for env, want := range map[string]string{
+		"TERM=foot":               ModeOSC777,
+		"TERM_PROGRAM=WezTerm":    ModeOSC9,
+		"KITTY_WINDOW_ID=1":       ModeOSC9,
+		"TERM=xterm-256color":     ModeBell,
+		"TERM=rxvt-unicode-256co": ModeOSC777,
+	} {
+		k, v, _ := strings.Cut(env, "=")
+		getenv := func(key string) string {
+			if key == k {
+				return v
+			}
+			return ""
+		}
+		if got := TerminalMode(getenv); got != want {
+			t.Errorf("%s: got %s, want %s", env, got, want)
+		}
+	}
+}
+
+func TestBell_Silent(t *testing.T) {
+	var buf strings.Builder
+	msg := Message{Title: "GoPomodoro", Body: "hi", Silent: true}
+	if err := (Bell{W: &buf}).NotifyMessage(msg); err != nil || buf.Len() != 0 {
+		t.Fatalf("silent plain bell wrote %q, %v", buf.String(), err)
+	}
+	if err := (Bell{W: &buf, Mode: ModeOSC9}).NotifyMessage(msg); err != nil || buf.String() != "\x1b]9;GoPomodoro: hi\a" {
+		t.Fatalf("silent OSC 9 wrote %q, %v", buf.String(), err)
+	}
+}
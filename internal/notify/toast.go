@@ -0,0 +1,94 @@
+package notify
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// toast is the Windows toast notification schema, as much of it as we use.
+type toast struct {
+	XMLName        xml.Name      `xml:"toast"`
+	ActivationType string        `xml:"activationType,attr,omitempty"`
+	Scenario       string        `xml:"scenario,attr,omitempty"`
+	Binding        toastBinding  `xml:"visual>binding"`
+	Actions        *toastActions `xml:"actions,omitempty"`
+	Audio          *toastAudio   `xml:"audio,omitempty"`
+}
+
+type toastAudio struct {
+	Silent bool `xml:"silent,attr"`
+}
+
+type toastActions struct {
+	Action []toastClick `xml:"action"`
+}
+
+type toastBinding struct {
+	Template string    `xml:"template,attr"`
+	Text     []string  `xml:"text"`
+	Progress *toastBar `xml:"progress,omitempty"`
+}
+
+type toastBar struct {
+	Title  string `xml:"title,attr,omitempty"`
+	Value  string `xml:"value,attr"`
+	Label  string `xml:"valueStringOverride,attr,omitempty"`
+	Status string `xml:"status,attr"`
+}
+
+type toastClick struct {
+	Content        string `xml:"content,attr"`
+	Arguments      string `xml:"arguments,attr"`
+	ActivationType string `xml:"activationType,attr"`
+}
+
+// toastXML renders msg as a ToastGeneric toast: a progress bar for the
+// phase its event belongs to and a button per action, whose arguments
+// are the action ID.
+func toastXML(msg Message) (string, error) {
+	t := toast{Binding: toastBinding{
+		Template: "ToastGeneric",
+		Text:     []string{msg.Title, msg.Body},
+		Progress: toastProgress(msg.Event),
+	}}
+	if len(msg.Actions) > 0 {
+		t.Actions = &toastActions{}
+		for _, a := range msg.Actions {
+			t.Actions.Action = append(t.Actions.Action, toastClick{Content: a.Label, Arguments: a.ID, ActivationType: "foreground"})
+		}
+		t.ActivationType = "foreground"
+		if msg.Event.Kind == core.EventOvertime {
+			// stays up until answered, like an alarm
+			t.Scenario = "reminder"
+		}
+	}
+	if msg.Silent {
+		t.Audio = &toastAudio{Silent: true}
+	}
+	out, err := xml.Marshal(t)
+	return string(out), err
+}
+
+// toastProgress is how far into its phase ev happened, or nil for events
+// outside a running phase.
+func toastProgress(ev core.Event) *toastBar {
+	st := ev.State
+	if ev.At.IsZero() || st.Idle() || st.Length <= 0 || ev.Kind == core.EventStop || ev.Kind == core.EventAbandon {
+		return nil
+	}
+	done := 1 - float64(ev.Remaining)/float64(st.Length)
+	done = min(max(done, 0), 1)
+	label := fmt.Sprintf("%s left", ev.Remaining.Round(time.Second))
+	if st.Overtime {
+		done, label = 1, "overtime"
+	}
+	return &toastBar{
+		Title:  st.Name(),
+		Value:  fmt.Sprintf("%.3f", done),
+		Label:  label,
+		Status: fmt.Sprintf("%d done", st.PomodoroDone),
+	}
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestToastXML(t *testing.T) {
+	start := time.Date(2026, 1, 2, 9, 0, 0, 0, time.UTC)
+	msg := Message{
+		Title: "GoPomodoro",
+		Body:  "5m left in <WORK>",
+		Event: core.Event{
+			Kind:      core.EventWarning,
+			State:     core.State{Phase: core.PhaseWork, StartedAt: start, Length: 25 * time.Minute, PomodoroDone: 2},
+			Remaining: 5 * time.Minute,
+			At:        start.Add(20 * time.Minute),
+		},
+		Actions: []Action{{ID: "skip", Label: "Skip"}},
+	}
+	got, err := toastXML(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`<toast activationType="foreground">`,
+		`<binding template="ToastGeneric"><text>GoPomodoro</text><text>5m left in &lt;WORK&gt;</text>`,
+		`<progress title="WORK" value="0.800" valueStringOverride="5m0s left" status="2 done"></progress>`,
+		`<actions><action content="Skip" arguments="skip" activationType="foreground"></action></actions>`,
+	} {
+		if !strings.Contains(got, want) {
+			t.Errorf("missing %s in\n%s", want, got)
+		}
+	}
+
+	plain, err := toastXML(Message{Title: "GoPomodoro", Body: "Stopped", Event: core.Event{Kind: core.EventStop}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(plain, "progress") || strings.Contains(plain, "actions") {
+		t.Errorf("unexpected progress or actions: %s", plain)
+	}
+}
@@ -0,0 +1,158 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// WebhookOptions configures a Webhook.
+type WebhookOptions struct {
+	URLs    []string
+	Headers map[string]string
+	Timeout time.Duration // per attempt; default 5s
+	Retries int           // extra attempts after a failure
+	Backoff time.Duration // wait before the first retry, doubled after each; default 1s
+	Client  *http.Client
+}
+
+// Webhook POSTs a JSON payload to each configured URL, e.g. for Home
+// Assistant, IFTTT or a custom server.
+type Webhook struct {
+	opts WebhookOptions
+}
+
+// WebhookPayload is the JSON body sent for every notification.
+type WebhookPayload struct {
+	Title        string    `json:"title"`
+	Body         string    `json:"body"`
+	Event        string    `json:"event,omitempty"`
+	Phase        string    `json:"phase,omitempty"`
+	Name         string    `json:"name,omitempty"`
+	PomodoroDone int       `json:"pomodoro_done"`
+	EndsAt       time.Time `json:"ends_at,omitzero"`
+	SentAt       time.Time `json:"sent_at"`
+	// Silent is set during quiet hours, for receivers that make sound.
+	Silent bool `json:"silent,omitempty"`
+}
+
+// NewWebhook creates a Webhook notifier.
+func NewWebhook(opts WebhookOptions) *Webhook {
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5 * time.Second
+	}
+	if opts.Backoff <= 0 {
+		opts.Backoff = time.Second
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &Webhook{opts: opts}
+}
+
+func (w *Webhook) Notify(title, body string) error {
+	return w.NotifyMessage(Message{Title: title, Body: body})
+}
+
+func (w *Webhook) NotifyMessage(msg Message) error {
+	p := WebhookPayload{
+		Title:  msg.Title,
+		Body:   msg.Body,
+		SentAt: time.Now(),
+		Silent: msg.Silent,
+	}
+	if !msg.Event.At.IsZero() {
+		st := msg.Event.State
+		p.Event = msg.Event.Kind.String()
+		p.Phase = st.Phase.String()
+		p.Name = st.Name()
+		p.PomodoroDone = st.PomodoroDone
+		p.EndsAt = st.EndsAt
+	}
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, url := range w.opts.URLs {
+		if err := w.post(url, data); err != nil {
+			errs = append(errs, fmt.Errorf("webhook %s: %w", url, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post delivers data to url, retrying network errors and 5xx/429
+// responses with exponential backoff.
+func (w *Webhook) post(url string, data []byte) error {
+	return retrying(w.opts.Retries, w.opts.Backoff, func() (bool, error) {
+		return w.attempt(url, data)
+	})
+}
+
+func (w *Webhook) attempt(url string, data []byte) (retry bool, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), w.opts.Timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "GoPomodoro")
+	for k, v := range w.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	return deliver(w.opts.Client, req, nil)
+}
+
+// retrying calls attempt until it succeeds, fails for good or retries
+// more times failed, waiting backoff before the first retry and twice
+// as long before each next one.
+func retrying(retries int, backoff time.Duration, attempt func() (retry bool, err error)) error {
+	wait := backoff
+	var err error
+	for n := 0; n <= retries; n++ {
+		if n > 0 {
+			time.Sleep(wait)
+			wait *= 2
+		}
+		var retry bool
+		retry, err = attempt()
+		if err == nil || !retry {
+			return err
+		}
+	}
+	return err
+}
+
+// deliver sends req, reporting whether a failure is worth retrying:
+// network errors, 429 and 5xx are. reason, if set, pulls the service's
+// explanation out of an error response's body for the error.
+func deliver(client *http.Client, req *http.Request, reason func([]byte) string) (retry bool, err error) {
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 300 {
+		return false, nil
+	}
+	err = fmt.Errorf("status %s", resp.Status)
+	if reason != nil {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		if why := reason(body); why != "" {
+			err = fmt.Errorf("status %s: %s", resp.Status, why)
+		}
+	}
+	return resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500, err
+}
+
+var (
+	_ Notifier        = (*Webhook)(nil)
+	_ MessageNotifier = (*Webhook)(nil)
+)
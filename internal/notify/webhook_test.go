@@ -0,0 +1,66 @@
+package notify
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestWebhook_RetriesThenDelivers(t *testing.T) {
+	var calls atomic.Int32
+	var got WebhookPayload
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if r.Header.Get("X-Token") != "secret" {
+			t.Errorf("missing header")
+		}
+		_ = json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	wh := NewWebhook(WebhookOptions{
+		URLs:    []string{srv.URL},
+		Headers: map[string]string{"X-Token": "secret"},
+		Retries: 2,
+		Backoff: time.Millisecond,
+	})
+	ev := core.Event{
+		Kind:  core.EventAdvance,
+		State: core.State{Phase: core.PhaseShortBreak, PomodoroDone: 1},
+		At:    time.Now(),
+	}
+	if err := Send(wh, Message{Title: "GoPomodoro", Body: "Phase: SHORT_BREAK", Event: ev}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls.Load())
+	}
+	if got.Event != "advance" || got.Phase != "SHORT_BREAK" || got.PomodoroDone != 1 || got.Body != "Phase: SHORT_BREAK" {
+		t.Fatalf("unexpected payload %+v", got)
+	}
+}
+
+func TestWebhook_NoRetryOnClientError(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	wh := NewWebhook(WebhookOptions{URLs: []string{srv.URL}, Retries: 3, Backoff: time.Millisecond})
+	if err := wh.Notify("t", "b"); err == nil {
+		t.Fatal("expected error for 400")
+	}
+	if calls.Load() != 1 {
+		t.Fatalf("4xx should not be retried, got %d attempts", calls.Load())
+	}
+}
@@ -0,0 +1,146 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// GitOptions configures a git backend.
+type GitOptions struct {
+	Repo string // the repository to clone, e.g. git@github.com:ana/pomodoros.git
+	Dir  string // the local clone, made on the first pull
+	Git  string // the git binary, default git
+}
+
+// Git keeps each machine's object as a file in a git repository,
+// committing and pushing it with the git command, so the user's own
+// credentials and SSH keys apply.
+type Git struct {
+	opts GitOptions
+}
+
+// NewGit returns a git backend.
+func NewGit(opts GitOptions) (*Git, error) {
+	if opts.Repo == "" {
+		return nil, errors.New("git: no repository")
+	}
+	if opts.Dir == "" {
+		return nil, errors.New("git: no directory to clone into")
+	}
+	if opts.Git == "" {
+		opts.Git = "git"
+	}
+	return &Git{opts: opts}, nil
+}
+
+// Pull clones the repository or brings the clone up to date, then reads
+// each machine's file.
+func (g *Git) Pull(ctx context.Context) (map[string][]byte, error) {
+	if _, err := os.Stat(filepath.Join(g.opts.Dir, ".git")); errors.Is(err, fs.ErrNotExist) {
+		if err := os.MkdirAll(filepath.Dir(g.opts.Dir), 0o755); err != nil {
+			return nil, err
+		}
+		if _, err := g.git(ctx, "", "clone", "--quiet", g.opts.Repo, g.opts.Dir); err != nil {
+			return nil, err
+		}
+	} else if err != nil {
+		return nil, err
+	} else if err := g.update(ctx, g.identity(ctx)); err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(g.opts.Dir)
+	if err != nil {
+		return nil, err
+	}
+	objects := map[string][]byte{}
+	for _, e := range entries {
+		machine := objectMachine(e.Name())
+		if machine == "" || !e.Type().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(g.opts.Dir, e.Name()))
+		if err != nil {
+			return nil, err
+		}
+		objects[machine] = data
+	}
+	return objects, nil
+}
+
+// Push commits the machine's file and pushes it. If another machine
+// pushed first, the commit is rebased onto theirs, which can't conflict
+// as each machine writes only its own file, and pushed again.
+func (g *Git) Push(ctx context.Context, machine string, data []byte) error {
+	name := machine + objectExt
+	if err := os.WriteFile(filepath.Join(g.opts.Dir, name), data, 0o644); err != nil {
+		return err
+	}
+	if _, err := g.git(ctx, g.opts.Dir, "add", "--", name); err != nil {
+		return err
+	}
+	env := g.identity(ctx)
+	if out, _ := g.git(ctx, g.opts.Dir, "status", "--porcelain", "--", name); len(bytes.TrimSpace(out)) > 0 {
+		if _, err := g.run(ctx, g.opts.Dir, env, "commit", "--quiet", "-m", "Sync "+machine, "--", name); err != nil {
+			return err
+		}
+	}
+	if _, err := g.git(ctx, g.opts.Dir, "push", "--quiet", "origin", "HEAD"); err == nil {
+		return nil
+	}
+	if err := g.update(ctx, env); err != nil {
+		return err
+	}
+	_, err := g.git(ctx, g.opts.Dir, "push", "--quiet", "origin", "HEAD")
+	return err
+}
+
+// update rebases the clone's commits onto the repository's. An empty
+// repository has nothing to pull.
+func (g *Git) update(ctx context.Context, env []string) error {
+	if out, _ := g.git(ctx, g.opts.Dir, "ls-remote", "--heads", "origin"); len(bytes.TrimSpace(out)) == 0 {
+		return nil
+	}
+	_, err := g.run(ctx, g.opts.Dir, env, "pull", "--quiet", "--rebase", "origin", "HEAD")
+	return err
+}
+
+// identity returns the environment making commits as GoPomodoro when
+// git doesn't know who the user is; otherwise commits are theirs.
+func (g *Git) identity(ctx context.Context) []string {
+	if who, _ := g.git(ctx, g.opts.Dir, "config", "user.email"); len(bytes.TrimSpace(who)) > 0 {
+		return nil
+	}
+	return []string{
+		"GIT_AUTHOR_NAME=GoPomodoro", "GIT_AUTHOR_EMAIL=gopomodoro@localhost",
+		"GIT_COMMITTER_NAME=GoPomodoro", "GIT_COMMITTER_EMAIL=gopomodoro@localhost",
+	}
+}
+
+// git runs a git command in dir, failing with what it printed.
+func (g *Git) git(ctx context.Context, dir string, args ...string) ([]byte, error) {
+	return g.run(ctx, dir, nil, args...)
+}
+
+func (g *Git) run(ctx context.Context, dir string, env []string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, g.opts.Git, args...)
+	cmd.Dir = dir
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		if why := strings.TrimSpace(stderr.String()); why != "" {
+			return out, fmt.Errorf("git %s: %s", args[0], why)
+		}
+		return out, fmt.Errorf("git %s: %w", args[0], err)
+	}
+	return out, nil
+}
@@ -0,0 +1,126 @@
+// Package remote syncs the history between machines through a store
+// they all reach: an S3 bucket, a WebDAV folder or a git repository.
+// Each machine keeps its records in an object of its own, so two never
+// write the same one, and merges everyone's into its history: the
+// latest version of each session wins and deletions carry over.
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// Backend is a remote store of one object per machine.
+type Backend interface {
+	// Pull returns every machine's object by machine name.
+	Pull(ctx context.Context) (map[string][]byte, error)
+	// Push replaces the machine's object with data.
+	Push(ctx context.Context, machine string, data []byte) error
+}
+
+// objectExt ends the name of every machine's object, e.g. laptop.jsonl.
+const objectExt = ".jsonl"
+
+var machineName = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Machine returns name made fit to name an object: characters other
+// than letters, digits, dots, dashes and underscores become dashes.
+func Machine(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r < 128 && (r == '.' || r == '_' || r == '-' ||
+			'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9'):
+			b.WriteRune(r)
+		default:
+			b.WriteByte('-')
+		}
+	}
+	return strings.Trim(b.String(), ".-")
+}
+
+// Result is what a sync did.
+type Result struct {
+	history.MergeResult
+	Machines []string // whose objects were merged, this one included
+}
+
+// Sync merges every machine's records on the backend into store, then
+// pushes the merged records as machine's own object. Sync is idempotent
+// and its order between machines doesn't matter.
+func Sync(ctx context.Context, store *history.Store, b Backend, machine string) (Result, error) {
+	var res Result
+	if !machineName.MatchString(machine) {
+		return res, fmt.Errorf("sync: bad machine name %q", machine)
+	}
+	local, err := store.Records()
+	if err != nil {
+		return res, err
+	}
+	objects, err := b.Pull(ctx)
+	if err != nil {
+		return res, fmt.Errorf("sync: %w", err)
+	}
+	sets := [][]history.Record{local}
+	var mine []history.Record
+	for _, name := range slices.Sorted(maps.Keys(objects)) {
+		records, err := store.ReadRecords(bytes.NewReader(objects[name]))
+		if err != nil {
+			return res, fmt.Errorf("sync: %s: %w", name, err)
+		}
+		sets = append(sets, records)
+		if name == machine {
+			mine = records
+		} else {
+			res.Machines = append(res.Machines, name)
+		}
+	}
+	res.Machines = append(res.Machines, machine)
+	slices.Sort(res.Machines)
+	merged := history.MergeRecords(sets...)
+	if res.MergeResult, err = store.Merge(merged); err != nil {
+		return res, err
+	}
+
+	if _, ok := objects[machine]; ok && sameRecords(mine, merged) {
+		return res, nil
+	}
+	var buf bytes.Buffer
+	if err := store.WriteRecords(&buf, merged); err != nil {
+		return res, err
+	}
+	if err := b.Push(ctx, machine, buf.Bytes()); err != nil {
+		return res, fmt.Errorf("sync: %w", err)
+	}
+	return res, nil
+}
+
+// sameRecords reports whether a and b hold the same records; encrypted
+// objects differ each time they are written, so they are compared
+// decrypted.
+func sameRecords(a, b []history.Record) bool {
+	ja, err := json.Marshal(a)
+	if err != nil {
+		return false
+	}
+	jb, err := json.Marshal(b)
+	return err == nil && bytes.Equal(ja, jb)
+}
+
+// objectMachine returns the machine an object named name belongs to,
+// or "" for a name that isn't a machine's object.
+func objectMachine(name string) string {
+	m, ok := strings.CutSuffix(name, objectExt)
+	if !ok || !machineName.MatchString(m) {
+		return ""
+	}
+	return m
+}
@@ -0,0 +1,223 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+// S3Options configures an S3 backend. Any S3-compatible store does,
+// e.g. MinIO, Cloudflare R2 or Backblaze B2.
+type S3Options struct {
+	// Endpoint is the service's URL, by default
+	// https://s3.<region>.amazonaws.com. Buckets are addressed by path.
+	Endpoint  string
+	Region    string // default us-east-1
+	Bucket    string
+	Prefix    string // folder of the objects in the bucket, e.g. gopomodoro/
+	AccessKey string
+	SecretKey string
+	Client    *http.Client // defaults to http.DefaultClient
+	Now       func() time.Time
+}
+
+// S3 keeps each machine's object in a bucket, signing its requests
+// with AWS Signature Version 4.
+type S3 struct {
+	opts S3Options
+}
+
+// NewS3 returns an S3 backend.
+func NewS3(opts S3Options) (*S3, error) {
+	if opts.Bucket == "" {
+		return nil, errors.New("s3: no bucket")
+	}
+	if opts.AccessKey == "" || opts.SecretKey == "" {
+		return nil, errors.New("s3: access key and secret key are required")
+	}
+	if opts.Region == "" {
+		opts.Region = "us-east-1"
+	}
+	if opts.Endpoint == "" {
+		opts.Endpoint = "https://s3." + opts.Region + ".amazonaws.com"
+	}
+	if _, err := url.Parse(opts.Endpoint); err != nil {
+		return nil, fmt.Errorf("s3: endpoint: %w", err)
+	}
+	opts.Endpoint = strings.TrimSuffix(opts.Endpoint, "/")
+	if p := strings.Trim(opts.Prefix, "/"); p != "" {
+		opts.Prefix = p + "/"
+	} else {
+		opts.Prefix = ""
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	return &S3{opts: opts}, nil
+}
+
+// Pull lists the objects under the prefix and fetches each machine's.
+func (s *S3) Pull(ctx context.Context) (map[string][]byte, error) {
+	var keys []string
+	token := ""
+	for {
+		q := url.Values{"list-type": {"2"}, "prefix": {s.opts.Prefix}}
+		if token != "" {
+			q.Set("continuation-token", token)
+		}
+		body, err := s.do(ctx, http.MethodGet, "", q, nil)
+		if err != nil {
+			return nil, err
+		}
+		var list struct {
+			Contents []struct {
+				Key string
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		if err := xml.Unmarshal(body, &list); err != nil {
+			return nil, fmt.Errorf("s3: listing: %w", err)
+		}
+		for _, c := range list.Contents {
+			keys = append(keys, c.Key)
+		}
+		if !list.IsTruncated || list.NextContinuationToken == "" {
+			break
+		}
+		token = list.NextContinuationToken
+	}
+
+	objects := map[string][]byte{}
+	for _, key := range keys {
+		rest, ok := strings.CutPrefix(key, s.opts.Prefix)
+		machine := objectMachine(rest)
+		if !ok || machine == "" || strings.Contains(rest, "/") {
+			continue
+		}
+		data, err := s.do(ctx, http.MethodGet, key, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		objects[machine] = data
+	}
+	return objects, nil
+}
+
+// Push uploads the machine's object.
+func (s *S3) Push(ctx context.Context, machine string, data []byte) error {
+	_, err := s.do(ctx, http.MethodPut, s.opts.Prefix+machine+objectExt, nil, data)
+	return err
+}
+
+// do sends a signed request for key in the bucket, or for the bucket
+// itself when key is empty, returning the response body.
+func (s *S3) do(ctx context.Context, method, key string, query url.Values, data []byte) ([]byte, error) {
+	u := s.opts.Endpoint + "/" + s.opts.Bucket
+	if key != "" {
+		u += "/" + key
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+	req.URL.RawQuery = canonicalQuery(query)
+	if data != nil {
+		req.Header.Set("Content-Type", "application/x-ndjson")
+	}
+	sum := sha256.Sum256(data)
+	signV4(req, hex.EncodeToString(sum[:]), s.opts.AccessKey, s.opts.SecretKey, s.opts.Region, s.opts.Now())
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("s3: %w", err)
+	}
+	if resp.StatusCode >= 300 {
+		var e struct{ Code, Message string }
+		if xml.Unmarshal(body, &e) == nil && e.Code != "" {
+			return nil, fmt.Errorf("s3: %s %s: %s: %s", method, path.Join(s.opts.Bucket, key), e.Code, e.Message)
+		}
+		return nil, fmt.Errorf("s3: %s %s: status %s", method, path.Join(s.opts.Bucket, key), resp.Status)
+	}
+	return body, nil
+}
+
+// signV4 signs req for the s3 service with AWS Signature Version 4,
+// given the hex SHA-256 of its body. The host, any range and content
+// type and every x-amz- header are signed.
+func signV4(req *http.Request, payloadHash, accessKey, secretKey, region string, now time.Time) {
+	now = now.UTC()
+	stamp := now.Format("20060102T150405Z")
+	day := now.Format("20060102")
+	req.Header.Set("X-Amz-Date", stamp)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "range" || name == "content-type" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	var canonHeaders strings.Builder
+	for _, name := range names {
+		canonHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signed := strings.Join(names, ";")
+
+	canonical := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		canonicalQuery(req.URL.Query()),
+		canonHeaders.String(),
+		signed,
+		payloadHash,
+	}, "\n")
+	scope := day + "/" + region + "/s3/aws4_request"
+	hash := sha256.Sum256([]byte(canonical))
+	toSign := "AWS4-HMAC-SHA256\n" + stamp + "\n" + scope + "\n" + hex.EncodeToString(hash[:])
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), day)
+	for _, part := range []string{region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, part)
+	}
+	sig := hex.EncodeToString(hmacSHA256(key, toSign))
+	req.Header.Set("Authorization", "AWS4-HMAC-SHA256 Credential="+accessKey+"/"+scope+
+		", SignedHeaders="+signed+", Signature="+sig)
+}
+
+// canonicalQuery encodes q sorted by key, with spaces as %20 as
+// Signature Version 4 wants.
+func canonicalQuery(q url.Values) string {
+	return strings.ReplaceAll(q.Encode(), "+", "%20")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
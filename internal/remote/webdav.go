@@ -0,0 +1,161 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// WebDAVOptions configures a WebDAV backend, e.g. a Nextcloud folder.
+type WebDAVOptions struct {
+	URL      string // the folder, e.g. https://cloud.example.com/remote.php/dav/files/ana/gopomodoro/
+	Username string
+	Password string
+	Client   *http.Client // defaults to http.DefaultClient
+}
+
+// WebDAV keeps each machine's object as a file in a folder, creating
+// the folder on the first push.
+type WebDAV struct {
+	opts WebDAVOptions
+	base *url.URL
+}
+
+// NewWebDAV returns a WebDAV backend.
+func NewWebDAV(opts WebDAVOptions) (*WebDAV, error) {
+	if opts.URL == "" {
+		return nil, errors.New("webdav: no url")
+	}
+	base, err := url.Parse(opts.URL)
+	if err != nil || base.Host == "" {
+		return nil, fmt.Errorf("webdav: bad url %q", opts.URL)
+	}
+	if !strings.HasSuffix(base.Path, "/") {
+		base.Path += "/"
+	}
+	if opts.Client == nil {
+		opts.Client = http.DefaultClient
+	}
+	return &WebDAV{opts: opts, base: base}, nil
+}
+
+// Pull lists the folder and fetches each machine's file. A folder not
+// yet created holds none.
+func (w *WebDAV) Pull(ctx context.Context) (map[string][]byte, error) {
+	body := `<?xml version="1.0" encoding="utf-8"?><propfind xmlns="DAV:"><prop><resourcetype/></prop></propfind>`
+	resp, err := w.do(ctx, "PROPFIND", w.base.String(), []byte(body), map[string]string{
+		"Depth":        "1",
+		"Content-Type": "application/xml",
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	objects := map[string][]byte{}
+	if resp.StatusCode == http.StatusNotFound {
+		return objects, nil
+	}
+	if err := davStatus(resp, "PROPFIND", w.base.Path); err != nil {
+		return nil, err
+	}
+	var ms struct {
+		Responses []struct {
+			Href string `xml:"href"`
+		} `xml:"response"`
+	}
+	if err := xml.NewDecoder(resp.Body).Decode(&ms); err != nil {
+		return nil, fmt.Errorf("webdav: listing: %w", err)
+	}
+	for _, r := range ms.Responses {
+		href, err := url.Parse(r.Href)
+		if err != nil {
+			continue
+		}
+		machine := objectMachine(path.Base(href.Path))
+		if machine == "" || path.Dir(href.Path)+"/" != w.base.Path {
+			continue
+		}
+		data, err := w.get(ctx, machine+objectExt)
+		if err != nil {
+			return nil, err
+		}
+		objects[machine] = data
+	}
+	return objects, nil
+}
+
+// Push uploads the machine's file, creating the folder if it's missing.
+func (w *WebDAV) Push(ctx context.Context, machine string, data []byte) error {
+	target := w.base.JoinPath(machine + objectExt).String()
+	put := func() (*http.Response, error) {
+		return w.do(ctx, http.MethodPut, target, data, map[string]string{"Content-Type": "application/x-ndjson"})
+	}
+	resp, err := put()
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusConflict {
+		mk, err := w.do(ctx, "MKCOL", w.base.String(), nil, nil)
+		if err != nil {
+			return err
+		}
+		mk.Body.Close()
+		if resp, err = put(); err != nil {
+			return err
+		}
+		resp.Body.Close()
+	}
+	return davStatus(resp, "PUT", path.Join(w.base.Path, machine+objectExt))
+}
+
+func (w *WebDAV) get(ctx context.Context, name string) ([]byte, error) {
+	resp, err := w.do(ctx, http.MethodGet, w.base.JoinPath(name).String(), nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if err := davStatus(resp, "GET", path.Join(w.base.Path, name)); err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("webdav: %w", err)
+	}
+	return data, nil
+}
+
+// do sends an authenticated request; the caller closes the body.
+func (w *WebDAV) do(ctx context.Context, method, target string, data []byte, headers map[string]string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, target, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("webdav: %w", err)
+	}
+	if w.opts.Username != "" || w.opts.Password != "" {
+		req.SetBasicAuth(w.opts.Username, w.opts.Password)
+	}
+	req.Header.Set("User-Agent", "GoPomodoro")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := w.opts.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("webdav: %w", err)
+	}
+	return resp, nil
+}
+
+// davStatus turns a failed response into an error.
+func davStatus(resp *http.Response, method, name string) error {
+	if resp.StatusCode < 300 {
+		return nil
+	}
+	return fmt.Errorf("webdav: %s %s: status %s", method, name, resp.Status)
+}
@@ -0,0 +1,207 @@
+// Package rpc serves the gRPC control API defined in
+// api/gopomodoro/v1 for a PomodoroEngine.
+package rpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	pb "github.com/ezchuang/GoPomodoro/api/gopomodoro/v1"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Server implements pb.PomodoroServiceServer for a single engine.
+type Server struct {
+	pb.UnimplementedPomodoroServiceServer
+	engine *core.PomodoroEngine
+}
+
+// New creates a Server for engine.
+func New(engine *core.PomodoroEngine) *Server {
+	return &Server{engine: engine}
+}
+
+// NewGRPC returns a grpc.Server with the service registered, taking
+// only calls carrying token as "authorization: Bearer <token>", like the
+// HTTP API's control calls.
+func NewGRPC(engine *core.PomodoroEngine, token string, opts ...grpc.ServerOption) *grpc.Server {
+	a := auth(token)
+	opts = append(opts, grpc.ChainUnaryInterceptor(a.unary), grpc.ChainStreamInterceptor(a.stream))
+	gs := grpc.NewServer(opts...)
+	pb.RegisterPomodoroServiceServer(gs, New(engine))
+	return gs
+}
+
+func (s *Server) GetState(context.Context, *pb.GetStateRequest) (*pb.GetStateResponse, error) {
+	return &pb.GetStateResponse{State: s.snapshot()}, nil
+}
+
+// Start reports a start refused by quitting time or the StartCheck as
+// FailedPrecondition.
+func (s *Server) Start(context.Context, *pb.StartRequest) (*pb.StartResponse, error) {
+	if err := s.engine.Start(); err != nil {
+		return nil, refused(err)
+	}
+	return &pb.StartResponse{State: s.snapshot()}, nil
+}
+
+func (s *Server) Pause(_ context.Context, req *pb.PauseRequest) (*pb.PauseResponse, error) {
+	reason, ok := pauseReasons[req.GetReason()]
+	if !ok || reason == core.ReasonIdle {
+		return nil, status.Errorf(codes.InvalidArgument, "unknown pause reason %v", req.GetReason())
+	}
+	err := s.engine.PauseWithReason(reason)
+	if errors.Is(err, core.ErrAlreadyPaused) {
+		err = s.engine.SetPauseReason(reason)
+	}
+	if err != nil {
+		return nil, refused(err)
+	}
+	return &pb.PauseResponse{State: s.snapshot()}, nil
+}
+
+func (s *Server) Resume(context.Context, *pb.ResumeRequest) (*pb.ResumeResponse, error) {
+	if err := s.engine.Resume(); err != nil {
+		return nil, refused(err)
+	}
+	return &pb.ResumeResponse{State: s.snapshot()}, nil
+}
+
+func (s *Server) Stop(context.Context, *pb.StopRequest) (*pb.StopResponse, error) {
+	s.engine.Stop()
+	return &pb.StopResponse{State: s.snapshot()}, nil
+}
+
+func (s *Server) Skip(context.Context, *pb.SkipRequest) (*pb.SkipResponse, error) {
+	if err := s.engine.Skip(); err != nil {
+		return nil, refused(err)
+	}
+	return &pb.SkipResponse{State: s.snapshot()}, nil
+}
+
+// refused reports a control call the engine refused in its state.
+func refused(err error) error {
+	return status.Error(codes.FailedPrecondition, err.Error())
+}
+
+// Watch streams like the WebSocket API: the state first, then events,
+// plus ticks if asked for. A slow client misses events rather than
+// holding up the engine.
+func (s *Server) Watch(req *pb.WatchRequest, stream grpc.ServerStreamingServer[pb.WatchResponse]) error {
+	events := make(chan core.Event, 32)
+	defer s.engine.Subscribe(func(ev core.Event) {
+		select {
+		case events <- ev:
+		default:
+		}
+	})()
+
+	var tick <-chan time.Time
+	if d := req.GetTickInterval().AsDuration(); d > 0 {
+		t := time.NewTicker(d)
+		defer t.Stop()
+		tick = t.C
+	}
+	if err := stream.Send(&pb.WatchResponse{Kind: pb.EventKind_EVENT_KIND_STATE, At: timestamppb.Now(), State: s.snapshot()}); err != nil {
+		return err
+	}
+	for {
+		var msg *pb.WatchResponse
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case ev := <-events:
+			msg = &pb.WatchResponse{
+				Kind:  eventKinds[ev.Kind],
+				At:    timestamppb.New(ev.At),
+				State: encodeState(ev.State, ev.Remaining),
+			}
+			if ev.Refusal != nil {
+				msg.Message = ev.Refusal.Error()
+			}
+			if ev.Kind == core.EventUndo {
+				msg.Message = ev.Undone
+			}
+		case now := <-tick:
+			if st := s.engine.State(); st.Idle() || st.Paused {
+				continue
+			}
+			msg = &pb.WatchResponse{Kind: pb.EventKind_EVENT_KIND_TICK, At: timestamppb.New(now), State: s.snapshot()}
+		}
+		if err := stream.Send(msg); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *Server) snapshot() *pb.State {
+	return encodeState(s.engine.State(), s.engine.Remaining())
+}
+
+func encodeState(st core.State, remain time.Duration) *pb.State {
+	out := &pb.State{
+		Phase:         phases[st.Phase],
+		Name:          st.Name(),
+		Remaining:     durationpb.New(remain),
+		PomodoroDone:  int32(st.PomodoroDone),
+		Paused:        st.Paused,
+		Interruptions: int32(st.Interruptions),
+		Overtime:      st.Overtime,
+		Idle:          st.Idle(),
+		Task:          st.Task.Title,
+	}
+	for r, cr := range pauseReasons {
+		if cr == st.PauseReason {
+			out.PauseReason = r
+		}
+	}
+	if !st.Idle() {
+		out.StartedAt = timestamppb.New(st.StartedAt)
+		out.EndsAt = timestamppb.New(st.EndsAt)
+	}
+	return out
+}
+
+// phases maps the engine's phases; PhaseIdle is PHASE_UNSPECIFIED, with
+// State.idle set.
+var phases = map[core.Phase]pb.Phase{
+	core.PhaseWork:       pb.Phase_PHASE_WORK,
+	core.PhaseShortBreak: pb.Phase_PHASE_SHORT_BREAK,
+	core.PhaseLongBreak:  pb.Phase_PHASE_LONG_BREAK,
+}
+
+var pauseReasons = map[pb.PauseReason]core.PauseReason{
+	pb.PauseReason_PAUSE_REASON_UNSPECIFIED:  core.ReasonNone,
+	pb.PauseReason_PAUSE_REASON_MEETING:      core.ReasonMeeting,
+	pb.PauseReason_PAUSE_REASON_BIO:          core.ReasonBio,
+	pb.PauseReason_PAUSE_REASON_INTERRUPTION: core.ReasonInterruption,
+	pb.PauseReason_PAUSE_REASON_OTHER:        core.ReasonOther,
+	pb.PauseReason_PAUSE_REASON_IDLE:         core.ReasonIdle,
+}
+
+var eventKinds = map[core.EventKind]pb.EventKind{
+	core.EventStart:          pb.EventKind_EVENT_KIND_START,
+	core.EventAdvance:        pb.EventKind_EVENT_KIND_ADVANCE,
+	core.EventPause:          pb.EventKind_EVENT_KIND_PAUSE,
+	core.EventResume:         pb.EventKind_EVENT_KIND_RESUME,
+	core.EventStop:           pb.EventKind_EVENT_KIND_STOP,
+	core.EventUpdate:         pb.EventKind_EVENT_KIND_UPDATE,
+	core.EventInterrupt:      pb.EventKind_EVENT_KIND_INTERRUPT,
+	core.EventOvertime:       pb.EventKind_EVENT_KIND_OVERTIME,
+	core.EventSkip:           pb.EventKind_EVENT_KIND_SKIP,
+	core.EventWarning:        pb.EventKind_EVENT_KIND_WARNING,
+	core.EventExtend:         pb.EventKind_EVENT_KIND_EXTEND,
+	core.EventAbandon:        pb.EventKind_EVENT_KIND_ABANDON,
+	core.EventTask:           pb.EventKind_EVENT_KIND_TASK,
+	core.EventRefused:        pb.EventKind_EVENT_KIND_REFUSED,
+	core.EventConfigReloaded: pb.EventKind_EVENT_KIND_CONFIG_RELOADED,
+	core.EventUndo:           pb.EventKind_EVENT_KIND_UNDO,
+	core.EventTick:           pb.EventKind_EVENT_KIND_TICK,
+}
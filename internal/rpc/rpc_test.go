@@ -0,0 +1,199 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	pb "github.com/ezchuang/GoPomodoro/api/gopomodoro/v1"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+const testToken = "secret"
+
+// bearer sends a token with every call, over an insecure connection too.
+type bearer string
+
+func (b bearer) GetRequestMetadata(context.Context, ...string) (map[string]string, error) {
+	return map[string]string{"authorization": "Bearer " + string(b)}, nil
+}
+
+func (bearer) RequireTransportSecurity() bool { return false }
+
+func newTestClient(t *testing.T, eng *core.PomodoroEngine) pb.PomodoroServiceClient {
+	return newTokenClient(t, eng, testToken)
+}
+
+// newTokenClient is a client sending token to a server taking
+// testToken.
+func newTokenClient(t *testing.T, eng *core.PomodoroEngine, token string) pb.PomodoroServiceClient {
+	t.Helper()
+	lis := bufconn.Listen(1 << 16)
+	gs := NewGRPC(eng, testToken)
+	go func() { _ = gs.Serve(lis) }()
+	t.Cleanup(gs.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithPerRPCCredentials(bearer(token)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewPomodoroServiceClient(conn)
+}
+
+func TestControl(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	c := newTestClient(t, eng)
+	ctx := context.Background()
+
+	st, err := c.GetState(ctx, &pb.GetStateRequest{})
+	if err != nil || !st.State.Idle || st.State.StartedAt != nil {
+		t.Fatalf("idle state %v, %v", st, err)
+	}
+	start, err := c.Start(ctx, &pb.StartRequest{})
+	if err != nil || start.State.Phase != pb.Phase_PHASE_WORK || start.State.Idle || start.State.Remaining.AsDuration() <= 0 {
+		t.Fatalf("start %v, %v", start, err)
+	}
+	pause, err := c.Pause(ctx, &pb.PauseRequest{Reason: pb.PauseReason_PAUSE_REASON_MEETING})
+	if err != nil || !pause.State.Paused || pause.State.PauseReason != pb.PauseReason_PAUSE_REASON_MEETING {
+		t.Fatalf("pause %v, %v", pause, err)
+	}
+	if _, err := c.Pause(ctx, &pb.PauseRequest{Reason: pb.PauseReason_PAUSE_REASON_IDLE}); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("idle reason: %v", err)
+	}
+	if res, err := c.Resume(ctx, &pb.ResumeRequest{}); err != nil || res.State.Paused {
+		t.Fatalf("resume %v, %v", res, err)
+	}
+	if skip, err := c.Skip(ctx, &pb.SkipRequest{}); err != nil || skip.State.Phase != pb.Phase_PHASE_SHORT_BREAK {
+		t.Fatalf("skip %v, %v", skip, err)
+	}
+	if stop, err := c.Stop(ctx, &pb.StopRequest{}); err != nil || !stop.State.Idle {
+		t.Fatalf("stop %v, %v", stop, err)
+	}
+}
+
+func TestUnauthenticated(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	c := newTokenClient(t, eng, "guess")
+	ctx := context.Background()
+
+	if _, err := c.Start(ctx, &pb.StartRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("start without the token: %v", err)
+	}
+	if !eng.State().Idle() {
+		t.Fatal("unauthenticated start changed the engine")
+	}
+	stream, err := c.Watch(ctx, &pb.WatchRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("watch without the token: %v", err)
+	}
+}
+
+func TestStart_Refused(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	eng.SetStartCheck(func(core.State) (time.Duration, error) { return 0, errors.New("meeting at 10:00") })
+	c := newTestClient(t, eng)
+
+	_, err := c.Start(context.Background(), &pb.StartRequest{})
+	if s, _ := status.FromError(err); s.Code() != codes.FailedPrecondition || s.Message() != "meeting at 10:00" {
+		t.Fatalf("err = %v", err)
+	}
+	if !eng.State().Idle() {
+		t.Fatal("refused start changed the engine")
+	}
+}
+
+func TestWatch(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	c := newTestClient(t, eng)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := c.Watch(ctx, &pb.WatchRequest{TickInterval: durationpb.New(10 * time.Millisecond)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, err := stream.Recv()
+	if err != nil || first.Kind != pb.EventKind_EVENT_KIND_STATE || !first.State.Idle {
+		t.Fatalf("first message %v, %v", first, err)
+	}
+	eng.Start()
+	var kinds []pb.EventKind
+	for len(kinds) < 2 {
+		msg, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(kinds) == 0 || kinds[len(kinds)-1] != msg.Kind {
+			kinds = append(kinds, msg.Kind)
+		}
+	}
+	if kinds[0] != pb.EventKind_EVENT_KIND_START || kinds[1] != pb.EventKind_EVENT_KIND_TICK {
+		t.Fatalf("kinds %v, want start then ticks", kinds)
+	}
+}
+
+func TestStart_QuittingTime(t *testing.T) {
+	// quitting at the start of the day, it's always past it
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4, QuittingTime: time.Nanosecond})
+	c := newTestClient(t, eng)
+
+	_, err := c.Start(context.Background(), &pb.StartRequest{})
+	if s, _ := status.FromError(err); s.Code() != codes.FailedPrecondition || !strings.Contains(s.Message(), "quitting time") {
+		t.Fatalf("err = %v", err)
+	}
+}
+
+func TestWatch_Undo(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	c := newTestClient(t, eng)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	stream, err := c.Watch(ctx, &pb.WatchRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+	eng.Start()
+	eng.Skip()
+	if err := eng.Undo(); err != nil {
+		t.Fatal(err)
+	}
+	for {
+		msg, err := stream.Recv()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if msg.Kind == pb.EventKind_EVENT_KIND_UNSPECIFIED {
+			t.Fatalf("unmapped event %v", msg)
+		}
+		if msg.Kind == pb.EventKind_EVENT_KIND_UNDO {
+			if msg.Message != "skip" || msg.State.Phase != pb.Phase_PHASE_WORK {
+				t.Fatalf("undo message %v", msg)
+			}
+			return
+		}
+	}
+}
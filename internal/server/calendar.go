@@ -0,0 +1,42 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// ServeCalendar adds GET /calendar.ics, an iCalendar feed of the
+// completed sessions list returns. ?days=N limits it to the last N days
+// and ?breaks=0 leaves breaks out.
+func (s *Server) ServeCalendar(list func() ([]history.Session, error)) {
+	s.mux.HandleFunc("GET /calendar.ics", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		var since time.Time
+		if d := q.Get("days"); d != "" {
+			n, err := strconv.Atoi(d)
+			if err != nil || n <= 0 {
+				http.Error(w, "days must be a positive number", http.StatusBadRequest)
+				return
+			}
+			since = time.Now().AddDate(0, 0, -n)
+		}
+		breaks := q.Get("breaks") != "0"
+		sessions, err := list()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		kept := sessions[:0:0]
+		for _, ss := range sessions {
+			if ss.Start.Before(since) || !breaks && ss.Phase != "WORK" {
+				continue
+			}
+			kept = append(kept, ss)
+		}
+		w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+		_ = history.WriteICS(w, kept)
+	})
+}
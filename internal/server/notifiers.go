@@ -0,0 +1,43 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+)
+
+// NotifierJSON is the wire form of a notification backend's health.
+type NotifierJSON struct {
+	Name       string    `json:"name"`
+	OK         bool      `json:"ok"`
+	Sent       int       `json:"sent"`
+	Failed     int       `json:"failed"`
+	Fallback   string    `json:"fallback,omitempty"`
+	FellBack   int       `json:"fell_back,omitempty"`
+	LastSent   time.Time `json:"last_sent,omitzero"`
+	LastFailed time.Time `json:"last_failed,omitzero"`
+	LastError  string    `json:"last_error,omitempty"`
+}
+
+// ServeNotifiers adds GET /notifiers, the health of the notification
+// backends as health reports it.
+func (s *Server) ServeNotifiers(health func() []notify.Health) {
+	s.mux.HandleFunc("GET /notifiers", func(w http.ResponseWriter, r *http.Request) {
+		list := []NotifierJSON{}
+		for _, h := range health() {
+			list = append(list, NotifierJSON{
+				Name:       h.Name,
+				OK:         h.OK(),
+				Sent:       h.Sent,
+				Failed:     h.Failed,
+				Fallback:   h.Fallback,
+				FellBack:   h.FellBack,
+				LastSent:   h.LastSent,
+				LastFailed: h.LastFailed,
+				LastError:  h.LastError,
+			})
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+}
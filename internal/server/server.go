@@ -0,0 +1,284 @@
+// Package server exposes a PomodoroEngine over HTTP: a small JSON control
+// API plus a WebSocket stream of engine events for dashboards and overlays.
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// DefaultAddr is where the daemon listens unless told otherwise.
+const DefaultAddr = "127.0.0.1:7767"
+
+// StateJSON is the wire form of an engine snapshot.
+type StateJSON struct {
+	Phase        string    `json:"phase"`
+	Name         string    `json:"name"`
+	StartedAt    time.Time `json:"started_at,omitzero"`
+	EndsAt       time.Time `json:"ends_at,omitzero"`
+	Remaining    float64   `json:"remaining_seconds"`
+	Elapsed      float64   `json:"elapsed_seconds"`
+	PomodoroDone int       `json:"pomodoro_done"`
+	Paused       bool      `json:"paused"`
+	PauseReason  string    `json:"pause_reason,omitempty"`
+	Interrupts   int       `json:"interruptions"`
+	Overtime     bool      `json:"overtime"`
+	Open         bool      `json:"open"` // flow mode work without a deadline
+	Idle         bool      `json:"idle"`
+	Task         string    `json:"task,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+}
+
+// EventJSON is a single message on the /ws stream.
+type EventJSON struct {
+	Type  string    `json:"type"`
+	At    time.Time `json:"at"`
+	State StateJSON `json:"state"`
+	// Message explains a "refused" start, pause, skip or extension.
+	Message string `json:"message,omitempty"`
+	// Timer names the timer on a /timers/{timer}/ws stream.
+	Timer string `json:"timer,omitempty"`
+	// Finished marks the "stop" ending a run of the engine's
+	// Config.Cycles.
+	Finished bool `json:"finished,omitempty"`
+}
+
+func encodeState(st core.State, remain, elapsed time.Duration) StateJSON {
+	return StateJSON{
+		Phase:        st.Phase.String(),
+		Name:         st.Name(),
+		StartedAt:    st.StartedAt,
+		EndsAt:       st.EndsAt,
+		Remaining:    remain.Seconds(),
+		Elapsed:      elapsed.Seconds(),
+		PomodoroDone: st.PomodoroDone,
+		Paused:       st.Paused,
+		PauseReason:  st.PauseReason.String(),
+		Interrupts:   st.Interruptions,
+		Overtime:     st.Overtime,
+		Open:         st.Open,
+		Idle:         st.Idle(),
+		Task:         st.Task.Title,
+		Tags:         st.Tags,
+	}
+}
+
+// Server serves the HTTP API for an engine, and for more named timers
+// once ServeTimers is called. Anything but GET needs "Authorization:
+// Bearer <Token>", and web pages from origins other than the server's
+// own and localhost are turned away unless listed in Origins.
+type Server struct {
+	engine *core.PomodoroEngine
+	timers *core.Manager
+	mux    *http.ServeMux
+
+	// TickInterval is how often /ws clients receive a "tick" message
+	// while a phase is running.
+	TickInterval time.Duration
+
+	// Token guards the endpoints that change anything; with none they
+	// are all refused.
+	Token string
+	// Origins are further origins whose pages may use the API, e.g.
+	// "https://dash.example.com"; "*" lets in any.
+	Origins []string
+
+	upgrader websocket.Upgrader
+}
+
+// New creates a Server for engine.
+func New(engine *core.PomodoroEngine) *Server {
+	s := &Server{
+		engine:       engine,
+		mux:          http.NewServeMux(),
+		TickInterval: time.Second,
+	}
+	// /ws carries task titles, so pages see it only where they'd see
+	// the rest of the API
+	s.upgrader.CheckOrigin = s.allowOrigin
+	s.routes("")
+	return s
+}
+
+// routes registers the engine endpoints under prefix.
+func (s *Server) routes(prefix string) {
+	s.mux.HandleFunc("GET "+prefix+"/state", s.handleState)
+	s.mux.HandleFunc("POST "+prefix+"/start", s.control((*core.PomodoroEngine).Start))
+	s.mux.HandleFunc("POST "+prefix+"/pause", s.handlePause)
+	s.mux.HandleFunc("POST "+prefix+"/resume", s.control((*core.PomodoroEngine).Resume))
+	s.mux.HandleFunc("POST "+prefix+"/stop", s.control(noError((*core.PomodoroEngine).Stop)))
+	s.mux.HandleFunc("POST "+prefix+"/acknowledge", s.control((*core.PomodoroEngine).Acknowledge))
+	s.mux.HandleFunc("POST "+prefix+"/skip", s.control((*core.PomodoroEngine).Skip))
+	s.mux.HandleFunc("POST "+prefix+"/undo", s.control((*core.PomodoroEngine).Undo))
+	s.mux.HandleFunc("POST "+prefix+"/override", s.control(noError((*core.PomodoroEngine).OverrideQuittingTime)))
+	s.mux.HandleFunc("POST "+prefix+"/interrupt", s.handleInterrupt)
+	s.mux.HandleFunc("POST "+prefix+"/extend", s.handleExtend)
+	s.mux.HandleFunc("POST "+prefix+"/remaining", s.handleRemaining)
+	s.mux.HandleFunc("GET "+prefix+"/ws", s.handleWS)
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.allowOrigin(r) {
+		http.Error(w, "origin not allowed; see [api] origins", http.StatusForbidden)
+		return
+	}
+	if r.Method != http.MethodGet && r.Method != http.MethodHead && !s.authorized(r) {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gopomodoro"`)
+		http.Error(w, "unknown token; see api.token", http.StatusUnauthorized)
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// engineFor is the engine r addresses: the {timer} of a /timers/ path,
+// or the server's own. It replies 404 and returns nil for an unknown
+// timer.
+func (s *Server) engineFor(w http.ResponseWriter, r *http.Request) *core.PomodoroEngine {
+	name := r.PathValue("timer")
+	if name == "" {
+		return s.engine
+	}
+	e := s.timers.Get(name)
+	if e == nil {
+		http.Error(w, "no timer "+name, http.StatusNotFound)
+	}
+	return e
+}
+
+func snapshot(e *core.PomodoroEngine) StateJSON {
+	return encodeState(e.State(), e.Remaining(), e.Elapsed())
+}
+
+func (s *Server) handleState(w http.ResponseWriter, r *http.Request) {
+	e := s.engineFor(w, r)
+	if e == nil {
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot(e))
+}
+
+// handlePause pauses with an optional ?reason= category.
+func (s *Server) handlePause(w http.ResponseWriter, r *http.Request) {
+	e := s.engineFor(w, r)
+	if e == nil {
+		return
+	}
+	reason := core.ReasonNone
+	if name := r.URL.Query().Get("reason"); name != "" {
+		if reason = core.ParsePauseReason(name); reason == core.ReasonNone {
+			http.Error(w, "unknown pause reason "+name, http.StatusBadRequest)
+			return
+		}
+	}
+	err := e.PauseWithReason(reason)
+	if errors.Is(err, core.ErrAlreadyPaused) {
+		err = e.SetPauseReason(reason)
+	}
+	reply(w, e, err)
+}
+
+// handleInterrupt logs an interruption: ?kind=internal|external&note=...
+func (s *Server) handleInterrupt(w http.ResponseWriter, r *http.Request) {
+	e := s.engineFor(w, r)
+	if e == nil {
+		return
+	}
+	q := r.URL.Query()
+	kind := core.InterruptInternal
+	if name := q.Get("kind"); name != "" {
+		var ok bool
+		if kind, ok = core.ParseInterruptionKind(name); !ok {
+			http.Error(w, "unknown interruption kind "+name, http.StatusBadRequest)
+			return
+		}
+	}
+	reply(w, e, e.Interrupt(kind, q.Get("note")))
+}
+
+// handleExtend changes the current phase by ?by=5m (negative shortens).
+func (s *Server) handleExtend(w http.ResponseWriter, r *http.Request) {
+	e := s.engineFor(w, r)
+	if e == nil {
+		return
+	}
+	by, err := time.ParseDuration(r.URL.Query().Get("by"))
+	if err != nil {
+		http.Error(w, "by: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	reply(w, e, e.Extend(by))
+}
+
+// handleRemaining reshapes the current phase to end after ?left=10m
+// or at ?until=2025-05-01T09:30:00Z.
+func (s *Server) handleRemaining(w http.ResponseWriter, r *http.Request) {
+	e := s.engineFor(w, r)
+	if e == nil {
+		return
+	}
+	q := r.URL.Query()
+	var err error
+	switch {
+	case q.Has("left"):
+		left, perr := time.ParseDuration(q.Get("left"))
+		if perr != nil {
+			http.Error(w, "left: "+perr.Error(), http.StatusBadRequest)
+			return
+		}
+		err = e.SetRemaining(left)
+	case q.Has("until"):
+		until, perr := time.Parse(time.RFC3339, q.Get("until"))
+		if perr != nil {
+			http.Error(w, "until: "+perr.Error(), http.StatusBadRequest)
+			return
+		}
+		err = e.SetDeadline(until)
+	default:
+		http.Error(w, "left or until is required", http.StatusBadRequest)
+		return
+	}
+	reply(w, e, err)
+}
+
+// control wraps an engine action and replies with the resulting state.
+func (s *Server) control(action func(*core.PomodoroEngine) error) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		e := s.engineFor(w, r)
+		if e == nil {
+			return
+		}
+		reply(w, e, action(e))
+	}
+}
+
+// noError adapts an action that is never refused for control.
+func noError(action func(*core.PomodoroEngine)) func(*core.PomodoroEngine) error {
+	return func(e *core.PomodoroEngine) error {
+		action(e)
+		return nil
+	}
+}
+
+// reply sends e's state after a control call, or 409 Conflict with the
+// reason when the engine refused it.
+func reply(w http.ResponseWriter, e *core.PomodoroEngine, err error) {
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	writeJSON(w, http.StatusOK, snapshot(e))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+var _ http.Handler = (*Server)(nil)
@@ -0,0 +1,280 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+const testToken = "t0ken"
+
+// post calls a control endpoint with the test token.
+func post(t *testing.T, url string) *http.Response {
+	t.Helper()
+	req, _ := http.NewRequest(http.MethodPost, url, nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("POST %s: %v", url, err)
+	}
+	return resp
+}
+
+func newTestServer(t *testing.T) (*core.PomodoroEngine, *httptest.Server) {
+	t.Helper()
+	eng := core.New(core.Config{
+		Work:      time.Minute,
+		ShortBrk:  time.Minute,
+		LongBrk:   time.Minute,
+		LongEvery: 4,
+	})
+	srv := New(eng)
+	srv.TickInterval = 10 * time.Millisecond
+	srv.Token = testToken
+	ts := httptest.NewServer(srv)
+	t.Cleanup(func() {
+		ts.Close()
+		eng.Stop()
+	})
+	return eng, ts
+}
+
+func TestStateAndControl(t *testing.T) {
+	_, ts := newTestServer(t)
+
+	post(t, ts.URL+"/start").Body.Close()
+
+	resp, err := http.Get(ts.URL + "/state")
+	if err != nil {
+		t.Fatalf("state: %v", err)
+	}
+	defer resp.Body.Close()
+	var st StateJSON
+	if err := json.NewDecoder(resp.Body).Decode(&st); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if st.Idle || st.Phase != "WORK" {
+		t.Fatalf("expected running WORK phase, got %+v", st)
+	}
+}
+
+func TestWebSocketStream(t *testing.T) {
+	eng, ts := newTestServer(t)
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial: %v", err)
+	}
+	defer conn.Close()
+
+	read := func() EventJSON {
+		t.Helper()
+		_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+		var msg EventJSON
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("read: %v", err)
+		}
+		return msg
+	}
+
+	if msg := read(); msg.Type != "state" || !msg.State.Idle {
+		t.Fatalf("expected initial idle state, got %+v", msg)
+	}
+
+	eng.Start()
+	if msg := read(); msg.Type != "start" {
+		t.Fatalf("expected start event, got %q", msg.Type)
+	}
+	if msg := read(); msg.Type != "tick" || msg.State.Remaining <= 0 {
+		t.Fatalf("expected tick with remaining time, got %+v", msg)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	eng, ts := newTestServer(t)
+	send := func(method, path, token, origin string) int {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		if origin != "" {
+			req.Header.Set("Origin", origin)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	for _, tc := range []struct {
+		method, path, token, origin string
+		want                        int
+	}{
+		{"POST", "/start", "", "", http.StatusUnauthorized},
+		{"POST", "/start", "guess", "", http.StatusUnauthorized},
+		{"GET", "/state", "", "", http.StatusOK},
+		{"GET", "/state", "", "https://evil.example", http.StatusForbidden},
+		{"POST", "/stop", testToken, "https://evil.example", http.StatusForbidden},
+		{"GET", "/state", "", "http://localhost:8080", http.StatusOK},
+		{"GET", "/state", "", ts.URL, http.StatusOK},
+		{"GET", "/state", "", "null", http.StatusForbidden},
+	} {
+		if got := send(tc.method, tc.path, tc.token, tc.origin); got != tc.want {
+			t.Errorf("%s %s (token %q, origin %q): %d, want %d", tc.method, tc.path, tc.token, tc.origin, got, tc.want)
+		}
+	}
+	if !eng.State().Idle() {
+		t.Fatal("a refused call started the timer")
+	}
+
+	url := "ws" + strings.TrimPrefix(ts.URL, "http") + "/ws"
+	if conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"https://evil.example"}}); err == nil {
+		conn.Close()
+		t.Fatal("a foreign page streamed /ws")
+	}
+	conn, _, err := websocket.DefaultDialer.Dial(url, http.Header{"Origin": {"http://127.0.0.1:3000"}})
+	if err != nil {
+		t.Fatalf("a localhost page can't stream /ws: %v", err)
+	}
+	conn.Close()
+}
+
+func TestOrigins(t *testing.T) {
+	eng := core.New(core.Config{Work: time.Minute})
+	defer eng.Stop()
+	srv := New(eng)
+	srv.Origins = []string{"https://dash.example.com"}
+	for origin, want := range map[string]bool{
+		"https://dash.example.com": true,
+		"http://dash.example.com":  false,
+		"https://evil.example":     false,
+		"http://[::1]:9000":        true,
+	} {
+		r := httptest.NewRequest("GET", "http://127.0.0.1:7767/ws", nil)
+		r.Header.Set("Origin", origin)
+		if got := srv.allowOrigin(r); got != want {
+			t.Errorf("origin %s allowed %v, want %v", origin, got, want)
+		}
+	}
+	srv.Origins = []string{"*"}
+	r := httptest.NewRequest("GET", "http://127.0.0.1:7767/ws", nil)
+	r.Header.Set("Origin", "https://evil.example")
+	if !srv.allowOrigin(r) {
+		t.Error("* didn't let any origin in")
+	}
+}
+
+func TestLoadToken(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "gopomodoro", "api.token")
+	if tok, err := ReadToken(path); tok != "" || err != nil {
+		t.Fatalf("ReadToken of no file = %q, %v", tok, err)
+	}
+	tok, err := LoadToken(path)
+	if err != nil || len(tok) != 32 {
+		t.Fatalf("LoadToken = %q, %v", tok, err)
+	}
+	if fi, err := os.Stat(path); err != nil || fi.Mode().Perm() != 0o600 {
+		t.Fatalf("token file %v, %v", fi.Mode(), err)
+	}
+	if again, err := LoadToken(path); again != tok || err != nil {
+		t.Fatalf("second LoadToken = %q, %v; want %q", again, err, tok)
+	}
+}
+
+func TestCalendar(t *testing.T) {
+	eng := core.New(core.Config{Work: time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4})
+	srv := New(eng)
+	now := time.Now().UTC().Truncate(time.Second)
+	srv.ServeCalendar(func() ([]history.Session, error) {
+		return []history.Session{
+			{ID: "old", Phase: "WORK", Start: now.AddDate(0, 0, -10), End: now.AddDate(0, 0, -10).Add(25 * time.Minute), Completed: true},
+			{ID: "work", Phase: "WORK", Start: now.Add(-time.Hour), End: now.Add(-35 * time.Minute), Completed: true},
+			{ID: "break", Phase: "SHORT_BREAK", Start: now.Add(-35 * time.Minute), End: now.Add(-30 * time.Minute), Completed: true},
+		}, nil
+	})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp, err := http.Get(ts.URL + "/calendar.ics?days=7&breaks=0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/calendar") {
+		t.Errorf("content type %q", ct)
+	}
+	if got := string(body); !strings.Contains(got, "UID:work@") || strings.Contains(got, "UID:old@") || strings.Contains(got, "UID:break@") {
+		t.Errorf("unexpected feed:\n%s", got)
+	}
+}
+
+func TestTimers(t *testing.T) {
+	cfg := core.Config{Work: time.Minute, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4}
+	eng := core.New(cfg)
+	m := core.NewManager()
+	_ = m.Add(core.DefaultTimer, eng)
+	srv := New(eng)
+	srv.Token = testToken
+	srv.ServeTimers(m, func(profile string) (*core.PomodoroEngine, error) {
+		if profile != "" {
+			return nil, errors.New("unknown profile " + profile)
+		}
+		return core.New(cfg), nil
+	})
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	defer eng.Stop()
+
+	do := func(method, path string, want int) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, ts.URL+path, nil)
+		req.Header.Set("Authorization", "Bearer "+testToken)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != want {
+			t.Fatalf("%s %s: %s, want %d", method, path, resp.Status, want)
+		}
+		return resp
+	}
+	do("POST", "/timers/laundry", http.StatusCreated).Body.Close()
+	do("POST", "/timers/laundry", http.StatusConflict).Body.Close()
+	do("POST", "/timers/dryer?profile=nope", http.StatusBadRequest).Body.Close()
+	do("POST", "/timers/laundry/start", http.StatusOK).Body.Close()
+	do("POST", "/timers/nope/start", http.StatusNotFound).Body.Close()
+
+	resp := do("GET", "/timers", http.StatusOK)
+	var list []TimerJSON
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if len(list) != 2 || list[0].Name != "default" || !list[0].State.Idle || list[1].Name != "laundry" || list[1].State.Idle {
+		t.Fatalf("timers %+v", list)
+	}
+	// the unprefixed endpoints stay on the default timer
+	if !eng.State().Idle() {
+		t.Fatal("laundry start reached the default timer")
+	}
+
+	do("DELETE", "/timers/default", http.StatusBadRequest).Body.Close()
+	do("DELETE", "/timers/laundry", http.StatusNoContent).Body.Close()
+	do("GET", "/timers/laundry/state", http.StatusNotFound).Body.Close()
+}
@@ -0,0 +1,9 @@
+package server
+
+import "github.com/ezchuang/GoPomodoro/internal/team"
+
+// ServeTeam hosts a team session at /team, where other instances join
+// and follow the timer.
+func (s *Server) ServeTeam(h *team.Host) {
+	s.mux.Handle("GET "+team.Path, h)
+}
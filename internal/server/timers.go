@@ -0,0 +1,68 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// TimerJSON is one entry of GET /timers.
+type TimerJSON struct {
+	Name  string    `json:"name"`
+	State StateJSON `json:"state"`
+}
+
+// ServeTimers exposes the timers of m: GET /timers lists them and every
+// engine endpoint is also served under /timers/{timer}/, e.g.
+// POST /timers/laundry/start. With create, POST /timers/{timer} adds a
+// timer (?profile= picks its timings) and DELETE removes one.
+func (s *Server) ServeTimers(m *core.Manager, create func(profile string) (*core.PomodoroEngine, error)) {
+	s.timers = m
+	s.mux.HandleFunc("GET /timers", func(w http.ResponseWriter, r *http.Request) {
+		list := []TimerJSON{}
+		for _, name := range m.Names() {
+			if e := m.Get(name); e != nil {
+				list = append(list, TimerJSON{Name: name, State: snapshot(e)})
+			}
+		}
+		writeJSON(w, http.StatusOK, list)
+	})
+	s.routes("/timers/{timer}")
+	if create == nil {
+		return
+	}
+	s.mux.HandleFunc("POST /timers/{timer}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("timer")
+		if !core.ValidTimerName(name) {
+			http.Error(w, "timer names use letters, digits, - and _", http.StatusBadRequest)
+			return
+		}
+		e, err := create(r.URL.Query().Get("profile"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := m.Add(name, e); err != nil {
+			status := http.StatusBadRequest
+			if errors.Is(err, core.ErrTimerExists) {
+				status = http.StatusConflict
+			}
+			http.Error(w, err.Error(), status)
+			return
+		}
+		writeJSON(w, http.StatusCreated, TimerJSON{Name: name, State: snapshot(e)})
+	})
+	s.mux.HandleFunc("DELETE /timers/{timer}", func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("timer")
+		if m.Get(name) == nil {
+			http.Error(w, "no timer "+name, http.StatusNotFound)
+			return
+		}
+		if err := m.Remove(name); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
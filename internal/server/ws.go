@@ -0,0 +1,87 @@
+package server
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+const writeWait = 5 * time.Second
+
+// handleWS streams engine events to the client as JSON. The current
+// state is sent first so a client can render without waiting, then one
+// message per engine event plus "tick" messages while a phase runs.
+func (s *Server) handleWS(w http.ResponseWriter, r *http.Request) {
+	e := s.engineFor(w, r)
+	if e == nil {
+		return
+	}
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// Upgrade already replied with an HTTP error.
+		return
+	}
+	defer conn.Close()
+
+	events := make(chan core.Event, 32)
+	unsubscribe := e.Subscribe(func(ev core.Event) {
+		select {
+		case events <- ev:
+		default:
+			// slow client: drop, the next tick carries fresh state anyway
+		}
+	})
+	defer unsubscribe()
+
+	// The read loop only exists to notice the client going away.
+	closed := make(chan struct{})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.NextReader(); err != nil {
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(s.TickInterval)
+	defer ticker.Stop()
+
+	send := func(msg EventJSON) bool {
+		_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+		return conn.WriteJSON(msg) == nil
+	}
+
+	if !send(EventJSON{Type: "state", At: time.Now(), State: snapshot(e), Timer: e.Name()}) {
+		return
+	}
+	for {
+		select {
+		case <-closed:
+			return
+		case ev := <-events:
+			msg := EventJSON{
+				Type:     ev.Kind.String(),
+				At:       ev.At,
+				State:    encodeState(ev.State, ev.Remaining, ev.Elapsed),
+				Timer:    ev.Timer,
+				Finished: ev.Finished,
+			}
+			if ev.Refusal != nil {
+				msg.Message = ev.Refusal.Error()
+			}
+			if !send(msg) {
+				return
+			}
+		case now := <-ticker.C:
+			st := e.State()
+			if st.Idle() || st.Paused {
+				continue
+			}
+			if !send(EventJSON{Type: "tick", At: now, State: snapshot(e), Timer: e.Name()}) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+// Package service installs the daemon as a per-user service started at
+// login: a systemd user unit on Linux, a launchd agent on macOS and a
+// scheduled task on Windows.
+package service
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Name names the unit and the task; Label is the launchd agent's.
+const (
+	Name  = "gopomodoro"
+	Label = "io.github.ezchuang.gopomodoro"
+)
+
+// ErrUnsupported is returned where no service manager is supported.
+var ErrUnsupported = errors.New("services are not supported on this system")
+
+// Config is the command the service runs.
+type Config struct {
+	Exe  string   // absolute path of the binary
+	Args []string // e.g. ["daemon", "-profile", "deep-work"]
+}
+
+// Status is what Query finds.
+type Status struct {
+	Installed bool
+	Running   bool
+	Path      string // the unit or plist file, or the task name
+}
+
+func (s Status) String() string {
+	if !s.Installed {
+		return "not installed"
+	}
+	state := "not running"
+	if s.Running {
+		state = "running"
+	}
+	return fmt.Sprintf("installed (%s), %s", s.Path, state)
+}
+
+// Install writes the service for cfg, replacing an earlier one, and
+// enables and starts it.
+func Install(cfg Config) error { return install(cfg) }
+
+// Uninstall stops and removes the service; a missing one is not an
+// error.
+func Uninstall() error { return uninstall() }
+
+// Query reports whether the service is installed and running.
+func Query() (Status, error) { return query() }
+
+// systemdUnit renders the user unit running cfg.
+func systemdUnit(cfg Config) string {
+	words := make([]string, 0, 1+len(cfg.Args))
+	for _, w := range append([]string{cfg.Exe}, cfg.Args...) {
+		words = append(words, systemdQuote(w))
+	}
+	return fmt.Sprintf(`[Unit]
+Description=GoPomodoro daemon
+After=graphical-session.target
+
+[Service]
+ExecStart=%s
+Restart=on-failure
+RestartSec=5
+
+[Install]
+WantedBy=default.target
+`, strings.Join(words, " "))
+}
+
+// systemdQuote quotes a word of ExecStart where needed; '%' starts a
+// specifier and is doubled either way.
+func systemdQuote(s string) string {
+	s = strings.ReplaceAll(s, "%", "%%")
+	if s != "" && !strings.ContainsAny(s, " \t\"'\\;$") {
+		return s
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `$$`)
+	return `"` + r.Replace(s) + `"`
+}
+
+// launchdPlist renders the agent running cfg at login, logging to log.
+func launchdPlist(cfg Config, log string) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>Label</key>
+	<string>` + Label + `</string>
+	<key>ProgramArguments</key>
+	<array>
+`)
+	for _, w := range append([]string{cfg.Exe}, cfg.Args...) {
+		b.WriteString("\t\t<string>" + escape(w) + "</string>\n")
+	}
+	b.WriteString(`	</array>
+	<key>RunAtLoad</key>
+	<true/>
+	<key>KeepAlive</key>
+	<dict>
+		<key>SuccessfulExit</key>
+		<false/>
+	</dict>
+	<key>StandardOutPath</key>
+	<string>` + escape(log) + `</string>
+	<key>StandardErrorPath</key>
+	<string>` + escape(log) + `</string>
+</dict>
+</plist>
+`)
+	return b.String()
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// plistPath is the agent under ~/Library/LaunchAgents.
+func plistPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "Library", "LaunchAgents", Label+".plist"), nil
+}
+
+// domain is the launchd domain of the user's GUI session.
+func domain() string {
+	return fmt.Sprintf("gui/%d", os.Getuid())
+}
+
+func launchctl(args ...string) error {
+	out, err := exec.Command("launchctl", args...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("launchctl %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func install(cfg Config) error {
+	path, err := plistPath()
+	if err != nil {
+		return err
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return err
+	}
+	log := filepath.Join(home, "Library", "Logs", Name+".log")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(launchdPlist(cfg, log)), 0o644); err != nil {
+		return err
+	}
+	// replace a loaded agent; bootout fails when there is none
+	_ = launchctl("bootout", domain()+"/"+Label)
+	return launchctl("bootstrap", domain(), path)
+}
+
+func uninstall() error {
+	path, err := plistPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	_ = launchctl("bootout", domain()+"/"+Label)
+	return os.Remove(path)
+}
+
+func query() (Status, error) {
+	path, err := plistPath()
+	if err != nil {
+		return Status{}, err
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return Status{}, nil
+	} else if err != nil {
+		return Status{}, err
+	}
+	out, _ := exec.Command("launchctl", "print", domain()+"/"+Label).Output()
+	return Status{Installed: true, Running: strings.Contains(string(out), "state = running"), Path: path}, nil
+}
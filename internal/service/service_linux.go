@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+const unit = Name + ".service"
+
+// unitPath is the user unit under $XDG_CONFIG_HOME/systemd/user.
+func unitPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "systemd", "user", unit), nil
+}
+
+func systemctl(args ...string) error {
+	out, err := exec.Command("systemctl", append([]string{"--user"}, args...)...).CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("systemctl %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func install(cfg Config) error {
+	path, err := unitPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(systemdUnit(cfg)), 0o644); err != nil {
+		return err
+	}
+	if err := systemctl("daemon-reload"); err != nil {
+		return err
+	}
+	if err := systemctl("enable", unit); err != nil {
+		return err
+	}
+	// pick up new flags when reinstalling over a running daemon
+	return systemctl("restart", unit)
+}
+
+func uninstall() error {
+	path, err := unitPath()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	if err := systemctl("disable", "--now", unit); err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return err
+	}
+	return systemctl("daemon-reload")
+}
+
+func query() (Status, error) {
+	path, err := unitPath()
+	if err != nil {
+		return Status{}, err
+	}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		return Status{}, nil
+	} else if err != nil {
+		return Status{}, err
+	}
+	// is-active exits non-zero for anything but "active"
+	out, _ := exec.Command("systemctl", "--user", "is-active", unit).Output()
+	return Status{Installed: true, Running: strings.TrimSpace(string(out)) == "active", Path: path}, nil
+}
@@ -0,0 +1,7 @@
+//go:build !linux && !darwin && !windows
+
+package service
+
+func install(Config) error   { return ErrUnsupported }
+func uninstall() error       { return ErrUnsupported }
+func query() (Status, error) { return Status{}, ErrUnsupported }
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func TestSystemdUnit_QuotesExecStart(t *testing.T) {
+	unit := systemdUnit(Config{Exe: "/opt/my apps/gopomodoro", Args: []string{"daemon", "-profile", "100%", "-listen", "127.0.0.1:7767"}})
+	want := `ExecStart="/opt/my apps/gopomodoro" daemon -profile 100%% -listen 127.0.0.1:7767` + "\n"
+	if !strings.Contains(unit, want) {
+		t.Fatalf("unit lacks %q:\n%s", want, unit)
+	}
+	if !strings.Contains(unit, "WantedBy=default.target") {
+		t.Fatalf("unit isn't enabled for login:\n%s", unit)
+	}
+}
+
+func TestLaunchdPlist_IsValidXML(t *testing.T) {
+	plist := launchdPlist(Config{Exe: "/Users/a&b/bin/gopomodoro", Args: []string{"daemon"}}, "/tmp/log")
+	d := xml.NewDecoder(strings.NewReader(plist))
+	var strs []string
+	inString := false
+	for {
+		tok, err := d.Token()
+		if err != nil {
+			break
+		}
+		switch tok := tok.(type) {
+		case xml.StartElement:
+			inString = tok.Name.Local == "string"
+		case xml.CharData:
+			if inString {
+				strs = append(strs, string(tok))
+			}
+		case xml.EndElement:
+			inString = false
+		}
+	}
+	want := []string{Label, "/Users/a&b/bin/gopomodoro", "daemon", "/tmp/log", "/tmp/log"}
+	if strings.Join(strs, "|") != strings.Join(want, "|") {
+		t.Fatalf("strings %q, want %q", strs, want)
+	}
+}
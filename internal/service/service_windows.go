@@ -0,0 +1,48 @@
+package service
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+func schtasks(args ...string) (string, error) {
+	out, err := exec.Command("schtasks", args...).CombinedOutput()
+	if err != nil {
+		return "", fmt.Errorf("schtasks %s: %w: %s", args[0], err, strings.TrimSpace(string(out)))
+	}
+	return string(out), nil
+}
+
+func install(cfg Config) error {
+	words := make([]string, 0, 1+len(cfg.Args))
+	for _, w := range append([]string{cfg.Exe}, cfg.Args...) {
+		words = append(words, syscall.EscapeArg(w))
+	}
+	// stop a running daemon so the new task's flags take effect
+	_, _ = schtasks("/End", "/TN", Name)
+	if _, err := schtasks("/Create", "/F", "/TN", Name, "/SC", "ONLOGON", "/RL", "LIMITED", "/TR", strings.Join(words, " ")); err != nil {
+		return err
+	}
+	_, err := schtasks("/Run", "/TN", Name)
+	return err
+}
+
+func uninstall() error {
+	if _, err := schtasks("/Query", "/TN", Name); err != nil {
+		return nil
+	}
+	_, _ = schtasks("/End", "/TN", Name)
+	_, err := schtasks("/Delete", "/F", "/TN", Name)
+	return err
+}
+
+func query() (Status, error) {
+	out, err := schtasks("/Query", "/TN", Name, "/FO", "LIST")
+	if err != nil {
+		// not found
+		return Status{}, nil
+	}
+	return Status{Installed: true, Running: strings.Contains(out, "Running"), Path: Name}, nil
+}
@@ -0,0 +1,68 @@
+package stats
+
+import (
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// Day is the work done on one local calendar day.
+type Day struct {
+	Date      time.Time // midnight, local time
+	Pomodoros int
+	Focus     time.Duration
+}
+
+// Daily returns one Day for each of the n calendar days ending on end,
+// oldest first.
+func Daily(sessions []history.Session, end time.Time, n int) []Day {
+	if n <= 0 {
+		return nil
+	}
+	last := startOfDay(end)
+	first := last.AddDate(0, 0, -(n - 1))
+	days := make([]Day, n)
+	index := make(map[time.Time]int, n)
+	for i := range days {
+		d := first.AddDate(0, 0, i)
+		days[i].Date = d
+		index[d] = i
+	}
+	for _, s := range sessions {
+		if s.Phase != core.PhaseWork.String() {
+			continue
+		}
+		i, ok := index[startOfDay(s.Start.In(end.Location()))]
+		if !ok {
+			continue
+		}
+		if s.Completed {
+			days[i].Pomodoros++
+		}
+		days[i].Focus += s.Active()
+	}
+	return days
+}
+
+// Streak counts consecutive days with at least one completed pomodoro,
+// ending today. A day without pomodoros yet doesn't break the streak
+// until it is over, so the count then ends yesterday.
+func Streak(sessions []history.Session, today time.Time) int {
+	done := map[time.Time]bool{}
+	for _, s := range sessions {
+		if s.Completed && s.Phase == core.PhaseWork.String() {
+			done[startOfDay(s.Start.In(today.Location()))] = true
+		}
+	}
+	day := startOfDay(today)
+	if !done[day] {
+		day = day.AddDate(0, 0, -1)
+	}
+	n := 0
+	for done[day] {
+		n++
+		day = day.AddDate(0, 0, -1)
+	}
+	return n
+}
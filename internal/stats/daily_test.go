@@ -0,0 +1,47 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestDailyAndStreak(t *testing.T) {
+	now := time.Date(2025, 5, 10, 9, 0, 0, 0, time.Local)
+	work := func(daysAgo int, completed bool) history.Session {
+		start := now.AddDate(0, 0, -daysAgo).Add(-time.Hour)
+		return history.Session{Phase: "WORK", Start: start, End: start.Add(25 * time.Minute), Completed: completed}
+	}
+	sessions := []history.Session{
+		work(1, true), work(1, true), work(1, false),
+		work(2, true),
+		// gap on day 3
+		work(4, true),
+		work(20, true), // outside the window
+		{Phase: "SHORT_BREAK", Start: now.Add(-time.Hour), Completed: true},
+	}
+
+	days := Daily(sessions, now, 7)
+	if len(days) != 7 || !days[6].Date.Equal(startOfDay(now)) {
+		t.Fatalf("unexpected days %+v", days)
+	}
+	want := []int{0, 0, 1, 0, 1, 2, 0}
+	for i, d := range days {
+		if d.Pomodoros != want[i] {
+			t.Fatalf("day %d: want %d, got %d", i, want[i], d.Pomodoros)
+		}
+	}
+	if days[5].Focus != 75*time.Minute {
+		t.Fatalf("focus should include unfinished sessions, got %v", days[5].Focus)
+	}
+
+	// nothing today yet: the streak runs through yesterday
+	if n := Streak(sessions, now); n != 2 {
+		t.Fatalf("want streak 2, got %d", n)
+	}
+	sessions = append(sessions, work(0, true))
+	if n := Streak(sessions, now); n != 3 {
+		t.Fatalf("want streak 3, got %d", n)
+	}
+}
@@ -0,0 +1,96 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// TaskEstimate compares the pomodoros a task was estimated to take with
+// the ones completed on it.
+type TaskEstimate struct {
+	Title    string
+	Estimate int // the latest estimate recorded
+	Actual   int // completed work sessions
+}
+
+// Variance is how many pomodoros the task took beyond its estimate;
+// negative when it took fewer.
+func (e TaskEstimate) Variance() int { return e.Actual - e.Estimate }
+
+// Estimates lists the estimated tasks worked on in sessions, largest
+// variance first. Tasks are told apart by source and ID, or by title
+// when they have neither.
+func Estimates(sessions []history.Session) []TaskEstimate {
+	type key struct{ source, id, title string }
+	byTask := map[key]*TaskEstimate{}
+	var order []key
+	for _, s := range sessions {
+		if s.Phase != core.PhaseWork.String() || s.Task == nil {
+			continue
+		}
+		k := key{s.Task.Source, s.Task.ID, ""}
+		if k.source == "" && k.id == "" {
+			k.title = s.Task.Title
+		}
+		te := byTask[k]
+		if te == nil {
+			te = &TaskEstimate{}
+			byTask[k] = te
+			order = append(order, k)
+		}
+		// the history is oldest first, so later estimates win
+		te.Title = s.Task.Title
+		if s.Task.Estimate > 0 {
+			te.Estimate = s.Task.Estimate
+		}
+		if s.Completed {
+			te.Actual++
+		}
+	}
+	var out []TaskEstimate
+	for _, k := range order {
+		if te := byTask[k]; te.Estimate > 0 {
+			out = append(out, *te)
+		}
+	}
+	slices.SortStableFunc(out, func(a, b TaskEstimate) int {
+		return cmp.Compare(abs(b.Variance()), abs(a.Variance()))
+	})
+	return out
+}
+
+// EstimateAccuracy is the pomodoros completed per one estimated over
+// ests: above 1 when tasks overran their estimates. It is 0 without
+// any.
+func EstimateAccuracy(ests []TaskEstimate) float64 {
+	var estimated, actual int
+	for _, e := range ests {
+		estimated += e.Estimate
+		actual += e.Actual
+	}
+	if estimated == 0 {
+		return 0
+	}
+	return float64(actual) / float64(estimated)
+}
+
+// LatestEstimate is the last estimate recorded for the task with the
+// given source and ID, or 0.
+func LatestEstimate(sessions []history.Session, source, id string) int {
+	for _, s := range slices.Backward(sessions) {
+		if s.Task != nil && s.Task.Source == source && s.Task.ID == id && s.Task.Estimate > 0 {
+			return s.Task.Estimate
+		}
+	}
+	return 0
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
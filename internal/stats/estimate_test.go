@@ -0,0 +1,41 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestEstimates(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 5, 1, h, 0, 0, 0, time.Local) }
+	task := func(id, title string, est int) *history.Task {
+		return &history.Task{Source: "file", ID: id, Title: title, Estimate: est}
+	}
+	sessions := []history.Session{
+		{Phase: "WORK", Start: at(9), Completed: true, Task: task("a", "report", 2)},
+		{Phase: "WORK", Start: at(10), Completed: true, Task: task("a", "report", 3)},
+		{Phase: "WORK", Start: at(11), Abandoned: true, Task: task("a", "report", 0)},
+		{Phase: "WORK", Start: at(12), Completed: true, Task: task("b", "review", 4)},
+		{Phase: "WORK", Start: at(13), Completed: true, Task: task("c", "mail", 0)},
+		{Phase: "SHORT_BREAK", Start: at(14), Completed: true, Task: task("b", "review", 9)},
+	}
+	got := Estimates(sessions)
+	want := []TaskEstimate{
+		{Title: "review", Estimate: 4, Actual: 1},
+		{Title: "report", Estimate: 3, Actual: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got[0].Variance() != -3 {
+		t.Errorf("variance %d, want -3", got[0].Variance())
+	}
+	if acc := EstimateAccuracy(got); acc != 3.0/7 {
+		t.Errorf("accuracy %v, want 3/7", acc)
+	}
+	if est := LatestEstimate(sessions, "file", "a"); est != 3 {
+		t.Errorf("latest estimate %d, want 3", est)
+	}
+}
@@ -0,0 +1,87 @@
+package stats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// CompletedOn counts completed work sessions that started on the same
+// local calendar day as day.
+func CompletedOn(sessions []history.Session, day time.Time) int {
+	start := startOfDay(day)
+	end := start.AddDate(0, 0, 1)
+	n := 0
+	for _, s := range sessions {
+		if s.Completed && s.Phase == core.PhaseWork.String() &&
+			!s.Start.Before(start) && s.Start.Before(end) {
+			n++
+		}
+	}
+	return n
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// Goal tracks today's completed pomodoros against a daily target. Seed
+// it from history, then subscribe Handle to the engine.
+type Goal struct {
+	mu     sync.Mutex
+	target int
+	day    time.Time
+	done   int
+	prev   core.State
+	onHit  func(done, target int)
+	now    func() time.Time
+}
+
+// NewGoal creates a Goal for target (0 disables the goal but still
+// counts). onHit, if non-nil, is called once per day when the target is
+// reached.
+func NewGoal(target int, sessions []history.Session, onHit func(done, target int)) *Goal {
+	g := &Goal{target: target, onHit: onHit, now: time.Now}
+	g.day = startOfDay(g.now())
+	g.done = CompletedOn(sessions, g.day)
+	return g
+}
+
+// Progress returns today's count and the target.
+func (g *Goal) Progress() (done, target int) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.rolloverLocked()
+	return g.done, g.target
+}
+
+func (g *Goal) rolloverLocked() {
+	if today := startOfDay(g.now()); !today.Equal(g.day) {
+		g.day = today
+		g.done = 0
+	}
+}
+
+// Handle consumes one engine event.
+func (g *Goal) Handle(ev core.Event) {
+	g.mu.Lock()
+	prev := g.prev
+	g.prev = ev.State
+
+	if !ev.CompletedWork(prev) {
+		g.mu.Unlock()
+		return
+	}
+	g.rolloverLocked()
+	g.done++
+	hit := g.target > 0 && g.done == g.target
+	done, target := g.done, g.target
+	g.mu.Unlock()
+
+	if hit && g.onHit != nil {
+		g.onHit(done, target)
+	}
+}
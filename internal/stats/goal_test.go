@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestGoal_SeedsFromHistoryAndFiresOnce(t *testing.T) {
+	now := time.Date(2025, 5, 1, 15, 0, 0, 0, time.Local)
+	sessions := []history.Session{
+		{Phase: "WORK", Completed: true, Start: now.Add(-2 * time.Hour)},
+		{Phase: "WORK", Completed: false, Start: now.Add(-time.Hour)},
+		{Phase: "WORK", Completed: true, Start: now.AddDate(0, 0, -1)},
+		{Phase: "SHORT_BREAK", Completed: true, Start: now.Add(-time.Hour)},
+	}
+
+	hits := 0
+	g := NewGoal(3, nil, func(done, target int) { hits++ })
+	g.now = func() time.Time { return now }
+	g.day = startOfDay(now)
+	g.done = CompletedOn(sessions, now)
+
+	if done, target := g.Progress(); done != 1 || target != 3 {
+		t.Fatalf("seeded progress: got %d/%d", done, target)
+	}
+
+	work := core.State{Phase: core.PhaseWork, StartedAt: now}
+	brk := core.State{Phase: core.PhaseShortBreak, StartedAt: now}
+	for range 3 {
+		g.Handle(core.Event{Kind: core.EventStart, State: work})
+		g.Handle(core.Event{Kind: core.EventAdvance, State: brk})
+	}
+	if done, _ := g.Progress(); done != 4 {
+		t.Fatalf("want 4 done, got %d", done)
+	}
+	if hits != 1 {
+		t.Fatalf("goal notification should fire once, got %d", hits)
+	}
+
+	// the last pomodoro of a -cycles run ends in a stop, not an advance
+	g.Handle(core.Event{Kind: core.EventStart, State: work})
+	g.Handle(core.Event{Kind: core.EventStop, Finished: true})
+	g.Handle(core.Event{Kind: core.EventStart, State: work})
+	g.Handle(core.Event{Kind: core.EventStop})
+	if done, _ := g.Progress(); done != 5 {
+		t.Fatalf("want 5 done after a finished run, got %d", done)
+	}
+
+	// a new day starts from zero
+	now = now.AddDate(0, 0, 1)
+	if done, _ := g.Progress(); done != 0 {
+		t.Fatalf("expected rollover, got %d", done)
+	}
+}
+
+func TestGoal_OvertimeStopCountsOnce(t *testing.T) {
+	eng := core.New(core.Config{Work: 10 * time.Millisecond, ShortBrk: time.Minute, LongBrk: time.Minute, LongEvery: 4, Overtime: true})
+	defer eng.Stop()
+	g := NewGoal(3, nil, nil)
+	handled := make(chan core.EventKind, 16)
+	defer eng.Subscribe(func(ev core.Event) {
+		g.Handle(ev)
+		handled <- ev.Kind
+	})()
+	waitFor := func(kind core.EventKind) {
+		t.Helper()
+		for {
+			select {
+			case k := <-handled:
+				if k == kind {
+					return
+				}
+			case <-time.After(time.Second):
+				t.Fatalf("timeout waiting for %v", kind)
+			}
+		}
+	}
+
+	eng.Start()
+	waitFor(core.EventOvertime)
+	eng.Stop()
+	waitFor(core.EventStop)
+	// the stop counted the pomodoro, so it can't be undone and counted
+	// again by an acknowledge
+	if err := eng.Undo(); !errors.Is(err, core.ErrNothingToUndo) {
+		t.Fatalf("undo of an overtime stop: %v", err)
+	}
+	eng.Acknowledge()
+	eng.Start()
+	waitFor(core.EventStart)
+	if done, _ := g.Progress(); done != 1 {
+		t.Fatalf("want 1 done, got %d", done)
+	}
+}
@@ -0,0 +1,252 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// TaskStat is the work spent on one task.
+type TaskStat struct {
+	Title     string
+	Pomodoros int
+	Focus     time.Duration
+}
+
+// DayReport is the end-of-day summary of one local calendar day.
+type DayReport struct {
+	Date time.Time // midnight, local time
+	Summary
+	// Tasks are the tasks worked on, most focus first.
+	Tasks []TaskStat
+	Goal  int // daily target, 0 for none
+}
+
+// Report summarizes the sessions that started on day's calendar day,
+// against a daily goal of target pomodoros.
+func Report(sessions []history.Session, day time.Time, target int) DayReport {
+	start := startOfDay(day)
+	today := between(sessions, start, start.AddDate(0, 0, 1))
+	return DayReport{Date: start, Summary: Summarize(today), Tasks: taskStats(today), Goal: target}
+}
+
+// between keeps sessions that started in [start, end).
+func between(sessions []history.Session, start, end time.Time) []history.Session {
+	var out []history.Session
+	for _, s := range sessions {
+		if !s.Start.Before(start) && s.Start.Before(end) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// taskStats totals work sessions per task title, most focus first.
+func taskStats(sessions []history.Session) []TaskStat {
+	byTask := map[string]*TaskStat{}
+	for _, s := range sessions {
+		if s.Phase != core.PhaseWork.String() || s.Task == nil || s.Task.Title == "" {
+			continue
+		}
+		ts := byTask[s.Task.Title]
+		if ts == nil {
+			ts = &TaskStat{Title: s.Task.Title}
+			byTask[s.Task.Title] = ts
+		}
+		if s.Completed {
+			ts.Pomodoros++
+		}
+		ts.Focus += s.Active()
+	}
+	var out []TaskStat
+	for _, ts := range byTask {
+		out = append(out, *ts)
+	}
+	slices.SortFunc(out, func(a, b TaskStat) int {
+		if c := cmp.Compare(b.Focus, a.Focus); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Title, b.Title)
+	})
+	return out
+}
+
+// GoalMet reports whether a goal is set and was reached.
+func (r DayReport) GoalMet() bool {
+	return r.Goal > 0 && r.Pomodoros >= r.Goal
+}
+
+// Top returns at most n of the tasks.
+func (r DayReport) Top(n int) []TaskStat {
+	return top(r.Tasks, n)
+}
+
+func top(tasks []TaskStat, n int) []TaskStat {
+	return tasks[:min(n, len(tasks))]
+}
+
+// Text is a short summary for a notification, naming up to top tasks.
+func (r DayReport) Text(top int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s, %s focus", pomodoros(r.Pomodoros), r.Focus.Round(time.Minute))
+	if r.Goal > 0 {
+		fmt.Fprintf(&b, " · goal %d/%d", r.Pomodoros, r.Goal)
+		if r.GoalMet() {
+			b.WriteString(" ✓")
+		}
+	}
+	if tasks := r.Top(top); len(tasks) > 0 {
+		names := make([]string, len(tasks))
+		for i, t := range tasks {
+			names[i] = fmt.Sprintf("%s (%d)", t.Title, t.Pomodoros)
+		}
+		b.WriteString("\nTop: " + strings.Join(names, ", "))
+	}
+	return b.String()
+}
+
+// Markdown renders the report as a Markdown document listing up to top
+// tasks.
+func (r DayReport) Markdown(top int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Pomodoro summary, %s\n\n", r.Date.Format("Monday 2006-01-02"))
+	fmt.Fprintf(&b, "- Pomodoros: %d", r.Pomodoros)
+	if r.Goal > 0 {
+		fmt.Fprintf(&b, " of %d (%d%%)", r.Goal, r.Pomodoros*100/r.Goal)
+		if r.GoalMet() {
+			b.WriteString(", goal reached")
+		}
+	}
+	b.WriteString("\n")
+	fmt.Fprintf(&b, "- Focus: %s\n", r.Focus.Round(time.Minute))
+	if r.Paused > 0 {
+		fmt.Fprintf(&b, "- Paused: %s\n", r.Paused.Round(time.Minute))
+	}
+	if r.Abandoned > 0 {
+		fmt.Fprintf(&b, "- Abandoned: %d\n", r.Abandoned)
+	}
+	if n := r.InternalInterruptions + r.ExternalInterruptions; n > 0 {
+		fmt.Fprintf(&b, "- Interruptions: %d (%d internal, %d external)\n", n, r.InternalInterruptions, r.ExternalInterruptions)
+	}
+	if tasks := r.Top(top); len(tasks) > 0 {
+		b.WriteString("\n## Top tasks\n\n")
+		for i, t := range tasks {
+			fmt.Fprintf(&b, "%d. %s: %s, %s\n", i+1, t.Title, pomodoros(t.Pomodoros), t.Focus.Round(time.Minute))
+		}
+	}
+	return b.String()
+}
+
+func pomodoros(n int) string {
+	if n == 1 {
+		return "1 pomodoro"
+	}
+	return fmt.Sprintf("%d pomodoros", n)
+}
+
+// WeekReport is the digest of the seven calendar days ending on End.
+type WeekReport struct {
+	Days []Day // oldest first
+	Summary
+	// Previous covers the seven days before.
+	Previous Summary
+	Tasks    []TaskStat
+	Goal     int // daily target, 0 for none
+	Streak   int // days in a row with a pomodoro, as of the last day
+}
+
+// Week summarizes the seven calendar days ending on end's day.
+func Week(sessions []history.Session, end time.Time, target int) WeekReport {
+	last := startOfDay(end).AddDate(0, 0, 1)
+	first := last.AddDate(0, 0, -7)
+	week := between(sessions, first, last)
+	return WeekReport{
+		Days:     Daily(sessions, end, 7),
+		Summary:  Summarize(week),
+		Previous: Summarize(between(sessions, first.AddDate(0, 0, -7), first)),
+		Tasks:    taskStats(week),
+		Goal:     target,
+		Streak:   Streak(sessions, end),
+	}
+}
+
+// GoalDays counts the days the goal was met, 0 without a goal.
+func (r WeekReport) GoalDays() int {
+	if r.Goal <= 0 {
+		return 0
+	}
+	n := 0
+	for _, d := range r.Days {
+		if d.Pomodoros >= r.Goal {
+			n++
+		}
+	}
+	return n
+}
+
+// Top returns at most n of the tasks.
+func (r WeekReport) Top(n int) []TaskStat {
+	return top(r.Tasks, n)
+}
+
+// Title names the week, e.g. "Apr 25 – May 1".
+func (r WeekReport) Title() string {
+	first, last := r.Days[0].Date, r.Days[len(r.Days)-1].Date
+	return first.Format("Jan 2") + " – " + last.Format("Jan 2")
+}
+
+// Text renders the digest as plain text with a bar chart of pomodoros
+// per day, listing up to top tasks. It needs a fixed-width font.
+func (r WeekReport) Text(top int) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Week of %s\n\n", r.Title())
+	fmt.Fprintf(&b, "Pomodoros:     %d%s\n", r.Pomodoros, delta(r.Pomodoros, r.Previous.Pomodoros))
+	fmt.Fprintf(&b, "Focus:         %s\n", r.Focus.Round(time.Minute))
+	if r.Pomodoros+r.Abandoned > 0 {
+		fmt.Fprintf(&b, "Completion:    %.0f%% (%d abandoned)\n", r.CompletionRate()*100, r.Abandoned)
+	}
+	if n := r.InternalInterruptions + r.ExternalInterruptions; n > 0 {
+		fmt.Fprintf(&b, "Interruptions: %d (%.1f per pomodoro)\n", n, r.InterruptionsPerPomodoro())
+	}
+	if r.Goal > 0 {
+		fmt.Fprintf(&b, "Goal:          met %d of 7 days\n", r.GoalDays())
+	}
+	fmt.Fprintf(&b, "Streak:        %d days\n\n", r.Streak)
+
+	const barWidth = 30
+	peak := max(1, r.Goal)
+	for _, d := range r.Days {
+		peak = max(peak, d.Pomodoros)
+	}
+	for _, d := range r.Days {
+		n := d.Pomodoros * barWidth / peak
+		if d.Pomodoros > 0 {
+			n = max(n, 1)
+		}
+		fmt.Fprintf(&b, "%s %-*s %2d  %s\n", d.Date.Format("Mon 01-02"), barWidth, strings.Repeat("#", n), d.Pomodoros, d.Focus.Round(time.Minute))
+	}
+	if tasks := r.Top(top); len(tasks) > 0 {
+		b.WriteString("\nTop tasks\n")
+		for i, t := range tasks {
+			fmt.Fprintf(&b, "%d. %s: %s, %s\n", i+1, t.Title, pomodoros(t.Pomodoros), t.Focus.Round(time.Minute))
+		}
+	}
+	return b.String()
+}
+
+// delta formats the change from the previous week, e.g.
+// " (+3 vs. last week)"; empty when unchanged.
+func delta(cur, prev int) string {
+	switch {
+	case cur > prev:
+		return fmt.Sprintf(" (+%d vs. last week)", cur-prev)
+	case cur < prev:
+		return fmt.Sprintf(" (%d vs. last week)", cur-prev)
+	}
+	return ""
+}
@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestReport_Day(t *testing.T) {
+	day := time.Date(2025, 5, 1, 0, 0, 0, 0, time.Local)
+	at := func(h, m int) time.Time { return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute) }
+	report := &history.Task{Title: "report"}
+	review := &history.Task{Title: "review"}
+	sessions := []history.Session{
+		{Phase: "WORK", Start: at(-2, 0), End: at(-1, 35), Completed: true, Task: report}, // yesterday
+		{Phase: "WORK", Start: at(9, 0), End: at(9, 25), Completed: true, Task: report},
+		{Phase: "SHORT_BREAK", Start: at(9, 25), End: at(9, 30), Completed: true},
+		{Phase: "WORK", Start: at(9, 30), End: at(9, 55), Completed: true, Task: review},
+		{Phase: "WORK", Start: at(10, 0), End: at(10, 25), Completed: true, Task: report},
+		{Phase: "WORK", Start: at(11, 0), End: at(11, 10), Abandoned: true},
+	}
+
+	r := Report(sessions, at(18, 0), 4)
+	if !r.Date.Equal(day) || r.Pomodoros != 3 || r.Abandoned != 1 || r.Focus != 85*time.Minute {
+		t.Fatalf("report: got %+v", r)
+	}
+	if r.GoalMet() {
+		t.Fatal("3/4 met the goal")
+	}
+	want := []TaskStat{
+		{Title: "report", Pomodoros: 2, Focus: 50 * time.Minute},
+		{Title: "review", Pomodoros: 1, Focus: 25 * time.Minute},
+	}
+	if len(r.Tasks) != len(want) || r.Tasks[0] != want[0] || r.Tasks[1] != want[1] {
+		t.Fatalf("tasks: want %v, got %v", want, r.Tasks)
+	}
+	if got := r.Text(1); got != "3 pomodoros, 1h25m0s focus · goal 3/4\nTop: report (2)" {
+		t.Fatalf("text: got %q", got)
+	}
+	md := r.Markdown(3)
+	for _, s := range []string{"# Pomodoro summary, Thursday 2025-05-01", "- Pomodoros: 3 of 4 (75%)", "1. report: 2 pomodoros, 50m0s", "2. review: 1 pomodoro, 25m0s"} {
+		if !strings.Contains(md, s) {
+			t.Fatalf("markdown lacks %q:\n%s", s, md)
+		}
+	}
+}
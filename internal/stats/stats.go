@@ -0,0 +1,117 @@
+// Package stats aggregates history sessions into summaries.
+package stats
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// ReasonStat is the pause total for one reason category.
+type ReasonStat struct {
+	Reason string
+	Count  int
+	Total  time.Duration
+}
+
+// Summary is an aggregate over a set of sessions.
+type Summary struct {
+	Pomodoros int           // completed work sessions
+	Focus     time.Duration // active (unpaused) time in work sessions
+	Paused    time.Duration // paused time in work sessions
+	Overtime  time.Duration // focus time past work deadlines
+	Pauses    []ReasonStat  // paused time per reason, largest first
+
+	InternalInterruptions int
+	ExternalInterruptions int
+	WorkSessions          int // work sessions, finished or not
+	Abandoned             int // work sessions stopped before their deadline
+}
+
+// CompletionRate is the share of work sessions that were completed
+// rather than abandoned; skipped sessions don't count either way. It is
+// 0 without any.
+func (s Summary) CompletionRate() float64 {
+	if s.Pomodoros+s.Abandoned == 0 {
+		return 0
+	}
+	return float64(s.Pomodoros) / float64(s.Pomodoros+s.Abandoned)
+}
+
+// InterruptionsPerPomodoro is the average number of interruptions per
+// work session.
+func (s Summary) InterruptionsPerPomodoro() float64 {
+	if s.WorkSessions == 0 {
+		return 0
+	}
+	return float64(s.InternalInterruptions+s.ExternalInterruptions) / float64(s.WorkSessions)
+}
+
+// Filter keeps sessions that started at or after since; a zero since
+// keeps everything.
+func Filter(sessions []history.Session, since time.Time) []history.Session {
+	if since.IsZero() {
+		return sessions
+	}
+	var out []history.Session
+	for _, s := range sessions {
+		if !s.Start.Before(since) {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// Summarize aggregates work sessions. Breaks are ignored: pauses during
+// a break don't fragment focus.
+func Summarize(sessions []history.Session) Summary {
+	var sum Summary
+	byReason := map[string]*ReasonStat{}
+	for _, s := range sessions {
+		if s.Phase != core.PhaseWork.String() {
+			continue
+		}
+		sum.WorkSessions++
+		if s.Completed {
+			sum.Pomodoros++
+		} else if s.Abandoned {
+			sum.Abandoned++
+		}
+		for _, it := range s.Interruptions {
+			if it.Kind == core.InterruptExternal.String() {
+				sum.ExternalInterruptions++
+			} else {
+				sum.InternalInterruptions++
+			}
+		}
+		sum.Focus += s.Active()
+		sum.Paused += s.Paused()
+		sum.Overtime += s.Overtime
+		for _, p := range s.Pauses {
+			reason := p.Reason
+			if reason == "" {
+				reason = "unspecified"
+			}
+			rs := byReason[reason]
+			if rs == nil {
+				rs = &ReasonStat{Reason: reason}
+				byReason[reason] = rs
+			}
+			rs.Count++
+			rs.Total += p.Duration()
+		}
+	}
+	for _, rs := range byReason {
+		sum.Pauses = append(sum.Pauses, *rs)
+	}
+	slices.SortFunc(sum.Pauses, func(a, b ReasonStat) int {
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Reason, b.Reason)
+	})
+	return sum
+}
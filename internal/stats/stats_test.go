@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestSummarize_PauseBreakdown(t *testing.T) {
+	base := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	at := func(m int) time.Time { return base.Add(time.Duration(m) * time.Minute) }
+	sessions := []history.Session{
+		{Phase: "WORK", Start: at(0), End: at(35), Completed: true, Pauses: []history.Pause{
+			{Start: at(5), End: at(15), Reason: "meeting"},
+		}},
+		{Phase: "SHORT_BREAK", Start: at(35), End: at(40), Completed: true, Pauses: []history.Pause{
+			{Start: at(36), End: at(38), Reason: "bio"},
+		}},
+		{Phase: "WORK", Start: at(40), End: at(50), Pauses: []history.Pause{
+			{Start: at(41), End: at(43), Reason: "bio"},
+			{Start: at(44), End: at(45)},
+		}},
+	}
+
+	sum := Summarize(sessions)
+	if sum.Pomodoros != 1 {
+		t.Fatalf("pomodoros: want 1, got %d", sum.Pomodoros)
+	}
+	if sum.Focus != 25*time.Minute+7*time.Minute {
+		t.Fatalf("focus: got %v", sum.Focus)
+	}
+	want := []ReasonStat{
+		{Reason: "meeting", Count: 1, Total: 10 * time.Minute},
+		{Reason: "bio", Count: 1, Total: 2 * time.Minute},
+		{Reason: "unspecified", Count: 1, Total: time.Minute},
+	}
+	if len(sum.Pauses) != len(want) {
+		t.Fatalf("pauses: want %v, got %v", want, sum.Pauses)
+	}
+	for i := range want {
+		if sum.Pauses[i] != want[i] {
+			t.Fatalf("pause %d: want %+v, got %+v", i, want[i], sum.Pauses[i])
+		}
+	}
+}
+
+func TestFilter_Since(t *testing.T) {
+	base := time.Date(2025, 5, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []history.Session{
+		{ID: "old", Start: base.AddDate(0, 0, -1)},
+		{ID: "new", Start: base},
+	}
+	got := Filter(sessions, base)
+	if len(got) != 1 || got[0].ID != "new" {
+		t.Fatalf("unexpected filter result %v", got)
+	}
+	if len(Filter(sessions, time.Time{})) != 2 {
+		t.Fatal("zero since should keep everything")
+	}
+}
+
+func TestSummarize_Interruptions(t *testing.T) {
+	sessions := []history.Session{
+		{Phase: "WORK", Completed: true, Interruptions: []history.Interruption{
+			{Kind: "internal"}, {Kind: "external", Note: "phone"},
+		}},
+		{Phase: "WORK", Interruptions: []history.Interruption{{Kind: "external"}}},
+		{Phase: "SHORT_BREAK"},
+	}
+	sum := Summarize(sessions)
+	if sum.InternalInterruptions != 1 || sum.ExternalInterruptions != 2 {
+		t.Fatalf("unexpected counts %+v", sum)
+	}
+	if got := sum.InterruptionsPerPomodoro(); got != 1.5 {
+		t.Fatalf("per pomodoro: want 1.5, got %v", got)
+	}
+}
+
+func TestSummarize_CompletionRate(t *testing.T) {
+	sessions := []history.Session{
+		{Phase: "WORK", Completed: true},
+		{Phase: "WORK", Completed: true},
+		{Phase: "WORK", Completed: true},
+		{Phase: "WORK", Abandoned: true},
+		{Phase: "WORK"}, // skipped
+		{Phase: "SHORT_BREAK", Abandoned: true},
+	}
+	sum := Summarize(sessions)
+	if sum.Abandoned != 1 || sum.CompletionRate() != 0.75 {
+		t.Fatalf("want 1 abandoned and 75%%, got %d and %v", sum.Abandoned, sum.CompletionRate())
+	}
+}
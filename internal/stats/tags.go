@@ -0,0 +1,51 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// TagStat is the work done under one tag.
+type TagStat struct {
+	Tag       string
+	Pomodoros int           // completed work sessions
+	Focus     time.Duration // active time in work sessions
+}
+
+// ByTag aggregates work sessions per tag, most focus first. A session
+// with several tags counts toward each of them; untagged ones toward
+// none.
+func ByTag(sessions []history.Session) []TagStat {
+	byTag := map[string]*TagStat{}
+	for _, s := range sessions {
+		if s.Phase != core.PhaseWork.String() {
+			continue
+		}
+		for _, tag := range s.Tags {
+			ts := byTag[tag]
+			if ts == nil {
+				ts = &TagStat{Tag: tag}
+				byTag[tag] = ts
+			}
+			if s.Completed {
+				ts.Pomodoros++
+			}
+			ts.Focus += s.Active()
+		}
+	}
+	out := make([]TagStat, 0, len(byTag))
+	for _, ts := range byTag {
+		out = append(out, *ts)
+	}
+	slices.SortFunc(out, func(a, b TagStat) int {
+		if c := cmp.Compare(b.Focus, a.Focus); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Tag, b.Tag)
+	})
+	return out
+}
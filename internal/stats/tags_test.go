@@ -0,0 +1,31 @@
+package stats
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestByTag(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2025, 5, 1, h, 0, 0, 0, time.Local) }
+	work := func(h int, mins time.Duration, completed bool, tags ...string) history.Session {
+		return history.Session{Phase: "WORK", Start: at(h), End: at(h).Add(mins * time.Minute), Completed: completed, Tags: tags}
+	}
+	sessions := []history.Session{
+		work(9, 25, true, "client-a", "coding"),
+		work(10, 25, true, "coding"),
+		work(11, 10, false, "client-a"),
+		work(12, 25, true),
+		{Phase: "SHORT_BREAK", Start: at(13), End: at(13).Add(5 * time.Minute), Completed: true, Tags: []string{"coding"}},
+	}
+	got := ByTag(sessions)
+	want := []TagStat{
+		{Tag: "coding", Pomodoros: 2, Focus: 50 * time.Minute},
+		{Tag: "client-a", Pomodoros: 1, Focus: 35 * time.Minute},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+}
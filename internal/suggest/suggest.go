@@ -0,0 +1,69 @@
+// Package suggest picks something to do during a break.
+package suggest
+
+import (
+	"math/rand/v2"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// DefaultShort and DefaultLong are used for lists left unset.
+var (
+	DefaultShort = []string{
+		"Stand up and stretch",
+		"Drink a glass of water",
+		"Rest your eyes: look at something 20 feet away for 20 seconds",
+		"Roll your shoulders and neck",
+		"Take a few slow, deep breaths",
+		"Tidy your desk",
+	}
+	DefaultLong = []string{
+		"Go for a short walk",
+		"Make tea or coffee, away from the screen",
+		"Have a healthy snack",
+		"Do a few minutes of yoga or mobility",
+		"Step outside for some fresh air",
+		"Call or message a friend",
+	}
+)
+
+// Suggester picks an activity per break. The pick is random, but the
+// same break always gets the same one, so every place that shows it
+// agrees.
+type Suggester struct {
+	short, long []string
+	salt        uint64
+}
+
+// New returns a Suggester for the given lists; an empty one falls back
+// to its default.
+func New(short, long []string) *Suggester {
+	if len(short) == 0 {
+		short = DefaultShort
+	}
+	if len(long) == 0 {
+		long = DefaultLong
+	}
+	return &Suggester{short: short, long: long, salt: rand.Uint64()}
+}
+
+// For returns the suggestion for the break st is in, or "" outside a
+// break. A nil Suggester suggests nothing.
+func (s *Suggester) For(st core.State) string {
+	if s == nil || st.Idle() {
+		return ""
+	}
+	var list []string
+	switch st.Phase {
+	case core.PhaseShortBreak:
+		list = s.short
+	case core.PhaseLongBreak:
+		list = s.long
+	default:
+		return ""
+	}
+	// StartedAt moves on resume, so identify the break by its place in
+	// the run instead
+	r := rand.New(rand.NewPCG(s.salt, uint64(st.PomodoroDone)<<32|uint64(st.Step)))
+	return list[r.IntN(len(list))]
+}
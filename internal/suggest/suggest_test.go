@@ -0,0 +1,43 @@
+package suggest
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestSuggester_For(t *testing.T) {
+	s := New([]string{"stretch", "water"}, []string{"walk"})
+	start := time.Date(2025, 5, 1, 9, 25, 0, 0, time.UTC)
+	brk := core.State{Phase: core.PhaseShortBreak, StartedAt: start, PomodoroDone: 1}
+
+	tip := s.For(brk)
+	if !slices.Contains(s.short, tip) {
+		t.Fatalf("short break got %q", tip)
+	}
+	resumed := brk
+	resumed.StartedAt = start.Add(3 * time.Minute)
+	if got := s.For(resumed); got != tip {
+		t.Fatalf("resuming changed the suggestion from %q to %q", tip, got)
+	}
+	if got := s.For(core.State{Phase: core.PhaseLongBreak, StartedAt: start, PomodoroDone: 4}); got != "walk" {
+		t.Fatalf("long break got %q", got)
+	}
+	for _, st := range []core.State{
+		{Phase: core.PhaseWork, StartedAt: start},
+		{}, // idle
+	} {
+		if got := s.For(st); got != "" {
+			t.Fatalf("%+v got %q", st, got)
+		}
+	}
+	var none *Suggester
+	if got := none.For(brk); got != "" {
+		t.Fatalf("nil suggester got %q", got)
+	}
+	if d := New(nil, nil); !slices.Equal(d.short, DefaultShort) || !slices.Equal(d.long, DefaultLong) {
+		t.Fatal("empty lists should fall back to the defaults")
+	}
+}
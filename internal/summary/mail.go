@@ -0,0 +1,140 @@
+package summary
+
+import (
+	"bytes"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"html"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+)
+
+// Mailer sends mail through an SMTP server, upgrading to TLS with
+// STARTTLS when the server offers it, or from the start on port 465.
+type Mailer struct {
+	Addr               string // host:port
+	Username, Password string // no authentication without a username
+	From               string // e.g. "GoPomodoro <me@example.com>"
+	To                 []string
+}
+
+// Send mails text with subject. The body goes out as plain text and as
+// HTML in a fixed-width block, so charts stay aligned in any client.
+func (m Mailer) Send(subject, text string) error {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return fmt.Errorf("email: from %q: %w", m.From, err)
+	}
+	if len(m.To) == 0 {
+		return errors.New("email: no recipients")
+	}
+	to := make([]string, len(m.To))
+	for i, addr := range m.To {
+		a, err := mail.ParseAddress(addr)
+		if err != nil {
+			return fmt.Errorf("email: to %q: %w", addr, err)
+		}
+		to[i] = a.Address
+	}
+	msg, err := message(m.From, m.To, subject, text, time.Now())
+	if err != nil {
+		return err
+	}
+	host, port, err := net.SplitHostPort(m.Addr)
+	if err != nil {
+		return fmt.Errorf("email: smtp %q: %w", m.Addr, err)
+	}
+
+	conn, err := net.DialTimeout("tcp", m.Addr, 30*time.Second)
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(time.Minute))
+	tlsConfig := &tls.Config{ServerName: host}
+	if port == "465" {
+		conn = tls.Client(conn, tlsConfig)
+	}
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("email: %w", err)
+	}
+	defer c.Close()
+	if ok, _ := c.Extension("STARTTLS"); ok && port != "465" {
+		if err := c.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("email: %w", err)
+		}
+	}
+	if m.Username != "" {
+		if err := c.Auth(smtp.PlainAuth("", m.Username, m.Password, host)); err != nil {
+			return fmt.Errorf("email: %w", err)
+		}
+	}
+	if err := c.Mail(from.Address); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return fmt.Errorf("email: %s: %w", addr, err)
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return c.Quit()
+}
+
+// message builds a multipart/alternative mail with text as its plain
+// and HTML parts.
+func message(from string, to []string, subject, text string, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	parts := []struct{ typ, content string }{
+		{"text/plain; charset=utf-8", text},
+		{"text/html; charset=utf-8", `<pre style="font-family: monospace">` + html.EscapeString(text) + "</pre>\n"},
+	}
+	for _, p := range parts {
+		pw, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {p.typ},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qw := quotedprintable.NewWriter(pw)
+		if _, err := qw.Write([]byte(strings.ReplaceAll(p.content, "\n", "\r\n"))); err != nil {
+			return nil, err
+		}
+		if err := qw.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+	msg.Write(body.Bytes())
+	return msg.Bytes(), nil
+}
@@ -0,0 +1,84 @@
+package summary
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// schedule is a local time of day, every day or on one weekday.
+type schedule struct {
+	at      time.Duration // since midnight
+	weekday time.Weekday
+	weekly  bool
+}
+
+// parseDaily reads a time of day, "15:04".
+func parseDaily(s string) (schedule, error) {
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return schedule{}, fmt.Errorf("%q: want HH:MM", s)
+	}
+	return schedule{at: time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute}, nil
+}
+
+// parseWeekly reads a weekday and time of day, e.g. "Fri 17:00" or
+// "friday 17:00".
+func parseWeekly(s string) (schedule, error) {
+	day, at, ok := strings.Cut(strings.TrimSpace(s), " ")
+	if ok {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			name := strings.ToLower(d.String())
+			if l := strings.ToLower(day); l == name || l == name[:3] {
+				sc, err := parseDaily(strings.TrimSpace(at))
+				sc.weekday, sc.weekly = d, true
+				return sc, err
+			}
+		}
+	}
+	return schedule{}, fmt.Errorf("%q: want a weekday and HH:MM, e.g. \"Fri 17:00\"", s)
+}
+
+// next is the first scheduled time after now.
+func (sc schedule) next(now time.Time) time.Time {
+	y, m, d := now.Date()
+	h, mins := int(sc.at/time.Hour), int(sc.at%time.Hour/time.Minute)
+	if sc.weekly {
+		d += (int(sc.weekday) - int(now.Weekday()) + 7) % 7
+	}
+	next := time.Date(y, m, d, h, mins, 0, 0, now.Location())
+	if !next.After(now) {
+		step := 1
+		if sc.weekly {
+			step = 7
+		}
+		next = time.Date(y, m, d+step, h, mins, 0, 0, now.Location())
+	}
+	return next
+}
+
+// every calls fn at each scheduled time until ctx is done. It checks
+// the wall clock every minute rather than sleeping until the deadline,
+// so a suspended laptop catches up on wake; a time missed by more
+// than an hour is skipped, since a late report is noise.
+func every(ctx context.Context, sc schedule, clock func() time.Time, fn func(now time.Time)) {
+	next := sc.next(clock())
+	tick := time.NewTicker(time.Minute)
+	defer tick.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-tick.C:
+		}
+		now := clock()
+		if now.Before(next) {
+			continue
+		}
+		if now.Sub(next) < time.Hour {
+			fn(now)
+		}
+		next = sc.next(now)
+	}
+}
@@ -0,0 +1,154 @@
+// Package summary delivers an end-of-day report of the day's work as a
+// notification and/or a Markdown file, at a set time of day or on
+// demand, e.g. when the daemon shuts down.
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// Options configures a Summary.
+type Options struct {
+	// At is the local time of day to deliver the report, "15:04"; empty
+	// delivers it only through Deliver.
+	At string
+	// Path is a template for the report file, e.g.
+	// "~/pomodoro/{{.Date}}.md"; empty writes no file.
+	Path string
+	// Notify, if non-nil, receives the notification text.
+	Notify func(body string) error
+	// Goal is the daily pomodoro target, 0 for none.
+	Goal int
+	// Top is the number of tasks listed; default 3.
+	Top int
+	// List returns the history.
+	List    func() ([]history.Session, error)
+	OnError func(error)
+}
+
+// Summary builds and delivers day reports.
+type Summary struct {
+	at    *schedule // nil without a scheduled time
+	path  *template.Template
+	opts  Options
+	clock func() time.Time
+}
+
+// pathData is what the path template sees.
+type pathData struct {
+	Date string // 2006-01-02
+}
+
+// New checks opts and parses At and the path template.
+func New(opts Options) (*Summary, error) {
+	if opts.List == nil {
+		return nil, errors.New("summary: no history")
+	}
+	if opts.Path == "" && opts.Notify == nil {
+		return nil, errors.New("summary: nothing to deliver to, set a path or enable notify")
+	}
+	if opts.Top <= 0 {
+		opts.Top = 3
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	s := &Summary{opts: opts, clock: time.Now}
+	if opts.At != "" {
+		at, err := parseDaily(opts.At)
+		if err != nil {
+			return nil, fmt.Errorf("summary: at %w", err)
+		}
+		s.at = &at
+	}
+	if opts.Path != "" {
+		path, err := template.New("path").Option("missingkey=error").Parse(opts.Path)
+		if err != nil {
+			return nil, fmt.Errorf("summary path: %w", err)
+		}
+		s.path = path
+	}
+	return s, nil
+}
+
+// Next is the first scheduled delivery after now, or the zero time
+// without At.
+func (s *Summary) Next(now time.Time) time.Time {
+	if s.at == nil {
+		return time.Time{}
+	}
+	return s.at.next(now)
+}
+
+// Run delivers the report daily at the configured time until ctx is
+// done.
+func (s *Summary) Run(ctx context.Context) {
+	if s.at == nil {
+		return
+	}
+	every(ctx, *s.at, s.clock, func(now time.Time) {
+		if err := s.Deliver(now); err != nil {
+			s.opts.OnError(err)
+		}
+	})
+}
+
+// Deliver reports on day's calendar day through every configured
+// channel.
+func (s *Summary) Deliver(day time.Time) error {
+	sessions, err := s.opts.List()
+	if err != nil {
+		return err
+	}
+	r := stats.Report(sessions, day.Local(), s.opts.Goal)
+	var errs []error
+	if s.path != nil {
+		errs = append(errs, s.write(r))
+	}
+	if s.opts.Notify != nil {
+		errs = append(errs, s.opts.Notify(r.Text(s.opts.Top)))
+	}
+	return errors.Join(errs...)
+}
+
+// write renders r to its file, replacing an earlier report of the day.
+func (s *Summary) write(r stats.DayReport) error {
+	var b strings.Builder
+	if err := s.path.Execute(&b, pathData{Date: r.Date.Format(time.DateOnly)}); err != nil {
+		return fmt.Errorf("summary path: %w", err)
+	}
+	path, err := expandHome(b.String())
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".gopomodoro~"
+	if err := os.WriteFile(tmp, []byte(r.Markdown(s.opts.Top)), 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// expandHome replaces a leading "~" with the home directory.
+func expandHome(path string) (string, error) {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		path = filepath.Join(home, path[1:])
+	}
+	return path, nil
+}
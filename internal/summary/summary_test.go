@@ -0,0 +1,64 @@
+package summary
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestNext(t *testing.T) {
+	s, err := New(Options{At: "18:30", Notify: func(string) error { return nil }, List: func() ([]history.Session, error) { return nil, nil }})
+	if err != nil {
+		t.Fatal(err)
+	}
+	morning := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC)
+	if got, want := s.Next(morning), time.Date(2025, 5, 1, 18, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("from morning: want %v, got %v", want, got)
+	}
+	due := time.Date(2025, 5, 1, 18, 30, 0, 0, time.UTC)
+	if got, want := s.Next(due), time.Date(2025, 5, 2, 18, 30, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("at the time: want %v, got %v", want, got)
+	}
+
+	if _, err := New(Options{At: "6pm", Notify: func(string) error { return nil }, List: s.opts.List}); err == nil {
+		t.Fatal("accepted a bad time")
+	}
+	if _, err := New(Options{List: s.opts.List}); err == nil {
+		t.Fatal("accepted no delivery")
+	}
+}
+
+func TestDeliver(t *testing.T) {
+	day := time.Date(2025, 5, 1, 17, 0, 0, 0, time.Local)
+	sessions := []history.Session{
+		{Phase: "WORK", Start: day.Add(-time.Hour), End: day.Add(-35 * time.Minute), Completed: true},
+	}
+	dir := t.TempDir()
+	var notified string
+	s, err := New(Options{
+		Path:   filepath.Join(dir, "{{.Date}}.md"),
+		Notify: func(body string) error { notified = body; return nil },
+		Goal:   1,
+		List:   func() ([]history.Session, error) { return sessions, nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Deliver(day); err != nil {
+		t.Fatal(err)
+	}
+	if notified != "1 pomodoro, 25m0s focus · goal 1/1 ✓" {
+		t.Fatalf("notification: got %q", notified)
+	}
+	doc, err := os.ReadFile(filepath.Join(dir, "2025-05-01.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(doc), "- Pomodoros: 1 of 1 (100%), goal reached") {
+		t.Fatalf("report:\n%s", doc)
+	}
+}
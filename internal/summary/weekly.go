@@ -0,0 +1,91 @@
+package summary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// WeeklyOptions configures a Weekly digest.
+type WeeklyOptions struct {
+	// At is the weekday and local time to send the digest, e.g.
+	// "Fri 17:00".
+	At string
+	// Send delivers the digest, e.g. Mailer.Send.
+	Send func(subject, text string) error
+	// Goal is the daily pomodoro target, 0 for none.
+	Goal int
+	// Top is the number of tasks listed; default 5.
+	Top int
+	// List returns the history.
+	List    func() ([]history.Session, error)
+	OnError func(error)
+}
+
+// Weekly sends a digest of the past seven days once a week.
+type Weekly struct {
+	at    schedule
+	opts  WeeklyOptions
+	clock func() time.Time
+}
+
+// NewWeekly checks opts and parses At.
+func NewWeekly(opts WeeklyOptions) (*Weekly, error) {
+	if opts.List == nil {
+		return nil, errors.New("weekly: no history")
+	}
+	if opts.Send == nil {
+		return nil, errors.New("weekly: nowhere to send the digest")
+	}
+	if opts.Top <= 0 {
+		opts.Top = 5
+	}
+	if opts.OnError == nil {
+		opts.OnError = func(error) {}
+	}
+	at, err := parseWeekly(opts.At)
+	if err != nil {
+		return nil, fmt.Errorf("weekly: at %w", err)
+	}
+	return &Weekly{at: at, opts: opts, clock: time.Now}, nil
+}
+
+// Next is the first scheduled digest after now.
+func (w *Weekly) Next(now time.Time) time.Time {
+	return w.at.next(now)
+}
+
+// Run sends the digest every week at the configured time until ctx is
+// done.
+func (w *Weekly) Run(ctx context.Context) {
+	every(ctx, w.at, w.clock, func(now time.Time) {
+		if err := w.Deliver(now); err != nil {
+			w.opts.OnError(err)
+		}
+	})
+}
+
+// Digest renders the subject and text of the digest for the seven days
+// ending on end's day.
+func (w *Weekly) Digest(end time.Time) (subject, text string, err error) {
+	sessions, err := w.opts.List()
+	if err != nil {
+		return "", "", err
+	}
+	r := stats.Week(sessions, end.Local(), w.opts.Goal)
+	subject = fmt.Sprintf("GoPomodoro weekly: %d pomodoros, %s focus", r.Pomodoros, r.Focus.Round(time.Minute))
+	return subject, r.Text(w.opts.Top), nil
+}
+
+// Deliver sends the digest for the seven days ending on end's day.
+func (w *Weekly) Deliver(end time.Time) error {
+	subject, text, err := w.Digest(end)
+	if err != nil {
+		return err
+	}
+	return w.opts.Send(subject, text)
+}
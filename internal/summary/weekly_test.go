@@ -0,0 +1,119 @@
+package summary
+
+import (
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestParseWeekly(t *testing.T) {
+	thu := time.Date(2025, 5, 1, 9, 0, 0, 0, time.UTC) // a Thursday
+	for _, tt := range []struct {
+		at   string
+		want time.Time
+	}{
+		{"Fri 17:00", time.Date(2025, 5, 2, 17, 0, 0, 0, time.UTC)},
+		{"thursday 10:00", time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC)},
+		{"Thu 08:00", time.Date(2025, 5, 8, 8, 0, 0, 0, time.UTC)},
+		{"mon 09:00", time.Date(2025, 5, 5, 9, 0, 0, 0, time.UTC)},
+	} {
+		sc, err := parseWeekly(tt.at)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.at, err)
+		}
+		if got := sc.next(thu); !got.Equal(tt.want) {
+			t.Fatalf("%s: want %v, got %v", tt.at, tt.want, got)
+		}
+	}
+	for _, bad := range []string{"17:00", "Fri", "Someday 17:00", "Fri 5pm"} {
+		if _, err := parseWeekly(bad); err == nil {
+			t.Fatalf("accepted %q", bad)
+		}
+	}
+}
+
+func TestWeekly_Deliver(t *testing.T) {
+	end := time.Date(2025, 5, 1, 17, 0, 0, 0, time.Local)
+	sessions := []history.Session{
+		{Phase: "WORK", Start: end.Add(-time.Hour), End: end.Add(-35 * time.Minute), Completed: true},
+		{Phase: "WORK", Start: end.AddDate(0, 0, -2), End: end.AddDate(0, 0, -2).Add(25 * time.Minute), Completed: true},
+		{Phase: "WORK", Start: end.AddDate(0, 0, -9), End: end.AddDate(0, 0, -9).Add(25 * time.Minute), Completed: true},
+	}
+	var subject, text string
+	w, err := NewWeekly(WeeklyOptions{
+		At:   "Fri 17:00",
+		Send: func(s, body string) error { subject, text = s, body; return nil },
+		List: func() ([]history.Session, error) { return sessions, nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Deliver(end); err != nil {
+		t.Fatal(err)
+	}
+	if subject != "GoPomodoro weekly: 2 pomodoros, 50m0s focus" {
+		t.Fatalf("subject: got %q", subject)
+	}
+	for _, s := range []string{"Week of Apr 25 – May 1", "Pomodoros:     2 (+1 vs. last week)", "Thu 05-01 ############################## "} {
+		if !strings.Contains(text, s) {
+			t.Fatalf("digest lacks %q:\n%s", s, text)
+		}
+	}
+}
+
+func TestMailer_Send(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	got := make(chan []string, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		tp := textproto.NewConn(conn)
+		_ = tp.PrintfLine("220 fake ESMTP")
+		var cmds []string
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				break
+			}
+			cmds = append(cmds, line)
+			switch verb, _, _ := strings.Cut(line, " "); strings.ToUpper(verb) {
+			case "EHLO":
+				_ = tp.PrintfLine("250 fake")
+			case "DATA":
+				_ = tp.PrintfLine("354 go ahead")
+				data, _ := tp.ReadDotLines()
+				cmds = append(cmds, data...)
+				_ = tp.PrintfLine("250 queued")
+			case "QUIT":
+				_ = tp.PrintfLine("221 bye")
+				got <- cmds
+				return
+			default:
+				_ = tp.PrintfLine("250 ok")
+			}
+		}
+		got <- cmds
+	}()
+
+	m := Mailer{Addr: ln.Addr().String(), From: "GoPomodoro <me@example.com>", To: []string{"me@example.com"}}
+	if err := m.Send("Weekly ✓", "Mon #### 4\n"); err != nil {
+		t.Fatal(err)
+	}
+	cmds := strings.Join(<-got, "\n")
+	for _, s := range []string{"MAIL FROM:<me@example.com>", "RCPT TO:<me@example.com>", "Subject: =?utf-8?q?Weekly_=E2=9C=93?=", "Content-Type: text/html; charset=utf-8", "Mon #### 4"} {
+		if !strings.Contains(cmds, s) {
+			t.Fatalf("session lacks %q:\n%s", s, cmds)
+		}
+	}
+}
@@ -0,0 +1,178 @@
+package team
+
+import (
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+const writeWait = 5 * time.Second
+
+// Host shares an engine with the members that join it. Every member
+// controls the shared timer; their calls arrive as commands. Joining
+// takes "Authorization: Bearer <token>" with the session's token.
+type Host struct {
+	engine   *core.PomodoroEngine
+	name     string
+	token    string
+	upgrader websocket.Upgrader
+	onErr    func(error)
+
+	mu    sync.Mutex
+	peers map[*peer]bool
+}
+
+// peer is a joined member.
+type peer struct {
+	name string
+	send chan message
+}
+
+// NewHost hosts engine, with name first on the member list, for the
+// members holding token. Subscribe its Handle method to the engine and
+// serve it at Path.
+func NewHost(engine *core.PomodoroEngine, name, token string, onErr func(error)) (*Host, error) {
+	if token == "" {
+		return nil, errors.New("team: the session needs a token")
+	}
+	if onErr == nil {
+		onErr = func(error) {}
+	}
+	return &Host{
+		engine: engine,
+		name:   name,
+		token:  token,
+		onErr:  onErr,
+		// members are other GoPomodoro instances, which send no Origin;
+		// one that does is a browser page, which has no business here
+		upgrader: websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return r.Header.Get("Origin") == "" }},
+		peers:    map[*peer]bool{},
+	}, nil
+}
+
+// Members lists the host and the joined members.
+func (h *Host) Members() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.membersLocked()
+}
+
+func (h *Host) membersLocked() []string {
+	names := []string{h.name}
+	for p := range h.peers {
+		if p.name != "" {
+			names = append(names, p.name)
+		}
+	}
+	// map order is random; keep the host first and the rest stable
+	slices.Sort(names[1:])
+	return names
+}
+
+// Handle relays an engine event to every member.
+func (h *Host) Handle(ev core.Event) {
+	h.broadcast(message{Type: "event", Event: encodeEvent(ev)})
+}
+
+func (h *Host) broadcast(msg message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for p := range h.peers {
+		h.sendLocked(p, msg)
+	}
+}
+
+// sendLocked queues msg for p, dropping a member too slow to keep up.
+func (h *Host) sendLocked(p *peer, msg message) {
+	select {
+	case p.send <- msg:
+	default:
+		delete(h.peers, p)
+		close(p.send)
+	}
+}
+
+// ServeHTTP accepts a member: it waits for the hello, sends the current
+// state, then applies the member's commands until it leaves.
+func (h *Host) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) != 1 {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="gopomodoro"`)
+		http.Error(w, "unknown token", http.StatusUnauthorized)
+		return
+	}
+	conn, err := h.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	var hello message
+	_ = conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	if err := conn.ReadJSON(&hello); err != nil || hello.Type != "hello" || hello.Name == "" {
+		return
+	}
+	_ = conn.SetReadDeadline(time.Time{})
+
+	p := &peer{name: hello.Name, send: make(chan message, 32)}
+	h.mu.Lock()
+	// the state goes first, before any event queued for the member
+	p.send <- message{Type: "state", Event: encodeEvent(core.Event{
+		Kind:      core.EventUpdate,
+		State:     h.engine.State(),
+		Remaining: h.engine.Remaining(),
+		Elapsed:   h.engine.Elapsed(),
+		At:        time.Now(),
+	})}
+	h.peers[p] = true
+	members := h.membersLocked()
+	for q := range h.peers {
+		h.sendLocked(q, message{Type: "members", Members: members})
+	}
+	h.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// closing ends the read loop below too, e.g. for a dropped member
+		defer conn.Close()
+		for msg := range p.send {
+			_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if conn.WriteJSON(msg) != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		var msg message
+		if err := conn.ReadJSON(&msg); err != nil {
+			break
+		}
+		if msg.Type == "command" && msg.Command != nil {
+			if err := h.engine.Do(*msg.Command); err != nil {
+				h.onErr(err)
+			}
+		}
+	}
+
+	h.mu.Lock()
+	if h.peers[p] {
+		delete(h.peers, p)
+		close(p.send)
+	}
+	members = h.membersLocked()
+	for q := range h.peers {
+		h.sendLocked(q, message{Type: "members", Members: members})
+	}
+	h.mu.Unlock()
+	<-done
+}
@@ -0,0 +1,193 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Member joins a hosted session and keeps a local engine in step with
+// the host's. While connected the local engine follows the host; when
+// the connection drops it runs on by itself until it's back.
+type Member struct {
+	url    string
+	name   string
+	token  string
+	engine *core.PomodoroEngine
+	onErr  func(error)
+
+	mu      sync.Mutex
+	members []string
+}
+
+// NewMember joins the host at addr ("host:port" or a URL) as name
+// with the session's token, once Run runs.
+func NewMember(addr, name, token string, engine *core.PomodoroEngine, onErr func(error)) (*Member, error) {
+	u, err := wsURL(addr)
+	if err != nil {
+		return nil, err
+	}
+	if token == "" {
+		return nil, errors.New("team: joining needs the session's token")
+	}
+	if onErr == nil {
+		onErr = func(error) {}
+	}
+	return &Member{url: u, name: name, token: token, engine: engine, onErr: onErr}, nil
+}
+
+// wsURL turns addr into the session's WebSocket URL.
+func wsURL(addr string) (string, error) {
+	if !strings.Contains(addr, "://") {
+		addr = "ws://" + addr
+	}
+	u, err := url.Parse(addr)
+	if err != nil {
+		return "", fmt.Errorf("team: %w", err)
+	}
+	switch u.Scheme {
+	case "http", "ws":
+		u.Scheme = "ws"
+	case "https", "wss":
+		u.Scheme = "wss"
+	default:
+		return "", fmt.Errorf("team: can't join %s", addr)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = Path
+	}
+	return u.String(), nil
+}
+
+// Members lists the session's members, host first; empty while
+// disconnected.
+func (m *Member) Members() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.members...)
+}
+
+func (m *Member) setMembers(names []string) {
+	m.mu.Lock()
+	m.members = names
+	m.mu.Unlock()
+}
+
+// Run stays in the session until ctx is done, reconnecting with a
+// growing delay when the connection drops.
+func (m *Member) Run(ctx context.Context) {
+	delay := time.Second
+	for {
+		joined, err := m.session(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		if joined {
+			delay = time.Second
+		}
+		if err != nil && (joined || delay == time.Second) {
+			// once per outage
+			m.onErr(err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, 30*time.Second)
+	}
+}
+
+// session is one connection to the host. joined reports whether the
+// host's state arrived.
+func (m *Member) session(ctx context.Context) (joined bool, err error) {
+	dialer := websocket.Dialer{HandshakeTimeout: 10 * time.Second}
+	header := http.Header{"Authorization": {"Bearer " + m.token}}
+	conn, resp, err := dialer.DialContext(ctx, m.url, header)
+	if err != nil {
+		switch {
+		case resp != nil && resp.StatusCode == http.StatusNotFound:
+			return false, fmt.Errorf("team: %s isn't hosting a session", m.url)
+		case resp != nil && resp.StatusCode == http.StatusUnauthorized:
+			return false, fmt.Errorf("team: %s refused the session token", m.url)
+		}
+		return false, fmt.Errorf("team: %w", err)
+	}
+	defer conn.Close()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	context.AfterFunc(ctx, func() { conn.Close() })
+
+	var wmu sync.Mutex
+	write := func(msg message) error {
+		wmu.Lock()
+		defer wmu.Unlock()
+		_ = conn.SetWriteDeadline(time.Now().Add(writeWait))
+		return conn.WriteJSON(msg)
+	}
+	if err := write(message{Type: "hello", Name: m.name}); err != nil {
+		return false, fmt.Errorf("team: %w", err)
+	}
+	// commands go out in order, without holding up the caller (often
+	// the UI); a late forward after the session is harmless, so the
+	// channel is never closed
+	cmds := make(chan core.Command, 16)
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case cmd := <-cmds:
+				if err := write(message{Type: "command", Command: &cmd}); err != nil {
+					m.onErr(fmt.Errorf("team: %w", err))
+				}
+			}
+		}
+	}()
+	defer func() {
+		if joined {
+			m.engine.Follow(nil)
+			m.setMembers(nil)
+		}
+	}()
+	for {
+		var msg message
+		if err := conn.ReadJSON(&msg); err != nil {
+			return joined, fmt.Errorf("team: lost the host: %w", err)
+		}
+		switch msg.Type {
+		case "state", "event":
+			if msg.Event == nil {
+				continue
+			}
+			ev, ok := msg.Event.decode()
+			if !ok {
+				continue
+			}
+			if !joined {
+				joined = true
+				m.engine.Follow(func(cmd core.Command) {
+					select {
+					case cmds <- cmd:
+					default:
+						m.onErr(fmt.Errorf("team: dropped %s, the host isn't keeping up", cmd.Action))
+					}
+				})
+			}
+			if err := m.engine.Sync(ev); err != nil {
+				return joined, err
+			}
+		case "members":
+			m.setMembers(msg.Members)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package team runs shared pomodoro sessions: one instance hosts, over
+// a WebSocket on its HTTP API, and the others join and follow its
+// timer, for mob programming or study-with-me groups.
+package team
+
+import (
+	"errors"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Path is where the host serves the session.
+const Path = "/team"
+
+// message is one WebSocket message, in either direction.
+type message struct {
+	// Type is hello (member → host, with Name), state (the host's
+	// state on joining), event, members or command (member → host).
+	Type    string        `json:"type"`
+	Name    string        `json:"name,omitempty"`
+	Event   *event        `json:"event,omitempty"`
+	Members []string      `json:"members,omitempty"`
+	Command *core.Command `json:"command,omitempty"`
+}
+
+// event is a core.Event on the wire.
+type event struct {
+	Kind      string        `json:"kind"`
+	State     core.State    `json:"state"`
+	Remaining time.Duration `json:"remaining"`
+	Elapsed   time.Duration `json:"elapsed,omitempty"`
+	At        time.Time     `json:"at"`
+	Warning   time.Duration `json:"warning,omitempty"`
+	Extension time.Duration `json:"extension,omitempty"`
+	Refusal   string        `json:"refusal,omitempty"`
+}
+
+func encodeEvent(ev core.Event) *event {
+	e := &event{
+		Kind:      ev.Kind.String(),
+		State:     ev.State,
+		Remaining: ev.Remaining,
+		Elapsed:   ev.Elapsed,
+		At:        ev.At,
+		Warning:   ev.Warning,
+		Extension: ev.Extension,
+	}
+	if ev.Refusal != nil {
+		e.Refusal = ev.Refusal.Error()
+	}
+	return e
+}
+
+// kinds maps event kind names back to kinds.
+var kinds = func() map[string]core.EventKind {
+	m := map[string]core.EventKind{}
+	for k := core.EventKind(0); k.String() != "unknown"; k++ {
+		m[k.String()] = k
+	}
+	return m
+}()
+
+func (e *event) decode() (core.Event, bool) {
+	kind, ok := kinds[e.Kind]
+	if !ok {
+		return core.Event{}, false
+	}
+	ev := core.Event{
+		Kind:      kind,
+		State:     e.State,
+		Remaining: e.Remaining,
+		Elapsed:   e.Elapsed,
+		At:        e.At,
+		Warning:   e.Warning,
+		Extension: e.Extension,
+	}
+	if e.Refusal != "" {
+		ev.Refusal = errors.New(e.Refusal)
+	}
+	return ev, true
+}
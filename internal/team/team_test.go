@@ -0,0 +1,167 @@
+package team
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func waitFor(t *testing.T, what string, ok func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(2 * time.Second); !ok(); time.Sleep(5 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting for %s", what)
+		}
+	}
+}
+
+func TestSession(t *testing.T) {
+	cfg := core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4}
+	hostEngine := core.New(cfg)
+	defer hostEngine.Stop()
+	host, err := NewHost(hostEngine, "alice", "s3cret", func(err error) { t.Error(err) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer hostEngine.Subscribe(host.Handle)()
+	ts := httptest.NewServer(host)
+	defer ts.Close()
+
+	hostEngine.Start()
+	local := core.New(cfg)
+	member, err := NewMember(ts.URL, "bob", "s3cret", local, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		member.Run(ctx)
+	}()
+
+	// joining mid-phase picks up the running pomodoro
+	waitFor(t, "the host's state", func() bool { return !local.State().Idle() })
+	if d := local.State().EndsAt.Sub(hostEngine.State().EndsAt); d.Abs() > time.Second {
+		t.Fatalf("deadlines %v apart", d)
+	}
+	waitFor(t, "the member list", func() bool { return slices.Equal(member.Members(), []string{"alice", "bob"}) })
+	if got := host.Members(); !slices.Equal(got, []string{"alice", "bob"}) {
+		t.Fatalf("host members %v", got)
+	}
+
+	// a member's interruption is logged on the host, and comes back
+	interrupts := make(chan core.Interruption, 1)
+	defer hostEngine.Subscribe(func(ev core.Event) {
+		if ev.Kind == core.EventInterrupt {
+			interrupts <- *ev.Interruption
+		}
+	})()
+	if err := local.Interrupt(core.InterruptExternal, "phone"); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case it := <-interrupts:
+		if it.Kind != core.InterruptExternal || it.Note != "phone" {
+			t.Fatalf("host logged %+v", it)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timed out waiting for the host to log the interruption")
+	}
+	waitFor(t, "the interruption to come back", func() bool { return local.State().Interruptions == 1 })
+
+	// either side controls the shared timer
+	local.Pause()
+	waitFor(t, "the host to pause", func() bool { return hostEngine.State().Paused })
+	waitFor(t, "the pause to come back", func() bool { return local.State().Paused })
+	hostEngine.Skip()
+	waitFor(t, "the skip", func() bool { return local.State().Phase == core.PhaseShortBreak })
+
+	cancel()
+	<-done
+	waitFor(t, "bob to leave", func() bool { return len(host.Members()) == 1 })
+	// on its own again, the local timer runs by itself
+	local.Stop()
+	if !local.State().Idle() {
+		t.Fatal("the member still forwards after leaving")
+	}
+}
+
+func TestHost_Refuses(t *testing.T) {
+	engine := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer engine.Stop()
+	if _, err := NewHost(engine, "alice", "", nil); err == nil {
+		t.Fatal("hosted without a token")
+	}
+	host, err := NewHost(engine, "alice", "s3cret", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ts := httptest.NewServer(host)
+	defer ts.Close()
+	u, _ := wsURL(ts.URL)
+
+	for name, tc := range map[string]struct {
+		header http.Header
+		status int
+	}{
+		"no token":       {http.Header{}, http.StatusUnauthorized},
+		"wrong token":    {http.Header{"Authorization": {"Bearer guess"}}, http.StatusUnauthorized},
+		"a web page":     {http.Header{"Authorization": {"Bearer s3cret"}, "Origin": {"https://evil.example"}}, http.StatusForbidden},
+		"same host page": {http.Header{"Authorization": {"Bearer s3cret"}, "Origin": {ts.URL}}, http.StatusForbidden},
+	} {
+		conn, resp, err := websocket.DefaultDialer.Dial(u, tc.header)
+		if err == nil {
+			conn.Close()
+			t.Errorf("%s: joined", name)
+			continue
+		}
+		if resp == nil || resp.StatusCode != tc.status {
+			t.Errorf("%s: %v, want %d", name, err, tc.status)
+		}
+	}
+
+	// a member with the wrong token says so, once
+	errs := make(chan error, 4)
+	local := core.New(core.Config{Work: time.Minute})
+	defer local.Stop()
+	m, err := NewMember(ts.URL, "bob", "guess", local, func(err error) { errs <- err })
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go m.Run(ctx)
+	select {
+	case err := <-errs:
+		if !strings.Contains(err.Error(), "refused the session token") {
+			t.Fatalf("member error %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("no error for the wrong token")
+	}
+	if got := host.Members(); len(got) != 1 {
+		t.Fatalf("members %v", got)
+	}
+}
+
+func TestWSURL(t *testing.T) {
+	for in, want := range map[string]string{
+		"10.0.0.2:7767":                "ws://10.0.0.2:7767/team",
+		"http://host:7767":             "ws://host:7767/team",
+		"https://pomo.example.com/":    "wss://pomo.example.com/team",
+		"wss://pomo.example.com/team2": "wss://pomo.example.com/team2",
+	} {
+		if got, err := wsURL(in); err != nil || got != want {
+			t.Errorf("wsURL(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+}
@@ -0,0 +1,17 @@
+//go:build tray
+
+package tray
+
+import "fyne.io/systray"
+
+// On macOS the countdown itself sits in the menu bar ("🍅 12:34"); its
+// emoji stands in for an icon, which keeps the item narrow.
+
+func setIcon() {}
+
+func setTitle(text string, idle bool) {
+	if idle {
+		text = "🍅"
+	}
+	systray.SetTitle(text)
+}
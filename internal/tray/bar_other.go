@@ -0,0 +1,11 @@
+//go:build tray && !darwin
+
+package tray
+
+import "fyne.io/systray"
+
+func setIcon() { systray.SetIcon(icon()) }
+
+// setTitle labels the icon where the tray supports it (KDE, some
+// AppIndicator hosts); elsewhere only the tooltip shows the time.
+func setTitle(text string, _ bool) { systray.SetTitle(text) }
@@ -0,0 +1,9 @@
+// Package tray shows the timer as a system tray icon with a menu to
+// control it. The real implementation needs the "tray" build tag
+// (go build -tags tray); without it Run returns ErrUnsupported.
+package tray
+
+import "errors"
+
+// ErrUnsupported is returned by Run in builds without the tray tag.
+var ErrUnsupported = errors.New("built without tray support; rebuild with -tags tray")
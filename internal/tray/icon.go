@@ -0,0 +1,37 @@
+//go:build tray && !darwin
+
+package tray
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+)
+
+// iconSize is the edge of the generated icon in pixels.
+const iconSize = 32
+
+// tomatoPNG draws a small tomato: a red disc with a green stalk.
+func tomatoPNG() []byte {
+	img := image.NewNRGBA(image.Rect(0, 0, iconSize, iconSize))
+	red := color.NRGBA{R: 0xe5, G: 0x39, B: 0x35, A: 0xff}
+	green := color.NRGBA{R: 0x43, G: 0xa0, B: 0x47, A: 0xff}
+	c, r := float64(iconSize)/2, float64(iconSize)/2-2
+	for y := range iconSize {
+		for x := range iconSize {
+			dx, dy := float64(x)-c+0.5, float64(y)-c-1.5
+			if dx*dx+dy*dy <= r*r*0.85 {
+				img.Set(x, y, red)
+			}
+		}
+	}
+	for y := 1; y < 8; y++ {
+		for x := iconSize/2 - 5 + y/2; x < iconSize/2+5-y/2; x++ {
+			img.Set(x, y, green)
+		}
+	}
+	var buf bytes.Buffer
+	_ = png.Encode(&buf, img)
+	return buf.Bytes()
+}
@@ -0,0 +1,6 @@
+//go:build tray && !windows && !darwin
+
+package tray
+
+// icon is the tray icon; macOS and the Linux StatusNotifier take PNG.
+func icon() []byte { return tomatoPNG() }
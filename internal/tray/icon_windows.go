@@ -0,0 +1,24 @@
+//go:build tray && windows
+
+package tray
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// icon wraps the PNG in an .ico container, which the Windows tray
+// requires.
+func icon() []byte {
+	img := tomatoPNG()
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	// ICONDIR: reserved, type 1 (icon), one image
+	_ = binary.Write(&buf, le, [3]uint16{0, 1, 1})
+	// ICONDIRENTRY: size, no palette, 1 plane, 32 bpp, data length, offset
+	buf.Write([]byte{iconSize, iconSize, 0, 0})
+	_ = binary.Write(&buf, le, [2]uint16{1, 32})
+	_ = binary.Write(&buf, le, [2]uint32{uint32(len(img)), 6 + 16})
+	buf.Write(img)
+	return buf.Bytes()
+}
@@ -0,0 +1,34 @@
+package tray
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Status is the short text shown next to the icon, e.g. "🍅 12:34".
+// elapsed is the engine's Elapsed, counted up past deadlines and in open
+// flow phases.
+func Status(st core.State, remaining, elapsed time.Duration) string {
+	switch {
+	case st.Idle():
+		return "Idle"
+	case st.Overtime:
+		return "⏰ +" + mmss(elapsed-st.Length)
+	case st.Open && st.Paused:
+		return "⏸ +" + mmss(elapsed)
+	case st.Open:
+		return "🍅 +" + mmss(elapsed)
+	case st.Paused:
+		return "⏸ " + mmss(remaining)
+	case st.Phase == core.PhaseWork:
+		return "🍅 " + mmss(remaining)
+	}
+	return "☕ " + mmss(remaining)
+}
+
+func mmss(d time.Duration) string {
+	s := int(max(d, 0).Round(time.Second) / time.Second)
+	return fmt.Sprintf("%02d:%02d", s/60, s%60)
+}
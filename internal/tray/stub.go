@@ -0,0 +1,14 @@
+//go:build !tray
+
+package tray
+
+import (
+	"context"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Run is unavailable without the tray build tag.
+func Run(context.Context, *core.PomodoroEngine) error {
+	return ErrUnsupported
+}
@@ -0,0 +1,117 @@
+//go:build tray
+
+package tray
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fyne.io/systray"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Run shows the tray icon for engine and blocks until Quit is picked
+// or ctx is done. It must be called from the main goroutine.
+func Run(ctx context.Context, engine *core.PomodoroEngine) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	systray.Run(func() { ready(ctx, engine) }, cancel)
+	return nil
+}
+
+// menu holds the items whose state follows the engine.
+type menu struct {
+	status, start, pause, skip, stop *systray.MenuItem
+}
+
+func ready(ctx context.Context, engine *core.PomodoroEngine) {
+	setIcon()
+	setTitle("GoPomodoro", true)
+	m := menu{
+		status: systray.AddMenuItem("Idle", ""),
+		start:  systray.AddMenuItem("Start", "Start or resume the timer"),
+		pause:  systray.AddMenuItem("Pause", "Pause the timer"),
+		skip:   systray.AddMenuItem("Skip", "End the current phase and move on"),
+		stop:   systray.AddMenuItem("Stop", "Reset the timer"),
+	}
+	m.status.Disable()
+	systray.AddSeparator()
+	quit := systray.AddMenuItem("Quit", "Quit GoPomodoro")
+
+	changed := make(chan struct{}, 1)
+	unsubscribe := engine.Subscribe(func(core.Event) {
+		select {
+		case changed <- struct{}{}:
+		default:
+		}
+	})
+	go func() {
+		defer unsubscribe()
+		tick := time.NewTicker(time.Second)
+		defer tick.Stop()
+		m.update(engine)
+		for {
+			select {
+			case <-ctx.Done():
+				systray.Quit()
+				return
+			case <-quit.ClickedCh:
+				systray.Quit()
+				return
+			case <-m.start.ClickedCh:
+				st := engine.State()
+				switch {
+				case st.Idle():
+					engine.Start()
+				case st.Overtime, st.Open && !st.Paused:
+					engine.Acknowledge()
+				default:
+					engine.Resume()
+				}
+			case <-m.pause.ClickedCh:
+				engine.Pause()
+			case <-m.skip.ClickedCh:
+				engine.Skip()
+			case <-m.stop.ClickedCh:
+				engine.Stop()
+			case <-changed:
+			case <-tick.C:
+			}
+			m.update(engine)
+		}
+	}()
+}
+
+// update shows the time left in the title and tooltip and enables the
+// items that make sense now.
+func (m menu) update(engine *core.PomodoroEngine) {
+	st := engine.State()
+	idle := st.Idle()
+	text := Status(st, engine.Remaining(), engine.Elapsed())
+	setTitle(text, idle)
+	systray.SetTooltip("GoPomodoro: " + text)
+	m.status.SetTitle(fmt.Sprintf("%s · %d done", text, st.PomodoroDone))
+
+	switch {
+	case idle:
+		m.start.SetTitle("Start")
+	case st.Overtime, st.Open && !st.Paused:
+		m.start.SetTitle("Take break")
+	default:
+		m.start.SetTitle("Resume")
+	}
+	enable(m.start, idle || st.Paused || st.Overtime || st.Open)
+	enable(m.pause, !idle && !st.Paused && !st.Overtime)
+	enable(m.skip, !idle)
+	enable(m.stop, !idle)
+}
+
+func enable(item *systray.MenuItem, on bool) {
+	if on {
+		item.Enable()
+	} else {
+		item.Disable()
+	}
+}
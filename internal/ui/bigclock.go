@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// font is a 3x5 pixel font for the big clock; '#' is lit.
+var font = map[rune][5]string{
+	'0': {"###", "#.#", "#.#", "#.#", "###"},
+	'1': {".#.", "##.", ".#.", ".#.", "###"},
+	'2': {"###", "..#", "###", "#..", "###"},
+	'3': {"###", "..#", "###", "..#", "###"},
+	'4': {"#.#", "#.#", "###", "..#", "..#"},
+	'5': {"###", "#..", "###", "..#", "###"},
+	'6': {"###", "#..", "###", "#.#", "###"},
+	'7': {"###", "..#", "..#", "..#", "..#"},
+	'8': {"###", "#.#", "###", "#.#", "###"},
+	'9': {"###", "#.#", "###", "..#", "###"},
+	':': {".", "#", ".", "#", "."},
+}
+
+const fontRows = 5
+
+// clockText formats d as M:SS, or H:MM:SS from an hour up.
+func clockText(d time.Duration) string {
+	d = d.Truncate(time.Second)
+	h, m, s := int(d.Hours()), int(d.Minutes())%60, int(d.Seconds())%60
+	if h > 0 {
+		return fmt.Sprintf("%d:%02d:%02d", h, m, s)
+	}
+	return fmt.Sprintf("%02d:%02d", m, s)
+}
+
+// bigClock renders text with the pixel font at the largest scale that
+// fits in width x height cells, or returns "" if even the smallest
+// doesn't. A pixel is twice as wide as tall to look square.
+func bigClock(text string, width, height int) string {
+	cols := 0
+	for i, r := range text {
+		if i > 0 {
+			cols++ // gap
+		}
+		cols += len(font[r][0])
+	}
+	scale := min(width/(2*cols), height/fontRows)
+	if scale < 1 {
+		return ""
+	}
+
+	var b strings.Builder
+	for row := range fontRows {
+		var line strings.Builder
+		for i, r := range text {
+			if i > 0 {
+				line.WriteString(strings.Repeat(" ", 2*scale))
+			}
+			for _, px := range font[r][row] {
+				cell := " "
+				if px == '#' {
+					cell = "█"
+				}
+				line.WriteString(strings.Repeat(cell, 2*scale))
+			}
+		}
+		l := strings.TrimRight(line.String(), " ")
+		for range scale {
+			b.WriteString(l)
+			b.WriteString("\n")
+		}
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
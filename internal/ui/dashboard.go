@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// dashboardDays is how many days the bar chart covers.
+const dashboardDays = 14
+
+// dashboard is the stats screen, rendered from a history snapshot taken
+// when it is opened.
+type dashboard struct {
+	sessions []history.Session
+	err      error
+	heatmap  bool // show the year heatmap instead of the last 14 days
+	back     int  // days the chart is scrolled into the past
+}
+
+// scroll moves the chart by days, toward the past for positive ones;
+// it never scrolls past today.
+func (d *dashboard) scroll(days int) {
+	d.back = max(d.back+days, 0)
+}
+
+// openDashboard reloads history for the stats screen.
+func (m *Model) openDashboard(heatmap bool) {
+	m.dash = &dashboard{heatmap: heatmap}
+	if m.history == nil {
+		m.dash.err = fmt.Errorf("no history store")
+		return
+	}
+	m.dash.sessions, m.dash.err = m.history.List()
+}
+
+func (m *Model) dashboardView(width int) string {
+	bold := lipgloss.NewStyle().Bold(true)
+	if m.dash.err != nil {
+		return "Stats unavailable: " + m.dash.err.Error()
+	}
+	now := time.Now()
+	if m.dash.heatmap && !m.plain {
+		return Heatmap(m.dash.sessions, now, width)
+	}
+	today := stats.Daily(m.dash.sessions, now, 1)[0]
+	days := stats.Daily(m.dash.sessions, now.AddDate(0, 0, -m.dash.back), dashboardDays)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %d pomodoros, %s focus\n", bold.Render("Today:"),
+		today.Pomodoros, today.Focus.Round(time.Minute))
+	fmt.Fprintf(&b, "%s %d days\n\n", bold.Render("Streak:"), stats.Streak(m.dash.sessions, now))
+
+	peak := 1
+	for _, d := range days {
+		peak = max(peak, d.Pomodoros)
+	}
+	// "Mon 05-01 " + bar + " 12"
+	barWidth := max(width-16, 4)
+	bar := m.theme.phase[core.PhaseWork]
+	for _, d := range days {
+		n := d.Pomodoros * barWidth / peak
+		if d.Pomodoros > 0 {
+			n = max(n, 1)
+		}
+		label := d.Date.Format("Mon 01-02")
+		if d.Date.Equal(today.Date) {
+			label = bold.Render(label)
+		}
+		if m.plain {
+			fmt.Fprintf(&b, "%s %d\n", label, d.Pomodoros)
+			continue
+		}
+		fmt.Fprintf(&b, "%s %s %d\n", label, bar.Render(strings.Repeat("█", n)), d.Pomodoros)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
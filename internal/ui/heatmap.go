@@ -0,0 +1,89 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// heatLevels are the cell colors from no pomodoros to the busiest days,
+// as on GitHub's contribution graph.
+var heatLevels = []lipgloss.Style{
+	lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#ebedf0", Dark: "#2d333b"}),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#0e4429")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#006d32")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#26a641")),
+	lipgloss.NewStyle().Foreground(lipgloss.Color("#39d353")),
+}
+
+// HeatmapWeeks is a year of weeks, the most Heatmap draws.
+const HeatmapWeeks = 53
+
+// Heatmap renders pomodoros per day as a calendar grid: one column per
+// week ending with the week of end, one row per weekday. It draws as
+// many weeks as fit in width, up to HeatmapWeeks.
+func Heatmap(sessions []history.Session, end time.Time, width int) string {
+	const label = 4 // "Mon "
+	weeks := min(HeatmapWeeks, max((width-label)/2, 1))
+	// start on the Sunday weeks-1 weeks before end's week
+	last := time.Date(end.Year(), end.Month(), end.Day(), 0, 0, 0, 0, end.Location())
+	first := last.AddDate(0, 0, -int(last.Weekday())-7*(weeks-1))
+	n := int(last.Sub(first).Hours()/24+0.5) + 1
+	days := stats.Daily(sessions, end, n)
+
+	peak := 0
+	for _, d := range days {
+		peak = max(peak, d.Pomodoros)
+	}
+	level := func(p int) int {
+		if p == 0 || peak == 0 {
+			return 0
+		}
+		return (p*(len(heatLevels)-1) + peak - 1) / peak
+	}
+
+	var b strings.Builder
+	// month labels above the first week of each month
+	months := []byte(strings.Repeat(" ", label+2*weeks))
+	prev, free := time.Month(0), 0
+	for w := range weeks {
+		d := first.AddDate(0, 0, 7*w)
+		pos := label + 2*w
+		if d.Month() != prev && pos >= free && pos+3 <= len(months) {
+			copy(months[pos:], d.Format("Jan"))
+			free = pos + 4
+		}
+		prev = d.Month()
+	}
+	b.WriteString(strings.TrimRight(string(months), " "))
+	b.WriteString("\n")
+
+	for wd := range 7 {
+		switch time.Weekday(wd) {
+		case time.Monday, time.Wednesday, time.Friday:
+			b.WriteString(time.Weekday(wd).String()[:3] + " ")
+		default:
+			b.WriteString(strings.Repeat(" ", label))
+		}
+		for w := range weeks {
+			i := 7*w + wd
+			if i >= len(days) {
+				break // after end
+			}
+			b.WriteString(heatLevels[level(days[i].Pomodoros)].Render("■"))
+			b.WriteString(" ")
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString(strings.Repeat(" ", label) + "Less ")
+	for _, st := range heatLevels {
+		b.WriteString(st.Render("■") + " ")
+	}
+	b.WriteString("More")
+	return b.String()
+}
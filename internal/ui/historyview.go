@@ -0,0 +1,343 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+// historyRanges are the date ranges the browser cycles through, in
+// days back from today; 0 is everything.
+var historyRanges = []int{0, 1, 7, 30}
+
+// historyStatuses are the status filters the browser cycles through;
+// "" is any.
+var historyStatuses = []string{"", "completed", "abandoned", "incomplete"}
+
+// historyFilter narrows the sessions the browser lists.
+type historyFilter struct {
+	// query is looked for in the task title or step name, in any case;
+	// "#tag" instead matches sessions carrying the tag.
+	query  string
+	days   int    // see historyRanges
+	status string // see historyStatuses
+}
+
+func (f historyFilter) match(s history.Session, now time.Time) bool {
+	if f.days > 0 && s.Start.Before(startOfDay(now).AddDate(0, 0, 1-f.days)) {
+		return false
+	}
+	if f.status != "" && s.Status() != f.status {
+		return false
+	}
+	if f.query == "" {
+		return true
+	}
+	if tag, ok := strings.CutPrefix(f.query, "#"); ok {
+		return slices.Contains(s.Tags, tag)
+	}
+	q := strings.ToLower(f.query)
+	if s.Task != nil && strings.Contains(strings.ToLower(s.Task.Title), q) {
+		return true
+	}
+	return strings.Contains(strings.ToLower(s.Name), q)
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// historyBrowser is the history screen: past sessions, newest first,
+// narrowed by filters, with deleting and editing single entries.
+type historyBrowser struct {
+	store  *history.Store
+	all    []history.Session // newest first
+	shown  []history.Session // the table's rows
+	err    error
+	filter historyFilter
+	table  table.Model
+
+	// search is focused while the query is typed, edit while a task
+	// title or tags are, as editing says; confirm asks before deleting.
+	search  textinput.Model
+	edit    textinput.Model
+	editing string // "task", "tags" or ""
+	confirm bool
+}
+
+func newHistoryBrowser(store *history.Store) *historyBrowser {
+	b := &historyBrowser{store: store}
+	b.search = newLineInput("search tasks and steps, or #tag")
+	b.search.Blur()
+	b.edit = newLineInput("")
+	b.edit.Blur()
+	b.table = table.New(
+		table.WithColumns([]table.Column{
+			{Title: "Date", Width: 10},
+			{Title: "Time", Width: 5},
+			{Title: "Phase", Width: 11},
+			{Title: "Active", Width: 8},
+			{Title: "Status", Width: 10},
+			{Title: "Tags", Width: 16},
+			{Title: "Task", Width: 20},
+		}),
+		table.WithFocused(true),
+	)
+	b.reload()
+	return b
+}
+
+// newLineInput returns a focused one-line text input.
+func newLineInput(placeholder string) textinput.Model {
+	ti := textinput.New()
+	ti.Placeholder = placeholder
+	ti.CharLimit = 120
+	ti.Cursor.SetMode(cursor.CursorStatic)
+	ti.Focus()
+	return ti
+}
+
+// reload rereads the history and reapplies the filter.
+func (b *historyBrowser) reload() {
+	if b.store == nil {
+		b.err = fmt.Errorf("no history store")
+		return
+	}
+	b.all, b.err = b.store.List()
+	slices.Reverse(b.all)
+	b.apply()
+}
+
+// apply refills the table from the sessions matching the filter.
+func (b *historyBrowser) apply() {
+	now := time.Now()
+	b.shown = b.shown[:0]
+	rows := make([]table.Row, 0, len(b.all))
+	for _, s := range b.all {
+		if !b.filter.match(s, now) {
+			continue
+		}
+		b.shown = append(b.shown, s)
+		phase := cmp.Or(s.Name, s.Phase)
+		task := ""
+		if s.Task != nil {
+			task = s.Task.Title
+		}
+		rows = append(rows, table.Row{
+			s.Start.Local().Format(time.DateOnly),
+			s.Start.Local().Format("15:04"),
+			phase,
+			s.Active().Round(time.Second).String(),
+			s.Status(),
+			core.FormatTags(s.Tags),
+			task,
+		})
+	}
+	b.table.SetRows(rows)
+	b.table.SetCursor(min(b.table.Cursor(), max(len(rows)-1, 0)))
+}
+
+// selected is the session under the cursor.
+func (b *historyBrowser) selected() (history.Session, bool) {
+	i := b.table.Cursor()
+	if i < 0 || i >= len(b.shown) {
+		return history.Session{}, false
+	}
+	return b.shown[i], true
+}
+
+// save writes an edited session back and rereads the history.
+func (b *historyBrowser) save(s history.Session) {
+	if err := b.store.Update(s); err != nil {
+		b.err = err
+		return
+	}
+	b.reload()
+}
+
+// handleKey runs a key on the browser; closed reports that it wants to
+// be dismissed.
+func (b *historyBrowser) handleKey(msg tea.KeyMsg) (closed bool) {
+	switch {
+	case b.search.Focused():
+		switch msg.String() {
+		case "enter", "esc":
+			b.search.Blur()
+		default:
+			b.search, _ = b.search.Update(msg)
+			b.filter.query = b.search.Value()
+			b.apply()
+		}
+		return false
+	case b.editing != "":
+		switch msg.String() {
+		case "enter":
+			if s, ok := b.selected(); ok {
+				b.applyEdit(&s, b.edit.Value())
+				b.save(s)
+			}
+			b.editing = ""
+		case "esc":
+			b.editing = ""
+		default:
+			b.edit, _ = b.edit.Update(msg)
+		}
+		return false
+	case b.confirm:
+		if msg.String() == "y" {
+			if s, ok := b.selected(); ok {
+				if err := b.store.Delete(s.ID); err != nil {
+					b.err = err
+				} else {
+					b.reload()
+				}
+			}
+		}
+		b.confirm = false
+		return false
+	}
+
+	switch msg.String() {
+	case "esc", "q":
+		return true
+	case "/":
+		b.search.Focus()
+	case "d":
+		i := slices.Index(historyRanges, b.filter.days)
+		b.filter.days = historyRanges[(i+1)%len(historyRanges)]
+		b.apply()
+	case "f":
+		i := slices.Index(historyStatuses, b.filter.status)
+		b.filter.status = historyStatuses[(i+1)%len(historyStatuses)]
+		b.apply()
+	case "x", "delete":
+		if _, ok := b.selected(); ok {
+			b.confirm = true
+		}
+	case "e":
+		if s, ok := b.selected(); ok {
+			title := ""
+			if s.Task != nil {
+				title = s.Task.Title
+			}
+			b.startEdit("task", title)
+		}
+	case "t":
+		if s, ok := b.selected(); ok {
+			b.startEdit("tags", core.FormatTags(s.Tags))
+		}
+	case "c":
+		// flip between completed and abandoned, e.g. for a pomodoro
+		// that was reset by mistake
+		if s, ok := b.selected(); ok {
+			s.Completed = !s.Completed
+			s.Abandoned = !s.Completed
+			b.save(s)
+		}
+	default:
+		b.table, _ = b.table.Update(msg)
+	}
+	return false
+}
+
+// startEdit opens the edit input on field, prefilled with value.
+func (b *historyBrowser) startEdit(field, value string) {
+	b.edit.Placeholder = field
+	b.edit.SetValue(value)
+	b.edit.CursorEnd()
+	b.edit.Focus()
+	b.editing = field
+}
+
+// applyEdit sets the field being edited on s to the typed value; an
+// empty one clears it.
+func (b *historyBrowser) applyEdit(s *history.Session, value string) {
+	if b.editing == "tags" {
+		s.Tags = core.ParseTags(value)
+		return
+	}
+	title := strings.TrimSpace(value)
+	if title == "" {
+		s.Task = nil
+		return
+	}
+	t := history.Task{Title: title}
+	if s.Task != nil {
+		t = *s.Task
+		t.Title = title
+	}
+	s.Task = &t
+}
+
+// scroll moves the cursor by n rows, down for positive ones.
+func (b *historyBrowser) scroll(n int) {
+	if n > 0 {
+		b.table.MoveDown(n)
+	} else {
+		b.table.MoveUp(-n)
+	}
+}
+
+// filterText describes the active filters, e.g. "last 7 days · completed".
+func (b *historyBrowser) filterText() string {
+	parts := []string{"all time"}
+	switch b.filter.days {
+	case 0:
+	case 1:
+		parts[0] = "today"
+	default:
+		parts[0] = fmt.Sprintf("last %d days", b.filter.days)
+	}
+	if b.filter.status != "" {
+		parts = append(parts, b.filter.status)
+	}
+	if b.filter.query != "" {
+		parts = append(parts, fmt.Sprintf("%q", b.filter.query))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// View renders the browser in width columns and about height rows.
+func (b *historyBrowser) View(width, height int, faint lipgloss.Style) string {
+	cols := b.table.Columns()
+	fixed := 0
+	for _, c := range cols[:len(cols)-1] {
+		fixed += c.Width + 2 // cell padding
+	}
+	cols[len(cols)-1].Width = max(width-fixed-2, 10)
+	b.table.SetColumns(cols)
+	b.table.SetHeight(max(height, 3))
+
+	var s strings.Builder
+	fmt.Fprintf(&s, "%s  %s\n", lipgloss.NewStyle().Bold(true).Render("History"),
+		faint.Render(fmt.Sprintf("%d of %d sessions, %s", len(b.shown), len(b.all), b.filterText())))
+	switch {
+	case b.search.Focused():
+		s.WriteString("Search: " + b.search.View() + "\n")
+	case b.editing == "task":
+		s.WriteString("Task: " + b.edit.View() + "\n")
+	case b.editing == "tags":
+		s.WriteString("Tags: " + b.edit.View() + "\n")
+	case b.confirm:
+		s.WriteString("Delete this session? [y/n]\n")
+	case b.err != nil:
+		s.WriteString("History: " + b.err.Error() + "\n")
+	default:
+		s.WriteString("\n")
+	}
+	s.WriteString(b.table.View() + "\n")
+	s.WriteString(faint.Render("[/] search  [d] dates  [f] status  [e] edit task  [t] tags  [c] completed/abandoned  [x] delete  [esc] back"))
+	return s.String()
+}
@@ -0,0 +1,88 @@
+package ui
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/history"
+)
+
+func TestHistoryBrowser_FilterEditDelete(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "history.jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	for _, s := range []history.Session{
+		{ID: "old", Phase: "WORK", Start: now.AddDate(0, 0, -40), End: now.AddDate(0, 0, -40).Add(25 * time.Minute), Completed: true},
+		{ID: "report", Phase: "WORK", Start: now.Add(-2 * time.Hour), End: now.Add(-95 * time.Minute), Completed: true, Task: &history.Task{Title: "Write report"}},
+		{ID: "reset", Phase: "WORK", Start: now.Add(-time.Hour), End: now.Add(-50 * time.Minute), Abandoned: true, Tags: []string{"coding"}},
+	} {
+		if err := store.Append(s); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := newHistoryBrowser(store)
+	key := func(keys ...string) {
+		for _, k := range keys {
+			switch k {
+			case "enter":
+				b.handleKey(tea.KeyMsg{Type: tea.KeyEnter})
+			case "down":
+				b.handleKey(tea.KeyMsg{Type: tea.KeyDown})
+			default:
+				b.handleKey(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+			}
+		}
+	}
+	ids := func() []string {
+		var out []string
+		for _, s := range b.shown {
+			out = append(out, s.ID)
+		}
+		return out
+	}
+
+	if got := ids(); len(got) != 3 || got[0] != "reset" {
+		t.Fatalf("want newest first, got %v", got)
+	}
+	key("d", "d") // last 7 days
+	if got := ids(); len(got) != 2 {
+		t.Fatalf("last 7 days: %v", got)
+	}
+	key("/", "r", "e", "p", "enter")
+	if got := ids(); len(got) != 1 || got[0] != "report" {
+		t.Fatalf("search: %v", got)
+	}
+	b.filter = historyFilter{query: "#coding"}
+	b.apply()
+	if got := ids(); len(got) != 1 || got[0] != "reset" {
+		t.Fatalf("tag filter: %v", got)
+	}
+	b.filter = historyFilter{}
+	b.apply()
+
+	// retag the report
+	key("down", "t")
+	b.edit.SetValue("#client-a #writing")
+	key("enter", "up")
+
+	// the newest, abandoned by mistake, counts after all
+	key("c")
+	key("down", "x", "y")
+	sessions, err := store.List()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 2 || sessions[0].ID != "old" || sessions[1].ID != "reset" || !sessions[1].Completed {
+		t.Fatalf("after edit and delete: %+v", sessions)
+	}
+	changes, err := store.Changes()
+	if err != nil || len(changes) != 3 || !slices.Equal(changes[0].After.Tags, []string{"client-a", "writing"}) {
+		t.Fatalf("audit journal: %+v, %v", changes, err)
+	}
+}
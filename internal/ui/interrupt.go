@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// interruptPrompt asks for the kind of an interruption and an optional
+// note. The timer keeps running while it is open.
+type interruptPrompt struct {
+	kind  core.InterruptionKind
+	note  textinput.Model
+	onLog func(core.InterruptionKind, string)
+}
+
+func newInterruptPrompt(onLog func(core.InterruptionKind, string)) *interruptPrompt {
+	return &interruptPrompt{note: newLineInput("optional note"), onLog: onLog}
+}
+
+func (p *interruptPrompt) handleKey(msg tea.KeyMsg) (closed bool) {
+	switch msg.String() {
+	case "tab":
+		if p.kind == core.InterruptInternal {
+			p.kind = core.InterruptExternal
+		} else {
+			p.kind = core.InterruptInternal
+		}
+		return false
+	case "enter":
+		p.onLog(p.kind, p.note.Value())
+		return true
+	case "esc":
+		return true
+	}
+	p.note, _ = p.note.Update(msg)
+	return false
+}
+
+func (p *interruptPrompt) View() string {
+	title := lipgloss.NewStyle().Bold(true).Render("Log interruption: " + p.kind.String())
+	help := lipgloss.NewStyle().Faint(true).Render("[tab] internal/external  [enter] log  [esc] cancel")
+	return title + "\n" + p.note.View() + "\n" + help
+}
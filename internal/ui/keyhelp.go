@@ -0,0 +1,113 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// keyHelp is the ? screen: every binding, scrolled in a viewport when
+// the terminal is too short for the list.
+type keyHelp struct {
+	view viewport.Model
+}
+
+// helpSection is a titled group of bindings on the help screen.
+type helpSection struct {
+	title    string
+	bindings []key.Binding
+}
+
+// openKeyHelp shows the help screen.
+func (m *Model) openKeyHelp() {
+	m.keyHelp = &keyHelp{view: viewport.New(0, 0)}
+}
+
+// handleKey scrolls the help screen; ?, esc and q close it.
+func (h *keyHelp) handleKey(msg tea.KeyMsg, close key.Binding) (closed bool) {
+	switch msg.String() {
+	case "esc", "q":
+		return true
+	}
+	if key.Matches(msg, close) {
+		return true
+	}
+	h.view, _ = h.view.Update(msg)
+	return false
+}
+
+// helpSections lists the bindings in effect, remapped ones included,
+// with the task keys only when a task integration provides tasks.
+func (m *Model) helpSections() []helpSection {
+	bindings := func(acts ...action) []key.Binding {
+		out := make([]key.Binding, len(acts))
+		for i, a := range acts {
+			out[i] = m.keys[a]
+		}
+		return out
+	}
+	sections := []helpSection{
+		{m.loc.T("Timer"), bindings(actStart, actPause, actInterrupt, actSkip, actExtend, actShorten,
+			actReset, actUndo, actAcknowledge, actOverride, actTags, actPrevTimer, actNextTimer)},
+		{m.loc.T("Views"), bindings(actClock, actZen, actCountUp, actMini, actDashboard, actHeatmap,
+			actHistory, actProfile, actTheme, actHelp, actQuit)},
+	}
+	if m.board != nil {
+		sections[1].bindings = append(sections[1].bindings, m.keys[actBoard])
+	}
+	if len(m.tasks) > 0 {
+		sections = append(sections, helpSection{m.loc.T("Tasks"), append(bindings(actTask, actTaskPanel, actEstimate),
+			key.NewBinding(key.WithKeys("up", "down"), key.WithHelp("↑/↓", "move in the task list")),
+			key.NewBinding(key.WithKeys("enter"), key.WithHelp("enter", "attach the task")),
+		)})
+	}
+	sections = append(sections, helpSection{m.loc.T("History"), []key.Binding{
+		key.NewBinding(key.WithKeys("/"), key.WithHelp("/", "search")),
+		key.NewBinding(key.WithKeys("d"), key.WithHelp("d", "dates")),
+		key.NewBinding(key.WithKeys("f"), key.WithHelp("f", "status")),
+		key.NewBinding(key.WithKeys("e"), key.WithHelp("e", "edit task")),
+		key.NewBinding(key.WithKeys("t"), key.WithHelp("t", "tags")),
+		key.NewBinding(key.WithKeys("c"), key.WithHelp("c", "completed/abandoned")),
+		key.NewBinding(key.WithKeys("x"), key.WithHelp("x", "delete")),
+		key.NewBinding(key.WithKeys("esc"), key.WithHelp("esc", "back")),
+	}})
+	return sections
+}
+
+// keyHelpView renders the help screen in width columns and height
+// rows.
+func (m *Model) keyHelpView(width, height int) string {
+	hm := help.New()
+	hm.Styles.FullKey = lipgloss.NewStyle().Bold(true)
+	hm.Styles.FullDesc = lipgloss.NewStyle()
+	if m.plain {
+		hm.Styles = help.Styles{}
+	}
+	var b strings.Builder
+	for i, s := range m.helpSections() {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		title := s.title
+		if !m.plain {
+			title = lipgloss.NewStyle().Underline(true).Render(title)
+		}
+		b.WriteString(title + "\n")
+		b.WriteString(hm.FullHelpView([][]key.Binding{s.bindings}))
+	}
+
+	title := m.loc.T("Keyboard help")
+	footer := "[" + m.keys[actHelp].Help().Key + "/esc] " + m.loc.T("close") + "  [↑/↓] " + m.loc.T("scroll")
+	if !m.plain {
+		title = lipgloss.NewStyle().Bold(true).Render(title)
+		footer = m.theme.faint.Render(footer)
+	}
+	v := &m.keyHelp.view
+	v.Width, v.Height = width, max(height-4, 3)
+	v.SetContent(b.String())
+	return title + "\n\n" + v.View() + "\n\n" + footer
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type noTasks struct{}
+
+func (noTasks) Tasks(context.Context) ([]core.Task, error) { return nil, nil }
+
+func TestKeyHelp_ListsEveryBinding(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Keys = map[string]config.Keys{"skip": {"x"}}
+	m, err := NewModel(eng, nil, Options{Config: cfg})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.width, m.height = 100, 80
+
+	if v := m.View(); strings.Contains(v, "heatmap") || !strings.Contains(v, "[?] keyboard help") {
+		t.Errorf("footer should be short and point at ?:\n%s", v)
+	}
+	m.Update(keyMsg("?"))
+	if m.keyHelp == nil {
+		t.Fatal("? didn't open the help")
+	}
+	// the help aligns descriptions in columns
+	v := strings.Join(strings.Fields(m.View()), " ")
+	for _, s := range []string{"x skip", "a acknowledge and take your break", "f zen mode", "Timer", "History"} {
+		if !strings.Contains(v, s) {
+			t.Errorf("help lacks %q:\n%s", s, v)
+		}
+	}
+	if strings.Contains(v, "Tasks") {
+		t.Errorf("task keys without a task source:\n%s", v)
+	}
+	m.Update(keyMsg("s")) // swallowed
+	if !eng.State().Idle() {
+		t.Error("a key got through the help")
+	}
+	m.Update(keyMsg("?"))
+	if m.keyHelp != nil {
+		t.Fatal("? didn't close the help")
+	}
+
+	m.tasks = []TaskSource{noTasks{}}
+	m.Update(keyMsg("?"))
+	if v := strings.Join(strings.Fields(m.View()), " "); !strings.Contains(v, "Tasks") || !strings.Contains(v, "l task list") {
+		t.Errorf("help lacks the task keys:\n%s", v)
+	}
+}
@@ -0,0 +1,167 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/i18n"
+)
+
+// action is something a key can be bound to.
+type action int
+
+const (
+	actStart action = iota
+	actPause
+	actInterrupt
+	actSkip
+	actExtend
+	actShorten
+	actReset
+	actUndo
+	actTask
+	actTaskPanel
+	actBoard
+	actEstimate
+	actTags
+	actProfile
+	actTheme
+	actClock
+	actZen
+	actCountUp
+	actMini
+	actDashboard
+	actHeatmap
+	actHistory
+	actNextTimer
+	actPrevTimer
+	actAcknowledge
+	actOverride
+	actHelp
+	actQuit
+	numActions
+)
+
+// actionInfo names an action in the config file and the help line.
+var actionInfo = [numActions]struct {
+	name, help string
+	keys       []string
+}{
+	actStart:       {"start", "start/resume", []string{"s"}},
+	actPause:       {"pause", "pause", []string{"p"}},
+	actInterrupt:   {"interrupt", "interruption", []string{"i"}},
+	actSkip:        {"skip", "skip", []string{"n"}},
+	actExtend:      {"extend", "+1m", []string{"+", "="}},
+	actShorten:     {"shorten", "-1m", []string{"-"}},
+	actReset:       {"reset", "reset", []string{"r"}},
+	actUndo:        {"undo", "undo", []string{"u"}},
+	actTask:        {"task", "task", []string{"t"}},
+	actTaskPanel:   {"task_panel", "task list", []string{"l"}},
+	actBoard:       {"leaderboard", "leaderboard", []string{"b"}},
+	actEstimate:    {"estimate", "estimate", []string{"e"}},
+	actTags:        {"tags", "tags", []string{"#"}},
+	actProfile:     {"profile", "profile", []string{"P"}},
+	actTheme:       {"theme", "theme", []string{"T"}},
+	actClock:       {"clock", "big clock", []string{"c"}},
+	actZen:         {"zen", "zen mode", []string{"f"}},
+	actCountUp:     {"count_up", "count up/down", []string{"U"}},
+	actMini:        {"mini", "mini mode", []string{"z"}},
+	actDashboard:   {"dashboard", "stats", []string{"tab"}},
+	actHeatmap:     {"heatmap", "heatmap", []string{"H"}},
+	actHistory:     {"history", "history", []string{"h"}},
+	actNextTimer:   {"next_timer", "next timer", []string{"]"}},
+	actPrevTimer:   {"prev_timer", "previous timer", []string{"["}},
+	actAcknowledge: {"acknowledge", "acknowledge and take your break", []string{"a"}},
+	actOverride:    {"override", "keep working past quitting time today", []string{"O"}},
+	actHelp:        {"help", "keyboard help", []string{"?"}},
+	actQuit:        {"quit", "quit", []string{"q", "esc"}},
+}
+
+// quitKey always quits, so a broken keymap can't trap the user.
+const quitKey = "ctrl+c"
+
+type keyMap [numActions]key.Binding
+
+// newKeyMap applies overrides (action name to keys) on top of the
+// defaults. Unknown actions, empty bindings and keys bound to two
+// actions are errors.
+func newKeyMap(overrides map[string]config.Keys) (keyMap, error) {
+	var km keyMap
+	byName := make(map[string]action, numActions)
+	for a, info := range actionInfo {
+		byName[info.name] = action(a)
+	}
+	keys := make([][]string, numActions)
+	for a, info := range actionInfo {
+		keys[a] = info.keys
+	}
+	for name, ks := range overrides {
+		a, ok := byName[name]
+		if !ok {
+			return km, fmt.Errorf("keys: unknown action %q", name)
+		}
+		if len(ks) == 0 {
+			return km, fmt.Errorf("keys: %s has no keys", name)
+		}
+		keys[a] = ks
+	}
+
+	owner := map[string]action{}
+	for a := range numActions {
+		bound := make([]string, len(keys[a]))
+		for i, k := range keys[a] {
+			// Bubble Tea reports the space bar as " "
+			if k == "space" {
+				k = " "
+			}
+			bound[i] = k
+			if k == quitKey {
+				return km, fmt.Errorf("keys: %s is reserved for quit", quitKey)
+			}
+			if prev, ok := owner[k]; ok && prev != a {
+				return km, fmt.Errorf("keys: %q is bound to both %s and %s", k, actionInfo[prev].name, actionInfo[a].name)
+			}
+			owner[k] = a
+		}
+		km[a] = key.NewBinding(
+			key.WithKeys(bound...),
+			key.WithHelp(strings.Join(keys[a], "/"), actionInfo[a].help),
+		)
+	}
+	return km, nil
+}
+
+// translate puts the help texts into loc.
+func (km *keyMap) translate(loc *i18n.Locale) {
+	for a := range numActions {
+		h := km[a].Help()
+		km[a].SetHelp(h.Key, loc.T(h.Desc))
+	}
+}
+
+// lookup returns the action bound to msg.
+func (km keyMap) lookup(msg tea.KeyMsg) (action, bool) {
+	if msg.String() == quitKey {
+		return actQuit, true
+	}
+	for a := range numActions {
+		if key.Matches(msg, km[a]) {
+			return a, true
+		}
+	}
+	return 0, false
+}
+
+// help renders "[s] start/resume  [p] pause ..." for the given actions.
+func (km keyMap) help(actions ...action) string {
+	parts := make([]string, 0, len(actions))
+	for _, a := range actions {
+		h := km[a].Help()
+		parts = append(parts, fmt.Sprintf("[%s] %s", h.Key, h.Desc))
+	}
+	return strings.Join(parts, "  ")
+}
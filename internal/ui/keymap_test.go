@@ -0,0 +1,53 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+)
+
+func keyMsg(s string) tea.KeyMsg {
+	return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+}
+
+func TestKeyMap_Overrides(t *testing.T) {
+	km, err := newKeyMap(map[string]config.Keys{"skip": {"x"}, "start": {"space", "s"}})
+	if err != nil {
+		t.Fatalf("newKeyMap: %v", err)
+	}
+	if a, ok := km.lookup(keyMsg("x")); !ok || a != actSkip {
+		t.Fatalf("x should skip, got %v %v", a, ok)
+	}
+	if a, ok := km.lookup(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}}); !ok || a != actStart {
+		t.Fatal("space should start")
+	}
+	if _, ok := km.lookup(keyMsg("n")); ok {
+		t.Fatal("default skip key should be replaced")
+	}
+	if a, ok := km.lookup(tea.KeyMsg{Type: tea.KeyCtrlC}); !ok || a != actQuit {
+		t.Fatal("ctrl+c must always quit")
+	}
+	if h := km.help(actSkip); h != "[x] skip" {
+		t.Fatalf("help should follow bindings, got %q", h)
+	}
+}
+
+func TestKeyMap_Validation(t *testing.T) {
+	for _, tc := range []struct {
+		keys map[string]config.Keys
+		want string
+	}{
+		{map[string]config.Keys{"pause": {"s"}}, "bound to both"},
+		{map[string]config.Keys{"jump": {"j"}}, "unknown action"},
+		{map[string]config.Keys{"skip": {}}, "no keys"},
+		{map[string]config.Keys{"skip": {"ctrl+c"}}, "reserved"},
+	} {
+		_, err := newKeyMap(tc.keys)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%v: want error containing %q, got %v", tc.keys, tc.want, err)
+		}
+	}
+}
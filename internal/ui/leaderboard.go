@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/leaderboard"
+)
+
+// Leaderboard is the team leaderboard's last board and why the last
+// fetch failed, if it did.
+type Leaderboard interface {
+	Board() (leaderboard.Board, error)
+}
+
+// toggleBoard shows or hides the leaderboard panel, in place of the
+// task panel.
+func (m *Model) toggleBoard() {
+	if m.board == nil {
+		return
+	}
+	m.boardOpen = !m.boardOpen
+	if m.boardOpen {
+		m.panel = nil
+	}
+}
+
+// boardPanelView renders the leaderboard panel.
+func (m *Model) boardPanelView() string {
+	return lipgloss.NewStyle().
+		Border(m.theme.border).
+		Padding(0, 1).
+		Width(panelWidth - 2).
+		Render(m.boardPanelText())
+}
+
+// boardPanelText lists today's pomodoros per member, most first, e.g.
+// "1. ana  🍅6 2h30m", with the user's line highlighted.
+func (m *Model) boardPanelText() string {
+	inner := panelWidth - 4
+	b, err := m.board.Board()
+	var out strings.Builder
+	out.WriteString(lipgloss.NewStyle().Bold(true).Render(m.loc.T("Leaderboard")) + "\n\n")
+	switch {
+	case b.Day == "" && err != nil:
+		out.WriteString(ansi.Wordwrap(m.loc.Sprintf("Leaderboard unreachable: %s", err), inner, ""))
+		return out.String()
+	case b.Day == "":
+		out.WriteString(m.theme.faint.Render("Loading…"))
+		return out.String()
+	case len(b.Entries) == 0:
+		out.WriteString(m.theme.faint.Render(m.loc.T("No pomodoros yet today.")) + "\n")
+	}
+	for i, e := range b.Entries {
+		rank := fmt.Sprintf("%d. ", i+1)
+		count := fmt.Sprintf("🍅%d %s", e.Pomodoros, minutesText(e.Focus()))
+		name := ansi.Truncate(e.Name, inner-len(rank)-lipgloss.Width(count)-1, "…")
+		gap := max(inner-lipgloss.Width(rank+name+count), 1)
+		line := rank + name + strings.Repeat(" ", gap) + count
+		if e.Name == b.You {
+			line = m.theme.phase[core.PhaseWork].Render(line)
+		}
+		out.WriteString(line + "\n")
+	}
+	if err != nil {
+		out.WriteString("\n" + m.theme.faint.Render(ansi.Wordwrap(m.loc.Sprintf("Leaderboard unreachable: %s", err), inner, "")))
+	}
+	return out.String()
+}
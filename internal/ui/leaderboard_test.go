@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/leaderboard"
+)
+
+type fakeBoard struct {
+	board leaderboard.Board
+	err   error
+}
+
+func (f *fakeBoard) Board() (leaderboard.Board, error) { return f.board, f.err }
+
+func TestLeaderboard_Panel(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	board := &fakeBoard{}
+	m, err := NewModel(eng, nil, Options{Leaderboard: board})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.Update(tea.WindowSizeMsg{Width: 120, Height: 50})
+
+	m.Update(keyMsg("b"))
+	if !m.boardOpen || !strings.Contains(m.View(), "Loading…") {
+		t.Fatal("b didn't open the leaderboard")
+	}
+	board.board = leaderboard.Board{Day: "2024-04-01", You: "ana", Entries: []leaderboard.Entry{
+		{Name: "bo", Totals: leaderboard.NewTotals(5, 2*time.Hour)},
+		{Name: "ana", Totals: leaderboard.NewTotals(3, 75*time.Minute)},
+	}}
+	board.err = errors.New("connection refused")
+	text := m.boardPanelText()
+	for _, want := range []string{"1. bo", "🍅5 2h00m", "2. ana", "🍅3 1h15m", "unreachable:", "connection refused"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("panel lacks %q:\n%s", want, text)
+		}
+	}
+	m.Update(keyMsg("b"))
+	if m.boardOpen {
+		t.Fatal("b didn't close the leaderboard")
+	}
+
+	m, _ = NewModel(eng, nil, Options{})
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.Update(keyMsg("b"))
+	if m.boardOpen {
+		t.Fatal("opened a leaderboard without one")
+	}
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// miniBarWidth is the widest the bar gets in mini mode.
+const miniBarWidth = 10
+
+// toggleMini switches between the full TUI and mini mode, which leaves
+// the alt screen and the mouse to the terminal, so a tmux pane one row
+// tall can hold it. Plain mode has neither to give up.
+func (m *Model) toggleMini() tea.Cmd {
+	if m.plain {
+		return nil
+	}
+	m.mini = !m.mini
+	if m.mini {
+		return tea.Sequence(tea.DisableMouse, tea.ExitAltScreen)
+	}
+	return tea.Sequence(tea.EnterAltScreen, tea.EnableMouseCellMotion)
+}
+
+// miniView is mini mode's line, e.g. "🍅 WORK 12:34 ▓▓▓░░", with a
+// modal's view under it while one is open.
+func (m *Model) miniView() string {
+	m.zones = zones{}
+	st := m.engine.State()
+	icon := "🍅"
+	if st.Phase == core.PhaseShortBreak || st.Phase == core.PhaseLongBreak {
+		icon = "☕"
+	}
+	style := m.theme.phase[st.Phase]
+	parts := []string{icon, style.Render(m.loc.Name(st))}
+	switch {
+	case st.Overtime:
+		parts = append(parts, m.theme.overtime.Render("+"+clockText(m.engine.Overtime())))
+	case !st.Idle():
+		parts = append(parts, clockText(m.shownTime(st)))
+	}
+	if st.Paused {
+		parts = append(parts, "⏸")
+	}
+	line := strings.Join(parts, " ")
+	if !st.Idle() {
+		width := miniBarWidth
+		if m.width > 0 {
+			width = min(width, m.width-lipgloss.Width(line)-1)
+		}
+		if width >= 3 {
+			filled := int(m.barRatio(st)*float64(width) + 0.5)
+			line += " " + style.Render(strings.Repeat("▓", filled)+strings.Repeat("░", width-filled))
+		}
+	}
+	if m.modal != nil {
+		line += "\n" + m.modal.View()
+	}
+	return line
+}
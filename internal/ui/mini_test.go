@@ -0,0 +1,49 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestMini_View(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	m, err := NewModel(eng, nil, Options{})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.width, m.height = 30, 1
+
+	if cmd := m.toggleMini(); !m.mini || cmd == nil {
+		t.Fatal("z didn't enter mini mode")
+	}
+	if v := m.View(); v != "🍅 IDLE" {
+		t.Errorf("idle view = %q", v)
+	}
+	eng.Start()
+	eng.Pause()
+	v := m.View()
+	if !strings.HasPrefix(v, "🍅 WORK 2") || !strings.Contains(v, " ⏸ ") || strings.Contains(v, "\n") {
+		t.Errorf("view = %q", v)
+	}
+	if !strings.Contains(v, "░") || lipgloss.Width(v) > m.width {
+		t.Errorf("view = %q, %d cells wide", v, lipgloss.Width(v))
+	}
+	// a narrow pane drops the bar
+	m.width = 18
+	if v := m.View(); strings.ContainsAny(v, "▓░") {
+		t.Errorf("narrow view = %q", v)
+	}
+
+	m.toggleMini()
+	if m.mini || !strings.Contains(m.View(), "Completed: 0") {
+		t.Fatal("z didn't leave mini mode")
+	}
+}
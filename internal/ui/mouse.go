@@ -0,0 +1,135 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// buttons are the on-screen buttons, in the order shown.
+var buttons = []struct {
+	label string
+	act   action
+}{
+	{"Start", actStart},
+	{"Pause", actPause},
+	{"Skip", actSkip},
+	{"Reset", actReset},
+}
+
+// zone is where a clickable part of the view was last drawn, in
+// terminal cells.
+type zone struct {
+	row, col, width int
+}
+
+func (z zone) contains(x, y int) bool {
+	return z.width > 0 && y == z.row && x >= z.col && x < z.col+z.width
+}
+
+// zones are the clickable parts of the last rendered view; the zero
+// value has none.
+type zones struct {
+	buttons []zone // one per button
+	bar     zone   // the bar's cells, without the percentage
+}
+
+// buttonText is how a button is drawn.
+func buttonText(label string) string {
+	return "[ " + label + " ]"
+}
+
+// buttonsView renders the buttons on one line.
+func (m *Model) buttonsView() string {
+	parts := make([]string, len(buttons))
+	for i, b := range buttons {
+		parts[i] = buttonText(m.loc.T(b.label))
+	}
+	return m.theme.faint.Render(strings.Join(parts, " "))
+}
+
+// mapZones finds the buttons and the bar in the rendered view, which
+// lipgloss may have centered and padded anywhere on screen.
+func (m *Model) mapZones(view, bar string) {
+	m.zones = zones{}
+	row := ansi.Strip(m.buttonsView())
+	plainBar := ansi.Strip(bar)
+	cells := len([]rune(plainBar)) - len([]rune(strings.TrimLeft(plainBar, "█░")))
+	for y, line := range strings.Split(view, "\n") {
+		plain := ansi.Strip(line)
+		if i := strings.Index(plain, row); i >= 0 && m.zones.buttons == nil {
+			col := ansi.StringWidth(plain[:i])
+			for _, b := range buttons {
+				w := ansi.StringWidth(buttonText(m.loc.T(b.label)))
+				m.zones.buttons = append(m.zones.buttons, zone{row: y, col: col, width: w})
+				col += w + 1
+			}
+		}
+		if i := strings.Index(plain, plainBar); i >= 0 && cells > 0 && m.zones.bar.width == 0 {
+			m.zones.bar = zone{row: y, col: ansi.StringWidth(plain[:i]), width: cells}
+		}
+	}
+}
+
+// handleMouse clicks buttons, scrubs the bar, extends the phase with
+// the wheel over the bar and scrolls the stats chart.
+func (m *Model) handleMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if m.modal != nil || msg.Action != tea.MouseActionPress {
+		return m, nil
+	}
+	wheel := 0
+	switch msg.Button {
+	case tea.MouseButtonWheelUp:
+		wheel = 1
+	case tea.MouseButtonWheelDown:
+		wheel = -1
+	case tea.MouseButtonLeft:
+	default:
+		return m, nil
+	}
+	if m.browser != nil {
+		m.browser.scroll(-wheel)
+		return m, nil
+	}
+	if m.dash != nil {
+		if !m.dash.heatmap {
+			// up goes back in time, like scrolling up a log
+			m.dash.scroll(wheel)
+		}
+		return m, nil
+	}
+	if m.zones.bar.contains(msg.X, msg.Y) {
+		if wheel != 0 {
+			m.engine.Extend(time.Duration(wheel) * extendStep)
+		} else {
+			m.scrub((float64(msg.X-m.zones.bar.col) + 0.5) / float64(m.zones.bar.width))
+		}
+		return m, nil
+	}
+	if wheel != 0 {
+		return m, nil
+	}
+	for i, z := range m.zones.buttons {
+		if z.contains(msg.X, msg.Y) {
+			return m.runAction(buttons[i].act)
+		}
+	}
+	return m, nil
+}
+
+// scrub sets the time left from a click at fraction f of the bar: the
+// bar's edge moves toward the click, shortening or extending the phase.
+func (m *Model) scrub(f float64) {
+	st := m.engine.State()
+	if st.Idle() || st.Open || st.Overtime || st.Length <= 0 {
+		return
+	}
+	if m.theme.bars[st.Phase].drain == m.countUp {
+		// a filling bar shows the time done
+		f = 1 - f
+	}
+	left := time.Duration(f * float64(st.Length)).Round(time.Second)
+	m.engine.SetRemaining(left)
+}
@@ -0,0 +1,60 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestMouse_ButtonsAndBar(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	m, err := NewModel(eng, nil, Options{})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.width, m.height = 100, 40
+	click := func(z zone, button tea.MouseButton) {
+		t.Helper()
+		m.View()
+		if z.width == 0 {
+			t.Fatal("zone not drawn")
+		}
+		m.Update(tea.MouseMsg{X: z.col, Y: z.row, Action: tea.MouseActionPress, Button: button})
+	}
+
+	m.View()
+	if len(m.zones.buttons) != len(buttons) {
+		t.Fatalf("found %d buttons, want %d", len(m.zones.buttons), len(buttons))
+	}
+	click(m.zones.buttons[0], tea.MouseButtonLeft) // Start
+	if eng.State().Idle() {
+		t.Fatal("Start button didn't start the timer")
+	}
+
+	// the work bar fills, so its middle is half the phase done
+	bar := m.zones.bar
+	click(zone{row: bar.row, col: bar.col + bar.width/2, width: 1}, tea.MouseButtonLeft)
+	if got := eng.Remaining(); got < 11*time.Minute || got > 14*time.Minute {
+		t.Errorf("remaining %v after clicking the middle of the bar", got)
+	}
+	before := eng.Remaining()
+	click(bar, tea.MouseButtonWheelUp)
+	if got := eng.Remaining() - before; got < 59*time.Second || got > extendStep {
+		t.Errorf("wheel up extended by %v, want %v", got, extendStep)
+	}
+
+	click(m.zones.buttons[3], tea.MouseButtonLeft) // Reset
+	if eng.State().Idle() {
+		t.Fatal("Reset button stopped the pomodoro without asking")
+	}
+	m.Update(keyMsg("y"))
+	if !eng.State().Idle() {
+		t.Fatal("Reset button didn't stop the timer")
+	}
+}
@@ -0,0 +1,70 @@
+package ui
+
+import (
+	"cmp"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/enforce"
+)
+
+// defaultDismiss is how long the break overlay holds without
+// [enforce] dismiss.
+const defaultDismiss = 30 * time.Second
+
+// breakOverlay covers the whole TUI at the start of an enforced break
+// and swallows every key but quit until it may be dismissed.
+type breakOverlay struct {
+	started   time.Time // of the break it covers
+	dismissAt time.Time
+}
+
+// checkOverlay opens the overlay when a break enforced with mode
+// "overlay" starts on the shown timer, and drops it once that break is
+// over.
+func (m *Model) checkOverlay(now time.Time) {
+	st := m.engine.State()
+	if o := m.overlay; o != nil {
+		if !st.StartedAt.Equal(o.started) {
+			m.overlay = nil
+		}
+		return
+	}
+	ec := m.cfg.Enforce
+	if mode, _ := enforce.ParseMode(ec.Mode); mode != enforce.Overlay ||
+		!enforce.Applies(st, ec.LongOnly) || !st.StartedAt.After(m.enforced) {
+		return
+	}
+	m.enforced = st.StartedAt
+	m.overlay = &breakOverlay{started: st.StartedAt, dismissAt: now.Add(cmp.Or(ec.Dismiss.Duration, defaultDismiss))}
+}
+
+// overlayKey handles a key while the overlay is up.
+func (m *Model) overlayKey(now time.Time) {
+	if !now.Before(m.overlay.dismissAt) {
+		m.overlay = nil
+	}
+}
+
+// overlayView fills the terminal with the break's countdown.
+func (m *Model) overlayView(now time.Time) string {
+	st := m.engine.State()
+	style := m.theme.phase[st.Phase]
+	lines := style.Render("☕ " + m.loc.Sprintf("%s: step away from the screen", m.loc.Name(st)))
+	text := clockText(m.engine.Remaining())
+	if big := bigClock(text, m.width, m.height-8); big != "" {
+		lines += "\n\n" + style.Render(big)
+	} else {
+		lines += "\n\n" + style.Render(text)
+	}
+	if tip := m.tips.For(st); tip != "" {
+		lines += "\n\n" + tip
+	}
+	hint := m.loc.T("Press any key to return")
+	if wait := m.overlay.dismissAt.Sub(now); wait > 0 {
+		hint = m.loc.Sprintf("You can return in %ds", int(wait.Round(time.Second).Seconds()))
+	}
+	lines += "\n\n" + m.theme.faint.Render(hint)
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, lipgloss.JoinVertical(lipgloss.Center, lines))
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestOverlay_HoldsUntilDismissable(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Enforce = config.Enforce{Mode: "overlay", Dismiss: config.Duration{Duration: 10 * time.Second}}
+	m, err := NewModel(eng, nil, Options{Config: cfg})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.width, m.height = 100, 40
+	m.enforced = time.Time{}
+
+	eng.Start()
+	m.checkOverlay(time.Now())
+	if m.overlay != nil {
+		t.Fatal("overlay over a work phase")
+	}
+	eng.Skip() // into the break
+	now := time.Now()
+	m.checkOverlay(now)
+	if m.overlay == nil {
+		t.Fatal("no overlay at the break's start")
+	}
+	if v := m.View(); !strings.Contains(v, "You can return in 10s") {
+		t.Fatalf("overlay view:\n%s", v)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("n")})
+	if m.overlay == nil || eng.State().Phase == core.PhaseWork {
+		t.Fatal("a key got through the overlay")
+	}
+	m.overlay.dismissAt = time.Now()
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("x")})
+	if m.overlay != nil {
+		t.Fatal("overlay not dismissed")
+	}
+	// the same break isn't covered twice
+	m.checkOverlay(time.Now())
+	if m.overlay != nil {
+		t.Fatal("overlay came back")
+	}
+}
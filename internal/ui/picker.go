@@ -0,0 +1,122 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// modal is an overlay that captures keys while open. Its view replaces
+// the help line.
+type modal interface {
+	// handleKey processes a key and reports whether the modal closed.
+	handleKey(msg tea.KeyMsg) (closed bool)
+	View() string
+}
+
+// pickerRows is how many items a picker shows at once; longer lists
+// scroll.
+const pickerRows = 10
+
+// picker is a small modal list.
+type picker struct {
+	title  string
+	items  []string
+	cursor int
+	onPick func(i int)
+}
+
+func newPicker(title string, items []string, current string, onPick func(string)) *picker {
+	p := newIndexPicker(title, items, func(i int) { onPick(items[i]) })
+	for i, it := range items {
+		if it == current {
+			p.cursor = i
+		}
+	}
+	return p
+}
+
+// newIndexPicker is like newPicker but reports the picked position, for
+// lists whose labels may repeat.
+func newIndexPicker(title string, items []string, onPick func(i int)) *picker {
+	return &picker{title: title, items: items, onPick: onPick}
+}
+
+func (p *picker) handleKey(msg tea.KeyMsg) (closed bool) {
+	switch msg.String() {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.items)-1 {
+			p.cursor++
+		}
+	case "enter":
+		if len(p.items) > 0 {
+			p.onPick(p.cursor)
+		}
+		return true
+	case "esc", "q":
+		return true
+	}
+	return false
+}
+
+func (p *picker) View() string {
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render(p.title))
+	b.WriteString("\n")
+	first := min(max(p.cursor-pickerRows/2, 0), max(len(p.items)-pickerRows, 0))
+	last := min(first+pickerRows, len(p.items))
+	for i := first; i < last; i++ {
+		it := p.items[i]
+		if i == p.cursor {
+			b.WriteString(lipgloss.NewStyle().Bold(true).Render("> " + it))
+		} else {
+			b.WriteString("  " + it)
+		}
+		b.WriteString("\n")
+	}
+	if len(p.items) > pickerRows {
+		b.WriteString(lipgloss.NewStyle().Faint(true).Render(fmt.Sprintf("  %d/%d", p.cursor+1, len(p.items))))
+		b.WriteString("\n")
+	}
+	b.WriteString(lipgloss.NewStyle().Faint(true).Render("[↑/↓] move  [enter] select  [esc] cancel"))
+	return b.String()
+}
+
+// linePrompt is a modal asking for one line of text.
+type linePrompt struct {
+	title   string
+	input   textinput.Model
+	onEnter func(string)
+}
+
+func newLinePrompt(title, placeholder, value string, onEnter func(string)) *linePrompt {
+	p := &linePrompt{title: title, input: newLineInput(placeholder), onEnter: onEnter}
+	p.input.SetValue(value)
+	p.input.CursorEnd()
+	return p
+}
+
+func (p *linePrompt) handleKey(msg tea.KeyMsg) (closed bool) {
+	switch msg.String() {
+	case "enter":
+		p.onEnter(p.input.Value())
+		return true
+	case "esc":
+		return true
+	}
+	p.input, _ = p.input.Update(msg)
+	return false
+}
+
+func (p *linePrompt) View() string {
+	title := lipgloss.NewStyle().Bold(true).Render(p.title)
+	help := lipgloss.NewStyle().Faint(true).Render("[enter] save  [esc] cancel")
+	return title + "\n" + p.input.View() + "\n" + help
+}
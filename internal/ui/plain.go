@@ -0,0 +1,199 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/notify"
+)
+
+// Plain mode is for screen readers and dumb terminals: no alt screen,
+// mouse, colors, borders or bars. What happens is printed as a line
+// above a short status that only changes by the minute, so there is
+// little to re-read; keys work as in the full TUI.
+
+// plainLineMsg is a line to print above the plain view.
+type plainLineMsg string
+
+func (m *Model) waitPlainLine() tea.Cmd {
+	if !m.plain {
+		return nil
+	}
+	return func() tea.Msg {
+		return plainLineMsg(<-m.lines)
+	}
+}
+
+// announce queues the line plain mode prints for ev, if any. Lines
+// beyond the queue are dropped rather than holding up the engine.
+func (m *Model) announce(ev core.Event) {
+	line := m.plainLine(ev)
+	if line == "" {
+		return
+	}
+	select {
+	case m.lines <- line:
+	default:
+	}
+}
+
+// plainLine describes ev in a line, or returns "" for events the status
+// shows well enough.
+func (m *Model) plainLine(ev core.Event) string {
+	st := ev.State
+	switch ev.Kind {
+	case core.EventStart, core.EventAdvance, core.EventSkip, core.EventUndo:
+		if st.Idle() {
+			return ""
+		}
+		line := m.loc.Sprintf("Phase: %s", m.loc.Name(st))
+		if st.Open {
+			line += ", " + m.loc.T("FLOW")
+		} else {
+			line += ", " + m.loc.Sprintf("Remaining: %s", minutesText(ev.Remaining))
+		}
+		if tip := m.tips.For(st); tip != "" {
+			line += ". " + m.loc.Sprintf("Break idea: %s", tip)
+		}
+		return line
+	case core.EventPause:
+		return m.loc.T("Paused")
+	case core.EventResume:
+		return m.loc.T("Resumed")
+	case core.EventExtend:
+		return m.loc.Sprintf("Remaining: %s", minutesText(ev.Remaining))
+	case core.EventInterrupt:
+		return m.loc.Sprintf("Interruptions: %d", st.Interruptions)
+	case core.EventWarning:
+		return notify.WarningBody(m.loc, ev)
+	case core.EventOvertime:
+		return m.loc.Sprintf("Work done, overtime running. Press [%s] to take your break.", m.keys[actAcknowledge].Help().Key)
+	case core.EventRefused:
+		return ev.Refusal.Error()
+	case core.EventStop:
+		switch {
+		case ev.QuittingTime:
+			return m.loc.Sprintf("Quitting time, done for today. Press [%s] to keep working.", m.keys[actOverride].Help().Key)
+		case ev.Finished:
+			return notify.FinishedBody(m.loc, m.engine.Config().Cycles)
+		}
+		return m.loc.T("Stopped")
+	}
+	return ""
+}
+
+// minutesText formats d in whole minutes, rounded up, e.g. "25m" or
+// "1h05m", so a status showing it changes once a minute.
+func minutesText(d time.Duration) string {
+	mins := int((d + time.Minute - 1) / time.Minute)
+	if mins >= 60 {
+		return fmt.Sprintf("%dh%02dm", mins/60, mins%60)
+	}
+	return fmt.Sprintf("%dm", mins)
+}
+
+// plainView is View in plain mode.
+func (m *Model) plainView() string {
+	st := m.engine.State()
+	if m.overlay != nil {
+		hint := m.loc.T("Press any key to return")
+		if wait := m.overlay.dismissAt.Sub(time.Now()); wait > 0 {
+			hint = m.loc.Sprintf("You can return in %ds", int(wait.Round(time.Second).Seconds()))
+		}
+		return m.loc.Sprintf("%s: step away from the screen", m.loc.Name(st)) + "\n" +
+			m.loc.Sprintf("Remaining: %s", minutesText(m.engine.Remaining())) + "\n" + hint
+	}
+	if m.keyHelp != nil {
+		return m.keyHelpView(max(m.width, 40), max(m.height, 10))
+	}
+	if m.browser != nil {
+		return m.browser.View(max(m.width, 60), max(m.height-6, 5), lipgloss.NewStyle())
+	}
+	if m.dash != nil {
+		return m.dashboardView(max(m.width, 40)) + "\n" + m.keys.help(actDashboard, actQuit)
+	}
+
+	status := []string{m.loc.Sprintf("Phase: %s", m.loc.Name(st))}
+	switch {
+	case st.Overtime:
+		status = append(status, m.loc.Sprintf("OVERTIME +%s", minutesText(m.engine.Overtime())))
+	case st.Open:
+		status = append(status, m.loc.T("FLOW"), m.loc.Sprintf("Elapsed: %s", minutesText(m.engine.Elapsed())))
+	case !st.Idle():
+		status = append(status, m.loc.Sprintf("Remaining: %s", minutesText(m.engine.Remaining())))
+	}
+	if st.Paused {
+		status = append(status, m.loc.T("Paused"))
+	}
+	status = append(status, m.loc.Sprintf("Completed: %d", st.PomodoroDone))
+	lines := []string{strings.Join(status, ", ")}
+
+	if tabs := m.plainTabs(); tabs != "" {
+		lines = append(lines, tabs)
+	}
+	if !st.Task.IsZero() {
+		lines = append(lines, m.loc.Sprintf("Task: %s", st.Task.Title))
+	}
+	if m.goal != nil {
+		done, target := m.goal.Progress()
+		if target > 0 {
+			lines = append(lines, m.loc.Sprintf("Today: %d/%d", done, target))
+		} else {
+			lines = append(lines, m.loc.Sprintf("Today: %d", done))
+		}
+	}
+	if m.reloadErr != nil {
+		lines = append(lines, m.loc.Sprintf("Config not reloaded: %s", m.reloadErr))
+	}
+	if m.panel != nil {
+		lines = append(lines, m.taskPanelText(max(m.height-8, 5)))
+	}
+	if m.boardOpen {
+		lines = append(lines, m.boardPanelText())
+	}
+
+	switch {
+	case m.modal != nil:
+		lines = append(lines, m.modal.View())
+	case st.Overtime || st.Open:
+		lines = append(lines, m.keys.help(actAcknowledge))
+	case m.engine.AfterHours():
+		lines = append(lines, m.keys.help(actOverride))
+	default:
+		lines = append(lines, m.keys.help(m.helpActions()...))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// plainTabs lists the named timers, e.g. "Timers: *default 12m, tea",
+// or nothing for a single timer.
+func (m *Model) plainTabs() string {
+	if m.timers == nil {
+		return ""
+	}
+	names := m.timers.Names()
+	if len(names) < 2 {
+		return ""
+	}
+	tabs := make([]string, 0, len(names))
+	for _, name := range names {
+		e := m.timers.Get(name)
+		if e == nil {
+			continue
+		}
+		label := name
+		if st := e.State(); !st.Idle() && !st.Open {
+			label += " " + minutesText(e.Remaining())
+		}
+		if name == m.timer {
+			label = "*" + label
+		}
+		tabs = append(tabs, label)
+	}
+	return m.loc.Sprintf("Timers: %s", strings.Join(tabs, ", "))
+}
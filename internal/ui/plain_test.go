@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestMinutesText(t *testing.T) {
+	for d, want := range map[time.Duration]string{
+		25 * time.Minute:             "25m",
+		24*time.Minute + time.Second: "25m",
+		10 * time.Second:             "1m",
+		65 * time.Minute:             "1h05m",
+		2*time.Hour - 30*time.Second: "2h00m",
+		0:                            "0m",
+	} {
+		if got := minutesText(d); got != want {
+			t.Errorf("minutesText(%v) = %q, want %q", d, got, want)
+		}
+	}
+}
+
+func TestPlain_LinesAndView(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	m, err := NewModel(eng, nil, Options{Plain: true})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.width, m.height = 80, 24
+
+	eng.Start()
+	select {
+	case line := <-m.lines:
+		if line != "Phase: WORK, Remaining: 25m" {
+			t.Errorf("start line = %q", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no line for the start")
+	}
+	eng.Pause()
+	select {
+	case line := <-m.lines:
+		if line != "Paused" {
+			t.Errorf("pause line = %q", line)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no line for the pause")
+	}
+
+	v := m.View()
+	if !strings.HasPrefix(v, "Phase: WORK, Remaining: 25m, Paused, Completed: 0\n") {
+		t.Errorf("view:\n%s", v)
+	}
+	if strings.ContainsAny(v, "│─╭╮╰╯█\x1b") || strings.Contains(v, "[c] big clock") {
+		t.Errorf("view isn't plain:\n%s", v)
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"cmp"
+	"reflect"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// Reload is a changed config file for the TUI to adopt, or the error
+// reloading it.
+type Reload struct {
+	Config *config.File
+	Err    error
+}
+
+type reloadMsg Reload
+
+// waitReload returns a command that waits for the next reload.
+func waitReload(reloads <-chan Reload) tea.Cmd {
+	if reloads == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		return reloadMsg(<-reloads)
+	}
+}
+
+// reloadConfig adopts a changed config file: the theme, the count-up
+// default and notification settings at once and, if the main timer's
+// profile changed, its timings from the next phase. Otherwise timing
+// flags stay in effect. Key bindings and suggestions need a restart.
+func (m *Model) reloadConfig(file *config.File) error {
+	name := m.theme.name
+	if file.Theme != m.cfg.Theme && name == cmp.Or(m.cfg.Theme, DefaultTheme) {
+		name = cmp.Or(file.Theme, DefaultTheme)
+	}
+	th, err := loadTheme(file, name)
+	if err != nil {
+		return err
+	}
+	engine, profile := m.engine, m.profile
+	if m.timer != core.DefaultTimer {
+		engine, profile = m.timers.Get(core.DefaultTimer), m.profiles[core.DefaultTimer]
+	}
+	prof, err := file.Resolve(profile)
+	if err != nil {
+		return err
+	}
+	cfg := engine.Config()
+	if old, err := m.cfg.Resolve(profile); err != nil || !reflect.DeepEqual(old, prof) {
+		cfg = prof.Core()
+		cfg.Cycles = engine.Config().Cycles
+	}
+	if file.CountUp != m.cfg.CountUp {
+		m.countUp = file.CountUp
+	}
+	m.cfg = file
+	m.theme = th
+	m.progress = th.newProgress()
+	m.notifyOn.Store(prof.NotificationsEnabled())
+	m.beepOn.Store(prof.WarningSound)
+	engine.ReloadConfig(cfg)
+	return nil
+}
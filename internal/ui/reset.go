@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// confirmPrompt is a modal asking a yes/no question: y runs onYes, any
+// other key says no.
+type confirmPrompt struct {
+	question string
+	onYes    func()
+}
+
+func (p *confirmPrompt) handleKey(msg tea.KeyMsg) (closed bool) {
+	if strings.EqualFold(msg.String(), "y") {
+		p.onYes()
+	}
+	return true
+}
+
+func (p *confirmPrompt) View() string {
+	return lipgloss.NewStyle().Bold(true).Render(p.question) + " y/n"
+}
+
+// reset stops the shown timer. Abandoning a running pomodoro asks
+// first, unless [reset] confirm is off, and then for the reason with
+// [reset] reason on; other phases stop right away.
+func (m *Model) reset() {
+	st := m.engine.State()
+	rc := m.cfg.Reset
+	if st.Phase != core.PhaseWork || st.Overtime || !rc.Confirms() && !rc.Reason {
+		m.engine.Stop()
+		return
+	}
+	stop := func() {
+		if !rc.Reason {
+			m.engine.Stop()
+			return
+		}
+		m.modal = newLinePrompt(m.loc.T("Why? (optional)"), "", "", m.engine.StopWithReason)
+	}
+	if !rc.Confirms() {
+		stop()
+		return
+	}
+	m.modal = &confirmPrompt{question: m.loc.T("Abandon current pomodoro?"), onYes: stop}
+}
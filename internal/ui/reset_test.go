@@ -0,0 +1,93 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestReset_Confirmation(t *testing.T) {
+	no := false
+	for _, tc := range []struct {
+		name   string
+		reset  config.Reset
+		keys   []string
+		idle   bool
+		reason string
+	}{
+		{"no", config.Reset{}, []string{"r", "n"}, false, ""},
+		{"yes", config.Reset{}, []string{"r", "y"}, true, ""},
+		{"yes with a reason", config.Reset{Reason: true}, []string{"r", "y", "m", "e", "enter"}, true, "me"},
+		{"reason skipped", config.Reset{Reason: true}, []string{"r", "y", "enter"}, true, ""},
+		{"reason cancelled", config.Reset{Reason: true}, []string{"r", "y", "esc"}, false, ""},
+		{"unconfirmed", config.Reset{Confirm: &no}, []string{"r"}, true, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+			defer eng.Stop()
+			cfg, err := config.Load("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			cfg.Reset = tc.reset
+			m, err := NewModel(eng, nil, Options{Config: cfg})
+			if err != nil {
+				t.Fatalf("NewModel: %v", err)
+			}
+			defer m.unsubscribe()
+			defer m.cancelTicks()
+			reasons := make(chan string, 1)
+			defer eng.Subscribe(func(ev core.Event) {
+				if ev.Kind == core.EventAbandon {
+					reasons <- ev.AbandonReason
+				}
+			})()
+
+			eng.Start()
+			for _, k := range tc.keys {
+				switch k {
+				case "enter":
+					m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+				case "esc":
+					m.Update(tea.KeyMsg{Type: tea.KeyEsc})
+				default:
+					m.Update(keyMsg(k))
+				}
+			}
+			if eng.State().Idle() != tc.idle {
+				t.Fatalf("idle = %v, want %v", eng.State().Idle(), tc.idle)
+			}
+			if !tc.idle {
+				return
+			}
+			select {
+			case r := <-reasons:
+				if r != tc.reason {
+					t.Errorf("reason = %q, want %q", r, tc.reason)
+				}
+			case <-time.After(time.Second):
+				t.Fatal("no EventAbandon")
+			}
+		})
+	}
+
+	// a break stops without asking
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	m, err := NewModel(eng, nil, Options{})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	eng.Start()
+	eng.Skip()
+	m.Update(keyMsg("r"))
+	if !eng.State().Idle() {
+		t.Fatal("reset asked during a break")
+	}
+}
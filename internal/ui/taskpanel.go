@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// panelWidth is the task panel's width, border included.
+const panelWidth = 36
+
+// taskPanel is the side panel listing the tasks with the pomodoros done
+// on each and their estimates. Picking one attaches it, from the next
+// work phase if a break runs.
+type taskPanel struct {
+	tasks   []core.Task
+	err     error
+	loading bool
+	cursor  int
+	// done counts completed pomodoros per task in the history and
+	// estimates holds the last estimate recorded there, as of seen
+	// pomodoros done in the engine.
+	done      map[taskKey]int
+	estimates map[taskKey]int
+	seen      int
+}
+
+// taskKey identifies a task across sources.
+type taskKey struct{ source, id string }
+
+func keyOf(t core.Task) taskKey { return taskKey{t.Source, t.ID} }
+
+// loadPanelTasks is loadTasks for the panel.
+func loadPanelTasks(sources []TaskSource) tea.Cmd {
+	load := loadTasks(sources)
+	return func() tea.Msg {
+		msg := load().(tasksMsg)
+		msg.panel = true
+		return msg
+	}
+}
+
+// toggleTaskPanel opens the panel and loads its tasks, or closes it.
+func (m *Model) toggleTaskPanel() tea.Cmd {
+	if m.panel != nil {
+		m.panel = nil
+		return nil
+	}
+	m.panel = &taskPanel{loading: true}
+	m.boardOpen = false
+	return loadPanelTasks(m.tasks)
+}
+
+// fillTaskPanel shows the loaded tasks, unless the panel was closed in
+// the meantime.
+func (m *Model) fillTaskPanel(msg tasksMsg) {
+	if m.panel == nil {
+		return
+	}
+	m.panel.tasks, m.panel.err, m.panel.loading = msg.tasks, msg.err, false
+	m.panel.cursor = 0
+	cur := m.engine.State().Task
+	for i, t := range msg.tasks {
+		if keyOf(t) == keyOf(cur) {
+			m.panel.cursor = i
+		}
+	}
+	m.countPanelDone()
+}
+
+// countPanelDone recounts the pomodoros done per task from the history.
+func (m *Model) countPanelDone() {
+	m.panel.seen = m.engine.State().PomodoroDone
+	if m.history == nil {
+		return
+	}
+	sessions, err := m.history.List()
+	if err != nil {
+		return
+	}
+	m.panel.done, m.panel.estimates = map[taskKey]int{}, map[taskKey]int{}
+	for _, s := range sessions {
+		if s.Task == nil || s.Phase != core.PhaseWork.String() {
+			continue
+		}
+		k := taskKey{s.Task.Source, s.Task.ID}
+		if s.Completed {
+			m.panel.done[k]++
+		}
+		if s.Task.Estimate > 0 {
+			m.panel.estimates[k] = s.Task.Estimate
+		}
+	}
+}
+
+// panelKey moves the panel's cursor or picks the task under it; it
+// reports whether it used the key.
+func (m *Model) panelKey(msg tea.KeyMsg) bool {
+	p := m.panel
+	if p == nil || len(p.tasks) == 0 {
+		return false
+	}
+	switch msg.String() {
+	case "up":
+		p.cursor = max(p.cursor-1, 0)
+	case "down":
+		p.cursor = min(p.cursor+1, len(p.tasks)-1)
+	case "enter":
+		m.attachTask(p.tasks[p.cursor])
+	default:
+		return false
+	}
+	return true
+}
+
+// countText renders a task's pomodoros, e.g. "2/4" against an
+// estimate of 4.
+func countText(done, estimate int) string {
+	if estimate > 0 {
+		return fmt.Sprintf("%d/%d", done, estimate)
+	}
+	return fmt.Sprint(done)
+}
+
+// taskPanelView renders the panel, height rows tall at most.
+func (m *Model) taskPanelView(height int) string {
+	return lipgloss.NewStyle().
+		Border(m.theme.border).
+		Padding(0, 1).
+		Width(panelWidth - 2).
+		Render(m.taskPanelText(height))
+}
+
+// taskPanelText is the panel's content, without the border.
+func (m *Model) taskPanelText(height int) string {
+	p := m.panel
+	inner := panelWidth - 4
+	var b strings.Builder
+	b.WriteString(lipgloss.NewStyle().Bold(true).Render("Tasks") + "\n\n")
+	switch {
+	case p.loading:
+		b.WriteString(m.theme.faint.Render("Loading…"))
+	case len(p.tasks) == 0 && p.err != nil:
+		b.WriteString(ansi.Wordwrap("Loading tasks failed: "+p.err.Error(), inner, ""))
+	case len(p.tasks) == 0:
+		b.WriteString(m.theme.faint.Render("No tasks."))
+	default:
+		cur := m.engine.State().Task
+		// keep the cursor in view
+		rows := max(height-8, 1)
+		first := max(min(p.cursor-rows/2, len(p.tasks)-rows), 0)
+		for i := first; i < min(first+rows, len(p.tasks)); i++ {
+			t := p.tasks[i]
+			est := cmp.Or(t.Estimate, p.estimates[keyOf(t)])
+			if keyOf(t) == keyOf(cur) && cur.Estimate > 0 {
+				est = cur.Estimate
+			}
+			count := "🍅" + countText(p.done[keyOf(t)], est)
+			marker := "  "
+			if i == p.cursor {
+				marker = "› "
+			}
+			title := ansi.Truncate(t.Title, inner-lipgloss.Width(marker)-lipgloss.Width(count)-1, "…")
+			gap := max(inner-lipgloss.Width(marker+title+count), 1)
+			line := marker + title + strings.Repeat(" ", gap) + count
+			if keyOf(t) == keyOf(cur) {
+				line = m.theme.phase[core.PhaseWork].Render(line)
+			}
+			b.WriteString(line + "\n")
+		}
+		b.WriteString("\n" + m.theme.faint.Render("↑/↓ move • enter attach"))
+	}
+	return b.String()
+}
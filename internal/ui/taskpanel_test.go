@@ -0,0 +1,50 @@
+package ui
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+type fakeTasks []core.Task
+
+func (f fakeTasks) Tasks(context.Context) ([]core.Task, error) { return f, nil }
+
+func TestTaskPanel_AttachesPickedTask(t *testing.T) {
+	tasks := fakeTasks{
+		{Source: "file", ID: "a", Title: "Write the report", Estimate: 4},
+		{Source: "file", ID: "b", Title: "Review PRs"},
+	}
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	m, err := NewModel(eng, nil, Options{Tasks: []TaskSource{tasks}})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.width, m.height = 120, 40
+
+	_, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if cmd == nil {
+		t.Fatal("opening the panel loaded no tasks")
+	}
+	m.Update(cmd())
+	if view := m.View(); !strings.Contains(view, "Write the report") || !strings.Contains(view, "🍅0/4") {
+		t.Fatalf("panel doesn't list the tasks with estimates:\n%s", view)
+	}
+	m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	m.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := eng.State().Task; got != tasks[1] {
+		t.Fatalf("attached %+v, want %+v", got, tasks[1])
+	}
+
+	m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("l")})
+	if m.panel != nil {
+		t.Fatal("l didn't close the panel")
+	}
+}
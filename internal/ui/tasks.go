@@ -0,0 +1,141 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+)
+
+// TaskSource lists tasks the user can attach to work sessions, e.g.
+// from a to-do app.
+type TaskSource interface {
+	Tasks(ctx context.Context) ([]core.Task, error)
+}
+
+// taskTimeout bounds loading tasks from every source.
+const taskTimeout = 15 * time.Second
+
+// noTask is the picker entry that detaches the current task.
+const noTask = "(no task)"
+
+// tasksMsg carries the tasks loaded for the picker.
+type tasksMsg struct {
+	tasks []core.Task
+	err   error
+	panel bool // for the task panel rather than the picker
+}
+
+// loadTasks queries every source off the update loop. Sources that fail
+// are reported but don't hide the others' tasks.
+func loadTasks(sources []TaskSource) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), taskTimeout)
+		defer cancel()
+		var msg tasksMsg
+		var errs []error
+		for _, src := range sources {
+			ts, err := src.Tasks(ctx)
+			if err != nil {
+				errs = append(errs, err)
+			}
+			msg.tasks = append(msg.tasks, ts...)
+		}
+		msg.err = errors.Join(errs...)
+		return msg
+	}
+}
+
+// notice is a modal showing a line of text until any key is pressed.
+type notice struct{ text string }
+
+func (n *notice) handleKey(tea.KeyMsg) (closed bool) { return true }
+
+func (n *notice) View() string {
+	return n.text + "\n" + lipgloss.NewStyle().Faint(true).Render("[any key] close")
+}
+
+// openTaskPicker shows the loaded tasks, unless the user closed the
+// loading notice in the meantime.
+func (m *Model) openTaskPicker(msg tasksMsg) {
+	if m.modal == nil || m.modal != m.taskLoad {
+		return
+	}
+	m.taskLoad = nil
+	if len(msg.tasks) == 0 {
+		text := m.loc.T("No tasks.")
+		if msg.err != nil {
+			text = m.loc.Sprintf("Loading tasks failed: %s", msg.err)
+		}
+		m.modal = &notice{text: text}
+		return
+	}
+	sources := map[string]bool{}
+	for _, t := range msg.tasks {
+		sources[t.Source] = true
+	}
+	items := []string{m.loc.T(noTask)}
+	current := 0
+	cur := m.engine.State().Task
+	for i, t := range msg.tasks {
+		label := t.Title
+		if len(sources) > 1 {
+			label = "[" + t.Source + "] " + label
+		}
+		items = append(items, label)
+		if t == cur {
+			current = i + 1
+		}
+	}
+	title := m.loc.T("Task")
+	if msg.err != nil {
+		title = m.loc.Sprintf("Task (some sources failed: %s)", msg.err)
+	}
+	p := newIndexPicker(title, items, func(i int) {
+		if i == 0 {
+			m.engine.SetTask(core.Task{})
+			return
+		}
+		m.attachTask(msg.tasks[i-1])
+	})
+	p.cursor = current
+	m.modal = p
+}
+
+// maxEstimate is the largest estimate the estimate picker offers.
+const maxEstimate = 12
+
+// attachTask attaches t to the engine. A task without an estimate gets
+// the last one recorded for it in the history.
+func (m *Model) attachTask(t core.Task) {
+	if t.Estimate == 0 && m.history != nil {
+		if sessions, err := m.history.List(); err == nil {
+			t.Estimate = stats.LatestEstimate(sessions, t.Source, t.ID)
+		}
+	}
+	m.engine.SetTask(t)
+}
+
+// openEstimatePicker asks how many pomodoros the attached task will
+// take.
+func (m *Model) openEstimatePicker() {
+	t := m.engine.State().Task
+	if t.IsZero() {
+		return
+	}
+	items := []string{"none"}
+	for n := 1; n <= maxEstimate; n++ {
+		items = append(items, strconv.Itoa(n))
+	}
+	m.modal = newIndexPicker("Estimate: "+t.Title, items, func(i int) {
+		t.Estimate = i
+		m.engine.SetTask(t)
+	})
+	m.modal.(*picker).cursor = min(t.Estimate, maxEstimate)
+}
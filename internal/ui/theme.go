@@ -0,0 +1,251 @@
+package ui
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// DefaultTheme is used when neither the config nor a flag picks one.
+const DefaultTheme = "default"
+
+// builtinThemes are always available; config themes may extend them.
+var builtinThemes = map[string]config.Theme{
+	"default": {
+		GradientStart: "#5A56E0", GradientEnd: "#EE6FF8",
+		Work: "#E05A5A", ShortBreak: "#5AC85A", LongBreak: "#3FA7D6",
+		Overtime: "208", Goal: "42", Border: "rounded",
+		Bars: map[string]config.Bar{
+			"short_break": {GradientStart: "#3FA7D6", GradientEnd: "#5AC85A"},
+			"long_break":  {GradientStart: "#5A56E0", GradientEnd: "#3FA7D6"},
+		},
+	},
+	"nord": {
+		GradientStart: "#5E81AC", GradientEnd: "#88C0D0",
+		Work: "#BF616A", ShortBreak: "#A3BE8C", LongBreak: "#81A1C1",
+		Overtime: "#D08770", Goal: "#A3BE8C", Faint: "#4C566A", Border: "rounded",
+		Bars: map[string]config.Bar{
+			"short_break": {GradientStart: "#8FBCBB", GradientEnd: "#A3BE8C"},
+			"long_break":  {GradientStart: "#5E81AC", GradientEnd: "#81A1C1"},
+		},
+	},
+	"dracula": {
+		GradientStart: "#BD93F9", GradientEnd: "#FF79C6",
+		Work: "#FF5555", ShortBreak: "#50FA7B", LongBreak: "#8BE9FD",
+		Overtime: "#FFB86C", Goal: "#50FA7B", Faint: "#6272A4", Border: "double",
+		Bars: map[string]config.Bar{
+			"short_break": {GradientStart: "#8BE9FD", GradientEnd: "#50FA7B"},
+			"long_break":  {GradientStart: "#6272A4", GradientEnd: "#8BE9FD"},
+		},
+	},
+	"solarized": {
+		GradientStart: "#268BD2", GradientEnd: "#2AA198",
+		Work: "#DC322F", ShortBreak: "#859900", LongBreak: "#268BD2",
+		Overtime: "#CB4B16", Goal: "#859900", Faint: "#586E75", Border: "normal",
+		Bars: map[string]config.Bar{
+			"short_break": {GradientStart: "#2AA198", GradientEnd: "#859900"},
+			"long_break":  {GradientStart: "#6C71C4", GradientEnd: "#268BD2"},
+		},
+	},
+	"mono": {
+		GradientStart: "245", GradientEnd: "255",
+		Work: "255", ShortBreak: "250", LongBreak: "250",
+		Overtime: "255", Goal: "255", Border: "normal",
+	},
+}
+
+var colorRe = regexp.MustCompile(`^(#[0-9a-fA-F]{6}|[0-9]{1,3})$`)
+
+var borders = map[string]lipgloss.Border{
+	"rounded": lipgloss.RoundedBorder(),
+	"normal":  lipgloss.NormalBorder(),
+	"thick":   lipgloss.ThickBorder(),
+	"double":  lipgloss.DoubleBorder(),
+	"hidden":  lipgloss.HiddenBorder(),
+}
+
+// barPhases are the phases a theme sets progress bars for.
+var barPhases = []core.Phase{core.PhaseWork, core.PhaseShortBreak, core.PhaseLongBreak}
+
+// barKey is a phase's key in config.Theme.Bars, e.g. "short_break".
+func barKey(ph core.Phase) string {
+	return strings.ToLower(ph.String())
+}
+
+// theme holds the styles derived from a config.Theme.
+type theme struct {
+	name     string
+	gradient [2]string
+	bars     map[core.Phase]bar
+	phase    map[core.Phase]lipgloss.Style
+	overtime lipgloss.Style
+	goal     lipgloss.Style
+	faint    lipgloss.Style
+	border   lipgloss.Border
+}
+
+// bar is how a phase's progress bar renders.
+type bar struct {
+	gradient [2]string
+	drain    bool // shrink as the phase passes instead of growing
+}
+
+// themeNames lists the built-in and configured themes, sorted.
+func themeNames(cfg *config.File) []string {
+	names := make([]string, 0, len(builtinThemes)+len(cfg.Themes))
+	for n := range builtinThemes {
+		names = append(names, n)
+	}
+	for n := range cfg.Themes {
+		if _, ok := builtinThemes[n]; !ok {
+			names = append(names, n)
+		}
+	}
+	slices.Sort(names)
+	return names
+}
+
+// ThemeNames lists the themes -theme accepts with cfg, for shell
+// completion.
+func ThemeNames(cfg *config.File) []string {
+	return themeNames(cfg)
+}
+
+// loadTheme resolves the named theme from cfg and the built-ins.
+func loadTheme(cfg *config.File, name string) (theme, error) {
+	if name == "" {
+		name = cmp.Or(cfg.Theme, DefaultTheme)
+	}
+	t, err := resolveTheme(cfg, name, 0)
+	if err != nil {
+		return theme{}, err
+	}
+	for _, c := range []string{t.GradientStart, t.GradientEnd, t.Work, t.ShortBreak, t.LongBreak, t.Overtime, t.Goal} {
+		if !colorRe.MatchString(c) {
+			return theme{}, fmt.Errorf("theme %q: bad color %q", name, c)
+		}
+	}
+	if t.Faint != "" && !colorRe.MatchString(t.Faint) {
+		return theme{}, fmt.Errorf("theme %q: bad color %q", name, t.Faint)
+	}
+	border, ok := borders[t.Border]
+	if !ok {
+		return theme{}, fmt.Errorf("theme %q: unknown border %q", name, t.Border)
+	}
+	for key := range t.Bars {
+		if !slices.ContainsFunc(barPhases, func(ph core.Phase) bool { return barKey(ph) == key }) {
+			return theme{}, fmt.Errorf("theme %q: bar for unknown phase %q", name, key)
+		}
+	}
+	bars := make(map[core.Phase]bar, len(barPhases))
+	for _, ph := range barPhases {
+		b := t.Bars[barKey(ph)]
+		start, end := cmp.Or(b.GradientStart, t.GradientStart), cmp.Or(b.GradientEnd, t.GradientEnd)
+		for _, c := range []string{start, end} {
+			if !colorRe.MatchString(c) {
+				return theme{}, fmt.Errorf("theme %q: bad color %q", name, c)
+			}
+		}
+		switch b.Mode {
+		case "", "fill", "drain":
+		default:
+			return theme{}, fmt.Errorf("theme %q: %s bar mode must be fill or drain", name, barKey(ph))
+		}
+		bars[ph] = bar{
+			gradient: [2]string{start, end},
+			drain:    b.Mode == "drain" || b.Mode == "" && ph != core.PhaseWork,
+		}
+	}
+	// idle shows the empty bar of the work it starts
+	bars[core.PhaseIdle] = bars[core.PhaseWork]
+
+	bold := lipgloss.NewStyle().Bold(true)
+	th := theme{
+		name:     name,
+		gradient: [2]string{t.GradientStart, t.GradientEnd},
+		bars:     bars,
+		phase: map[core.Phase]lipgloss.Style{
+			core.PhaseWork:       bold.Foreground(lipgloss.Color(t.Work)),
+			core.PhaseShortBreak: bold.Foreground(lipgloss.Color(t.ShortBreak)),
+			core.PhaseLongBreak:  bold.Foreground(lipgloss.Color(t.LongBreak)),
+			core.PhaseIdle:       bold,
+		},
+		overtime: bold.Foreground(lipgloss.Color(t.Overtime)),
+		goal:     bold.Foreground(lipgloss.Color(t.Goal)),
+		faint:    lipgloss.NewStyle().Faint(true),
+		border:   border,
+	}
+	if t.Faint != "" {
+		th.faint = lipgloss.NewStyle().Foreground(lipgloss.Color(t.Faint))
+	}
+	return th, nil
+}
+
+// resolveTheme fills unset fields of the named theme from its base.
+func resolveTheme(cfg *config.File, name string, depth int) (config.Theme, error) {
+	if depth > 8 {
+		return config.Theme{}, fmt.Errorf("theme %q: base chain too deep", name)
+	}
+	t, ok := cfg.Themes[name]
+	if !ok {
+		if t, ok = builtinThemes[name]; !ok {
+			return config.Theme{}, fmt.Errorf("unknown theme %q", name)
+		}
+		return t, nil
+	}
+	base := cmp.Or(t.Base, DefaultTheme)
+	if base == name {
+		// a config theme named like a built-in overrides it
+		if _, ok := builtinThemes[name]; !ok {
+			return config.Theme{}, fmt.Errorf("theme %q: cannot be its own base", name)
+		}
+		return merge(t, builtinThemes[name]), nil
+	}
+	b, err := resolveTheme(cfg, base, depth+1)
+	if err != nil {
+		return config.Theme{}, err
+	}
+	return merge(t, b), nil
+}
+
+func merge(t, base config.Theme) config.Theme {
+	t.GradientStart = cmp.Or(t.GradientStart, base.GradientStart)
+	t.GradientEnd = cmp.Or(t.GradientEnd, base.GradientEnd)
+	t.Work = cmp.Or(t.Work, base.Work)
+	t.ShortBreak = cmp.Or(t.ShortBreak, base.ShortBreak)
+	t.LongBreak = cmp.Or(t.LongBreak, base.LongBreak)
+	t.Overtime = cmp.Or(t.Overtime, base.Overtime)
+	t.Goal = cmp.Or(t.Goal, base.Goal)
+	t.Faint = cmp.Or(t.Faint, base.Faint)
+	t.Border = cmp.Or(t.Border, base.Border)
+	bars := make(map[string]config.Bar, len(base.Bars)+len(t.Bars))
+	for key, b := range base.Bars {
+		bars[key] = b
+	}
+	for key, b := range t.Bars {
+		bb := bars[key]
+		b.GradientStart = cmp.Or(b.GradientStart, bb.GradientStart)
+		b.GradientEnd = cmp.Or(b.GradientEnd, bb.GradientEnd)
+		b.Mode = cmp.Or(b.Mode, bb.Mode)
+		bars[key] = b
+	}
+	t.Bars = bars
+	return t
+}
+
+// newProgress builds a progress bar per phase.
+func (t theme) newProgress() map[core.Phase]progress.Model {
+	models := make(map[core.Phase]progress.Model, len(t.bars))
+	for ph, b := range t.bars {
+		models[ph] = progress.New(progress.WithGradient(b.gradient[0], b.gradient[1]))
+	}
+	return models
+}
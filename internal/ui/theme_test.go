@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"testing"
+
+	"github.com/ezchuang/GoPomodoro/internal/config"
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestLoadTheme_CustomInheritsBase(t *testing.T) {
+	cfg := &config.File{Themes: map[string]config.Theme{
+		"mine":   {Base: "nord", Work: "#ff0000"},
+		"broken": {Work: "red"},
+		"loop":   {Base: "loop2"},
+		"loop2":  {Base: "loop"},
+	}}
+	th, err := loadTheme(cfg, "mine")
+	if err != nil {
+		t.Fatalf("loadTheme: %v", err)
+	}
+	if th.gradient != [2]string{"#5E81AC", "#88C0D0"} {
+		t.Fatalf("gradient should come from nord, got %v", th.gradient)
+	}
+	if names := themeNames(cfg); len(names) != len(builtinThemes)+4 {
+		t.Fatalf("unexpected names %v", names)
+	}
+	for _, name := range []string{"broken", "loop", "nope"} {
+		if _, err := loadTheme(cfg, name); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+	for name := range builtinThemes {
+		if _, err := loadTheme(&config.File{}, name); err != nil {
+			t.Errorf("built-in %s: %v", name, err)
+		}
+	}
+}
+
+func TestLoadTheme_PhaseBars(t *testing.T) {
+	cfg := &config.File{Themes: map[string]config.Theme{
+		"mine": {
+			Base: "nord",
+			Bars: map[string]config.Bar{
+				"work":       {Mode: "drain"},
+				"long_break": {GradientEnd: "#ffffff", Mode: "fill"},
+			},
+		},
+		"badmode":  {Bars: map[string]config.Bar{"work": {Mode: "empty"}}},
+		"badphase": {Bars: map[string]config.Bar{"lunch": {}}},
+	}}
+	th, err := loadTheme(cfg, "mine")
+	if err != nil {
+		t.Fatalf("loadTheme: %v", err)
+	}
+	want := map[core.Phase]bar{
+		core.PhaseWork:       {gradient: [2]string{"#5E81AC", "#88C0D0"}, drain: true},
+		core.PhaseShortBreak: {gradient: [2]string{"#8FBCBB", "#A3BE8C"}, drain: true},
+		core.PhaseLongBreak:  {gradient: [2]string{"#5E81AC", "#ffffff"}},
+	}
+	for ph, b := range want {
+		if th.bars[ph] != b {
+			t.Errorf("%v bar = %+v, want %+v", ph, th.bars[ph], b)
+		}
+	}
+	if builtinThemes["nord"].Bars["long_break"].GradientEnd != "#81A1C1" {
+		t.Error("merging changed the built-in theme")
+	}
+	for _, name := range []string{"badmode", "badphase"} {
+		if _, err := loadTheme(cfg, name); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
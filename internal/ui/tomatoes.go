@@ -0,0 +1,58 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+// The tomato row shows the pomodoros of the current cycle, done or to
+// go, and where the long break falls, e.g. "🍅🍅🍅○ ☕".
+const (
+	tomatoDone = "🍅"
+	tomatoToGo = "○"
+	tomatoLong = "☕"
+)
+
+// tomatoRow renders the row for st under cfg, or "" when breaks aren't
+// counted in pomodoros: with no long break, or the time cadence, which
+// takes a long break after an amount of focus time instead.
+func tomatoRow(cfg core.Config, st core.State) string {
+	if len(cfg.Cycle) > 0 {
+		return stepRow(cfg.Cycle, st)
+	}
+	n := cfg.LongEvery
+	if n <= 0 || cfg.Cadence == core.CadenceTime {
+		return ""
+	}
+	done := st.PomodoroDone % n
+	if done == 0 && st.PomodoroDone > 0 && st.Phase == core.PhaseLongBreak {
+		// the long break ends the cycle it rewards
+		done = n
+	}
+	return strings.Repeat(tomatoDone, done) + strings.Repeat(tomatoToGo, n-done) + " " + tomatoLong
+}
+
+// stepRow is tomatoRow for a custom cycle: a tomato per work step, and
+// a cup for each long break step, in their order. The steps before the
+// current one are done.
+func stepRow(cycle []core.Step, st core.State) string {
+	current := -1
+	if !st.Idle() && st.Label != "" {
+		current = st.Step
+	}
+	var b strings.Builder
+	for i, step := range cycle {
+		switch step.Kind {
+		case core.PhaseWork:
+			if i < current {
+				b.WriteString(tomatoDone)
+			} else {
+				b.WriteString(tomatoToGo)
+			}
+		case core.PhaseLongBreak:
+			b.WriteString(" " + tomatoLong + " ")
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
@@ -0,0 +1,40 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestTomatoRow(t *testing.T) {
+	count := core.Config{LongEvery: 4}
+	cycle := core.Config{Cycle: []core.Step{
+		{Name: "work", Kind: core.PhaseWork},
+		{Name: "stretch", Kind: core.PhaseShortBreak},
+		{Name: "work", Kind: core.PhaseWork},
+		{Name: "long", Kind: core.PhaseLongBreak},
+		{Name: "work", Kind: core.PhaseWork},
+	}}
+	for _, tc := range []struct {
+		name string
+		cfg  core.Config
+		st   core.State
+		want string
+	}{
+		{"idle", count, core.State{}, "○○○○ ☕"},
+		{"third work", count, core.State{Phase: core.PhaseWork, PomodoroDone: 2}, "🍅🍅○○ ☕"},
+		{"long break", count, core.State{Phase: core.PhaseLongBreak, PomodoroDone: 4}, "🍅🍅🍅🍅 ☕"},
+		{"after the long break", count, core.State{Phase: core.PhaseWork, PomodoroDone: 4}, "○○○○ ☕"},
+		{"next cycle", count, core.State{Phase: core.PhaseShortBreak, PomodoroDone: 5}, "🍅○○○ ☕"},
+		{"no long break", core.Config{LongEvery: -1}, core.State{PomodoroDone: 2}, ""},
+		{"time cadence", core.Config{LongEvery: 4, Cadence: core.CadenceTime, LongAfter: 2 * time.Hour}, core.State{}, ""},
+		{"cycle idle", cycle, core.State{}, "○○ ☕ ○"},
+		{"cycle second work", cycle, core.State{Phase: core.PhaseWork, Step: 2, Label: "work"}, "🍅○ ☕ ○"},
+		{"cycle long break", cycle, core.State{Phase: core.PhaseLongBreak, Step: 3, Label: "long"}, "🍅🍅 ☕ ○"},
+	} {
+		if got := tomatoRow(tc.cfg, tc.st); got != tc.want {
+			t.Errorf("%s: tomatoRow = %q, want %q", tc.name, got, tc.want)
+		}
+	}
+}
@@ -1,55 +1,320 @@
 package ui
 
 import (
+	"cmp"
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
+	"github.com/ezchuang/GoPomodoro/internal/config"
 	"github.com/ezchuang/GoPomodoro/internal/core"
+	"github.com/ezchuang/GoPomodoro/internal/history"
+	"github.com/ezchuang/GoPomodoro/internal/i18n"
 	"github.com/ezchuang/GoPomodoro/internal/notify"
+	"github.com/ezchuang/GoPomodoro/internal/stats"
+	"github.com/ezchuang/GoPomodoro/internal/suggest"
 )
 
+// Options carries optional settings for the TUI.
+type Options struct {
+	// Config provides the profiles offered by the picker;
+	// nil means the built-in profiles only.
+	Config *config.File
+	// Profile is the name of the profile the engine was started with.
+	Profile string
+	// Scheduled follows the config's schedule, switching the main
+	// timer's profile as its rules start and end, until one is picked by
+	// hand.
+	Scheduled bool
+	// Goal, if set, provides today's progress toward the daily goal.
+	// The model feeds it the engine's events, so notifications see the
+	// count with the phase just ended.
+	Goal *stats.Goal
+	// Templates rewrite the notifications; nil keeps the built-in text.
+	Templates *notify.Templates
+	// Locale translates the TUI and its notifications; nil is English.
+	Locale *i18n.Locale
+	// Plain renders for screen readers and dumb terminals: line-based,
+	// without the alt screen, mouse, colors or box drawing.
+	Plain bool
+	// Mini starts in mini mode: a single line without the alt screen.
+	Mini bool
+	// Theme overrides the config file's theme.
+	Theme string
+	// History feeds the stats dashboard; nil disables it.
+	History *history.Store
+	// Tasks fill the task picker; without any the task key does nothing.
+	Tasks []TaskSource
+	// Timers, holding the engine as core.DefaultTimer, adds a tab per
+	// named timer.
+	Timers *core.Manager
+	// Team lists who shares the timer in a team session.
+	Team Team
+	// Leaderboard provides the team leaderboard's board; without one
+	// the leaderboard key does nothing.
+	Leaderboard Leaderboard
+	// Reloads delivers the config file each time it changes on disk.
+	Reloads <-chan Reload
+}
+
+// Team is a shared session's member list.
+type Team interface {
+	Members() []string
+}
+
 type Model struct {
 	engine   *core.PomodoroEngine
 	notifier notify.Notifier
+	cfg      *config.File
+	goal     *stats.Goal
+	history  *history.Store
+	tasks    []TaskSource
+
+	// timers are the tabs; engine is the one shown, named timer, and
+	// profiles remembers the profile of the others.
+	timers   *core.Manager
+	timer    string
+	profiles map[string]string
+	team     Team
+	board    Leaderboard
+	tips     *suggest.Suggester // nil with suggestions off
+	reloads  <-chan Reload
+	// ticks wakes the view on the shown engine's ticks
+	ticks       chan struct{}
+	cancelTicks func()
+	// reloadErr is why the last config reload failed, until one works.
+	reloadErr error
 
 	width  int
 	height int
 
-	progress progress.Model
+	profile     string
+	scheduled   bool // follow the config's schedule
+	notifyOn    atomic.Bool
+	beepOn      atomic.Bool // beep with pre-end warnings
+	modal       modal
+	bigClock    bool
+	zen         bool            // only the big clock
+	mini        bool            // a single line, outside the alt screen
+	countUp     bool            // show elapsed instead of remaining time
+	dash        *dashboard      // non-nil while the stats screen is shown
+	browser     *historyBrowser // non-nil while the history screen is shown
+	keyHelp     *keyHelp        // non-nil while the key help is shown
+	taskLoad    *notice         // the modal shown while tasks load
+	panel       *taskPanel      // non-nil while the task panel is shown
+	boardOpen   bool            // the leaderboard panel is shown
+	overlay     *breakOverlay   // non-nil while an enforced break covers the TUI
+	enforced    time.Time       // start of the last break the overlay covered
+	unsubscribe func()
+	// finished fires when the engine ends a run of Config.Cycles, which
+	// quits the TUI; done records that it did.
+	finished chan struct{}
+	done     bool
+
+	// plain mode prints the engine's events, queued on lines
+	plain bool
+	lines chan string
+
+	loc      *i18n.Locale
+	keys     keyMap
+	theme    theme
+	progress map[core.Phase]progress.Model
+	zones    zones // where the last view drew clickable parts
 	quit     bool
 }
 
-func NewModel(engine *core.PomodoroEngine, notifier notify.Notifier) (*Model, error) {
+func NewModel(engine *core.PomodoroEngine, notifier notify.Notifier, opts Options) (*Model, error) {
+	cfg := opts.Config
+	if cfg == nil {
+		var err error
+		if cfg, err = config.Load(""); err != nil {
+			return nil, err
+		}
+	}
 	m := &Model{
-		engine:   engine,
-		notifier: notifier,
-		progress: progress.New(progress.WithDefaultGradient()),
+		engine:    engine,
+		notifier:  notifier,
+		cfg:       cfg,
+		goal:      opts.Goal,
+		loc:       opts.Locale,
+		plain:     opts.Plain,
+		mini:      opts.Mini,
+		lines:     make(chan string, 16),
+		history:   opts.History,
+		tasks:     opts.Tasks,
+		profile:   opts.Profile,
+		scheduled: opts.Scheduled,
+		timers:    opts.Timers,
+		team:      opts.Team,
+		board:     opts.Leaderboard,
+		reloads:   opts.Reloads,
+		timer:     core.DefaultTimer,
+		profiles:  map[string]string{},
+		countUp:   cfg.CountUp,
+		// a break already running isn't covered
+		enforced: time.Now(),
+		finished: make(chan struct{}, 1),
+	}
+	if m.profile == "" {
+		m.profile = cfg.Profile
+	}
+	for name, prof := range cfg.Timers {
+		m.profiles[name] = cmp.Or(prof, m.profile)
 	}
-	// subscribe to phase changes to send notifications
-	engine.SetOnAdvance(func(st core.State) {
+	prof, err := cfg.Resolve(m.profile)
+	if err != nil {
+		return nil, err
+	}
+	if sc := cfg.Suggestions; sc.On() {
+		m.tips = suggest.New(sc.Short, sc.Long)
+	}
+	m.notifyOn.Store(prof.NotificationsEnabled())
+	m.beepOn.Store(prof.WarningSound)
+	if m.keys, err = newKeyMap(cfg.Keys); err != nil {
+		return nil, err
+	}
+	m.keys.translate(m.loc)
+	if m.theme, err = loadTheme(cfg, opts.Theme); err != nil {
+		return nil, err
+	}
+	m.progress = m.theme.newProgress()
+	m.ticks = make(chan struct{}, 1)
+	m.followTicks()
+
+	// subscribe to phase changes to send notifications; like the
+	// notifier's, the quiet hours are the ones at startup
+	quiet := cfg.Notify.Quiet
+	m.unsubscribe = engine.Subscribe(func(ev core.Event) {
+		if m.goal != nil {
+			m.goal.Handle(ev)
+		}
+		if ev.Kind == core.EventStop && ev.Finished {
+			select {
+			case m.finished <- struct{}{}:
+			default:
+			}
+		}
+		if m.plain {
+			m.announce(ev)
+		}
+		if !m.notifyOn.Load() {
+			return
+		}
 		title := "GoPomodoro"
-		body := fmt.Sprintf("Phase: %s", st.Phase.String())
-		_ = notifier.Notify(title, body)
+		var body string
+		switch ev.Kind {
+		case core.EventAdvance:
+			body = m.loc.Sprintf("Phase: %s", m.loc.Name(ev.State))
+			if tip := m.tips.For(ev.State); tip != "" {
+				body += "\n" + tip
+			}
+		case core.EventWarning:
+			body = notify.WarningBody(m.loc, ev)
+			if m.beepOn.Load() && !quiet.Covers(ev.At) {
+				_ = notify.Beep()
+			}
+		case core.EventOvertime:
+			body = m.loc.Sprintf("Work done, overtime running. Press [%s] to take your break.", m.keys[actAcknowledge].Help().Key)
+		case core.EventRefused:
+			body = ev.Refusal.Error()
+		case core.EventStop:
+			switch {
+			case ev.QuittingTime:
+				body = m.loc.Sprintf("Quitting time, done for today. Press [%s] to keep working.", m.keys[actOverride].Help().Key)
+			case ev.Finished:
+				body = notify.FinishedBody(m.loc, engine.Config().Cycles)
+			default:
+				return
+			}
+		default:
+			return
+		}
+		msg := notify.Message{
+			Title:   title,
+			Body:    body,
+			Event:   ev,
+			Actions: notify.PhaseActions(engine, ev),
+		}
+		// a broken template leaves the built-in text
+		done, target := m.goalProgress()
+		msg, _ = opts.Templates.Apply(msg, done, target)
+		_ = notify.Send(notifier, msg)
 	})
 	return m, nil
 }
 
 func Run(m *Model) error {
-	p := tea.NewProgram(m, tea.WithAltScreen())
+	defer m.unsubscribe()
+	defer func() { m.cancelTicks() }()
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithMouseCellMotion()}
+	if m.plain || m.mini {
+		opts = nil
+	}
+	p := tea.NewProgram(m, opts...)
 	_, err := p.Run()
 	return err
 }
 
 func (m *Model) Init() tea.Cmd {
-	return tickCmd()
+	return tea.Batch(tickCmd(), m.waitEngineTick(), waitReload(m.reloads), m.waitFinished(), m.waitPlainLine())
 }
 
+// finishedMsg says the engine finished its run of Config.Cycles.
+type finishedMsg struct{}
+
+func (m *Model) waitFinished() tea.Cmd {
+	return func() tea.Msg {
+		<-m.finished
+		return finishedMsg{}
+	}
+}
+
+// Finished reports whether the TUI quit because the engine completed
+// its Config.Cycles pomodoros, rather than at the user's request.
+func (m *Model) Finished() bool {
+	return m.done
+}
+
+// extendStep is how much the extend/shorten keys change a phase.
+const extendStep = time.Minute
+
+// tickMsg is the TUI's own tick, for housekeeping and the other tabs;
+// the shown countdown redraws on the engine's ticks.
 type tickMsg time.Time
 
+// engineTickMsg is a tick of the shown engine, redrawing the countdown
+// right as it changes.
+type engineTickMsg struct{}
+
+// followTicks subscribes to the shown engine's ticks, replacing the
+// previous engine's subscription.
+func (m *Model) followTicks() {
+	if m.cancelTicks != nil {
+		m.cancelTicks()
+	}
+	m.cancelTicks = m.engine.SubscribeTicks(func(core.Event) {
+		// the view reads the engine anyway, so ticks can coalesce
+		select {
+		case m.ticks <- struct{}{}:
+		default:
+		}
+	})
+}
+
+func (m *Model) waitEngineTick() tea.Cmd {
+	return func() tea.Msg {
+		<-m.ticks
+		return engineTickMsg{}
+	}
+}
+
 // tickCmd returns a command that sends a tickMsg after one second.
 // It uses tea.Tick (not time.Ticker), which schedules a one-time event
 // without leaving behind a running goroutine. Each tick must be
@@ -61,33 +326,304 @@ func tickCmd() tea.Cmd {
 	})
 }
 
+// applyProfile switches the engine to the named profile. While a phase
+// runs, the new timings take effect from the next phase.
+func (m *Model) applyProfile(name string) {
+	prof, err := m.cfg.Resolve(name)
+	if err != nil {
+		return
+	}
+	cfg := prof.Core()
+	cfg.Cycles = m.engine.Config().Cycles // the run's length isn't the profile's
+	m.engine.SetConfig(cfg)
+	m.profile = name
+	m.notifyOn.Store(prof.NotificationsEnabled())
+	m.beepOn.Store(prof.WarningSound)
+}
+
+// switchTimer shows the timer delta tabs away.
+func (m *Model) switchTimer(delta int) {
+	if m.timers == nil {
+		return
+	}
+	names := m.timers.Names()
+	if len(names) < 2 {
+		return
+	}
+	// -1 for a removed tab is fine: the neighbours still exist
+	i := slices.Index(names, m.timer)
+	m.showTimer(names[((i+delta)%len(names)+len(names))%len(names)])
+}
+
+// showTimer makes the named timer the current tab.
+func (m *Model) showTimer(name string) {
+	e := m.timers.Get(name)
+	if e == nil {
+		return
+	}
+	m.profiles[m.timer] = m.profile
+	m.timer, m.engine = name, e
+	m.profile = cmp.Or(m.profiles[name], m.profile)
+	m.followTicks()
+}
+
+// tabsView renders a tab per timer with its time left, or nothing for
+// a single timer.
+func (m *Model) tabsView() string {
+	if m.timers == nil {
+		return ""
+	}
+	names := m.timers.Names()
+	if len(names) < 2 {
+		return ""
+	}
+	tabs := make([]string, 0, len(names))
+	for _, name := range names {
+		e := m.timers.Get(name)
+		if e == nil {
+			continue
+		}
+		label := name
+		st := e.State()
+		style := m.theme.faint
+		if !st.Idle() {
+			left := e.Remaining()
+			if st.Open {
+				left = e.Elapsed()
+			}
+			label += " " + clockText(left)
+			style = m.theme.phase[st.Phase]
+		}
+		if name == m.timer {
+			style = style.Bold(true).Underline(true)
+		}
+		tabs = append(tabs, style.Render(label))
+	}
+	return strings.Join(tabs, m.theme.faint.Render(" │ ")) + "\n"
+}
+
+// teamView renders the team session's members, e.g. "Team: ana, bo".
+func (m *Model) teamView() string {
+	members := m.team.Members()
+	if len(members) == 0 {
+		return m.loc.Sprintf("Team: %s", m.theme.faint.Render("reconnecting…"))
+	}
+	return m.loc.Sprintf("Team: %s", strings.Join(members, ", "))
+}
+
+// applyTheme switches the TUI to the named theme.
+func (m *Model) applyTheme(name string) {
+	th, err := loadTheme(m.cfg, name)
+	if err != nil {
+		return
+	}
+	m.theme = th
+	m.progress = th.newProgress()
+}
+
+// pauseReasonNames lists core.PauseReasons in loc's words.
+func pauseReasonNames(loc *i18n.Locale) []string {
+	names := make([]string, len(core.PauseReasons))
+	for i, r := range core.PauseReasons {
+		names[i] = loc.T(r.String())
+	}
+	return names
+}
+
+// runAction does what a key or button bound to act does.
+func (m *Model) runAction(act action) (tea.Model, tea.Cmd) {
+	switch act {
+	case actQuit:
+		m.quit = true
+		return m, tea.Quit
+	case actStart:
+		// Paused -> Resume, Idle -> Start; a running phase stays as is
+		if err := m.engine.Resume(); errors.Is(err, core.ErrNotRunning) {
+			m.engine.Start()
+		}
+	case actAcknowledge:
+		// Overtime -> break
+		m.engine.Acknowledge()
+	case actOverride:
+		m.engine.OverrideQuittingTime()
+	case actPause:
+		// pause right away, then ask why without holding the timer
+		if m.engine.Pause() != nil {
+			break // idle, paused, in overtime or refused by strict mode
+		}
+		m.modal = newIndexPicker(m.loc.T("Pause reason"), pauseReasonNames(m.loc), func(i int) {
+			m.engine.SetPauseReason(core.PauseReasons[i])
+		})
+	case actSkip:
+		m.engine.Skip()
+	case actExtend:
+		m.engine.Extend(extendStep)
+	case actShorten:
+		m.engine.Extend(-extendStep)
+	case actReset:
+		m.reset()
+	case actUndo:
+		if errors.Is(m.engine.Undo(), core.ErrNothingToUndo) {
+			m.modal = &notice{text: m.loc.T("Nothing to undo.")}
+		}
+	case actTask:
+		if len(m.tasks) == 0 {
+			break
+		}
+		m.taskLoad = &notice{text: m.loc.T("Loading tasks…")}
+		m.modal = m.taskLoad
+		return m, loadTasks(m.tasks)
+	case actEstimate:
+		m.openEstimatePicker()
+	case actTags:
+		m.modal = newLinePrompt(m.loc.T("Tags"), "#client-a #coding", core.FormatTags(m.engine.State().Tags), func(s string) {
+			m.engine.SetTags(core.ParseTags(s))
+		})
+	case actTaskPanel:
+		if len(m.tasks) == 0 {
+			break
+		}
+		return m, m.toggleTaskPanel()
+	case actBoard:
+		m.toggleBoard()
+	case actProfile:
+		m.modal = newPicker(m.loc.T("Profile"), m.cfg.Names(), m.profile, func(name string) {
+			m.scheduled = false
+			m.applyProfile(name)
+		})
+	case actDashboard, actHeatmap:
+		heatmap := act == actHeatmap
+		if m.dash != nil && m.dash.heatmap == heatmap {
+			m.dash = nil
+		} else {
+			m.openDashboard(heatmap)
+		}
+	case actHistory:
+		m.dash = nil
+		m.browser = newHistoryBrowser(m.history)
+	case actClock:
+		m.bigClock = !m.bigClock
+	case actZen:
+		m.zen = !m.zen
+	case actCountUp:
+		m.countUp = !m.countUp
+	case actMini:
+		return m, m.toggleMini()
+	case actHelp:
+		m.openKeyHelp()
+	case actNextTimer:
+		m.switchTimer(1)
+	case actPrevTimer:
+		m.switchTimer(-1)
+	case actTheme:
+		m.modal = newPicker(m.loc.T("Theme"), themeNames(m.cfg), m.theme.name, m.applyTheme)
+	case actInterrupt:
+		st := m.engine.State()
+		if st.Phase != core.PhaseWork {
+			break
+		}
+		m.modal = newInterruptPrompt(func(kind core.InterruptionKind, note string) {
+			m.engine.Interrupt(kind, note)
+		})
+	}
+	return m, nil
+}
+
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 
 	case tea.KeyMsg:
-		switch msg.String() {
-		case "q", "esc", "ctrl+c":
-			m.quit = true
-			return m, tea.Quit
-		case "s":
-			st := m.engine.State()
-			if st.Paused || st.StartedAt.IsZero() {
-				if st.StartedAt.IsZero() {
-					// Idle -> Start
-					m.engine.Start()
-				} else if st.Paused {
-					// Paused -> Resume
-					m.engine.Resume()
-				}
+		if m.overlay != nil {
+			if msg.String() == quitKey {
+				m.quit = true
+				return m, tea.Quit
+			}
+			m.overlayKey(time.Now())
+			return m, nil
+		}
+		if m.modal != nil {
+			if msg.String() == "ctrl+c" {
+				m.quit = true
+				return m, tea.Quit
 			}
-		case "p":
-			m.engine.Pause()
-		case "r":
-			// Reset/Stop to idle
-			m.engine.Stop()
+			// a modal may hand over to the next, as reset's do
+			if open := m.modal; open.handleKey(msg) && m.modal == open {
+				m.modal = nil
+			}
+			return m, nil
+		}
+		if m.browser != nil {
+			if msg.String() == quitKey {
+				m.quit = true
+				return m, tea.Quit
+			}
+			if m.browser.handleKey(msg) {
+				m.browser = nil
+			}
+			return m, nil
 		}
+		if m.keyHelp != nil {
+			if msg.String() == quitKey {
+				m.quit = true
+				return m, tea.Quit
+			}
+			if m.keyHelp.handleKey(msg, m.keys[actHelp]) {
+				m.keyHelp = nil
+			}
+			return m, nil
+		}
+		act, ok := m.keys.lookup(msg)
+		if !ok {
+			m.panelKey(msg)
+			break
+		}
+		return m.runAction(act)
+
+	case tea.MouseMsg:
+		if m.overlay != nil {
+			return m, nil
+		}
+		return m.handleMouse(msg)
+
+	case tasksMsg:
+		if msg.panel {
+			m.fillTaskPanel(msg)
+		} else {
+			m.openTaskPicker(msg)
+		}
+
+	case engineTickMsg:
+		m.checkOverlay(time.Now())
+		return m, m.waitEngineTick()
+
+	case plainLineMsg:
+		return m, tea.Batch(tea.Println(string(msg)), m.waitPlainLine())
+
+	case finishedMsg:
+		m.done = true
+		return m, tea.Quit
+
+	case reloadMsg:
+		m.reloadErr = msg.Err
+		if msg.Err == nil {
+			m.reloadErr = m.reloadConfig(msg.Config)
+		}
+		return m, waitReload(m.reloads)
 
 	case tickMsg:
+		// the shown timer was removed through the API
+		if m.timers != nil && m.timers.Get(m.timer) == nil {
+			m.showTimer(core.DefaultTimer)
+		}
+		m.checkOverlay(time.Time(msg))
+		if p := m.panel; p != nil && !p.loading && p.seen != m.engine.State().PomodoroDone {
+			m.countPanelDone()
+		}
+		if m.scheduled && m.timer == core.DefaultTimer {
+			if name := m.cfg.Scheduled(time.Time(msg)); name != m.profile {
+				m.applyProfile(name)
+			}
+		}
 		// Schedule the next tick
 		return m, tickCmd()
 
@@ -97,43 +633,225 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m *Model) View() string {
-	st := m.engine.State()
-	remain := m.engine.Remaining().Truncate(time.Second)
-
-	title := lipgloss.NewStyle().Bold(true).Underline(true).Render("GoPomodoro")
+// goalProgress is today's pomodoros and the daily goal, zero without a
+// Goal.
+func (m *Model) goalProgress() (done, target int) {
+	if m.goal == nil {
+		return 0, 0
+	}
+	return m.goal.Progress()
+}
 
-	phaseLabel := st.Phase.String()
-	if st.StartedAt.IsZero() {
-		phaseLabel = "IDLE"
+// goalView renders today's progress, e.g. "Today: 3/8".
+func (m *Model) goalView() string {
+	done, target := m.goal.Progress()
+	if target <= 0 {
+		return m.loc.Sprintf("Today: %d", done)
 	}
-	phase := lipgloss.NewStyle().Bold(true).Render(phaseLabel)
+	line := m.loc.Sprintf("Today: %d/%d", done, target)
+	if done >= target {
+		return m.theme.goal.Render(line + " 🎉")
+	}
+	return line
+}
+
+// chromeRows is roughly how many rows the box needs besides the big
+// clock: border, padding, title, info lines, bar and help.
+const chromeRows = 18
 
-	info := fmt.Sprintf("Remaining: %s\nCompleted: %d\nPaused: %v\n",
-		remain, st.PomodoroDone, st.Paused)
+// shownTime is the time the clock shows: remaining, or elapsed when
+// counting up or in an open flow phase.
+func (m *Model) shownTime(st core.State) time.Duration {
+	if m.countUp || st.Open {
+		return m.engine.Elapsed()
+	}
+	return m.engine.Remaining()
+}
 
-	// progress bar based on phase duration
-	total := m.engine.PhaseDuration(st.Phase)
-	if phaseLabel == "IDLE" {
-		// The idle phase is always represented by a new State object.
-		// We can detect the idle phase by checking whether State.StartedAt is zero.
-		total = 0
+// clockView renders the remaining or elapsed (or overtime) time in large digits,
+// centered, followed by a blank line. It is empty when the terminal is
+// too small.
+func (m *Model) clockView(st core.State, width int) string {
+	text := clockText(m.shownTime(st))
+	style := m.theme.phase[st.Phase]
+	if st.Overtime {
+		text = clockText(m.engine.Overtime())
+		style = m.theme.overtime
 	}
+	big := bigClock(text, width, m.height-chromeRows)
+	if big == "" {
+		return ""
+	}
+	return "\n" + lipgloss.PlaceHorizontal(width, lipgloss.Center, style.Render(big)) + "\n\n"
+}
+
+// barRatio is how full the progress bar is in st.
+func (m *Model) barRatio(st core.State) float64 {
+	// based on phase duration; idle has none
+	total := st.Length
 	var ratio float64
 	if total > 0 {
 		done := min(max(total-m.engine.Remaining(), 0), total)
 		ratio = float64(done) / float64(total)
+		// the theme picks whether the phase fills or drains, and the
+		// bar shows the side the clock doesn't, so counting up flips it
+		if m.theme.bars[st.Phase].drain != m.countUp {
+			ratio = 1 - ratio
+		}
+	}
+
+	if st.Open {
+		// no deadline: fill toward the usual work length
+		if work := m.engine.Config().Work; work > 0 {
+			ratio = min(float64(m.engine.Elapsed())/float64(work), 1)
+		}
+	}
+	if st.Overtime {
+		ratio = 1
+	}
+	return ratio
+}
+
+// helpActions are the actions the help line offers; ? lists the rest.
+func (m *Model) helpActions() []action {
+	acts := []action{actStart, actPause, actInterrupt, actSkip, actReset}
+	if m.timers != nil && len(m.timers.Names()) > 1 {
+		acts = append(acts, actPrevTimer, actNextTimer)
+	}
+	return append(acts, actHelp, actQuit)
+}
+
+func (m *Model) View() string {
+	if m.plain {
+		return m.plainView()
+	}
+	if m.overlay != nil {
+		return m.overlayView(time.Now())
+	}
+	if m.mini && m.dash == nil && m.browser == nil && m.keyHelp == nil {
+		return m.miniView()
+	}
+	if m.zen && m.dash == nil && m.browser == nil && m.keyHelp == nil {
+		return m.zenView()
+	}
+	st := m.engine.State()
+	remain := m.shownTime(st).Truncate(time.Second)
+	remainLabel := "Remaining: %s"
+	if m.countUp || st.Open {
+		remainLabel = "Elapsed: %s"
+	}
+
+	title := lipgloss.NewStyle().Bold(true).Underline(true).Render("GoPomodoro")
+
+	phase := m.theme.phase[st.Phase].Render(m.loc.Name(st))
+	if st.Overtime {
+		over := m.engine.Overtime().Truncate(time.Second)
+		phase += " " + m.theme.overtime.Render(m.loc.Sprintf("OVERTIME +%s", over))
+	}
+	if st.Open {
+		phase += " " + m.theme.faint.Render(m.loc.T("FLOW"))
+	}
+
+	paused := m.loc.T(fmt.Sprint(st.Paused))
+	if st.Paused && st.PauseReason != core.ReasonNone {
+		paused += " (" + st.PauseReason.String() + ")"
+	}
+	completed := m.loc.Sprintf("Completed: %d", st.PomodoroDone)
+	if row := tomatoRow(m.engine.Config(), st); row != "" {
+		completed += "  " + row
+	}
+	info := strings.Join([]string{
+		m.loc.Sprintf(remainLabel, remain),
+		completed,
+		m.loc.Sprintf("Paused: %s", paused),
+		m.loc.Sprintf("Interruptions: %d", st.Interruptions),
+		m.loc.Sprintf("Profile: %s", m.profile),
+	}, "\n") + "\n"
+	if !st.Task.IsZero() {
+		info += m.loc.Sprintf("Task: %s", st.Task.Title)
+		if st.Task.Estimate > 0 {
+			info += m.loc.Sprintf(" (estimate %d 🍅)", st.Task.Estimate)
+		}
+		info += "\n"
+	}
+	if len(st.Tags) > 0 {
+		info += m.loc.Sprintf("Tags: %s", core.FormatTags(st.Tags)) + "\n"
+	}
+	if tip := m.tips.For(st); tip != "" {
+		info += m.loc.Sprintf("Break idea: %s", tip) + "\n"
+	}
+	if m.goal != nil {
+		info += m.goalView() + "\n"
+	}
+	if m.team != nil && m.timer == core.DefaultTimer {
+		info += m.teamView() + "\n"
+	}
+	if m.reloadErr != nil {
+		info += m.theme.overtime.Render(m.loc.Sprintf("Config not reloaded: %s", m.reloadErr)) + "\n"
+	}
+	afterHours := m.engine.AfterHours()
+	if afterHours {
+		info += m.theme.overtime.Render(m.loc.T("Quitting time: done for today")) + "\n"
 	}
 
-	bar := m.progress.ViewAs(ratio)
+	bar := m.progress[st.Phase].ViewAs(m.barRatio(st))
 
-	help := lipgloss.NewStyle().Faint(true).Render("[s] start/resume  [p] pause  [r] reset  [q] quit")
+	boxWidth := max(32, m.width-4)
+	if m.panel != nil || m.boardOpen {
+		boxWidth = max(32, m.width-4-panelWidth)
+	}
+	innerWidth := boxWidth - 4
+	clock := ""
+	if m.bigClock {
+		clock = m.clockView(st, innerWidth)
+	}
+
+	tabs := m.tabsView()
+	help := m.theme.faint.Render(m.keys.help(m.helpActions()...))
+	if st.Overtime || st.Open {
+		help = m.theme.overtime.Render(m.keys.help(actAcknowledge)) + "\n" + help
+	}
+	if afterHours {
+		help = m.theme.overtime.Render(m.keys.help(actOverride)) + "\n" + help
+	}
+	if m.modal != nil {
+		help = m.modal.View()
+	}
+
+	if tabs != "" {
+		title += "\n\n" + tabs
+	}
+	body := fmt.Sprintf("%s\n\n%s\n%s%s\n%s\n\n%s\n\n%s", title, m.loc.Sprintf("Phase: %s", phase), clock, info, bar, m.buttonsView(), help)
+	if m.dash != nil {
+		if m.modal == nil {
+			help = m.theme.faint.Render(m.keys.help(actDashboard, actHeatmap, actQuit))
+		}
+		body = fmt.Sprintf("%s\n\n%s  %s\n\n%s\n\n%s", title, m.loc.Sprintf("Phase: %s", phase), remain, m.dashboardView(innerWidth), help)
+	}
+
+	if m.browser != nil {
+		body = fmt.Sprintf("%s\n\n%s  %s\n\n%s", title, m.loc.Sprintf("Phase: %s", phase), remain, m.browser.View(innerWidth, m.height-16, m.theme.faint))
+	}
+	if m.keyHelp != nil {
+		body = fmt.Sprintf("%s\n\n%s", title, m.keyHelpView(innerWidth, m.height-10))
+	}
 
 	box := lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
+		Border(m.theme.border).
 		Padding(1, 2).
-		Width(max(32, m.width-4)).
-		Render(fmt.Sprintf("%s\n\nPhase: %s\n%s\n%s\n\n%s", title, phase, info, bar, help))
+		Width(boxWidth).
+		Render(body)
+	if m.panel != nil {
+		box = lipgloss.JoinHorizontal(lipgloss.Top, box, m.taskPanelView(lipgloss.Height(box)))
+	}
+	if m.boardOpen {
+		box = lipgloss.JoinHorizontal(lipgloss.Top, box, m.boardPanelView())
+	}
 
-	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+	view := lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+	m.zones = zones{}
+	if m.dash == nil && m.browser == nil && m.keyHelp == nil && m.modal == nil {
+		m.mapZones(view, bar)
+	}
+	return view
 }
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestUndo_Key(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	m, err := NewModel(eng, nil, Options{})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+
+	eng.Start()
+	m.Update(keyMsg("n"))
+	if eng.State().Phase != core.PhaseShortBreak {
+		t.Fatalf("n didn't skip: %+v", eng.State())
+	}
+	m.Update(keyMsg("u"))
+	if st := eng.State(); st.Phase != core.PhaseWork || m.modal != nil {
+		t.Fatalf("u didn't undo the skip: %+v", st)
+	}
+	m.Update(keyMsg("u"))
+	if _, ok := m.modal.(*notice); !ok {
+		t.Fatalf("undo with nothing to undo shows %T, want a notice", m.modal)
+	}
+}
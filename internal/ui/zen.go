@@ -0,0 +1,31 @@
+package ui
+
+import (
+	"github.com/charmbracelet/lipgloss"
+)
+
+// zenView is zen mode: the countdown in large digits of the phase's
+// color, alone in the middle of the terminal. Keys work as usual, and a
+// picker opens under the clock.
+func (m *Model) zenView() string {
+	m.zones = zones{}
+	st := m.engine.State()
+	text := clockText(m.shownTime(st))
+	style := m.theme.phase[st.Phase]
+	if st.Overtime {
+		text = clockText(m.engine.Overtime())
+		style = m.theme.overtime
+	}
+	rows := m.height
+	if m.modal != nil {
+		rows -= lipgloss.Height(m.modal.View()) + 1
+	}
+	clock := style.Render(text)
+	if big := bigClock(text, m.width, rows); big != "" {
+		clock = style.Render(big)
+	}
+	if m.modal != nil {
+		clock = lipgloss.JoinVertical(lipgloss.Center, clock, "", m.modal.View())
+	}
+	return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, clock)
+}
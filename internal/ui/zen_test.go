@@ -0,0 +1,46 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ezchuang/GoPomodoro/internal/core"
+)
+
+func TestZen_OnlyTheClock(t *testing.T) {
+	eng := core.New(core.Config{Work: 25 * time.Minute, ShortBrk: 5 * time.Minute, LongBrk: 15 * time.Minute, LongEvery: 4})
+	defer eng.Stop()
+	m, err := NewModel(eng, nil, Options{})
+	if err != nil {
+		t.Fatalf("NewModel: %v", err)
+	}
+	defer m.unsubscribe()
+	defer m.cancelTicks()
+	m.width, m.height = 100, 30
+	eng.Start()
+
+	m.Update(keyMsg("f"))
+	if !m.zen {
+		t.Fatal("f didn't enter zen mode")
+	}
+	v := m.View()
+	for _, s := range []string{"Completed", "[s]", "WORK", "╭"} {
+		if strings.Contains(v, s) {
+			t.Errorf("zen view shows %q:\n%s", s, v)
+		}
+	}
+	if !strings.Contains(v, "█") {
+		t.Errorf("no big clock:\n%s", v)
+	}
+	// too small for large digits
+	m.width, m.height = 20, 3
+	if v := m.View(); !strings.Contains(v, "24:5") && !strings.Contains(v, "25:00") {
+		t.Errorf("small zen view:\n%s", v)
+	}
+
+	m.Update(keyMsg("f"))
+	if m.zen || !strings.Contains(m.View(), "Completed") {
+		t.Fatal("f didn't leave zen mode")
+	}
+}